@@ -59,6 +59,7 @@ type Options struct {
 	Client                       Client
 	ReconnectingPTYTimeout       time.Duration
 	EnvironmentVariables         map[string]string
+	GitCredentialHelper          string
 	Logger                       slog.Logger
 	IgnorePorts                  map[int]string
 	SSHMaxTimeout                time.Duration
@@ -128,6 +129,7 @@ func New(options Options) Agent {
 		closeCancel:                  cancelFunc,
 		closed:                       make(chan struct{}),
 		envVars:                      options.EnvironmentVariables,
+		gitCredentialHelper:          options.GitCredentialHelper,
 		client:                       options.Client,
 		exchangeToken:                options.ExchangeToken,
 		filesystem:                   options.Filesystem,
@@ -138,6 +140,7 @@ func New(options Options) Agent {
 		lifecycleStates:              []agentsdk.PostLifecycleRequest{{State: codersdk.WorkspaceAgentLifecycleCreated}},
 		ignorePorts:                  options.IgnorePorts,
 		connStatsChan:                make(chan *agentsdk.Stats, 1),
+		egressDenials:                make(chan string, 64),
 		reportMetadataInterval:       options.ReportMetadataInterval,
 		serviceBannerRefreshInterval: options.ServiceBannerRefreshInterval,
 		sshMaxTimeout:                options.SSHMaxTimeout,
@@ -165,7 +168,10 @@ type agent struct {
 	ignorePorts map[int]string
 	subsystems  []codersdk.AgentSubsystem
 
-	reconnectingPTYs       sync.Map
+	reconnectingPTYs sync.Map
+	// reconnectingPTYExits holds a *reconnectingPTYExit for each
+	// reconnecting PTY, until the timeout after it exits.
+	reconnectingPTYExits   sync.Map
 	reconnectingPTYTimeout time.Duration
 
 	connCloseWait sync.WaitGroup
@@ -174,6 +180,8 @@ type agent struct {
 	closed        chan struct{}
 
 	envVars map[string]string
+	// gitCredentialHelper is installed into the git config of the user.
+	gitCredentialHelper string
 
 	manifest                     atomic.Pointer[agentsdk.Manifest] // manifest is atomic because values can change after reconnection.
 	reportMetadataInterval       time.Duration
@@ -182,6 +190,9 @@ type agent struct {
 	sessionToken                 atomic.Pointer[string]
 	sshServer                    *agentssh.Server
 	sshMaxTimeout                time.Duration
+	// egressDenials are the destinations of forwarded connections that the
+	// egress policy denied, which are reported to coderd.
+	egressDenials chan string
 
 	lifecycleUpdate   chan struct{}
 	lifecycleReported chan codersdk.WorkspaceAgentLifecycle
@@ -212,6 +223,13 @@ func (a *agent) init(ctx context.Context) {
 	sshSrv.AgentToken = func() string { return *a.sessionToken.Load() }
 	sshSrv.Manifest = &a.manifest
 	sshSrv.ServiceBanner = &a.serviceBanner
+	sshSrv.EgressDenied = func(dst string) {
+		select {
+		case a.egressDenials <- dst:
+		default:
+			// Denials are dropped while reporting falls behind.
+		}
+	}
 	a.sshServer = sshSrv
 
 	go a.runLoop(ctx)
@@ -225,6 +243,7 @@ func (a *agent) runLoop(ctx context.Context) {
 	go a.reportLifecycleLoop(ctx)
 	go a.reportMetadataLoop(ctx)
 	go a.fetchServiceBannerLoop(ctx)
+	go a.reportEgressDenialsLoop(ctx)
 
 	for retrier := retry.New(100*time.Millisecond, 10*time.Second); retrier.Wait(ctx); {
 		a.logger.Info(ctx, "connecting to coderd")
@@ -567,6 +586,74 @@ func (a *agent) fetchServiceBannerLoop(ctx context.Context) {
 	}
 }
 
+const (
+	// egressDenialReportInterval is how often denials of the same destination
+	// are reported to coderd at most, so that retries don't fill the agent
+	// logs.
+	egressDenialReportInterval = time.Minute
+	// egressDenialReportLimit is how many destinations are reported to coderd
+	// per interval at most. Further denials are summarized at the end of the
+	// interval, since they're logged by the agent itself anyway.
+	egressDenialReportLimit = 10
+)
+
+// reportEgressDenialsLoop reports the connections that the egress policy
+// denied to coderd as agent logs.
+func (a *agent) reportEgressDenialsLoop(ctx context.Context) {
+	ticker := time.NewTicker(egressDenialReportInterval)
+	defer ticker.Stop()
+	reported := make(map[string]time.Time)
+	// reports counts the destinations that were reported in the current
+	// interval, and suppressed holds those that weren't.
+	reports := 0
+	suppressed := make(map[string]struct{})
+	for {
+		var dst string
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			for reportedDst, reportedAt := range reported {
+				if now.Sub(reportedAt) >= egressDenialReportInterval {
+					delete(reported, reportedDst)
+				}
+			}
+			if len(suppressed) > 0 {
+				a.reportEgressDenial(ctx, fmt.Sprintf("The egress policy of the template denied forwarding connections to %d more destinations, see the agent logs for details", len(suppressed)))
+			}
+			reports = 0
+			suppressed = make(map[string]struct{})
+			continue
+		case dst = <-a.egressDenials:
+		}
+		if _, ok := reported[dst]; ok {
+			continue
+		}
+		if reports >= egressDenialReportLimit {
+			suppressed[dst] = struct{}{}
+			continue
+		}
+		reported[dst] = time.Now()
+		reports++
+		a.reportEgressDenial(ctx, fmt.Sprintf("The egress policy of the template denied forwarding a connection to %s", dst))
+	}
+}
+
+// reportEgressDenial sends a log about denied connections to coderd.
+func (a *agent) reportEgressDenial(ctx context.Context, output string) {
+	err := a.client.PatchLogs(ctx, agentsdk.PatchLogs{
+		Logs: []agentsdk.Log{{
+			CreatedAt: time.Now(),
+			Output:    output,
+			Level:     codersdk.LogLevelWarn,
+			Source:    codersdk.WorkspaceAgentLogSourceEgressPolicy,
+		}},
+	})
+	if err != nil && ctx.Err() == nil {
+		a.logger.Warn(ctx, "failed to report egress denial", slog.F("output", output), slog.Error(err))
+	}
+}
+
 func (a *agent) run(ctx context.Context) error {
 	// This allows the agent to refresh it's token if necessary.
 	// For instance identity this is required, since the instance
@@ -611,6 +698,19 @@ func (a *agent) run(ctx context.Context) error {
 		return xerrors.Errorf("update workspace agent version: %w", err)
 	}
 
+	egressPolicy, err := agentssh.NewEgressPolicy(manifest.EgressPolicy)
+	if err != nil {
+		// coderd validates the policy, so this only happens if the agent
+		// doesn't understand it. Deny all connections rather than allowing
+		// what the policy may deny.
+		a.logger.Error(ctx, "invalid egress policy, denying all forwarded connections", slog.Error(err))
+		egressPolicy, _ = agentssh.NewEgressPolicy(codersdk.EgressPolicy{DefaultAction: codersdk.EgressActionDeny})
+	}
+	// The policy must be set before the tailnet is created below, since
+	// connections are allowed until it is. Policy changes of the template
+	// only apply when the manifest is fetched again on reconnecting.
+	a.sshServer.SetEgressPolicy(egressPolicy)
+
 	oldManifest := a.manifest.Swap(&manifest)
 
 	// The startup script should only execute on the first run!
@@ -629,6 +729,12 @@ func (a *agent) run(ctx context.Context) error {
 			if err != nil {
 				a.logger.Warn(ctx, "failed to override vscode git auth configs", slog.Error(err))
 			}
+			if a.gitCredentialHelper != "" {
+				err = gitauth.InstallCredentialHelper(a.filesystem, a.gitCredentialHelper)
+				if err != nil {
+					a.logger.Warn(ctx, "failed to install git credential helper", slog.Error(err))
+				}
+			}
 		}
 
 		lifecycleState := codersdk.WorkspaceAgentLifecycleReady
@@ -776,6 +882,11 @@ func (a *agent) createTailnet(ctx context.Context, agentID uuid.UUID, derpMap *t
 			network.Close()
 		}
 	}()
+	// Ports that the agent doesn't listen on in the tailnet are forwarded to
+	// localhost.
+	network.SetForwardTCPFilter(func(port uint16) bool {
+		return a.sshServer.EgressAllowed(netip.AddrPortFrom(netip.AddrFrom4([4]byte{127, 0, 0, 1}), port))
+	})
 
 	sshListener, err := network.Listen("tcp", ":"+strconv.Itoa(codersdk.WorkspaceAgentSSHPort))
 	if err != nil {
@@ -1115,9 +1226,12 @@ func (a *agent) handleReconnectingPTY(ctx context.Context, logger slog.Logger, m
 		connLogger.Debug(ctx, "creating new reconnecting pty")
 
 		connected := false
+		exit := &reconnectingPTYExit{done: make(chan struct{})}
+		a.reconnectingPTYExits.Store(msg.ID, exit)
 		defer func() {
 			if !connected && retErr != nil {
 				a.reconnectingPTYs.Delete(msg.ID)
+				a.reconnectingPTYExits.CompareAndDelete(msg.ID, exit)
 				close(sendConnected)
 			}
 		}()
@@ -1137,6 +1251,13 @@ func (a *agent) handleReconnectingPTY(ctx context.Context, logger slog.Logger, m
 		if err = a.trackConnGoroutine(func() {
 			rpty.Wait()
 			a.reconnectingPTYs.Delete(msg.ID)
+			exit.code, exit.ok = rpty.ExitCode()
+			close(exit.done)
+			// Clients that were disconnected when the command exited have
+			// until the timeout to find out how it exited.
+			time.AfterFunc(a.reconnectingPTYTimeout, func() {
+				a.reconnectingPTYExits.CompareAndDelete(msg.ID, exit)
+			})
 		}); err != nil {
 			rpty.Close(err)
 			return xerrors.Errorf("start routine: %w", err)
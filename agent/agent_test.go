@@ -45,6 +45,7 @@ import (
 	"github.com/coder/coder/v2/agent"
 	"github.com/coder/coder/v2/agent/agentssh"
 	"github.com/coder/coder/v2/agent/agenttest"
+	"github.com/coder/coder/v2/cli/gitauth"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/codersdk/agentsdk"
@@ -1704,6 +1705,20 @@ func TestAgent_ReconnectingPTY(t *testing.T) {
 
 			require.NoError(t, testutil.ReadUntil(ctx, t, netConn4, matchEchoOutput), "find echo output")
 			require.ErrorIs(t, testutil.ReadUntil(ctx, t, netConn3, nil), io.EOF)
+
+			// The exit code of the command is kept for clients that weren't
+			// attached when it exited.
+			exitID := uuid.New()
+			netConn5, err := conn.ReconnectingPTY(ctx, exitID, 80, 80, "exit 3")
+			require.NoError(t, err)
+			defer netConn5.Close()
+
+			require.ErrorIs(t, testutil.ReadUntil(ctx, t, netConn5, nil), io.EOF)
+			exit, err := conn.ReconnectingPTYExit(ctx, exitID)
+			require.NoError(t, err)
+			require.Equal(t, 3, exit.ExitCode)
+			_, err = conn.ReconnectingPTYExit(ctx, uuid.New())
+			require.Error(t, err)
 		})
 	}
 }
@@ -1772,6 +1787,67 @@ func TestAgent_Dial(t *testing.T) {
 	}
 }
 
+func TestAgent_EgressPolicy(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	allowed, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer allowed.Close()
+	go func() {
+		for {
+			c, err := allowed.Accept()
+			if err != nil {
+				return
+			}
+			go testAccept(t, c)
+		}
+	}()
+	denied, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer denied.Close()
+	allowedPort := allowed.Addr().(*net.TCPAddr).Port
+	deniedPort := denied.Addr().(*net.TCPAddr).Port
+
+	//nolint:dogsled
+	conn, client, _, _, _ := setupAgent(t, agentsdk.Manifest{
+		EgressPolicy: codersdk.EgressPolicy{
+			DefaultAction: codersdk.EgressActionDeny,
+			Rules: []codersdk.EgressRule{{
+				Action: codersdk.EgressActionAllow,
+				CIDR:   "127.0.0.1",
+				Ports:  []string{strconv.Itoa(allowedPort)},
+			}},
+		},
+	}, 0)
+	sshClient, err := conn.SSHClient(ctx)
+	require.NoError(t, err)
+	defer sshClient.Close()
+
+	// The policy is applied once the agent has fetched its manifest.
+	require.Eventually(t, func() bool {
+		_, err := sshClient.Dial("tcp", denied.Addr().String())
+		return err != nil
+	}, testutil.WaitLong, testutil.IntervalFast)
+
+	allowedConn, err := sshClient.Dial("tcp", allowed.Addr().String())
+	require.NoError(t, err)
+	defer allowedConn.Close()
+	testDial(t, allowedConn)
+
+	require.Eventually(t, func() bool {
+		for _, log := range client.GetStartupLogs() {
+			if log.Source == codersdk.WorkspaceAgentLogSourceEgressPolicy &&
+				strings.Contains(log.Output, strconv.Itoa(deniedPort)) {
+				return true
+			}
+		}
+		return false
+	}, testutil.WaitLong, testutil.IntervalFast)
+}
+
 // TestAgent_UpdatedDERP checks that agents can handle their DERP map being
 // updated, and that clients can also handle it.
 func TestAgent_UpdatedDERP(t *testing.T) {
@@ -1987,6 +2063,42 @@ func TestAgent_WriteVSCodeConfigs(t *testing.T) {
 	}, testutil.WaitShort, testutil.IntervalFast)
 }
 
+func TestAgent_InstallGitCredentialHelper(t *testing.T) {
+	t.Parallel()
+	logger := slogtest.Make(t, nil).Leveled(slog.LevelDebug)
+	coordinator := tailnet.NewCoordinator(logger)
+	defer coordinator.Close()
+
+	client := agenttest.NewClient(t,
+		logger,
+		uuid.New(),
+		agentsdk.Manifest{
+			GitAuthConfigs: 1,
+			DERPMap:        &tailcfg.DERPMap{},
+		},
+		make(chan *agentsdk.Stats, 50),
+		coordinator,
+	)
+	filesystem := afero.NewMemMapFs()
+	closer := agent.New(agent.Options{
+		ExchangeToken: func(ctx context.Context) (string, error) {
+			return "", nil
+		},
+		Client:              client,
+		Logger:              logger.Named("agent"),
+		Filesystem:          filesystem,
+		GitCredentialHelper: "!'/tmp/coder' git-credential",
+	})
+	defer closer.Close()
+
+	name, err := gitauth.CredentialHelperConfigPath()
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		data, err := afero.ReadFile(filesystem, name)
+		return err == nil && strings.Contains(string(data), "/tmp/coder")
+	}, testutil.WaitShort, testutil.IntervalFast)
+}
+
 func TestAgent_DebugServer(t *testing.T) {
 	t.Parallel()
 
@@ -68,6 +68,14 @@ type Server struct {
 	AgentToken    func() string
 	Manifest      *atomic.Pointer[agentsdk.Manifest]
 	ServiceBanner *atomic.Pointer[codersdk.ServiceBannerConfig]
+	// EgressDenied is called with the destination of connections that the
+	// egress policy denies forwarding.
+	EgressDenied func(dst string)
+
+	// egressPolicy is nil until the agent fetched its manifest, which allows
+	// all connections. The agent sets it before it creates the tailnet, so no
+	// connections are forwarded before then.
+	egressPolicy atomic.Pointer[EgressPolicy]
 
 	connCountVSCode     atomic.Int64
 	connCountJetBrains  atomic.Int64
@@ -109,7 +117,7 @@ func NewServer(ctx context.Context, logger slog.Logger, prometheusRegistry *prom
 
 	srv := &ssh.Server{
 		ChannelHandlers: map[string]ssh.ChannelHandler{
-			"direct-tcpip":                   ssh.DirectTCPIPHandler,
+			"direct-tcpip":                   s.directTCPIPHandler,
 			"direct-streamlocal@openssh.com": directStreamLocalHandler,
 			"session":                        ssh.DefaultSessionHandler,
 		},
@@ -0,0 +1,148 @@
+package agentssh
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"strconv"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/codersdk"
+)
+
+var errEgressDenied = xerrors.New("denied by the egress policy")
+
+// EgressPolicy decides which destinations the agent forwards connections to.
+// A nil policy allows all destinations.
+type EgressPolicy struct {
+	defaultAllow bool
+	rules        []egressRule
+}
+
+type egressRule struct {
+	allow bool
+	// prefix is invalid if the rule matches all addresses.
+	prefix netip.Prefix
+	// ports is empty if the rule matches all ports.
+	ports []egressPorts
+}
+
+type egressPorts struct {
+	start, end uint16
+}
+
+// NewEgressPolicy compiles the egress policy of a template.
+func NewEgressPolicy(policy codersdk.EgressPolicy) (*EgressPolicy, error) {
+	err := policy.Validate()
+	if err != nil {
+		return nil, err
+	}
+	compiled := &EgressPolicy{
+		defaultAllow: policy.DefaultAction != codersdk.EgressActionDeny,
+	}
+	for _, rule := range policy.Rules {
+		compiledRule := egressRule{
+			allow: rule.Action == codersdk.EgressActionAllow,
+		}
+		if rule.CIDR != "" {
+			compiledRule.prefix, err = codersdk.ParseEgressCIDR(rule.CIDR)
+			if err != nil {
+				return nil, xerrors.Errorf("parse cidr: %w", err)
+			}
+		}
+		for _, rawPorts := range rule.Ports {
+			start, end, err := codersdk.ParseEgressPorts(rawPorts)
+			if err != nil {
+				return nil, xerrors.Errorf("parse ports: %w", err)
+			}
+			compiledRule.ports = append(compiledRule.ports, egressPorts{start: start, end: end})
+		}
+		compiled.rules = append(compiled.rules, compiledRule)
+	}
+	return compiled, nil
+}
+
+// Allowed reports whether connections to dst may be forwarded. The first rule
+// that matches dst decides.
+func (p *EgressPolicy) Allowed(dst netip.AddrPort) bool {
+	if p == nil {
+		return true
+	}
+	addr := dst.Addr().Unmap()
+	for _, rule := range p.rules {
+		if rule.prefix.IsValid() && !rule.prefix.Contains(addr) {
+			continue
+		}
+		if len(rule.ports) > 0 && !rule.matchesPort(dst.Port()) {
+			continue
+		}
+		return rule.allow
+	}
+	return p.defaultAllow
+}
+
+func (r egressRule) matchesPort(port uint16) bool {
+	for _, ports := range r.ports {
+		if port >= ports.start && port <= ports.end {
+			return true
+		}
+	}
+	return false
+}
+
+// SetEgressPolicy replaces the policy that forwarded connections are checked
+// against.
+func (s *Server) SetEgressPolicy(policy *EgressPolicy) {
+	s.egressPolicy.Store(policy)
+}
+
+// EgressAllowed reports whether the egress policy allows forwarding connections
+// to dst, and reports the connection to EgressDenied if it doesn't.
+func (s *Server) EgressAllowed(dst netip.AddrPort) bool {
+	if s.egressPolicy.Load().Allowed(dst) {
+		return true
+	}
+	s.reportEgressDenied(context.Background(), dst.String())
+	return false
+}
+
+// dialEgress connects to the first address of host that the egress policy
+// allows. The addresses are resolved before they're checked, so that host
+// can't resolve to a different address when it's dialed.
+func (s *Server) dialEgress(ctx context.Context, host string, port uint16) (net.Conn, error) {
+	var dialer net.Dialer
+	policy := s.egressPolicy.Load()
+	if policy == nil {
+		return dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, strconv.Itoa(int(port))))
+	}
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return nil, xerrors.Errorf("resolve %q: %w", host, err)
+	}
+	dialErr := errEgressDenied
+	for _, addr := range addrs {
+		dst := netip.AddrPortFrom(addr.Unmap(), port)
+		if !policy.Allowed(dst) {
+			continue
+		}
+		conn, err := dialer.DialContext(ctx, "tcp", dst.String())
+		if err == nil {
+			return conn, nil
+		}
+		dialErr = err
+	}
+	if xerrors.Is(dialErr, errEgressDenied) {
+		s.reportEgressDenied(ctx, net.JoinHostPort(host, strconv.Itoa(int(port))))
+	}
+	return nil, dialErr
+}
+
+func (s *Server) reportEgressDenied(ctx context.Context, dst string) {
+	s.logger.Info(ctx, "egress policy denied forwarding a connection", slog.F("destination", dst))
+	if s.EgressDenied != nil {
+		s.EgressDenied(dst)
+	}
+}
@@ -0,0 +1,78 @@
+package agentssh_test
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/agent/agentssh"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+func TestEgressPolicy(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Nil", func(t *testing.T) {
+		t.Parallel()
+		var policy *agentssh.EgressPolicy
+		require.True(t, policy.Allowed(netip.MustParseAddrPort("10.0.0.1:22")))
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		t.Parallel()
+		policy, err := agentssh.NewEgressPolicy(codersdk.EgressPolicy{})
+		require.NoError(t, err)
+		require.True(t, policy.Allowed(netip.MustParseAddrPort("10.0.0.1:22")))
+	})
+
+	t.Run("Rules", func(t *testing.T) {
+		t.Parallel()
+		policy, err := agentssh.NewEgressPolicy(codersdk.EgressPolicy{
+			DefaultAction: codersdk.EgressActionDeny,
+			Rules: []codersdk.EgressRule{{
+				Action: codersdk.EgressActionDeny,
+				CIDR:   "10.0.0.1",
+			}, {
+				Action: codersdk.EgressActionAllow,
+				CIDR:   "10.0.0.0/8",
+				Ports:  []string{"22", "8000-8999"},
+			}, {
+				Action: codersdk.EgressActionAllow,
+				Ports:  []string{"443"},
+			}},
+		})
+		require.NoError(t, err)
+
+		for dst, allowed := range map[string]bool{
+			// The first rule that matches decides.
+			"10.0.0.1:22":   false,
+			"10.0.0.2:22":   true,
+			"10.0.0.2:8080": true,
+			"10.0.0.2:9000": false,
+			"1.1.1.1:443":   true,
+			"1.1.1.1:80":    false,
+			// IPv4-mapped addresses match IPv4 prefixes.
+			"[::ffff:10.0.0.2]:22": true,
+			"[fd7a::1]:443":        true,
+			"[fd7a::1]:22":         false,
+		} {
+			assert.Equal(t, allowed, policy.Allowed(netip.MustParseAddrPort(dst)), dst)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+		for _, policy := range []codersdk.EgressPolicy{
+			{DefaultAction: "block"},
+			{Rules: []codersdk.EgressRule{{Action: "block"}}},
+			{Rules: []codersdk.EgressRule{{Action: codersdk.EgressActionDeny, CIDR: "10.0.0.0/33"}}},
+			{Rules: []codersdk.EgressRule{{Action: codersdk.EgressActionDeny, Ports: []string{"0"}}}},
+			{Rules: []codersdk.EgressRule{{Action: codersdk.EgressActionDeny, Ports: []string{"9000-8000"}}}},
+		} {
+			_, err := agentssh.NewEgressPolicy(policy)
+			require.Error(t, err)
+		}
+	})
+}
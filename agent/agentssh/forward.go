@@ -3,6 +3,7 @@ package agentssh
 import (
 	"context"
 	"fmt"
+	"math"
 	"net"
 	"os"
 	"path/filepath"
@@ -201,3 +202,50 @@ func directStreamLocalHandler(_ *ssh.Server, _ *gossh.ServerConn, newChan gossh.
 
 	Bicopy(ctx, ch, dconn)
 }
+
+// directTCPIPPayload describes the extra data sent in a direct-tcpip channel
+// request containing the destination to connect to.
+type directTCPIPPayload struct {
+	DestAddr   string
+	DestPort   uint32
+	OriginAddr string
+	OriginPort uint32
+}
+
+// directTCPIPHandler is a clone of ssh.DirectTCPIPHandler that only connects
+// to destinations that the egress policy allows.
+func (s *Server) directTCPIPHandler(srv *ssh.Server, _ *gossh.ServerConn, newChan gossh.NewChannel, ctx ssh.Context) {
+	var reqPayload directTCPIPPayload
+	err := gossh.Unmarshal(newChan.ExtraData(), &reqPayload)
+	if err != nil {
+		_ = newChan.Reject(gossh.ConnectionFailed, "could not parse direct-tcpip channel payload")
+		return
+	}
+	if reqPayload.DestPort > math.MaxUint16 {
+		_ = newChan.Reject(gossh.ConnectionFailed, fmt.Sprintf("invalid port %d", reqPayload.DestPort))
+		return
+	}
+	if srv.LocalPortForwardingCallback == nil || !srv.LocalPortForwardingCallback(ctx, reqPayload.DestAddr, reqPayload.DestPort) {
+		_ = newChan.Reject(gossh.Prohibited, "port forwarding is disabled")
+		return
+	}
+
+	dconn, err := s.dialEgress(ctx, reqPayload.DestAddr, uint16(reqPayload.DestPort))
+	if err != nil {
+		reason := gossh.ConnectionFailed
+		if xerrors.Is(err, errEgressDenied) {
+			reason = gossh.Prohibited
+		}
+		_ = newChan.Reject(reason, fmt.Sprintf("dial %s:%d: %s", reqPayload.DestAddr, reqPayload.DestPort, err.Error()))
+		return
+	}
+
+	ch, reqs, err := newChan.Accept()
+	if err != nil {
+		_ = dconn.Close()
+		return
+	}
+	go gossh.DiscardRequests(reqs)
+
+	Bicopy(ctx, ch, dconn)
+}
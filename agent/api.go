@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
 
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/codersdk"
@@ -28,10 +29,55 @@ func (a *agent) apiHandler() http.Handler {
 
 	lp := &listeningPortsHandler{ignorePorts: cpy}
 	r.Get("/api/v0/listening-ports", lp.handler)
+	r.Get("/api/v0/reconnecting-pty/{id}/exit", a.handleReconnectingPTYExit)
 
 	return r
 }
 
+// reconnectingPTYExit is how the command of a reconnecting PTY exited. code
+// and ok are set before done is closed.
+type reconnectingPTYExit struct {
+	done chan struct{}
+	code int
+	ok   bool
+}
+
+// handleReconnectingPTYExit waits for the command of a reconnecting PTY to exit
+// and returns its exit code, so that clients can exit with it.
+func (a *agent) handleReconnectingPTYExit(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid reconnecting PTY ID.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	value, ok := a.reconnectingPTYExits.Load(id)
+	if !ok {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: "Reconnecting PTY not found.",
+		})
+		return
+	}
+	exit, _ := value.(*reconnectingPTYExit)
+	select {
+	case <-ctx.Done():
+		return
+	case <-exit.done:
+	}
+	if !exit.ok {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: "The command of the reconnecting PTY didn't exit on its own.",
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.WorkspaceAgentReconnectingPTYExit{
+		ExitCode: exit.code,
+	})
+}
+
 type listeningPortsHandler struct {
 	mut         sync.Mutex
 	ports       []codersdk.WorkspaceAgentListeningPort
@@ -27,6 +27,9 @@ type bufferedReconnectingPTY struct {
 
 	ptty    pty.PTYCmd
 	process pty.Process
+	// exitCode and exited are set before the state moves to done.
+	exitCode int
+	exited   bool
 
 	metrics *prometheus.CounterVec
 
@@ -89,9 +92,7 @@ func newBuffered(ctx context.Context, cmd *pty.Cmd, options *Options, logger slo
 					rpty.metrics.WithLabelValues("output_reader").Add(1)
 				}
 				// Could have been killed externally or failed to start at all (command
-				// not found for example).
-				// TODO: Should we check the process's exit code in case the command was
-				//       invalid?
+				// not found for example).  The exit code is available from ExitCode().
 				rpty.Close(nil)
 				break
 			}
@@ -166,6 +167,7 @@ func (rpty *bufferedReconnectingPTY) lifecycle(ctx context.Context, logger slog.
 	if err != nil {
 		logger.Debug(ctx, "killed process with error", slog.Error(err))
 	}
+	rpty.exitCode, rpty.exited = exitCode(rpty.process.Wait())
 
 	logger.Info(ctx, "closed reconnecting pty")
 	rpty.state.setState(StateDone, reasonErr)
@@ -239,3 +241,8 @@ func (rpty *bufferedReconnectingPTY) Close(error error) {
 	// The closing state change will be handled by the lifecycle.
 	rpty.state.setState(StateClosing, error)
 }
+
+func (rpty *bufferedReconnectingPTY) ExitCode() (int, bool) {
+	_, _ = rpty.state.waitForState(StateDone)
+	return rpty.exitCode, rpty.exited
+}
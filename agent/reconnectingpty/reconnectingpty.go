@@ -3,6 +3,7 @@ package reconnectingpty
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"net"
 	"os/exec"
@@ -49,13 +50,16 @@ type ReconnectingPTY interface {
 	Wait()
 	// Close kills the reconnecting pty process.
 	Close(err error)
+	// ExitCode waits for the process to exit after the reconnecting pty closes
+	// and returns its exit code.  ok is false if the process didn't exit on its
+	// own, for example when it was killed after the timeout.
+	ExitCode() (code int, ok bool)
 }
 
 // New sets up a new reconnecting pty that wraps the provided command.  Any
 // errors with starting are returned on Attach().  The reconnecting pty will
 // close itself (and all connections to it) if nothing is attached for the
-// duration of the timeout, if the context ends, or the process exits (buffered
-// backend only).
+// duration of the timeout, if the context ends, or the process exits.
 func New(ctx context.Context, cmd *pty.Cmd, options *Options, logger slog.Logger) ReconnectingPTY {
 	if options.Timeout == 0 {
 		options.Timeout = 5 * time.Minute
@@ -82,6 +86,19 @@ func New(ctx context.Context, cmd *pty.Cmd, options *Options, logger slog.Logger
 	}
 }
 
+// exitCode returns the exit code of a process from the error of waiting for it.
+// ok is false if the process didn't exit on its own.
+func exitCode(err error) (code int, ok bool) {
+	if err == nil {
+		return 0, true
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() >= 0 {
+		return exitErr.ExitCode(), true
+	}
+	return 0, false
+}
+
 // heartbeat resets timer before timeout elapses and blocks until ctx ends.
 func heartbeat(ctx context.Context, timer *time.Timer, timeout time.Duration) {
 	// Reset now in case it is near the end.
@@ -11,6 +11,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -43,6 +44,12 @@ type screenReconnectingPTY struct {
 	mutex sync.Mutex
 
 	configFile string
+	// exitFile is where the exit code of the command is written when it exits,
+	// since screen doesn't report it.
+	exitFile string
+	// exitCode and exited are set before the state moves to done.
+	exitCode int
+	exited   bool
 
 	metrics *prometheus.CounterVec
 
@@ -116,6 +123,7 @@ func newScreen(ctx context.Context, cmd *pty.Cmd, options *Options, logger slog.
 		rpty.state.setState(StateDone, xerrors.Errorf("create config file: %w", err))
 		return rpty
 	}
+	rpty.exitFile = filepath.Join(filepath.Dir(rpty.configFile), rpty.id+".exit")
 
 	return rpty
 }
@@ -144,6 +152,13 @@ func (rpty *screenReconnectingPTY) lifecycle(ctx context.Context, logger slog.Lo
 		logger.Error(ctx, "close screen session", slog.Error(err))
 	}
 
+	data, err := os.ReadFile(rpty.exitFile)
+	if err == nil {
+		rpty.exitCode, err = strconv.Atoi(strings.TrimSpace(string(data)))
+		rpty.exited = err == nil
+		_ = os.Remove(rpty.exitFile)
+	}
+
 	logger.Info(ctx, "closed reconnecting pty")
 	rpty.state.setState(StateDone, reasonErr)
 }
@@ -202,7 +217,9 @@ func (rpty *screenReconnectingPTY) doAttach(ctx context.Context, conn net.Conn,
 
 	logger.Debug(ctx, "spawning screen client", slog.F("screen_id", rpty.id))
 
-	// Wrap the command with screen and tie it to the connection's context.
+	// Wrap the command with screen and tie it to the connection's context.  The
+	// command itself is wrapped with sh to write its exit code to the exit file,
+	// which is passed as $0.
 	cmd := pty.CommandContext(ctx, "screen", append([]string{
 		// -S is for setting the session's name.
 		"-S", rpty.id,
@@ -212,6 +229,7 @@ func (rpty *screenReconnectingPTY) doAttach(ctx context.Context, conn net.Conn,
 		//    when creating a new session with -RR.
 		// -c is the flag for the config file.
 		"-xRRqc", rpty.configFile,
+		"sh", "-c", `"$@"; echo $? > "$0"`, rpty.exitFile,
 		rpty.command.Path,
 		// pty.Cmd duplicates Path as the first argument so remove it.
 	}, rpty.command.Args[1:]...)...)
@@ -265,11 +283,15 @@ func (rpty *screenReconnectingPTY) doAttach(ctx context.Context, conn net.Conn,
 				}
 				// The process might have died because the session itself died or it
 				// might have been separately killed and the session is still up (for
-				// example `exit` or we killed it when the connection closed).  If the
-				// session is still up we might leave the reconnecting pty in memory
-				// around longer than it needs to be but it will eventually clean up
+				// example `exit` or we killed it when the connection closed).  The
+				// command writes the exit file when it exits, which ends the session.
+				// Otherwise the session might still be up and will eventually clean up
 				// with the timer or context, or the next attach will respawn the screen
 				// daemon which is fine too.
+				if _, err := os.Stat(rpty.exitFile); err == nil {
+					logger.Debug(ctx, "command exited")
+					rpty.Close(nil)
+				}
 				break
 			}
 			part := buffer[:read]
@@ -386,3 +408,8 @@ func (rpty *screenReconnectingPTY) Close(err error) {
 	// The closing state change will be handled by the lifecycle.
 	rpty.state.setState(StateClosing, err)
 }
+
+func (rpty *screenReconnectingPTY) ExitCode() (int, bool) {
+	_, _ = rpty.state.waitForState(StateDone)
+	return rpty.exitCode, rpty.exited
+}
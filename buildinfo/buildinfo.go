@@ -74,7 +74,12 @@ func VersionsMatch(v1, v2 string) bool {
 
 // IsDev returns true if this is a development build.
 func IsDev() bool {
-	return strings.HasPrefix(Version(), develPrefix)
+	return IsDevVersion(Version())
+}
+
+// IsDevVersion returns true if the version is that of a development build.
+func IsDevVersion(v string) bool {
+	return strings.HasPrefix(v, develPrefix)
 }
 
 // IsSlim returns true if this is a slim build.
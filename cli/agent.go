@@ -32,6 +32,7 @@ import (
 	"github.com/coder/coder/v2/agent/reaper"
 	"github.com/coder/coder/v2/buildinfo"
 	"github.com/coder/coder/v2/cli/clibase"
+	"github.com/coder/coder/v2/cli/gitauth"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/codersdk/agentsdk"
 )
@@ -286,9 +287,10 @@ func (r *RootCmd) workspaceAgent() *clibase.Cmd {
 				EnvironmentVariables: map[string]string{
 					"GIT_ASKPASS": executablePath,
 				},
-				IgnorePorts:   ignorePorts,
-				SSHMaxTimeout: sshMaxTimeout,
-				Subsystems:    subsystems,
+				GitCredentialHelper: gitauth.CredentialHelper(executablePath),
+				IgnorePorts:         ignorePorts,
+				SSHMaxTimeout:       sshMaxTimeout,
+				Subsystems:          subsystems,
 
 				PrometheusRegistry: prometheusRegistry,
 			})
@@ -68,6 +68,8 @@ func ProvisionerJob(ctx context.Context, writer io.Writer, opts ProvisionerJobOp
 		errChan  = make(chan error, 1)
 		job      codersdk.ProvisionerJob
 		jobMutex sync.Mutex
+
+		queuePosition int
 	)
 
 	sw := &stageWriter{w: writer, verbose: opts.Verbose, silentLogs: opts.Silent}
@@ -103,6 +105,11 @@ func ProvisionerJob(ctx context.Context, writer io.Writer, opts ProvisionerJobOp
 			return
 		}
 		if job.StartedAt == nil {
+			if job.QueuePosition > 0 && job.QueuePosition != queuePosition {
+				// Only report changes, the job is fetched every interval.
+				queuePosition = job.QueuePosition
+				sw.Log(time.Time{}, codersdk.LogLevelInfo, queueMessage(job))
+			}
 			return
 		}
 		if currentStage != "Queued" {
@@ -196,6 +203,20 @@ func ProvisionerJob(ctx context.Context, writer io.Writer, opts ProvisionerJobOp
 	}
 }
 
+// queueMessage describes the position of a pending job in the queue and
+// when it's expected to start.
+func queueMessage(job codersdk.ProvisionerJob) string {
+	message := fmt.Sprintf("Position %d of %d in the queue", job.QueuePosition, job.QueueSize)
+	if job.EstimatedStartAt == nil {
+		return message
+	}
+	startsIn := time.Until(*job.EstimatedStartAt).Round(time.Second)
+	if startsIn < time.Second {
+		return message + ", starting shortly"
+	}
+	return fmt.Sprintf("%s, estimated to start in %s", message, startsIn)
+}
+
 type stageWriter struct {
 	w          io.Writer
 	verbose    bool
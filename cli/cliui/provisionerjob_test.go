@@ -79,6 +79,39 @@ func TestProvisionerJob(t *testing.T) {
 		test.PTY.ExpectMatch("Something")
 	})
 
+	t.Run("QueuePosition", func(t *testing.T) {
+		t.Parallel()
+
+		test := newProvisionerJob(t)
+		test.JobMutex.Lock()
+		test.Job.QueuePosition = 3
+		test.Job.QueueSize = 5
+		startAt := dbtime.Now().Add(2 * time.Minute)
+		test.Job.EstimatedStartAt = &startAt
+		test.JobMutex.Unlock()
+		go func() {
+			<-test.Next
+			test.JobMutex.Lock()
+			test.Job.QueuePosition = 1
+			test.Job.EstimatedStartAt = nil
+			test.JobMutex.Unlock()
+			<-test.Next
+			test.JobMutex.Lock()
+			test.Job.Status = codersdk.ProvisionerJobSucceeded
+			now := dbtime.Now()
+			test.Job.StartedAt = &now
+			test.Job.CompletedAt = &now
+			close(test.Logs)
+			test.JobMutex.Unlock()
+		}()
+		test.PTY.ExpectMatch("Queued")
+		test.PTY.ExpectMatch("Position 3 of 5 in the queue, estimated to start in")
+		test.Next <- struct{}{}
+		test.PTY.ExpectMatch("Position 1 of 5 in the queue")
+		test.Next <- struct{}{}
+		test.PTY.ExpectMatch("Running")
+	})
+
 	// This cannot be ran in parallel because it uses a signal.
 	// nolint:paralleltest
 	t.Run("Cancel", func(t *testing.T) {
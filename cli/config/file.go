@@ -46,6 +46,33 @@ func (r Root) Organization() File {
 	return File(filepath.Join(string(r), "organization"))
 }
 
+// WorkspaceProxy is the workspace proxy region the CLI last connected to
+// workspaces through.
+func (r Root) WorkspaceProxy() File {
+	r.mustNotEmpty()
+	return File(filepath.Join(string(r), "workspace_proxy"))
+}
+
+// TunnelServerKey is the wireguard private key of "coder tunnel-server".
+func (r Root) TunnelServerKey() File {
+	r.mustNotEmpty()
+	return File(filepath.Join(string(r), "tunnel_server_key"))
+}
+
+// VPNSocket is the unix socket that "coder vpn" shares its connections to
+// workspaces with other commands over.
+func (r Root) VPNSocket() string {
+	r.mustNotEmpty()
+	return filepath.Join(string(r), "vpn.sock")
+}
+
+// SSHPersistDir holds the unix sockets that "coder ssh --persist" shares its
+// connections to workspaces over.
+func (r Root) SSHPersistDir() string {
+	r.mustNotEmpty()
+	return filepath.Join(string(r), "ssh-persist")
+}
+
 func (r Root) DotfilesURL() File {
 	r.mustNotEmpty()
 	return File(filepath.Join(string(r), "dotfilesurl"))
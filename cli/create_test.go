@@ -14,7 +14,7 @@ import (
 
 	"github.com/coder/coder/v2/cli/clitest"
 	"github.com/coder/coder/v2/coderd/coderdtest"
-	"github.com/coder/coder/v2/coderd/gitauth"
+	"github.com/coder/coder/v2/coderd/externalauth"
 	"github.com/coder/coder/v2/coderd/util/ptr"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/provisioner/echo"
@@ -587,6 +587,41 @@ func TestCreateValidateRichParameters(t *testing.T) {
 			}
 		}
 	})
+
+	t.Run("ValidateConditions", func(t *testing.T) {
+		t.Parallel()
+
+		conditionalRichParameters := []*proto.RichParameter{
+			{Name: "machine", Type: "string", Mutable: true, DefaultValue: "cpu"},
+			{
+				Name: "gpu_count", Type: "number", Mutable: true, DefaultValue: "1",
+				VisibleIf: `parameters["machine"] == "gpu"`,
+				ValidationRules: []*proto.RichParameterValidationRule{
+					{Condition: "tonumber(self.value) <= 4", Error: "At most 4 GPUs are available."},
+				},
+			},
+		}
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, prepareEchoResponses(conditionalRichParameters))
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+		inv, root := clitest.New(t, "create", "my-workspace", "--template", template.Name,
+			"--parameter", "machine=gpu", "--parameter", "gpu_count=8", "-y")
+		clitest.SetupConfig(t, client, root)
+		err := inv.Run()
+		require.ErrorContains(t, err, "At most 4 GPUs are available.")
+
+		// The GPU count is neither prompted for nor validated when it's hidden.
+		inv, root = clitest.New(t, "create", "my-workspace", "--template", template.Name,
+			"--parameter", "machine=cpu", "-y")
+		clitest.SetupConfig(t, client, root)
+		pty := ptytest.New(t).Attach(inv)
+		clitest.Start(t, inv)
+		pty.ExpectMatch("has been created")
+	})
 }
 
 func TestCreateWithGitAuth(t *testing.T) {
@@ -606,7 +641,7 @@ func TestCreateWithGitAuth(t *testing.T) {
 	}
 
 	client := coderdtest.New(t, &coderdtest.Options{
-		GitAuthConfigs: []*gitauth.Config{{
+		GitAuthConfigs: []*externalauth.Config{{
 			OAuth2Config: &testutil.OAuth2Config{},
 			ID:           "github",
 			Regex:        regexp.MustCompile(`github\.com`),
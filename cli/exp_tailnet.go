@@ -0,0 +1,186 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/clibase"
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+func (r *RootCmd) expTailnetCmd() *clibase.Cmd {
+	cmd := &clibase.Cmd{
+		Use:   "tailnet",
+		Short: "Inspect the tailnet of the deployment",
+		Handler: func(inv *clibase.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*clibase.Cmd{
+			r.expTailnetStatus(),
+		},
+	}
+	return cmd
+}
+
+// tailnetPeerRow is the type provided to the OutputFormatter.
+type tailnetPeerRow struct {
+	// For JSON format:
+	codersdk.TailnetPeer `table:"-"`
+
+	// For table format:
+	Name          string `json:"-" table:"name"`
+	ID            string `json:"-" table:"id"`
+	Type          string `json:"-" table:"type,default_sort"`
+	Coordinator   string `json:"-" table:"coordinator"`
+	Connected     bool   `json:"-" table:"connected"`
+	Tunnels       int    `json:"-" table:"tunnels"`
+	PreferredDERP int    `json:"-" table:"preferred derp"`
+	LastUpdate    string `json:"-" table:"last update"`
+	Path          string `json:"-" table:"path"`
+	Handshake     string `json:"-" table:"handshake"`
+	RxBytes       string `json:"-" table:"rx bytes"`
+	TxBytes       string `json:"-" table:"tx bytes"`
+}
+
+func tailnetPeerRowFromPeer(now time.Time, tunnels int, peer codersdk.TailnetPeer) tailnetPeerRow {
+	ago := func(t *time.Time) string {
+		if t == nil {
+			return "-"
+		}
+		return durationDisplay(now.Sub(*t).Truncate(time.Second)) + " ago"
+	}
+	row := tailnetPeerRow{
+		TailnetPeer:   peer,
+		Name:          peer.Name,
+		ID:            peer.ID.String(),
+		Type:          string(peer.Type),
+		Coordinator:   "-",
+		Connected:     peer.Connected,
+		Tunnels:       tunnels,
+		PreferredDERP: peer.PreferredDERP,
+		LastUpdate:    ago(peer.LastNodeUpdateAt),
+		Path:          "-",
+		Handshake:     "-",
+		RxBytes:       "-",
+		TxBytes:       "-",
+	}
+	if peer.CoordinatorID != nil {
+		row.Coordinator = peer.CoordinatorID.String()
+	}
+	if conn := peer.Connection; conn != nil {
+		row.Path = string(conn.Path)
+		if conn.Path == codersdk.TailnetPathDirect {
+			row.Path += " (" + conn.Address + ")"
+		} else if conn.Relay != "" {
+			row.Path += " (" + conn.Relay + ")"
+		}
+		row.Handshake = ago(conn.LastHandshakeAt)
+		row.RxBytes = strconv.FormatInt(conn.RxBytes, 10)
+		row.TxBytes = strconv.FormatInt(conn.TxBytes, 10)
+	}
+	return row
+}
+
+func (r *RootCmd) expTailnetStatus() *clibase.Cmd {
+	var (
+		agent     string
+		formatter = cliui.NewOutputFormatter(
+			cliui.TableFormat(
+				[]tailnetPeerRow{},
+				[]string{
+					"name",
+					"id",
+					"type",
+					"connected",
+					"tunnels",
+					"preferred derp",
+					"last update",
+					"path",
+					"handshake",
+				},
+			),
+			cliui.JSONFormat(),
+		)
+	)
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "status",
+		Short: "Show the peers of the tailnet coordinator and how they are connected",
+		Long: "Path, handshake and bytes are those of the connections of the replica that " +
+			"served the request, which only has connections to agents.",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(0),
+			r.InitClient(client),
+		),
+		Options: clibase.OptionSet{
+			{
+				Flag:        "agent",
+				Description: "Only show the agent with this ID or name, and the clients with tunnels to it.",
+				Value:       clibase.StringOf(&agent),
+			},
+		},
+		Handler: func(inv *clibase.Invocation) error {
+			status, err := client.DebugTailnetStatus(inv.Context())
+			if err != nil {
+				return err
+			}
+
+			tunnels := map[uuid.UUID]int{}
+			// peers maps an agent to the clients with tunnels to it.
+			peers := map[uuid.UUID]map[uuid.UUID]struct{}{}
+			for _, tunnel := range status.Tunnels {
+				tunnels[tunnel.Src]++
+				tunnels[tunnel.Dst]++
+				if peers[tunnel.Dst] == nil {
+					peers[tunnel.Dst] = map[uuid.UUID]struct{}{}
+				}
+				peers[tunnel.Dst][tunnel.Src] = struct{}{}
+			}
+
+			var selected map[uuid.UUID]struct{}
+			if agent != "" {
+				for _, peer := range status.Peers {
+					if peer.Type != codersdk.TailnetPeerTypeAgent {
+						continue
+					}
+					if peer.ID.String() != agent && peer.Name != agent {
+						continue
+					}
+					selected = map[uuid.UUID]struct{}{peer.ID: {}}
+					for id := range peers[peer.ID] {
+						selected[id] = struct{}{}
+					}
+					break
+				}
+				if selected == nil {
+					return xerrors.Errorf("agent %q is not known to the coordinator", agent)
+				}
+			}
+
+			now := time.Now()
+			rows := make([]tailnetPeerRow, 0, len(status.Peers))
+			for _, peer := range status.Peers {
+				if selected != nil {
+					if _, ok := selected[peer.ID]; !ok {
+						continue
+					}
+				}
+				rows = append(rows, tailnetPeerRowFromPeer(now, tunnels[peer.ID], peer))
+			}
+
+			out, err := formatter.Format(inv.Context(), rows)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
@@ -0,0 +1,77 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/agent"
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestExpTailnetStatus(t *testing.T) {
+	t.Parallel()
+
+	client, workspace, agentToken := setupWorkspaceForAgent(t, nil)
+	agentClient := agentsdk.New(client.URL)
+	agentClient.SetSessionToken(agentToken)
+	agentCloser := agent.New(agent.Options{
+		Client: agentClient,
+		Logger: slogtest.Make(t, nil).Named("agent"),
+	})
+	defer func() {
+		_ = agentCloser.Close()
+	}()
+	resources := coderdtest.AwaitWorkspaceAgents(t, client, workspace.ID)
+	agentID := resources[0].Agents[0].ID
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	t.Run("Table", func(t *testing.T) {
+		t.Parallel()
+
+		inv, root := clitest.New(t, "exp", "tailnet", "status")
+		clitest.SetupConfig(t, client, root)
+		out := bytes.NewBuffer(nil)
+		inv.Stdout = out
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+		require.Contains(t, out.String(), agentID.String())
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		t.Parallel()
+
+		inv, root := clitest.New(t, "exp", "tailnet", "status", "--agent", agentID.String(), "--output", "json")
+		clitest.SetupConfig(t, client, root)
+		out := bytes.NewBuffer(nil)
+		inv.Stdout = out
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+
+		var peers []codersdk.TailnetPeer
+		require.NoError(t, json.Unmarshal(out.Bytes(), &peers))
+		require.Len(t, peers, 1)
+		require.Equal(t, agentID, peers[0].ID)
+		require.Equal(t, codersdk.TailnetPeerTypeAgent, peers[0].Type)
+		require.True(t, peers[0].Connected)
+	})
+
+	t.Run("UnknownAgent", func(t *testing.T) {
+		t.Parallel()
+
+		inv, root := clitest.New(t, "exp", "tailnet", "status", "--agent", "doesnotexist")
+		clitest.SetupConfig(t, client, root)
+		err := inv.WithContext(ctx).Run()
+		require.ErrorContains(t, err, "not known to the coordinator")
+	})
+}
@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"fmt"
+	"os/signal"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/clibase"
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+)
+
+func (r *RootCmd) externalAuth() *clibase.Cmd {
+	return &clibase.Cmd{
+		Use:   "external-auth",
+		Short: "Manage external authentication",
+		Long:  "Authenticate with external services inside of a workspace.",
+		Handler: func(inv *clibase.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*clibase.Cmd{
+			r.externalAuthAccessToken(),
+		},
+	}
+}
+
+func (r *RootCmd) externalAuthAccessToken() *clibase.Cmd {
+	return &clibase.Cmd{
+		Use:   "access-token <provider>",
+		Short: "Print an access token for an external auth provider",
+		Long: "If the workspace owner has not authenticated with the provider, the URL\n" +
+			"to authenticate at is printed instead and the command exits with a non-zero\n" +
+			"code.\n" + formatExamples(
+			example{
+				Description: "Print the access token for the GitHub provider",
+				Command:     "coder external-auth access-token github",
+			},
+			example{
+				Description: "Use the access token of a Jira provider in a script",
+				Command:     `curl -H "Authorization: Bearer $(coder external-auth access-token jira)" https://api.atlassian.com/me`,
+			},
+		),
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx := inv.Context()
+
+			ctx, stop := signal.NotifyContext(ctx, InterruptSignals...)
+			defer stop()
+
+			client, err := r.createAgentClient()
+			if err != nil {
+				return xerrors.Errorf("create agent client: %w", err)
+			}
+
+			token, err := client.ExternalAuth(ctx, agentsdk.ExternalAuthRequest{
+				ID: inv.Args[0],
+			})
+			if err != nil {
+				return xerrors.Errorf("get external auth token: %w", err)
+			}
+
+			if token.AccessToken == "" {
+				_, _ = fmt.Fprintln(inv.Stdout, token.URL)
+				return cliui.Canceled
+			}
+			_, _ = fmt.Fprintln(inv.Stdout, token.AccessToken)
+			return nil
+		},
+	}
+}
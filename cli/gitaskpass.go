@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -13,6 +14,7 @@ import (
 	"github.com/coder/coder/v2/cli/cliui"
 	"github.com/coder/coder/v2/cli/gitauth"
 	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
 	"github.com/coder/retry"
 )
 
@@ -38,38 +40,9 @@ func (r *RootCmd) gitAskpass() *clibase.Cmd {
 				return xerrors.Errorf("create agent client: %w", err)
 			}
 
-			token, err := client.GitAuth(ctx, host, false)
+			token, err := gitAuthToken(ctx, inv, client, host)
 			if err != nil {
-				var apiError *codersdk.Error
-				if errors.As(err, &apiError) && apiError.StatusCode() == http.StatusNotFound {
-					// This prevents the "Run 'coder --help' for usage"
-					// message from occurring.
-					lines := []string{apiError.Message}
-					if apiError.Detail != "" {
-						lines = append(lines, apiError.Detail)
-					}
-					cliui.Warn(inv.Stderr, "Coder was unable to handle this git request. The default git behavior will be used instead.",
-						lines...,
-					)
-					return cliui.Canceled
-				}
-				return xerrors.Errorf("get git token: %w", err)
-			}
-			if token.URL != "" {
-				if err := openURL(inv, token.URL); err == nil {
-					cliui.Infof(inv.Stderr, "Your browser has been opened to authenticate with Git:\n%s", token.URL)
-				} else {
-					cliui.Infof(inv.Stderr, "Open the following URL to authenticate with Git:\n%s", token.URL)
-				}
-
-				for r := retry.New(250*time.Millisecond, 10*time.Second); r.Wait(ctx); {
-					token, err = client.GitAuth(ctx, host, true)
-					if err != nil {
-						continue
-					}
-					cliui.Infof(inv.Stderr, "You've been authenticated with Git!")
-					break
-				}
+				return err
 			}
 
 			if token.Password != "" {
@@ -86,3 +59,44 @@ func (r *RootCmd) gitAskpass() *clibase.Cmd {
 		},
 	}
 }
+
+// gitAuthToken fetches a Git username and password for the URL. If the
+// workspace owner has not authenticated with the Git provider, the browser is
+// opened and it waits for them to authenticate. cliui.Canceled is returned if
+// no Git provider in Coder matches the URL.
+func gitAuthToken(ctx context.Context, inv *clibase.Invocation, client *agentsdk.Client, gitURL string) (agentsdk.GitAuthResponse, error) {
+	token, err := client.GitAuth(ctx, gitURL, false)
+	if err != nil {
+		var apiError *codersdk.Error
+		if errors.As(err, &apiError) && apiError.StatusCode() == http.StatusNotFound {
+			// This prevents the "Run 'coder --help' for usage"
+			// message from occurring.
+			lines := []string{apiError.Message}
+			if apiError.Detail != "" {
+				lines = append(lines, apiError.Detail)
+			}
+			cliui.Warn(inv.Stderr, "Coder was unable to handle this git request. The default git behavior will be used instead.",
+				lines...,
+			)
+			return agentsdk.GitAuthResponse{}, cliui.Canceled
+		}
+		return agentsdk.GitAuthResponse{}, xerrors.Errorf("get git token: %w", err)
+	}
+	if token.URL != "" {
+		if err := openURL(inv, token.URL); err == nil {
+			cliui.Infof(inv.Stderr, "Your browser has been opened to authenticate with Git:\n%s", token.URL)
+		} else {
+			cliui.Infof(inv.Stderr, "Open the following URL to authenticate with Git:\n%s", token.URL)
+		}
+
+		for r := retry.New(250*time.Millisecond, 10*time.Second); r.Wait(ctx); {
+			token, err = client.GitAuth(ctx, gitURL, true)
+			if err != nil {
+				continue
+			}
+			cliui.Infof(inv.Stderr, "You've been authenticated with Git!")
+			break
+		}
+	}
+	return token, nil
+}
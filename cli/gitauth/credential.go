@@ -0,0 +1,178 @@
+package gitauth
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+	"golang.org/x/xerrors"
+)
+
+// credentialHelperMarker is written at the top of the git config file of the
+// credential helper, which is replaced whenever the agent starts.
+const credentialHelperMarker = "# Managed by the Coder agent. Changes will be overwritten."
+
+// Credential is a set of attributes of the git credential protocol.
+// See: https://git-scm.com/docs/git-credential#IOFMT
+type Credential struct {
+	Protocol string
+	Host     string
+	Path     string
+	Username string
+	Password string
+}
+
+// ParseCredential reads attributes from git until a blank line or EOF.
+// Unknown attributes are ignored.
+func ParseCredential(r io.Reader) (Credential, error) {
+	var cred Credential
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			break
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return Credential{}, xerrors.Errorf("invalid attribute: %q", line)
+		}
+		switch key {
+		case "protocol":
+			cred.Protocol = value
+		case "host":
+			cred.Host = value
+		case "path":
+			cred.Path = value
+		case "username":
+			cred.Username = value
+		case "password":
+			cred.Password = value
+		case "url":
+			// The url attribute is shorthand for the other attributes.
+			u, err := url.Parse(value)
+			if err != nil {
+				return Credential{}, xerrors.Errorf("parse url: %w", err)
+			}
+			cred.Protocol = u.Scheme
+			cred.Host = u.Host
+			cred.Path = strings.TrimPrefix(u.Path, "/")
+			if u.User != nil {
+				cred.Username = u.User.Username()
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return Credential{}, xerrors.Errorf("read attributes: %w", err)
+	}
+	return cred, nil
+}
+
+// URL returns the URL git is requesting credentials for. Only HTTP
+// protocols are supported. The path is only set by git if
+// credential.useHttpPath is enabled.
+func (c Credential) URL() (string, error) {
+	switch c.Protocol {
+	case "http", "https":
+	default:
+		return "", xerrors.Errorf("unsupported protocol: %q", c.Protocol)
+	}
+	if c.Host == "" {
+		return "", xerrors.Errorf("host is empty")
+	}
+	u := url.URL{
+		Scheme: c.Protocol,
+		Host:   c.Host,
+	}
+	if c.Path != "" {
+		u.Path = "/" + c.Path
+	}
+	return u.String(), nil
+}
+
+// WriteCredential writes the username and password attributes for git.
+func WriteCredential(w io.Writer, cred Credential) error {
+	_, err := fmt.Fprintf(w, "username=%s\npassword=%s\n", cred.Username, cred.Password)
+	return err
+}
+
+// CredentialHelper returns the git credential helper that invokes the
+// `git-credential` subcommand of the executable.
+func CredentialHelper(executablePath string) string {
+	return "!'" + strings.ReplaceAll(executablePath, "'", `'\''`) + "' git-credential"
+}
+
+// CredentialHelperConfigPath returns the git config file the credential
+// helper is written to. It is included from the global git config.
+func CredentialHelperConfigPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "coderv2", "git-credential.gitconfig"), nil
+}
+
+// InstallCredentialHelper writes the helper to its own git config file and
+// includes that file from the global git config of the user. The global
+// config is only appended to once, so later installs that change the path of
+// the executable don't rewrite it.
+func InstallCredentialHelper(fs afero.Fs, helper string) error {
+	helperPath, err := CredentialHelperConfigPath()
+	if err != nil {
+		return err
+	}
+	err = fs.MkdirAll(filepath.Dir(helperPath), 0o700)
+	if err != nil {
+		return xerrors.Errorf("create %q: %w", filepath.Dir(helperPath), err)
+	}
+	content := fmt.Sprintf("%s\n[credential]\n\thelper = %s\n", credentialHelperMarker, quoteConfigValue(helper))
+	err = afero.WriteFile(fs, helperPath, []byte(content), 0o600)
+	if err != nil {
+		return xerrors.Errorf("write %q: %w", helperPath, err)
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+	configPath := filepath.Join(home, ".gitconfig")
+	data, err := afero.ReadFile(fs, configPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return xerrors.Errorf("read %q: %w", configPath, err)
+	}
+	include := "\tpath = " + quoteConfigValue(helperPath) + "\n"
+	if bytes.Contains(data, []byte(include)) {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	if len(data) > 0 && !bytes.HasSuffix(data, []byte("\n")) {
+		_, _ = buf.WriteString("\n")
+	}
+	_, _ = buf.WriteString("[include]\n")
+	_, _ = buf.WriteString(include)
+	f, err := fs.OpenFile(configPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return xerrors.Errorf("open %q: %w", configPath, err)
+	}
+	defer f.Close()
+	_, err = f.Write(buf.Bytes())
+	if err != nil {
+		return xerrors.Errorf("write %q: %w", configPath, err)
+	}
+	return nil
+}
+
+// quoteConfigValue quotes a value for a git config file.
+// See: https://git-scm.com/docs/git-config#_syntax
+func quoteConfigValue(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	return `"` + value + `"`
+}
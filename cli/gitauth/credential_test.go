@@ -0,0 +1,136 @@
+package gitauth_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/gitauth"
+)
+
+func TestParseCredential(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name    string
+		in      string
+		wantURL string
+		wantErr bool
+	}{
+		{
+			name:    "Host",
+			in:      "protocol=https\nhost=github.com\n\n",
+			wantURL: "https://github.com",
+		},
+		{
+			name:    "Path",
+			in:      "protocol=https\nhost=github.com\npath=coder/coder.git\nusername=kyle\n",
+			wantURL: "https://github.com/coder/coder.git",
+		},
+		{
+			name:    "Port",
+			in:      "protocol=http\nhost=git.example.com:8080\n",
+			wantURL: "http://git.example.com:8080",
+		},
+		{
+			name:    "URL",
+			in:      "url=https://kyle@gitlab.com/coder/coder\n",
+			wantURL: "https://gitlab.com/coder/coder",
+		},
+		{
+			name:    "UnknownAttributes",
+			in:      "capability[]=authtype\nprotocol=https\nhost=github.com\nwwwauth[]=Basic realm=\"GitHub\"\n",
+			wantURL: "https://github.com",
+		},
+		{
+			name:    "UnsupportedProtocol",
+			in:      "protocol=ssh\nhost=github.com\n",
+			wantErr: true,
+		},
+		{
+			name:    "NoHost",
+			in:      "protocol=https\n",
+			wantErr: true,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			cred, err := gitauth.ParseCredential(strings.NewReader(tc.in))
+			require.NoError(t, err)
+			gitURL, err := cred.URL()
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.wantURL, gitURL)
+		})
+	}
+
+	t.Run("InvalidAttribute", func(t *testing.T) {
+		t.Parallel()
+		_, err := gitauth.ParseCredential(strings.NewReader("protocol\n"))
+		require.Error(t, err)
+	})
+}
+
+func TestWriteCredential(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	err := gitauth.WriteCredential(&buf, gitauth.Credential{
+		Username: "oauth2",
+		Password: "token",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "username=oauth2\npassword=token\n", buf.String())
+}
+
+func TestInstallCredentialHelper(t *testing.T) {
+	t.Parallel()
+	home, err := os.UserHomeDir()
+	require.NoError(t, err)
+	configPath := filepath.Join(home, ".gitconfig")
+	helperPath, err := gitauth.CredentialHelperConfigPath()
+	require.NoError(t, err)
+
+	t.Run("Create", func(t *testing.T) {
+		t.Parallel()
+		fs := afero.NewMemMapFs()
+		err := gitauth.InstallCredentialHelper(fs, gitauth.CredentialHelper("/tmp/coder"))
+		require.NoError(t, err)
+		data, err := afero.ReadFile(fs, helperPath)
+		require.NoError(t, err)
+		require.Contains(t, string(data), "[credential]\n")
+		require.Contains(t, string(data), "\thelper = \"!'/tmp/coder' git-credential\"\n")
+		data, err = afero.ReadFile(fs, configPath)
+		require.NoError(t, err)
+		require.Equal(t, "[include]\n\tpath = \""+helperPath+"\"\n", string(data))
+	})
+	t.Run("Replace", func(t *testing.T) {
+		t.Parallel()
+		fs := afero.NewMemMapFs()
+		existing := "[user]\n\tname = Kyle"
+		err := afero.WriteFile(fs, configPath, []byte(existing), 0o644)
+		require.NoError(t, err)
+
+		err = gitauth.InstallCredentialHelper(fs, gitauth.CredentialHelper("/tmp/coder.1/coder"))
+		require.NoError(t, err)
+		err = gitauth.InstallCredentialHelper(fs, gitauth.CredentialHelper("/tmp/coder.2/coder"))
+		require.NoError(t, err)
+
+		data, err := afero.ReadFile(fs, helperPath)
+		require.NoError(t, err)
+		require.NotContains(t, string(data), "/tmp/coder.1/coder")
+		require.Contains(t, string(data), "/tmp/coder.2/coder")
+
+		// The global config is only appended to once.
+		data, err = afero.ReadFile(fs, configPath)
+		require.NoError(t, err)
+		require.Equal(t, existing+"\n[include]\n\tpath = \""+helperPath+"\"\n", string(data))
+	})
+}
@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"os/signal"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/clibase"
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/cli/gitauth"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+)
+
+// gitCredential is a git credential helper that is installed by the Coder
+// agent to authenticate tools that don't use GIT_ASKPASS, such as Git LFS.
+// See: https://git-scm.com/docs/gitcredentials#_custom_helpers
+func (r *RootCmd) gitCredential() *clibase.Cmd {
+	return &clibase.Cmd{
+		Use:    "git-credential <get|store|erase>",
+		Short:  "Git credential helper for Coder Git providers",
+		Hidden: true,
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx := inv.Context()
+
+			ctx, stop := signal.NotifyContext(ctx, InterruptSignals...)
+			defer stop()
+
+			cred, err := gitauth.ParseCredential(inv.Stdin)
+			if err != nil {
+				return xerrors.Errorf("parse credential: %w", err)
+			}
+
+			if inv.Args[0] == "store" {
+				// Tokens are stored and refreshed by Coder, so there is
+				// nothing to do when git approves them.
+				return nil
+			}
+
+			gitURL, err := cred.URL()
+			if err != nil {
+				// Other protocols are handled by other helpers.
+				return nil
+			}
+
+			client, err := r.createAgentClient()
+			if err != nil {
+				return xerrors.Errorf("create agent client: %w", err)
+			}
+
+			if inv.Args[0] == "erase" {
+				return eraseGitCredential(ctx, inv, client, gitURL, cred)
+			}
+
+			token, err := gitAuthToken(ctx, inv, client, gitURL)
+			if err != nil {
+				if errors.Is(err, cliui.Canceled) {
+					// Writing no attributes makes git fall back to the
+					// next helper or prompt.
+					return nil
+				}
+				return err
+			}
+			if token.Username == "" {
+				return nil
+			}
+
+			password := token.Password
+			if password == "" {
+				// Providers like GitHub accept the token as both the
+				// username and password.
+				password = token.Username
+			}
+			return gitauth.WriteCredential(inv.Stdout, gitauth.Credential{
+				Username: token.Username,
+				Password: password,
+			})
+		},
+	}
+}
+
+// eraseGitCredential is called by git when the remote rejected credentials.
+// Coder tokens can't be erased locally, so if the rejected credentials came
+// from Coder, the user is told to authenticate again instead of git
+// silently retrying with the same token.
+func eraseGitCredential(ctx context.Context, inv *clibase.Invocation, client *agentsdk.Client, gitURL string, cred gitauth.Credential) error {
+	token, err := client.GitAuth(ctx, gitURL, false)
+	if err != nil {
+		// Credentials for hosts Coder doesn't handle belong to other
+		// helpers.
+		return nil
+	}
+	if token.Username == "" || token.Username != cred.Username {
+		return nil
+	}
+	password := token.Password
+	if password == "" {
+		password = token.Username
+	}
+	if password != cred.Password {
+		return nil
+	}
+	cliui.Warn(inv.Stderr, "Git rejected the credentials Coder provided for "+gitURL+".",
+		"The token may have been revoked or may lack access to the repository.",
+		"Reconnect the Git provider in your Coder account and try again.",
+	)
+	return nil
+}
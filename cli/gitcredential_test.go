@@ -0,0 +1,130 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+)
+
+func TestGitCredential(t *testing.T) {
+	t.Parallel()
+	t.Run("Get", func(t *testing.T) {
+		t.Parallel()
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "https://gitlab.com/coder/coder.git", r.URL.Query().Get("url"))
+			httpapi.Write(context.Background(), w, http.StatusOK, agentsdk.GitAuthResponse{
+				Username: "oauth2",
+				Password: "bananas",
+			})
+		}))
+		t.Cleanup(srv.Close)
+		inv, _ := clitest.New(t, "--agent-url", srv.URL, "git-credential", "get")
+		inv.Stdin = strings.NewReader("protocol=https\nhost=gitlab.com\npath=coder/coder.git\n\n")
+		var stdout bytes.Buffer
+		inv.Stdout = &stdout
+		err := inv.Run()
+		require.NoError(t, err)
+		require.Equal(t, "username=oauth2\npassword=bananas\n", stdout.String())
+	})
+
+	t.Run("TokenAsPassword", func(t *testing.T) {
+		t.Parallel()
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			httpapi.Write(context.Background(), w, http.StatusOK, agentsdk.GitAuthResponse{
+				Username: "bananas",
+			})
+		}))
+		t.Cleanup(srv.Close)
+		inv, _ := clitest.New(t, "--agent-url", srv.URL, "git-credential", "get")
+		inv.Stdin = strings.NewReader("protocol=https\nhost=github.com\n\n")
+		var stdout bytes.Buffer
+		inv.Stdout = &stdout
+		err := inv.Run()
+		require.NoError(t, err)
+		require.Equal(t, "username=bananas\npassword=bananas\n", stdout.String())
+	})
+
+	t.Run("NoHost", func(t *testing.T) {
+		t.Parallel()
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			httpapi.Write(context.Background(), w, http.StatusNotFound, codersdk.Response{
+				Message: "Nope!",
+			})
+		}))
+		t.Cleanup(srv.Close)
+		inv, _ := clitest.New(t, "--agent-url", srv.URL, "git-credential", "get")
+		inv.Stdin = strings.NewReader("protocol=https\nhost=example.com\n\n")
+		var stdout, stderr bytes.Buffer
+		inv.Stdout = &stdout
+		inv.Stderr = &stderr
+		err := inv.Run()
+		require.NoError(t, err)
+		require.Empty(t, stdout.String())
+		require.Contains(t, stderr.String(), "Nope!")
+	})
+
+	t.Run("Store", func(t *testing.T) {
+		t.Parallel()
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Error("store should not make requests")
+		}))
+		t.Cleanup(srv.Close)
+		inv, _ := clitest.New(t, "--agent-url", srv.URL, "git-credential", "store")
+		inv.Stdin = strings.NewReader("protocol=https\nhost=github.com\nusername=bananas\npassword=bananas\n\n")
+		var stdout bytes.Buffer
+		inv.Stdout = &stdout
+		err := inv.Run()
+		require.NoError(t, err)
+		require.Empty(t, stdout.String())
+	})
+
+	t.Run("Erase", func(t *testing.T) {
+		t.Parallel()
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.False(t, r.URL.Query().Has("listen"))
+			httpapi.Write(context.Background(), w, http.StatusOK, agentsdk.GitAuthResponse{
+				Username: "bananas",
+			})
+		}))
+		t.Cleanup(srv.Close)
+		inv, _ := clitest.New(t, "--agent-url", srv.URL, "git-credential", "erase")
+		inv.Stdin = strings.NewReader("protocol=https\nhost=github.com\nusername=bananas\npassword=bananas\n\n")
+		var stdout, stderr bytes.Buffer
+		inv.Stdout = &stdout
+		inv.Stderr = &stderr
+		err := inv.Run()
+		require.NoError(t, err)
+		require.Empty(t, stdout.String())
+		require.Contains(t, stderr.String(), "Git rejected the credentials")
+	})
+
+	t.Run("EraseOtherCredential", func(t *testing.T) {
+		t.Parallel()
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			httpapi.Write(context.Background(), w, http.StatusOK, agentsdk.GitAuthResponse{
+				Username: "bananas",
+			})
+		}))
+		t.Cleanup(srv.Close)
+		inv, _ := clitest.New(t, "--agent-url", srv.URL, "git-credential", "erase")
+		inv.Stdin = strings.NewReader("protocol=https\nhost=github.com\nusername=apples\npassword=apples\n\n")
+		var stdout, stderr bytes.Buffer
+		inv.Stdout = &stdout
+		inv.Stderr = &stderr
+		err := inv.Run()
+		require.NoError(t, err)
+		require.Empty(t, stdout.String())
+		require.Empty(t, stderr.String())
+	})
+}
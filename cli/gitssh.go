@@ -16,6 +16,8 @@ import (
 
 	"github.com/coder/coder/v2/cli/clibase"
 	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/coderd/gitsshkey"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
 )
 
 func (r *RootCmd) gitssh() *clibase.Cmd {
@@ -33,7 +35,7 @@ func (r *RootCmd) gitssh() *clibase.Cmd {
 			defer stop()
 
 			// Early check so errors are reported immediately.
-			identityFiles, err := parseIdentityFilesForHost(ctx, inv.Args, env)
+			host, identityFiles, err := parseSSHConfigForHost(ctx, inv.Args, env)
 			if err != nil {
 				return err
 			}
@@ -42,36 +44,33 @@ func (r *RootCmd) gitssh() *clibase.Cmd {
 			if err != nil {
 				return xerrors.Errorf("create agent client: %w", err)
 			}
-			key, err := client.GitSSHKey(ctx)
+			allKeys, err := client.GitSSHKeys(ctx)
 			if err != nil {
-				return xerrors.Errorf("get agent git ssh token: %w", err)
+				return xerrors.Errorf("get agent git ssh keys: %w", err)
 			}
+			keys := gitSSHKeysForHost(allKeys, host)
 
-			privateKeyFile, err := os.CreateTemp("", "coder-gitsshkey-*")
-			if err != nil {
-				return xerrors.Errorf("create temp gitsshkey file: %w", err)
-			}
-			defer func() {
-				_ = privateKeyFile.Close()
-				_ = os.Remove(privateKeyFile.Name())
-			}()
-			_, err = privateKeyFile.WriteString(key.PrivateKey)
-			if err != nil {
-				return xerrors.Errorf("write to temp gitsshkey file: %w", err)
-			}
-			err = privateKeyFile.Close()
-			if err != nil {
-				return xerrors.Errorf("close temp gitsshkey file: %w", err)
-			}
-
-			// Append our key, giving precedence to user keys. Note that
+			// Append our keys, giving precedence to user keys. Note that
 			// OpenSSH server are typically configured with MaxAuthTries
 			// set to the default value of 6. This means that only the 6
 			// first keys can be tried. However, we will assume that if
 			// a user has configured 6+ keys for a host, they know what
 			// they're doing. This behavior is critical if a server has
 			// been configured with MaxAuthTries set to 1.
-			identityFiles = append(identityFiles, privateKeyFile.Name())
+			var privateKeyFiles []string
+			defer func() {
+				for _, name := range privateKeyFiles {
+					_ = os.Remove(name)
+				}
+			}()
+			for _, key := range keys {
+				name, err := writeTempPrivateKey(key.PrivateKey)
+				if err != nil {
+					return err
+				}
+				privateKeyFiles = append(privateKeyFiles, name)
+			}
+			identityFiles = append(identityFiles, privateKeyFiles...)
 
 			var identityArgs []string
 			for _, id := range identityFiles {
@@ -91,8 +90,10 @@ func (r *RootCmd) gitssh() *clibase.Cmd {
 				if xerrors.As(err, &exitErr) && exitErr.ExitCode() == 255 {
 					_, _ = fmt.Fprintln(inv.Stderr,
 						"\n"+cliui.DefaultStyles.Wrap.Render("Coder authenticates with "+cliui.DefaultStyles.Field.Render("git")+
-							" using the public key below. All clones with SSH are authenticated automatically 🪄.")+"\n")
-					_, _ = fmt.Fprintln(inv.Stderr, cliui.DefaultStyles.Code.Render(strings.TrimSpace(key.PublicKey))+"\n")
+							" using the public keys below. All clones with SSH are authenticated automatically 🪄.")+"\n")
+					for _, key := range keys {
+						_, _ = fmt.Fprintln(inv.Stderr, cliui.DefaultStyles.Code.Render(strings.TrimSpace(key.PublicKey))+"\n")
+					}
 					_, _ = fmt.Fprintln(inv.Stderr, "Add to GitHub and GitLab:")
 					_, _ = fmt.Fprintln(inv.Stderr, cliui.DefaultStyles.Prompt.String()+"https://github.com/settings/ssh/new")
 					_, _ = fmt.Fprintln(inv.Stderr, cliui.DefaultStyles.Prompt.String()+"https://gitlab.com/-/profile/keys")
@@ -109,6 +110,45 @@ func (r *RootCmd) gitssh() *clibase.Cmd {
 	return cmd
 }
 
+// writeTempPrivateKey writes a private key to a temporary file and returns
+// its name. The caller is responsible for removing the file.
+func writeTempPrivateKey(privateKey string) (string, error) {
+	privateKeyFile, err := os.CreateTemp("", "coder-gitsshkey-*")
+	if err != nil {
+		return "", xerrors.Errorf("create temp gitsshkey file: %w", err)
+	}
+	_, err = privateKeyFile.WriteString(privateKey)
+	if err != nil {
+		_ = privateKeyFile.Close()
+		_ = os.Remove(privateKeyFile.Name())
+		return "", xerrors.Errorf("write to temp gitsshkey file: %w", err)
+	}
+	err = privateKeyFile.Close()
+	if err != nil {
+		_ = os.Remove(privateKeyFile.Name())
+		return "", xerrors.Errorf("close temp gitsshkey file: %w", err)
+	}
+	return privateKeyFile.Name(), nil
+}
+
+// gitSSHKeysForHost returns the keys to offer to the host, with the default
+// key first. Servers stop accepting keys after a few attempts, so the key
+// that works for most users is tried before any others.
+func gitSSHKeysForHost(keys []agentsdk.GitSSHKey, host string) []agentsdk.GitSSHKey {
+	matched := make([]agentsdk.GitSSHKey, 0, len(keys))
+	for _, key := range keys {
+		if !key.MatchesHost(host) {
+			continue
+		}
+		if key.Name == gitsshkey.DefaultName {
+			matched = append([]agentsdk.GitSSHKey{key}, matched...)
+			continue
+		}
+		matched = append(matched, key)
+	}
+	return matched
+}
+
 // fallbackIdentityFiles is the list of identity files SSH tries when
 // none have been defined for a host.
 var fallbackIdentityFiles = strings.Join([]string{
@@ -121,9 +161,10 @@ var fallbackIdentityFiles = strings.Join([]string{
 	"identityfile ~/.ssh/id_xmss",
 }, "\n")
 
-// parseIdentityFilesForHost uses ssh -G to discern what SSH keys have
-// been enabled for the host (via the users SSH config) and returns a
-// list of existing identity files.
+// parseSSHConfigForHost uses ssh -G to discern the name of the host and
+// what SSH keys have been enabled for it (via the users SSH config), and
+// returns the host name and a list of existing identity files. The host
+// name is empty if ssh -G isn't supported.
 //
 // We do this because when no keys are defined for a host, SSH uses
 // fallback keys (see above). However, by passing `-i` to attach our
@@ -135,10 +176,10 @@ var fallbackIdentityFiles = strings.Join([]string{
 //
 // The extra arguments work without issue and lets us run the command
 // as-is without stripping out the excess (git-upload-pack 'coder/coder').
-func parseIdentityFilesForHost(ctx context.Context, args, env []string) (identityFiles []string, error error) {
+func parseSSHConfigForHost(ctx context.Context, args, env []string) (host string, identityFiles []string, error error) {
 	home, err := os.UserHomeDir()
 	if err != nil {
-		return nil, xerrors.Errorf("get user home dir failed: %w", err)
+		return "", nil, xerrors.Errorf("get user home dir failed: %w", err)
 	}
 
 	var outBuf bytes.Buffer
@@ -159,6 +200,9 @@ func parseIdentityFilesForHost(ctx context.Context, args, env []string) (identit
 	s := bufio.NewScanner(r)
 	for s.Scan() {
 		line := s.Text()
+		if strings.HasPrefix(line, "hostname ") {
+			host = strings.TrimPrefix(line, "hostname ")
+		}
 		if strings.HasPrefix(line, "identityfile ") {
 			id := strings.TrimPrefix(line, "identityfile ")
 			if strings.HasPrefix(id, "~/") {
@@ -183,8 +227,8 @@ func parseIdentityFilesForHost(ctx context.Context, args, env []string) (identit
 	}
 	if err := s.Err(); err != nil {
 		// This should never happen, the check is for completeness.
-		return nil, xerrors.Errorf("scan ssh output: %w", err)
+		return "", nil, xerrors.Errorf("scan ssh output: %w", err)
 	}
 
-	return identityFiles, nil
+	return host, identityFiles, nil
 }
@@ -0,0 +1,31 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+)
+
+func TestGitSSHKeysForHost(t *testing.T) {
+	t.Parallel()
+
+	keys := []agentsdk.GitSSHKey{
+		{Name: "work", Hosts: []string{"*.example.com"}},
+		{Name: "laptop"},
+		{Name: "default"},
+	}
+	names := func(keys []agentsdk.GitSSHKey) []string {
+		var names []string
+		for _, key := range keys {
+			names = append(names, key.Name)
+		}
+		return names
+	}
+
+	require.Equal(t, []string{"default", "work", "laptop"}, names(gitSSHKeysForHost(keys, "git.example.com")))
+	require.Equal(t, []string{"default", "laptop"}, names(gitSSHKeysForHost(keys, "github.com")))
+	// Restricted keys aren't offered if the host is unknown.
+	require.Equal(t, []string{"default", "laptop"}, names(gitSSHKeysForHost(keys, "")))
+}
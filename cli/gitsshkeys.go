@@ -0,0 +1,281 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/clibase"
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+func (r *RootCmd) gitSSHKeys() *clibase.Cmd {
+	cmd := &clibase.Cmd{
+		Use:   "gitsshkeys",
+		Short: "Manage the SSH keys used for Git operations",
+		Long: "When Git connects with SSH inside of a workspace, the \"default\" key is\n" +
+			"offered first, followed by the other keys whose host patterns match. The\n" +
+			"\"default\" key is generated for every user and can't be removed.\n" + formatExamples(
+			example{
+				Description: "Generate an RSA key for a provider that doesn't support Ed25519",
+				Command:     "coder gitsshkeys create azure --type rsa4096",
+			},
+			example{
+				Description: "Import an existing private key",
+				Command:     "coder gitsshkeys create laptop --import ~/.ssh/id_ed25519",
+			},
+			example{
+				Description: "Generate a key and register it with the GitHub provider",
+				Command:     "coder gitsshkeys create work --upload github",
+			},
+			example{
+				Description: "Generate a key that is only offered to GitHub",
+				Command:     "coder gitsshkeys create github --host github.com --host '*.github.com'",
+			},
+			example{
+				Description: "List your keys",
+				Command:     "coder gitsshkeys ls",
+			},
+		),
+		Aliases: []string{"gitsshkey"},
+		Handler: func(inv *clibase.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*clibase.Cmd{
+			r.createGitSSHKey(),
+			r.listGitSSHKeys(),
+			r.regenerateGitSSHKey(),
+			r.removeGitSSHKey(),
+			r.uploadGitSSHKey(),
+		},
+	}
+	return cmd
+}
+
+func (r *RootCmd) createGitSSHKey() *clibase.Cmd {
+	var (
+		keyType    string
+		importPath string
+		uploadTo   []string
+		hosts      []string
+	)
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "create <name>",
+		Short: "Generate or import a key",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			req := codersdk.CreateGitSSHKeyRequest{
+				Name:      inv.Args[0],
+				Algorithm: keyType,
+				Hosts:     hosts,
+			}
+			if importPath != "" {
+				if keyType != "" {
+					return xerrors.New("--type cannot be used with --import")
+				}
+				privateKey, err := os.ReadFile(importPath)
+				if err != nil {
+					return xerrors.Errorf("read private key: %w", err)
+				}
+				req.PrivateKey = string(privateKey)
+			}
+
+			key, err := client.CreateGitSSHKey(inv.Context(), codersdk.Me, req)
+			if err != nil {
+				return xerrors.Errorf("create git ssh key: %w", err)
+			}
+
+			for _, provider := range uploadTo {
+				err = client.UploadGitSSHKey(inv.Context(), codersdk.Me, key.Name, codersdk.UploadGitSSHKeyRequest{
+					ExternalAuthProviderID: provider,
+				})
+				if err != nil {
+					return xerrors.Errorf("upload git ssh key to %q: %w", provider, err)
+				}
+				cliui.Infof(inv.Stderr, "Uploaded the key to %s.", provider)
+			}
+
+			_, _ = fmt.Fprintln(inv.Stdout, strings.TrimSpace(key.PublicKey))
+			return nil
+		},
+	}
+
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag:        "type",
+			Description: "The type of key to generate. Defaults to the type configured for the deployment.",
+			Value:       clibase.EnumOf(&keyType, "ed25519", "ecdsa", "rsa4096"),
+		},
+		{
+			Flag:        "import",
+			Description: "Path to a private key to import instead of generating one. The key must not be protected by a passphrase.",
+			Value:       clibase.StringOf(&importPath),
+		},
+		{
+			Flag:        "upload",
+			Description: "The ID of an external auth provider to register the public key with. Can be specified multiple times.",
+			Value:       clibase.StringArrayOf(&uploadTo),
+		},
+		{
+			Flag:        "host",
+			Description: "A pattern of the hosts to offer the key to, such as \"*.github.com\". Patterns prefixed with \"!\" exclude hosts. The key is offered to every host if none are specified. Can be specified multiple times.",
+			Value:       clibase.StringArrayOf(&hosts),
+		},
+	}
+	return cmd
+}
+
+// gitSSHKeyListRow is the type provided to the OutputFormatter.
+type gitSSHKeyListRow struct {
+	// For JSON format:
+	codersdk.GitSSHKey `table:"-"`
+
+	// For table format:
+	Name      string    `json:"-" table:"name,default_sort"`
+	KeyType   string    `json:"-" table:"type"`
+	Imported  bool      `json:"-" table:"imported"`
+	Hosts     string    `json:"-" table:"hosts"`
+	CreatedAt time.Time `json:"-" table:"created at"`
+	UpdatedAt time.Time `json:"-" table:"updated at"`
+}
+
+func (r *RootCmd) listGitSSHKeys() *clibase.Cmd {
+	formatter := cliui.NewOutputFormatter(
+		cliui.TableFormat([]gitSSHKeyListRow{}, []string{"name", "type", "imported", "hosts", "created at"}),
+		cliui.JSONFormat(),
+	)
+
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List keys",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(0),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			keys, err := client.GitSSHKeys(inv.Context(), codersdk.Me)
+			if err != nil {
+				return xerrors.Errorf("list git ssh keys: %w", err)
+			}
+
+			rows := make([]gitSSHKeyListRow, 0, len(keys))
+			for _, key := range keys {
+				rows = append(rows, gitSSHKeyListRow{
+					GitSSHKey: key,
+					Name:      key.Name,
+					KeyType:   key.KeyType,
+					Imported:  key.Imported,
+					Hosts:     strings.Join(key.Hosts, ","),
+					CreatedAt: key.CreatedAt,
+					UpdatedAt: key.UpdatedAt,
+				})
+			}
+
+			out, err := formatter.Format(inv.Context(), rows)
+			if err != nil {
+				return err
+			}
+
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+func (r *RootCmd) regenerateGitSSHKey() *clibase.Cmd {
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "regenerate <name>",
+		Short: "Replace a key with a newly generated one",
+		Long: "The new public key must be registered with Git providers again. Imported keys\n" +
+			"are replaced with a generated key of the type configured for the deployment.",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			_, err := cliui.Prompt(inv, cliui.PromptOptions{
+				Text:      fmt.Sprintf("Confirm regenerate the key %q? This action cannot be reverted.", inv.Args[0]),
+				IsConfirm: true,
+			})
+			if err != nil {
+				return err
+			}
+
+			key, err := client.RegenerateNamedGitSSHKey(inv.Context(), codersdk.Me, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("regenerate git ssh key: %w", err)
+			}
+
+			_, _ = fmt.Fprintln(inv.Stdout, strings.TrimSpace(key.PublicKey))
+			return nil
+		},
+	}
+	cmd.Options = clibase.OptionSet{
+		cliui.SkipPromptOption(),
+	}
+	return cmd
+}
+
+func (r *RootCmd) removeGitSSHKey() *clibase.Cmd {
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:     "remove <name>",
+		Aliases: []string{"delete", "rm"},
+		Short:   "Delete a key",
+		Long:    "The key is not removed from Git providers it was registered with.",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			err := client.DeleteGitSSHKey(inv.Context(), codersdk.Me, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("delete git ssh key: %w", err)
+			}
+
+			cliui.Infof(inv.Stdout, "Key %q has been deleted.", inv.Args[0])
+			return nil
+		},
+	}
+	return cmd
+}
+
+func (r *RootCmd) uploadGitSSHKey() *clibase.Cmd {
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "upload <name> <provider>",
+		Short: "Register the public key with a Git provider",
+		Long: "The key is registered using your token for the external auth provider, so\n" +
+			"you must have authenticated with it. Only GitHub and GitLab are supported.",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(2),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			err := client.UploadGitSSHKey(inv.Context(), codersdk.Me, inv.Args[0], codersdk.UploadGitSSHKeyRequest{
+				ExternalAuthProviderID: inv.Args[1],
+			})
+			if err != nil {
+				return xerrors.Errorf("upload git ssh key: %w", err)
+			}
+
+			cliui.Infof(inv.Stdout, "Key %q has been uploaded to %s.", inv.Args[0], inv.Args[1])
+			return nil
+		},
+	}
+	return cmd
+}
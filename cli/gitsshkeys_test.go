@@ -0,0 +1,95 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/gitsshkey"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestGitSSHKeys(t *testing.T) {
+	t.Parallel()
+	client := coderdtest.New(t, nil)
+	_ = coderdtest.CreateFirstUser(t, client)
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	inv, root := clitest.New(t, "gitsshkeys", "create", "work", "--type", "ecdsa", "--host", "*.example.com")
+	clitest.SetupConfig(t, client, root)
+	buf := new(bytes.Buffer)
+	inv.Stdout = buf
+	err := inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(buf.String(), "ecdsa-sha2-nistp256 "))
+
+	privateKey, publicKey, err := gitsshkey.Generate(gitsshkey.AlgorithmEd25519)
+	require.NoError(t, err)
+	keyPath := filepath.Join(t.TempDir(), "id_ed25519")
+	err = os.WriteFile(keyPath, []byte(privateKey), 0o600)
+	require.NoError(t, err)
+
+	inv, root = clitest.New(t, "gitsshkeys", "create", "laptop", "--import", keyPath)
+	clitest.SetupConfig(t, client, root)
+	buf = new(bytes.Buffer)
+	inv.Stdout = buf
+	err = inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+	require.Equal(t, publicKey, buf.String())
+
+	inv, root = clitest.New(t, "gitsshkeys", "ls")
+	clitest.SetupConfig(t, client, root)
+	buf = new(bytes.Buffer)
+	inv.Stdout = buf
+	err = inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), "IMPORTED")
+	require.Contains(t, buf.String(), "laptop")
+	require.Contains(t, buf.String(), "work")
+	require.Contains(t, buf.String(), "*.example.com")
+
+	inv, root = clitest.New(t, "gitsshkeys", "rm", "work")
+	clitest.SetupConfig(t, client, root)
+	err = inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+
+	inv, root = clitest.New(t, "gitsshkeys", "ls", "--output=json")
+	clitest.SetupConfig(t, client, root)
+	buf = new(bytes.Buffer)
+	inv.Stdout = buf
+	err = inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+	var keys []codersdk.GitSSHKey
+	err = json.Unmarshal(buf.Bytes(), &keys)
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+	require.Equal(t, gitsshkey.DefaultName, keys[0].Name)
+	require.Equal(t, "laptop", keys[1].Name)
+	require.True(t, keys[1].Imported)
+
+	// Regenerating an imported key replaces it with a generated one.
+	inv, root = clitest.New(t, "gitsshkeys", "regenerate", "laptop", "--yes")
+	clitest.SetupConfig(t, client, root)
+	buf = new(bytes.Buffer)
+	inv.Stdout = buf
+	err = inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+	require.NotEqual(t, strings.TrimSpace(publicKey), strings.TrimSpace(buf.String()))
+	keys, err = client.GitSSHKeys(ctx, codersdk.Me)
+	require.NoError(t, err)
+	require.False(t, keys[1].Imported)
+
+	// The default key can only be regenerated.
+	inv, root = clitest.New(t, "gitsshkeys", "rm", gitsshkey.DefaultName)
+	clitest.SetupConfig(t, client, root)
+	err = inv.WithContext(ctx).Run()
+	require.Error(t, err)
+}
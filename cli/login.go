@@ -90,17 +90,46 @@ func (r *RootCmd) loginWithPassword(
 	client *codersdk.Client,
 	email, password string,
 ) error {
-	resp, err := client.LoginWithPassword(inv.Context(), codersdk.LoginWithPasswordRequest{
+	req := codersdk.LoginWithPasswordRequest{
 		Email:    email,
 		Password: password,
-	})
+	}
+	resp, err := client.LoginWithPassword(inv.Context(), req)
+	switch {
+	case codersdk.IsMFAEnrollmentRequired(err):
+		resp, err = enrollTOTPWithPassword(inv, client, req)
+	case codersdk.IsMFACodeRequired(err):
+		resp, err = loginWithMFACode(inv, client, req)
+	}
 	if err != nil {
 		return xerrors.Errorf("login with password: %w", err)
 	}
+	if len(resp.MFARecoveryCodes) > 0 {
+		printMFARecoveryCodes(inv, resp.MFARecoveryCodes)
+	}
+	return r.writeSession(inv, client, resp.SessionToken)
+}
 
-	sessionToken := resp.SessionToken
+func (r *RootCmd) loginWithLDAP(
+	inv *clibase.Invocation,
+	client *codersdk.Client,
+	username, password string,
+) error {
+	resp, err := client.LoginWithLDAP(inv.Context(), codersdk.LoginWithLDAPRequest{
+		Username: username,
+		Password: password,
+	})
+	if err != nil {
+		return xerrors.Errorf("login with ldap: %w", err)
+	}
+	return r.writeSession(inv, client, resp.SessionToken)
+}
+
+// writeSession stores a session token returned by a login and greets the
+// user it belongs to.
+func (r *RootCmd) writeSession(inv *clibase.Invocation, client *codersdk.Client, sessionToken string) error {
 	config := r.createConfig()
-	err = config.Session().Write(sessionToken)
+	err := config.Session().Write(sessionToken)
 	if err != nil {
 		return xerrors.Errorf("write session token: %w", err)
 	}
@@ -126,6 +155,41 @@ func (r *RootCmd) loginWithPassword(
 	return nil
 }
 
+// loginWithMFACode prompts for a code from the authenticator of the user
+// until the login succeeds, or a few attempts failed.
+func loginWithMFACode(inv *clibase.Invocation, client *codersdk.Client, req codersdk.LoginWithPasswordRequest) (codersdk.LoginWithPasswordResponse, error) {
+	const attempts = 3
+	var err error
+	for i := 0; i < attempts; i++ {
+		req.MFACode, err = cliui.Prompt(inv, cliui.PromptOptions{
+			Text:     "Enter a code from your " + cliui.DefaultStyles.Field.Render("authenticator app") + ", or a recovery code:",
+			Validate: cliui.ValidateNotEmpty,
+		})
+		if err != nil {
+			return codersdk.LoginWithPasswordResponse{}, xerrors.Errorf("mfa code prompt: %w", err)
+		}
+		var resp codersdk.LoginWithPasswordResponse
+		resp, err = client.LoginWithPassword(inv.Context(), req)
+		if !codersdk.IsMFACodeRequired(err) {
+			return resp, err
+		}
+		_, _ = fmt.Fprintln(inv.Stdout, cliui.DefaultStyles.Error.Render("Invalid code, try again."))
+	}
+	return codersdk.LoginWithPasswordResponse{}, err
+}
+
+// enrollTOTPWithPassword enrolls an authenticator for a user that must use
+// MFA but has not enrolled yet, and completes the login with a code from it.
+func enrollTOTPWithPassword(inv *clibase.Invocation, client *codersdk.Client, req codersdk.LoginWithPasswordRequest) (codersdk.LoginWithPasswordResponse, error) {
+	enrollment, err := client.EnrollTOTPWithPassword(inv.Context(), req)
+	if err != nil {
+		return codersdk.LoginWithPasswordResponse{}, xerrors.Errorf("enroll authenticator: %w", err)
+	}
+	_, _ = fmt.Fprintln(inv.Stdout, cliui.DefaultStyles.Paragraph.Render("Your deployment requires multi-factor authentication."))
+	printTOTPEnrollment(inv, enrollment)
+	return loginWithMFACode(inv, client, req)
+}
+
 func (r *RootCmd) login() *clibase.Cmd {
 	const firstUserTrialEnv = "CODER_FIRST_USER_TRIAL"
 
@@ -135,6 +199,8 @@ func (r *RootCmd) login() *clibase.Cmd {
 		password           string
 		trial              bool
 		useTokenForSession bool
+		usePassword        bool
+		useLDAP            bool
 	)
 	cmd := &clibase.Cmd{
 		Use:        "login <url>",
@@ -266,6 +332,60 @@ func (r *RootCmd) login() *clibase.Cmd {
 			}
 
 			sessionToken, _ := inv.ParsedFlags().GetString(varToken)
+			if sessionToken == "" && usePassword {
+				loginEmail, err := cliui.Prompt(inv, cliui.PromptOptions{
+					Text:     "What's your " + cliui.DefaultStyles.Field.Render("email") + "?",
+					Validate: cliui.ValidateNotEmpty,
+				})
+				if err != nil {
+					return xerrors.Errorf("email prompt: %w", err)
+				}
+				loginPassword, err := cliui.Prompt(inv, cliui.PromptOptions{
+					Text:     "Enter your " + cliui.DefaultStyles.Field.Render("password") + ":",
+					Secret:   true,
+					Validate: cliui.ValidateNotEmpty,
+				})
+				if err != nil {
+					return xerrors.Errorf("password prompt: %w", err)
+				}
+
+				err = r.loginWithPassword(inv, client, loginEmail, loginPassword)
+				if err != nil {
+					return err
+				}
+				err = r.createConfig().URL().Write(serverURL.String())
+				if err != nil {
+					return xerrors.Errorf("write server url: %w", err)
+				}
+				return nil
+			}
+			if sessionToken == "" && useLDAP {
+				loginUsername, err := cliui.Prompt(inv, cliui.PromptOptions{
+					Text:     "What's your " + cliui.DefaultStyles.Field.Render("LDAP username") + "?",
+					Validate: cliui.ValidateNotEmpty,
+				})
+				if err != nil {
+					return xerrors.Errorf("username prompt: %w", err)
+				}
+				loginPassword, err := cliui.Prompt(inv, cliui.PromptOptions{
+					Text:     "Enter your " + cliui.DefaultStyles.Field.Render("LDAP password") + ":",
+					Secret:   true,
+					Validate: cliui.ValidateNotEmpty,
+				})
+				if err != nil {
+					return xerrors.Errorf("password prompt: %w", err)
+				}
+
+				err = r.loginWithLDAP(inv, client, loginUsername, loginPassword)
+				if err != nil {
+					return err
+				}
+				err = r.createConfig().URL().Write(serverURL.String())
+				if err != nil {
+					return xerrors.Errorf("write server url: %w", err)
+				}
+				return nil
+			}
 			if sessionToken == "" {
 				authURL := *serverURL
 				// Don't use filepath.Join, we don't want to use the os separator
@@ -356,6 +476,16 @@ func (r *RootCmd) login() *clibase.Cmd {
 			Description: "Specifies whether a trial license should be provisioned for the Coder deployment or not.",
 			Value:       clibase.BoolOf(&trial),
 		},
+		{
+			Flag:        "use-password",
+			Description: "Log in with an email and password instead of opening the browser. Prompts for a code if the account uses multi-factor authentication.",
+			Value:       clibase.BoolOf(&usePassword),
+		},
+		{
+			Flag:        "use-ldap",
+			Description: "Log in with the username and password of your account in the deployment's LDAP directory instead of opening the browser.",
+			Value:       clibase.BoolOf(&useLDAP),
+		},
 		{
 			Flag:        "use-token-as-session",
 			Description: "By default, the CLI will generate a new session token when logging in. This flag will instead use the provided token as the session token.",
@@ -4,13 +4,19 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/coder/coder/v2/cli/clitest"
 	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/coderd"
 	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/ldap"
+	"github.com/coder/coder/v2/coderd/ldap/ldaptest"
+	"github.com/coder/coder/v2/coderd/totp"
+	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/pty/ptytest"
 )
 
@@ -196,6 +202,102 @@ func TestLogin(t *testing.T) {
 		<-doneChan
 	})
 
+	t.Run("PasswordMFA", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		coderdtest.CreateFirstUser(t, client)
+
+		ctx := context.Background()
+		enrollment, err := client.EnrollUserTOTP(ctx, codersdk.Me)
+		require.NoError(t, err)
+		code, err := totp.Code(enrollment.Secret, totp.Step(time.Now()))
+		require.NoError(t, err)
+		_, err = client.VerifyUserTOTP(ctx, codersdk.Me, codersdk.VerifyTOTPRequest{Code: code})
+		require.NoError(t, err)
+
+		doneChan := make(chan struct{})
+		root, _ := clitest.New(t, "login", "--force-tty", "--use-password", client.URL.String())
+		pty := ptytest.New(t).Attach(root)
+		go func() {
+			defer close(doneChan)
+			err := root.Run()
+			assert.NoError(t, err)
+		}()
+
+		// The code used to verify the enrollment cannot be used again.
+		code, err = totp.Code(enrollment.Secret, totp.Step(time.Now())+1)
+		require.NoError(t, err)
+		pty.ExpectMatch("email")
+		pty.WriteLine(coderdtest.FirstUserParams.Email)
+		pty.ExpectMatch("password")
+		pty.WriteLine(coderdtest.FirstUserParams.Password)
+		pty.ExpectMatch("authenticator app")
+		pty.WriteLine("000000")
+		pty.ExpectMatch("Invalid code")
+		pty.ExpectMatch("authenticator app")
+		pty.WriteLine(code)
+		pty.ExpectMatch("Welcome to Coder")
+		<-doneChan
+	})
+
+	t.Run("LDAP", func(t *testing.T) {
+		t.Parallel()
+
+		const (
+			bindDN  = "CN=coder,OU=Services,DC=example,DC=com"
+			aliceDN = "CN=Alice,OU=Users,DC=example,DC=com"
+		)
+		srv := ldaptest.New(t, nil)
+		srv.AddEntry(bindDN, "objectClass", "person")
+		srv.SetPassword(bindDN, "service-password")
+		srv.AddEntry(aliceDN,
+			"objectClass", "person",
+			"sAMAccountName", "alice",
+			"mail", "alice@example.com",
+		)
+		srv.SetPassword(aliceDN, "alice-password")
+		client := coderdtest.New(t, &coderdtest.Options{
+			LDAPConfig: &coderd.LDAPConfig{
+				Config: ldap.Config{
+					URL:               srv.URL,
+					StartTLS:          true,
+					TLSConfig:         srv.TLSConfig,
+					BindDN:            bindDN,
+					BindPassword:      "service-password",
+					BaseDN:            "DC=example,DC=com",
+					UserFilter:        "(objectClass=person)",
+					UsernameAttribute: "sAMAccountName",
+					EmailAttribute:    "mail",
+				},
+				AllowSignups: true,
+			},
+		})
+		coderdtest.CreateFirstUser(t, client)
+
+		doneChan := make(chan struct{})
+		root, cfg := clitest.New(t, "login", "--force-tty", "--use-ldap", client.URL.String())
+		pty := ptytest.New(t).Attach(root)
+		go func() {
+			defer close(doneChan)
+			err := root.Run()
+			assert.NoError(t, err)
+		}()
+
+		pty.ExpectMatch("LDAP username")
+		pty.WriteLine("alice")
+		pty.ExpectMatch("LDAP password")
+		pty.WriteLine("alice-password")
+		pty.ExpectMatch("Welcome to Coder")
+		<-doneChan
+
+		sessionFile, err := cfg.Session().Read()
+		require.NoError(t, err)
+		client.SetSessionToken(sessionFile)
+		user, err := client.User(context.Background(), codersdk.Me)
+		require.NoError(t, err)
+		require.Equal(t, "alice@example.com", user.Email)
+	})
+
 	// TokenFlag should generate a new session token and store it in the session file.
 	t.Run("TokenFlag", func(t *testing.T) {
 		t.Parallel()
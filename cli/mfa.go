@@ -0,0 +1,218 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/clibase"
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+func (r *RootCmd) mfa() *clibase.Cmd {
+	cmd := &clibase.Cmd{
+		Use:   "mfa",
+		Short: "Manage multi-factor authentication",
+		Long: "Users that log in with a password can add an authenticator app as a second\n" +
+			"factor.\n" + formatExamples(
+			example{
+				Description: "Enroll an authenticator app",
+				Command:     "coder mfa enroll",
+			},
+			example{
+				Description: "Remove your authenticator with a code from it",
+				Command:     "coder mfa reset",
+			},
+			example{
+				Description: "Reset the authenticator of a user that lost it",
+				Command:     "coder mfa reset alice",
+			},
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*clibase.Cmd{
+			r.enrollMFA(),
+			r.resetMFA(),
+			r.mfaStatus(),
+		},
+	}
+	return cmd
+}
+
+func (r *RootCmd) enrollMFA() *clibase.Cmd {
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "enroll",
+		Short: "Enroll an authenticator app",
+		Long:  "Enabling the authenticator logs out your other sessions.",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(0),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			enrollment, err := client.EnrollUserTOTP(inv.Context(), codersdk.Me)
+			if err != nil {
+				return xerrors.Errorf("enroll authenticator: %w", err)
+			}
+			printTOTPEnrollment(inv, enrollment)
+
+			var codes codersdk.MFARecoveryCodes
+			_, err = cliui.Prompt(inv, cliui.PromptOptions{
+				Text: "Enter a code from your " + cliui.DefaultStyles.Field.Render("authenticator app") + ":",
+				Validate: func(code string) error {
+					codes, err = client.VerifyUserTOTP(inv.Context(), codersdk.Me, codersdk.VerifyTOTPRequest{
+						Code: code,
+					})
+					if err != nil {
+						return xerrors.Errorf("Invalid code, try again: %w", err)
+					}
+					return nil
+				},
+			})
+			if err != nil {
+				return xerrors.Errorf("code prompt: %w", err)
+			}
+
+			printMFARecoveryCodes(inv, codes.RecoveryCodes)
+			cliui.Infof(inv.Stdout, "Multi-factor authentication is enabled.")
+			return nil
+		},
+	}
+	return cmd
+}
+
+func (r *RootCmd) resetMFA() *clibase.Cmd {
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "reset [user]",
+		Short: "Remove an authenticator and its recovery codes",
+		Long: "Removing your own authenticator requires a code from it or a recovery code.\n" +
+			"Owners and user admins can reset the authenticator of other users without one.",
+		Middleware: clibase.Chain(
+			clibase.RequireRangeArgs(0, 1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			user := codersdk.Me
+			if len(inv.Args) > 0 {
+				user = inv.Args[0]
+			}
+
+			var req codersdk.ResetUserMFARequest
+			if user == codersdk.Me {
+				code, err := cliui.Prompt(inv, cliui.PromptOptions{
+					Text:     "Enter a code from your " + cliui.DefaultStyles.Field.Render("authenticator app") + ", or a recovery code:",
+					Secret:   true,
+					Validate: cliui.ValidateNotEmpty,
+				})
+				if err != nil {
+					return xerrors.Errorf("code prompt: %w", err)
+				}
+				req.Code = code
+			} else {
+				_, err := cliui.Prompt(inv, cliui.PromptOptions{
+					Text:      fmt.Sprintf("Reset the authenticator of %s? They will log in with their password alone.", cliui.DefaultStyles.Keyword.Render(user)),
+					IsConfirm: true,
+					Default:   cliui.ConfirmNo,
+				})
+				if err != nil {
+					return err
+				}
+			}
+
+			err := client.ResetUserMFA(inv.Context(), user, req)
+			if err != nil {
+				return xerrors.Errorf("reset mfa: %w", err)
+			}
+			cliui.Infof(inv.Stdout, "Multi-factor authentication has been reset.")
+			return nil
+		},
+	}
+	cmd.Options = clibase.OptionSet{
+		cliui.SkipPromptOption(),
+	}
+	return cmd
+}
+
+// mfaStatusRow is the type provided to the OutputFormatter.
+type mfaStatusRow struct {
+	// For JSON format:
+	codersdk.UserMFA `table:"-"`
+
+	// For table format:
+	Enabled                bool       `json:"-" table:"enabled,default_sort"`
+	EnabledAt              *time.Time `json:"-" table:"enabled at"`
+	Required               bool       `json:"-" table:"required"`
+	RecoveryCodesRemaining int        `json:"-" table:"recovery codes remaining"`
+}
+
+func (r *RootCmd) mfaStatus() *clibase.Cmd {
+	formatter := cliui.NewOutputFormatter(
+		cliui.TableFormat([]mfaStatusRow{}, []string{"enabled", "enabled at", "required", "recovery codes remaining"}),
+		cliui.JSONFormat(),
+	)
+
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "status [user]",
+		Short: "Show whether multi-factor authentication is enabled",
+		Middleware: clibase.Chain(
+			clibase.RequireRangeArgs(0, 1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			user := codersdk.Me
+			if len(inv.Args) > 0 {
+				user = inv.Args[0]
+			}
+
+			status, err := client.UserMFA(inv.Context(), user)
+			if err != nil {
+				return xerrors.Errorf("get mfa status: %w", err)
+			}
+
+			out, err := formatter.Format(inv.Context(), []mfaStatusRow{{
+				UserMFA:                status,
+				Enabled:                status.Enabled,
+				EnabledAt:              status.EnabledAt,
+				Required:               status.Required,
+				RecoveryCodesRemaining: status.RecoveryCodesRemaining,
+			}})
+			if err != nil {
+				return err
+			}
+
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+// printTOTPEnrollment shows the secret of an enrollment for the user to add
+// to their authenticator app.
+func printTOTPEnrollment(inv *clibase.Invocation, enrollment codersdk.TOTPEnrollment) {
+	_, _ = fmt.Fprintf(inv.Stdout, "%s\n\n\t%s\n\n%s\n\n\t%s\n\n",
+		cliui.DefaultStyles.Paragraph.Render("Add your account to an authenticator app with this secret:"),
+		cliui.DefaultStyles.Code.Render(enrollment.Secret),
+		cliui.DefaultStyles.Paragraph.Render("Or with this URL, for example by converting it to a QR code:"),
+		enrollment.URL,
+	)
+}
+
+// printMFARecoveryCodes shows recovery codes, which are only returned when
+// they are created.
+func printMFARecoveryCodes(inv *clibase.Invocation, codes []string) {
+	_, _ = fmt.Fprintln(inv.Stdout, cliui.DefaultStyles.Paragraph.Render(
+		"Save these recovery codes somewhere safe. Each one can be used once instead of a code from your authenticator app, and they will not be shown again:",
+	))
+	for _, code := range codes {
+		_, _ = fmt.Fprintf(inv.Stdout, "\t%s\n", cliui.DefaultStyles.Code.Render(code))
+	}
+	_, _ = fmt.Fprintln(inv.Stdout)
+}
@@ -0,0 +1,85 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/totp"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/pty/ptytest"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestMFA(t *testing.T) {
+	t.Parallel()
+	client := coderdtest.New(t, nil)
+	_ = coderdtest.CreateFirstUser(t, client)
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	inv, root := clitest.New(t, "mfa", "enroll")
+	clitest.SetupConfig(t, client, root)
+	pty := ptytest.New(t).Attach(inv)
+	doneChan := make(chan struct{})
+	go func() {
+		defer close(doneChan)
+		err := inv.WithContext(ctx).Run()
+		assert.NoError(t, err)
+	}()
+
+	pty.ExpectMatch("with this secret:")
+	var secret string
+	for secret == "" {
+		secret = strings.TrimSpace(pty.ReadLine(ctx))
+	}
+	code, err := totp.Code(secret, totp.Step(time.Now()))
+	require.NoError(t, err)
+	pty.ExpectMatch("authenticator app")
+	pty.WriteLine("000000")
+	pty.ExpectMatch("Invalid code")
+	pty.ExpectMatch("authenticator app")
+	pty.WriteLine(code)
+	pty.ExpectMatch("recovery codes")
+	pty.ExpectMatch("Multi-factor authentication is enabled")
+	<-doneChan
+
+	inv, root = clitest.New(t, "mfa", "status", "-o", "json")
+	clitest.SetupConfig(t, client, root)
+	buf := new(bytes.Buffer)
+	inv.Stdout = buf
+	err = inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+	var status []codersdk.UserMFA
+	err = json.Unmarshal(buf.Bytes(), &status)
+	require.NoError(t, err)
+	require.Len(t, status, 1)
+	require.True(t, status[0].Enabled)
+
+	// The code used to verify the enrollment cannot be used again.
+	code, err = totp.Code(secret, totp.Step(time.Now())+1)
+	require.NoError(t, err)
+	inv, root = clitest.New(t, "mfa", "reset")
+	clitest.SetupConfig(t, client, root)
+	pty = ptytest.New(t).Attach(inv)
+	doneChan = make(chan struct{})
+	go func() {
+		defer close(doneChan)
+		err := inv.WithContext(ctx).Run()
+		assert.NoError(t, err)
+	}()
+	pty.ExpectMatch("authenticator app")
+	pty.WriteLine(code)
+	pty.ExpectMatch("Multi-factor authentication has been reset")
+	<-doneChan
+
+	mfa, err := client.UserMFA(ctx, codersdk.Me)
+	require.NoError(t, err)
+	require.False(t, mfa.Enabled)
+}
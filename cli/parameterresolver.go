@@ -7,6 +7,7 @@ import (
 
 	"github.com/coder/coder/v2/cli/clibase"
 	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/coderd/parameter"
 	"github.com/coder/coder/v2/codersdk"
 )
 
@@ -73,6 +74,9 @@ func (pr *ParameterResolver) Resolve(inv *clibase.Invocation, action WorkspaceCL
 	if staged, err = pr.resolveWithInput(staged, inv, action, templateVersionParameters); err != nil {
 		return nil, err
 	}
+	if err = pr.verifyValidationRules(staged, templateVersionParameters); err != nil {
+		return nil, err
+	}
 	return staged, nil
 }
 
@@ -183,6 +187,16 @@ func (pr *ParameterResolver) resolveWithInput(resolved []codersdk.WorkspaceBuild
 		}
 		// Parameter has not been resolved yet, so CLI needs to determine if user should input it.
 
+		// Visibility depends on the values entered so far, so it's evaluated
+		// for every parameter.
+		visible, err := pr.evaluator(resolved, templateVersionParameters).Visible(tvp)
+		if err != nil {
+			return nil, err
+		}
+		if !visible {
+			continue // the template doesn't use this parameter with the current values
+		}
+
 		firstTimeUse := pr.isFirstTimeUse(tvp.Name)
 		promptParameterOption := pr.isLastBuildParameterInvalidOption(tvp)
 
@@ -197,6 +211,10 @@ func (pr *ParameterResolver) resolveWithInput(resolved []codersdk.WorkspaceBuild
 			if err != nil {
 				return nil, err
 			}
+			err = pr.evaluator(resolved, templateVersionParameters).Validate(tvp, parameterValue)
+			if err != nil {
+				return nil, xerrors.Errorf("parameter %q is invalid: %w", tvp.Name, err)
+			}
 
 			resolved = append(resolved, codersdk.WorkspaceBuildParameter{
 				Name:  tvp.Name,
@@ -209,6 +227,49 @@ func (pr *ParameterResolver) resolveWithInput(resolved []codersdk.WorkspaceBuild
 	return resolved, nil
 }
 
+// verifyValidationRules checks the resolved values against the validation rules
+// of the template, so that the user doesn't have to wait for the build to
+// fail. Parameters hidden by the values of other parameters are not checked.
+func (pr *ParameterResolver) verifyValidationRules(resolved []codersdk.WorkspaceBuildParameter, templateVersionParameters []codersdk.TemplateVersionParameter) error {
+	evaluator := pr.evaluator(resolved, templateVersionParameters)
+	for _, r := range resolved {
+		tvp := findTemplateVersionParameter(r, templateVersionParameters)
+		if tvp == nil {
+			continue
+		}
+		visible, err := evaluator.Visible(*tvp)
+		if err != nil {
+			return err
+		}
+		if !visible {
+			continue
+		}
+		err = evaluator.Validate(*tvp, r.Value)
+		if err != nil {
+			return xerrors.Errorf("parameter %q is invalid: %w", r.Name, err)
+		}
+	}
+	return nil
+}
+
+// evaluator returns an evaluator for the values the build would use: resolved
+// values, then values from the last build, then defaults.
+func (pr *ParameterResolver) evaluator(resolved []codersdk.WorkspaceBuildParameter, templateVersionParameters []codersdk.TemplateVersionParameter) *parameter.Evaluator {
+	values := make(map[string]string, len(templateVersionParameters))
+	for _, tvp := range templateVersionParameters {
+		if tvp.Ephemeral {
+			continue
+		}
+		if p := findWorkspaceBuildParameter(tvp.Name, pr.lastBuildParameters); p != nil {
+			values[tvp.Name] = p.Value
+		}
+	}
+	for _, r := range resolved {
+		values[r.Name] = r.Value
+	}
+	return parameter.NewEvaluator(templateVersionParameters, values)
+}
+
 func (pr *ParameterResolver) isFirstTimeUse(parameterName string) bool {
 	return findWorkspaceBuildParameter(parameterName, pr.lastBuildParameters) == nil
 }
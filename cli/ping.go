@@ -52,9 +52,15 @@ func (r *RootCmd) ping() *clibase.Cmd {
 			if r.disableDirect {
 				_, _ = fmt.Fprintln(inv.Stderr, "Direct connections disabled.")
 			}
+			proxy, err := r.workspaceProxy(inv, client)
+			if err != nil {
+				return err
+			}
 			conn, err := client.DialWorkspaceAgent(ctx, workspaceAgent.ID, &codersdk.DialWorkspaceAgentOptions{
-				Logger:         logger,
-				BlockEndpoints: r.disableDirect,
+				Logger:            logger,
+				BlockEndpoints:    r.disableDirect,
+				WorkspaceProxy:    proxy.Name,
+				WorkspaceProxyURL: workspaceProxyURL(proxy),
 			})
 			if err != nil {
 				return err
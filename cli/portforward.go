@@ -13,6 +13,7 @@ import (
 	"syscall"
 
 	"github.com/pion/udp"
+	gossh "golang.org/x/crypto/ssh"
 	"golang.org/x/xerrors"
 
 	"cdr.dev/slog"
@@ -22,17 +23,21 @@ import (
 	"github.com/coder/coder/v2/cli/clibase"
 	"github.com/coder/coder/v2/cli/cliui"
 	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/vpn"
 )
 
 func (r *RootCmd) portForward() *clibase.Cmd {
 	var (
-		tcpForwards []string // <port>:<port>
-		udpForwards []string // <port>:<port>
+		tcpForwards    []string // <port>:<port>
+		udpForwards    []string // <port>:<port>
+		unixForwards   []string // <path>:<path>
+		remoteForwards []string // tcp:<port>:<address>:<port> or unix:<path>:<path>
+		socks5Address  string   // [<ip>:]<port>
 	)
 	client := new(codersdk.Client)
 	cmd := &clibase.Cmd{
 		Use:     "port-forward <workspace>",
-		Short:   `Forward ports from a workspace to the local machine. For reverse port forwarding, use "coder ssh -R".`,
+		Short:   "Forward ports from a workspace to the local machine. For reverse port forwarding, use --remote.",
 		Aliases: []string{"tunnel"},
 		Long: formatExamples(
 			example{
@@ -55,6 +60,18 @@ func (r *RootCmd) portForward() *clibase.Cmd {
 				Description: "Port forward specifying the local address to bind to",
 				Command:     "coder port-forward <workspace> --tcp 1.2.3.4:8080:8080",
 			},
+			example{
+				Description: "Port forward a unix socket in the workspace to a local unix socket",
+				Command:     "coder port-forward <workspace> --unix ./docker.sock:/var/run/docker.sock",
+			},
+			example{
+				Description: "Serve a SOCKS5 proxy on port 1080 that connects to hosts from inside the workspace",
+				Command:     "coder port-forward <workspace> --socks5 1080",
+			},
+			example{
+				Description: "Reverse port forward port 3000 on your local machine to port 8080 in the workspace",
+				Command:     "coder port-forward <workspace> --remote tcp:8080:localhost:3000",
+			},
 		),
 		Middleware: clibase.Chain(
 			clibase.RequireNArgs(1),
@@ -64,11 +81,25 @@ func (r *RootCmd) portForward() *clibase.Cmd {
 			ctx, cancel := context.WithCancel(inv.Context())
 			defer cancel()
 
-			specs, err := parsePortForwards(tcpForwards, udpForwards)
+			specs, err := parsePortForwards(tcpForwards, udpForwards, unixForwards)
 			if err != nil {
 				return xerrors.Errorf("parse port-forward specs: %w", err)
 			}
-			if len(specs) == 0 {
+			if socks5Address != "" {
+				socks5Address, err = parseSOCKS5Address(socks5Address)
+				if err != nil {
+					return xerrors.Errorf("parse --socks5: %w", err)
+				}
+			}
+			remoteSpecs := make([]remoteForwardSpec, 0, len(remoteForwards))
+			for _, spec := range remoteForwards {
+				local, remote, err := parsePortForwardRemote(spec)
+				if err != nil {
+					return xerrors.Errorf("parse remote port-forward spec: %w", err)
+				}
+				remoteSpecs = append(remoteSpecs, remoteForwardSpec{local: local, remote: remote})
+			}
+			if len(specs) == 0 && len(remoteSpecs) == 0 && socks5Address == "" {
 				err = inv.Command.HelpHandler(inv)
 				if err != nil {
 					return xerrors.Errorf("generate help output: %w", err)
@@ -103,17 +134,51 @@ func (r *RootCmd) portForward() *clibase.Cmd {
 				logger = slog.Make(sloghuman.Sink(inv.Stdout)).Leveled(slog.LevelDebug)
 			}
 
-			if r.disableDirect {
-				_, _ = fmt.Fprintln(inv.Stderr, "Direct connections disabled.")
+			// Reuse the connection of coder vpn if it's running, which only
+			// tunnels TCP.
+			var (
+				conn      agentDialer
+				agentConn *codersdk.WorkspaceAgentConn
+				ok        bool
+			)
+			if len(udpForwards) == 0 {
+				conn, ok = r.vpnDialer(ctx, client, workspaceAgent.ID)
 			}
-			conn, err := client.DialWorkspaceAgent(ctx, workspaceAgent.ID, &codersdk.DialWorkspaceAgentOptions{
-				Logger:         logger,
-				BlockEndpoints: r.disableDirect,
-			})
-			if err != nil {
-				return err
+			if ok {
+				logger.Debug(ctx, "connecting through coder vpn")
+			} else {
+				if r.disableDirect {
+					_, _ = fmt.Fprintln(inv.Stderr, "Direct connections disabled.")
+				}
+				proxy, err := r.workspaceProxy(inv, client)
+				if err != nil {
+					return err
+				}
+				agentConn, err = client.DialWorkspaceAgent(ctx, workspaceAgent.ID, &codersdk.DialWorkspaceAgentOptions{
+					Logger:            logger,
+					BlockEndpoints:    r.disableDirect,
+					WorkspaceProxy:    proxy.Name,
+					WorkspaceProxyURL: workspaceProxyURL(proxy),
+				})
+				if err != nil {
+					return err
+				}
+				defer agentConn.Close()
+				conn = agentConn
+			}
+
+			// Unix sockets and hosts other than the agent are dialed from
+			// inside the workspace over SSH, and the agent listens for reverse
+			// port-forwards through it.
+			var sshClient *gossh.Client
+			if socks5Address != "" || len(unixForwards) > 0 || len(remoteSpecs) > 0 {
+				sshClient, err = agentSSHClient(ctx, conn)
+				if err != nil {
+					return xerrors.Errorf("ssh client: %w", err)
+				}
+				defer sshClient.Close()
+				conn = &workspaceDialer{agentDialer: conn, sshClient: sshClient}
 			}
-			defer conn.Close()
 
 			// Start all listeners.
 			var (
@@ -137,6 +202,21 @@ func (r *RootCmd) portForward() *clibase.Cmd {
 				}
 				listeners[i] = l
 			}
+			if socks5Address != "" {
+				l, err := serveSOCKS5(ctx, inv, logger, sshClient, wg, socks5Address)
+				if err != nil {
+					return err
+				}
+				listeners = append(listeners, l)
+			}
+			for _, spec := range remoteSpecs {
+				_, _ = fmt.Fprintf(inv.Stderr, "Forwarding '%v://%v' in the workspace to '%v://%v' locally\n", spec.remote.Network(), spec.remote.String(), spec.local.Network(), spec.local.String())
+				closer, err := sshRemoteForward(ctx, inv.Stderr, sshClient, spec.local, spec.remote)
+				if err != nil {
+					return err
+				}
+				defer closer.Close()
+			}
 
 			// Wait for the context to be canceled or for a signal and close
 			// all listeners.
@@ -159,7 +239,9 @@ func (r *RootCmd) portForward() *clibase.Cmd {
 				closeAllListeners()
 			}()
 
-			conn.AwaitReachable(ctx)
+			if agentConn != nil {
+				agentConn.AwaitReachable(ctx)
+			}
 			_, _ = fmt.Fprintln(inv.Stderr, "Ready!")
 			wg.Wait()
 			return closeErr
@@ -180,12 +262,30 @@ func (r *RootCmd) portForward() *clibase.Cmd {
 			Description: "Forward UDP port(s) from the workspace to the local machine. The UDP connection has TCP-like semantics to support stateful UDP protocols.",
 			Value:       clibase.StringArrayOf(&udpForwards),
 		},
+		{
+			Flag:        "unix",
+			Env:         "CODER_PORT_FORWARD_UNIX",
+			Description: "Forward unix socket(s) from the workspace to the local machine, in the form <local-path>:<remote-path>.",
+			Value:       clibase.StringArrayOf(&unixForwards),
+		},
+		{
+			Flag:        "remote",
+			Env:         "CODER_PORT_FORWARD_REMOTE",
+			Description: "Forward port(s) or unix socket(s) from the local machine to the workspace, in the form tcp:<remote-port>:<local-address>:<local-port> or unix:<remote-path>:<local-path>.",
+			Value:       clibase.StringArrayOf(&remoteForwards),
+		},
+		{
+			Flag:        "socks5",
+			Env:         "CODER_PORT_FORWARD_SOCKS5",
+			Description: "Serve a SOCKS5 proxy on the local address [<ip>:]<port> that connects to any host:port from inside the workspace. HTTP CONNECT requests are accepted as well.",
+			Value:       clibase.StringOf(&socks5Address),
+		},
 	}
 
 	return cmd
 }
 
-func listenAndPortForward(ctx context.Context, inv *clibase.Invocation, conn *codersdk.WorkspaceAgentConn, wg *sync.WaitGroup, spec portForwardSpec) (net.Listener, error) {
+func listenAndPortForward(ctx context.Context, inv *clibase.Invocation, conn agentDialer, wg *sync.WaitGroup, spec portForwardSpec) (net.Listener, error) {
 	_, _ = fmt.Fprintf(inv.Stderr, "Forwarding '%v://%v' locally to '%v://%v' in the workspace\n", spec.listenNetwork, spec.listenAddress, spec.dialNetwork, spec.dialAddress)
 
 	var (
@@ -193,7 +293,7 @@ func listenAndPortForward(ctx context.Context, inv *clibase.Invocation, conn *co
 		err error
 	)
 	switch spec.listenNetwork {
-	case "tcp":
+	case "tcp", "unix":
 		l, err = net.Listen(spec.listenNetwork, spec.listenAddress)
 	case "udp":
 		var host, port string
@@ -259,7 +359,48 @@ type portForwardSpec struct {
 	dialAddress string // <ip>:<port> or path
 }
 
-func parsePortForwards(tcpSpecs, udpSpecs []string) ([]portForwardSpec, error) {
+type remoteForwardSpec struct {
+	local, remote net.Addr
+}
+
+// workspaceDialer dials unix sockets in the workspace over SSH, since agents
+// only expose TCP and UDP ports over tailnet.
+type workspaceDialer struct {
+	agentDialer
+	sshClient *gossh.Client
+}
+
+func (d *workspaceDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	if network == "unix" {
+		return d.sshClient.Dial(network, addr)
+	}
+	return d.agentDialer.DialContext(ctx, network, addr)
+}
+
+// serveSOCKS5 serves a proxy on address that dials hosts from inside the
+// workspace over SSH.
+func serveSOCKS5(ctx context.Context, inv *clibase.Invocation, logger slog.Logger, sshClient *gossh.Client, wg *sync.WaitGroup, address string) (net.Listener, error) {
+	_, _ = fmt.Fprintf(inv.Stderr, "Serving a SOCKS5 proxy to the workspace network on 'tcp://%v'\n", address)
+
+	l, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, xerrors.Errorf("listen 'tcp://%v': %w", address, err)
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		// Like "ssh -D", the proxy is unauthenticated.
+		err := vpn.ServeProxy(ctx, logger, l, func(_ context.Context, host string, port uint16) (net.Conn, error) {
+			return sshClient.Dial("tcp", net.JoinHostPort(host, strconv.Itoa(int(port))))
+		})
+		if err != nil {
+			_, _ = fmt.Fprintf(inv.Stderr, "Error serving SOCKS5 proxy on 'tcp://%v': %v\n", address, err)
+		}
+	}()
+	return l, nil
+}
+
+func parsePortForwards(tcpSpecs, udpSpecs, unixSpecs []string) ([]portForwardSpec, error) {
 	specs := []portForwardSpec{}
 
 	for _, specEntry := range tcpSpecs {
@@ -298,6 +439,19 @@ func parsePortForwards(tcpSpecs, udpSpecs []string) ([]portForwardSpec, error) {
 		}
 	}
 
+	for _, spec := range unixSpecs {
+		local, remote, err := parseUnixForward(spec)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to parse unix socket port-forward specification %q: %w", spec, err)
+		}
+		specs = append(specs, portForwardSpec{
+			listenNetwork: "unix",
+			listenAddress: local,
+			dialNetwork:   "unix",
+			dialAddress:   remote,
+		})
+	}
+
 	// Check for duplicate entries.
 	locals := map[string]struct{}{}
 	for _, spec := range specs {
@@ -311,6 +465,37 @@ func parsePortForwards(tcpSpecs, udpSpecs []string) ([]portForwardSpec, error) {
 	return specs, nil
 }
 
+// parseUnixForward parses <local-path>:<remote-path>. The last colon separates
+// the paths, so local paths with a drive letter work on Windows.
+func parseUnixForward(in string) (local string, remote string, err error) {
+	i := strings.LastIndex(in, ":")
+	if i <= 0 || i == len(in)-1 {
+		return "", "", xerrors.Errorf("invalid unix socket specification %q, expected <local-path>:<remote-path>", in)
+	}
+	return in[:i], in[i+1:], nil
+}
+
+// parseSOCKS5Address parses [<ip>:]<port>. The proxy listens on 127.0.0.1 if
+// no IP is given, since it's unauthenticated.
+func parseSOCKS5Address(in string) (string, error) {
+	host, rawPort, err := net.SplitHostPort(in)
+	if err != nil {
+		host, rawPort = "", in
+	}
+	port, err := parsePort(rawPort)
+	if err != nil {
+		return "", err
+	}
+	addr := netip.AddrFrom4([4]byte{127, 0, 0, 1})
+	if host != "" {
+		addr, err = netip.ParseAddr(host)
+		if err != nil {
+			return "", xerrors.Errorf("invalid ip %q: %w", host, err)
+		}
+	}
+	return netip.AddrPortFrom(addr, port).String(), nil
+}
+
 func parsePort(in string) (uint16, error) {
 	port, err := strconv.ParseUint(strings.TrimSpace(in), 10, 16)
 	if err != nil {
@@ -19,8 +19,9 @@ func Test_parsePortForwards(t *testing.T) {
 		return out
 	}
 	type args struct {
-		tcpSpecs []string
-		udpSpecs []string
+		tcpSpecs  []string
+		udpSpecs  []string
+		unixSpecs []string
 	}
 	tests := []struct {
 		name    string
@@ -72,13 +73,37 @@ func Test_parsePortForwards(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Unix sockets",
+			args: args{
+				unixSpecs: []string{"./docker.sock:/var/run/docker.sock", `C:\Users\me\app.sock:/tmp/app.sock`},
+			},
+			want: []string{
+				"./docker.sock:/var/run/docker.sock",
+				`C:\Users\me\app.sock:/tmp/app.sock`,
+			},
+		},
+		{
+			name: "Unix socket without remote path",
+			args: args{
+				unixSpecs: []string{"./docker.sock"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Duplicate unix socket",
+			args: args{
+				unixSpecs: []string{"./app.sock:/tmp/a.sock", "./app.sock:/tmp/b.sock"},
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			got, err := parsePortForwards(tt.args.tcpSpecs, tt.args.udpSpecs)
+			got, err := parsePortForwards(tt.args.tcpSpecs, tt.args.udpSpecs, tt.args.unixSpecs)
 			if (err != nil) != tt.wantErr {
 				t.Fatalf("parsePortForwards() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -88,3 +113,70 @@ func Test_parsePortForwards(t *testing.T) {
 		})
 	}
 }
+
+func Test_parseSOCKS5Address(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "1080", want: "127.0.0.1:1080"},
+		{in: ":1080", want: "127.0.0.1:1080"},
+		{in: "0.0.0.0:1080", want: "0.0.0.0:1080"},
+		{in: "[::1]:1080", want: "[::1]:1080"},
+		{in: "localhost:1080", wantErr: true},
+		{in: "0", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.in, func(t *testing.T) {
+			t.Parallel()
+			got, err := parseSOCKS5Address(tt.in)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_parsePortForwardRemote(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in          string
+		wantLocal   string
+		wantRemote  string
+		wantNetwork string
+		wantErr     bool
+	}{
+		{in: "tcp:8080:localhost:3000", wantNetwork: "tcp", wantLocal: "127.0.0.1:3000", wantRemote: "127.0.0.1:8080"},
+		{in: "tcp:8080:10.0.0.1:3000", wantNetwork: "tcp", wantLocal: "10.0.0.1:3000", wantRemote: "127.0.0.1:8080"},
+		{in: "unix:/tmp/app.sock:./app.sock", wantNetwork: "unix", wantLocal: "./app.sock", wantRemote: "/tmp/app.sock"},
+		{in: `unix:/tmp/app.sock:C:\app.sock`, wantNetwork: "unix", wantLocal: `C:\app.sock`, wantRemote: "/tmp/app.sock"},
+		{in: "8080:localhost:3000", wantErr: true},
+		{in: "tcp:8080:3000", wantErr: true},
+		{in: "udp:8080:localhost:3000", wantErr: true},
+		{in: "unix:/tmp/app.sock", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.in, func(t *testing.T) {
+			t.Parallel()
+			local, remote, err := parsePortForwardRemote(tt.in)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantNetwork, local.Network())
+			require.Equal(t, tt.wantNetwork, remote.Network())
+			require.Equal(t, tt.wantLocal, local.String())
+			require.Equal(t, tt.wantRemote, remote.String())
+		})
+	}
+}
@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"path/filepath"
+	"runtime"
+	"strings"
 	"sync"
 	"testing"
 
@@ -12,6 +15,7 @@ import (
 	"github.com/pion/udp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/net/proxy"
 
 	"github.com/coder/coder/v2/cli/clitest"
 	"github.com/coder/coder/v2/coderd/coderdtest"
@@ -286,6 +290,106 @@ func TestPortForward(t *testing.T) {
 		err := <-errC
 		require.ErrorIs(t, err, context.Canceled)
 	})
+
+	t.Run("SOCKS5", func(t *testing.T) {
+		remoteListener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err, "create TCP listener")
+		remotePort := setupTestListener(t, remoteListener)
+		localAddress, _ := cases[0].setupLocal(t)
+
+		inv, root := clitest.New(t, "-v", "port-forward", workspace.Name, "--socks5", localAddress)
+		clitest.SetupConfig(t, client, root)
+		pty := ptytest.New(t).Attach(inv)
+		inv.Stderr = pty.Output()
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+		errC := make(chan error)
+		go func() {
+			errC <- inv.WithContext(ctx).Run()
+		}()
+		pty.ExpectMatchContext(ctx, "Ready!")
+
+		t.Parallel() // Port is reserved, enable parallel execution.
+
+		// The proxy dials from inside the workspace, which is this machine.
+		dialer, err := proxy.SOCKS5("tcp", localAddress, nil, proxy.Direct)
+		require.NoError(t, err)
+		c, err := dialer.(proxy.ContextDialer).DialContext(ctx, "tcp", net.JoinHostPort("localhost", remotePort))
+		require.NoError(t, err, "open connection through SOCKS5 proxy")
+		defer c.Close()
+		testDial(t, c)
+
+		cancel()
+		err = <-errC
+		require.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("Remote", func(t *testing.T) {
+		localListener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err, "create TCP listener")
+		localPort := setupTestListener(t, localListener)
+		// The agent listens on this machine as well.
+		remoteAddress, remotePort := cases[0].setupLocal(t)
+
+		inv, root := clitest.New(t, "-v", "port-forward", workspace.Name, "--remote", fmt.Sprintf("tcp:%s:127.0.0.1:%s", remotePort, localPort))
+		clitest.SetupConfig(t, client, root)
+		pty := ptytest.New(t).Attach(inv)
+		inv.Stderr = pty.Output()
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+		errC := make(chan error)
+		go func() {
+			errC <- inv.WithContext(ctx).Run()
+		}()
+		pty.ExpectMatchContext(ctx, "Ready!")
+
+		t.Parallel() // Port is reserved, enable parallel execution.
+
+		d := net.Dialer{Timeout: testutil.WaitShort}
+		c, err := d.DialContext(ctx, "tcp", remoteAddress)
+		require.NoError(t, err, "open connection to 'remote' listener")
+		defer c.Close()
+		testDial(t, c)
+
+		cancel()
+		err = <-errC
+		require.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("Unix", func(t *testing.T) {
+		t.Parallel()
+		if runtime.GOOS == "windows" {
+			t.Skip("Unix socket forwarding isn't supported by the agent on Windows")
+		}
+
+		dir := t.TempDir()
+		remoteListener, err := net.Listen("unix", filepath.Join(dir, "remote.sock"))
+		require.NoError(t, err, "create unix listener")
+		remotePath := setupTestListener(t, remoteListener)
+		localPath := filepath.Join(dir, "local.sock")
+
+		inv, root := clitest.New(t, "-v", "port-forward", workspace.Name, "--unix", localPath+":"+remotePath)
+		clitest.SetupConfig(t, client, root)
+		pty := ptytest.New(t).Attach(inv)
+		inv.Stderr = pty.Output()
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+		errC := make(chan error)
+		go func() {
+			errC <- inv.WithContext(ctx).Run()
+		}()
+		pty.ExpectMatchContext(ctx, "Ready!")
+
+		d := net.Dialer{Timeout: testutil.WaitShort}
+		c, err := d.DialContext(ctx, "unix", localPath)
+		require.NoError(t, err, "open connection to 'local' unix listener")
+		defer c.Close()
+		testDial(t, c)
+
+		cancel()
+		err = <-errC
+		require.ErrorIs(t, err, context.Canceled)
+	})
 }
 
 // runAgent creates a fake workspace and starts an agent locally for that
@@ -336,7 +440,7 @@ func runAgent(t *testing.T, client *codersdk.Client, userID uuid.UUID) codersdk.
 }
 
 // setupTestListener starts accepting connections and echoing a single packet.
-// Returns the listener and the listen port.
+// Returns the listen port, or the path for unix sockets.
 func setupTestListener(t *testing.T, l net.Listener) string {
 	t.Helper()
 
@@ -368,9 +472,11 @@ func setupTestListener(t *testing.T, l net.Listener) string {
 	}()
 
 	addr := l.Addr().String()
-	_, port, err := net.SplitHostPort(addr)
-	require.NoErrorf(t, err, "split non-Unix listen path %q", addr)
-	addr = port
+	if !strings.HasPrefix(l.Addr().Network(), "unix") {
+		_, port, err := net.SplitHostPort(addr)
+		require.NoErrorf(t, err, "split non-Unix listen path %q", addr)
+		addr = port
+	}
 
 	return addr
 }
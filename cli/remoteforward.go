@@ -7,6 +7,7 @@ import (
 	"net"
 	"regexp"
 	"strconv"
+	"strings"
 
 	gossh "golang.org/x/crypto/ssh"
 	"golang.org/x/xerrors"
@@ -57,6 +58,35 @@ func parseRemoteForward(flag string) (net.Addr, net.Addr, error) {
 	return localAddr, remoteAddr, nil
 }
 
+// parsePortForwardRemote parses the --remote spec of "coder port-forward",
+// which is one of:
+//
+//	tcp:remote_port:local_address:local_port
+//	unix:remote_path:local_path
+func parsePortForwardRemote(spec string) (net.Addr, net.Addr, error) {
+	network, rest, ok := strings.Cut(spec, ":")
+	if !ok {
+		return nil, nil, xerrors.Errorf("invalid remote port-forward specification %q", spec)
+	}
+	switch network {
+	case "tcp":
+		if !validateRemoteForward(rest) {
+			return nil, nil, xerrors.Errorf("invalid remote port-forward specification %q, expected tcp:<remote-port>:<local-address>:<local-port>", spec)
+		}
+		return parseRemoteForward(rest)
+	case "unix":
+		// The remote path comes first and is split off at the first colon, so
+		// local paths with a drive letter work on Windows.
+		remotePath, localPath, ok := strings.Cut(rest, ":")
+		if !ok || remotePath == "" || localPath == "" {
+			return nil, nil, xerrors.Errorf("invalid remote port-forward specification %q, expected unix:<remote-path>:<local-path>", spec)
+		}
+		return &net.UnixAddr{Name: localPath, Net: "unix"}, &net.UnixAddr{Name: remotePath, Net: "unix"}, nil
+	default:
+		return nil, nil, xerrors.Errorf("unknown network %q in remote port-forward specification %q, expected tcp or unix", network, spec)
+	}
+}
+
 // sshRemoteForward starts forwarding connections from a remote listener to a
 // local address via SSH in a goroutine.
 //
@@ -64,6 +64,7 @@ const (
 	varForceTty         = "force-tty"
 	varVerbose          = "verbose"
 	varDisableDirect    = "disable-direct-connections"
+	varProxy            = "proxy"
 	notLoggedInMessage  = "You are not logged in. Try logging in using 'coder login <url>'."
 
 	envNoVersionCheck   = "CODER_NO_VERSION_WARNING"
@@ -80,8 +81,11 @@ func (r *RootCmd) Core() []*clibase.Cmd {
 	// Please re-sort this list alphabetically if you change it!
 	return []*clibase.Cmd{
 		r.dotfiles(),
+		r.externalAuth(),
+		r.gitSSHKeys(),
 		r.login(),
 		r.logout(),
+		r.mfa(),
 		r.netcheck(),
 		r.portForward(),
 		r.publickey(),
@@ -89,8 +93,10 @@ func (r *RootCmd) Core() []*clibase.Cmd {
 		r.state(),
 		r.templates(),
 		r.tokens(),
+		r.tunnelServer(),
 		r.users(),
 		r.version(defaultVersionInfo),
+		r.vpnCmd(),
 
 		// Workspace Commands
 		r.configSSH(),
@@ -110,7 +116,9 @@ func (r *RootCmd) Core() []*clibase.Cmd {
 		r.stat(),
 
 		// Hidden
+		r.gitCredential(),
 		r.gitssh(),
+		r.sshPersist(),
 		r.vscodeSSH(),
 		r.workspaceAgent(),
 		r.expCmd(),
@@ -394,6 +402,13 @@ func (r *RootCmd) Command(subcommands []*clibase.Cmd) (*clibase.Cmd, error) {
 			Value:       clibase.BoolOf(&r.disableDirect),
 			Group:       globalGroup,
 		},
+		{
+			Flag:        varProxy,
+			Env:         "CODER_PROXY",
+			Description: "Name of the workspace proxy region to connect to workspaces through. By default, the healthy region with the lowest latency is selected and remembered until \"auto\" is given.",
+			Value:       clibase.StringOf(&r.proxy),
+			Group:       globalGroup,
+		},
 		{
 			Flag:        "debug-http",
 			Description: "Debug codersdk HTTP requests.",
@@ -451,6 +466,7 @@ type RootCmd struct {
 	noOpen        bool
 	verbose       bool
 	disableDirect bool
+	proxy         string
 	debugHTTP     bool
 
 	noVersionCheck   bool
@@ -70,10 +70,11 @@ import (
 	"github.com/coder/coder/v2/coderd/database/migrations"
 	"github.com/coder/coder/v2/coderd/database/pubsub"
 	"github.com/coder/coder/v2/coderd/devtunnel"
-	"github.com/coder/coder/v2/coderd/gitauth"
+	"github.com/coder/coder/v2/coderd/externalauth"
 	"github.com/coder/coder/v2/coderd/gitsshkey"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/ldap"
 	"github.com/coder/coder/v2/coderd/oauthpki"
 	"github.com/coder/coder/v2/coderd/prometheusmetrics"
 	"github.com/coder/coder/v2/coderd/schedule"
@@ -82,6 +83,7 @@ import (
 	"github.com/coder/coder/v2/coderd/unhanger"
 	"github.com/coder/coder/v2/coderd/updatecheck"
 	"github.com/coder/coder/v2/coderd/util/slice"
+	"github.com/coder/coder/v2/coderd/workloadidentity"
 	"github.com/coder/coder/v2/coderd/workspaceapps"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/cryptorand"
@@ -97,14 +99,29 @@ import (
 )
 
 // ReadGitAuthProvidersFromEnv is provided for compatibility purposes with the
-// viper CLI.
+// viper CLI. Providers are read from both the CODER_GITAUTH_ and the
+// CODER_EXTERNAL_AUTH_ prefixes.
 // DEPRECATED
 func ReadGitAuthProvidersFromEnv(environ []string) ([]codersdk.GitAuthConfig, error) {
 	// The index numbers must be in-order.
 	sort.Strings(environ)
 
+	providers, err := readExternalAuthProvidersFromEnv("CODER_GITAUTH_", environ)
+	if err != nil {
+		return nil, err
+	}
+	externalAuthProviders, err := readExternalAuthProvidersFromEnv("CODER_EXTERNAL_AUTH_", environ)
+	if err != nil {
+		return nil, err
+	}
+	return append(providers, externalAuthProviders...), nil
+}
+
+// readExternalAuthProvidersFromEnv parses the providers configured with
+// indexed environment variables of the prefix. The environ must be sorted.
+func readExternalAuthProvidersFromEnv(prefix string, environ []string) ([]codersdk.GitAuthConfig, error) {
 	var providers []codersdk.GitAuthConfig
-	for _, v := range clibase.ParseEnviron(environ, "CODER_GITAUTH_") {
+	for _, v := range clibase.ParseEnviron(environ, prefix) {
 		tokens := strings.SplitN(v.Name, "_", 2)
 		if len(tokens) != 2 {
 			return nil, xerrors.Errorf("invalid env var: %s", v.Name)
@@ -147,6 +164,8 @@ func ReadGitAuthProvidersFromEnv(environ []string) ([]codersdk.GitAuthConfig, er
 			provider.TokenURL = v.Value
 		case "VALIDATE_URL":
 			provider.ValidateURL = v.Value
+		case "SSH_KEYS_URL":
+			provider.SSHKeysURL = v.Value
 		case "REGEX":
 			provider.Regex = v.Value
 		case "DEVICE_FLOW":
@@ -169,6 +188,10 @@ func ReadGitAuthProvidersFromEnv(environ []string) ([]codersdk.GitAuthConfig, er
 			provider.AppInstallURL = v.Value
 		case "APP_INSTALLATIONS_URL":
 			provider.AppInstallationsURL = v.Value
+		case "DISPLAY_NAME":
+			provider.DisplayName = v.Value
+		case "DISPLAY_ICON":
+			provider.DisplayIcon = v.Value
 		}
 		providers[providerNum] = provider
 	}
@@ -247,6 +270,142 @@ func createOIDCConfig(ctx context.Context, vals *codersdk.DeploymentValues) (*co
 	}, nil
 }
 
+// createOIDCProviderConfigs creates the configs of the additional OIDC
+// providers. Their callbacks are served at /api/v2/users/oidc/<id>/callback.
+func createOIDCProviderConfigs(ctx context.Context, vals *codersdk.DeploymentValues) ([]*coderd.OIDCConfig, error) {
+	ids := map[string]struct{}{}
+	configs := make([]*coderd.OIDCConfig, 0, len(vals.OIDC.Providers.Value))
+	for _, entry := range vals.OIDC.Providers.Value {
+		if valid := httpapi.NameValid(entry.ID); valid != nil {
+			return nil, xerrors.Errorf("oidc provider %q doesn't have a valid id: %w", entry.ID, valid)
+		}
+		// The default provider is served at /api/v2/users/oidc/callback.
+		if entry.ID == "callback" {
+			return nil, xerrors.Errorf("oidc provider id %q is reserved", entry.ID)
+		}
+		if _, exists := ids[entry.ID]; exists {
+			return nil, xerrors.Errorf("multiple oidc providers exist with the id %q. specify a unique id for each", entry.ID)
+		}
+		ids[entry.ID] = struct{}{}
+
+		if entry.ClientID == "" {
+			return nil, xerrors.Errorf("%q oidc provider: client id must be provided", entry.ID)
+		}
+		if entry.IssuerURL == "" {
+			return nil, xerrors.Errorf("%q oidc provider: issuer url must be provided", entry.ID)
+		}
+
+		oidcProvider, err := oidc.NewProvider(ctx, entry.IssuerURL)
+		if err != nil {
+			return nil, xerrors.Errorf("configure %q oidc provider: %w", entry.ID, err)
+		}
+		redirectURL, err := vals.AccessURL.Value().Parse(fmt.Sprintf("/api/v2/users/oidc/%s/callback", entry.ID))
+		if err != nil {
+			return nil, xerrors.Errorf("parse %q oidc oauth callback url: %w", entry.ID, err)
+		}
+
+		scopes := entry.Scopes
+		if len(scopes) == 0 {
+			scopes = []string{oidc.ScopeOpenID, "profile", "email"}
+		}
+		usernameField := entry.UsernameField
+		if usernameField == "" {
+			usernameField = "preferred_username"
+		}
+		emailField := entry.EmailField
+		if emailField == "" {
+			emailField = "email"
+		}
+		// If the scopes contain 'groups', we enable group support.
+		// Do not override any custom value set by the user.
+		groupField := entry.GroupField
+		if slice.Contains(scopes, "groups") && groupField == "" {
+			groupField = "groups"
+		}
+
+		configs = append(configs, &coderd.OIDCConfig{
+			OAuth2Config: &oauth2.Config{
+				ClientID:     entry.ClientID,
+				ClientSecret: entry.ClientSecret,
+				RedirectURL:  redirectURL.String(),
+				Endpoint:     oidcProvider.Endpoint(),
+				Scopes:       scopes,
+			},
+			ID:       entry.ID,
+			Provider: oidcProvider,
+			Verifier: oidcProvider.Verifier(&oidc.Config{
+				ClientID: entry.ClientID,
+			}),
+			EmailDomain:         entry.EmailDomain,
+			AllowSignups:        entry.AllowSignups,
+			UsernameField:       usernameField,
+			EmailField:          emailField,
+			AuthURLParams:       entry.AuthURLParams,
+			IgnoreUserInfo:      entry.IgnoreUserInfo,
+			GroupField:          groupField,
+			CreateMissingGroups: entry.GroupAutoCreate,
+			GroupMapping:        entry.GroupMapping,
+			UserRoleField:       entry.UserRoleField,
+			UserRoleMapping:     entry.UserRoleMapping,
+			UserRolesDefault:    entry.UserRolesDefault,
+			SignInText:          entry.SignInText,
+			IconURL:             entry.IconURL,
+			IgnoreEmailVerified: entry.IgnoreEmailVerified,
+		})
+	}
+	return configs, nil
+}
+
+// createLDAPConfig creates the config of LDAP authentication, which is only
+// enabled when an LDAP URL is set.
+func createLDAPConfig(vals *codersdk.DeploymentValues) (*coderd.LDAPConfig, error) {
+	if vals.LDAP.BaseDN == "" {
+		return nil, xerrors.Errorf("LDAP base DN must be set!")
+	}
+	var tlsConfig *tls.Config
+	if vals.LDAP.TLSCAFile != "" {
+		data, err := os.ReadFile(vals.LDAP.TLSCAFile.String())
+		if err != nil {
+			return nil, xerrors.Errorf("read ldap tls ca file %q: %w", vals.LDAP.TLSCAFile.String(), err)
+		}
+		caPool := x509.NewCertPool()
+		if !caPool.AppendCertsFromPEM(data) {
+			return nil, xerrors.Errorf("no certificates found in ldap tls ca file %q", vals.LDAP.TLSCAFile.String())
+		}
+		tlsConfig = &tls.Config{
+			MinVersion: tls.VersionTLS12,
+			RootCAs:    caPool,
+		}
+	}
+
+	cfg := &coderd.LDAPConfig{
+		Config: ldap.Config{
+			URL:               vals.LDAP.URL.String(),
+			StartTLS:          vals.LDAP.StartTLS.Value(),
+			TLSConfig:         tlsConfig,
+			BindDN:            vals.LDAP.BindDN.String(),
+			BindPassword:      vals.LDAP.BindPassword.String(),
+			BaseDN:            vals.LDAP.BaseDN.String(),
+			UserFilter:        vals.LDAP.UserFilter.String(),
+			UsernameAttribute: vals.LDAP.UsernameAttribute.String(),
+			EmailAttribute:    vals.LDAP.EmailAttribute.String(),
+			GroupAttribute:    vals.LDAP.GroupAttribute.String(),
+		},
+		AllowSignups:        vals.LDAP.AllowSignups.Value(),
+		GroupMapping:        vals.LDAP.GroupMapping.Value,
+		CreateMissingGroups: vals.LDAP.GroupAutoCreate.Value(),
+		UserRoleMapping:     vals.LDAP.UserRoleMapping.Value,
+		UserRolesDefault:    vals.LDAP.UserRolesDefault.GetSlice(),
+		SyncInterval:        vals.LDAP.SyncInterval.Value(),
+		SignInText:          vals.LDAP.SignInText.String(),
+	}
+	err := cfg.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
 func afterCtx(ctx context.Context, fn func()) {
 	go func() {
 		<-ctx.Done()
@@ -568,11 +727,11 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 
 			gitAuthEnv, err := ReadGitAuthProvidersFromEnv(os.Environ())
 			if err != nil {
-				return xerrors.Errorf("read git auth providers from env: %w", err)
+				return xerrors.Errorf("read external auth providers from env: %w", err)
 			}
 
 			vals.GitAuthProviders.Value = append(vals.GitAuthProviders.Value, gitAuthEnv...)
-			gitAuthConfigs, err := gitauth.ConvertConfig(
+			gitAuthConfigs, err := externalauth.ConvertConfig(
 				vals.GitAuthProviders.Value,
 				vals.AccessURL.Value(),
 			)
@@ -586,6 +745,19 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 				)
 			}
 
+			workloadIdentityProviders, err := workloadidentity.ConvertConfig(vals.WorkloadIdentityProviders.Value)
+			if err != nil {
+				return xerrors.Errorf("convert workload identity config: %w", err)
+			}
+			for _, p := range workloadIdentityProviders {
+				p.HTTPClient = httpClient
+				logger.Debug(
+					ctx, "loaded workload identity provider",
+					slog.F("id", p.ID),
+					slog.F("issuer", p.Issuer),
+				)
+			}
+
 			realIPConfig, err := httpmw.ParseRealIPConfig(vals.ProxyTrustedHeaders, vals.ProxyTrustedOrigins)
 			if err != nil {
 				return xerrors.Errorf("parse real ip config: %w", err)
@@ -607,6 +779,7 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 				CacheDir:                    cacheDir,
 				GoogleTokenValidator:        googleTokenValidator,
 				GitAuthConfigs:              gitAuthConfigs,
+				WorkloadIdentityProviders:   workloadIdentityProviders,
 				RealIPConfig:                realIPConfig,
 				SecureAuthCookie:            vals.SecureAuthCookie.Value(),
 				SSHKeygenAlgorithm:          sshKeygenAlgorithm,
@@ -686,6 +859,18 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 				options.OIDCConfig = oc
 			}
 
+			options.OIDCProviders, err = createOIDCProviderConfigs(ctx, vals)
+			if err != nil {
+				return xerrors.Errorf("create oidc provider configs: %w", err)
+			}
+
+			if vals.LDAP.URL != "" {
+				options.LDAPConfig, err = createLDAPConfig(vals)
+				if err != nil {
+					return xerrors.Errorf("create ldap config: %w", err)
+				}
+			}
+
 			if vals.InMemoryDatabase {
 				// This is only used for testing.
 				options.Database = dbfake.New()
@@ -1310,6 +1495,7 @@ func newProvisionerDaemon(
 	}
 
 	provisioners := provisionerd.Provisioners{}
+	provisionerVersions := map[string]string{}
 	if cfg.Provisioner.DaemonsEcho {
 		echoClient, echoServer := provisionersdk.MemTransportPipe()
 		wg.Add(1)
@@ -1376,6 +1562,13 @@ func newProvisionerDaemon(
 		}()
 
 		provisioners[string(database.ProvisionerTypeTerraform)] = sdkproto.NewDRPCProvisionerClient(terraformClient)
+
+		tfVersion, err := terraform.ResolveVersion(ctx, "")
+		if err != nil {
+			logger.Warn(ctx, "unable to determine terraform version", slog.Error(err))
+		} else {
+			provisionerVersions[string(database.ProvisionerTypeTerraform)] = tfVersion.String()
+		}
 	}
 
 	debounce := time.Second
@@ -1391,6 +1584,7 @@ func newProvisionerDaemon(
 		UpdateInterval:      time.Second,
 		ForceCancelInterval: cfg.Provisioner.ForceCancelInterval.Value(),
 		Provisioners:        provisioners,
+		ProvisionerVersions: provisionerVersions,
 		TracerProvider:      coderAPI.TracerProvider,
 		Metrics:             &metrics,
 	}), nil
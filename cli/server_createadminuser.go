@@ -201,6 +201,8 @@ func (r *RootCmd) newCreateAdminUserCommand() *clibase.Cmd {
 					UpdatedAt:  dbtime.Now(),
 					PrivateKey: privateKey,
 					PublicKey:  publicKey,
+					Name:       gitsshkey.DefaultName,
+					Hosts:      []string{},
 				})
 				if err != nil {
 					return xerrors.Errorf("insert user gitsshkey: %w", err)
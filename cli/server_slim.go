@@ -19,7 +19,7 @@ func (r *RootCmd) Server(_ func()) *clibase.Cmd {
 		RawArgs: true,
 		Hidden:  true,
 		Handler: func(inv *clibase.Invocation) error {
-			serverUnsupported(inv.Stderr)
+			slimUnsupported(inv.Stderr, "server")
 			return nil
 		},
 	}
@@ -27,8 +27,8 @@ func (r *RootCmd) Server(_ func()) *clibase.Cmd {
 	return root
 }
 
-func serverUnsupported(w io.Writer) {
-	_, _ = fmt.Fprintf(w, "You are using a 'slim' build of Coder, which does not support the %s subcommand.\n", cliui.DefaultStyles.Code.Render("server"))
+func slimUnsupported(w io.Writer, cmd string) {
+	_, _ = fmt.Fprintf(w, "You are using a 'slim' build of Coder, which does not support the %s subcommand.\n", cliui.DefaultStyles.Code.Render(cmd))
 	_, _ = fmt.Fprintln(w, "")
 	_, _ = fmt.Fprintln(w, "Please use a build of Coder from GitHub releases:")
 	_, _ = fmt.Fprintln(w, "  https://github.com/coder/coder/releases")
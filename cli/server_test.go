@@ -102,6 +102,29 @@ func TestReadGitAuthProvidersFromEnv(t *testing.T) {
 		assert.Equal(t, []string{"repo:read", "repo:write"}, providers[1].Scopes)
 		assert.Equal(t, true, providers[1].NoRefresh)
 	})
+	t.Run("ExternalAuth", func(t *testing.T) {
+		t.Parallel()
+		providers, err := cli.ReadGitAuthProvidersFromEnv([]string{
+			"CODER_GITAUTH_0_ID=github",
+			"CODER_GITAUTH_0_TYPE=github",
+			"CODER_EXTERNAL_AUTH_0_ID=jira",
+			"CODER_EXTERNAL_AUTH_0_TYPE=jira",
+			"CODER_EXTERNAL_AUTH_0_AUTH_URL=https://auth.atlassian.com/authorize",
+			"CODER_EXTERNAL_AUTH_0_TOKEN_URL=https://auth.atlassian.com/oauth/token",
+			"CODER_EXTERNAL_AUTH_0_DISPLAY_NAME=Jira",
+			"CODER_EXTERNAL_AUTH_0_DISPLAY_ICON=/icon/jira.svg",
+		})
+		require.NoError(t, err)
+		require.Len(t, providers, 2)
+
+		assert.Equal(t, "github", providers[0].ID)
+		assert.Equal(t, "jira", providers[1].ID)
+		assert.Equal(t, "jira", providers[1].Type)
+		assert.Equal(t, "https://auth.atlassian.com/authorize", providers[1].AuthURL)
+		assert.Equal(t, "https://auth.atlassian.com/oauth/token", providers[1].TokenURL)
+		assert.Equal(t, "Jira", providers[1].DisplayName)
+		assert.Equal(t, "/icon/jira.svg", providers[1].DisplayIcon)
+	})
 }
 
 func TestServer(t *testing.T) {
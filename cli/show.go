@@ -1,6 +1,9 @@
 package cli
 
 import (
+	"fmt"
+	"io"
+
 	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/v2/cli/clibase"
@@ -26,10 +29,62 @@ func (r *RootCmd) show() *clibase.Cmd {
 			if err != nil {
 				return xerrors.Errorf("get workspace: %w", err)
 			}
-			return cliui.WorkspaceResources(inv.Stdout, workspace.LatestBuild.Resources, cliui.WorkspaceResourcesOptions{
+			err = cliui.WorkspaceResources(inv.Stdout, workspace.LatestBuild.Resources, cliui.WorkspaceResourcesOptions{
 				WorkspaceName: workspace.Name,
 				ServerVersion: buildInfo.Version,
 			})
+			if err != nil {
+				return err
+			}
+
+			proxy, err := r.workspaceProxy(inv, client)
+			if err != nil {
+				return err
+			}
+			if proxy.PathAppURL == "" {
+				proxy.PathAppURL = client.URL.String()
+			}
+			return showWorkspaceApps(inv.Stdout, proxy, workspace)
 		},
 	}
 }
+
+// showWorkspaceApps writes the URLs of the apps of the workspace, as opened
+// through the given region.
+func showWorkspaceApps(w io.Writer, region codersdk.Region, workspace codersdk.Workspace) error {
+	var lines []string
+	for _, resource := range workspace.LatestBuild.Resources {
+		for _, agent := range resource.Agents {
+			for _, app := range agent.Apps {
+				appURL := workspaceAppURL(region, workspace, agent, app)
+				if appURL == "" {
+					continue
+				}
+				lines = append(lines, fmt.Sprintf("  %s/%s: %s", agent.Name, app.Slug, appURL))
+			}
+		}
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+
+	via := region.DisplayName
+	if via == "" {
+		via = region.Name
+	}
+	header := "Apps"
+	if via != "" {
+		header += " (via " + via + ")"
+	}
+	_, err := fmt.Fprintln(w, cliui.DefaultStyles.Bold.Render(header))
+	if err != nil {
+		return err
+	}
+	for _, line := range lines {
+		_, err = fmt.Fprintln(w, line)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
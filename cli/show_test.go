@@ -1,12 +1,17 @@
 package cli_test
 
 import (
+	"bytes"
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/coder/coder/v2/cli/clitest"
 	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisionersdk/proto"
 	"github.com/coder/coder/v2/pty/ptytest"
 )
 
@@ -51,4 +56,47 @@ func TestShow(t *testing.T) {
 		}
 		<-doneChan
 	})
+
+	t.Run("Apps", func(t *testing.T) {
+		t.Parallel()
+		client, workspace, _ := setupWorkspaceForAgent(t, func(agents []*proto.Agent) []*proto.Agent {
+			agents[0].Name = "main"
+			agents[0].Apps = []*proto.App{{Slug: "code", DisplayName: "code", Url: "http://localhost:8080"}}
+			return agents
+		})
+
+		inv, root := clitest.New(t, "show", workspace.Name)
+		clitest.SetupConfig(t, client, root)
+		out := bytes.NewBuffer(nil)
+		inv.Stdout = out
+		err := inv.Run()
+		require.NoError(t, err)
+		require.Contains(t, out.String(), client.URL.String()+"/@testuser/"+workspace.Name+".main/apps/code/")
+
+		// The nearest region is remembered.
+		raw, err := root.WorkspaceProxy().Read()
+		require.NoError(t, err)
+		var proxy struct {
+			Region codersdk.Region `json:"region"`
+		}
+		err = json.Unmarshal([]byte(raw), &proxy)
+		require.NoError(t, err)
+		require.Equal(t, codersdk.PrimaryRegionName, proxy.Region.Name)
+	})
+
+	t.Run("UnknownProxy", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, completeWithAgent())
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+		inv, root := clitest.New(t, "show", workspace.Name, "--proxy", "doesnotexist")
+		clitest.SetupConfig(t, client, root)
+		err := inv.Run()
+		require.ErrorContains(t, err, `workspace proxy "doesnotexist" does not exist`)
+	})
 }
@@ -59,8 +59,14 @@ func (r *RootCmd) speedtest() *clibase.Cmd {
 			if r.disableDirect {
 				_, _ = fmt.Fprintln(inv.Stderr, "Direct connections disabled.")
 			}
+			proxy, err := r.workspaceProxy(inv, client)
+			if err != nil {
+				return err
+			}
 			conn, err := client.DialWorkspaceAgent(ctx, workspaceAgent.ID, &codersdk.DialWorkspaceAgentOptions{
-				Logger: logger,
+				Logger:            logger,
+				WorkspaceProxy:    proxy.Name,
+				WorkspaceProxyURL: workspaceProxyURL(proxy),
 			})
 			if err != nil {
 				return err
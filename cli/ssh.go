@@ -6,10 +6,12 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -51,6 +53,8 @@ func (r *RootCmd) ssh() *clibase.Cmd {
 		noWait         bool
 		logDirPath     string
 		remoteForward  string
+		persist        bool
+		persistTimeout time.Duration
 	)
 	client := new(codersdk.Client)
 	cmd := &clibase.Cmd{
@@ -130,88 +134,137 @@ func (r *RootCmd) ssh() *clibase.Cmd {
 				}
 			}
 
-			workspace, workspaceAgent, err := getWorkspaceAndAgent(ctx, inv, client, codersdk.Me, inv.Args[0])
-			if err != nil {
-				return err
+			var (
+				workspace      codersdk.Workspace
+				workspaceAgent codersdk.WorkspaceAgent
+				conn           agentDialer
+				persistUserID  uuid.UUID
+			)
+			if persist {
+				// Connections are only shared by invocations of the same
+				// user.
+				me, err := client.User(ctx, codersdk.Me)
+				if err != nil {
+					return xerrors.Errorf("get user: %w", err)
+				}
+				persistUserID = me.ID
+				// Skip looking up the workspace and waiting for the agent if
+				// a previous invocation left its connection open.
+				var ok bool
+				conn, ok = r.sshPersistDialer(ctx, client, persistUserID, inv.Args[0])
+				if ok {
+					logger.Debug(ctx, "connecting through persistent connection")
+				}
 			}
+			if conn == nil {
+				var err error
+				workspace, workspaceAgent, err = getWorkspaceAndAgent(ctx, inv, client, codersdk.Me, inv.Args[0])
+				if err != nil {
+					return err
+				}
 
-			// Select the startup script behavior based on template configuration or flags.
-			var wait bool
-			switch waitEnum {
-			case "yes":
-				wait = true
-			case "no":
-				wait = false
-			case "auto":
-				switch workspaceAgent.StartupScriptBehavior {
-				case codersdk.WorkspaceAgentStartupScriptBehaviorBlocking:
+				// Select the startup script behavior based on template configuration or flags.
+				var wait bool
+				switch waitEnum {
+				case "yes":
 					wait = true
-				case codersdk.WorkspaceAgentStartupScriptBehaviorNonBlocking:
+				case "no":
 					wait = false
+				case "auto":
+					switch workspaceAgent.StartupScriptBehavior {
+					case codersdk.WorkspaceAgentStartupScriptBehaviorBlocking:
+						wait = true
+					case codersdk.WorkspaceAgentStartupScriptBehaviorNonBlocking:
+						wait = false
+					default:
+						return xerrors.Errorf("unknown startup script behavior %q", workspaceAgent.StartupScriptBehavior)
+					}
 				default:
-					return xerrors.Errorf("unknown startup script behavior %q", workspaceAgent.StartupScriptBehavior)
+					return xerrors.Errorf("unknown wait value %q", waitEnum)
+				}
+				// The `--no-wait` flag is deprecated, but for now, check it.
+				if noWait {
+					wait = false
 				}
-			default:
-				return xerrors.Errorf("unknown wait value %q", waitEnum)
-			}
-			// The `--no-wait` flag is deprecated, but for now, check it.
-			if noWait {
-				wait = false
-			}
 
-			templateVersion, err := client.TemplateVersion(ctx, workspace.LatestBuild.TemplateVersionID)
-			if err != nil {
-				return err
-			}
+				templateVersion, err := client.TemplateVersion(ctx, workspace.LatestBuild.TemplateVersionID)
+				if err != nil {
+					return err
+				}
 
-			var unsupportedWorkspace bool
-			for _, warning := range templateVersion.Warnings {
-				if warning == codersdk.TemplateVersionWarningUnsupportedWorkspaces {
-					unsupportedWorkspace = true
-					break
+				var unsupportedWorkspace bool
+				for _, warning := range templateVersion.Warnings {
+					if warning == codersdk.TemplateVersionWarningUnsupportedWorkspaces {
+						unsupportedWorkspace = true
+						break
+					}
 				}
-			}
 
-			if unsupportedWorkspace && isTTYErr(inv) {
-				_, _ = fmt.Fprintln(inv.Stderr, "👋 Your workspace uses legacy parameters which are not supported anymore. Contact your administrator for assistance.")
-			}
+				if unsupportedWorkspace && isTTYErr(inv) {
+					_, _ = fmt.Fprintln(inv.Stderr, "👋 Your workspace uses legacy parameters which are not supported anymore. Contact your administrator for assistance.")
+				}
 
-			updateWorkspaceBanner, outdated := verifyWorkspaceOutdated(client, workspace)
-			if outdated && isTTYErr(inv) {
-				_, _ = fmt.Fprintln(inv.Stderr, updateWorkspaceBanner)
-			}
+				updateWorkspaceBanner, outdated := verifyWorkspaceOutdated(client, workspace)
+				if outdated && isTTYErr(inv) {
+					_, _ = fmt.Fprintln(inv.Stderr, updateWorkspaceBanner)
+				}
 
-			// OpenSSH passes stderr directly to the calling TTY.
-			// This is required in "stdio" mode so a connecting indicator can be displayed.
-			err = cliui.Agent(ctx, inv.Stderr, workspaceAgent.ID, cliui.AgentOptions{
-				Fetch:     client.WorkspaceAgent,
-				FetchLogs: client.WorkspaceAgentLogsAfter,
-				Wait:      wait,
-			})
-			if err != nil {
-				if xerrors.Is(err, context.Canceled) {
-					return cliui.Canceled
+				// OpenSSH passes stderr directly to the calling TTY.
+				// This is required in "stdio" mode so a connecting indicator can be displayed.
+				err = cliui.Agent(ctx, inv.Stderr, workspaceAgent.ID, cliui.AgentOptions{
+					Fetch:     client.WorkspaceAgent,
+					FetchLogs: client.WorkspaceAgentLogsAfter,
+					Wait:      wait,
+				})
+				if err != nil {
+					if xerrors.Is(err, context.Canceled) {
+						return cliui.Canceled
+					}
 				}
-			}
 
-			if r.disableDirect {
-				_, _ = fmt.Fprintln(inv.Stderr, "Direct connections disabled.")
-			}
-			conn, err := client.DialWorkspaceAgent(ctx, workspaceAgent.ID, &codersdk.DialWorkspaceAgentOptions{
-				Logger:         logger,
-				BlockEndpoints: r.disableDirect,
-			})
-			if err != nil {
-				return xerrors.Errorf("dial agent: %w", err)
-			}
-			defer conn.Close()
-			conn.AwaitReachable(ctx)
+				// Reuse the connection of coder vpn if it's running.
+				var ok bool
+				conn, ok = r.vpnDialer(ctx, client, workspaceAgent.ID)
+				if ok {
+					logger.Debug(ctx, "connecting through coder vpn")
+				}
+				if !ok && persist {
+					conn, err = r.startSSHPersist(ctx, inv, client, persistUserID, inv.Args[0], persistTimeout)
+					if err == nil {
+						ok = true
+						logger.Debug(ctx, "connecting through new persistent connection")
+					} else {
+						_, _ = fmt.Fprintf(inv.Stderr, "Failed to keep the connection open: %s\n", err)
+					}
+				}
+				if !ok {
+					if r.disableDirect {
+						_, _ = fmt.Fprintln(inv.Stderr, "Direct connections disabled.")
+					}
+					proxy, err := r.workspaceProxy(inv, client)
+					if err != nil {
+						return err
+					}
+					agentConn, err := client.DialWorkspaceAgent(ctx, workspaceAgent.ID, &codersdk.DialWorkspaceAgentOptions{
+						Logger:            logger,
+						BlockEndpoints:    r.disableDirect,
+						WorkspaceProxy:    proxy.Name,
+						WorkspaceProxyURL: workspaceProxyURL(proxy),
+					})
+					if err != nil {
+						return xerrors.Errorf("dial agent: %w", err)
+					}
+					defer agentConn.Close()
+					agentConn.AwaitReachable(ctx)
+					conn = agentConn
+				}
 
-			stopPolling := tryPollWorkspaceAutostop(ctx, client, workspace)
-			defer stopPolling()
+				stopPolling := tryPollWorkspaceAutostop(ctx, client, workspace)
+				defer stopPolling()
+			}
 
 			if stdio {
-				rawSSH, err := conn.SSH(ctx)
+				rawSSH, err := conn.DialContext(ctx, "tcp", agentSSHAddress)
 				if err != nil {
 					return xerrors.Errorf("connect SSH: %w", err)
 				}
@@ -249,7 +302,24 @@ func (r *RootCmd) ssh() *clibase.Cmd {
 				return nil
 			}
 
-			sshClient, err := conn.SSHClient(ctx)
+			// Sessions that forward over SSH don't survive reconnects, so
+			// only plain shells run in a reconnecting PTY.
+			if persist && !forwardAgent && !forwardGPG && remoteForward == "" {
+				// Put cancel at the top of the defer stack to initiate
+				// shutdown of services.
+				defer cancel()
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					watchAndClose(ctx, func() error {
+						cancel()
+						return nil
+					}, logger, client, workspace)
+				}()
+				return runReconnectingPTY(ctx, inv, conn, logger)
+			}
+
+			sshClient, err := agentSSHClient(ctx, conn)
 			if err != nil {
 				return xerrors.Errorf("ssh client: %w", err)
 			}
@@ -453,10 +523,40 @@ func (r *RootCmd) ssh() *clibase.Cmd {
 			FlagShorthand: "R",
 			Value:         clibase.StringOf(&remoteForward),
 		},
+		{
+			Flag:        "persist",
+			Env:         "CODER_SSH_PERSIST",
+			Description: "Share the connection to the workspace with later invocations, and reconnect the shell when the connection drops. The connection is kept open in the background until it's been unused for --persist-timeout. Shells that forward agents or ports don't reconnect.",
+			Value:       clibase.BoolOf(&persist),
+		},
+		{
+			Flag:        "persist-timeout",
+			Env:         "CODER_SSH_PERSIST_TIMEOUT",
+			Description: "Specifies how long a persistent connection is kept open after it was last used.",
+			Default:     "10m",
+			Value:       clibase.DurationOf(&persistTimeout),
+		},
 	}
 	return cmd
 }
 
+// agentSSHAddress is the address of the SSH server of an agent.
+var agentSSHAddress = net.JoinHostPort("localhost", strconv.Itoa(codersdk.WorkspaceAgentSSHPort))
+
+// agentSSHClient connects to the SSH server of the agent.
+func agentSSHClient(ctx context.Context, conn agentDialer) (*gossh.Client, error) {
+	rawSSH, err := conn.DialContext(ctx, "tcp", agentSSHAddress)
+	if err != nil {
+		return nil, xerrors.Errorf("connect SSH: %w", err)
+	}
+	sshClient, err := codersdk.NewWorkspaceAgentSSHClient(rawSSH)
+	if err != nil {
+		_ = rawSSH.Close()
+		return nil, err
+	}
+	return sshClient, nil
+}
+
 // watchAndClose ensures closer is called if the context is canceled or
 // the workspace reaches the stopped state.
 //
@@ -477,6 +577,13 @@ func watchAndClose(ctx context.Context, closer func() error, logger slog.Logger,
 		}
 	}()
 
+	// The workspace isn't looked up when reusing a persistent connection,
+	// which is closed when the workspace stops instead.
+	if workspace.ID == uuid.Nil {
+		<-ctx.Done()
+		return
+	}
+
 startWatchLoop:
 	for {
 		logger.Debug(ctx, "connecting to the coder server to watch workspace events")
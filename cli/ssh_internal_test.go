@@ -1,13 +1,26 @@
 package cli
 
 import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"strconv"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"cdr.dev/slog/sloggers/slogtest"
+
 	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
 )
 
 const (
@@ -56,3 +69,96 @@ func TestBuildWorkspaceLink(t *testing.T) {
 
 	assert.Equal(t, workspaceLink.String(), fakeServerURL+"/@"+fakeOwnerName+"/"+fakeWorkspaceName)
 }
+
+func TestReconnectingPTYSession(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	api := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(rw).Encode(codersdk.WorkspaceAgentReconnectingPTYExit{ExitCode: 3})
+	}))
+	defer api.Close()
+
+	stdoutReader, stdoutWriter := io.Pipe()
+	defer stdoutReader.Close()
+	stderr := &bytes.Buffer{}
+	session := &reconnectingPTYSession{
+		dialer: testAgentDialer{
+			codersdk.WorkspaceAgentReconnectingPTYPort: listener.Addr().String(),
+			codersdk.WorkspaceAgentHTTPAPIServerPort:   api.Listener.Addr().String(),
+		},
+		logger: slogtest.Make(t, nil),
+		id:     uuid.New(),
+		stdout: stdoutWriter,
+		stderr: stderr,
+		size: func() (uint16, uint16) {
+			return 80, 80
+		},
+	}
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- session.run(ctx)
+	}()
+
+	// accept accepts a connection to the reconnecting PTY of the session.
+	accept := func() *net.TCPConn {
+		conn, err := listener.Accept()
+		require.NoError(t, err)
+		rawLen := make([]byte, 2)
+		_, err = io.ReadFull(conn, rawLen)
+		require.NoError(t, err)
+		data := make([]byte, binary.LittleEndian.Uint16(rawLen))
+		_, err = io.ReadFull(conn, data)
+		require.NoError(t, err)
+		var init codersdk.WorkspaceAgentReconnectingPTYInit
+		require.NoError(t, json.Unmarshal(data, &init))
+		require.Equal(t, session.id, init.ID)
+		return conn.(*net.TCPConn)
+	}
+
+	conn := accept()
+	_, err = conn.Write([]byte("hello"))
+	require.NoError(t, err)
+	require.NoError(t, testutil.ReadUntilString(ctx, t, "hello", stdoutReader))
+	// Reset the connection to lose it.
+	require.NoError(t, conn.SetLinger(0))
+	require.NoError(t, conn.Close())
+
+	// The session reattaches to the same reconnecting PTY.
+	conn = accept()
+	_, err = conn.Write([]byte("world"))
+	require.NoError(t, err)
+	require.NoError(t, testutil.ReadUntilString(ctx, t, "world", stdoutReader))
+	// The shell exits.
+	require.NoError(t, conn.Close())
+
+	select {
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for the session to end")
+	case err := <-runErr:
+		require.ErrorContains(t, err, "exited with status 3")
+	}
+	require.Contains(t, stderr.String(), "reconnecting")
+}
+
+// testAgentDialer dials the addresses that ports of an agent are mapped to.
+type testAgentDialer map[int]string
+
+func (d testAgentDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	_, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	portNumber, err := strconv.Atoi(port)
+	if err != nil {
+		return nil, err
+	}
+	var dialer net.Dialer
+	return dialer.DialContext(ctx, network, d[portNumber])
+}
@@ -8,7 +8,9 @@ import (
 	"io"
 	"net"
 	"os"
+	"os/exec"
 	"os/signal"
+	"syscall"
 
 	gossh "golang.org/x/crypto/ssh"
 	"golang.org/x/sys/unix"
@@ -46,3 +48,9 @@ func forwardGPGAgent(ctx context.Context, stderr io.Writer, sshClient *gossh.Cli
 
 	return sshRemoteForward(ctx, stderr, sshClient, localAddr, remoteAddr)
 }
+
+// detachProcess starts cmd in a session of its own, so that it keeps running
+// after the terminal it was started from is closed.
+func detachProcess(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+}
@@ -493,6 +493,57 @@ func TestSSH(t *testing.T) {
 		require.NoError(t, err)
 		require.Len(t, ents, 1, "expected one file in logdir %s", logDir)
 	})
+
+	t.Run("Persist", func(t *testing.T) {
+		t.Parallel()
+
+		client, workspace, agentToken := setupWorkspaceForAgent(t, nil)
+		agentClient := agentsdk.New(client.URL)
+		agentClient.SetSessionToken(agentToken)
+		agentCloser := agent.New(agent.Options{
+			Client: agentClient,
+			Logger: slogtest.Make(t, nil).Named("agent"),
+		})
+		defer func() {
+			_ = agentCloser.Close()
+		}()
+		coderdtest.AwaitWorkspaceAgents(t, client, workspace.ID)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		// Start the process that the first "coder ssh --persist" starts
+		// in the background.
+		persistInv, root := clitest.New(t, "ssh-persist", workspace.Name)
+		clitest.SetupConfig(t, client, root)
+		persistPty := ptytest.New(t).Attach(persistInv)
+		persistDone := tGo(t, func() {
+			err := persistInv.WithContext(ctx).Run()
+			assert.NoError(t, err)
+		})
+		persistPty.ExpectMatch("serving connection")
+
+		logDir := t.TempDir()
+		inv, _ := clitest.New(t, "ssh", "--persist", "-v", "-l", logDir, "--global-config", string(root), workspace.Name)
+		pty := ptytest.New(t).Attach(inv)
+		cmdDone := tGo(t, func() {
+			err := inv.WithContext(ctx).Run()
+			assert.NoError(t, err)
+		})
+		// The shell runs in a reconnecting PTY, which ends when it exits.
+		pty.WriteLine("exit")
+		<-cmdDone
+
+		ents, err := os.ReadDir(logDir)
+		require.NoError(t, err)
+		require.Len(t, ents, 1, "expected one file in logdir %s", logDir)
+		logs, err := os.ReadFile(filepath.Join(logDir, ents[0].Name()))
+		require.NoError(t, err)
+		require.Contains(t, string(logs), "connecting through persistent connection")
+
+		cancel()
+		<-persistDone
+	})
 }
 
 //nolint:paralleltest // This test uses t.Setenv, parent test MUST NOT be parallel.
@@ -9,10 +9,13 @@ import (
 	"io"
 	"net"
 	"os"
+	"os/exec"
 	"strconv"
+	"syscall"
 	"time"
 
 	gossh "golang.org/x/crypto/ssh"
+	"golang.org/x/sys/windows"
 	"golang.org/x/xerrors"
 )
 
@@ -103,3 +106,11 @@ func forwardGPGAgent(ctx context.Context, stderr io.Writer, sshClient *gossh.Cli
 
 	return sshRemoteForward(ctx, stderr, sshClient, localAddr, remoteAddr)
 }
+
+// detachProcess starts cmd without a console, so that it keeps running after
+// the console it was started from is closed.
+func detachProcess(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		CreationFlags: windows.CREATE_NEW_PROCESS_GROUP | windows.DETACHED_PROCESS,
+	}
+}
@@ -0,0 +1,556 @@
+package cli
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/google/uuid"
+	"github.com/mattn/go-isatty"
+	"golang.org/x/term"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/sloghuman"
+
+	"github.com/coder/coder/v2/cli/clibase"
+	"github.com/coder/coder/v2/cli/config"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/vpn"
+	"github.com/coder/retry"
+)
+
+const (
+	// sshPersistStartTimeout is how long "coder ssh --persist" waits for the
+	// process that keeps the connection open to start serving it.
+	sshPersistStartTimeout = 30 * time.Second
+	// reconnectingPTYPingInterval is how often the connection to the agent
+	// is checked while attached to a reconnecting PTY.
+	reconnectingPTYPingInterval = 5 * time.Second
+	// reconnectingPTYMaxPingFailures is how many checks in a row must fail
+	// before the connection is considered lost.
+	reconnectingPTYMaxPingFailures = 3
+	// reconnectingPTYReconnectTimeout matches how long agents keep
+	// reconnecting PTYs alive without connections by default.
+	reconnectingPTYReconnectTimeout = 5 * time.Minute
+	// reconnectingPTYExitTimeout is how long the agent is given to report
+	// the exit code of a shell that exited.
+	reconnectingPTYExitTimeout = 10 * time.Second
+)
+
+// sshPersist keeps a connection to the agent of a workspace open for
+// invocations of "coder ssh --persist" to share, until it's been unused for
+// the idle timeout or the workspace stops. It's started in the background by
+// the first invocation, like the master of an OpenSSH ControlMaster.
+func (r *RootCmd) sshPersist() *clibase.Cmd {
+	var idleTimeout time.Duration
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:    "ssh-persist <workspace>",
+		Short:  `Keep a connection to a workspace open for "coder ssh --persist"`,
+		Hidden: true,
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx, stop := signal.NotifyContext(inv.Context(), InterruptSignals...)
+			defer stop()
+
+			logger := slog.Make(sloghuman.Sink(inv.Stderr))
+			if r.verbose {
+				logger = logger.Leveled(slog.LevelDebug)
+			}
+
+			me, err := client.User(ctx, codersdk.Me)
+			if err != nil {
+				return xerrors.Errorf("get user: %w", err)
+			}
+			basePath := r.sshPersistPath(client, me.ID, inv.Args[0])
+			err = os.MkdirAll(filepath.Dir(basePath), 0o700)
+			if err != nil {
+				return xerrors.Errorf("create directory: %w", err)
+			}
+			// Parallel invocations of "coder ssh --persist" may start more
+			// than one of us, but only one serves the socket.
+			lock := flock.New(basePath + ".lock")
+			locked, err := lock.TryLock()
+			if err != nil {
+				return xerrors.Errorf("lock %q: %w", lock.Path(), err)
+			}
+			if !locked {
+				logger.Info(ctx, "another process is already serving the connection")
+				return nil
+			}
+			defer func() {
+				_ = lock.Unlock()
+			}()
+
+			_, workspaceAgent, err := getWorkspaceAndAgent(ctx, inv, client, codersdk.Me, inv.Args[0])
+			if err != nil {
+				return err
+			}
+			daemon, err := vpn.New(ctx, vpn.Options{
+				Client:         client,
+				Logger:         logger,
+				BlockEndpoints: r.disableDirect,
+				AgentID:        workspaceAgent.ID,
+			})
+			if err != nil {
+				return xerrors.Errorf("connect to workspace: %w", err)
+			}
+			defer daemon.Close()
+			if len(daemon.Status().Agents) == 0 {
+				return xerrors.Errorf("agent %q isn't running", workspaceAgent.Name)
+			}
+
+			socketPath := basePath + ".sock"
+			// A previous process that didn't exit cleanly leaves its socket
+			// behind.
+			err = os.Remove(socketPath)
+			if err != nil && !os.IsNotExist(err) {
+				return xerrors.Errorf("remove stale socket: %w", err)
+			}
+			listener, err := net.Listen("unix", socketPath)
+			if err != nil {
+				return xerrors.Errorf("listen on %q: %w", socketPath, err)
+			}
+			defer listener.Close()
+			// Requests on the socket aren't authenticated.
+			err = os.Chmod(socketPath, 0o600)
+			if err != nil {
+				return xerrors.Errorf("chmod socket: %w", err)
+			}
+			serveErr := make(chan error, 1)
+			go func() {
+				serveErr <- daemon.ServeSocket(listener)
+			}()
+			logger.Info(ctx, "serving connection", slog.F("agent_id", workspaceAgent.ID), slog.F("socket", socketPath))
+
+			ticker := time.NewTicker(time.Second)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return nil
+				case err := <-serveErr:
+					return err
+				case <-ticker.C:
+				}
+				if len(daemon.Status().Agents) == 0 {
+					logger.Info(ctx, "workspace stopped")
+					return nil
+				}
+				if daemon.Idle() >= idleTimeout {
+					logger.Info(ctx, "connection unused for the idle timeout", slog.F("idle_timeout", idleTimeout))
+					return nil
+				}
+			}
+		},
+	}
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag:        "idle-timeout",
+			Description: "Close the connection after it's been unused for this long.",
+			Default:     "10m",
+			Value:       clibase.DurationOf(&idleTimeout),
+		},
+	}
+	return cmd
+}
+
+// sshPersistPath returns the path without extension of the files of the
+// shared connection to a workspace. Connections are shared between
+// invocations for the same deployment, user, workspace argument and
+// networking flags, so that sharing doesn't require looking up the workspace.
+// The workspace argument is relative to the user, who may be logged in to
+// another account since the connection was opened.
+func (r *RootCmd) sshPersistPath(client *codersdk.Client, userID uuid.UUID, workspace string) string {
+	hash := sha256.Sum256([]byte(strings.Join([]string{
+		client.URL.String(),
+		userID.String(),
+		strings.ToLower(workspace),
+		strconv.FormatBool(r.disableDirect),
+	}, "\n")))
+	// Unix socket paths are limited to about 100 characters.
+	return filepath.Join(r.createConfig().SSHPersistDir(), hex.EncodeToString(hash[:8]))
+}
+
+// sshPersistDialer returns a dialer for the agent of the workspace if a
+// shared connection to it is open.
+func (r *RootCmd) sshPersistDialer(ctx context.Context, client *codersdk.Client, userID uuid.UUID, workspace string) (agentDialer, bool) {
+	socketClient := &vpn.Client{SocketPath: r.sshPersistPath(client, userID, workspace) + ".sock"}
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	status, err := socketClient.Status(ctx)
+	if err != nil || status.URL != client.URL.String() || len(status.Agents) != 1 {
+		return nil, false
+	}
+	return &vpnAgentDialer{client: socketClient, agentID: status.Agents[0].ID}, true
+}
+
+// startSSHPersist starts "coder ssh-persist" in the background and waits for
+// it to share its connection to the workspace.
+func (r *RootCmd) startSSHPersist(ctx context.Context, inv *clibase.Invocation, client *codersdk.Client, userID uuid.UUID, workspace string, idleTimeout time.Duration) (agentDialer, error) {
+	basePath := r.sshPersistPath(client, userID, workspace)
+	err := os.MkdirAll(filepath.Dir(basePath), 0o700)
+	if err != nil {
+		return nil, xerrors.Errorf("create directory: %w", err)
+	}
+	executablePath, err := os.Executable()
+	if err != nil {
+		return nil, xerrors.Errorf("get executable: %w", err)
+	}
+	args := []string{
+		"ssh-persist",
+		"--" + config.FlagName, string(r.createConfig()),
+		"--idle-timeout", idleTimeout.String(),
+	}
+	if r.disableDirect {
+		args = append(args, "--"+varDisableDirect)
+	}
+	for _, header := range r.header {
+		args = append(args, "--"+varHeader, header)
+	}
+	if r.headerCommand != "" {
+		args = append(args, "--"+varHeaderCommand, r.headerCommand)
+	}
+	args = append(args, workspace)
+
+	logPath := basePath + ".log"
+	logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, xerrors.Errorf("open %q: %w", logPath, err)
+	}
+	defer logFile.Close()
+
+	//nolint:gosec // The arguments are ours.
+	cmd := exec.Command(executablePath, args...)
+	environ := inv.Environ
+	// The session token is passed through the environment so that it
+	// doesn't show up in the process list.
+	environ.Set(envURL, client.URL.String())
+	environ.Set(envSessionToken, client.SessionToken())
+	environ.Set(envNoVersionCheck, "true")
+	cmd.Env = environ.ToOS()
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	// The connection outlives this invocation.
+	detachProcess(cmd)
+	err = cmd.Start()
+	if err != nil {
+		return nil, xerrors.Errorf("start %q: %w", executablePath, err)
+	}
+	go func() {
+		_ = cmd.Wait()
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, sshPersistStartTimeout)
+	defer cancel()
+	for retrier := retry.New(50*time.Millisecond, time.Second); retrier.Wait(ctx); {
+		dialer, ok := r.sshPersistDialer(ctx, client, userID, workspace)
+		if ok {
+			return dialer, nil
+		}
+	}
+	return nil, xerrors.Errorf("persistent connection didn't start in time, see %q", logPath)
+}
+
+// dialReconnectingPTY connects to the reconnecting PTY with the ID of init,
+// starting it if it doesn't exist.
+func dialReconnectingPTY(ctx context.Context, dialer agentDialer, init codersdk.WorkspaceAgentReconnectingPTYInit) (net.Conn, error) {
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort("localhost", strconv.Itoa(codersdk.WorkspaceAgentReconnectingPTYPort)))
+	if err != nil {
+		return nil, xerrors.Errorf("connect reconnecting PTY: %w", err)
+	}
+	err = codersdk.WriteReconnectingPTYInit(conn, init)
+	if err != nil {
+		_ = conn.Close()
+		return nil, xerrors.Errorf("write init: %w", err)
+	}
+	return conn, nil
+}
+
+// reconnectingPTYSession runs a shell in a reconnecting PTY of the agent, and
+// reattaches to it when the connection to the agent is lost. The agent keeps
+// the shell running while no one is attached, and replays its recent output
+// on reattach.
+type reconnectingPTYSession struct {
+	dialer agentDialer
+	logger slog.Logger
+	id     uuid.UUID
+	stdout io.Writer
+	stderr io.Writer
+	// size returns the size of the local terminal.
+	size         func() (height, width uint16)
+	windowChange <-chan os.Signal
+
+	// input is read from stdin once, so that input isn't lost while
+	// reconnecting. It's nil after stdin is closed.
+	input <-chan []byte
+	// pending is input that wasn't written before the connection was
+	// lost.
+	pending []byte
+}
+
+// runReconnectingPTY runs an interactive shell in a reconnecting PTY until it
+// exits, and returns an error if it exited with a non-zero exit code.
+func runReconnectingPTY(ctx context.Context, inv *clibase.Invocation, dialer agentDialer, logger slog.Logger) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	session := &reconnectingPTYSession{
+		dialer: dialer,
+		logger: logger,
+		id:     uuid.New(),
+		stdout: inv.Stdout,
+		stderr: inv.Stderr,
+		size: func() (uint16, uint16) {
+			return 128, 128
+		},
+	}
+	stdoutFile, validOut := inv.Stdout.(*os.File)
+	stdinFile, validIn := inv.Stdin.(*os.File)
+	if validOut && validIn && isatty.IsTerminal(stdoutFile.Fd()) {
+		state, err := term.MakeRaw(int(stdinFile.Fd()))
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = term.Restore(int(stdinFile.Fd()), state)
+		}()
+		session.windowChange = listenWindowSize(ctx)
+		session.size = func() (uint16, uint16) {
+			width, height, err := term.GetSize(int(stdoutFile.Fd()))
+			if err != nil {
+				return 128, 128
+			}
+			return uint16(height), uint16(width)
+		}
+	}
+
+	input := make(chan []byte)
+	session.input = input
+	go func() {
+		defer close(input)
+		for {
+			buf := make([]byte, 4096)
+			n, err := inv.Stdin.Read(buf)
+			if n > 0 {
+				select {
+				case input <- buf[:n]:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	return session.run(ctx)
+}
+
+func (s *reconnectingPTYSession) run(ctx context.Context) error {
+	height, width := s.size()
+	conn, err := dialReconnectingPTY(ctx, s.dialer, codersdk.WorkspaceAgentReconnectingPTYInit{
+		ID:     s.id,
+		Height: height,
+		Width:  width,
+	})
+	if err != nil {
+		return err
+	}
+	for {
+		lost := s.attach(ctx, conn)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if !lost {
+			code, err := s.exitCode(ctx)
+			if err != nil {
+				// Agents that predate reporting exit codes don't.
+				s.logger.Debug(ctx, "get exit code of reconnecting PTY", slog.Error(err))
+				return nil
+			}
+			if code != 0 {
+				return xerrors.Errorf("session ended: process exited with status %d", code)
+			}
+			return nil
+		}
+
+		_, _ = fmt.Fprint(s.stderr, "\r\nConnection to the workspace lost, reconnecting...\r\n")
+		conn, err = s.reconnect(ctx)
+		if err != nil {
+			return err
+		}
+		// The agent replays the recent output of the shell, which is
+		// drawn on a clean screen.
+		_, _ = fmt.Fprint(s.stdout, "\x1b[H\x1b[2J\x1b[3J")
+	}
+}
+
+// reconnect dials the reconnecting PTY until it succeeds, or the agent has
+// likely closed it.
+func (s *reconnectingPTYSession) reconnect(ctx context.Context) (net.Conn, error) {
+	reconnectCtx, cancel := context.WithTimeout(ctx, reconnectingPTYReconnectTimeout)
+	defer cancel()
+	var err error
+	for retrier := retry.New(time.Second, 10*time.Second); retrier.Wait(reconnectCtx); {
+		height, width := s.size()
+		var conn net.Conn
+		conn, err = dialReconnectingPTY(reconnectCtx, s.dialer, codersdk.WorkspaceAgentReconnectingPTYInit{
+			ID:     s.id,
+			Height: height,
+			Width:  width,
+		})
+		if err == nil {
+			return conn, nil
+		}
+		s.logger.Debug(ctx, "reconnect to reconnecting PTY", slog.Error(err))
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	return nil, xerrors.Errorf("reconnect to workspace: %w", err)
+}
+
+// attach pipes the terminal to conn until the shell exits or the connection
+// is lost, and reports whether it was lost.
+func (s *reconnectingPTYSession) attach(ctx context.Context, conn net.Conn) bool {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var lost atomic.Bool
+	go func() {
+		<-ctx.Done()
+		_ = conn.Close()
+	}()
+
+	// Reads from the connection block while the network is down, so the
+	// agent is checked on the side.
+	go func() {
+		ticker := time.NewTicker(reconnectingPTYPingInterval)
+		defer ticker.Stop()
+		failures := 0
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			err := s.ping(ctx)
+			if err == nil {
+				failures = 0
+				continue
+			}
+			failures++
+			s.logger.Debug(ctx, "ping agent", slog.F("failures", failures), slog.Error(err))
+			if failures >= reconnectingPTYMaxPingFailures {
+				lost.Store(true)
+				cancel()
+				return
+			}
+		}
+	}()
+
+	inputDone := make(chan struct{})
+	go func() {
+		defer close(inputDone)
+		encoder := json.NewEncoder(conn)
+		for {
+			if s.pending != nil {
+				err := encoder.Encode(codersdk.ReconnectingPTYRequest{Data: string(s.pending)})
+				if err != nil {
+					lost.Store(true)
+					cancel()
+					return
+				}
+				s.pending = nil
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case data, ok := <-s.input:
+				if !ok {
+					s.input = nil
+					continue
+				}
+				s.pending = data
+			case <-s.windowChange:
+				height, width := s.size()
+				err := encoder.Encode(codersdk.ReconnectingPTYRequest{Height: height, Width: width})
+				if err != nil {
+					lost.Store(true)
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	_, err := io.Copy(s.stdout, conn)
+	if err != nil && !lost.Load() && ctx.Err() == nil {
+		s.logger.Debug(ctx, "read reconnecting PTY", slog.Error(err))
+		lost.Store(true)
+	}
+	cancel()
+	<-inputDone
+	return lost.Load()
+}
+
+// exitCode asks the agent for the exit code of the shell once it has exited.
+func (s *reconnectingPTYSession) exitCode(ctx context.Context) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, reconnectingPTYExitTimeout)
+	defer cancel()
+	client := &http.Client{
+		Transport: &http.Transport{
+			DisableKeepAlives: true,
+			DialContext:       s.dialer.DialContext,
+		},
+	}
+	host := net.JoinHostPort("localhost", strconv.Itoa(codersdk.WorkspaceAgentHTTPAPIServerPort))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("http://%s/api/v0/reconnecting-pty/%s/exit", host, s.id), nil)
+	if err != nil {
+		return 0, err
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return 0, codersdk.ReadBodyAsError(res)
+	}
+	var exit codersdk.WorkspaceAgentReconnectingPTYExit
+	err = json.NewDecoder(res.Body).Decode(&exit)
+	if err != nil {
+		return 0, xerrors.Errorf("decode exit: %w", err)
+	}
+	return exit.ExitCode, nil
+}
+
+// ping checks that the agent can be reached by connecting to it.
+func (s *reconnectingPTYSession) ping(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, reconnectingPTYPingInterval)
+	defer cancel()
+	conn, err := s.dialer.DialContext(ctx, "tcp", net.JoinHostPort("localhost", strconv.Itoa(codersdk.WorkspaceAgentHTTPAPIServerPort)))
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"time"
 
+	"github.com/google/uuid"
 	"golang.org/x/exp/slices"
 	"golang.org/x/xerrors"
 
@@ -22,6 +23,14 @@ func (r *RootCmd) tokens() *clibase.Cmd {
 				Description: "Create a token for automation",
 				Command:     "coder tokens create",
 			},
+			example{
+				Description: "Create a token that can only start one workspace",
+				Command:     "coder tokens create --scope workspace:start --allow <workspace-id> --allow <template-id>",
+			},
+			example{
+				Description: "Create a token for a service account owned by one of your groups",
+				Command:     "coder tokens create --user ci-bot",
+			},
 			example{
 				Description: "List your tokens",
 				Command:     "coder tokens ls",
@@ -48,6 +57,9 @@ func (r *RootCmd) createToken() *clibase.Cmd {
 	var (
 		tokenLifetime time.Duration
 		name          string
+		scopes        []string
+		allowList     []string
+		user          string
 	)
 	client := new(codersdk.Client)
 	cmd := &clibase.Cmd{
@@ -58,10 +70,22 @@ func (r *RootCmd) createToken() *clibase.Cmd {
 			r.InitClient(client),
 		),
 		Handler: func(inv *clibase.Invocation) error {
-			res, err := client.CreateToken(inv.Context(), codersdk.Me, codersdk.CreateTokenRequest{
+			req := codersdk.CreateTokenRequest{
 				Lifetime:  tokenLifetime,
 				TokenName: name,
-			})
+			}
+			for _, scope := range scopes {
+				req.Scopes = append(req.Scopes, codersdk.APIKeyScope(scope))
+			}
+			for _, allow := range allowList {
+				id, err := uuid.Parse(allow)
+				if err != nil {
+					return xerrors.Errorf("parse allow list ID %q: %w", allow, err)
+				}
+				req.AllowList = append(req.AllowList, id)
+			}
+
+			res, err := client.CreateToken(inv.Context(), user, req)
 			if err != nil {
 				return xerrors.Errorf("create tokens: %w", err)
 			}
@@ -87,6 +111,25 @@ func (r *RootCmd) createToken() *clibase.Cmd {
 			Description:   "Specify a human-readable name.",
 			Value:         clibase.StringOf(&name),
 		},
+		{
+			Flag:        "scope",
+			Env:         "CODER_TOKEN_SCOPE",
+			Description: "Limit the token to fine-grained scopes. Can be specified multiple times. One of: template:push, template:read, user:read, workspace:read, workspace:start.",
+			Value:       clibase.StringArrayOf(&scopes),
+		},
+		{
+			Flag:        "allow",
+			Env:         "CODER_TOKEN_ALLOW",
+			Description: "Limit the token to the given workspace or template IDs. Requires --scope. Can be specified multiple times.",
+			Value:       clibase.StringArrayOf(&allowList),
+		},
+		{
+			Flag:        "user",
+			Env:         "CODER_TOKEN_USER",
+			Description: "Create the token for another user, such as a service account owned by one of your groups.",
+			Default:     codersdk.Me,
+			Value:       clibase.StringOf(&user),
+		},
 	}
 
 	return cmd
@@ -0,0 +1,214 @@
+//go:build !slim
+
+package cli
+
+import (
+	"context"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os/signal"
+	"strconv"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/sloghuman"
+	"github.com/coder/coder/v2/cli/clibase"
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/coderd/devtunnel"
+	"github.com/coder/wgtunnel/tunneld"
+)
+
+const tunnelServerDescriptionLong = `Runs a tunnel server that speaks the same wireguard-based protocol as the
+tunnels hosted by Coder. Point "coder server" at it with --wg-tunnel-host or
+$WGTUNNEL_HOST to use it instead of the hosted tunnels.
+Tunnels are served on subdomains of the base URL, which clients always connect
+to over HTTPS. Serve it with a certificate that is valid for the host of the
+base URL and its subdomains, or behind a proxy that terminates TLS.
+`
+
+func (r *RootCmd) tunnelServer() *clibase.Cmd {
+	var (
+		address           string
+		baseURL           clibase.URL
+		tlsCertFile       string
+		tlsKeyFile        string
+		wireguardEndpoint string
+		wireguardPort     int64
+		wireguardKeyFile  string
+		wireguardMTU      int64
+	)
+	cmd := &clibase.Cmd{
+		Use:   "tunnel-server",
+		Short: "Run a self-hosted tunnel server for deployments without an access URL",
+		Long: tunnelServerDescriptionLong + "\n" +
+			formatExamples(
+				example{
+					Description: "Run a tunnel server for tunnels under tunnel.example.com",
+					Command:     "coder tunnel-server --base-url https://tunnel.example.com --wireguard-endpoint tunnel.example.com:55551 --tls-cert-file cert.pem --tls-key-file key.pem",
+				},
+			),
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(0),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx, cancel := context.WithCancel(inv.Context())
+			defer cancel()
+
+			if baseURL.Scheme != "https" {
+				return xerrors.Errorf("--base-url must be an https URL, clients always connect to the tunnel server over HTTPS: %q", baseURL.String())
+			}
+			if (tlsCertFile == "") != (tlsKeyFile == "") {
+				return xerrors.New("--tls-cert-file and --tls-key-file must be set together")
+			}
+			_, endpointPort, err := net.SplitHostPort(wireguardEndpoint)
+			if err != nil {
+				return xerrors.Errorf("--wireguard-endpoint %q must be in the form host:port: %w", wireguardEndpoint, err)
+			}
+			if wireguardPort == 0 {
+				wireguardPort, err = strconv.ParseInt(endpointPort, 10, 64)
+				if err != nil {
+					return xerrors.Errorf("parse port of --wireguard-endpoint %q: %w", wireguardEndpoint, err)
+				}
+			}
+			if wireguardPort < 1 || wireguardPort > 65535 {
+				return xerrors.Errorf("--wireguard-port must be between 1 and 65535, got %d", wireguardPort)
+			}
+			if wireguardKeyFile == "" {
+				wireguardKeyFile = string(r.createConfig().TunnelServerKey())
+			}
+
+			logger := slog.Make(sloghuman.Sink(inv.Stderr))
+			if r.verbose {
+				logger = logger.Leveled(slog.LevelDebug)
+			}
+
+			key, err := devtunnel.ReadOrGenerateServerKey(wireguardKeyFile)
+			if err != nil {
+				return xerrors.Errorf("read or generate wireguard key: %w", err)
+			}
+
+			api, err := tunneld.New(&tunneld.Options{
+				Log:               logger.Named("tunneld"),
+				BaseURL:           baseURL.Value(),
+				WireguardEndpoint: wireguardEndpoint,
+				WireguardPort:     uint16(wireguardPort),
+				WireguardKey:      key,
+				WireguardMTU:      int(wireguardMTU),
+			})
+			if err != nil {
+				return xerrors.Errorf("create tunnel server: %w", err)
+			}
+			defer api.Close()
+
+			// ReadHeaderTimeout is purposefully not enabled. It caused some
+			// issues with websockets over the dev tunnel.
+			// See: https://github.com/coder/coder/pull/3730
+			//nolint:gosec
+			httpServer := &http.Server{
+				// These errors are typically noise like "TLS: EOF". Vault does
+				// similar:
+				// https://github.com/hashicorp/vault/blob/e2490059d0711635e529a4efcbaa1b26998d6e1c/command/server.go#L2714
+				ErrorLog: log.New(io.Discard, "", 0),
+				Handler:  api.Router(),
+				BaseContext: func(_ net.Listener) context.Context {
+					return ctx
+				},
+			}
+			listener, err := net.Listen("tcp", address)
+			if err != nil {
+				return xerrors.Errorf("listen on %q: %w", address, err)
+			}
+			defer listener.Close()
+
+			cliui.Infof(inv.Stdout, "Started tunnel server at %s", listener.Addr().String())
+			cliui.Infof(inv.Stdout, "Tunnels will be served under %s", baseURL.String())
+
+			notifyCtx, notifyStop := signal.NotifyContext(ctx, InterruptSignals...)
+			defer notifyStop()
+
+			eg, egCtx := errgroup.WithContext(notifyCtx)
+			eg.Go(func() error {
+				var err error
+				if tlsCertFile != "" {
+					err = httpServer.ServeTLS(listener, tlsCertFile, tlsKeyFile)
+				} else {
+					err = httpServer.Serve(listener)
+				}
+				if err != nil && !xerrors.Is(err, http.ErrServerClosed) {
+					return xerrors.Errorf("serve: %w", err)
+				}
+				return nil
+			})
+			eg.Go(func() error {
+				<-egCtx.Done()
+				cliui.Infof(inv.Stdout, "Shutting down tunnel server...")
+
+				shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer shutdownCancel()
+				return httpServer.Shutdown(shutdownCtx)
+			})
+			return eg.Wait()
+		},
+	}
+
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag:        "address",
+			Env:         "CODER_TUNNEL_SERVER_ADDRESS",
+			Description: "HTTP listen address of the tunnel server. It serves both the API tunnels are created with and the traffic to the tunnels.",
+			Default:     "127.0.0.1:8080",
+			Value:       clibase.StringOf(&address),
+		},
+		{
+			Flag:        "base-url",
+			Env:         "CODER_TUNNEL_SERVER_BASE_URL",
+			Description: "The https URL of the tunnel server. Tunnels are served on subdomains of its host.",
+			Required:    true,
+			Value:       &baseURL,
+		},
+		{
+			Flag:        "tls-cert-file",
+			Env:         "CODER_TUNNEL_SERVER_TLS_CERT_FILE",
+			Description: "Path to a PEM-encoded certificate to serve the tunnel server with over TLS. It must be valid for the host of the base URL and its subdomains.",
+			Value:       clibase.StringOf(&tlsCertFile),
+		},
+		{
+			Flag:        "tls-key-file",
+			Env:         "CODER_TUNNEL_SERVER_TLS_KEY_FILE",
+			Description: "Path to the PEM-encoded private key of the TLS certificate.",
+			Value:       clibase.StringOf(&tlsKeyFile),
+		},
+		{
+			Flag:        "wireguard-endpoint",
+			Env:         "CODER_TUNNEL_SERVER_WIREGUARD_ENDPOINT",
+			Description: "The UDP address in the form host:port that tunnel clients connect to for wireguard connections.",
+			Required:    true,
+			Value:       clibase.StringOf(&wireguardEndpoint),
+		},
+		{
+			Flag:        "wireguard-port",
+			Env:         "CODER_TUNNEL_SERVER_WIREGUARD_PORT",
+			Description: "The UDP port the wireguard server listens on. Defaults to the port of the wireguard endpoint.",
+			Value:       clibase.Int64Of(&wireguardPort),
+		},
+		{
+			Flag:        "wireguard-key-file",
+			Env:         "CODER_TUNNEL_SERVER_WIREGUARD_KEY_FILE",
+			Description: "Path to the base64-encoded private key of the wireguard server. It's generated if it doesn't exist. Defaults to a file in the global config directory.",
+			Value:       clibase.StringOf(&wireguardKeyFile),
+		},
+		{
+			Flag:        "wireguard-mtu",
+			Env:         "CODER_TUNNEL_SERVER_WIREGUARD_MTU",
+			Description: "The MTU of the wireguard interface.",
+			Default:     strconv.Itoa(tunneld.DefaultWireguardMTU),
+			Value:       clibase.Int64Of(&wireguardMTU),
+		},
+	}
+	return cmd
+}
@@ -0,0 +1,21 @@
+//go:build slim
+
+package cli
+
+import (
+	"github.com/coder/coder/v2/cli/clibase"
+)
+
+func (*RootCmd) tunnelServer() *clibase.Cmd {
+	return &clibase.Cmd{
+		Use:   "tunnel-server",
+		Short: "Run a self-hosted tunnel server for deployments without an access URL",
+		// We accept RawArgs so all commands and flags are accepted.
+		RawArgs: true,
+		Hidden:  true,
+		Handler: func(inv *clibase.Invocation) error {
+			slimUnsupported(inv.Stderr, "tunnel-server")
+			return nil
+		},
+	}
+}
@@ -0,0 +1,51 @@
+package cli_test
+
+import (
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/pty/ptytest"
+)
+
+func TestTunnelServer(t *testing.T) {
+	t.Parallel()
+
+	t.Run("RequiresHTTPS", func(t *testing.T) {
+		t.Parallel()
+
+		inv, _ := clitest.New(t, "tunnel-server",
+			"--base-url", "http://tunnel.example.com",
+			"--wireguard-endpoint", "127.0.0.1:55551",
+		)
+		err := inv.Run()
+		require.ErrorContains(t, err, "must be an https URL")
+	})
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		// The wireguard server doesn't accept a listener, so pick a free port
+		// for it. This is prone to races, but good enough for a test.
+		conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+		require.NoError(t, err)
+		endpoint := conn.LocalAddr().String()
+		require.NoError(t, conn.Close())
+
+		keyFile := filepath.Join(t.TempDir(), "key")
+		inv, _ := clitest.New(t, "tunnel-server",
+			"--address", "127.0.0.1:0",
+			"--base-url", "https://tunnel.example.com",
+			"--wireguard-endpoint", endpoint,
+			"--wireguard-key-file", keyFile,
+		)
+		pty := ptytest.New(t).Attach(inv)
+		clitest.Start(t, inv)
+
+		pty.ExpectMatch("Started tunnel server")
+		require.FileExists(t, keyFile)
+	})
+}
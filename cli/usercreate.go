@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
 	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/v2/cli/clibase"
@@ -15,11 +16,13 @@ import (
 
 func (r *RootCmd) userCreate() *clibase.Cmd {
 	var (
-		email        string
-		username     string
-		password     string
-		disableLogin bool
-		loginType    string
+		email          string
+		username       string
+		password       string
+		disableLogin   bool
+		loginType      string
+		serviceAccount bool
+		ownerGroup     string
 	)
 	client := new(codersdk.Client)
 	cmd := &clibase.Cmd{
@@ -41,6 +44,11 @@ func (r *RootCmd) userCreate() *clibase.Cmd {
 					return err
 				}
 			}
+			if serviceAccount && email == "" {
+				// Service accounts never receive mail, but users must have
+				// a unique email address.
+				email = username + "@service-account.invalid"
+			}
 			if email == "" {
 				email, err = cliui.Prompt(inv, cliui.PromptOptions{
 					Text: "Email:",
@@ -60,12 +68,31 @@ func (r *RootCmd) userCreate() *clibase.Cmd {
 			if disableLogin && loginType != "" {
 				return xerrors.New("You cannot specify both --disable-login and --login-type")
 			}
+			if serviceAccount && (disableLogin || loginType != "") {
+				return xerrors.New("You cannot specify --service-account with --disable-login or --login-type")
+			}
+			if ownerGroup != "" && !serviceAccount {
+				return xerrors.New("--owner-group can only be specified with --service-account")
+			}
 			if disableLogin {
 				userLoginType = codersdk.LoginTypeNone
 			} else if loginType != "" {
 				userLoginType = codersdk.LoginType(loginType)
 			}
 
+			var ownerGroupID *uuid.UUID
+			if serviceAccount {
+				userLoginType = codersdk.LoginTypeServiceAccount
+				if ownerGroup == "" {
+					return xerrors.New("Service accounts must be owned by a group, specify one with --owner-group")
+				}
+				group, err := client.GroupByOrgAndName(inv.Context(), organization.ID, ownerGroup)
+				if err != nil {
+					return xerrors.Errorf("get owner group %q: %w", ownerGroup, err)
+				}
+				ownerGroupID = &group.ID
+			}
+
 			if password == "" && userLoginType == codersdk.LoginTypePassword {
 				// Generate a random password
 				password, err = cryptorand.StringCharset(cryptorand.Human, 20)
@@ -75,16 +102,25 @@ func (r *RootCmd) userCreate() *clibase.Cmd {
 			}
 
 			_, err = client.CreateUser(inv.Context(), codersdk.CreateUserRequest{
-				Email:          email,
-				Username:       username,
-				Password:       password,
-				OrganizationID: organization.ID,
-				UserLoginType:  userLoginType,
+				Email:                      email,
+				Username:                   username,
+				Password:                   password,
+				OrganizationID:             organization.ID,
+				UserLoginType:              userLoginType,
+				ServiceAccountOwnerGroupID: ownerGroupID,
 			})
 			if err != nil {
 				return err
 			}
 
+			if serviceAccount {
+				_, _ = fmt.Fprintln(inv.Stderr, `A new service account has been created!
+Members of the `+cliui.DefaultStyles.Field.Render(ownerGroup)+` group can create tokens for it with:
+
+  `+cliui.DefaultStyles.Code.Render("coder tokens create --user "+username))
+				return nil
+			}
+
 			authenticationMethod := ""
 			switch codersdk.LoginType(strings.ToLower(string(userLoginType))) {
 			case codersdk.LoginTypePassword:
@@ -95,6 +131,8 @@ func (r *RootCmd) userCreate() *clibase.Cmd {
 				authenticationMethod = `Login is authenticated through GitHub.`
 			case codersdk.LoginTypeOIDC:
 				authenticationMethod = `Login is authenticated through the configured OIDC provider.`
+			case codersdk.LoginTypeLDAP:
+				authenticationMethod = `Login is authenticated through the configured LDAP directory.`
 			}
 
 			_, _ = fmt.Fprintln(inv.Stderr, `A new user has been created!
@@ -143,11 +181,22 @@ Create a workspace  `+cliui.DefaultStyles.Code.Render("coder create")+`!`)
 			Description: fmt.Sprintf("Optionally specify the login type for the user. Valid values are: %s. "+
 				"Using 'none' prevents the user from authenticating and requires an API key/token to be generated by an admin.",
 				strings.Join([]string{
-					string(codersdk.LoginTypePassword), string(codersdk.LoginTypeNone), string(codersdk.LoginTypeGithub), string(codersdk.LoginTypeOIDC),
+					string(codersdk.LoginTypePassword), string(codersdk.LoginTypeNone), string(codersdk.LoginTypeGithub), string(codersdk.LoginTypeOIDC), string(codersdk.LoginTypeLDAP),
 				}, ", ",
 				)),
 			Value: clibase.StringOf(&loginType),
 		},
+		{
+			Flag: "service-account",
+			Description: "Create a service account for CI and automation. Service accounts cannot log in and are not counted against the licensed user limit. " +
+				"Members of the owner group create tokens for them instead.",
+			Value: clibase.BoolOf(&serviceAccount),
+		},
+		{
+			Flag:        "owner-group",
+			Description: "The name of the group that owns the service account. Required with --service-account.",
+			Value:       clibase.StringOf(&ownerGroup),
+		},
 	}
 	return cmd
 }
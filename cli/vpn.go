@@ -0,0 +1,277 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/sloghuman"
+	"github.com/coder/coder/v2/cli/clibase"
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/cryptorand"
+	"github.com/coder/coder/v2/vpn"
+)
+
+const vpnDescriptionLong = `Keeps a single connection to all of your running workspaces open, and exposes
+their agents as <agent>.<workspace>.<owner>.coder through a SOCKS5 and HTTP
+CONNECT proxy. "coder ssh" and "coder port-forward" reuse the connection while
+it's running instead of connecting to the workspace themselves.
+Clients of the proxy must authenticate with a password that is generated for
+every run. "coder vpn proxy-url" prints the URL of the proxy including it.
+Point a resolver for the "coder" domain at the DNS address to resolve the
+hostnames to the tailnet IPs of the agents, which the proxy accepts as well.
+`
+
+func (r *RootCmd) vpnCmd() *clibase.Cmd {
+	var (
+		dnsAddress   string
+		proxyAddress string
+	)
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "vpn",
+		Short: "Connect to all of your running workspaces over a single connection",
+		Long: vpnDescriptionLong + "\n" + formatExamples(
+			example{
+				Description: "Request a web server in a workspace through the proxy",
+				Command:     `curl --proxy "$(coder vpn proxy-url)" http://main.myworkspace.myuser.coder:8080`,
+			},
+		),
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(0),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx, stop := signal.NotifyContext(inv.Context(), InterruptSignals...)
+			defer stop()
+
+			socketPath := r.createConfig().VPNSocket()
+			socketClient := &vpn.Client{SocketPath: socketPath}
+			statusCtx, cancel := context.WithTimeout(ctx, time.Second)
+			status, err := socketClient.Status(statusCtx)
+			cancel()
+			if err == nil {
+				return xerrors.Errorf("coder vpn is already running for %s", status.URL)
+			}
+			// A previous daemon that didn't exit cleanly leaves its socket
+			// behind.
+			err = os.Remove(socketPath)
+			if err != nil && !os.IsNotExist(err) {
+				return xerrors.Errorf("remove stale socket: %w", err)
+			}
+
+			logger := slog.Make(sloghuman.Sink(inv.Stderr))
+			if r.verbose {
+				logger = logger.Leveled(slog.LevelDebug)
+			}
+			if r.disableDirect {
+				_, _ = fmt.Fprintln(inv.Stderr, "Direct connections disabled.")
+			}
+
+			daemon, err := vpn.New(ctx, vpn.Options{
+				Client:         client,
+				Logger:         logger,
+				BlockEndpoints: r.disableDirect,
+			})
+			if err != nil {
+				return xerrors.Errorf("start vpn: %w", err)
+			}
+			defer daemon.Close()
+
+			socketListener, err := net.Listen("unix", socketPath)
+			if err != nil {
+				return xerrors.Errorf("listen on %q: %w", socketPath, err)
+			}
+			defer socketListener.Close()
+			// Requests on the socket aren't authenticated.
+			err = os.Chmod(socketPath, 0o600)
+			if err != nil {
+				return xerrors.Errorf("chmod socket: %w", err)
+			}
+			// Anyone on the machine can connect to the proxy, so clients
+			// authenticate with a password of this run.
+			proxyPassword, err := cryptorand.String(32)
+			if err != nil {
+				return xerrors.Errorf("generate proxy password: %w", err)
+			}
+			proxyListener, err := net.Listen("tcp", proxyAddress)
+			if err != nil {
+				return xerrors.Errorf("listen on %q: %w", proxyAddress, err)
+			}
+			defer proxyListener.Close()
+			var dnsConn net.PacketConn
+			if dnsAddress != "" {
+				dnsConn, err = net.ListenPacket("udp", dnsAddress)
+				if err != nil {
+					return xerrors.Errorf("listen on %q: %w", dnsAddress, err)
+				}
+				defer dnsConn.Close()
+			}
+
+			cliui.Infof(inv.Stdout, "Connected to %d agent(s) of %s", len(daemon.Status().Agents), client.URL.String())
+			cliui.Infof(inv.Stdout, "Serving the proxy at %s", proxyListener.Addr().String())
+			if dnsConn != nil {
+				cliui.Infof(inv.Stdout, "Serving DNS for the %q domain at %s", vpn.HostnameSuffix, dnsConn.LocalAddr().String())
+			}
+
+			eg, egCtx := errgroup.WithContext(ctx)
+			eg.Go(func() error {
+				return daemon.ServeSocket(socketListener)
+			})
+			eg.Go(func() error {
+				return daemon.ServeProxy(proxyListener, proxyPassword)
+			})
+			if dnsConn != nil {
+				eg.Go(func() error {
+					return daemon.ServeDNS(dnsConn)
+				})
+			}
+			eg.Go(func() error {
+				<-egCtx.Done()
+				_ = socketListener.Close()
+				_ = proxyListener.Close()
+				if dnsConn != nil {
+					_ = dnsConn.Close()
+				}
+				return nil
+			})
+			<-egCtx.Done()
+			if ctx.Err() != nil {
+				_, _ = fmt.Fprintln(inv.Stderr, "\nReceived signal, closing all connections")
+			}
+			return eg.Wait()
+		},
+		Children: []*clibase.Cmd{
+			r.vpnStatus(),
+			r.vpnProxyURL(),
+		},
+	}
+
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag:        "dns-address",
+			Env:         "CODER_VPN_DNS_ADDRESS",
+			Description: "The UDP address to resolve the hostnames of agents on. Set to an empty string to disable.",
+			Default:     "127.0.0.1:5300", // 5353 is taken by mDNS on most machines.
+			Value:       clibase.StringOf(&dnsAddress),
+		},
+		{
+			Flag:        "proxy-address",
+			Env:         "CODER_VPN_PROXY_ADDRESS",
+			Description: "The address to serve the SOCKS5 and HTTP CONNECT proxy on.",
+			Default:     "127.0.0.1:1080",
+			Value:       clibase.StringOf(&proxyAddress),
+		},
+	}
+	return cmd
+}
+
+func (r *RootCmd) vpnStatus() *clibase.Cmd {
+	formatter := cliui.NewOutputFormatter(
+		cliui.TableFormat([]vpn.Agent{}, []string{"hostname", "workspace", "agent", "owner", "ip"}),
+		cliui.JSONFormat(),
+	)
+	cmd := &clibase.Cmd{
+		Use:   "status",
+		Short: "List the agents that coder vpn is connected to",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(0),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			socketClient := &vpn.Client{SocketPath: r.createConfig().VPNSocket()}
+			status, err := socketClient.Status(inv.Context())
+			if err != nil {
+				return xerrors.Errorf("coder vpn isn't running: %w", err)
+			}
+			out, err := formatter.Format(inv.Context(), status.Agents)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+func (r *RootCmd) vpnProxyURL() *clibase.Cmd {
+	return &clibase.Cmd{
+		Use:   "proxy-url",
+		Short: "Print the URL of the proxy of coder vpn, including its password",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(0),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			socketClient := &vpn.Client{SocketPath: r.createConfig().VPNSocket()}
+			status, err := socketClient.Status(inv.Context())
+			if err != nil {
+				return xerrors.Errorf("coder vpn isn't running: %w", err)
+			}
+			if status.ProxyURL == "" {
+				return xerrors.New("coder vpn isn't serving the proxy yet")
+			}
+			_, err = fmt.Fprintln(inv.Stdout, status.ProxyURL)
+			return err
+		},
+	}
+}
+
+// agentDialer dials ports of a workspace agent, either over a connection of
+// its own or through coder vpn.
+type agentDialer interface {
+	DialContext(ctx context.Context, network, addr string) (net.Conn, error)
+}
+
+// vpnAgentDialer dials an agent through a running coder vpn.
+type vpnAgentDialer struct {
+	client  *vpn.Client
+	agentID uuid.UUID
+}
+
+func (d *vpnAgentDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	if network != "tcp" {
+		return nil, xerrors.Errorf("coder vpn doesn't support network %q", network)
+	}
+	_, rawPort, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, xerrors.Errorf("split %q: %w", addr, err)
+	}
+	port, err := strconv.ParseUint(rawPort, 10, 16)
+	if err != nil {
+		return nil, xerrors.Errorf("parse port %q: %w", rawPort, err)
+	}
+	return d.client.DialAgent(ctx, d.agentID, uint16(port))
+}
+
+// vpnDialer returns a dialer for the agent if coder vpn is running for the
+// same deployment and is connected to the agent. Direct connections must be
+// allowed, since the daemon decides for itself whether to use them.
+func (r *RootCmd) vpnDialer(ctx context.Context, client *codersdk.Client, agentID uuid.UUID) (agentDialer, bool) {
+	if r.disableDirect {
+		return nil, false
+	}
+	socketClient := &vpn.Client{SocketPath: r.createConfig().VPNSocket()}
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	status, err := socketClient.Status(ctx)
+	if err != nil || status.URL != client.URL.String() {
+		return nil, false
+	}
+	for _, agent := range status.Agents {
+		if agent.ID == agentID {
+			return &vpnAgentDialer{client: socketClient, agentID: agentID}, true
+		}
+	}
+	return nil, false
+}
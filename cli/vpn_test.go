@@ -0,0 +1,104 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/proxy"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/agent"
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/pty/ptytest"
+	"github.com/coder/coder/v2/testutil"
+	"github.com/coder/coder/v2/vpn"
+)
+
+func TestVPN(t *testing.T) {
+	t.Parallel()
+
+	client, workspace, agentToken := setupWorkspaceForAgent(t, nil)
+	agentClient := agentsdk.New(client.URL)
+	agentClient.SetSessionToken(agentToken)
+	agentCloser := agent.New(agent.Options{
+		Client: agentClient,
+		Logger: slogtest.Make(t, nil).Named("agent"),
+	})
+	defer func() {
+		_ = agentCloser.Close()
+	}()
+	resources := coderdtest.AwaitWorkspaceAgents(t, client, workspace.ID)
+	workspaceAgent := resources[0].Agents[0]
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	inv, root := clitest.New(t, "vpn", "--proxy-address", "127.0.0.1:0", "--dns-address", "")
+	clitest.SetupConfig(t, client, root)
+	pty := ptytest.New(t).Attach(inv)
+	cmdDone := tGo(t, func() {
+		err := inv.WithContext(ctx).Run()
+		assert.NoError(t, err)
+	})
+	pty.ExpectMatch("Serving the proxy at ")
+	proxyAddress := strings.TrimSpace(pty.ReadLine(ctx))
+
+	// The agent is listed by "coder vpn status".
+	statusInv, _ := clitest.New(t, "vpn", "status", "--global-config", string(root), "--output", "json")
+	var stdout bytes.Buffer
+	statusInv.Stdout = &stdout
+	err := statusInv.WithContext(ctx).Run()
+	require.NoError(t, err)
+	var agents []vpn.Agent
+	err = json.Unmarshal(stdout.Bytes(), &agents)
+	require.NoError(t, err)
+	require.Len(t, agents, 1)
+	hostname := vpn.Hostname(workspaceAgent.Name, workspace.Name, workspace.OwnerName)
+	require.Equal(t, workspaceAgent.ID, agents[0].ID)
+	require.Equal(t, hostname, agents[0].Hostname)
+
+	// "coder vpn proxy-url" prints the proxy address with its password.
+	urlInv, _ := clitest.New(t, "vpn", "proxy-url", "--global-config", string(root))
+	stdout.Reset()
+	urlInv.Stdout = &stdout
+	err = urlInv.WithContext(ctx).Run()
+	require.NoError(t, err)
+	proxyURL, err := url.Parse(strings.TrimSpace(stdout.String()))
+	require.NoError(t, err)
+	require.Equal(t, proxyAddress, proxyURL.Host)
+	password, _ := proxyURL.User.Password()
+	require.NotEmpty(t, password)
+
+	// The proxy rejects connections without the password.
+	address := net.JoinHostPort(hostname, strconv.Itoa(codersdk.WorkspaceAgentSSHPort))
+	dialer, err := proxy.SOCKS5("tcp", proxyAddress, nil, proxy.Direct)
+	require.NoError(t, err)
+	_, err = dialer.(proxy.ContextDialer).DialContext(ctx, "tcp", address)
+	require.Error(t, err)
+
+	// The SSH server of the agent is reachable by hostname through the proxy.
+	dialer, err = proxy.SOCKS5("tcp", proxyAddress, &proxy.Auth{User: proxyURL.User.Username(), Password: password}, proxy.Direct)
+	require.NoError(t, err)
+	conn, err := dialer.(proxy.ContextDialer).DialContext(ctx, "tcp", address)
+	require.NoError(t, err)
+	defer conn.Close()
+	banner := make([]byte, len("SSH-2.0"))
+	_, err = io.ReadFull(conn, banner)
+	require.NoError(t, err)
+	require.Equal(t, "SSH-2.0", string(banner))
+
+	cancel()
+	<-cmdDone
+}
@@ -0,0 +1,262 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/clibase"
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/cli/config"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+const (
+	// workspaceProxyAuto selects the healthy region with the lowest latency,
+	// even if another one was remembered.
+	workspaceProxyAuto = "auto"
+	// workspaceProxyProbes is the number of latency checks sent to each
+	// region. The first one also establishes the connection, so the fastest
+	// is used.
+	workspaceProxyProbes       = 3
+	workspaceProxyProbeTimeout = 5 * time.Second
+	// workspaceProxyCacheTTL is how long the remembered region is used
+	// without listing the regions of the deployment again, so connecting
+	// doesn't wait for the selection every time.
+	workspaceProxyCacheTTL = time.Hour
+)
+
+// rememberedWorkspaceProxy is the region the CLI last connected to workspaces
+// through, as stored in the config directory.
+type rememberedWorkspaceProxy struct {
+	Region    codersdk.Region `json:"region"`
+	CheckedAt time.Time       `json:"checked_at"`
+}
+
+// workspaceProxy returns the region to connect to workspaces through. A region
+// given with --proxy is used as is, otherwise the remembered one is used while
+// it's healthy. If neither is, the healthy region with the lowest latency is
+// selected. The region is remembered in the config directory, and used without
+// checking the regions again for workspaceProxyCacheTTL.
+//
+// If the deployment doesn't list its regions, the zero Region is returned,
+// which connects through whatever region is closest to the agent.
+func (r *RootCmd) workspaceProxy(inv *clibase.Invocation, client *codersdk.Client) (codersdk.Region, error) {
+	ctx := inv.Context()
+	conf := r.createConfig()
+	remembered, err := readWorkspaceProxy(conf)
+	if err != nil {
+		return codersdk.Region{}, xerrors.Errorf("read remembered workspace proxy: %w", err)
+	}
+	name := r.proxy
+	if name == "" {
+		name = remembered.Region.Name
+	}
+	if name != "" && strings.EqualFold(name, remembered.Region.Name) && time.Since(remembered.CheckedAt) < workspaceProxyCacheTTL {
+		return remembered.Region, nil
+	}
+
+	regions, err := client.Regions(ctx)
+	if err != nil {
+		if r.proxy != "" && r.proxy != workspaceProxyAuto {
+			return codersdk.Region{}, xerrors.Errorf("list workspace proxies: %w", err)
+		}
+		return codersdk.Region{}, nil
+	}
+
+	if r.proxy == "" {
+		if region, ok := findRegion(regions, remembered.Region.Name); ok && region.Healthy {
+			return region, rememberWorkspaceProxy(conf, region)
+		}
+		name = workspaceProxyAuto
+	}
+
+	var region codersdk.Region
+	if name == workspaceProxyAuto {
+		var ok bool
+		region, ok = nearestRegion(ctx, client.HTTPClient, regions)
+		if !ok {
+			// Nothing answered, so there's nothing worth remembering.
+			region, _ = findRegion(regions, codersdk.PrimaryRegionName)
+			return region, nil
+		}
+	} else {
+		var ok bool
+		region, ok = findRegion(regions, name)
+		if !ok {
+			names := make([]string, 0, len(regions))
+			for _, region := range regions {
+				names = append(names, region.Name)
+			}
+			return codersdk.Region{}, xerrors.Errorf("workspace proxy %q does not exist, available proxies are: %s", name, strings.Join(names, ", "))
+		}
+		if !region.Healthy {
+			cliui.Warnf(inv.Stderr, "Workspace proxy %q is unhealthy, connections through it may fail.", region.Name)
+		}
+	}
+	return region, rememberWorkspaceProxy(conf, region)
+}
+
+// readWorkspaceProxy reads the remembered region. Older versions only stored
+// its name, which is checked against the regions again before it's used.
+func readWorkspaceProxy(conf config.Root) (rememberedWorkspaceProxy, error) {
+	raw, err := conf.WorkspaceProxy().Read()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return rememberedWorkspaceProxy{}, nil
+		}
+		return rememberedWorkspaceProxy{}, err
+	}
+	var remembered rememberedWorkspaceProxy
+	if json.Unmarshal([]byte(raw), &remembered) != nil {
+		return rememberedWorkspaceProxy{Region: codersdk.Region{Name: strings.TrimSpace(raw)}}, nil
+	}
+	return remembered, nil
+}
+
+func rememberWorkspaceProxy(conf config.Root, region codersdk.Region) error {
+	data, err := json.Marshal(rememberedWorkspaceProxy{
+		Region:    region,
+		CheckedAt: time.Now(),
+	})
+	if err != nil {
+		return xerrors.Errorf("marshal workspace proxy: %w", err)
+	}
+	err = conf.WorkspaceProxy().Write(string(data))
+	if err != nil {
+		return xerrors.Errorf("remember workspace proxy: %w", err)
+	}
+	return nil
+}
+
+// workspaceProxyURL returns the URL to coordinate connections to agents through
+// when connecting via the region. It's nil for the primary, which connections
+// coordinate through anyway.
+func workspaceProxyURL(region codersdk.Region) *url.URL {
+	if region.Name == "" || strings.EqualFold(region.Name, codersdk.PrimaryRegionName) {
+		return nil
+	}
+	u, err := url.Parse(region.PathAppURL)
+	if err != nil || u.Host == "" {
+		return nil
+	}
+	return u
+}
+
+func findRegion(regions []codersdk.Region, name string) (codersdk.Region, bool) {
+	for _, region := range regions {
+		if name != "" && strings.EqualFold(region.Name, name) {
+			return region, true
+		}
+	}
+	return codersdk.Region{}, false
+}
+
+// nearestRegion returns the healthy region with the lowest latency. It returns
+// false if no region answered.
+func nearestRegion(ctx context.Context, httpClient *http.Client, regions []codersdk.Region) (codersdk.Region, bool) {
+	ctx, cancel := context.WithTimeout(ctx, workspaceProxyProbeTimeout)
+	defer cancel()
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		nearest   codersdk.Region
+		latency   time.Duration
+		responded bool
+	)
+	for _, region := range regions {
+		if !region.Healthy || region.PathAppURL == "" {
+			continue
+		}
+		region := region
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d, err := regionLatency(ctx, httpClient, region)
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if !responded || d < latency {
+				nearest, latency, responded = region, d, true
+			}
+		}()
+	}
+	wg.Wait()
+	return nearest, responded
+}
+
+// regionLatency measures the round trip time to the latency check endpoint of
+// a region, which is served by coderd and every workspace proxy.
+func regionLatency(ctx context.Context, httpClient *http.Client, region codersdk.Region) (time.Duration, error) {
+	u, err := url.Parse(region.PathAppURL)
+	if err != nil {
+		return 0, xerrors.Errorf("parse path app url: %w", err)
+	}
+	u = u.JoinPath("latency-check")
+
+	var fastest time.Duration
+	for i := 0; i < workspaceProxyProbes; i++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+		if err != nil {
+			return 0, xerrors.Errorf("create request: %w", err)
+		}
+		start := time.Now()
+		res, err := httpClient.Do(req)
+		if err != nil {
+			return 0, xerrors.Errorf("check latency: %w", err)
+		}
+		_, _ = io.Copy(io.Discard, res.Body)
+		_ = res.Body.Close()
+		d := time.Since(start)
+		if res.StatusCode != http.StatusOK {
+			return 0, xerrors.Errorf("unexpected status code %d", res.StatusCode)
+		}
+		if i == 0 || d < fastest {
+			fastest = d
+		}
+	}
+	return fastest, nil
+}
+
+// workspaceAppURL returns the URL to open an app through the given region,
+// like the dashboard does. It's empty if the app can't be reached through it.
+func workspaceAppURL(region codersdk.Region, workspace codersdk.Workspace, agent codersdk.WorkspaceAgent, app codersdk.WorkspaceApp) string {
+	if app.External {
+		return app.URL
+	}
+	slug := app.Slug
+	if slug == "" {
+		slug = app.DisplayName
+	}
+	if app.Subdomain {
+		if region.WildcardHostname == "" {
+			return ""
+		}
+		scheme := "https"
+		if u, err := url.Parse(region.PathAppURL); err == nil && u.Scheme != "" {
+			scheme = u.Scheme
+		}
+		subdomain := fmt.Sprintf("%s--%s--%s--%s", slug, agent.Name, workspace.Name, workspace.OwnerName)
+		return fmt.Sprintf("%s://%s/", scheme, strings.Replace(region.WildcardHostname, "*", subdomain, 1))
+	}
+	base := strings.TrimSuffix(region.PathAppURL, "/")
+	if base == "" {
+		return ""
+	}
+	if app.Command != "" {
+		return fmt.Sprintf("%s/@%s/%s.%s/terminal?command=%s", base, workspace.OwnerName, workspace.Name, agent.Name, url.QueryEscape(app.Command))
+	}
+	return fmt.Sprintf("%s/@%s/%s.%s/apps/%s/", base, workspace.OwnerName, workspace.Name, agent.Name, url.PathEscape(slug))
+}
@@ -0,0 +1,191 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clibase"
+	"github.com/coder/coder/v2/cli/config"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceAppURL(t *testing.T) {
+	t.Parallel()
+
+	region := codersdk.Region{
+		Name:             "eu",
+		PathAppURL:       "https://eu.example.com/",
+		WildcardHostname: "*.eu-apps.example.com",
+	}
+	workspace := codersdk.Workspace{Name: "dev", OwnerName: "alice"}
+	agent := codersdk.WorkspaceAgent{Name: "main"}
+
+	for _, tc := range []struct {
+		Name     string
+		Region   codersdk.Region
+		App      codersdk.WorkspaceApp
+		Expected string
+	}{
+		{
+			Name:     "Path",
+			Region:   region,
+			App:      codersdk.WorkspaceApp{Slug: "code"},
+			Expected: "https://eu.example.com/@alice/dev.main/apps/code/",
+		},
+		{
+			Name:     "Subdomain",
+			Region:   region,
+			App:      codersdk.WorkspaceApp{Slug: "code", Subdomain: true},
+			Expected: "https://code--main--dev--alice.eu-apps.example.com/",
+		},
+		{
+			Name:     "SubdomainWithoutWildcard",
+			Region:   codersdk.Region{PathAppURL: "http://eu.example.com"},
+			App:      codersdk.WorkspaceApp{Slug: "code", Subdomain: true},
+			Expected: "",
+		},
+		{
+			Name:     "Command",
+			Region:   region,
+			App:      codersdk.WorkspaceApp{Slug: "top", Command: "top -d 1"},
+			Expected: "https://eu.example.com/@alice/dev.main/terminal?command=top+-d+1",
+		},
+		{
+			Name:     "External",
+			Region:   region,
+			App:      codersdk.WorkspaceApp{Slug: "docs", External: true, URL: "https://docs.example.com"},
+			Expected: "https://docs.example.com",
+		},
+	} {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.Expected, workspaceAppURL(tc.Region, workspace, agent, tc.App))
+		})
+	}
+}
+
+func TestNearestRegion(t *testing.T) {
+	t.Parallel()
+
+	latencyCheck := func(delay time.Duration) *httptest.Server {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/latency-check", r.URL.Path)
+			time.Sleep(delay)
+			w.WriteHeader(http.StatusOK)
+		}))
+		t.Cleanup(srv.Close)
+		return srv
+	}
+	fast := latencyCheck(0)
+	slow := latencyCheck(100 * time.Millisecond)
+	unhealthy := latencyCheck(0)
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	t.Cleanup(broken.Close)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitShort)
+	defer cancel()
+
+	region, ok := nearestRegion(ctx, http.DefaultClient, []codersdk.Region{
+		{Name: "primary", Healthy: true, PathAppURL: slow.URL},
+		{Name: "fast", Healthy: true, PathAppURL: fast.URL},
+		{Name: "unhealthy", Healthy: false, PathAppURL: unhealthy.URL},
+		{Name: "broken", Healthy: true, PathAppURL: broken.URL},
+		{Name: "nourl", Healthy: true},
+	})
+	require.True(t, ok)
+	require.Equal(t, "fast", region.Name)
+
+	_, ok = nearestRegion(ctx, http.DefaultClient, []codersdk.Region{
+		{Name: "broken", Healthy: true, PathAppURL: broken.URL},
+	})
+	require.False(t, ok)
+}
+
+func TestWorkspaceProxyURL(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, workspaceProxyURL(codersdk.Region{}))
+	require.Nil(t, workspaceProxyURL(codersdk.Region{Name: codersdk.PrimaryRegionName, PathAppURL: "https://coder.example.com"}))
+	require.Nil(t, workspaceProxyURL(codersdk.Region{Name: "eu"}))
+	u := workspaceProxyURL(codersdk.Region{Name: "eu", PathAppURL: "https://eu.example.com"})
+	require.NotNil(t, u)
+	require.Equal(t, "https://eu.example.com", u.String())
+}
+
+func TestWorkspaceProxyCache(t *testing.T) {
+	t.Parallel()
+
+	var (
+		srvURL string
+		calls  atomic.Int64
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/regions":
+			calls.Add(1)
+			_ = json.NewEncoder(w).Encode(codersdk.RegionsResponse[codersdk.Region]{
+				Regions: []codersdk.Region{{Name: codersdk.PrimaryRegionName, Healthy: true, PathAppURL: srvURL}},
+			})
+		case "/latency-check":
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	srvURL = srv.URL
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	client := codersdk.New(u)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitShort)
+	defer cancel()
+	r := &RootCmd{globalConfig: t.TempDir()}
+	conf := config.Root(r.globalConfig)
+	inv := (&clibase.Cmd{}).Invoke().WithContext(ctx)
+	requireRegion := func(t *testing.T, wantCalls int64) {
+		t.Helper()
+		region, err := r.workspaceProxy(inv, client)
+		require.NoError(t, err)
+		require.Equal(t, codersdk.PrimaryRegionName, region.Name)
+		require.Equal(t, srv.URL, region.PathAppURL)
+		require.Equal(t, wantCalls, calls.Load())
+	}
+
+	// The nearest region is selected and remembered.
+	requireRegion(t, 1)
+	// While it's cached, the regions aren't listed again.
+	requireRegion(t, 1)
+
+	// Once the cache expires, the remembered region is checked again.
+	err = rememberWorkspaceProxy(conf, codersdk.Region{Name: codersdk.PrimaryRegionName, PathAppURL: srv.URL})
+	require.NoError(t, err)
+	remembered, err := readWorkspaceProxy(conf)
+	require.NoError(t, err)
+	remembered.CheckedAt = remembered.CheckedAt.Add(-workspaceProxyCacheTTL)
+	data, err := json.Marshal(remembered)
+	require.NoError(t, err)
+	err = conf.WorkspaceProxy().Write(string(data))
+	require.NoError(t, err)
+	requireRegion(t, 2)
+	requireRegion(t, 2)
+
+	// Older versions only remembered the name.
+	err = conf.WorkspaceProxy().Write(codersdk.PrimaryRegionName)
+	require.NoError(t, err)
+	requireRegion(t, 3)
+	requireRegion(t, 3)
+}
@@ -427,6 +427,31 @@ const docTemplate = `{
                 }
             }
         },
+        "/debug/tailnet": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Debug"
+                ],
+                "summary": "Debug tailnet status",
+                "operationId": "debug-tailnet-status",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.TailnetStatus"
+                        }
+                    }
+                }
+            }
+        },
         "/debug/ws": {
             "get": {
                 "security": [
@@ -1081,6 +1106,279 @@ const docTemplate = `{
                 }
             }
         },
+        "/oauth2-provider/apps": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "OAuth2"
+                ],
+                "summary": "Get OAuth2 applications",
+                "operationId": "get-oauth2-applications",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.OAuth2ProviderApp"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "OAuth2"
+                ],
+                "summary": "Create OAuth2 application",
+                "operationId": "create-oauth2-application",
+                "parameters": [
+                    {
+                        "description": "The OAuth2 application to create.",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.PostOAuth2ProviderAppRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.OAuth2ProviderApp"
+                        }
+                    }
+                }
+            }
+        },
+        "/oauth2-provider/apps/{app}": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "OAuth2"
+                ],
+                "summary": "Get OAuth2 application",
+                "operationId": "get-oauth2-application",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "App ID",
+                        "name": "app",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.OAuth2ProviderApp"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "OAuth2"
+                ],
+                "summary": "Update OAuth2 application",
+                "operationId": "update-oauth2-application",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "App ID",
+                        "name": "app",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update an OAuth2 application.",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.PutOAuth2ProviderAppRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.OAuth2ProviderApp"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "tags": [
+                    "OAuth2"
+                ],
+                "summary": "Delete OAuth2 application",
+                "operationId": "delete-oauth2-application",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "App ID",
+                        "name": "app",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/oauth2-provider/apps/{app}/secrets": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "OAuth2"
+                ],
+                "summary": "Get OAuth2 application secrets",
+                "operationId": "get-oauth2-application-secrets",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "App ID",
+                        "name": "app",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.OAuth2ProviderAppSecret"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "OAuth2"
+                ],
+                "summary": "Create OAuth2 application secret",
+                "operationId": "create-oauth2-application-secret",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "App ID",
+                        "name": "app",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.OAuth2ProviderAppSecretFull"
+                        }
+                    }
+                }
+            }
+        },
+        "/oauth2-provider/apps/{app}/secrets/{secretID}": {
+            "delete": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "tags": [
+                    "OAuth2"
+                ],
+                "summary": "Delete OAuth2 application secret",
+                "operationId": "delete-oauth2-application-secret",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "App ID",
+                        "name": "app",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Secret ID",
+                        "name": "secretID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
         "/organizations": {
             "post": {
                 "security": [
@@ -3205,7 +3503,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/login": {
+        "/users/ldap/login": {
             "post": {
                 "consumes": [
                     "application/json"
@@ -3216,8 +3514,8 @@ const docTemplate = `{
                 "tags": [
                     "Authorization"
                 ],
-                "summary": "Log in user",
-                "operationId": "log-in-user",
+                "summary": "Log in user with LDAP",
+                "operationId": "log-in-user-with-ldap",
                 "parameters": [
                     {
                         "description": "Login request",
@@ -3225,7 +3523,7 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.LoginWithPasswordRequest"
+                            "$ref": "#/definitions/codersdk.LoginWithLDAPRequest"
                         }
                     }
                 ],
@@ -3239,18 +3537,86 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/logout": {
+        "/users/login": {
             "post": {
-                "security": [
-                    {
-                        "CoderSessionToken": []
-                    }
+                "consumes": [
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Authorization"
+                ],
+                "summary": "Log in user",
+                "operationId": "log-in-user",
+                "parameters": [
+                    {
+                        "description": "Login request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.LoginWithPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.LoginWithPasswordResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/login/mfa/enroll": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authorization"
+                ],
+                "summary": "Enroll TOTP authenticator with password",
+                "operationId": "enroll-totp-authenticator-with-password",
+                "parameters": [
+                    {
+                        "description": "Login request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.LoginWithPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.TOTPEnrollment"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/logout": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
                 ],
                 "summary": "Log out user",
                 "operationId": "log-out-user",
@@ -3330,6 +3696,40 @@ const docTemplate = `{
                 }
             }
         },
+        "/users/workload-identity/exchange": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authorization"
+                ],
+                "summary": "Exchange workload identity token",
+                "operationId": "exchange-workload-identity-token",
+                "parameters": [
+                    {
+                        "description": "Exchange request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkloadIdentityExchangeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkloadIdentityExchangeResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/users/{user}": {
             "get": {
                 "security": [
@@ -3508,41 +3908,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{user}/keys": {
-            "post": {
-                "security": [
-                    {
-                        "CoderSessionToken": []
-                    }
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Users"
-                ],
-                "summary": "Create new session key",
-                "operationId": "create-new-session-key",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.GenerateAPIKeyResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/users/{user}/keys/tokens": {
+        "/users/{user}/gitsshkeys": {
             "get": {
                 "security": [
                     {
@@ -3555,8 +3921,8 @@ const docTemplate = `{
                 "tags": [
                     "Users"
                 ],
-                "summary": "Get user tokens",
-                "operationId": "get-user-tokens",
+                "summary": "Get user Git SSH keys",
+                "operationId": "get-user-git-ssh-keys",
                 "parameters": [
                     {
                         "type": "string",
@@ -3572,7 +3938,7 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/codersdk.APIKey"
+                                "$ref": "#/definitions/codersdk.GitSSHKey"
                             }
                         }
                     }
@@ -3593,8 +3959,8 @@ const docTemplate = `{
                 "tags": [
                     "Users"
                 ],
-                "summary": "Create token API key",
-                "operationId": "create-token-api-key",
+                "summary": "Create user Git SSH key",
+                "operationId": "create-user-git-ssh-key",
                 "parameters": [
                     {
                         "type": "string",
@@ -3604,12 +3970,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Create token request",
+                        "description": "Create Git SSH key request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.CreateTokenRequest"
+                            "$ref": "#/definitions/codersdk.CreateGitSSHKeyRequest"
                         }
                     }
                 ],
@@ -3617,48 +3983,14 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.GenerateAPIKeyResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/users/{user}/keys/tokens/tokenconfig": {
-            "get": {
-                "security": [
-                    {
-                        "CoderSessionToken": []
-                    }
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "General"
-                ],
-                "summary": "Get token config",
-                "operationId": "get-token-config",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.TokenConfig"
+                            "$ref": "#/definitions/codersdk.GitSSHKey"
                         }
                     }
                 }
             }
         },
-        "/users/{user}/keys/tokens/{keyname}": {
-            "get": {
+        "/users/{user}/gitsshkeys/{keyname}": {
+            "put": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -3670,8 +4002,8 @@ const docTemplate = `{
                 "tags": [
                     "Users"
                 ],
-                "summary": "Get API key by token name",
-                "operationId": "get-api-key-by-token-name",
+                "summary": "Regenerate user Git SSH key by name",
+                "operationId": "regenerate-user-git-ssh-key-by-name",
                 "parameters": [
                     {
                         "type": "string",
@@ -3682,8 +4014,7 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "format": "string",
-                        "description": "Key Name",
+                        "description": "Key name",
                         "name": "keyname",
                         "in": "path",
                         "required": true
@@ -3693,27 +4024,22 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.APIKey"
+                            "$ref": "#/definitions/codersdk.GitSSHKey"
                         }
                     }
                 }
-            }
-        },
-        "/users/{user}/keys/{keyid}": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
                     "Users"
                 ],
-                "summary": "Get API key by ID",
-                "operationId": "get-api-key-by-id",
+                "summary": "Delete user Git SSH key",
+                "operationId": "delete-user-git-ssh-key",
                 "parameters": [
                     {
                         "type": "string",
@@ -3724,33 +4050,34 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Key ID",
-                        "name": "keyid",
+                        "description": "Key name",
+                        "name": "keyname",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.APIKey"
-                        }
+                    "204": {
+                        "description": "No Content"
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/users/{user}/gitsshkeys/{keyname}/upload": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "tags": [
                     "Users"
                 ],
-                "summary": "Delete API key",
-                "operationId": "delete-api-key",
+                "summary": "Upload user Git SSH key to external auth provider",
+                "operationId": "upload-user-git-ssh-key-to-external-auth-provider",
                 "parameters": [
                     {
                         "type": "string",
@@ -3761,11 +4088,19 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Key ID",
-                        "name": "keyid",
+                        "description": "Key name",
+                        "name": "keyname",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Upload Git SSH key request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UploadGitSSHKeyRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -3775,8 +4110,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{user}/login-type": {
-            "get": {
+        "/users/{user}/keys": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -3788,8 +4123,8 @@ const docTemplate = `{
                 "tags": [
                     "Users"
                 ],
-                "summary": "Get user login type",
-                "operationId": "get-user-login-type",
+                "summary": "Create new session key",
+                "operationId": "create-new-session-key",
                 "parameters": [
                     {
                         "type": "string",
@@ -3800,16 +4135,16 @@ const docTemplate = `{
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.UserLoginType"
+                            "$ref": "#/definitions/codersdk.GenerateAPIKeyResponse"
                         }
                     }
                 }
             }
         },
-        "/users/{user}/organizations": {
+        "/users/{user}/keys/tokens": {
             "get": {
                 "security": [
                     {
@@ -3822,8 +4157,8 @@ const docTemplate = `{
                 "tags": [
                     "Users"
                 ],
-                "summary": "Get organizations by user",
-                "operationId": "get-organizations-by-user",
+                "summary": "Get user tokens",
+                "operationId": "get-user-tokens",
                 "parameters": [
                     {
                         "type": "string",
@@ -3839,28 +4174,29 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/codersdk.Organization"
+                                "$ref": "#/definitions/codersdk.APIKey"
                             }
                         }
                     }
                 }
-            }
-        },
-        "/users/{user}/organizations/{organizationname}": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Users"
                 ],
-                "summary": "Get organization by user and organization name",
-                "operationId": "get-organization-by-user-and-organization-name",
+                "summary": "Create token API key",
+                "operationId": "create-token-api-key",
                 "parameters": [
                     {
                         "type": "string",
@@ -3870,38 +4206,40 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Organization name",
-                        "name": "organizationname",
-                        "in": "path",
-                        "required": true
+                        "description": "Create token request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateTokenRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Organization"
+                            "$ref": "#/definitions/codersdk.GenerateAPIKeyResponse"
                         }
                     }
                 }
             }
         },
-        "/users/{user}/password": {
-            "put": {
+        "/users/{user}/keys/tokens/tokenconfig": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
+                "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "General"
                 ],
-                "summary": "Update user password",
-                "operationId": "update-user-password",
+                "summary": "Get token config",
+                "operationId": "get-token-config",
                 "parameters": [
                     {
                         "type": "string",
@@ -3909,42 +4247,33 @@ const docTemplate = `{
                         "name": "user",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Update password request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.UpdateUserPasswordRequest"
-                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.TokenConfig"
+                        }
                     }
                 }
             }
         },
-        "/users/{user}/profile": {
-            "put": {
+        "/users/{user}/keys/tokens/{keyname}": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Users"
                 ],
-                "summary": "Update user profile",
-                "operationId": "update-user-profile",
+                "summary": "Get API key by token name",
+                "operationId": "get-api-key-by-token-name",
                 "parameters": [
                     {
                         "type": "string",
@@ -3954,26 +4283,25 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Updated profile",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.UpdateUserProfileRequest"
-                        }
+                        "type": "string",
+                        "format": "string",
+                        "description": "Key Name",
+                        "name": "keyname",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.User"
+                            "$ref": "#/definitions/codersdk.APIKey"
                         }
                     }
                 }
             }
         },
-        "/users/{user}/quiet-hours": {
+        "/users/{user}/keys/{keyid}": {
             "get": {
                 "security": [
                     {
@@ -3984,82 +4312,106 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
+                    "Users"
                 ],
-                "summary": "Get user quiet hours schedule",
-                "operationId": "get-user-quiet-hours-schedule",
+                "summary": "Get API key by ID",
+                "operationId": "get-api-key-by-id",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "User ID",
+                        "description": "User ID, name, or me",
                         "name": "user",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Key ID",
+                        "name": "keyid",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.UserQuietHoursScheduleResponse"
-                            }
+                            "$ref": "#/definitions/codersdk.APIKey"
                         }
                     }
                 }
             },
-            "put": {
+            "delete": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Delete API key",
+                "operationId": "delete-api-key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Key ID",
+                        "name": "keyid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/users/{user}/login-type": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
+                    "Users"
                 ],
-                "summary": "Update user quiet hours schedule",
-                "operationId": "update-user-quiet-hours-schedule",
+                "summary": "Get user login type",
+                "operationId": "get-user-login-type",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "User ID",
+                        "description": "User ID, name, or me",
                         "name": "user",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Update schedule request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.UpdateUserQuietHoursScheduleRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.UserQuietHoursScheduleResponse"
-                            }
+                            "$ref": "#/definitions/codersdk.UserLoginType"
                         }
                     }
                 }
             }
         },
-        "/users/{user}/roles": {
+        "/users/{user}/mfa": {
             "get": {
                 "security": [
                     {
@@ -4072,8 +4424,8 @@ const docTemplate = `{
                 "tags": [
                     "Users"
                 ],
-                "summary": "Get user roles",
-                "operationId": "get-user-roles",
+                "summary": "Get user MFA status",
+                "operationId": "get-user-mfa-status",
                 "parameters": [
                     {
                         "type": "string",
@@ -4087,28 +4439,26 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.User"
+                            "$ref": "#/definitions/codersdk.UserMFA"
                         }
                     }
                 }
             },
-            "put": {
+            "delete": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "description": "Removes the authenticator and recovery codes of the user. Users can remove their own with a current code unless the deployment requires MFA.",
                 "consumes": [
                     "application/json"
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
                     "Users"
                 ],
-                "summary": "Assign role to user",
-                "operationId": "assign-role-to-user",
+                "summary": "Reset user MFA",
+                "operationId": "reset-user-mfa",
                 "parameters": [
                     {
                         "type": "string",
@@ -4118,40 +4468,37 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Update roles request",
+                        "description": "Reset request",
                         "name": "request",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.UpdateRoles"
+                            "$ref": "#/definitions/codersdk.ResetUserMFARequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.User"
-                        }
+                    "204": {
+                        "description": "No Content"
                     }
                 }
             }
         },
-        "/users/{user}/status/activate": {
-            "put": {
+        "/users/{user}/mfa/totp": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "description": "Starts the enrollment of an authenticator app, replacing a previous pending enrollment. The enrollment must be verified with a code from the app before it is enabled.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Users"
                 ],
-                "summary": "Activate user account",
-                "operationId": "activate-user-account",
+                "summary": "Enroll user TOTP authenticator",
+                "operationId": "enroll-user-totp-authenticator",
                 "parameters": [
                     {
                         "type": "string",
@@ -4162,30 +4509,34 @@ const docTemplate = `{
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.User"
+                            "$ref": "#/definitions/codersdk.TOTPEnrollment"
                         }
                     }
                 }
             }
         },
-        "/users/{user}/status/suspend": {
-            "put": {
+        "/users/{user}/mfa/totp/verify": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "description": "Enables the pending enrollment of an authenticator app and returns new recovery codes, which are only shown once.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Users"
                 ],
-                "summary": "Suspend user account",
-                "operationId": "suspend-user-account",
+                "summary": "Verify user TOTP authenticator",
+                "operationId": "verify-user-totp-authenticator",
                 "parameters": [
                     {
                         "type": "string",
@@ -4193,19 +4544,28 @@ const docTemplate = `{
                         "name": "user",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Verify request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.VerifyTOTPRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.User"
+                            "$ref": "#/definitions/codersdk.MFARecoveryCodes"
                         }
                     }
                 }
             }
         },
-        "/users/{user}/workspace/{workspacename}": {
+        "/users/{user}/organizations": {
             "get": {
                 "security": [
                     {
@@ -4216,10 +4576,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Workspaces"
+                    "Users"
                 ],
-                "summary": "Get workspace metadata by user and workspace name",
-                "operationId": "get-workspace-metadata-by-user-and-workspace-name",
+                "summary": "Get organizations by user",
+                "operationId": "get-organizations-by-user",
                 "parameters": [
                     {
                         "type": "string",
@@ -4227,32 +4587,22 @@ const docTemplate = `{
                         "name": "user",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Workspace name",
-                        "name": "workspacename",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Return data instead of HTTP 404 if the workspace is deleted",
-                        "name": "include_deleted",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Workspace"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.Organization"
+                            }
                         }
                     }
                 }
             }
         },
-        "/users/{user}/workspace/{workspacename}/builds/{buildnumber}": {
+        "/users/{user}/organizations/{organizationname}": {
             "get": {
                 "security": [
                     {
@@ -4263,10 +4613,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Builds"
+                    "Users"
                 ],
-                "summary": "Get workspace build by user, workspace name, and build number",
-                "operationId": "get-workspace-build-by-user-workspace-name-and-build-number",
+                "summary": "Get organization by user and organization name",
+                "operationId": "get-organization-by-user-and-organization-name",
                 "parameters": [
                     {
                         "type": "string",
@@ -4277,16 +4627,8 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Workspace name",
-                        "name": "workspacename",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "format": "number",
-                        "description": "Build number",
-                        "name": "buildnumber",
+                        "description": "Organization name",
+                        "name": "organizationname",
                         "in": "path",
                         "required": true
                     }
@@ -4295,27 +4637,27 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.WorkspaceBuild"
+                            "$ref": "#/definitions/codersdk.Organization"
                         }
                     }
                 }
             }
         },
-        "/workspace-quota/{user}": {
-            "get": {
+        "/users/{user}/password": {
+            "put": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "produces": [
+                "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
+                    "Users"
                 ],
-                "summary": "Get workspace quota by user",
-                "operationId": "get-workspace-quota-by-user",
+                "summary": "Update user password",
+                "operationId": "update-user-password",
                 "parameters": [
                     {
                         "type": "string",
@@ -4323,20 +4665,26 @@ const docTemplate = `{
                         "name": "user",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Update password request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpdateUserPasswordRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.WorkspaceQuota"
-                        }
+                    "204": {
+                        "description": "No Content"
                     }
                 }
             }
         },
-        "/workspaceagents/aws-instance-identity": {
-            "post": {
+        "/users/{user}/profile": {
+            "put": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -4349,18 +4697,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Agents"
+                    "Users"
                 ],
-                "summary": "Authenticate agent on AWS instance",
-                "operationId": "authenticate-agent-on-aws-instance",
+                "summary": "Update user profile",
+                "operationId": "update-user-profile",
                 "parameters": [
                     {
-                        "description": "Instance identity token",
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated profile",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/agentsdk.AWSInstanceIdentityToken"
+                            "$ref": "#/definitions/codersdk.UpdateUserProfileRequest"
                         }
                     }
                 ],
@@ -4368,14 +4723,50 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/agentsdk.AuthenticateResponse"
+                            "$ref": "#/definitions/codersdk.User"
                         }
                     }
                 }
             }
         },
-        "/workspaceagents/azure-instance-identity": {
-            "post": {
+        "/users/{user}/quiet-hours": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "Get user quiet hours schedule",
+                "operationId": "get-user-quiet-hours-schedule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "User ID",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.UserQuietHoursScheduleResponse"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -4388,18 +4779,26 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Agents"
+                    "Enterprise"
                 ],
-                "summary": "Authenticate agent on Azure instance",
-                "operationId": "authenticate-agent-on-azure-instance",
+                "summary": "Update user quiet hours schedule",
+                "operationId": "update-user-quiet-hours-schedule",
                 "parameters": [
                     {
-                        "description": "Instance identity token",
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "User ID",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update schedule request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/agentsdk.AzureInstanceIdentityToken"
+                            "$ref": "#/definitions/codersdk.UpdateUserQuietHoursScheduleRequest"
                         }
                     }
                 ],
@@ -4407,13 +4806,16 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/agentsdk.AuthenticateResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.UserQuietHoursScheduleResponse"
+                            }
                         }
                     }
                 }
             }
         },
-        "/workspaceagents/connection": {
+        "/users/{user}/roles": {
             "get": {
                 "security": [
                     {
@@ -4424,25 +4826,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Agents"
+                    "Users"
+                ],
+                "summary": "Get user roles",
+                "operationId": "get-user-roles",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get connection info for workspace agent generic",
-                "operationId": "get-connection-info-for-workspace-agent-generic",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.WorkspaceAgentConnectionInfo"
+                            "$ref": "#/definitions/codersdk.User"
                         }
                     }
-                },
-                "x-apidocgen": {
-                    "skip": true
                 }
-            }
-        },
-        "/workspaceagents/google-instance-identity": {
-            "post": {
+            },
+            "put": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -4455,18 +4861,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Agents"
+                    "Users"
                 ],
-                "summary": "Authenticate agent on Google Cloud instance",
-                "operationId": "authenticate-agent-on-google-cloud-instance",
+                "summary": "Assign role to user",
+                "operationId": "assign-role-to-user",
                 "parameters": [
                     {
-                        "description": "Instance identity token",
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update roles request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/agentsdk.GoogleInstanceIdentityToken"
+                            "$ref": "#/definitions/codersdk.UpdateRoles"
                         }
                     }
                 ],
@@ -4474,69 +4887,81 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/agentsdk.AuthenticateResponse"
+                            "$ref": "#/definitions/codersdk.User"
                         }
                     }
                 }
             }
         },
-        "/workspaceagents/me/app-health": {
-            "post": {
+        "/users/{user}/status/activate": {
+            "put": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Agents"
+                    "Users"
                 ],
-                "summary": "Submit workspace agent application health",
-                "operationId": "submit-workspace-agent-application-health",
+                "summary": "Activate user account",
+                "operationId": "activate-user-account",
                 "parameters": [
                     {
-                        "description": "Application health request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/agentsdk.PostAppHealthsRequest"
-                        }
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.User"
+                        }
                     }
                 }
             }
         },
-        "/workspaceagents/me/coordinate": {
-            "get": {
+        "/users/{user}/status/suspend": {
+            "put": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "description": "It accepts a WebSocket connection to an agent that listens to\nincoming connections and publishes node updates.",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Agents"
+                    "Users"
+                ],
+                "summary": "Suspend user account",
+                "operationId": "suspend-user-account",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Coordinate workspace agent via Tailnet",
-                "operationId": "coordinate-workspace-agent-via-tailnet",
                 "responses": {
-                    "101": {
-                        "description": "Switching Protocols"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.User"
+                        }
                     }
                 }
             }
         },
-        "/workspaceagents/me/gitauth": {
+        "/users/{user}/workspace/{workspacename}": {
             "get": {
                 "security": [
                     {
@@ -4547,23 +4972,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Agents"
+                    "Workspaces"
                 ],
-                "summary": "Get workspace agent Git auth",
-                "operationId": "get-workspace-agent-git-auth",
+                "summary": "Get workspace metadata by user and workspace name",
+                "operationId": "get-workspace-metadata-by-user-and-workspace-name",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "uri",
-                        "description": "Git URL",
-                        "name": "url",
-                        "in": "query",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Workspace name",
+                        "name": "workspacename",
+                        "in": "path",
                         "required": true
                     },
                     {
                         "type": "boolean",
-                        "description": "Wait for a new token to be issued",
-                        "name": "listen",
+                        "description": "Return data instead of HTTP 404 if the workspace is deleted",
+                        "name": "include_deleted",
                         "in": "query"
                     }
                 ],
@@ -4571,13 +5002,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/agentsdk.GitAuthResponse"
+                            "$ref": "#/definitions/codersdk.Workspace"
                         }
                     }
                 }
             }
         },
-        "/workspaceagents/me/gitsshkey": {
+        "/users/{user}/workspace/{workspacename}/builds/{buildnumber}": {
             "get": {
                 "security": [
                     {
@@ -4588,85 +5019,118 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Agents"
+                    "Builds"
                 ],
-                "summary": "Get workspace agent Git SSH key",
-                "operationId": "get-workspace-agent-git-ssh-key",
-                "responses": {
+                "summary": "Get workspace build by user, workspace name, and build number",
+                "operationId": "get-workspace-build-by-user-workspace-name-and-build-number",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Workspace name",
+                        "name": "workspacename",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "number",
+                        "description": "Build number",
+                        "name": "buildnumber",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/agentsdk.GitSSHKey"
+                            "$ref": "#/definitions/codersdk.WorkspaceBuild"
                         }
                     }
                 }
             }
         },
-        "/workspaceagents/me/logs": {
-            "patch": {
+        "/workspace-quota/{user}": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Agents"
+                    "Enterprise"
                 ],
-                "summary": "Patch workspace agent logs",
-                "operationId": "patch-workspace-agent-logs",
+                "summary": "Get workspace quota by user",
+                "operationId": "get-workspace-quota-by-user",
                 "parameters": [
                     {
-                        "description": "logs",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/agentsdk.PatchLogs"
-                        }
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Response"
+                            "$ref": "#/definitions/codersdk.WorkspaceQuota"
                         }
                     }
                 }
             }
         },
-        "/workspaceagents/me/manifest": {
-            "get": {
+        "/workspaceagents/aws-instance-identity": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Agents"
                 ],
-                "summary": "Get authorized workspace agent manifest",
-                "operationId": "get-authorized-workspace-agent-manifest",
+                "summary": "Authenticate agent on AWS instance",
+                "operationId": "authenticate-agent-on-aws-instance",
+                "parameters": [
+                    {
+                        "description": "Instance identity token",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.AWSInstanceIdentityToken"
+                        }
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/agentsdk.Manifest"
+                            "$ref": "#/definitions/agentsdk.AuthenticateResponse"
                         }
                     }
                 }
             }
         },
-        "/workspaceagents/me/metadata/{key}": {
+        "/workspaceagents/azure-instance-identity": {
             "post": {
                 "security": [
                     {
@@ -4676,77 +5140,64 @@ const docTemplate = `{
                 "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
                     "Agents"
                 ],
-                "summary": "Submit workspace agent metadata",
-                "operationId": "submit-workspace-agent-metadata",
+                "summary": "Authenticate agent on Azure instance",
+                "operationId": "authenticate-agent-on-azure-instance",
                 "parameters": [
                     {
-                        "description": "Workspace agent metadata request",
+                        "description": "Instance identity token",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/agentsdk.PostMetadataRequest"
+                            "$ref": "#/definitions/agentsdk.AzureInstanceIdentityToken"
                         }
-                    },
-                    {
-                        "type": "string",
-                        "format": "string",
-                        "description": "metadata key",
-                        "name": "key",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "Success"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.AuthenticateResponse"
+                        }
                     }
-                },
-                "x-apidocgen": {
-                    "skip": true
                 }
             }
         },
-        "/workspaceagents/me/report-lifecycle": {
-            "post": {
+        "/workspaceagents/connection": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
+                "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Agents"
                 ],
-                "summary": "Submit workspace agent lifecycle state",
-                "operationId": "submit-workspace-agent-lifecycle-state",
-                "parameters": [
-                    {
-                        "description": "Workspace agent lifecycle request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
+                "summary": "Get connection info for workspace agent generic",
+                "operationId": "get-connection-info-for-workspace-agent-generic",
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/agentsdk.PostLifecycleRequest"
+                            "$ref": "#/definitions/codersdk.WorkspaceAgentConnectionInfo"
                         }
                     }
-                ],
-                "responses": {
-                    "204": {
-                        "description": "Success"
-                    }
                 },
                 "x-apidocgen": {
                     "skip": true
                 }
             }
         },
-        "/workspaceagents/me/report-stats": {
+        "/workspaceagents/google-instance-identity": {
             "post": {
                 "security": [
                     {
@@ -4762,16 +5213,16 @@ const docTemplate = `{
                 "tags": [
                     "Agents"
                 ],
-                "summary": "Submit workspace agent stats",
-                "operationId": "submit-workspace-agent-stats",
+                "summary": "Authenticate agent on Google Cloud instance",
+                "operationId": "authenticate-agent-on-google-cloud-instance",
                 "parameters": [
                     {
-                        "description": "Stats request",
+                        "description": "Instance identity token",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/agentsdk.Stats"
+                            "$ref": "#/definitions/agentsdk.GoogleInstanceIdentityToken"
                         }
                     }
                 ],
@@ -4779,13 +5230,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/agentsdk.StatsResponse"
+                            "$ref": "#/definitions/agentsdk.AuthenticateResponse"
                         }
                     }
                 }
             }
         },
-        "/workspaceagents/me/startup": {
+        "/workspaceagents/me/app-health": {
             "post": {
                 "security": [
                     {
@@ -4801,16 +5252,16 @@ const docTemplate = `{
                 "tags": [
                     "Agents"
                 ],
-                "summary": "Submit workspace agent startup",
-                "operationId": "submit-workspace-agent-startup",
+                "summary": "Submit workspace agent application health",
+                "operationId": "submit-workspace-agent-application-health",
                 "parameters": [
                     {
-                        "description": "Startup request",
+                        "description": "Application health request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/agentsdk.PostStartupRequest"
+                            "$ref": "#/definitions/agentsdk.PostAppHealthsRequest"
                         }
                     }
                 ],
@@ -4818,52 +5269,38 @@ const docTemplate = `{
                     "200": {
                         "description": "OK"
                     }
-                },
-                "x-apidocgen": {
-                    "skip": true
                 }
             }
         },
-        "/workspaceagents/me/startup-logs": {
-            "patch": {
+        "/workspaceagents/me/coordinate": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
+                "description": "It accepts a WebSocket connection to an agent that listens to\nincoming connections and publishes node updates.",
                 "tags": [
                     "Agents"
                 ],
-                "summary": "Removed: Patch workspace agent logs",
-                "operationId": "removed-patch-workspace-agent-logs",
+                "summary": "Coordinate workspace agent via Tailnet",
+                "operationId": "coordinate-workspace-agent-via-tailnet",
                 "parameters": [
                     {
-                        "description": "logs",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/agentsdk.PatchLogs"
-                        }
+                        "type": "string",
+                        "description": "Version of the coordinator protocol, e.g. 2.0. Defaults to 1.0",
+                        "name": "version",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.Response"
-                        }
+                    "101": {
+                        "description": "Switching Protocols"
                     }
                 }
             }
         },
-        "/workspaceagents/{workspaceagent}": {
+        "/workspaceagents/me/external-auth": {
             "get": {
                 "security": [
                     {
@@ -4876,29 +5313,39 @@ const docTemplate = `{
                 "tags": [
                     "Agents"
                 ],
-                "summary": "Get workspace agent by ID",
-                "operationId": "get-workspace-agent-by-id",
+                "summary": "Get workspace agent external auth",
+                "operationId": "get-workspace-agent-external-auth",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Workspace agent ID",
-                        "name": "workspaceagent",
-                        "in": "path",
-                        "required": true
+                        "description": "Provider ID",
+                        "name": "id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Git URL to match against the Git providers",
+                        "name": "match",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Wait for a new token to be issued",
+                        "name": "listen",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.WorkspaceAgent"
+                            "$ref": "#/definitions/agentsdk.ExternalAuthResponse"
                         }
                     }
                 }
             }
         },
-        "/workspaceagents/{workspaceagent}/connection": {
+        "/workspaceagents/me/gitauth": {
             "get": {
                 "security": [
                     {
@@ -4911,52 +5358,448 @@ const docTemplate = `{
                 "tags": [
                     "Agents"
                 ],
-                "summary": "Get connection info for workspace agent",
-                "operationId": "get-connection-info-for-workspace-agent",
+                "summary": "Get workspace agent Git auth",
+                "operationId": "get-workspace-agent-git-auth",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Workspace agent ID",
-                        "name": "workspaceagent",
-                        "in": "path",
+                        "format": "uri",
+                        "description": "Git URL",
+                        "name": "url",
+                        "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Wait for a new token to be issued",
+                        "name": "listen",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.WorkspaceAgentConnectionInfo"
+                            "$ref": "#/definitions/agentsdk.GitAuthResponse"
                         }
                     }
                 }
             }
         },
-        "/workspaceagents/{workspaceagent}/coordinate": {
+        "/workspaceagents/me/gitsshkey": {
             "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
                     "Agents"
                 ],
-                "summary": "Coordinate workspace agent",
-                "operationId": "coordinate-workspace-agent",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Workspace agent ID",
-                        "name": "workspaceagent",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
+                "summary": "Get workspace agent Git SSH key",
+                "operationId": "get-workspace-agent-git-ssh-key",
                 "responses": {
-                    "101": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.GitSSHKey"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/me/gitsshkeys": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Get workspace agent Git SSH keys",
+                "operationId": "get-workspace-agent-git-ssh-keys",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/agentsdk.GitSSHKey"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/me/logs": {
+            "patch": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Patch workspace agent logs",
+                "operationId": "patch-workspace-agent-logs",
+                "parameters": [
+                    {
+                        "description": "logs",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.PatchLogs"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/me/manifest": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Get authorized workspace agent manifest",
+                "operationId": "get-authorized-workspace-agent-manifest",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.Manifest"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/me/metadata/{key}": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Submit workspace agent metadata",
+                "operationId": "submit-workspace-agent-metadata",
+                "parameters": [
+                    {
+                        "description": "Workspace agent metadata request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.PostMetadataRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "format": "string",
+                        "description": "metadata key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Success"
+                    }
+                },
+                "x-apidocgen": {
+                    "skip": true
+                }
+            }
+        },
+        "/workspaceagents/me/report-lifecycle": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Submit workspace agent lifecycle state",
+                "operationId": "submit-workspace-agent-lifecycle-state",
+                "parameters": [
+                    {
+                        "description": "Workspace agent lifecycle request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.PostLifecycleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Success"
+                    }
+                },
+                "x-apidocgen": {
+                    "skip": true
+                }
+            }
+        },
+        "/workspaceagents/me/report-stats": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Submit workspace agent stats",
+                "operationId": "submit-workspace-agent-stats",
+                "parameters": [
+                    {
+                        "description": "Stats request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.Stats"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.StatsResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/me/startup": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Submit workspace agent startup",
+                "operationId": "submit-workspace-agent-startup",
+                "parameters": [
+                    {
+                        "description": "Startup request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.PostStartupRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    }
+                },
+                "x-apidocgen": {
+                    "skip": true
+                }
+            }
+        },
+        "/workspaceagents/me/startup-logs": {
+            "patch": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Removed: Patch workspace agent logs",
+                "operationId": "removed-patch-workspace-agent-logs",
+                "parameters": [
+                    {
+                        "description": "logs",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.PatchLogs"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/{workspaceagent}": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Get workspace agent by ID",
+                "operationId": "get-workspace-agent-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceAgent"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/{workspaceagent}/connection": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Get connection info for workspace agent",
+                "operationId": "get-connection-info-for-workspace-agent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceAgentConnectionInfo"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/{workspaceagent}/coordinate": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Coordinate workspace agent",
+                "operationId": "coordinate-workspace-agent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Version of the coordinator protocol, e.g. 2.0. Defaults to 1.0",
+                        "name": "version",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "101": {
                         "description": "Switching Protocols"
                     }
                 }
@@ -6351,6 +7194,28 @@ const docTemplate = `{
                 }
             }
         },
+        "agentsdk.ExternalAuthResponse": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "description": "AccessToken is empty if the workspace owner must authenticate at URL.",
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "username": {
+                    "description": "Username and Password are the credentials to use for Git operations.",
+                    "type": "string"
+                }
+            }
+        },
         "agentsdk.GitAuthResponse": {
             "type": "object",
             "properties": {
@@ -6368,6 +7233,16 @@ const docTemplate = `{
         "agentsdk.GitSSHKey": {
             "type": "object",
             "properties": {
+                "hosts": {
+                    "description": "Hosts are patterns of the hosts the key is offered to. The key is\noffered to every host if it's empty.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
                 "private_key": {
                     "type": "string"
                 },
@@ -6428,6 +7303,9 @@ const docTemplate = `{
                 "disable_direct_connections": {
                     "type": "boolean"
                 },
+                "egress_policy": {
+                    "$ref": "#/definitions/codersdk.EgressPolicy"
+                },
                 "environment_variables": {
                     "type": "object",
                     "additionalProperties": {
@@ -6728,6 +7606,28 @@ const docTemplate = `{
                 }
             }
         },
+        "clibase.Struct-array_codersdk_OIDCProviderConfig": {
+            "type": "object",
+            "properties": {
+                "value": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.OIDCProviderConfig"
+                    }
+                }
+            }
+        },
+        "clibase.Struct-array_codersdk_WorkloadIdentityProvider": {
+            "type": "object",
+            "properties": {
+                "value": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkloadIdentityProvider"
+                    }
+                }
+            }
+        },
         "clibase.URL": {
             "type": "object",
             "properties": {
@@ -6902,6 +7802,14 @@ const docTemplate = `{
                 "user_id"
             ],
             "properties": {
+                "allow_list": {
+                    "description": "AllowList limits the key to the listed resources, e.g. specific\nworkspaces or templates. An empty list allows all resources.",
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "format": "uuid"
+                    }
+                },
                 "created_at": {
                     "type": "string",
                     "format": "date-time"
@@ -6925,7 +7833,9 @@ const docTemplate = `{
                         "password",
                         "github",
                         "oidc",
-                        "token"
+                        "token",
+                        "oauth2_provider_app",
+                        "ldap"
                     ],
                     "allOf": [
                         {
@@ -6944,6 +7854,20 @@ const docTemplate = `{
                         }
                     ]
                 },
+                "scopes": {
+                    "description": "Scopes are the fine-grained scopes of the key. When set, they replace\nScope.",
+                    "type": "array",
+                    "items": {
+                        "enum": [
+                            "template:push",
+                            "template:read",
+                            "user:read",
+                            "workspace:read",
+                            "workspace:start"
+                        ],
+                        "$ref": "#/definitions/codersdk.APIKeyScope"
+                    }
+                },
                 "token_name": {
                     "type": "string"
                 },
@@ -6961,11 +7885,21 @@ const docTemplate = `{
             "type": "string",
             "enum": [
                 "all",
-                "application_connect"
+                "application_connect",
+                "user:read",
+                "workspace:read",
+                "workspace:start",
+                "template:read",
+                "template:push"
             ],
             "x-enum-varnames": [
                 "APIKeyScopeAll",
-                "APIKeyScopeApplicationConnect"
+                "APIKeyScopeApplicationConnect",
+                "APIKeyScopeUserRead",
+                "APIKeyScopeWorkspaceRead",
+                "APIKeyScopeWorkspaceStart",
+                "APIKeyScopeTemplateRead",
+                "APIKeyScopeTemplatePush"
             ]
         },
         "codersdk.AddLicenseRequest": {
@@ -7167,9 +8101,19 @@ const docTemplate = `{
                 "github": {
                     "$ref": "#/definitions/codersdk.AuthMethod"
                 },
+                "ldap": {
+                    "$ref": "#/definitions/codersdk.LDAPAuthMethod"
+                },
                 "oidc": {
                     "$ref": "#/definitions/codersdk.OIDCAuthMethod"
                 },
+                "oidc_providers": {
+                    "description": "OIDCProviders are the additional OIDC providers users can sign in\nwith.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.OIDCProviderAuthMethod"
+                    }
+                },
                 "password": {
                     "$ref": "#/definitions/codersdk.AuthMethod"
                 }
@@ -7343,6 +8287,36 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.CreateGitSSHKeyRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "algorithm": {
+                    "description": "Algorithm of the generated key. It defaults to the algorithm\nconfigured for the deployment, and must be empty when importing.",
+                    "type": "string",
+                    "enum": [
+                        "ed25519",
+                        "ecdsa",
+                        "rsa4096"
+                    ]
+                },
+                "hosts": {
+                    "description": "Hosts are patterns of the hosts the key is offered to. Patterns\nsupport \"*\" and \"?\" wildcards, and are negated by a leading \"!\".",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "private_key": {
+                    "type": "string"
+                }
+            }
+        },
         "codersdk.CreateGroupRequest": {
             "type": "object",
             "properties": {
@@ -7585,6 +8559,14 @@ const docTemplate = `{
         "codersdk.CreateTokenRequest": {
             "type": "object",
             "properties": {
+                "allow_list": {
+                    "description": "AllowList limits the token to the listed resources, e.g. specific\nworkspaces or templates. It requires at least one fine-grained scope.",
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "format": "uuid"
+                    }
+                },
                 "lifetime": {
                     "type": "integer"
                 },
@@ -7599,6 +8581,20 @@ const docTemplate = `{
                         }
                     ]
                 },
+                "scopes": {
+                    "description": "Scopes are fine-grained scopes to limit the token to. They cannot be\ncombined with the application_connect scope.",
+                    "type": "array",
+                    "items": {
+                        "enum": [
+                            "template:push",
+                            "template:read",
+                            "user:read",
+                            "workspace:read",
+                            "workspace:start"
+                        ],
+                        "$ref": "#/definitions/codersdk.APIKeyScope"
+                    }
+                },
                 "token_name": {
                     "type": "string"
                 }
@@ -7634,6 +8630,11 @@ const docTemplate = `{
                 "password": {
                     "type": "string"
                 },
+                "service_account_owner_group_id": {
+                    "description": "ServiceAccountOwnerGroupID is required when UserLoginType is\nLoginTypeServiceAccount. Members of the group can issue tokens for\nthe service account.",
+                    "type": "string",
+                    "format": "uuid"
+                },
                 "username": {
                     "type": "string"
                 }
@@ -7969,6 +8970,9 @@ const docTemplate = `{
                 "job_hang_detector_interval": {
                     "type": "integer"
                 },
+                "ldap": {
+                    "$ref": "#/definitions/codersdk.LDAPConfig"
+                },
                 "logging": {
                     "$ref": "#/definitions/codersdk.LoggingConfig"
                 },
@@ -8014,12 +9018,18 @@ const docTemplate = `{
                         "type": "string"
                     }
                 },
+                "proxy_version_policy": {
+                    "type": "string"
+                },
                 "rate_limit": {
                     "$ref": "#/definitions/codersdk.RateLimitConfig"
                 },
                 "redirect_to_access_url": {
                     "type": "boolean"
                 },
+                "require_mfa": {
+                    "type": "boolean"
+                },
                 "scim_api_key": {
                     "type": "string"
                 },
@@ -8068,6 +9078,9 @@ const docTemplate = `{
                 "wildcard_access_url": {
                     "$ref": "#/definitions/clibase.URL"
                 },
+                "workload_identity_providers": {
+                    "$ref": "#/definitions/clibase.Struct-array_codersdk_WorkloadIdentityProvider"
+                },
                 "write_config": {
                     "type": "boolean"
                 }
@@ -8090,6 +9103,48 @@ const docTemplate = `{
                 "DisplayAppSSH"
             ]
         },
+        "codersdk.EgressPolicy": {
+            "type": "object",
+            "properties": {
+                "default_action": {
+                    "description": "DefaultAction applies to destinations that no rule matches. It defaults\nto allow.",
+                    "type": "string",
+                    "enum": [
+                        "allow",
+                        "deny"
+                    ]
+                },
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.EgressRule"
+                    }
+                }
+            }
+        },
+        "codersdk.EgressRule": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "enum": [
+                        "allow",
+                        "deny"
+                    ]
+                },
+                "cidr": {
+                    "description": "CIDR is the network of destinations the rule matches, e.g. 10.0.0.0/8.\nA single IP address only matches itself. If empty, all addresses match.",
+                    "type": "string"
+                },
+                "ports": {
+                    "description": "Ports are the ports or port ranges of destinations the rule matches,\ne.g. 443 or 8000-8999. If empty, all ports match.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
         "codersdk.Entitlement": {
             "type": "string",
             "enum": [
@@ -8269,6 +9324,14 @@ const docTemplate = `{
                 "device_flow": {
                     "type": "boolean"
                 },
+                "display_icon": {
+                    "description": "DisplayIcon is a URL to an icon shown next to the display name.",
+                    "type": "string"
+                },
+                "display_name": {
+                    "description": "DisplayName is shown to users when they authenticate. It defaults to\nthe name of the provider type.",
+                    "type": "string"
+                },
                 "id": {
                     "type": "string"
                 },
@@ -8284,6 +9347,10 @@ const docTemplate = `{
                         "type": "string"
                     }
                 },
+                "ssh_keys_url": {
+                    "description": "SSHKeysURL is an API endpoint that public SSH keys of the user are\nuploaded to. It defaults to the endpoint of GitHub or GitLab.",
+                    "type": "string"
+                },
                 "token_url": {
                     "type": "string"
                 },
@@ -8354,6 +9421,25 @@ const docTemplate = `{
                     "type": "string",
                     "format": "date-time"
                 },
+                "hosts": {
+                    "description": "Hosts are patterns of the hosts the key is offered to, such as\n\"*.github.com\". The key is offered to every host if it's empty.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "imported": {
+                    "description": "Imported is true if the private key was provided by the user instead\nof being generated by Coder.",
+                    "type": "boolean"
+                },
+                "key_type": {
+                    "description": "KeyType is the type of the public key, such as \"ssh-ed25519\".",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Name is unique per user. Every user has a key named \"default\".",
+                    "type": "string"
+                },
                 "public_key": {
                     "type": "string"
                 },
@@ -8450,28 +9536,98 @@ const docTemplate = `{
                     "format": "uuid"
                 },
                 "url": {
-                    "description": "URL is the URL of the reconnecting-pty endpoint you are connecting to.",
+                    "description": "URL is the URL of the reconnecting-pty endpoint you are connecting to.",
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.IssueReconnectingPTYSignedTokenResponse": {
+            "type": "object",
+            "properties": {
+                "signed_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.JobErrorCode": {
+            "type": "string",
+            "enum": [
+                "REQUIRED_TEMPLATE_VARIABLES"
+            ],
+            "x-enum-varnames": [
+                "RequiredTemplateVariables"
+            ]
+        },
+        "codersdk.LDAPAuthMethod": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "signInText": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.LDAPConfig": {
+            "type": "object",
+            "properties": {
+                "allow_signups": {
+                    "type": "boolean"
+                },
+                "base_dn": {
+                    "type": "string"
+                },
+                "bind_dn": {
+                    "type": "string"
+                },
+                "bind_password": {
+                    "type": "string"
+                },
+                "email_attribute": {
+                    "type": "string"
+                },
+                "group_attribute": {
+                    "type": "string"
+                },
+                "group_auto_create": {
+                    "type": "boolean"
+                },
+                "group_mapping": {
+                    "type": "object"
+                },
+                "sign_in_text": {
+                    "type": "string"
+                },
+                "start_tls": {
+                    "type": "boolean"
+                },
+                "sync_interval": {
+                    "type": "integer"
+                },
+                "tls_ca_file": {
+                    "type": "string"
+                },
+                "url": {
                     "type": "string"
-                }
-            }
-        },
-        "codersdk.IssueReconnectingPTYSignedTokenResponse": {
-            "type": "object",
-            "properties": {
-                "signed_token": {
+                },
+                "user_filter": {
+                    "type": "string"
+                },
+                "user_role_mapping": {
+                    "type": "object"
+                },
+                "user_roles_default": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "username_attribute": {
                     "type": "string"
                 }
             }
         },
-        "codersdk.JobErrorCode": {
-            "type": "string",
-            "enum": [
-                "REQUIRED_TEMPLATE_VARIABLES"
-            ],
-            "x-enum-varnames": [
-                "RequiredTemplateVariables"
-            ]
-        },
         "codersdk.License": {
             "type": "object",
             "properties": {
@@ -8563,7 +9719,10 @@ const docTemplate = `{
                 "github",
                 "oidc",
                 "token",
-                "none"
+                "oauth2_provider_app",
+                "none",
+                "service_account",
+                "ldap"
             ],
             "x-enum-varnames": [
                 "LoginTypeUnknown",
@@ -8571,9 +9730,27 @@ const docTemplate = `{
                 "LoginTypeGithub",
                 "LoginTypeOIDC",
                 "LoginTypeToken",
-                "LoginTypeNone"
+                "LoginTypeOAuth2ProviderApp",
+                "LoginTypeNone",
+                "LoginTypeServiceAccount",
+                "LoginTypeLDAP"
             ]
         },
+        "codersdk.LoginWithLDAPRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "username"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
         "codersdk.LoginWithPasswordRequest": {
             "type": "object",
             "required": [
@@ -8585,6 +9762,10 @@ const docTemplate = `{
                     "type": "string",
                     "format": "email"
                 },
+                "mfa_code": {
+                    "description": "MFACode is a code from the authenticator of the user, or one of their\nrecovery codes. It is required for users that enrolled in MFA.",
+                    "type": "string"
+                },
                 "password": {
                     "type": "string"
                 }
@@ -8596,11 +9777,29 @@ const docTemplate = `{
                 "session_token"
             ],
             "properties": {
+                "mfa_recovery_codes": {
+                    "description": "MFARecoveryCodes are returned once, when the login completed an MFA\nenrollment the deployment requires.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "session_token": {
                     "type": "string"
                 }
             }
         },
+        "codersdk.MFARecoveryCodes": {
+            "type": "object",
+            "properties": {
+                "recovery_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
         "codersdk.MinimalUser": {
             "type": "object",
             "required": [
@@ -8621,6 +9820,17 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.OAuth2AppEndpoints": {
+            "type": "object",
+            "properties": {
+                "authorization": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
         "codersdk.OAuth2Config": {
             "type": "object",
             "properties": {
@@ -8644,49 +9854,206 @@ const docTemplate = `{
                         "type": "string"
                     }
                 },
-                "allowed_teams": {
+                "allowed_teams": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "client_id": {
+                    "type": "string"
+                },
+                "client_secret": {
+                    "type": "string"
+                },
+                "enterprise_base_url": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.OAuth2ProviderApp": {
+            "type": "object",
+            "properties": {
+                "callback_url": {
+                    "type": "string"
+                },
+                "endpoints": {
+                    "description": "Endpoints are included in the app response for easier discovery. The\nOAuth2 spec does not have a defined place to find these (for comparison,\nOIDC has a '/.well-known/openid-configuration' endpoint).",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.OAuth2AppEndpoints"
+                        }
+                    ]
+                },
+                "icon": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.OAuth2ProviderAppSecret": {
+            "type": "object",
+            "properties": {
+                "client_secret_truncated": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "last_used_at": {
+                    "type": "string",
+                    "format": "date-time"
+                }
+            }
+        },
+        "codersdk.OAuth2ProviderAppSecretFull": {
+            "type": "object",
+            "properties": {
+                "client_secret_full": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "codersdk.OAuthConversionResponse": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "state_string": {
+                    "type": "string"
+                },
+                "to_type": {
+                    "$ref": "#/definitions/codersdk.LoginType"
+                },
+                "user_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "codersdk.OIDCAuthMethod": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "iconUrl": {
+                    "type": "string"
+                },
+                "signInText": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.OIDCConfig": {
+            "type": "object",
+            "properties": {
+                "allow_signups": {
+                    "type": "boolean"
+                },
+                "auth_url_params": {
+                    "type": "object"
+                },
+                "client_cert_file": {
+                    "type": "string"
+                },
+                "client_id": {
+                    "type": "string"
+                },
+                "client_key_file": {
+                    "description": "ClientKeyFile \u0026 ClientCertFile are used in place of ClientSecret for PKI auth.",
+                    "type": "string"
+                },
+                "client_secret": {
+                    "type": "string"
+                },
+                "email_domain": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "email_field": {
+                    "type": "string"
+                },
+                "group_auto_create": {
+                    "type": "boolean"
+                },
+                "group_mapping": {
+                    "type": "object"
+                },
+                "group_regex_filter": {
+                    "$ref": "#/definitions/clibase.Regexp"
+                },
+                "groups_field": {
+                    "type": "string"
+                },
+                "icon_url": {
+                    "$ref": "#/definitions/clibase.URL"
+                },
+                "ignore_email_verified": {
+                    "type": "boolean"
+                },
+                "ignore_user_info": {
+                    "type": "boolean"
+                },
+                "issuer_url": {
+                    "type": "string"
+                },
+                "providers": {
+                    "description": "Providers are additional OIDC providers users can sign in with.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/clibase.Struct-array_codersdk_OIDCProviderConfig"
+                        }
+                    ]
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "sign_in_text": {
+                    "type": "string"
+                },
+                "user_role_field": {
+                    "type": "string"
+                },
+                "user_role_mapping": {
+                    "type": "object"
+                },
+                "user_roles_default": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
                 },
-                "client_id": {
-                    "type": "string"
-                },
-                "client_secret": {
-                    "type": "string"
-                },
-                "enterprise_base_url": {
+                "username_field": {
                     "type": "string"
                 }
             }
         },
-        "codersdk.OAuthConversionResponse": {
+        "codersdk.OIDCProviderAuthMethod": {
             "type": "object",
             "properties": {
-                "expires_at": {
-                    "type": "string",
-                    "format": "date-time"
-                },
-                "state_string": {
+                "iconUrl": {
                     "type": "string"
                 },
-                "to_type": {
-                    "$ref": "#/definitions/codersdk.LoginType"
-                },
-                "user_id": {
-                    "type": "string",
-                    "format": "uuid"
-                }
-            }
-        },
-        "codersdk.OIDCAuthMethod": {
-            "type": "object",
-            "properties": {
-                "enabled": {
-                    "type": "boolean"
-                },
-                "iconUrl": {
+                "id": {
                     "type": "string"
                 },
                 "signInText": {
@@ -8694,28 +10061,21 @@ const docTemplate = `{
                 }
             }
         },
-        "codersdk.OIDCConfig": {
+        "codersdk.OIDCProviderConfig": {
             "type": "object",
             "properties": {
                 "allow_signups": {
                     "type": "boolean"
                 },
                 "auth_url_params": {
-                    "type": "object"
-                },
-                "client_cert_file": {
-                    "type": "string"
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
                 },
                 "client_id": {
                     "type": "string"
                 },
-                "client_key_file": {
-                    "description": "ClientKeyFile \u0026 ClientCertFile are used in place of ClientSecret for PKI auth.",
-                    "type": "string"
-                },
-                "client_secret": {
-                    "type": "string"
-                },
                 "email_domain": {
                     "type": "array",
                     "items": {
@@ -8729,16 +10089,20 @@ const docTemplate = `{
                     "type": "boolean"
                 },
                 "group_mapping": {
-                    "type": "object"
-                },
-                "group_regex_filter": {
-                    "$ref": "#/definitions/clibase.Regexp"
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
                 },
                 "groups_field": {
                     "type": "string"
                 },
                 "icon_url": {
-                    "$ref": "#/definitions/clibase.URL"
+                    "type": "string"
+                },
+                "id": {
+                    "description": "ID identifies the provider in its callback URL and user links.",
+                    "type": "string"
                 },
                 "ignore_email_verified": {
                     "type": "boolean"
@@ -8762,7 +10126,13 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "user_role_mapping": {
-                    "type": "object"
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        }
+                    }
                 },
                 "user_roles_default": {
                     "type": "array",
@@ -8895,6 +10265,24 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.PostOAuth2ProviderAppRequest": {
+            "type": "object",
+            "required": [
+                "callback_url",
+                "name"
+            ],
+            "properties": {
+                "callback_url": {
+                    "type": "string"
+                },
+                "icon": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
         "codersdk.PprofConfig": {
             "type": "object",
             "properties": {
@@ -8953,13 +10341,37 @@ const docTemplate = `{
                     "type": "string",
                     "format": "date-time"
                 },
+                "current_job": {
+                    "description": "CurrentJob is the job the daemon is running, if any.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.ProvisionerDaemonJob"
+                        }
+                    ]
+                },
+                "healthy": {
+                    "description": "Healthy is true if the daemon has sent a heartbeat recently.",
+                    "type": "boolean"
+                },
                 "id": {
                     "type": "string",
                     "format": "uuid"
                 },
+                "last_seen_at": {
+                    "description": "LastSeenAt is the last time the daemon sent a heartbeat.",
+                    "type": "string",
+                    "format": "date-time"
+                },
                 "name": {
                     "type": "string"
                 },
+                "provisioner_versions": {
+                    "description": "ProvisionerVersions maps a provisioner type to the version of the\nunderlying tool, e.g. \"terraform\": \"1.5.5\".",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
                 "provisioners": {
                     "type": "array",
                     "items": {
@@ -8979,6 +10391,38 @@ const docTemplate = `{
                             "$ref": "#/definitions/sql.NullTime"
                         }
                     ]
+                },
+                "version": {
+                    "description": "Version is the Coder version the daemon is running.",
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.ProvisionerDaemonJob": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "started_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "status": {
+                    "enum": [
+                        "pending",
+                        "running",
+                        "succeeded",
+                        "canceling",
+                        "canceled",
+                        "failed"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.ProvisionerJobStatus"
+                        }
+                    ]
                 }
             }
         },
@@ -9010,6 +10454,11 @@ const docTemplate = `{
                         }
                     ]
                 },
+                "estimated_start_at": {
+                    "description": "EstimatedStartAt is when a pending workspace build is expected to\nstart, based on its queue position, the number of provisioner daemons\nable to run it and the jobs they're running, and the median build time\nof the template. It is omitted when no estimate is available.",
+                    "type": "string",
+                    "format": "date-time"
+                },
                 "file_id": {
                     "type": "string",
                     "format": "uuid"
@@ -9173,6 +10622,24 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.PutOAuth2ProviderAppRequest": {
+            "type": "object",
+            "required": [
+                "callback_url",
+                "name"
+            ],
+            "properties": {
+                "callback_url": {
+                    "type": "string"
+                },
+                "icon": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
         "codersdk.RBACResource": {
             "type": "string",
             "enum": [
@@ -9321,6 +10788,15 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.ResetUserMFARequest": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Code is a current code from the authenticator, or a recovery code.",
+                    "type": "string"
+                }
+            }
+        },
         "codersdk.ResourceType": {
             "type": "string",
             "enum": [
@@ -9335,7 +10811,10 @@ const docTemplate = `{
                 "license",
                 "convert_login",
                 "workspace_proxy",
-                "organization"
+                "organization",
+                "oauth2_provider_app",
+                "oauth2_provider_app_secret",
+                "user_mfa"
             ],
             "x-enum-varnames": [
                 "ResourceTypeTemplate",
@@ -9349,7 +10828,10 @@ const docTemplate = `{
                 "ResourceTypeLicense",
                 "ResourceTypeConvertLogin",
                 "ResourceTypeWorkspaceProxy",
-                "ResourceTypeOrganization"
+                "ResourceTypeOrganization",
+                "ResourceTypeOAuth2ProviderApp",
+                "ResourceTypeOAuth2ProviderAppSecret",
+                "ResourceTypeUserMFA"
             ]
         },
         "codersdk.Response": {
@@ -9487,17 +10969,178 @@ const docTemplate = `{
                 "enable": {
                     "type": "boolean"
                 },
-                "key_file": {
+                "key_file": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "min_version": {
+                    "type": "string"
+                },
+                "redirect_http": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "codersdk.TOTPEnrollment": {
+            "type": "object",
+            "properties": {
+                "secret": {
+                    "type": "string"
+                },
+                "url": {
+                    "description": "URL is the \"otpauth://\" URL authenticator apps can scan as a QR code.",
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.TailnetConnection": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "description": "Address is the address of the peer if the path is direct.",
+                    "type": "string"
+                },
+                "last_handshake_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "path": {
+                    "enum": [
+                        "direct",
+                        "derp"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.TailnetPath"
+                        }
+                    ]
+                },
+                "relay": {
+                    "description": "Relay is the DERP region that relays packets to the peer.",
+                    "type": "string"
+                },
+                "rx_bytes": {
+                    "type": "integer"
+                },
+                "tx_bytes": {
+                    "type": "integer"
+                }
+            }
+        },
+        "codersdk.TailnetPath": {
+            "type": "string",
+            "enum": [
+                "direct",
+                "derp"
+            ],
+            "x-enum-varnames": [
+                "TailnetPathDirect",
+                "TailnetPathDERP"
+            ]
+        },
+        "codersdk.TailnetPeer": {
+            "type": "object",
+            "properties": {
+                "connected": {
+                    "description": "Connected is false for agents that clients have tunnels to, but that\naren't connected to the coordinator.",
+                    "type": "boolean"
+                },
+                "connection": {
+                    "description": "Connection is the connection of the replica that served the request to\nthe peer, if it has one.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.TailnetConnection"
+                        }
+                    ]
+                },
+                "coordinator_id": {
+                    "description": "CoordinatorID is the replica the peer is coordinated by. It's omitted if\nthe coordinator isn't shared by replicas.",
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "endpoints": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "last_node_update_at": {
+                    "description": "LastNodeUpdateAt is the last time the peer sent its node to the\ncoordinator.",
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "preferred_derp": {
+                    "type": "integer"
+                },
+                "type": {
+                    "enum": [
+                        "agent",
+                        "client"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.TailnetPeerType"
+                        }
+                    ]
+                }
+            }
+        },
+        "codersdk.TailnetPeerType": {
+            "type": "string",
+            "enum": [
+                "agent",
+                "client"
+            ],
+            "x-enum-varnames": [
+                "TailnetPeerTypeAgent",
+                "TailnetPeerTypeClient"
+            ]
+        },
+        "codersdk.TailnetStatus": {
+            "type": "object",
+            "properties": {
+                "ha": {
+                    "description": "HA is true if the coordinator is shared by replicas. Peers and tunnels\nof every replica are included.",
+                    "type": "boolean"
+                },
+                "peers": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/codersdk.TailnetPeer"
                     }
                 },
-                "min_version": {
-                    "type": "string"
+                "replica_id": {
+                    "description": "ReplicaID is the replica that served the request. Connections of peers\nare those of this replica.",
+                    "type": "string",
+                    "format": "uuid"
                 },
-                "redirect_http": {
-                    "type": "boolean"
+                "tunnels": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.TailnetTunnel"
+                    }
+                }
+            }
+        },
+        "codersdk.TailnetTunnel": {
+            "type": "object",
+            "properties": {
+                "dst": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "src": {
+                    "type": "string",
+                    "format": "uuid"
                 }
             }
         },
@@ -9567,6 +11210,14 @@ const docTemplate = `{
                 "display_name": {
                     "type": "string"
                 },
+                "egress_policy": {
+                    "description": "EgressPolicy restricts the destinations that the agents of workspaces\nforward connections to.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.EgressPolicy"
+                        }
+                    ]
+                },
                 "failure_ttl_ms": {
                     "description": "FailureTTLMillis, TimeTilDormantMillis, and TimeTilDormantAutoDeleteMillis are enterprise-only. Their\nvalues are used if your license is entitled to use the advanced\ntemplate scheduling feature.",
                     "type": "integer"
@@ -9907,6 +11558,11 @@ const docTemplate = `{
                         "$ref": "#/definitions/codersdk.Role"
                     }
                 },
+                "service_account_owner_group_id": {
+                    "description": "ServiceAccountOwnerGroupID is only set for service accounts. Members of\nthis group can issue tokens for the service account.",
+                    "type": "string",
+                    "format": "uuid"
+                },
                 "status": {
                     "enum": [
                         "active",
@@ -9981,6 +11637,12 @@ const docTemplate = `{
                 "authenticated": {
                     "type": "boolean"
                 },
+                "display_icon": {
+                    "type": "string"
+                },
+                "display_name": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "string"
                 },
@@ -10056,6 +11718,16 @@ const docTemplate = `{
                 },
                 "validation_regex": {
                     "type": "string"
+                },
+                "validation_rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.TemplateVersionParameterValidationRule"
+                    }
+                },
+                "visible_if": {
+                    "description": "VisibleIf is an expression that controls whether the parameter is\nshown, based on the values of other parameters. See\ncoderd/parameter for the evaluation rules.",
+                    "type": "string"
                 }
             }
         },
@@ -10076,6 +11748,17 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.TemplateVersionParameterValidationRule": {
+            "type": "object",
+            "properties": {
+                "condition": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                }
+            }
+        },
         "codersdk.TemplateVersionVariable": {
             "type": "object",
             "properties": {
@@ -10301,6 +11984,17 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.UploadGitSSHKeyRequest": {
+            "type": "object",
+            "required": [
+                "external_auth_provider_id"
+            ],
+            "properties": {
+                "external_auth_provider_id": {
+                    "type": "string"
+                }
+            }
+        },
         "codersdk.UploadResponse": {
             "type": "object",
             "properties": {
@@ -10355,6 +12049,11 @@ const docTemplate = `{
                         "$ref": "#/definitions/codersdk.Role"
                     }
                 },
+                "service_account_owner_group_id": {
+                    "description": "ServiceAccountOwnerGroupID is only set for service accounts. Members of\nthis group can issue tokens for the service account.",
+                    "type": "string",
+                    "format": "uuid"
+                },
                 "status": {
                     "enum": [
                         "active",
@@ -10439,6 +12138,25 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.UserMFA": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "enabled_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "recovery_codes_remaining": {
+                    "type": "integer"
+                },
+                "required": {
+                    "description": "Required is true when the deployment requires password users to use\nMFA, in which case it cannot be disabled by the user.",
+                    "type": "boolean"
+                }
+            }
+        },
         "codersdk.UserQuietHoursScheduleConfig": {
             "type": "object",
             "properties": {
@@ -10522,6 +12240,103 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.VerifyTOTPRequest": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.WorkloadIdentityExchangeRequest": {
+            "type": "object",
+            "required": [
+                "provider_id",
+                "token"
+            ],
+            "properties": {
+                "provider_id": {
+                    "description": "ProviderID is the ID of the configured provider that issued the token.",
+                    "type": "string"
+                },
+                "token": {
+                    "description": "Token is the OIDC JWT issued by the provider.",
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.WorkloadIdentityExchangeResponse": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "session_token": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.WorkloadIdentityProvider": {
+            "type": "object",
+            "properties": {
+                "audience": {
+                    "description": "Audience is the audience tokens must be issued for.",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "ID identifies the provider in exchange requests.",
+                    "type": "string"
+                },
+                "issuer": {
+                    "description": "Issuer is the issuer URL of the provider. Signing keys are discovered\nfrom its OpenID configuration.",
+                    "type": "string"
+                },
+                "rules": {
+                    "description": "Rules map tokens to Coder users. The first rule whose claims all match\nthe token is used.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkloadIdentityRule"
+                    }
+                }
+            }
+        },
+        "codersdk.WorkloadIdentityRule": {
+            "type": "object",
+            "properties": {
+                "claims": {
+                    "description": "Claims must all be equal to the claims of the token. A value ending in\n\"*\" matches any claim value with that prefix.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "scopes": {
+                    "description": "Scopes are fine-grained scopes to limit the issued token to. At least\none is required.",
+                    "type": "array",
+                    "items": {
+                        "enum": [
+                            "template:push",
+                            "template:read",
+                            "user:read",
+                            "workspace:read",
+                            "workspace:start"
+                        ],
+                        "$ref": "#/definitions/codersdk.APIKeyScope"
+                    }
+                },
+                "username": {
+                    "description": "Username is the user or service account to issue the token for.",
+                    "type": "string"
+                }
+            }
+        },
         "codersdk.Workspace": {
             "type": "object",
             "properties": {
@@ -10854,7 +12669,8 @@ const docTemplate = `{
                 "kubernetes",
                 "envbox",
                 "envbuilder",
-                "external"
+                "external",
+                "egress_policy"
             ],
             "x-enum-varnames": [
                 "WorkspaceAgentLogSourceStartupScript",
@@ -10862,7 +12678,8 @@ const docTemplate = `{
                 "WorkspaceAgentLogSourceKubernetes",
                 "WorkspaceAgentLogSourceEnvbox",
                 "WorkspaceAgentLogSourceEnvbuilder",
-                "WorkspaceAgentLogSourceExternal"
+                "WorkspaceAgentLogSourceExternal",
+                "WorkspaceAgentLogSourceEgressPolicy"
             ]
         },
         "codersdk.WorkspaceAgentMetadataDescription": {
@@ -11556,6 +13373,112 @@ const docTemplate = `{
                 }
             }
         },
+        "healthcheck.ProvisionerDaemonsReport": {
+            "type": "object",
+            "properties": {
+                "daemons": {
+                    "description": "Daemons is the number of registered provisioner daemons.",
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "healthy": {
+                    "type": "boolean"
+                },
+                "healthy_daemons": {
+                    "description": "HealthyDaemons is the number of daemons that have sent a heartbeat\nrecently.",
+                    "type": "integer"
+                },
+                "unserved_templates": {
+                    "description": "UnservedTemplates lists templates whose active version cannot be built\nbecause no healthy daemon matches its provisioner and tags.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/healthcheck.ProvisionerDaemonsUnservedTemplate"
+                    }
+                },
+                "versions": {
+                    "description": "Versions maps each Coder version reported by a healthy daemon to the\nnumber of daemons running it.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "healthcheck.ProvisionerDaemonsUnservedTemplate": {
+            "type": "object",
+            "properties": {
+                "provisioner": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "template_id": {
+                    "type": "string"
+                },
+                "template_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "healthcheck.ReplicaReport": {
+            "type": "object",
+            "properties": {
+                "database_latency_ms": {
+                    "type": "integer"
+                },
+                "error": {
+                    "description": "Error is the error the replica encountered when dialing the DERP\nservers of the replicas it meshes with.",
+                    "type": "string"
+                },
+                "gateway": {
+                    "description": "Gateway is true if the replica meshes its DERP server with the replicas\nof other regions.",
+                    "type": "boolean"
+                },
+                "healthy": {
+                    "type": "boolean"
+                },
+                "hostname": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "region_id": {
+                    "type": "integer"
+                },
+                "relay_address": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "healthcheck.ReplicasReport": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "healthy": {
+                    "type": "boolean"
+                },
+                "replicas": {
+                    "description": "Replicas are the primary replicas that are alive, by region. It's empty\nif the deployment doesn't run multiple replicas.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/healthcheck.ReplicaReport"
+                    }
+                }
+            }
+        },
         "healthcheck.Report": {
             "type": "object",
             "properties": {
@@ -11583,6 +13506,12 @@ const docTemplate = `{
                     "description": "Healthy is true if the report returns no errors.",
                     "type": "boolean"
                 },
+                "provisioner_daemons": {
+                    "$ref": "#/definitions/healthcheck.ProvisionerDaemonsReport"
+                },
+                "replicas": {
+                    "$ref": "#/definitions/healthcheck.ReplicasReport"
+                },
                 "time": {
                     "description": "Time is the time the report was generated at.",
                     "type": "string"
@@ -12024,6 +13953,10 @@ const docTemplate = `{
                 "derp_mesh_key": {
                     "type": "string"
                 },
+                "derp_only": {
+                    "description": "DerpOnly is true if the proxy must only serve DERP, even if it didn't\nask for it, because its version is incompatible with the primary.",
+                    "type": "boolean"
+                },
                 "derp_region_id": {
                     "type": "integer"
                 },
@@ -12033,6 +13966,14 @@ const docTemplate = `{
                     "items": {
                         "$ref": "#/definitions/codersdk.Replica"
                     }
+                },
+                "version_compatibility": {
+                    "description": "VersionCompatibility is how compatible the primary found the version of\nthe proxy.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/wsproxysdk.VersionCompatibility"
+                        }
+                    ]
                 }
             }
         },
@@ -12046,6 +13987,19 @@ const docTemplate = `{
                     }
                 }
             }
+        },
+        "wsproxysdk.VersionCompatibility": {
+            "type": "string",
+            "enum": [
+                "compatible",
+                "degraded",
+                "incompatible"
+            ],
+            "x-enum-varnames": [
+                "VersionCompatible",
+                "VersionDegraded",
+                "VersionIncompatible"
+            ]
         }
     },
     "securityDefinitions": {
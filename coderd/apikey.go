@@ -10,11 +10,13 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/moby/moby/pkg/namesgenerator"
+	"golang.org/x/exp/slices"
 	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/v2/coderd/apikey"
 	"github.com/coder/coder/v2/coderd/audit"
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
@@ -60,6 +62,41 @@ func (api *API) postToken(rw http.ResponseWriter, r *http.Request) {
 		scope = database.APIKeyScope(createToken.Scope)
 	}
 
+	scopes := make([]string, 0, len(createToken.Scopes))
+	for _, name := range createToken.Scopes {
+		if !rbac.ScopeName(name).FineGrained() {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: fmt.Sprintf("Scope %q is not a fine-grained scope.", name),
+				Validations: []codersdk.ValidationError{{
+					Field:  "scopes",
+					Detail: fmt.Sprintf("Must be one of %q.", rbac.FineGrainedScopes()),
+				}},
+			})
+			return
+		}
+		scopes = append(scopes, string(name))
+	}
+	if len(scopes) > 0 && createToken.Scope != "" && createToken.Scope != codersdk.APIKeyScopeAll {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Scope %q cannot be combined with fine-grained scopes.", createToken.Scope),
+		})
+		return
+	}
+	if len(createToken.AllowList) > 0 && len(scopes) == 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "An allow list can only be used with fine-grained scopes.",
+			Validations: []codersdk.ValidationError{{
+				Field:  "scopes",
+				Detail: fmt.Sprintf("Must contain at least one of %q.", rbac.FineGrainedScopes()),
+			}},
+		})
+		return
+	}
+	allowList := make([]string, 0, len(createToken.AllowList))
+	for _, id := range createToken.AllowList {
+		allowList = append(allowList, id.String())
+	}
+
 	// default lifetime is 30 days
 	lifeTime := 30 * 24 * time.Hour
 	if createToken.Lifetime != 0 {
@@ -81,16 +118,40 @@ func (api *API) postToken(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	cookie, key, err := api.createAPIKey(ctx, apikey.CreateParams{
+	createCtx := ctx
+	if isServiceAccountOwner(r, user) {
+		// The caller's key must be allowed to create tokens for the caller,
+		// otherwise a narrowly scoped key could mint an unrestricted token
+		// for the service account. Keys issued to OAuth2 apps act for the
+		// user in that app only, so they never qualify.
+		callerKey := httpmw.APIKey(r)
+		if callerKey.LoginType == database.LoginTypeOAuth2ProviderApp ||
+			!api.Authorize(r, rbac.ActionCreate, rbac.ResourceAPIKey.WithOwner(callerKey.UserID.String())) {
+			httpapi.Forbidden(rw)
+			return
+		}
+		// Members of the owner group issue tokens for the service account
+		// without otherwise having permission to act on its behalf.
+		//nolint:gocritic // Owner group membership and the caller's scope are checked above.
+		createCtx = dbauthz.AsSystemRestricted(ctx)
+	}
+
+	cookie, key, err := api.createAPIKey(createCtx, apikey.CreateParams{
 		UserID:           user.ID,
 		LoginType:        database.LoginTypeToken,
 		DeploymentValues: api.DeploymentValues,
 		ExpiresAt:        dbtime.Now().Add(lifeTime),
 		Scope:            scope,
+		Scopes:           scopes,
+		AllowList:        allowList,
 		LifetimeSeconds:  int64(lifeTime.Seconds()),
 		TokenName:        tokenName,
 	})
 	if err != nil {
+		if dbauthz.IsNotAuthorizedError(err) {
+			httpapi.Forbidden(rw)
+			return
+		}
 		if database.IsUniqueViolation(err, database.UniqueIndexApiKeyName) {
 			httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
 				Message: fmt.Sprintf("A token with name %q already exists.", tokenName),
@@ -233,19 +294,17 @@ func (api *API) tokens(rw http.ResponseWriter, r *http.Request) {
 		includeAll, _ = strconv.ParseBool(queryStr)
 	)
 
-	if includeAll {
-		// get tokens for all users
-		keys, err = api.Database.GetAPIKeysByLoginType(ctx, database.LoginTypeToken)
-		if err != nil {
-			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
-				Message: "Internal error fetching API keys.",
-				Detail:  err.Error(),
-			})
-			return
+	// Keys issued to OAuth2 apps are listed alongside tokens so users can
+	// revoke an app's access.
+	for _, loginType := range []database.LoginType{database.LoginTypeToken, database.LoginTypeOAuth2ProviderApp} {
+		var loginTypeKeys []database.APIKey
+		if includeAll {
+			// get tokens for all users
+			loginTypeKeys, err = api.Database.GetAPIKeysByLoginType(ctx, loginType)
+		} else {
+			// get user's tokens only
+			loginTypeKeys, err = api.Database.GetAPIKeysByUserID(ctx, database.GetAPIKeysByUserIDParams{LoginType: loginType, UserID: user.ID})
 		}
-	} else {
-		// get user's tokens only
-		keys, err = api.Database.GetAPIKeysByUserID(ctx, database.GetAPIKeysByUserIDParams{LoginType: database.LoginTypeToken, UserID: user.ID})
 		if err != nil {
 			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
 				Message: "Internal error fetching API keys.",
@@ -253,6 +312,7 @@ func (api *API) tokens(rw http.ResponseWriter, r *http.Request) {
 			})
 			return
 		}
+		keys = append(keys, loginTypeKeys...)
 	}
 
 	keys, err = AuthorizeFilter(api.HTTPAuth, r, rbac.ActionRead, keys)
@@ -374,6 +434,15 @@ func (api *API) validateAPIKeyLifetime(lifetime time.Duration) error {
 	return nil
 }
 
+// isServiceAccountOwner returns true if the requester is a member of the group
+// that owns the given service account.
+func isServiceAccountOwner(r *http.Request, user database.User) bool {
+	if user.LoginType != database.LoginTypeServiceAccount || !user.ServiceAccountOwnerGroupID.Valid {
+		return false
+	}
+	return slices.Contains(httpmw.UserAuthorization(r).Actor.Groups, user.ServiceAccountOwnerGroupID.UUID.String())
+}
+
 func (api *API) createAPIKey(ctx context.Context, params apikey.CreateParams) (*http.Cookie, *database.APIKey, error) {
 	key, sessionToken, err := apikey.Generate(params)
 	if err != nil {
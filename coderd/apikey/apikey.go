@@ -12,6 +12,7 @@ import (
 
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/cryptorand"
 )
@@ -25,8 +26,13 @@ type CreateParams struct {
 	ExpiresAt       time.Time
 	LifetimeSeconds int64
 	Scope           database.APIKeyScope
+	Scopes          []string
+	AllowList       []string
 	TokenName       string
 	RemoteAddr      string
+	// ProviderID is the ID of the additional OIDC provider the user
+	// authenticated with.
+	ProviderID string
 }
 
 // Generate generates an API key, returning the key as a string as well as the
@@ -70,6 +76,20 @@ func Generate(params CreateParams) (database.InsertAPIKeyParams, string, error)
 	default:
 		return database.InsertAPIKeyParams{}, "", xerrors.Errorf("invalid API key scope: %q", scope)
 	}
+	if len(params.Scopes) > 0 && scope != database.APIKeyScopeAll {
+		return database.InsertAPIKeyParams{}, "", xerrors.Errorf("scope %q cannot be combined with fine-grained scopes", scope)
+	}
+	for _, name := range params.Scopes {
+		if !rbac.ScopeName(name).FineGrained() {
+			return database.InsertAPIKeyParams{}, "", xerrors.Errorf("invalid API key scope: %q", name)
+		}
+	}
+	if len(params.AllowList) > 0 && len(params.Scopes) == 0 {
+		return database.InsertAPIKeyParams{}, "", xerrors.New("an allow list requires fine-grained scopes")
+	}
+	// The columns are not nullable.
+	scopes := append([]string{}, params.Scopes...)
+	allowList := append([]string{}, params.AllowList...)
 
 	token := fmt.Sprintf("%s-%s", keyID, keySecret)
 
@@ -91,7 +111,10 @@ func Generate(params CreateParams) (database.InsertAPIKeyParams, string, error)
 		HashedSecret: hashed[:],
 		LoginType:    params.LoginType,
 		Scope:        scope,
+		Scopes:       scopes,
+		AllowList:    allowList,
 		TokenName:    params.TokenName,
+		ProviderID:   params.ProviderID,
 	}, token, nil
 }
 
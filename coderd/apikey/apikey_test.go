@@ -95,6 +95,63 @@ func TestGenerate(t *testing.T) {
 				Scope:            "",
 			},
 		},
+		{
+			name: "FineGrainedScopes",
+			params: apikey.CreateParams{
+				UserID:           uuid.New(),
+				LoginType:        database.LoginTypeToken,
+				DeploymentValues: &codersdk.DeploymentValues{},
+				ExpiresAt:        time.Now().Add(time.Hour),
+				LifetimeSeconds:  int64(time.Hour.Seconds()),
+				TokenName:        "hello",
+				RemoteAddr:       "1.2.3.4",
+				Scopes:           []string{"workspace:read", "template:push"},
+				AllowList:        []string{uuid.NewString()},
+			},
+		},
+		{
+			name: "InvalidFineGrainedScope",
+			params: apikey.CreateParams{
+				UserID:           uuid.New(),
+				LoginType:        database.LoginTypeToken,
+				DeploymentValues: &codersdk.DeploymentValues{},
+				ExpiresAt:        time.Now().Add(time.Hour),
+				LifetimeSeconds:  int64(time.Hour.Seconds()),
+				TokenName:        "hello",
+				RemoteAddr:       "1.2.3.4",
+				Scopes:           []string{"application_connect"},
+			},
+			fail: true,
+		},
+		{
+			name: "AllowListWithoutScopes",
+			params: apikey.CreateParams{
+				UserID:           uuid.New(),
+				LoginType:        database.LoginTypeToken,
+				DeploymentValues: &codersdk.DeploymentValues{},
+				ExpiresAt:        time.Now().Add(time.Hour),
+				LifetimeSeconds:  int64(time.Hour.Seconds()),
+				TokenName:        "hello",
+				RemoteAddr:       "1.2.3.4",
+				AllowList:        []string{uuid.NewString()},
+			},
+			fail: true,
+		},
+		{
+			name: "FineGrainedScopesWithCoarseScope",
+			params: apikey.CreateParams{
+				UserID:           uuid.New(),
+				LoginType:        database.LoginTypeToken,
+				DeploymentValues: &codersdk.DeploymentValues{},
+				ExpiresAt:        time.Now().Add(time.Hour),
+				LifetimeSeconds:  int64(time.Hour.Seconds()),
+				TokenName:        "hello",
+				RemoteAddr:       "1.2.3.4",
+				Scope:            database.APIKeyScopeApplicationConnect,
+				Scopes:           []string{"workspace:read"},
+			},
+			fail: true,
+		},
 	}
 
 	for _, tc := range cases {
@@ -153,6 +210,11 @@ func TestGenerate(t *testing.T) {
 			} else {
 				assert.Equal(t, database.APIKeyScopeAll, key.Scope)
 			}
+			// The columns are not nullable, so these are never nil.
+			assert.NotNil(t, key.Scopes)
+			assert.ElementsMatch(t, tc.params.Scopes, key.Scopes)
+			assert.NotNil(t, key.AllowList)
+			assert.ElementsMatch(t, tc.params.AllowList, key.AllowList)
 
 			if tc.params.TokenName != "" {
 				assert.Equal(t, tc.params.TokenName, key.TokenName)
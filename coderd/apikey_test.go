@@ -1,12 +1,14 @@
 package coderd_test
 
 import (
+	"bytes"
 	"context"
 	"net/http"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -17,6 +19,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbtestutil"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisioner/echo"
 	"github.com/coder/coder/v2/testutil"
 )
 
@@ -85,6 +88,222 @@ func TestTokenScoped(t *testing.T) {
 	require.Equal(t, keys[0].Scope, codersdk.APIKeyScopeApplicationConnect)
 }
 
+func TestTokenFineGrainedScopes(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Scopes", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		res, err := client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+			Scopes: []codersdk.APIKeyScope{codersdk.APIKeyScopeWorkspaceRead, codersdk.APIKeyScopeUserRead},
+		})
+		require.NoError(t, err)
+
+		keys, err := client.Tokens(ctx, codersdk.Me, codersdk.TokensFilter{})
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+		require.Equal(t, codersdk.APIKeyScopeAll, keys[0].Scope)
+		require.ElementsMatch(t, []codersdk.APIKeyScope{codersdk.APIKeyScopeWorkspaceRead, codersdk.APIKeyScopeUserRead}, keys[0].Scopes)
+
+		scoped := codersdk.New(client.URL)
+		scoped.SetSessionToken(res.Key)
+		_, err = scoped.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		_, err = scoped.Workspaces(ctx, codersdk.WorkspaceFilter{})
+		require.NoError(t, err)
+
+		// The owner role allows this, but the scope does not.
+		_, err = scoped.DeploymentConfig(ctx)
+		var sdkErr *codersdk.Error
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, http.StatusForbidden, sdkErr.StatusCode())
+	})
+
+	t.Run("AllowList", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		allowed := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, allowed.LatestBuild.ID)
+		other := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, other.LatestBuild.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		res, err := client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+			Scopes:    []codersdk.APIKeyScope{codersdk.APIKeyScopeWorkspaceRead},
+			AllowList: []uuid.UUID{allowed.ID, template.ID},
+		})
+		require.NoError(t, err)
+
+		scoped := codersdk.New(client.URL)
+		scoped.SetSessionToken(res.Key)
+		_, err = scoped.Workspace(ctx, allowed.ID)
+		require.NoError(t, err)
+		_, err = scoped.Workspace(ctx, other.ID)
+		require.Error(t, err)
+
+		workspaces, err := scoped.Workspaces(ctx, codersdk.WorkspaceFilter{})
+		require.NoError(t, err)
+		require.Len(t, workspaces.Workspaces, 1)
+		require.Equal(t, allowed.ID, workspaces.Workspaces[0].ID)
+
+		// New keys are not in the allow list, so the token cannot be used
+		// to mint an unrestricted one.
+		_, err = scoped.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{})
+		var sdkErr *codersdk.Error
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, http.StatusForbidden, sdkErr.StatusCode())
+	})
+
+	t.Run("WorkspaceStart", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+		other := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, other.LatestBuild.ID)
+
+		for _, tc := range []struct {
+			name      string
+			allowList []uuid.UUID
+		}{
+			{name: "Scoped"},
+			// Builds read the template and its versions, so the template
+			// must be allowed along with the workspace.
+			{name: "AllowList", allowList: []uuid.UUID{workspace.ID, template.ID}},
+		} {
+			ctx := testutil.Context(t, testutil.WaitLong)
+			res, err := client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+				Scopes:    []codersdk.APIKeyScope{codersdk.APIKeyScopeWorkspaceStart},
+				AllowList: tc.allowList,
+			})
+			require.NoError(t, err, tc.name)
+
+			scoped := codersdk.New(client.URL)
+			scoped.SetSessionToken(res.Key)
+			for _, transition := range []codersdk.WorkspaceTransition{codersdk.WorkspaceTransitionStop, codersdk.WorkspaceTransitionStart} {
+				build, err := scoped.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+					Transition: transition,
+				})
+				require.NoError(t, err, tc.name)
+				build = coderdtest.AwaitWorkspaceBuildJob(t, client, build.ID)
+				require.Equal(t, codersdk.ProvisionerJobSucceeded, build.Job.Status, tc.name)
+			}
+
+			// The scope does not allow deleting workspaces.
+			_, err = scoped.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+				Transition: codersdk.WorkspaceTransitionDelete,
+			})
+			require.Error(t, err, tc.name)
+
+			build, err := scoped.CreateWorkspaceBuild(ctx, other.ID, codersdk.CreateWorkspaceBuildRequest{
+				Transition: codersdk.WorkspaceTransitionStop,
+			})
+			if tc.allowList != nil {
+				require.Error(t, err, tc.name)
+				continue
+			}
+			require.NoError(t, err, tc.name)
+			coderdtest.AwaitWorkspaceBuildJob(t, client, build.ID)
+		}
+	})
+
+	t.Run("TemplatePush", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		otherTemplate := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+		for _, tc := range []struct {
+			name      string
+			allowList []uuid.UUID
+		}{
+			{name: "Scoped"},
+			{name: "AllowList", allowList: []uuid.UUID{template.ID}},
+		} {
+			ctx := testutil.Context(t, testutil.WaitLong)
+			res, err := client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+				Scopes:    []codersdk.APIKeyScope{codersdk.APIKeyScopeTemplatePush},
+				AllowList: tc.allowList,
+			})
+			require.NoError(t, err, tc.name)
+
+			scoped := codersdk.New(client.URL)
+			scoped.SetSessionToken(res.Key)
+			// This is what "coder templates push" does.
+			data, err := echo.Tar(nil)
+			require.NoError(t, err)
+			file, err := scoped.Upload(ctx, codersdk.ContentTypeTar, bytes.NewReader(data))
+			require.NoError(t, err, tc.name)
+			pushed, err := scoped.CreateTemplateVersion(ctx, user.OrganizationID, codersdk.CreateTemplateVersionRequest{
+				TemplateID:    template.ID,
+				FileID:        file.ID,
+				StorageMethod: codersdk.ProvisionerStorageMethodFile,
+				Provisioner:   codersdk.ProvisionerTypeEcho,
+			})
+			require.NoError(t, err, tc.name)
+			pushed = coderdtest.AwaitTemplateVersionJob(t, scoped, pushed.ID)
+			require.Equal(t, codersdk.ProvisionerJobSucceeded, pushed.Job.Status, tc.name)
+			err = scoped.UpdateActiveTemplateVersion(ctx, template.ID, codersdk.UpdateActiveTemplateVersion{
+				ID: pushed.ID,
+			})
+			require.NoError(t, err, tc.name)
+			updated, err := client.Template(ctx, template.ID)
+			require.NoError(t, err)
+			require.Equal(t, pushed.ID, updated.ActiveVersionID, tc.name)
+
+			_, err = scoped.CreateTemplateVersion(ctx, user.OrganizationID, codersdk.CreateTemplateVersionRequest{
+				TemplateID:    otherTemplate.ID,
+				FileID:        file.ID,
+				StorageMethod: codersdk.ProvisionerStorageMethodFile,
+				Provisioner:   codersdk.ProvisionerTypeEcho,
+			})
+			if tc.allowList != nil {
+				require.Error(t, err, tc.name)
+			} else {
+				require.NoError(t, err, tc.name)
+			}
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		for _, req := range []codersdk.CreateTokenRequest{
+			{Scopes: []codersdk.APIKeyScope{codersdk.APIKeyScopeAll}},
+			{Scopes: []codersdk.APIKeyScope{"workspace:delete"}},
+			{Scope: codersdk.APIKeyScopeApplicationConnect, Scopes: []codersdk.APIKeyScope{codersdk.APIKeyScopeWorkspaceRead}},
+			{AllowList: []uuid.UUID{uuid.New()}},
+		} {
+			_, err := client.CreateToken(ctx, codersdk.Me, req)
+			var sdkErr *codersdk.Error
+			require.ErrorAs(t, err, &sdkErr)
+			require.Equal(t, http.StatusBadRequest, sdkErr.StatusCode())
+		}
+	})
+}
+
 func TestUserSetTokenDuration(t *testing.T) {
 	t.Parallel()
 
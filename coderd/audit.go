@@ -190,6 +190,7 @@ func (api *API) convertAuditLog(ctx context.Context, dblog database.GetAuditLogs
 			Status:    codersdk.UserStatus(dblog.UserStatus.UserStatus),
 			Roles:     []codersdk.Role{},
 			AvatarURL: dblog.UserAvatarUrl.String,
+			LoginType: codersdk.LoginType(dblog.UserLoginType.LoginType),
 		}
 
 		for _, roleName := range dblog.UserRoles {
@@ -89,6 +89,12 @@ func ResourceTarget[T Auditable](tgt T) string {
 		return typed.Name
 	case database.AuditOAuthConvertState:
 		return string(typed.ToLoginType)
+	case database.OAuth2ProviderApp:
+		return typed.Name
+	case database.OAuth2ProviderAppSecret:
+		return typed.DisplaySecret
+	case database.AuditableUserMFA:
+		return typed.Username
 	default:
 		panic(fmt.Sprintf("unknown resource %T", tgt))
 	}
@@ -119,6 +125,12 @@ func ResourceID[T Auditable](tgt T) uuid.UUID {
 	case database.AuditOAuthConvertState:
 		// The merge state is for the given user
 		return typed.UserID
+	case database.OAuth2ProviderApp:
+		return typed.ID
+	case database.OAuth2ProviderAppSecret:
+		return typed.ID
+	case database.AuditableUserMFA:
+		return typed.UserID
 	default:
 		panic(fmt.Sprintf("unknown resource %T", tgt))
 	}
@@ -148,6 +160,12 @@ func ResourceType[T Auditable](tgt T) database.ResourceType {
 		return database.ResourceTypeWorkspaceProxy
 	case database.AuditOAuthConvertState:
 		return database.ResourceTypeConvertLogin
+	case database.OAuth2ProviderApp:
+		return database.ResourceTypeOAuth2ProviderApp
+	case database.OAuth2ProviderAppSecret:
+		return database.ResourceTypeOAuth2ProviderAppSecret
+	case database.AuditableUserMFA:
+		return database.ResourceTypeUserMFA
 	default:
 		panic(fmt.Sprintf("unknown resource %T", typed))
 	}
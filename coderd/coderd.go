@@ -48,7 +48,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/database/pubsub"
-	"github.com/coder/coder/v2/coderd/gitauth"
+	"github.com/coder/coder/v2/coderd/externalauth"
 	"github.com/coder/coder/v2/coderd/gitsshkey"
 	"github.com/coder/coder/v2/coderd/healthcheck"
 	"github.com/coder/coder/v2/coderd/httpapi"
@@ -61,6 +61,7 @@ import (
 	"github.com/coder/coder/v2/coderd/tracing"
 	"github.com/coder/coder/v2/coderd/updatecheck"
 	"github.com/coder/coder/v2/coderd/util/slice"
+	"github.com/coder/coder/v2/coderd/workloadidentity"
 	"github.com/coder/coder/v2/coderd/workspaceapps"
 	"github.com/coder/coder/v2/coderd/wsconncache"
 	"github.com/coder/coder/v2/codersdk"
@@ -110,13 +111,16 @@ type Options struct {
 	GoogleTokenValidator           *idtoken.Validator
 	GithubOAuth2Config             *GithubOAuth2Config
 	OIDCConfig                     *OIDCConfig
+	OIDCProviders                  []*OIDCConfig
+	LDAPConfig                     *LDAPConfig
 	PrometheusRegistry             *prometheus.Registry
 	SecureAuthCookie               bool
 	StrictTransportSecurityCfg     httpmw.HSTSConfig
 	SSHKeygenAlgorithm             gitsshkey.Algorithm
 	Telemetry                      telemetry.Reporter
 	TracerProvider                 trace.TracerProvider
-	GitAuthConfigs                 []*gitauth.Config
+	GitAuthConfigs                 []*externalauth.Config
+	WorkloadIdentityProviders      []*workloadidentity.Provider
 	RealIPConfig                   *httpmw.RealIPConfig
 	TrialGenerator                 func(ctx context.Context, email string) error
 	// TLSCertificates is used to mesh DERP servers securely.
@@ -316,8 +320,12 @@ func New(options *Options) *API {
 	)
 
 	oauthConfigs := &httpmw.OAuth2Configs{
-		Github: options.GithubOAuth2Config,
-		OIDC:   options.OIDCConfig,
+		Github:        options.GithubOAuth2Config,
+		OIDC:          options.OIDCConfig,
+		OIDCProviders: map[string]httpmw.OAuth2Config{},
+	}
+	for _, oidcProvider := range options.OIDCProviders {
+		oauthConfigs.OIDCProviders[oidcProvider.ID] = oidcProvider
 	}
 
 	staticHandler := site.New(&site.Options{
@@ -375,8 +383,20 @@ func New(options *Options) *API {
 			*options.UpdateCheckOptions,
 		)
 	}
+	if options.LDAPConfig != nil && options.LDAPConfig.SyncInterval > 0 {
+		api.ldapSyncClose = api.startLDAPSync(api.ctx, options.LDAPConfig.SyncInterval)
+	}
+	if options.DeploymentValues.RequireMFA.Value() {
+		err := api.revokeSessionsWithoutMFA(api.ctx)
+		if err != nil {
+			api.Logger.Warn(api.ctx, "revoke sessions of users without mfa", slog.Error(err))
+		}
+	}
 	if options.HealthcheckFunc == nil {
 		options.HealthcheckFunc = func(ctx context.Context, apiKey string) *healthcheck.Report {
+			// nolint:gocritic // The healthcheck inspects provisioner daemons
+			// and templates across the deployment.
+			ctx = dbauthz.AsSystemRestricted(ctx)
 			return healthcheck.Run(ctx, &healthcheck.ReportOptions{
 				DB:        options.Database,
 				AccessURL: options.AccessURL,
@@ -399,6 +419,13 @@ func New(options *Options) *API {
 
 	api.Auditor.Store(&options.Auditor)
 	api.TailnetCoordinator.Store(&options.TailnetCoordinator)
+	api.TailnetClientService, err = tailnet.NewClientService(
+		api.Logger.Named("tailnetclient"),
+		&api.TailnetCoordinator,
+	)
+	if err != nil {
+		panic("failed to initialize tailnet client service: " + err.Error())
+	}
 	if api.Experiments.Enabled(codersdk.ExperimentSingleTailnet) {
 		api.agentProvider, err = NewServerTailnet(api.ctx,
 			options.Logger,
@@ -553,6 +580,22 @@ func New(options *Options) *API {
 		}
 	})
 
+	// Coder acting as an OAuth2 authorization server for third-party apps.
+	r.Route("/oauth2", func(r chi.Router) {
+		r.Route("/authorize", func(r chi.Router) {
+			// Users are sent here by the app, so they may need to log in first.
+			r.Use(apiKeyMiddlewareRedirect)
+			r.Get("/", api.getOAuth2ProviderAppAuthorize)
+			// Submitted by the page rendered above when the user allows the app.
+			r.Post("/", api.postOAuth2ProviderAppAuthorize)
+		})
+		r.Route("/tokens", func(r chi.Router) {
+			// Apps authenticate with their client credentials instead.
+			r.Use(apiRateLimiter)
+			r.Post("/", api.postOAuth2ProviderAppToken)
+		})
+	})
+
 	r.Route("/api/v2", func(r chi.Router) {
 		api.APIHandler = r
 
@@ -702,6 +745,9 @@ func New(options *Options) *API {
 				// This value is intentionally increased during tests.
 				r.Use(httpmw.RateLimit(options.LoginRateLimit, time.Minute))
 				r.Post("/login", api.postLogin)
+				r.Post("/login/mfa/enroll", api.postLoginMFAEnroll)
+				r.Post("/ldap/login", api.postLDAPLogin)
+				r.Post("/workload-identity/exchange", api.postWorkloadIdentityExchange)
 				r.Route("/oauth2", func(r chi.Router) {
 					r.Route("/github", func(r chi.Router) {
 						r.Use(
@@ -716,6 +762,14 @@ func New(options *Options) *API {
 					)
 					r.Get("/", api.userOIDC)
 				})
+				for _, oidcProvider := range options.OIDCProviders {
+					r.Route(fmt.Sprintf("/oidc/%s/callback", oidcProvider.ID), func(r chi.Router) {
+						r.Use(
+							httpmw.ExtractOAuth2(oidcProvider, options.HTTPClient, oidcProvider.AuthURLParams),
+						)
+						r.Get("/", api.userOIDCProvider(oidcProvider))
+					})
+				}
 			})
 			r.Group(func(r chi.Router) {
 				r.Use(
@@ -742,6 +796,12 @@ func New(options *Options) *API {
 					r.Route("/password", func(r chi.Router) {
 						r.Put("/", api.putUserPassword)
 					})
+					r.Route("/mfa", func(r chi.Router) {
+						r.Get("/", api.userMFA)
+						r.Delete("/", api.deleteUserMFA)
+						r.Post("/totp", api.postUserTOTP)
+						r.Post("/totp/verify", api.postUserTOTPVerify)
+					})
 					// These roles apply to the site wide permissions.
 					r.Put("/roles", api.putUserRoles)
 					r.Get("/roles", api.userRoles)
@@ -772,6 +832,15 @@ func New(options *Options) *API {
 					})
 					r.Get("/gitsshkey", api.gitSSHKey)
 					r.Put("/gitsshkey", api.regenerateGitSSHKey)
+					r.Route("/gitsshkeys", func(r chi.Router) {
+						r.Get("/", api.gitSSHKeys)
+						r.Post("/", api.postGitSSHKey)
+						r.Route("/{keyname}", func(r chi.Router) {
+							r.Put("/", api.regenerateGitSSHKeyByName)
+							r.Delete("/", api.deleteGitSSHKey)
+							r.Post("/upload", api.uploadGitSSHKey)
+						})
+					})
 				})
 			})
 		})
@@ -801,7 +870,9 @@ func New(options *Options) *API {
 				r.Patch("/logs", api.patchWorkspaceAgentLogs)
 				r.Post("/app-health", api.postWorkspaceAppHealth)
 				r.Get("/gitauth", api.workspaceAgentsGitAuth)
+				r.Get("/external-auth", api.workspaceAgentsExternalAuth)
 				r.Get("/gitsshkey", api.agentGitSSHKey)
+				r.Get("/gitsshkeys", api.agentGitSSHKeys)
 				r.Get("/coordinate", api.workspaceAgentCoordinate)
 				r.Post("/report-stats", api.workspaceAgentReportStats)
 				r.Post("/report-lifecycle", api.workspaceAgentReportLifecycle)
@@ -889,6 +960,30 @@ func New(options *Options) *API {
 				r.Get("/", api.workspaceApplicationAuth)
 			})
 		})
+		r.Route("/oauth2-provider", func(r chi.Router) {
+			r.Use(apiKeyMiddleware)
+			r.Route("/apps", func(r chi.Router) {
+				r.Get("/", api.oAuth2ProviderApps)
+				r.Post("/", api.postOAuth2ProviderApp)
+
+				r.Route("/{app}", func(r chi.Router) {
+					r.Use(httpmw.ExtractOAuth2ProviderAppParam(options.Database))
+					r.Get("/", api.oAuth2ProviderApp)
+					r.Put("/", api.putOAuth2ProviderApp)
+					r.Delete("/", api.deleteOAuth2ProviderApp)
+
+					r.Route("/secrets", func(r chi.Router) {
+						r.Get("/", api.oAuth2ProviderAppSecrets)
+						r.Post("/", api.postOAuth2ProviderAppSecret)
+
+						r.Route("/{secretID}", func(r chi.Router) {
+							r.Use(httpmw.ExtractOAuth2ProviderAppSecretParam(options.Database))
+							r.Delete("/", api.deleteOAuth2ProviderAppSecret)
+						})
+					})
+				})
+			})
+		})
 		r.Route("/insights", func(r chi.Router) {
 			r.Use(apiKeyMiddleware)
 			r.Get("/daus", api.deploymentDAUs)
@@ -913,6 +1008,7 @@ func New(options *Options) *API {
 
 			r.Get("/coordinator", api.debugCoordinator)
 			r.Get("/health", api.debugDeploymentHealth)
+			r.Get("/tailnet", api.debugTailnetStatus)
 			r.Get("/ws", (&healthcheck.WebsocketEchoServer{}).ServeHTTP)
 		})
 	})
@@ -976,6 +1072,7 @@ type API struct {
 	Auditor                           atomic.Pointer[audit.Auditor]
 	WorkspaceClientCoordinateOverride atomic.Pointer[func(rw http.ResponseWriter) bool]
 	TailnetCoordinator                atomic.Pointer[tailnet.Coordinator]
+	TailnetClientService              *tailnet.ClientService
 	QuotaCommitter                    atomic.Pointer[proto.QuotaCommitter]
 	// WorkspaceProxyHostsFn returns the hosts of healthy workspace proxies
 	// for header reasons.
@@ -1005,6 +1102,7 @@ type API struct {
 
 	metricsCache          *metricscache.Cache
 	updateChecker         *updatecheck.Checker
+	ldapSyncClose         func()
 	WorkspaceAppsProvider workspaceapps.SignedTokenProvider
 	workspaceAppServer    *workspaceapps.Server
 	agentProvider         workspaceapps.AgentProvider
@@ -1032,6 +1130,9 @@ func (api *API) Close() error {
 	if api.updateChecker != nil {
 		api.updateChecker.Close()
 	}
+	if api.ldapSyncClose != nil {
+		api.ldapSyncClose()
+	}
 	_ = api.workspaceAppServer.Close()
 	coordinator := api.TailnetCoordinator.Load()
 	if coordinator != nil {
@@ -1066,6 +1167,15 @@ func compressHandler(h http.Handler) http.Handler {
 	return cmp.Handler(h)
 }
 
+// OIDCProviderConfigs returns the additional OIDC providers keyed by ID.
+func (api *API) OIDCProviderConfigs() map[string]httpmw.OAuth2Config {
+	configs := make(map[string]httpmw.OAuth2Config, len(api.OIDCProviders))
+	for _, provider := range api.OIDCProviders {
+		configs[provider.ID] = provider
+	}
+	return configs
+}
+
 // CreateInMemoryProvisionerDaemon is an in-memory connection to a provisionerd.
 // Useful when starting coderd and provisionerd in the same process.
 func (api *API) CreateInMemoryProvisionerDaemon(ctx context.Context, debounce time.Duration) (client proto.DRPCProvisionerDaemonClient, err error) {
@@ -1117,6 +1227,7 @@ func (api *API) CreateInMemoryProvisionerDaemon(ctx context.Context, debounce ti
 		debounce,
 		provisionerdserver.Options{
 			OIDCConfig:     api.OIDCConfig,
+			OIDCProviders:  api.OIDCProviderConfigs(),
 			GitAuthConfigs: api.GitAuthConfigs,
 		},
 	)
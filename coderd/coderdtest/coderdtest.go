@@ -59,7 +59,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbtestutil"
 	"github.com/coder/coder/v2/coderd/database/pubsub"
-	"github.com/coder/coder/v2/coderd/gitauth"
+	"github.com/coder/coder/v2/coderd/externalauth"
 	"github.com/coder/coder/v2/coderd/gitsshkey"
 	"github.com/coder/coder/v2/coderd/healthcheck"
 	"github.com/coder/coder/v2/coderd/httpapi"
@@ -70,6 +70,7 @@ import (
 	"github.com/coder/coder/v2/coderd/unhanger"
 	"github.com/coder/coder/v2/coderd/updatecheck"
 	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/coderd/workloadidentity"
 	"github.com/coder/coder/v2/coderd/workspaceapps"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/codersdk/agentsdk"
@@ -91,24 +92,27 @@ type Options struct {
 	// AccessURL denotes a custom access URL. By default we use the httptest
 	// server's URL. Setting this may result in unexpected behavior (especially
 	// with running agents).
-	AccessURL             *url.URL
-	AppHostname           string
-	AWSCertificates       awsidentity.Certificates
-	Authorizer            rbac.Authorizer
-	AzureCertificates     x509.VerifyOptions
-	GithubOAuth2Config    *coderd.GithubOAuth2Config
-	RealIPConfig          *httpmw.RealIPConfig
-	OIDCConfig            *coderd.OIDCConfig
-	GoogleTokenValidator  *idtoken.Validator
-	SSHKeygenAlgorithm    gitsshkey.Algorithm
-	AutobuildTicker       <-chan time.Time
-	AutobuildStats        chan<- autobuild.Stats
-	Auditor               audit.Auditor
-	TLSCertificates       []tls.Certificate
-	GitAuthConfigs        []*gitauth.Config
-	TrialGenerator        func(context.Context, string) error
-	TemplateScheduleStore schedule.TemplateScheduleStore
-	Coordinator           tailnet.Coordinator
+	AccessURL                 *url.URL
+	AppHostname               string
+	AWSCertificates           awsidentity.Certificates
+	Authorizer                rbac.Authorizer
+	AzureCertificates         x509.VerifyOptions
+	GithubOAuth2Config        *coderd.GithubOAuth2Config
+	RealIPConfig              *httpmw.RealIPConfig
+	OIDCConfig                *coderd.OIDCConfig
+	OIDCProviders             []*coderd.OIDCConfig
+	LDAPConfig                *coderd.LDAPConfig
+	GoogleTokenValidator      *idtoken.Validator
+	SSHKeygenAlgorithm        gitsshkey.Algorithm
+	AutobuildTicker           <-chan time.Time
+	AutobuildStats            chan<- autobuild.Stats
+	Auditor                   audit.Auditor
+	TLSCertificates           []tls.Certificate
+	GitAuthConfigs            []*externalauth.Config
+	WorkloadIdentityProviders []*workloadidentity.Provider
+	TrialGenerator            func(context.Context, string) error
+	TemplateScheduleStore     schedule.TemplateScheduleStore
+	Coordinator               tailnet.Coordinator
 
 	HealthcheckFunc    func(ctx context.Context, apiKey string) *healthcheck.Report
 	HealthcheckTimeout time.Duration
@@ -392,6 +396,7 @@ func NewOptions(t testing.TB, options *Options) (func(http.Handler), context.Can
 			Database:                       options.Database,
 			Pubsub:                         options.Pubsub,
 			GitAuthConfigs:                 options.GitAuthConfigs,
+			WorkloadIdentityProviders:      options.WorkloadIdentityProviders,
 
 			Auditor:                            options.Auditor,
 			AWSCertificates:                    options.AWSCertificates,
@@ -399,6 +404,8 @@ func NewOptions(t testing.TB, options *Options) (func(http.Handler), context.Can
 			GithubOAuth2Config:                 options.GithubOAuth2Config,
 			RealIPConfig:                       options.RealIPConfig,
 			OIDCConfig:                         options.OIDCConfig,
+			OIDCProviders:                      options.OIDCProviders,
+			LDAPConfig:                         options.LDAPConfig,
 			GoogleTokenValidator:               options.GoogleTokenValidator,
 			SSHKeygenAlgorithm:                 options.SSHKeygenAlgorithm,
 			DERPServer:                         derpServer,
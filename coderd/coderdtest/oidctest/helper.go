@@ -62,8 +62,9 @@ func (*LoginHelper) ExpireOauthToken(t *testing.T, db database.Store, user *code
 	require.NoError(t, err, "get api key")
 
 	link, err := db.GetUserLinkByUserIDLoginType(ctx, database.GetUserLinkByUserIDLoginTypeParams{
-		UserID:    key.UserID,
-		LoginType: database.LoginTypeOIDC,
+		UserID:     key.UserID,
+		LoginType:  database.LoginTypeOIDC,
+		ProviderID: key.ProviderID,
 	})
 	require.NoError(t, err, "get user link")
 
@@ -74,6 +75,7 @@ func (*LoginHelper) ExpireOauthToken(t *testing.T, db database.Store, user *code
 		OAuthExpiry:       time.Now().Add(time.Hour * -1),
 		UserID:            link.UserID,
 		LoginType:         link.LoginType,
+		ProviderID:        link.ProviderID,
 	})
 	require.NoError(t, err, "expire user link")
 
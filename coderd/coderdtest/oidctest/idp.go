@@ -73,6 +73,9 @@ type FakeIDP struct {
 	// to test something like PKI auth vs a client_secret.
 	hookAuthenticateClient func(t testing.TB, req *http.Request) (url.Values, error)
 	serve                  bool
+	// callbackPath is the Coderd callback the IDP redirects to. It is
+	// set by OIDCConfig, as additional providers have their own callback.
+	callbackPath string
 }
 
 type FakeIDPOpt func(idp *FakeIDP)
@@ -162,6 +165,7 @@ func NewFakeIDP(t testing.TB, opts ...FakeIDPOpt) *FakeIDP {
 		hookOnRefresh:        func(_ string) error { return nil },
 		hookUserInfo:         func(email string) jwt.MapClaims { return jwt.MapClaims{} },
 		hookValidRedirectURL: func(redirectURL string) error { return nil },
+		callbackPath:         "/api/v2/users/oidc/callback",
 	}
 
 	for _, opt := range opts {
@@ -258,7 +262,7 @@ func (f *FakeIDP) AttemptLogin(t testing.TB, client *codersdk.Client, idTokenCla
 func (f *FakeIDP) LoginWithClient(t testing.TB, client *codersdk.Client, idTokenClaims jwt.MapClaims, opts ...func(r *http.Request)) (*codersdk.Client, *http.Response) {
 	t.Helper()
 
-	coderOauthURL, err := client.URL.Parse("/api/v2/users/oidc/callback")
+	coderOauthURL, err := client.URL.Parse(f.callbackPath)
 	require.NoError(t, err)
 	f.SetRedirect(t, coderOauthURL.String())
 
@@ -405,8 +409,9 @@ func (f *FakeIDP) authenticateOIDCClientRequest(t testing.TB, req *http.Request)
 	return values, nil
 }
 
-// encodeClaims is a helper func to convert claims to a valid JWT.
-func (f *FakeIDP) encodeClaims(t testing.TB, claims jwt.MapClaims) string {
+// EncodeClaims is a helper func to convert claims to a valid JWT signed by
+// the IDP.
+func (f *FakeIDP) EncodeClaims(t testing.TB, claims jwt.MapClaims) string {
 	t.Helper()
 
 	if _, ok := claims["exp"]; !ok {
@@ -584,7 +589,7 @@ func (f *FakeIDP) httpHandler(t testing.TB) http.Handler {
 			"refresh_token": refreshToken,
 			"token_type":    "Bearer",
 			"expires_in":    int64((time.Minute * 5).Seconds()),
-			"id_token":      f.encodeClaims(t, claims),
+			"id_token":      f.EncodeClaims(t, claims),
 		}
 		// Store the claims for the next refresh
 		f.refreshIDTokenClaims.Store(refreshToken, claims)
@@ -763,6 +768,9 @@ func (f *FakeIDP) OIDCConfig(t testing.TB, scopes []string, opts ...func(cfg *co
 		opt(cfg)
 	}
 
+	if cfg.ID != "" {
+		f.callbackPath = fmt.Sprintf("/api/v2/users/oidc/%s/callback", cfg.ID)
+	}
 	f.cfg = oauthCfg
 
 	return cfg
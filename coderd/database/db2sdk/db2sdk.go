@@ -41,6 +41,11 @@ func TemplateVersionParameter(param database.TemplateVersionParameter) (codersdk
 		return codersdk.TemplateVersionParameter{}, err
 	}
 
+	validationRules, err := templateVersionParameterValidationRules(param.ValidationRules)
+	if err != nil {
+		return codersdk.TemplateVersionParameter{}, err
+	}
+
 	var validationMin *int32
 	if param.ValidationMin.Valid {
 		validationMin = &param.ValidationMin.Int32
@@ -68,6 +73,8 @@ func TemplateVersionParameter(param database.TemplateVersionParameter) (codersdk
 		ValidationMonotonic:  codersdk.ValidationMonotonicOrder(param.ValidationMonotonic),
 		Required:             param.Required,
 		Ephemeral:            param.Ephemeral,
+		VisibleIf:            param.VisibleIf,
+		ValidationRules:      validationRules,
 	}, nil
 }
 
@@ -109,6 +116,9 @@ func User(user database.User, organizationIDs []uuid.UUID) codersdk.User {
 		AvatarURL:       user.AvatarURL.String,
 		LoginType:       codersdk.LoginType(user.LoginType),
 	}
+	if user.ServiceAccountOwnerGroupID.Valid {
+		convertedUser.ServiceAccountOwnerGroupID = &user.ServiceAccountOwnerGroupID.UUID
+	}
 
 	for _, roleName := range user.RBACRoles {
 		rbacRole, _ := rbac.RoleByName(roleName)
@@ -125,6 +135,38 @@ func Role(role rbac.Role) codersdk.Role {
 	}
 }
 
+func EgressPolicy(policy database.EgressPolicy) codersdk.EgressPolicy {
+	rules := make([]codersdk.EgressRule, 0, len(policy.Rules))
+	for _, rule := range policy.Rules {
+		rules = append(rules, codersdk.EgressRule{
+			Action: codersdk.EgressAction(rule.Action),
+			CIDR:   rule.CIDR,
+			Ports:  rule.Ports,
+		})
+	}
+	return codersdk.EgressPolicy{
+		DefaultAction: codersdk.EgressAction(policy.DefaultAction),
+		Rules:         rules,
+	}
+}
+
+// DatabaseEgressPolicy converts the egress policy of an API request into the
+// form it's stored in.
+func DatabaseEgressPolicy(policy codersdk.EgressPolicy) database.EgressPolicy {
+	rules := make([]database.EgressRule, 0, len(policy.Rules))
+	for _, rule := range policy.Rules {
+		rules = append(rules, database.EgressRule{
+			Action: string(rule.Action),
+			CIDR:   rule.CIDR,
+			Ports:  rule.Ports,
+		})
+	}
+	return database.EgressPolicy{
+		DefaultAction: string(policy.DefaultAction),
+		Rules:         rules,
+	}
+}
+
 func TemplateInsightsParameters(parameterRows []database.GetTemplateParameterInsightsRow) ([]codersdk.TemplateParameterUsage, error) {
 	// Use a stable sort, similarly to how we would sort in the query, note that
 	// we don't sort in the query because order varies depending on the table
@@ -203,3 +245,22 @@ func templateVersionParameterOptions(rawOptions json.RawMessage) ([]codersdk.Tem
 	}
 	return options, nil
 }
+
+func templateVersionParameterValidationRules(rawRules json.RawMessage) ([]codersdk.TemplateVersionParameterValidationRule, error) {
+	if len(rawRules) == 0 {
+		return nil, nil
+	}
+	var protoRules []*proto.RichParameterValidationRule
+	err := json.Unmarshal(rawRules, &protoRules)
+	if err != nil {
+		return nil, err
+	}
+	var rules []codersdk.TemplateVersionParameterValidationRule
+	for _, rule := range protoRules {
+		rules = append(rules, codersdk.TemplateVersionParameterValidationRule{
+			Condition: rule.Condition,
+			Error:     rule.Error,
+		})
+	}
+	return rules, nil
+}
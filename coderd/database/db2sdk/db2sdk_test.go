@@ -179,3 +179,24 @@ func TestTemplateVersionParameter_BadDescription(t *testing.T) {
 	req.NoError(err)
 	req.NotEmpty(sdk.DescriptionPlaintext, "broke the markdown parser with %v", desc)
 }
+
+func TestEgressPolicy(t *testing.T) {
+	t.Parallel()
+
+	policy := codersdk.EgressPolicy{
+		DefaultAction: codersdk.EgressActionDeny,
+		Rules: []codersdk.EgressRule{
+			{Action: codersdk.EgressActionAllow, CIDR: "10.0.0.0/8", Ports: []string{"443", "8000-8999"}},
+			{Action: codersdk.EgressActionDeny},
+		},
+	}
+	stored := db2sdk.DatabaseEgressPolicy(policy)
+	require.Equal(t, database.EgressPolicy{
+		DefaultAction: "deny",
+		Rules: []database.EgressRule{
+			{Action: "allow", CIDR: "10.0.0.0/8", Ports: []string{"443", "8000-8999"}},
+			{Action: "deny"},
+		},
+	}, stored)
+	require.Equal(t, policy, db2sdk.EgressPolicy(stored))
+}
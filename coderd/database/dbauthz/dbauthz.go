@@ -219,6 +219,10 @@ var (
 					rbac.ResourceWorkspaceBuild.Type:     {rbac.ActionUpdate},
 					rbac.ResourceWorkspaceExecution.Type: {rbac.ActionCreate},
 					rbac.ResourceWorkspaceProxy.Type:     {rbac.ActionCreate, rbac.ActionUpdate, rbac.ActionDelete},
+					// Exchanging OAuth2 codes and refresh tokens happens before
+					// there is a user to act as.
+					rbac.ResourceOAuth2ProviderAppSecret.Type:    {rbac.ActionUpdate},
+					rbac.ResourceOAuth2ProviderAppCodeToken.Type: {rbac.ActionCreate, rbac.ActionDelete},
 				}),
 				Org:  map[string][]rbac.Permission{},
 				User: []rbac.Permission{},
@@ -686,8 +690,14 @@ func (q *querier) DeleteCoordinator(ctx context.Context, id uuid.UUID) error {
 	return q.db.DeleteCoordinator(ctx, id)
 }
 
-func (q *querier) DeleteGitSSHKey(ctx context.Context, userID uuid.UUID) error {
-	return deleteQ(q.log, q.auth, q.db.GetGitSSHKey, q.db.DeleteGitSSHKey)(ctx, userID)
+func (q *querier) DeleteGitSSHKey(ctx context.Context, arg database.DeleteGitSSHKeyParams) error {
+	fetch := func(ctx context.Context, arg database.DeleteGitSSHKeyParams) (database.GitSSHKey, error) {
+		return q.db.GetGitSSHKey(ctx, database.GetGitSSHKeyParams{
+			UserID: arg.UserID,
+			Name:   arg.Name,
+		})
+	}
+	return deleteQ(q.log, q.auth, fetch, q.db.DeleteGitSSHKey)(ctx, arg)
 }
 
 func (q *querier) DeleteGroupByID(ctx context.Context, id uuid.UUID) error {
@@ -723,6 +733,22 @@ func (q *querier) DeleteLicense(ctx context.Context, id int32) (int32, error) {
 	return id, nil
 }
 
+func (q *querier) DeleteOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) error {
+	return deleteQ(q.log, q.auth, q.db.GetOAuth2ProviderAppByID, q.db.DeleteOAuth2ProviderAppByID)(ctx, id)
+}
+
+func (q *querier) DeleteOAuth2ProviderAppCodeByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderAppCode, error) {
+	return fetchAndQuery(q.log, q.auth, rbac.ActionDelete, q.db.GetOAuth2ProviderAppCodeByID, q.db.DeleteOAuth2ProviderAppCodeByID)(ctx, id)
+}
+
+func (q *querier) DeleteOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) error {
+	return deleteQ(q.log, q.auth, q.db.GetOAuth2ProviderAppSecretByID, q.db.DeleteOAuth2ProviderAppSecretByID)(ctx, id)
+}
+
+func (q *querier) DeleteOAuth2ProviderAppTokenByPrefix(ctx context.Context, hashPrefix string) (database.OAuth2ProviderAppToken, error) {
+	return fetchAndQuery(q.log, q.auth, rbac.ActionDelete, q.db.GetOAuth2ProviderAppTokenByPrefix, q.db.DeleteOAuth2ProviderAppTokenByPrefix)(ctx, hashPrefix)
+}
+
 func (q *querier) DeleteOldWorkspaceAgentLogs(ctx context.Context) error {
 	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
 		return err
@@ -758,6 +784,24 @@ func (q *querier) DeleteTailnetClient(ctx context.Context, arg database.DeleteTa
 	return q.db.DeleteTailnetClient(ctx, arg)
 }
 
+func (q *querier) DeleteUserMFAByUserID(ctx context.Context, userID uuid.UUID) error {
+	return deleteQ(q.log, q.auth, q.db.GetUserMFAByUserID, q.db.DeleteUserMFAByUserID)(ctx, userID)
+}
+
+func (q *querier) DeleteUserMFARecoveryCodesByUserID(ctx context.Context, userID uuid.UUID) error {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceUserData.WithOwner(userID.String()).WithID(userID)); err != nil {
+		return err
+	}
+	return q.db.DeleteUserMFARecoveryCodesByUserID(ctx, userID)
+}
+
+func (q *querier) EnableUserMFA(ctx context.Context, arg database.EnableUserMFAParams) (database.UserMFA, error) {
+	fetch := func(ctx context.Context, arg database.EnableUserMFAParams) (database.UserMFA, error) {
+		return q.db.GetUserMFAByUserID(ctx, arg.UserID)
+	}
+	return updateWithReturn(q.log, q.auth, fetch, q.db.EnableUserMFA)(ctx, arg)
+}
+
 func (q *querier) GetAPIKeyByID(ctx context.Context, id string) (database.APIKey, error) {
 	return fetch(q.log, q.auth, q.db.GetAPIKeyByID)(ctx, id)
 }
@@ -778,6 +822,13 @@ func (q *querier) GetAPIKeysLastUsedAfter(ctx context.Context, lastUsed time.Tim
 	return fetchWithPostFilter(q.auth, q.db.GetAPIKeysLastUsedAfter)(ctx, lastUsed)
 }
 
+func (q *querier) GetActiveTemplateVersionJobTags(ctx context.Context) ([]database.GetActiveTemplateVersionJobTagsRow, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetActiveTemplateVersionJobTags(ctx)
+}
+
 func (q *querier) GetActiveUserCount(ctx context.Context) (int64, error) {
 	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
 		return 0, err
@@ -905,8 +956,12 @@ func (q *querier) GetGitAuthLink(ctx context.Context, arg database.GetGitAuthLin
 	return fetch(q.log, q.auth, q.db.GetGitAuthLink)(ctx, arg)
 }
 
-func (q *querier) GetGitSSHKey(ctx context.Context, userID uuid.UUID) (database.GitSSHKey, error) {
-	return fetch(q.log, q.auth, q.db.GetGitSSHKey)(ctx, userID)
+func (q *querier) GetGitSSHKey(ctx context.Context, arg database.GetGitSSHKeyParams) (database.GitSSHKey, error) {
+	return fetch(q.log, q.auth, q.db.GetGitSSHKey)(ctx, arg)
+}
+
+func (q *querier) GetGitSSHKeysByUserID(ctx context.Context, userID uuid.UUID) ([]database.GitSSHKey, error) {
+	return fetchWithPostFilter(q.auth, q.db.GetGitSSHKeysByUserID)(ctx, userID)
 }
 
 func (q *querier) GetGroupByID(ctx context.Context, id uuid.UUID) (database.Group, error) {
@@ -986,6 +1041,40 @@ func (q *querier) GetLogoURL(ctx context.Context) (string, error) {
 	return q.db.GetLogoURL(ctx)
 }
 
+func (q *querier) GetOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderApp, error) {
+	return fetch(q.log, q.auth, q.db.GetOAuth2ProviderAppByID)(ctx, id)
+}
+
+func (q *querier) GetOAuth2ProviderAppCodeByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderAppCode, error) {
+	return fetch(q.log, q.auth, q.db.GetOAuth2ProviderAppCodeByID)(ctx, id)
+}
+
+func (q *querier) GetOAuth2ProviderAppCodeByPrefix(ctx context.Context, secretPrefix string) (database.OAuth2ProviderAppCode, error) {
+	return fetch(q.log, q.auth, q.db.GetOAuth2ProviderAppCodeByPrefix)(ctx, secretPrefix)
+}
+
+func (q *querier) GetOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderAppSecret, error) {
+	return fetch(q.log, q.auth, q.db.GetOAuth2ProviderAppSecretByID)(ctx, id)
+}
+
+func (q *querier) GetOAuth2ProviderAppSecretsByAppID(ctx context.Context, appID uuid.UUID) ([]database.OAuth2ProviderAppSecret, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceOAuth2ProviderAppSecret); err != nil {
+		return []database.OAuth2ProviderAppSecret{}, err
+	}
+	return q.db.GetOAuth2ProviderAppSecretsByAppID(ctx, appID)
+}
+
+func (q *querier) GetOAuth2ProviderAppTokenByPrefix(ctx context.Context, hashPrefix string) (database.OAuth2ProviderAppToken, error) {
+	return fetch(q.log, q.auth, q.db.GetOAuth2ProviderAppTokenByPrefix)(ctx, hashPrefix)
+}
+
+func (q *querier) GetOAuth2ProviderApps(ctx context.Context) ([]database.OAuth2ProviderApp, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceOAuth2ProviderApp); err != nil {
+		return []database.OAuth2ProviderApp{}, err
+	}
+	return q.db.GetOAuth2ProviderApps(ctx)
+}
+
 func (q *querier) GetOAuthSigningKey(ctx context.Context) (string, error) {
 	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
 		return "", err
@@ -1116,6 +1205,14 @@ func (q *querier) GetProvisionerJobsCreatedAfter(ctx context.Context, createdAt
 	return q.db.GetProvisionerJobsCreatedAfter(ctx, createdAt)
 }
 
+// TODO: We need to create a ProvisionerJob resource type
+func (q *querier) GetProvisionerJobsRunningByWorkerIDs(ctx context.Context, workerIDs []uuid.UUID) ([]database.ProvisionerJob, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetProvisionerJobsRunningByWorkerIDs(ctx, workerIDs)
+}
+
 func (q *querier) GetProvisionerLogsAfterID(ctx context.Context, arg database.GetProvisionerLogsAfterIDParams) ([]database.ProvisionerJobLog, error) {
 	// Authorized read on job lets the actor also read the logs.
 	_, err := q.GetProvisionerJobByID(ctx, arg.JobID)
@@ -1459,11 +1556,11 @@ func (q *querier) GetUserLatencyInsights(ctx context.Context, arg database.GetUs
 	return q.db.GetUserLatencyInsights(ctx, arg)
 }
 
-func (q *querier) GetUserLinkByLinkedID(ctx context.Context, linkedID string) (database.UserLink, error) {
+func (q *querier) GetUserLinkByLinkedID(ctx context.Context, arg database.GetUserLinkByLinkedIDParams) (database.UserLink, error) {
 	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
 		return database.UserLink{}, err
 	}
-	return q.db.GetUserLinkByLinkedID(ctx, linkedID)
+	return q.db.GetUserLinkByLinkedID(ctx, arg)
 }
 
 func (q *querier) GetUserLinkByUserIDLoginType(ctx context.Context, arg database.GetUserLinkByUserIDLoginTypeParams) (database.UserLink, error) {
@@ -1473,6 +1570,31 @@ func (q *querier) GetUserLinkByUserIDLoginType(ctx context.Context, arg database
 	return q.db.GetUserLinkByUserIDLoginType(ctx, arg)
 }
 
+func (q *querier) GetUserLinksByLoginType(ctx context.Context, loginType database.LoginType) ([]database.UserLink, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetUserLinksByLoginType(ctx, loginType)
+}
+
+func (q *querier) GetUserLinksByUserID(ctx context.Context, userID uuid.UUID) ([]database.UserLink, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetUserLinksByUserID(ctx, userID)
+}
+
+func (q *querier) GetUserMFAByUserID(ctx context.Context, userID uuid.UUID) (database.UserMFA, error) {
+	return fetch(q.log, q.auth, q.db.GetUserMFAByUserID)(ctx, userID)
+}
+
+func (q *querier) GetUserMFARecoveryCodesByUserID(ctx context.Context, userID uuid.UUID) ([]database.UserMFARecoveryCode, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceUserData.WithOwner(userID.String()).WithID(userID)); err != nil {
+		return nil, err
+	}
+	return q.db.GetUserMFARecoveryCodesByUserID(ctx, userID)
+}
+
 func (q *querier) GetUsers(ctx context.Context, arg database.GetUsersParams) ([]database.GetUsersRow, error) {
 	// This does the filtering in SQL.
 	prep, err := prepareSQLFilter(ctx, q.auth, rbac.ActionRead, rbac.ResourceUser.Type)
@@ -1887,6 +2009,22 @@ func (q *querier) InsertMissingGroups(ctx context.Context, arg database.InsertMi
 	return q.db.InsertMissingGroups(ctx, arg)
 }
 
+func (q *querier) InsertOAuth2ProviderApp(ctx context.Context, arg database.InsertOAuth2ProviderAppParams) (database.OAuth2ProviderApp, error) {
+	return insert(q.log, q.auth, rbac.ResourceOAuth2ProviderApp, q.db.InsertOAuth2ProviderApp)(ctx, arg)
+}
+
+func (q *querier) InsertOAuth2ProviderAppCode(ctx context.Context, arg database.InsertOAuth2ProviderAppCodeParams) (database.OAuth2ProviderAppCode, error) {
+	return insert(q.log, q.auth, rbac.ResourceOAuth2ProviderAppCodeToken.WithOwner(arg.UserID.String()), q.db.InsertOAuth2ProviderAppCode)(ctx, arg)
+}
+
+func (q *querier) InsertOAuth2ProviderAppSecret(ctx context.Context, arg database.InsertOAuth2ProviderAppSecretParams) (database.OAuth2ProviderAppSecret, error) {
+	return insert(q.log, q.auth, rbac.ResourceOAuth2ProviderAppSecret, q.db.InsertOAuth2ProviderAppSecret)(ctx, arg)
+}
+
+func (q *querier) InsertOAuth2ProviderAppToken(ctx context.Context, arg database.InsertOAuth2ProviderAppTokenParams) (database.OAuth2ProviderAppToken, error) {
+	return insert(q.log, q.auth, rbac.ResourceOAuth2ProviderAppCodeToken.WithOwner(arg.UserID.String()), q.db.InsertOAuth2ProviderAppToken)(ctx, arg)
+}
+
 func (q *querier) InsertOrganization(ctx context.Context, arg database.InsertOrganizationParams) (database.Organization, error) {
 	return insert(q.log, q.auth, rbac.ResourceOrganization, q.db.InsertOrganization)(ctx, arg)
 }
@@ -2008,6 +2146,10 @@ func (q *querier) InsertUserLink(ctx context.Context, arg database.InsertUserLin
 	return q.db.InsertUserLink(ctx, arg)
 }
 
+func (q *querier) InsertUserMFARecoveryCode(ctx context.Context, arg database.InsertUserMFARecoveryCodeParams) (database.UserMFARecoveryCode, error) {
+	return insert(q.log, q.auth, rbac.ResourceUserData.WithOwner(arg.UserID.String()).WithID(arg.UserID), q.db.InsertUserMFARecoveryCode)(ctx, arg)
+}
+
 func (q *querier) InsertWorkspace(ctx context.Context, arg database.InsertWorkspaceParams) (database.Workspace, error) {
 	obj := rbac.ResourceWorkspace.WithOwner(arg.OwnerID.String()).InOrg(arg.OrganizationID)
 	return insert(q.log, q.auth, obj, q.db.InsertWorkspace)(ctx, arg)
@@ -2128,6 +2270,13 @@ func (q *querier) InsertWorkspaceResourceMetadata(ctx context.Context, arg datab
 	return q.db.InsertWorkspaceResourceMetadata(ctx, arg)
 }
 
+func (q *querier) RecordUserMFAFailedAttempt(ctx context.Context, arg database.RecordUserMFAFailedAttemptParams) (database.UserMFA, error) {
+	fetch := func(ctx context.Context, arg database.RecordUserMFAFailedAttemptParams) (database.UserMFA, error) {
+		return q.db.GetUserMFAByUserID(ctx, arg.UserID)
+	}
+	return updateWithReturn(q.log, q.auth, fetch, q.db.RecordUserMFAFailedAttempt)(ctx, arg)
+}
+
 func (q *querier) RegisterWorkspaceProxy(ctx context.Context, arg database.RegisterWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	fetch := func(ctx context.Context, arg database.RegisterWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 		return q.db.GetWorkspaceProxyByID(ctx, arg.ID)
@@ -2135,6 +2284,10 @@ func (q *querier) RegisterWorkspaceProxy(ctx context.Context, arg database.Regis
 	return updateWithReturn(q.log, q.auth, fetch, q.db.RegisterWorkspaceProxy)(ctx, arg)
 }
 
+func (q *querier) ResetUserMFAFailedAttempts(ctx context.Context, userID uuid.UUID) error {
+	return update(q.log, q.auth, q.db.GetUserMFAByUserID, q.db.ResetUserMFAFailedAttempts)(ctx, userID)
+}
+
 func (q *querier) TryAcquireLock(ctx context.Context, id int64) (bool, error) {
 	return q.db.TryAcquireLock(ctx, id)
 }
@@ -2155,7 +2308,10 @@ func (q *querier) UpdateGitAuthLink(ctx context.Context, arg database.UpdateGitA
 
 func (q *querier) UpdateGitSSHKey(ctx context.Context, arg database.UpdateGitSSHKeyParams) (database.GitSSHKey, error) {
 	fetch := func(ctx context.Context, arg database.UpdateGitSSHKeyParams) (database.GitSSHKey, error) {
-		return q.db.GetGitSSHKey(ctx, arg.UserID)
+		return q.db.GetGitSSHKey(ctx, database.GetGitSSHKeyParams{
+			UserID: arg.UserID,
+			Name:   arg.Name,
+		})
 	}
 	return updateWithReturn(q.log, q.auth, fetch, q.db.UpdateGitSSHKey)(ctx, arg)
 }
@@ -2195,6 +2351,27 @@ func (q *querier) UpdateMemberRoles(ctx context.Context, arg database.UpdateMemb
 	return q.db.UpdateMemberRoles(ctx, arg)
 }
 
+func (q *querier) UpdateOAuth2ProviderAppByID(ctx context.Context, arg database.UpdateOAuth2ProviderAppByIDParams) (database.OAuth2ProviderApp, error) {
+	fetch := func(ctx context.Context, arg database.UpdateOAuth2ProviderAppByIDParams) (database.OAuth2ProviderApp, error) {
+		return q.db.GetOAuth2ProviderAppByID(ctx, arg.ID)
+	}
+	return updateWithReturn(q.log, q.auth, fetch, q.db.UpdateOAuth2ProviderAppByID)(ctx, arg)
+}
+
+func (q *querier) UpdateOAuth2ProviderAppSecretByID(ctx context.Context, arg database.UpdateOAuth2ProviderAppSecretByIDParams) (database.OAuth2ProviderAppSecret, error) {
+	fetch := func(ctx context.Context, arg database.UpdateOAuth2ProviderAppSecretByIDParams) (database.OAuth2ProviderAppSecret, error) {
+		return q.db.GetOAuth2ProviderAppSecretByID(ctx, arg.ID)
+	}
+	return updateWithReturn(q.log, q.auth, fetch, q.db.UpdateOAuth2ProviderAppSecretByID)(ctx, arg)
+}
+
+func (q *querier) UpdateProvisionerDaemonLastSeenAt(ctx context.Context, arg database.UpdateProvisionerDaemonLastSeenAtParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpdateProvisionerDaemonLastSeenAt(ctx, arg)
+}
+
 // TODO: We need to create a ProvisionerJob resource type
 func (q *querier) UpdateProvisionerJobByID(ctx context.Context, arg database.UpdateProvisionerJobByIDParams) error {
 	// if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
@@ -2434,8 +2611,9 @@ func (q *querier) UpdateUserLastSeenAt(ctx context.Context, arg database.UpdateU
 func (q *querier) UpdateUserLink(ctx context.Context, arg database.UpdateUserLinkParams) (database.UserLink, error) {
 	fetch := func(ctx context.Context, arg database.UpdateUserLinkParams) (database.UserLink, error) {
 		return q.db.GetUserLinkByUserIDLoginType(ctx, database.GetUserLinkByUserIDLoginTypeParams{
-			UserID:    arg.UserID,
-			LoginType: arg.LoginType,
+			UserID:     arg.UserID,
+			LoginType:  arg.LoginType,
+			ProviderID: arg.ProviderID,
 		})
 	}
 	return updateWithReturn(q.log, q.auth, fetch, q.db.UpdateUserLink)(ctx, arg)
@@ -2455,6 +2633,13 @@ func (q *querier) UpdateUserLoginType(ctx context.Context, arg database.UpdateUs
 	return q.db.UpdateUserLoginType(ctx, arg)
 }
 
+func (q *querier) UpdateUserMFALastUsedStep(ctx context.Context, arg database.UpdateUserMFALastUsedStepParams) (database.UserMFA, error) {
+	fetch := func(ctx context.Context, arg database.UpdateUserMFALastUsedStepParams) (database.UserMFA, error) {
+		return q.db.GetUserMFAByUserID(ctx, arg.UserID)
+	}
+	return updateWithReturn(q.log, q.auth, fetch, q.db.UpdateUserMFALastUsedStep)(ctx, arg)
+}
+
 func (q *querier) UpdateUserProfile(ctx context.Context, arg database.UpdateUserProfileParams) (database.User, error) {
 	u, err := q.db.GetUserByID(ctx, arg.ID)
 	if err != nil {
@@ -2741,6 +2926,20 @@ func (q *querier) UpsertTailnetCoordinator(ctx context.Context, id uuid.UUID) (d
 	return q.db.UpsertTailnetCoordinator(ctx, id)
 }
 
+func (q *querier) UpsertUserMFA(ctx context.Context, arg database.UpsertUserMFAParams) (database.UserMFA, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceUserData.WithOwner(arg.UserID.String()).WithID(arg.UserID)); err != nil {
+		return database.UserMFA{}, err
+	}
+	return q.db.UpsertUserMFA(ctx, arg)
+}
+
+func (q *querier) UseUserMFARecoveryCode(ctx context.Context, arg database.UseUserMFARecoveryCodeParams) (database.UserMFARecoveryCode, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceUserData.WithOwner(arg.UserID.String()).WithID(arg.UserID)); err != nil {
+		return database.UserMFARecoveryCode{}, err
+	}
+	return q.db.UseUserMFARecoveryCode(ctx, arg)
+}
+
 func (q *querier) GetAuthorizedTemplates(ctx context.Context, arg database.GetTemplatesWithFilterParams, _ rbac.PreparedAuthorized) ([]database.Template, error) {
 	// TODO Delete this function, all GetTemplates should be authorized. For now just call getTemplates on the authz querier.
 	return q.GetTemplatesWithFilter(ctx, arg)
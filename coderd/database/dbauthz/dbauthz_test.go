@@ -621,6 +621,177 @@ func (s *MethodTestSuite) TestWorkspaceProxy() {
 	}))
 }
 
+func (s *MethodTestSuite) TestOAuth2ProviderApps() {
+	s.Run("GetOAuth2ProviderApps", s.Subtest(func(db database.Store, check *expects) {
+		apps := []database.OAuth2ProviderApp{
+			dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{Name: "first"}),
+			dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{Name: "last"}),
+		}
+		check.Args().Asserts(rbac.ResourceOAuth2ProviderApp, rbac.ActionRead).Returns(apps)
+	}))
+	s.Run("GetOAuth2ProviderAppByID", s.Subtest(func(db database.Store, check *expects) {
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		check.Args(app.ID).Asserts(rbac.ResourceOAuth2ProviderApp, rbac.ActionRead).Returns(app)
+	}))
+	s.Run("InsertOAuth2ProviderApp", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.InsertOAuth2ProviderAppParams{}).Asserts(rbac.ResourceOAuth2ProviderApp, rbac.ActionCreate)
+	}))
+	s.Run("UpdateOAuth2ProviderAppByID", s.Subtest(func(db database.Store, check *expects) {
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		app.Name = "my-new-name"
+		app.UpdatedAt = time.Now()
+		check.Args(database.UpdateOAuth2ProviderAppByIDParams{
+			ID:          app.ID,
+			Name:        app.Name,
+			CallbackURL: app.CallbackURL,
+			UpdatedAt:   app.UpdatedAt,
+		}).Asserts(rbac.ResourceOAuth2ProviderApp, rbac.ActionUpdate).Returns(app)
+	}))
+	s.Run("DeleteOAuth2ProviderAppByID", s.Subtest(func(db database.Store, check *expects) {
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		check.Args(app.ID).Asserts(rbac.ResourceOAuth2ProviderApp, rbac.ActionDelete)
+	}))
+}
+
+func (s *MethodTestSuite) TestOAuth2ProviderAppSecrets() {
+	s.Run("GetOAuth2ProviderAppSecretsByAppID", s.Subtest(func(db database.Store, check *expects) {
+		app1 := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		app2 := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		secrets := []database.OAuth2ProviderAppSecret{
+			dbgen.OAuth2ProviderAppSecret(s.T(), db, database.OAuth2ProviderAppSecret{
+				AppID:     app1.ID,
+				CreatedAt: time.Now().Add(-time.Hour), // For ordering.
+			}),
+			dbgen.OAuth2ProviderAppSecret(s.T(), db, database.OAuth2ProviderAppSecret{
+				AppID: app1.ID,
+			}),
+		}
+		_ = dbgen.OAuth2ProviderAppSecret(s.T(), db, database.OAuth2ProviderAppSecret{
+			AppID: app2.ID,
+		})
+		check.Args(app1.ID).Asserts(rbac.ResourceOAuth2ProviderAppSecret, rbac.ActionRead).Returns(secrets)
+	}))
+	s.Run("GetOAuth2ProviderAppSecretByID", s.Subtest(func(db database.Store, check *expects) {
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		secret := dbgen.OAuth2ProviderAppSecret(s.T(), db, database.OAuth2ProviderAppSecret{
+			AppID: app.ID,
+		})
+		check.Args(secret.ID).Asserts(rbac.ResourceOAuth2ProviderAppSecret, rbac.ActionRead).Returns(secret)
+	}))
+	s.Run("InsertOAuth2ProviderAppSecret", s.Subtest(func(db database.Store, check *expects) {
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		check.Args(database.InsertOAuth2ProviderAppSecretParams{
+			AppID: app.ID,
+		}).Asserts(rbac.ResourceOAuth2ProviderAppSecret, rbac.ActionCreate)
+	}))
+	s.Run("UpdateOAuth2ProviderAppSecretByID", s.Subtest(func(db database.Store, check *expects) {
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		secret := dbgen.OAuth2ProviderAppSecret(s.T(), db, database.OAuth2ProviderAppSecret{
+			AppID: app.ID,
+		})
+		secret.LastUsedAt = sql.NullTime{Time: time.Now(), Valid: true}
+		check.Args(database.UpdateOAuth2ProviderAppSecretByIDParams{
+			ID:         secret.ID,
+			LastUsedAt: secret.LastUsedAt,
+		}).Asserts(rbac.ResourceOAuth2ProviderAppSecret, rbac.ActionUpdate).Returns(secret)
+	}))
+	s.Run("DeleteOAuth2ProviderAppSecretByID", s.Subtest(func(db database.Store, check *expects) {
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		secret := dbgen.OAuth2ProviderAppSecret(s.T(), db, database.OAuth2ProviderAppSecret{
+			AppID: app.ID,
+		})
+		check.Args(secret.ID).Asserts(rbac.ResourceOAuth2ProviderAppSecret, rbac.ActionDelete)
+	}))
+}
+
+func (s *MethodTestSuite) TestOAuth2ProviderAppCodes() {
+	s.Run("GetOAuth2ProviderAppCodeByID", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		code := dbgen.OAuth2ProviderAppCode(s.T(), db, database.OAuth2ProviderAppCode{
+			AppID:  app.ID,
+			UserID: user.ID,
+		})
+		check.Args(code.ID).Asserts(code, rbac.ActionRead).Returns(code)
+	}))
+	s.Run("GetOAuth2ProviderAppCodeByPrefix", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		code := dbgen.OAuth2ProviderAppCode(s.T(), db, database.OAuth2ProviderAppCode{
+			AppID:  app.ID,
+			UserID: user.ID,
+		})
+		check.Args(code.SecretPrefix).Asserts(code, rbac.ActionRead).Returns(code)
+	}))
+	s.Run("InsertOAuth2ProviderAppCode", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		check.Args(database.InsertOAuth2ProviderAppCodeParams{
+			AppID:  app.ID,
+			UserID: user.ID,
+		}).Asserts(rbac.ResourceOAuth2ProviderAppCodeToken.WithOwner(user.ID.String()), rbac.ActionCreate)
+	}))
+	s.Run("DeleteOAuth2ProviderAppCodeByID", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		code := dbgen.OAuth2ProviderAppCode(s.T(), db, database.OAuth2ProviderAppCode{
+			AppID:  app.ID,
+			UserID: user.ID,
+		})
+		check.Args(code.ID).Asserts(code, rbac.ActionDelete).Returns(code)
+	}))
+}
+
+func (s *MethodTestSuite) TestOAuth2ProviderAppTokens() {
+	s.Run("InsertOAuth2ProviderAppToken", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		key, _ := dbgen.APIKey(s.T(), db, database.APIKey{
+			UserID: user.ID,
+		})
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		secret := dbgen.OAuth2ProviderAppSecret(s.T(), db, database.OAuth2ProviderAppSecret{
+			AppID: app.ID,
+		})
+		check.Args(database.InsertOAuth2ProviderAppTokenParams{
+			AppSecretID: secret.ID,
+			APIKeyID:    key.ID,
+			UserID:      user.ID,
+		}).Asserts(rbac.ResourceOAuth2ProviderAppCodeToken.WithOwner(user.ID.String()), rbac.ActionCreate)
+	}))
+	s.Run("GetOAuth2ProviderAppTokenByPrefix", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		key, _ := dbgen.APIKey(s.T(), db, database.APIKey{
+			UserID: user.ID,
+		})
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		secret := dbgen.OAuth2ProviderAppSecret(s.T(), db, database.OAuth2ProviderAppSecret{
+			AppID: app.ID,
+		})
+		token := dbgen.OAuth2ProviderAppToken(s.T(), db, database.OAuth2ProviderAppToken{
+			AppSecretID: secret.ID,
+			APIKeyID:    key.ID,
+			UserID:      user.ID,
+		})
+		check.Args(token.HashPrefix).Asserts(token, rbac.ActionRead).Returns(token)
+	}))
+	s.Run("DeleteOAuth2ProviderAppTokenByPrefix", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		key, _ := dbgen.APIKey(s.T(), db, database.APIKey{
+			UserID: user.ID,
+		})
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		secret := dbgen.OAuth2ProviderAppSecret(s.T(), db, database.OAuth2ProviderAppSecret{
+			AppID: app.ID,
+		})
+		token := dbgen.OAuth2ProviderAppToken(s.T(), db, database.OAuth2ProviderAppToken{
+			AppSecretID: secret.ID,
+			APIKeyID:    key.ID,
+			UserID:      user.ID,
+		})
+		check.Args(token.HashPrefix).Asserts(token, rbac.ActionDelete).Returns(token)
+	}))
+}
+
 func (s *MethodTestSuite) TestTemplate() {
 	s.Run("GetPreviousTemplateVersion", s.Subtest(func(db database.Store, check *expects) {
 		tvid := uuid.New()
@@ -934,11 +1105,23 @@ func (s *MethodTestSuite) TestUser() {
 	}))
 	s.Run("DeleteGitSSHKey", s.Subtest(func(db database.Store, check *expects) {
 		key := dbgen.GitSSHKey(s.T(), db, database.GitSSHKey{})
-		check.Args(key.UserID).Asserts(key, rbac.ActionDelete).Returns()
+		check.Args(database.DeleteGitSSHKeyParams{
+			UserID: key.UserID,
+			Name:   key.Name,
+		}).Asserts(key, rbac.ActionDelete).Returns()
 	}))
 	s.Run("GetGitSSHKey", s.Subtest(func(db database.Store, check *expects) {
 		key := dbgen.GitSSHKey(s.T(), db, database.GitSSHKey{})
-		check.Args(key.UserID).Asserts(key, rbac.ActionRead).Returns(key)
+		check.Args(database.GetGitSSHKeyParams{
+			UserID: key.UserID,
+			Name:   key.Name,
+		}).Asserts(key, rbac.ActionRead).Returns(key)
+	}))
+	s.Run("GetGitSSHKeysByUserID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		a := dbgen.GitSSHKey(s.T(), db, database.GitSSHKey{UserID: u.ID, Name: "a"})
+		b := dbgen.GitSSHKey(s.T(), db, database.GitSSHKey{UserID: u.ID, Name: "b"})
+		check.Args(u.ID).Asserts(a, rbac.ActionRead, b, rbac.ActionRead).Returns(slice.New(a, b))
 	}))
 	s.Run("InsertGitSSHKey", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
@@ -950,6 +1133,7 @@ func (s *MethodTestSuite) TestUser() {
 		key := dbgen.GitSSHKey(s.T(), db, database.GitSSHKey{})
 		check.Args(database.UpdateGitSSHKeyParams{
 			UserID:    key.UserID,
+			Name:      key.Name,
 			UpdatedAt: key.UpdatedAt,
 		}).Asserts(key, rbac.ActionUpdate).Returns(key)
 	}))
@@ -1003,6 +1187,80 @@ func (s *MethodTestSuite) TestUser() {
 	}))
 }
 
+func (s *MethodTestSuite) TestUserMFA() {
+	s.Run("GetUserMFAByUserID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		mfa := dbgen.UserMFA(s.T(), db, database.UserMFA{UserID: u.ID})
+		check.Args(u.ID).Asserts(mfa, rbac.ActionRead).Returns(mfa)
+	}))
+	s.Run("UpsertUserMFA", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.UpsertUserMFAParams{
+			UserID: u.ID,
+		}).Asserts(rbac.ResourceUserData.WithOwner(u.ID.String()).WithID(u.ID), rbac.ActionUpdate)
+	}))
+	s.Run("EnableUserMFA", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		mfa := dbgen.UserMFA(s.T(), db, database.UserMFA{UserID: u.ID})
+		check.Args(database.EnableUserMFAParams{
+			UserID:           u.ID,
+			UpdatedAt:        mfa.UpdatedAt,
+			TOTPLastUsedStep: 1,
+		}).Asserts(mfa, rbac.ActionUpdate)
+	}))
+	s.Run("UpdateUserMFALastUsedStep", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		mfa := dbgen.UserMFA(s.T(), db, database.UserMFA{UserID: u.ID})
+		check.Args(database.UpdateUserMFALastUsedStepParams{
+			UserID:           u.ID,
+			TOTPLastUsedStep: 1,
+		}).Asserts(mfa, rbac.ActionUpdate)
+	}))
+	s.Run("RecordUserMFAFailedAttempt", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		mfa := dbgen.UserMFA(s.T(), db, database.UserMFA{UserID: u.ID})
+		check.Args(database.RecordUserMFAFailedAttemptParams{
+			UserID:       u.ID,
+			FailedAfter:  dbtime.Now().Add(-time.Hour),
+			LastFailedAt: sql.NullTime{Time: dbtime.Now(), Valid: true},
+		}).Asserts(mfa, rbac.ActionUpdate)
+	}))
+	s.Run("ResetUserMFAFailedAttempts", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		mfa := dbgen.UserMFA(s.T(), db, database.UserMFA{UserID: u.ID})
+		check.Args(u.ID).Asserts(mfa, rbac.ActionUpdate).Returns()
+	}))
+	s.Run("DeleteUserMFAByUserID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		mfa := dbgen.UserMFA(s.T(), db, database.UserMFA{UserID: u.ID})
+		check.Args(u.ID).Asserts(mfa, rbac.ActionDelete).Returns()
+	}))
+	s.Run("GetUserMFARecoveryCodesByUserID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		code := dbgen.UserMFARecoveryCode(s.T(), db, database.UserMFARecoveryCode{UserID: u.ID})
+		check.Args(u.ID).Asserts(rbac.ResourceUserData.WithOwner(u.ID.String()).WithID(u.ID), rbac.ActionRead).
+			Returns([]database.UserMFARecoveryCode{code})
+	}))
+	s.Run("InsertUserMFARecoveryCode", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.InsertUserMFARecoveryCodeParams{
+			UserID: u.ID,
+		}).Asserts(rbac.ResourceUserData.WithOwner(u.ID.String()).WithID(u.ID), rbac.ActionCreate)
+	}))
+	s.Run("UseUserMFARecoveryCode", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		code := dbgen.UserMFARecoveryCode(s.T(), db, database.UserMFARecoveryCode{UserID: u.ID})
+		check.Args(database.UseUserMFARecoveryCodeParams{
+			UserID:     u.ID,
+			HashedCode: code.HashedCode,
+		}).Asserts(rbac.ResourceUserData.WithOwner(u.ID.String()).WithID(u.ID), rbac.ActionUpdate)
+	}))
+	s.Run("DeleteUserMFARecoveryCodesByUserID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(u.ID).Asserts(rbac.ResourceUserData.WithOwner(u.ID.String()).WithID(u.ID), rbac.ActionDelete).Returns()
+	}))
+}
+
 func (s *MethodTestSuite) TestWorkspace() {
 	s.Run("GetWorkspaceByID", s.Subtest(func(db database.Store, check *expects) {
 		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
@@ -1306,7 +1564,10 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 	}))
 	s.Run("GetUserLinkByLinkedID", s.Subtest(func(db database.Store, check *expects) {
 		l := dbgen.UserLink(s.T(), db, database.UserLink{})
-		check.Args(l.LinkedID).Asserts(rbac.ResourceSystem, rbac.ActionRead).Returns(l)
+		check.Args(database.GetUserLinkByLinkedIDParams{
+			LinkedID:   l.LinkedID,
+			ProviderID: l.ProviderID,
+		}).Asserts(rbac.ResourceSystem, rbac.ActionRead).Returns(l)
 	}))
 	s.Run("GetUserLinkByUserIDLoginType", s.Subtest(func(db database.Store, check *expects) {
 		l := dbgen.UserLink(s.T(), db, database.UserLink{})
@@ -1315,6 +1576,14 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 			LoginType: l.LoginType,
 		}).Asserts(rbac.ResourceSystem, rbac.ActionRead).Returns(l)
 	}))
+	s.Run("GetUserLinksByLoginType", s.Subtest(func(db database.Store, check *expects) {
+		l := dbgen.UserLink(s.T(), db, database.UserLink{LoginType: database.LoginTypeLDAP})
+		check.Args(database.LoginTypeLDAP).Asserts(rbac.ResourceSystem, rbac.ActionRead).Returns([]database.UserLink{l})
+	}))
+	s.Run("GetUserLinksByUserID", s.Subtest(func(db database.Store, check *expects) {
+		l := dbgen.UserLink(s.T(), db, database.UserLink{})
+		check.Args(l.UserID).Asserts(rbac.ResourceSystem, rbac.ActionRead).Returns([]database.UserLink{l})
+	}))
 	s.Run("GetLatestWorkspaceBuilds", s.Subtest(func(db database.Store, check *expects) {
 		dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{})
 		dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{})
@@ -1414,6 +1683,28 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 		_ = dbgen.ProvisionerJob(s.T(), db, database.ProvisionerJob{CreatedAt: time.Now().Add(-time.Hour)})
 		check.Args(time.Now()).Asserts( /*rbac.ResourceSystem, rbac.ActionRead*/ )
 	}))
+	s.Run("GetProvisionerJobsRunningByWorkerIDs", s.Subtest(func(db database.Store, check *expects) {
+		workerID := uuid.New()
+		_ = dbgen.ProvisionerJob(s.T(), db, database.ProvisionerJob{
+			WorkerID:  uuid.NullUUID{UUID: workerID, Valid: true},
+			StartedAt: sql.NullTime{Time: time.Now(), Valid: true},
+		})
+		check.Args([]uuid.UUID{workerID}).Asserts(rbac.ResourceSystem, rbac.ActionRead)
+	}))
+	s.Run("UpdateProvisionerDaemonLastSeenAt", s.Subtest(func(db database.Store, check *expects) {
+		d, err := db.InsertProvisionerDaemon(context.Background(), database.InsertProvisionerDaemonParams{
+			ID: uuid.New(),
+		})
+		s.NoError(err, "insert provisioner daemon")
+		check.Args(database.UpdateProvisionerDaemonLastSeenAtParams{
+			ID:         d.ID,
+			LastSeenAt: sql.NullTime{Time: time.Now(), Valid: true},
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
+	}))
+	s.Run("GetActiveTemplateVersionJobTags", s.Subtest(func(db database.Store, check *expects) {
+		_ = dbgen.Template(s.T(), db, database.Template{})
+		check.Args().Asserts(rbac.ResourceSystem, rbac.ActionRead)
+	}))
 	s.Run("GetTemplateVersionsByIDs", s.Subtest(func(db database.Store, check *expects) {
 		t1 := dbgen.Template(s.T(), db, database.Template{})
 		t2 := dbgen.Template(s.T(), db, database.Template{})
@@ -1,6 +1,7 @@
 package dbfake
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"encoding/json"
@@ -123,6 +124,10 @@ type data struct {
 	groupMembers                  []database.GroupMember
 	groups                        []database.Group
 	licenses                      []database.License
+	oauth2ProviderApps            []database.OAuth2ProviderApp
+	oauth2ProviderAppSecrets      []database.OAuth2ProviderAppSecret
+	oauth2ProviderAppCodes        []database.OAuth2ProviderAppCode
+	oauth2ProviderAppTokens       []database.OAuth2ProviderAppToken
 	parameterSchemas              []database.ParameterSchema
 	provisionerDaemons            []database.ProvisionerDaemon
 	provisionerJobLogs            []database.ProvisionerJobLog
@@ -132,6 +137,8 @@ type data struct {
 	templateVersionParameters     []database.TemplateVersionParameter
 	templateVersionVariables      []database.TemplateVersionVariable
 	templates                     []database.TemplateTable
+	userMFA                       []database.UserMFA
+	userMFARecoveryCodes          []database.UserMFARecoveryCode
 	workspaceAgents               []database.WorkspaceAgent
 	workspaceAgentMetadata        []database.WorkspaceAgentMetadatum
 	workspaceAgentLogs            []database.WorkspaceAgentLog
@@ -274,19 +281,20 @@ func convertUsers(users []database.User, count int64) []database.GetUsersRow {
 	rows := make([]database.GetUsersRow, len(users))
 	for i, u := range users {
 		rows[i] = database.GetUsersRow{
-			ID:             u.ID,
-			Email:          u.Email,
-			Username:       u.Username,
-			HashedPassword: u.HashedPassword,
-			CreatedAt:      u.CreatedAt,
-			UpdatedAt:      u.UpdatedAt,
-			Status:         u.Status,
-			RBACRoles:      u.RBACRoles,
-			LoginType:      u.LoginType,
-			AvatarURL:      u.AvatarURL,
-			Deleted:        u.Deleted,
-			LastSeenAt:     u.LastSeenAt,
-			Count:          count,
+			ID:                         u.ID,
+			Email:                      u.Email,
+			Username:                   u.Username,
+			HashedPassword:             u.HashedPassword,
+			CreatedAt:                  u.CreatedAt,
+			UpdatedAt:                  u.UpdatedAt,
+			Status:                     u.Status,
+			RBACRoles:                  u.RBACRoles,
+			LoginType:                  u.LoginType,
+			AvatarURL:                  u.AvatarURL,
+			Deleted:                    u.Deleted,
+			LastSeenAt:                 u.LastSeenAt,
+			Count:                      count,
+			ServiceAccountOwnerGroupID: u.ServiceAccountOwnerGroupID,
 		}
 	}
 
@@ -654,6 +662,30 @@ func (q *FakeQuerier) getEveryoneGroupMembersNoLock(orgID uuid.UUID) []database.
 	return everyone
 }
 
+// deleteOAuth2ProviderAppTokensNoLock deletes the matching tokens along with
+// the API keys they were issued with, like the trigger on the table does.
+func (q *FakeQuerier) deleteOAuth2ProviderAppTokensNoLock(match func(token database.OAuth2ProviderAppToken) bool) {
+	q.oauth2ProviderAppTokens = slices.DeleteFunc(q.oauth2ProviderAppTokens, func(token database.OAuth2ProviderAppToken) bool {
+		if !match(token) {
+			return false
+		}
+		q.apiKeys = slices.DeleteFunc(q.apiKeys, func(key database.APIKey) bool {
+			return key.ID == token.APIKeyID
+		})
+		return true
+	})
+}
+
+// deleteOAuth2ProviderAppTokensByAPIKeyNoLock cascades deleted API keys to the
+// OAuth2 tokens that reference them.
+func (q *FakeQuerier) deleteOAuth2ProviderAppTokensByAPIKeyNoLock() {
+	q.oauth2ProviderAppTokens = slices.DeleteFunc(q.oauth2ProviderAppTokens, func(token database.OAuth2ProviderAppToken) bool {
+		return !slices.ContainsFunc(q.apiKeys, func(key database.APIKey) bool {
+			return key.ID == token.APIKeyID
+		})
+	})
+}
+
 // isEveryoneGroup returns true if the provided ID matches
 // an organization ID.
 func (q *FakeQuerier) isEveryoneGroup(id uuid.UUID) bool {
@@ -738,6 +770,7 @@ func (q *FakeQuerier) DeleteAPIKeyByID(_ context.Context, id string) error {
 		}
 		q.apiKeys[index] = q.apiKeys[len(q.apiKeys)-1]
 		q.apiKeys = q.apiKeys[:len(q.apiKeys)-1]
+		q.deleteOAuth2ProviderAppTokensByAPIKeyNoLock()
 		return nil
 	}
 	return sql.ErrNoRows
@@ -752,6 +785,7 @@ func (q *FakeQuerier) DeleteAPIKeysByUserID(_ context.Context, userID uuid.UUID)
 			q.apiKeys = append(q.apiKeys[:i], q.apiKeys[i+1:]...)
 		}
 	}
+	q.deleteOAuth2ProviderAppTokensByAPIKeyNoLock()
 
 	return nil
 }
@@ -773,12 +807,16 @@ func (*FakeQuerier) DeleteCoordinator(context.Context, uuid.UUID) error {
 	return ErrUnimplemented
 }
 
-func (q *FakeQuerier) DeleteGitSSHKey(_ context.Context, userID uuid.UUID) error {
+func (q *FakeQuerier) DeleteGitSSHKey(_ context.Context, arg database.DeleteGitSSHKeyParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
 
 	for index, key := range q.gitSSHKey {
-		if key.UserID != userID {
+		if key.UserID != arg.UserID || key.Name != arg.Name {
 			continue
 		}
 		q.gitSSHKey[index] = q.gitSSHKey[len(q.gitSSHKey)-1]
@@ -858,6 +896,79 @@ func (q *FakeQuerier) DeleteLicense(_ context.Context, id int32) (int32, error)
 	return 0, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) DeleteOAuth2ProviderAppByID(_ context.Context, id uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	index := slices.IndexFunc(q.oauth2ProviderApps, func(app database.OAuth2ProviderApp) bool {
+		return app.ID == id
+	})
+	if index < 0 {
+		return sql.ErrNoRows
+	}
+	q.oauth2ProviderApps = slices.Delete(q.oauth2ProviderApps, index, index+1)
+
+	// Cascade to the secrets, codes, and tokens of the app.
+	q.oauth2ProviderAppCodes = slices.DeleteFunc(q.oauth2ProviderAppCodes, func(code database.OAuth2ProviderAppCode) bool {
+		return code.AppID == id
+	})
+	for _, secret := range q.oauth2ProviderAppSecrets {
+		if secret.AppID == id {
+			q.deleteOAuth2ProviderAppTokensNoLock(func(token database.OAuth2ProviderAppToken) bool {
+				return token.AppSecretID == secret.ID
+			})
+		}
+	}
+	q.oauth2ProviderAppSecrets = slices.DeleteFunc(q.oauth2ProviderAppSecrets, func(secret database.OAuth2ProviderAppSecret) bool {
+		return secret.AppID == id
+	})
+	return nil
+}
+
+func (q *FakeQuerier) DeleteOAuth2ProviderAppCodeByID(_ context.Context, id uuid.UUID) (database.OAuth2ProviderAppCode, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, code := range q.oauth2ProviderAppCodes {
+		if code.ID == id {
+			q.oauth2ProviderAppCodes = slices.Delete(q.oauth2ProviderAppCodes, index, index+1)
+			return code, nil
+		}
+	}
+	return database.OAuth2ProviderAppCode{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) DeleteOAuth2ProviderAppSecretByID(_ context.Context, id uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, secret := range q.oauth2ProviderAppSecrets {
+		if secret.ID == id {
+			q.oauth2ProviderAppSecrets = slices.Delete(q.oauth2ProviderAppSecrets, index, index+1)
+			q.deleteOAuth2ProviderAppTokensNoLock(func(token database.OAuth2ProviderAppToken) bool {
+				return token.AppSecretID == id
+			})
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+func (q *FakeQuerier) DeleteOAuth2ProviderAppTokenByPrefix(_ context.Context, hashPrefix string) (database.OAuth2ProviderAppToken, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, token := range q.oauth2ProviderAppTokens {
+		if token.HashPrefix == hashPrefix {
+			q.deleteOAuth2ProviderAppTokensNoLock(func(other database.OAuth2ProviderAppToken) bool {
+				return other.ID == token.ID
+			})
+			return token, nil
+		}
+	}
+	return database.OAuth2ProviderAppToken{}, sql.ErrNoRows
+}
+
 func (*FakeQuerier) DeleteOldWorkspaceAgentLogs(_ context.Context) error {
 	// noop
 	return nil
@@ -889,6 +1000,51 @@ func (*FakeQuerier) DeleteTailnetClient(context.Context, database.DeleteTailnetC
 	return database.DeleteTailnetClientRow{}, ErrUnimplemented
 }
 
+func (q *FakeQuerier) DeleteUserMFAByUserID(_ context.Context, userID uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.userMFA = slices.DeleteFunc(q.userMFA, func(mfa database.UserMFA) bool {
+		return mfa.UserID == userID
+	})
+	// Recovery codes reference the enrollment and are deleted with it.
+	q.userMFARecoveryCodes = slices.DeleteFunc(q.userMFARecoveryCodes, func(code database.UserMFARecoveryCode) bool {
+		return code.UserID == userID
+	})
+	return nil
+}
+
+func (q *FakeQuerier) DeleteUserMFARecoveryCodesByUserID(_ context.Context, userID uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.userMFARecoveryCodes = slices.DeleteFunc(q.userMFARecoveryCodes, func(code database.UserMFARecoveryCode) bool {
+		return code.UserID == userID
+	})
+	return nil
+}
+
+func (q *FakeQuerier) EnableUserMFA(_ context.Context, arg database.EnableUserMFAParams) (database.UserMFA, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.UserMFA{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, mfa := range q.userMFA {
+		if mfa.UserID != arg.UserID || mfa.EnabledAt.Valid {
+			continue
+		}
+		mfa.UpdatedAt = arg.UpdatedAt
+		mfa.EnabledAt = sql.NullTime{Time: arg.UpdatedAt, Valid: true}
+		mfa.TOTPLastUsedStep = arg.TOTPLastUsedStep
+		q.userMFA[index] = mfa
+		return mfa, nil
+	}
+	return database.UserMFA{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) GetAPIKeyByID(_ context.Context, id string) (database.APIKey, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -959,13 +1115,40 @@ func (q *FakeQuerier) GetAPIKeysLastUsedAfter(_ context.Context, after time.Time
 	return apiKeys, nil
 }
 
+func (q *FakeQuerier) GetActiveTemplateVersionJobTags(ctx context.Context) ([]database.GetActiveTemplateVersionJobTagsRow, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	rows := make([]database.GetActiveTemplateVersionJobTagsRow, 0)
+	for _, template := range q.templates {
+		if template.Deleted {
+			continue
+		}
+		version, err := q.getTemplateVersionByIDNoLock(ctx, template.ActiveVersionID)
+		if err != nil {
+			continue
+		}
+		job, err := q.getProvisionerJobByIDNoLock(ctx, version.JobID)
+		if err != nil {
+			continue
+		}
+		rows = append(rows, database.GetActiveTemplateVersionJobTagsRow{
+			TemplateID:   template.ID,
+			TemplateName: template.Name,
+			Provisioner:  job.Provisioner,
+			Tags:         job.Tags,
+		})
+	}
+	return rows, nil
+}
+
 func (q *FakeQuerier) GetActiveUserCount(_ context.Context) (int64, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
 
 	active := int64(0)
 	for _, u := range q.users {
-		if u.Status == database.UserStatusActive && !u.Deleted {
+		if u.Status == database.UserStatusActive && !u.Deleted && u.LoginType != database.LoginTypeServiceAccount {
 			active++
 		}
 	}
@@ -1092,6 +1275,7 @@ func (q *FakeQuerier) GetAuditLogsOffset(_ context.Context, arg database.GetAudi
 			UserCreatedAt:    sql.NullTime{Time: user.CreatedAt, Valid: userValid},
 			UserStatus:       database.NullUserStatus{UserStatus: user.Status, Valid: userValid},
 			UserRoles:        user.RBACRoles,
+			UserLoginType:    database.NullLoginType{LoginType: user.LoginType, Valid: userValid},
 			Count:            0,
 		})
 
@@ -1393,18 +1577,41 @@ func (q *FakeQuerier) GetGitAuthLink(_ context.Context, arg database.GetGitAuthL
 	return database.GitAuthLink{}, sql.ErrNoRows
 }
 
-func (q *FakeQuerier) GetGitSSHKey(_ context.Context, userID uuid.UUID) (database.GitSSHKey, error) {
+func (q *FakeQuerier) GetGitSSHKey(_ context.Context, arg database.GetGitSSHKeyParams) (database.GitSSHKey, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.GitSSHKey{}, err
+	}
+
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
 
 	for _, key := range q.gitSSHKey {
-		if key.UserID == userID {
+		if key.UserID == arg.UserID && key.Name == arg.Name {
 			return key, nil
 		}
 	}
 	return database.GitSSHKey{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) GetGitSSHKeysByUserID(_ context.Context, userID uuid.UUID) ([]database.GitSSHKey, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	keys := make([]database.GitSSHKey, 0)
+	for _, key := range q.gitSSHKey {
+		if key.UserID == userID {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if !keys[i].CreatedAt.Equal(keys[j].CreatedAt) {
+			return keys[i].CreatedAt.Before(keys[j].CreatedAt)
+		}
+		return keys[i].Name < keys[j].Name
+	})
+	return keys, nil
+}
+
 func (q *FakeQuerier) GetGroupByID(ctx context.Context, id uuid.UUID) (database.Group, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -1588,6 +1795,109 @@ func (q *FakeQuerier) GetLogoURL(_ context.Context) (string, error) {
 	return q.logoURL, nil
 }
 
+func (q *FakeQuerier) GetOAuth2ProviderAppByID(_ context.Context, id uuid.UUID) (database.OAuth2ProviderApp, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, app := range q.oauth2ProviderApps {
+		if app.ID == id {
+			return app, nil
+		}
+	}
+	return database.OAuth2ProviderApp{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetOAuth2ProviderAppCodeByID(_ context.Context, id uuid.UUID) (database.OAuth2ProviderAppCode, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, code := range q.oauth2ProviderAppCodes {
+		if code.ID == id {
+			return code, nil
+		}
+	}
+	return database.OAuth2ProviderAppCode{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetOAuth2ProviderAppCodeByPrefix(_ context.Context, secretPrefix string) (database.OAuth2ProviderAppCode, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, code := range q.oauth2ProviderAppCodes {
+		if code.SecretPrefix == secretPrefix {
+			return code, nil
+		}
+	}
+	return database.OAuth2ProviderAppCode{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetOAuth2ProviderAppSecretByID(_ context.Context, id uuid.UUID) (database.OAuth2ProviderAppSecret, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, secret := range q.oauth2ProviderAppSecrets {
+		if secret.ID == id {
+			return secret, nil
+		}
+	}
+	return database.OAuth2ProviderAppSecret{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetOAuth2ProviderAppSecretsByAppID(_ context.Context, appID uuid.UUID) ([]database.OAuth2ProviderAppSecret, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, app := range q.oauth2ProviderApps {
+		if app.ID == appID {
+			secrets := []database.OAuth2ProviderAppSecret{}
+			for _, secret := range q.oauth2ProviderAppSecrets {
+				if secret.AppID == appID {
+					secrets = append(secrets, secret)
+				}
+			}
+
+			slices.SortFunc(secrets, func(a, b database.OAuth2ProviderAppSecret) int {
+				if a.CreatedAt.Equal(b.CreatedAt) {
+					return slice.Ascending(a.ID.String(), b.ID.String())
+				}
+				if a.CreatedAt.Before(b.CreatedAt) {
+					return -1
+				}
+				return 1
+			})
+			return secrets, nil
+		}
+	}
+
+	return []database.OAuth2ProviderAppSecret{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetOAuth2ProviderAppTokenByPrefix(_ context.Context, hashPrefix string) (database.OAuth2ProviderAppToken, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, token := range q.oauth2ProviderAppTokens {
+		if token.HashPrefix == hashPrefix {
+			return token, nil
+		}
+	}
+	return database.OAuth2ProviderAppToken{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetOAuth2ProviderApps(_ context.Context) ([]database.OAuth2ProviderApp, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	apps := slices.Clone(q.oauth2ProviderApps)
+	slices.SortFunc(apps, func(a, b database.OAuth2ProviderApp) int {
+		if a.Name == b.Name {
+			return slice.Ascending(a.ID.String(), b.ID.String())
+		}
+		return slice.Ascending(a.Name, b.Name)
+	})
+	return apps, nil
+}
+
 func (q *FakeQuerier) GetOAuthSigningKey(_ context.Context) (string, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -1821,30 +2131,52 @@ func (q *FakeQuerier) GetProvisionerJobsByIDsWithQueuePosition(_ context.Context
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
 
+	// Jobs are queued behind unstarted jobs with the same provisioner and a
+	// subset of their tags, which every daemon that can acquire them can
+	// acquire as well. Provisioner jobs are stored in creation order.
+	queuedBehind := func(job, other database.ProvisionerJob) bool {
+		if job.Provisioner != other.Provisioner {
+			return false
+		}
+		for key, value := range other.Tags {
+			if jobValue, ok := job.Tags[key]; !ok || jobValue != value {
+				return false
+			}
+		}
+		return true
+	}
 	jobs := make([]database.GetProvisionerJobsByIDsWithQueuePositionRow, 0)
-	queuePosition := int64(1)
-	for _, job := range q.provisionerJobs {
-		for _, id := range ids {
-			if id == job.ID {
-				job := database.GetProvisionerJobsByIDsWithQueuePositionRow{
-					ProvisionerJob: job,
+	for i, job := range q.provisionerJobs {
+		if !slices.Contains(ids, job.ID) {
+			continue
+		}
+		row := database.GetProvisionerJobsByIDsWithQueuePositionRow{
+			ProvisionerJob: job,
+		}
+		if !job.StartedAt.Valid {
+			for j, other := range q.provisionerJobs {
+				if other.StartedAt.Valid || !queuedBehind(job, other) {
+					continue
 				}
-				if !job.ProvisionerJob.StartedAt.Valid {
-					job.QueuePosition = queuePosition
+				if j <= i {
+					row.QueuePosition++
 				}
-				jobs = append(jobs, job)
-				break
+				row.QueueSize++
 			}
 		}
-		if !job.StartedAt.Valid {
-			queuePosition++
-		}
-	}
-	for _, job := range jobs {
-		if !job.ProvisionerJob.StartedAt.Valid {
-			// Set it to the max position!
-			job.QueueSize = queuePosition
+		for _, daemon := range q.provisionerDaemons {
+			if !daemon.Healthy(database.ProvisionerDaemonStaleTimeout) || !daemon.CanAcquire(job.Provisioner, job.Tags) {
+				continue
+			}
+			row.MatchingDaemons++
+			for _, running := range q.provisionerJobs {
+				if running.WorkerID.Valid && running.WorkerID.UUID == daemon.ID &&
+					running.StartedAt.Valid && !running.CompletedAt.Valid {
+					row.RunningJobs++
+				}
+			}
 		}
+		jobs = append(jobs, row)
 	}
 	return jobs, nil
 }
@@ -1862,6 +2194,22 @@ func (q *FakeQuerier) GetProvisionerJobsCreatedAfter(_ context.Context, after ti
 	return jobs, nil
 }
 
+func (q *FakeQuerier) GetProvisionerJobsRunningByWorkerIDs(_ context.Context, workerIDs []uuid.UUID) ([]database.ProvisionerJob, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	jobs := make([]database.ProvisionerJob, 0)
+	for _, job := range q.provisionerJobs {
+		if !job.WorkerID.Valid || !slices.Contains(workerIDs, job.WorkerID.UUID) {
+			continue
+		}
+		if job.StartedAt.Valid && !job.CompletedAt.Valid {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs, nil
+}
+
 func (q *FakeQuerier) GetProvisionerLogsAfterID(_ context.Context, arg database.GetProvisionerLogsAfterIDParams) ([]database.ProvisionerJobLog, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return nil, err
@@ -2805,12 +3153,16 @@ func (q *FakeQuerier) GetUserLatencyInsights(_ context.Context, arg database.Get
 	return rows, nil
 }
 
-func (q *FakeQuerier) GetUserLinkByLinkedID(_ context.Context, id string) (database.UserLink, error) {
+func (q *FakeQuerier) GetUserLinkByLinkedID(_ context.Context, arg database.GetUserLinkByLinkedIDParams) (database.UserLink, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.UserLink{}, err
+	}
+
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
 
 	for _, link := range q.userLinks {
-		if link.LinkedID == id {
+		if link.LinkedID == arg.LinkedID && link.ProviderID == arg.ProviderID {
 			return link, nil
 		}
 	}
@@ -2826,13 +3178,67 @@ func (q *FakeQuerier) GetUserLinkByUserIDLoginType(_ context.Context, params dat
 	defer q.mutex.RUnlock()
 
 	for _, link := range q.userLinks {
-		if link.UserID == params.UserID && link.LoginType == params.LoginType {
+		if link.UserID == params.UserID && link.LoginType == params.LoginType && link.ProviderID == params.ProviderID {
 			return link, nil
 		}
 	}
 	return database.UserLink{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) GetUserLinksByLoginType(_ context.Context, loginType database.LoginType) ([]database.UserLink, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	links := make([]database.UserLink, 0)
+	for _, link := range q.userLinks {
+		if link.LoginType == loginType {
+			links = append(links, link)
+		}
+	}
+	return links, nil
+}
+
+func (q *FakeQuerier) GetUserLinksByUserID(_ context.Context, userID uuid.UUID) ([]database.UserLink, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	links := make([]database.UserLink, 0)
+	for _, link := range q.userLinks {
+		if link.UserID == userID {
+			links = append(links, link)
+		}
+	}
+	return links, nil
+}
+
+func (q *FakeQuerier) GetUserMFAByUserID(_ context.Context, userID uuid.UUID) (database.UserMFA, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, mfa := range q.userMFA {
+		if mfa.UserID == userID {
+			return mfa, nil
+		}
+	}
+	return database.UserMFA{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetUserMFARecoveryCodesByUserID(_ context.Context, userID uuid.UUID) ([]database.UserMFARecoveryCode, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	codes := make([]database.UserMFARecoveryCode, 0)
+	for _, code := range q.userMFARecoveryCodes {
+		if code.UserID == userID {
+			codes = append(codes, code)
+		}
+	}
+	slices.SortFunc(codes, func(a, b database.UserMFARecoveryCode) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return codes, nil
+}
+
 func (q *FakeQuerier) GetUsers(_ context.Context, params database.GetUsersParams) ([]database.GetUsersRow, error) {
 	if err := validateDatabaseType(params); err != nil {
 		return nil, err
@@ -3808,6 +4214,9 @@ func (q *FakeQuerier) InsertAPIKey(_ context.Context, arg database.InsertAPIKeyP
 		LoginType:       arg.LoginType,
 		Scope:           arg.Scope,
 		TokenName:       arg.TokenName,
+		Scopes:          arg.Scopes,
+		AllowList:       arg.AllowList,
+		ProviderID:      arg.ProviderID,
 	}
 	q.apiKeys = append(q.apiKeys, key)
 	return key, nil
@@ -3912,6 +4321,12 @@ func (q *FakeQuerier) InsertGitSSHKey(_ context.Context, arg database.InsertGitS
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
 
+	for _, key := range q.gitSSHKey {
+		if key.UserID == arg.UserID && key.Name == arg.Name {
+			return database.GitSSHKey{}, errDuplicateKey
+		}
+	}
+
 	//nolint:gosimple
 	gitSSHKey := database.GitSSHKey{
 		UserID:     arg.UserID,
@@ -3919,6 +4334,9 @@ func (q *FakeQuerier) InsertGitSSHKey(_ context.Context, arg database.InsertGitS
 		UpdatedAt:  arg.UpdatedAt,
 		PrivateKey: arg.PrivateKey,
 		PublicKey:  arg.PublicKey,
+		Name:       arg.Name,
+		Imported:   arg.Imported,
+		Hosts:      arg.Hosts,
 	}
 	q.gitSSHKey = append(q.gitSSHKey, gitSSHKey)
 	return gitSSHKey, nil
@@ -4039,6 +4457,132 @@ func (q *FakeQuerier) InsertMissingGroups(_ context.Context, arg database.Insert
 	return newGroups, nil
 }
 
+func (q *FakeQuerier) InsertOAuth2ProviderApp(_ context.Context, arg database.InsertOAuth2ProviderAppParams) (database.OAuth2ProviderApp, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.OAuth2ProviderApp{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, app := range q.oauth2ProviderApps {
+		if app.Name == arg.Name {
+			return database.OAuth2ProviderApp{}, errDuplicateKey
+		}
+	}
+
+	//nolint:gosimple // Go wants database.OAuth2ProviderApp(arg), but we cannot be sure the structs will remain identical.
+	app := database.OAuth2ProviderApp{
+		ID:          arg.ID,
+		CreatedAt:   arg.CreatedAt,
+		UpdatedAt:   arg.UpdatedAt,
+		Name:        arg.Name,
+		Icon:        arg.Icon,
+		CallbackURL: arg.CallbackURL,
+	}
+	q.oauth2ProviderApps = append(q.oauth2ProviderApps, app)
+
+	return app, nil
+}
+
+func (q *FakeQuerier) InsertOAuth2ProviderAppCode(_ context.Context, arg database.InsertOAuth2ProviderAppCodeParams) (database.OAuth2ProviderAppCode, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.OAuth2ProviderAppCode{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if !slices.ContainsFunc(q.oauth2ProviderApps, func(app database.OAuth2ProviderApp) bool {
+		return app.ID == arg.AppID
+	}) {
+		return database.OAuth2ProviderAppCode{}, sql.ErrNoRows
+	}
+	for _, code := range q.oauth2ProviderAppCodes {
+		if code.SecretPrefix == arg.SecretPrefix {
+			return database.OAuth2ProviderAppCode{}, errDuplicateKey
+		}
+	}
+
+	//nolint:gosimple // Go wants database.OAuth2ProviderAppCode(arg), but we cannot be sure the structs will remain identical.
+	code := database.OAuth2ProviderAppCode{
+		ID:            arg.ID,
+		CreatedAt:     arg.CreatedAt,
+		ExpiresAt:     arg.ExpiresAt,
+		SecretPrefix:  arg.SecretPrefix,
+		HashedSecret:  arg.HashedSecret,
+		UserID:        arg.UserID,
+		AppID:         arg.AppID,
+		RedirectURI:   arg.RedirectURI,
+		CodeChallenge: arg.CodeChallenge,
+	}
+	q.oauth2ProviderAppCodes = append(q.oauth2ProviderAppCodes, code)
+	return code, nil
+}
+
+func (q *FakeQuerier) InsertOAuth2ProviderAppSecret(_ context.Context, arg database.InsertOAuth2ProviderAppSecretParams) (database.OAuth2ProviderAppSecret, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.OAuth2ProviderAppSecret{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, app := range q.oauth2ProviderApps {
+		if app.ID == arg.AppID {
+			secret := database.OAuth2ProviderAppSecret{
+				ID:            arg.ID,
+				CreatedAt:     arg.CreatedAt,
+				HashedSecret:  arg.HashedSecret,
+				DisplaySecret: arg.DisplaySecret,
+				AppID:         arg.AppID,
+			}
+			q.oauth2ProviderAppSecrets = append(q.oauth2ProviderAppSecrets, secret)
+			return secret, nil
+		}
+	}
+
+	return database.OAuth2ProviderAppSecret{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) InsertOAuth2ProviderAppToken(_ context.Context, arg database.InsertOAuth2ProviderAppTokenParams) (database.OAuth2ProviderAppToken, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.OAuth2ProviderAppToken{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if !slices.ContainsFunc(q.oauth2ProviderAppSecrets, func(secret database.OAuth2ProviderAppSecret) bool {
+		return secret.ID == arg.AppSecretID
+	}) {
+		return database.OAuth2ProviderAppToken{}, sql.ErrNoRows
+	}
+	if !slices.ContainsFunc(q.apiKeys, func(key database.APIKey) bool {
+		return key.ID == arg.APIKeyID
+	}) {
+		return database.OAuth2ProviderAppToken{}, sql.ErrNoRows
+	}
+
+	//nolint:gosimple // Go wants database.OAuth2ProviderAppToken(arg), but we cannot be sure the structs will remain identical.
+	token := database.OAuth2ProviderAppToken{
+		ID:          arg.ID,
+		CreatedAt:   arg.CreatedAt,
+		ExpiresAt:   arg.ExpiresAt,
+		HashPrefix:  arg.HashPrefix,
+		RefreshHash: arg.RefreshHash,
+		AppSecretID: arg.AppSecretID,
+		APIKeyID:    arg.APIKeyID,
+		UserID:      arg.UserID,
+	}
+	q.oauth2ProviderAppTokens = append(q.oauth2ProviderAppTokens, token)
+	return token, nil
+}
+
 func (q *FakeQuerier) InsertOrganization(_ context.Context, arg database.InsertOrganizationParams) (database.Organization, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.Organization{}, err
@@ -4261,6 +4805,8 @@ func (q *FakeQuerier) InsertTemplateVersionParameter(_ context.Context, arg data
 		Required:            arg.Required,
 		DisplayOrder:        arg.DisplayOrder,
 		Ephemeral:           arg.Ephemeral,
+		VisibleIf:           arg.VisibleIf,
+		ValidationRules:     arg.ValidationRules,
 	}
 	q.templateVersionParameters = append(q.templateVersionParameters, param)
 	return param, nil
@@ -4319,15 +4865,16 @@ func (q *FakeQuerier) InsertUser(_ context.Context, arg database.InsertUserParam
 	}
 
 	user := database.User{
-		ID:             arg.ID,
-		Email:          arg.Email,
-		HashedPassword: arg.HashedPassword,
-		CreatedAt:      arg.CreatedAt,
-		UpdatedAt:      arg.UpdatedAt,
-		Username:       arg.Username,
-		Status:         database.UserStatusDormant,
-		RBACRoles:      arg.RBACRoles,
-		LoginType:      arg.LoginType,
+		ID:                         arg.ID,
+		Email:                      arg.Email,
+		HashedPassword:             arg.HashedPassword,
+		CreatedAt:                  arg.CreatedAt,
+		UpdatedAt:                  arg.UpdatedAt,
+		Username:                   arg.Username,
+		Status:                     database.UserStatusDormant,
+		RBACRoles:                  arg.RBACRoles,
+		LoginType:                  arg.LoginType,
+		ServiceAccountOwnerGroupID: arg.ServiceAccountOwnerGroupID,
 	}
 	q.users = append(q.users, user)
 	return user, nil
@@ -4368,6 +4915,7 @@ func (q *FakeQuerier) InsertUserLink(_ context.Context, args database.InsertUser
 		OAuthAccessToken:  args.OAuthAccessToken,
 		OAuthRefreshToken: args.OAuthRefreshToken,
 		OAuthExpiry:       args.OAuthExpiry,
+		ProviderID:        args.ProviderID,
 	}
 
 	q.userLinks = append(q.userLinks, link)
@@ -4375,6 +4923,30 @@ func (q *FakeQuerier) InsertUserLink(_ context.Context, args database.InsertUser
 	return link, nil
 }
 
+func (q *FakeQuerier) InsertUserMFARecoveryCode(_ context.Context, arg database.InsertUserMFARecoveryCodeParams) (database.UserMFARecoveryCode, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.UserMFARecoveryCode{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, code := range q.userMFARecoveryCodes {
+		if code.UserID == arg.UserID && bytes.Equal(code.HashedCode, arg.HashedCode) {
+			return database.UserMFARecoveryCode{}, errDuplicateKey
+		}
+	}
+
+	code := database.UserMFARecoveryCode{
+		ID:         arg.ID,
+		UserID:     arg.UserID,
+		CreatedAt:  arg.CreatedAt,
+		HashedCode: arg.HashedCode,
+	}
+	q.userMFARecoveryCodes = append(q.userMFARecoveryCodes, code)
+	return code, nil
+}
+
 func (q *FakeQuerier) InsertWorkspace(_ context.Context, arg database.InsertWorkspaceParams) (database.Workspace, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.Workspace{}, err
@@ -4775,6 +5347,30 @@ func (q *FakeQuerier) InsertWorkspaceResourceMetadata(_ context.Context, arg dat
 	return metadata, nil
 }
 
+func (q *FakeQuerier) RecordUserMFAFailedAttempt(_ context.Context, arg database.RecordUserMFAFailedAttemptParams) (database.UserMFA, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.UserMFA{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, mfa := range q.userMFA {
+		if mfa.UserID != arg.UserID {
+			continue
+		}
+		if mfa.LastFailedAt.Valid && mfa.LastFailedAt.Time.After(arg.FailedAfter) {
+			mfa.FailedAttempts++
+		} else {
+			mfa.FailedAttempts = 1
+		}
+		mfa.LastFailedAt = arg.LastFailedAt
+		q.userMFA[index] = mfa
+		return mfa, nil
+	}
+	return database.UserMFA{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) RegisterWorkspaceProxy(_ context.Context, arg database.RegisterWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -4793,6 +5389,22 @@ func (q *FakeQuerier) RegisterWorkspaceProxy(_ context.Context, arg database.Reg
 	return database.WorkspaceProxy{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) ResetUserMFAFailedAttempts(_ context.Context, userID uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, mfa := range q.userMFA {
+		if mfa.UserID != userID {
+			continue
+		}
+		mfa.FailedAttempts = 0
+		mfa.LastFailedAt = sql.NullTime{}
+		q.userMFA[index] = mfa
+		return nil
+	}
+	return nil
+}
+
 func (*FakeQuerier) TryAcquireLock(_ context.Context, _ int64) (bool, error) {
 	return false, xerrors.New("TryAcquireLock must only be called within a transaction")
 }
@@ -4852,12 +5464,13 @@ func (q *FakeQuerier) UpdateGitSSHKey(_ context.Context, arg database.UpdateGitS
 	defer q.mutex.Unlock()
 
 	for index, key := range q.gitSSHKey {
-		if key.UserID != arg.UserID {
+		if key.UserID != arg.UserID || key.Name != arg.Name {
 			continue
 		}
 		key.UpdatedAt = arg.UpdatedAt
 		key.PrivateKey = arg.PrivateKey
 		key.PublicKey = arg.PublicKey
+		key.Imported = arg.Imported
 		q.gitSSHKey[index] = key
 		return key, nil
 	}
@@ -4938,6 +5551,74 @@ func (q *FakeQuerier) UpdateMemberRoles(_ context.Context, arg database.UpdateMe
 	return database.OrganizationMember{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateOAuth2ProviderAppByID(_ context.Context, arg database.UpdateOAuth2ProviderAppByIDParams) (database.OAuth2ProviderApp, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.OAuth2ProviderApp{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, app := range q.oauth2ProviderApps {
+		if app.Name == arg.Name && app.ID != arg.ID {
+			return database.OAuth2ProviderApp{}, errDuplicateKey
+		}
+	}
+
+	for index, app := range q.oauth2ProviderApps {
+		if app.ID == arg.ID {
+			app.UpdatedAt = arg.UpdatedAt
+			app.Name = arg.Name
+			app.Icon = arg.Icon
+			app.CallbackURL = arg.CallbackURL
+			q.oauth2ProviderApps[index] = app
+			return app, nil
+		}
+	}
+	return database.OAuth2ProviderApp{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) UpdateOAuth2ProviderAppSecretByID(_ context.Context, arg database.UpdateOAuth2ProviderAppSecretByIDParams) (database.OAuth2ProviderAppSecret, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.OAuth2ProviderAppSecret{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, secret := range q.oauth2ProviderAppSecrets {
+		if secret.ID == arg.ID {
+			secret.LastUsedAt = arg.LastUsedAt
+			q.oauth2ProviderAppSecrets[index] = secret
+			return secret, nil
+		}
+	}
+	return database.OAuth2ProviderAppSecret{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) UpdateProvisionerDaemonLastSeenAt(_ context.Context, arg database.UpdateProvisionerDaemonLastSeenAtParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, daemon := range q.provisionerDaemons {
+		if daemon.ID != arg.ID {
+			continue
+		}
+		daemon.LastSeenAt = arg.LastSeenAt
+		daemon.Version = arg.Version
+		daemon.ProvisionerVersions = arg.ProvisionerVersions
+		q.provisionerDaemons[index] = daemon
+		return nil
+	}
+	return sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateProvisionerJobByID(_ context.Context, arg database.UpdateProvisionerJobByIDParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -5105,6 +5786,7 @@ func (q *FakeQuerier) UpdateTemplateMetaByID(_ context.Context, arg database.Upd
 		tpl.DisplayName = arg.DisplayName
 		tpl.Description = arg.Description
 		tpl.Icon = arg.Icon
+		tpl.EgressPolicy = arg.EgressPolicy
 		q.templates[idx] = tpl
 		return nil
 	}
@@ -5304,7 +5986,7 @@ func (q *FakeQuerier) UpdateUserLink(_ context.Context, params database.UpdateUs
 	defer q.mutex.Unlock()
 
 	for i, link := range q.userLinks {
-		if link.UserID == params.UserID && link.LoginType == params.LoginType {
+		if link.UserID == params.UserID && link.LoginType == params.LoginType && link.ProviderID == params.ProviderID {
 			link.OAuthAccessToken = params.OAuthAccessToken
 			link.OAuthRefreshToken = params.OAuthRefreshToken
 			link.OAuthExpiry = params.OAuthExpiry
@@ -5326,7 +6008,7 @@ func (q *FakeQuerier) UpdateUserLinkedID(_ context.Context, params database.Upda
 	defer q.mutex.Unlock()
 
 	for i, link := range q.userLinks {
-		if link.UserID == params.UserID && link.LoginType == params.LoginType {
+		if link.UserID == params.UserID && link.LoginType == params.LoginType && link.ProviderID == params.ProviderID {
 			link.LinkedID = params.LinkedID
 
 			q.userLinks[i] = link
@@ -5358,6 +6040,25 @@ func (q *FakeQuerier) UpdateUserLoginType(_ context.Context, arg database.Update
 	return database.User{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateUserMFALastUsedStep(_ context.Context, arg database.UpdateUserMFALastUsedStepParams) (database.UserMFA, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.UserMFA{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, mfa := range q.userMFA {
+		if mfa.UserID != arg.UserID || mfa.TOTPLastUsedStep >= arg.TOTPLastUsedStep {
+			continue
+		}
+		mfa.TOTPLastUsedStep = arg.TOTPLastUsedStep
+		q.userMFA[index] = mfa
+		return mfa, nil
+	}
+	return database.UserMFA{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateUserProfile(_ context.Context, arg database.UpdateUserProfileParams) (database.User, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.User{}, err
@@ -5904,6 +6605,52 @@ func (q *FakeQuerier) UpsertServiceBanner(_ context.Context, data string) error
 	return nil
 }
 
+func (q *FakeQuerier) UpsertUserMFA(_ context.Context, arg database.UpsertUserMFAParams) (database.UserMFA, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.UserMFA{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	mfa := database.UserMFA{
+		UserID:     arg.UserID,
+		CreatedAt:  arg.CreatedAt,
+		UpdatedAt:  arg.UpdatedAt,
+		TOTPSecret: arg.TOTPSecret,
+	}
+	for index, existing := range q.userMFA {
+		if existing.UserID == arg.UserID {
+			if existing.EnabledAt.Valid {
+				return database.UserMFA{}, sql.ErrNoRows
+			}
+			q.userMFA[index] = mfa
+			return mfa, nil
+		}
+	}
+	q.userMFA = append(q.userMFA, mfa)
+	return mfa, nil
+}
+
+func (q *FakeQuerier) UseUserMFARecoveryCode(_ context.Context, arg database.UseUserMFARecoveryCodeParams) (database.UserMFARecoveryCode, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.UserMFARecoveryCode{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, code := range q.userMFARecoveryCodes {
+		if code.UserID != arg.UserID || !bytes.Equal(code.HashedCode, arg.HashedCode) || code.UsedAt.Valid {
+			continue
+		}
+		code.UsedAt = arg.UsedAt
+		q.userMFARecoveryCodes[index] = code
+		return code, nil
+	}
+	return database.UserMFARecoveryCode{}, sql.ErrNoRows
+}
+
 func (*FakeQuerier) UpsertTailnetAgent(context.Context, database.UpsertTailnetAgentParams) (database.TailnetAgent, error) {
 	return database.TailnetAgent{}, ErrUnimplemented
 }
@@ -117,6 +117,9 @@ func APIKey(t testing.TB, db database.Store, seed database.APIKey) (key database
 		LoginType:       takeFirst(seed.LoginType, database.LoginTypePassword),
 		Scope:           takeFirst(seed.Scope, database.APIKeyScopeAll),
 		TokenName:       takeFirst(seed.TokenName),
+		Scopes:          takeFirstSlice(seed.Scopes, []string{}),
+		AllowList:       takeFirstSlice(seed.AllowList, []string{}),
+		ProviderID:      takeFirst(seed.ProviderID),
 	})
 	require.NoError(t, err, "insert api key")
 	return key, fmt.Sprintf("%s-%s", key.ID, secret)
@@ -214,14 +217,15 @@ func WorkspaceBuild(t testing.TB, db database.Store, orig database.WorkspaceBuil
 
 func User(t testing.TB, db database.Store, orig database.User) database.User {
 	user, err := db.InsertUser(genCtx, database.InsertUserParams{
-		ID:             takeFirst(orig.ID, uuid.New()),
-		Email:          takeFirst(orig.Email, namesgenerator.GetRandomName(1)),
-		Username:       takeFirst(orig.Username, namesgenerator.GetRandomName(1)),
-		HashedPassword: takeFirstSlice(orig.HashedPassword, []byte(must(cryptorand.String(32)))),
-		CreatedAt:      takeFirst(orig.CreatedAt, dbtime.Now()),
-		UpdatedAt:      takeFirst(orig.UpdatedAt, dbtime.Now()),
-		RBACRoles:      takeFirstSlice(orig.RBACRoles, []string{}),
-		LoginType:      takeFirst(orig.LoginType, database.LoginTypePassword),
+		ID:                         takeFirst(orig.ID, uuid.New()),
+		Email:                      takeFirst(orig.Email, namesgenerator.GetRandomName(1)),
+		Username:                   takeFirst(orig.Username, namesgenerator.GetRandomName(1)),
+		HashedPassword:             takeFirstSlice(orig.HashedPassword, []byte(must(cryptorand.String(32)))),
+		CreatedAt:                  takeFirst(orig.CreatedAt, dbtime.Now()),
+		UpdatedAt:                  takeFirst(orig.UpdatedAt, dbtime.Now()),
+		RBACRoles:                  takeFirstSlice(orig.RBACRoles, []string{}),
+		LoginType:                  takeFirst(orig.LoginType, database.LoginTypePassword),
+		ServiceAccountOwnerGroupID: orig.ServiceAccountOwnerGroupID,
 	})
 	require.NoError(t, err, "insert user")
 
@@ -250,6 +254,9 @@ func GitSSHKey(t testing.TB, db database.Store, orig database.GitSSHKey) databas
 		UpdatedAt:  takeFirst(orig.UpdatedAt, dbtime.Now()),
 		PrivateKey: takeFirst(orig.PrivateKey, ""),
 		PublicKey:  takeFirst(orig.PublicKey, ""),
+		Name:       takeFirst(orig.Name, "default"),
+		Imported:   orig.Imported,
+		Hosts:      takeFirstSlice(orig.Hosts, []string{}),
 	})
 	require.NoError(t, err, "insert ssh key")
 	return key
@@ -476,6 +483,7 @@ func UserLink(t testing.TB, db database.Store, orig database.UserLink) database.
 		OAuthAccessToken:  takeFirst(orig.OAuthAccessToken, uuid.NewString()),
 		OAuthRefreshToken: takeFirst(orig.OAuthAccessToken, uuid.NewString()),
 		OAuthExpiry:       takeFirst(orig.OAuthExpiry, dbtime.Now().Add(time.Hour*24)),
+		ProviderID:        takeFirst(orig.ProviderID),
 	})
 
 	require.NoError(t, err, "insert link")
@@ -570,6 +578,93 @@ func WorkspaceAgentStat(t testing.TB, db database.Store, orig database.Workspace
 	return scheme
 }
 
+func OAuth2ProviderApp(t testing.TB, db database.Store, seed database.OAuth2ProviderApp) database.OAuth2ProviderApp {
+	app, err := db.InsertOAuth2ProviderApp(genCtx, database.InsertOAuth2ProviderAppParams{
+		ID:          takeFirst(seed.ID, uuid.New()),
+		Name:        takeFirst(seed.Name, namesgenerator.GetRandomName(1)),
+		CreatedAt:   takeFirst(seed.CreatedAt, dbtime.Now()),
+		UpdatedAt:   takeFirst(seed.UpdatedAt, dbtime.Now()),
+		Icon:        takeFirst(seed.Icon, ""),
+		CallbackURL: takeFirst(seed.CallbackURL, "http://localhost"),
+	})
+	require.NoError(t, err, "insert oauth2 app")
+	return app
+}
+
+func OAuth2ProviderAppSecret(t testing.TB, db database.Store, seed database.OAuth2ProviderAppSecret) database.OAuth2ProviderAppSecret {
+	secret, err := db.InsertOAuth2ProviderAppSecret(genCtx, database.InsertOAuth2ProviderAppSecretParams{
+		ID:            takeFirst(seed.ID, uuid.New()),
+		CreatedAt:     takeFirst(seed.CreatedAt, dbtime.Now()),
+		HashedSecret:  takeFirstSlice(seed.HashedSecret, []byte(must(cryptorand.String(32)))),
+		DisplaySecret: takeFirst(seed.DisplaySecret, "secret"),
+		AppID:         takeFirst(seed.AppID, uuid.New()),
+	})
+	require.NoError(t, err, "insert oauth2 app secret")
+	return secret
+}
+
+func OAuth2ProviderAppCode(t testing.TB, db database.Store, seed database.OAuth2ProviderAppCode) database.OAuth2ProviderAppCode {
+	code, err := db.InsertOAuth2ProviderAppCode(genCtx, database.InsertOAuth2ProviderAppCodeParams{
+		ID:            takeFirst(seed.ID, uuid.New()),
+		CreatedAt:     takeFirst(seed.CreatedAt, dbtime.Now()),
+		ExpiresAt:     takeFirst(seed.ExpiresAt, dbtime.Now().Add(10*time.Minute)),
+		SecretPrefix:  takeFirst(seed.SecretPrefix, uuid.NewString()),
+		HashedSecret:  takeFirstSlice(seed.HashedSecret, []byte(must(cryptorand.String(32)))),
+		UserID:        takeFirst(seed.UserID, uuid.New()),
+		AppID:         takeFirst(seed.AppID, uuid.New()),
+		RedirectURI:   takeFirst(seed.RedirectURI, "http://localhost"),
+		CodeChallenge: takeFirst(seed.CodeChallenge, ""),
+	})
+	require.NoError(t, err, "insert oauth2 app code")
+	return code
+}
+
+func OAuth2ProviderAppToken(t testing.TB, db database.Store, seed database.OAuth2ProviderAppToken) database.OAuth2ProviderAppToken {
+	token, err := db.InsertOAuth2ProviderAppToken(genCtx, database.InsertOAuth2ProviderAppTokenParams{
+		ID:          takeFirst(seed.ID, uuid.New()),
+		CreatedAt:   takeFirst(seed.CreatedAt, dbtime.Now()),
+		ExpiresAt:   takeFirst(seed.ExpiresAt, dbtime.Now().Add(time.Hour)),
+		HashPrefix:  takeFirst(seed.HashPrefix, uuid.NewString()),
+		RefreshHash: takeFirstSlice(seed.RefreshHash, []byte(must(cryptorand.String(32)))),
+		AppSecretID: takeFirst(seed.AppSecretID, uuid.New()),
+		APIKeyID:    takeFirst(seed.APIKeyID, uuid.NewString()),
+		UserID:      takeFirst(seed.UserID, uuid.New()),
+	})
+	require.NoError(t, err, "insert oauth2 app token")
+	return token
+}
+
+// UserMFA inserts an MFA enrollment, which is enabled if EnabledAt is set.
+func UserMFA(t testing.TB, db database.Store, seed database.UserMFA) database.UserMFA {
+	mfa, err := db.UpsertUserMFA(genCtx, database.UpsertUserMFAParams{
+		UserID:     takeFirst(seed.UserID, uuid.New()),
+		CreatedAt:  takeFirst(seed.CreatedAt, dbtime.Now()),
+		UpdatedAt:  takeFirst(seed.UpdatedAt, dbtime.Now()),
+		TOTPSecret: takeFirst(seed.TOTPSecret, "JBSWY3DPEHPK3PXP"),
+	})
+	require.NoError(t, err, "insert user mfa")
+	if seed.EnabledAt.Valid {
+		mfa, err = db.EnableUserMFA(genCtx, database.EnableUserMFAParams{
+			UserID:           mfa.UserID,
+			UpdatedAt:        seed.EnabledAt.Time,
+			TOTPLastUsedStep: seed.TOTPLastUsedStep,
+		})
+		require.NoError(t, err, "enable user mfa")
+	}
+	return mfa
+}
+
+func UserMFARecoveryCode(t testing.TB, db database.Store, seed database.UserMFARecoveryCode) database.UserMFARecoveryCode {
+	code, err := db.InsertUserMFARecoveryCode(genCtx, database.InsertUserMFARecoveryCodeParams{
+		ID:         takeFirst(seed.ID, uuid.New()),
+		UserID:     takeFirst(seed.UserID, uuid.New()),
+		CreatedAt:  takeFirst(seed.CreatedAt, dbtime.Now()),
+		HashedCode: takeFirstSlice(seed.HashedCode, []byte(must(cryptorand.String(32)))),
+	})
+	require.NoError(t, err, "insert user mfa recovery code")
+	return code
+}
+
 func must[V any](v V, err error) V {
 	if err != nil {
 		panic(err)
@@ -41,7 +41,10 @@ func TestGenerator(t *testing.T) {
 		t.Parallel()
 		db := dbfake.New()
 		exp := dbgen.UserLink(t, db, database.UserLink{})
-		require.Equal(t, exp, must(db.GetUserLinkByLinkedID(context.Background(), exp.LinkedID)))
+		require.Equal(t, exp, must(db.GetUserLinkByLinkedID(context.Background(), database.GetUserLinkByLinkedIDParams{
+			LinkedID:   exp.LinkedID,
+			ProviderID: exp.ProviderID,
+		})))
 	})
 
 	t.Run("GitAuthLink", func(t *testing.T) {
@@ -171,7 +174,10 @@ func TestGenerator(t *testing.T) {
 		t.Parallel()
 		db := dbfake.New()
 		exp := dbgen.GitSSHKey(t, db, database.GitSSHKey{})
-		require.Equal(t, exp, must(db.GetGitSSHKey(context.Background(), exp.UserID)))
+		require.Equal(t, exp, must(db.GetGitSSHKey(context.Background(), database.GetGitSSHKeyParams{
+			UserID: exp.UserID,
+			Name:   exp.Name,
+		})))
 	})
 }
 
@@ -127,9 +127,9 @@ func (m metricsStore) DeleteCoordinator(ctx context.Context, id uuid.UUID) error
 	return m.s.DeleteCoordinator(ctx, id)
 }
 
-func (m metricsStore) DeleteGitSSHKey(ctx context.Context, userID uuid.UUID) error {
+func (m metricsStore) DeleteGitSSHKey(ctx context.Context, arg database.DeleteGitSSHKeyParams) error {
 	start := time.Now()
-	err := m.s.DeleteGitSSHKey(ctx, userID)
+	err := m.s.DeleteGitSSHKey(ctx, arg)
 	m.queryLatencies.WithLabelValues("DeleteGitSSHKey").Observe(time.Since(start).Seconds())
 	return err
 }
@@ -162,6 +162,34 @@ func (m metricsStore) DeleteLicense(ctx context.Context, id int32) (int32, error
 	return licenseID, err
 }
 
+func (m metricsStore) DeleteOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteOAuth2ProviderAppByID(ctx, id)
+	m.queryLatencies.WithLabelValues("DeleteOAuth2ProviderAppByID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) DeleteOAuth2ProviderAppCodeByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderAppCode, error) {
+	start := time.Now()
+	r0, r1 := m.s.DeleteOAuth2ProviderAppCodeByID(ctx, id)
+	m.queryLatencies.WithLabelValues("DeleteOAuth2ProviderAppCodeByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) DeleteOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteOAuth2ProviderAppSecretByID(ctx, id)
+	m.queryLatencies.WithLabelValues("DeleteOAuth2ProviderAppSecretByID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) DeleteOAuth2ProviderAppTokenByPrefix(ctx context.Context, hashPrefix string) (database.OAuth2ProviderAppToken, error) {
+	start := time.Now()
+	r0, r1 := m.s.DeleteOAuth2ProviderAppTokenByPrefix(ctx, hashPrefix)
+	m.queryLatencies.WithLabelValues("DeleteOAuth2ProviderAppTokenByPrefix").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) DeleteOldWorkspaceAgentLogs(ctx context.Context) error {
 	start := time.Now()
 	r0 := m.s.DeleteOldWorkspaceAgentLogs(ctx)
@@ -195,6 +223,27 @@ func (m metricsStore) DeleteTailnetClient(ctx context.Context, arg database.Dele
 	return m.s.DeleteTailnetClient(ctx, arg)
 }
 
+func (m metricsStore) DeleteUserMFAByUserID(ctx context.Context, userID uuid.UUID) error {
+	start := time.Now()
+	err := m.s.DeleteUserMFAByUserID(ctx, userID)
+	m.queryLatencies.WithLabelValues("DeleteUserMFAByUserID").Observe(time.Since(start).Seconds())
+	return err
+}
+
+func (m metricsStore) DeleteUserMFARecoveryCodesByUserID(ctx context.Context, userID uuid.UUID) error {
+	start := time.Now()
+	err := m.s.DeleteUserMFARecoveryCodesByUserID(ctx, userID)
+	m.queryLatencies.WithLabelValues("DeleteUserMFARecoveryCodesByUserID").Observe(time.Since(start).Seconds())
+	return err
+}
+
+func (m metricsStore) EnableUserMFA(ctx context.Context, arg database.EnableUserMFAParams) (database.UserMFA, error) {
+	start := time.Now()
+	r0, r1 := m.s.EnableUserMFA(ctx, arg)
+	m.queryLatencies.WithLabelValues("EnableUserMFA").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetAPIKeyByID(ctx context.Context, id string) (database.APIKey, error) {
 	start := time.Now()
 	apiKey, err := m.s.GetAPIKeyByID(ctx, id)
@@ -230,6 +279,13 @@ func (m metricsStore) GetAPIKeysLastUsedAfter(ctx context.Context, lastUsed time
 	return apiKeys, err
 }
 
+func (m metricsStore) GetActiveTemplateVersionJobTags(ctx context.Context) ([]database.GetActiveTemplateVersionJobTagsRow, error) {
+	start := time.Now()
+	rows, err := m.s.GetActiveTemplateVersionJobTags(ctx)
+	m.queryLatencies.WithLabelValues("GetActiveTemplateVersionJobTags").Observe(time.Since(start).Seconds())
+	return rows, err
+}
+
 func (m metricsStore) GetActiveUserCount(ctx context.Context) (int64, error) {
 	start := time.Now()
 	count, err := m.s.GetActiveUserCount(ctx)
@@ -349,13 +405,20 @@ func (m metricsStore) GetGitAuthLink(ctx context.Context, arg database.GetGitAut
 	return link, err
 }
 
-func (m metricsStore) GetGitSSHKey(ctx context.Context, userID uuid.UUID) (database.GitSSHKey, error) {
+func (m metricsStore) GetGitSSHKey(ctx context.Context, arg database.GetGitSSHKeyParams) (database.GitSSHKey, error) {
 	start := time.Now()
-	key, err := m.s.GetGitSSHKey(ctx, userID)
+	key, err := m.s.GetGitSSHKey(ctx, arg)
 	m.queryLatencies.WithLabelValues("GetGitSSHKey").Observe(time.Since(start).Seconds())
 	return key, err
 }
 
+func (m metricsStore) GetGitSSHKeysByUserID(ctx context.Context, userID uuid.UUID) ([]database.GitSSHKey, error) {
+	start := time.Now()
+	keys, err := m.s.GetGitSSHKeysByUserID(ctx, userID)
+	m.queryLatencies.WithLabelValues("GetGitSSHKeysByUserID").Observe(time.Since(start).Seconds())
+	return keys, err
+}
+
 func (m metricsStore) GetGroupByID(ctx context.Context, id uuid.UUID) (database.Group, error) {
 	start := time.Now()
 	group, err := m.s.GetGroupByID(ctx, id)
@@ -440,6 +503,55 @@ func (m metricsStore) GetLogoURL(ctx context.Context) (string, error) {
 	return url, err
 }
 
+func (m metricsStore) GetOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderApp, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOAuth2ProviderAppByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetOAuth2ProviderAppByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetOAuth2ProviderAppCodeByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderAppCode, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOAuth2ProviderAppCodeByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetOAuth2ProviderAppCodeByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetOAuth2ProviderAppCodeByPrefix(ctx context.Context, secretPrefix string) (database.OAuth2ProviderAppCode, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOAuth2ProviderAppCodeByPrefix(ctx, secretPrefix)
+	m.queryLatencies.WithLabelValues("GetOAuth2ProviderAppCodeByPrefix").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderAppSecret, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOAuth2ProviderAppSecretByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetOAuth2ProviderAppSecretByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetOAuth2ProviderAppSecretsByAppID(ctx context.Context, appID uuid.UUID) ([]database.OAuth2ProviderAppSecret, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOAuth2ProviderAppSecretsByAppID(ctx, appID)
+	m.queryLatencies.WithLabelValues("GetOAuth2ProviderAppSecretsByAppID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetOAuth2ProviderAppTokenByPrefix(ctx context.Context, hashPrefix string) (database.OAuth2ProviderAppToken, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOAuth2ProviderAppTokenByPrefix(ctx, hashPrefix)
+	m.queryLatencies.WithLabelValues("GetOAuth2ProviderAppTokenByPrefix").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetOAuth2ProviderApps(ctx context.Context) ([]database.OAuth2ProviderApp, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOAuth2ProviderApps(ctx)
+	m.queryLatencies.WithLabelValues("GetOAuth2ProviderApps").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetOAuthSigningKey(ctx context.Context) (string, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetOAuthSigningKey(ctx)
@@ -545,6 +657,13 @@ func (m metricsStore) GetProvisionerJobsCreatedAfter(ctx context.Context, create
 	return jobs, err
 }
 
+func (m metricsStore) GetProvisionerJobsRunningByWorkerIDs(ctx context.Context, workerIDs []uuid.UUID) ([]database.ProvisionerJob, error) {
+	start := time.Now()
+	jobs, err := m.s.GetProvisionerJobsRunningByWorkerIDs(ctx, workerIDs)
+	m.queryLatencies.WithLabelValues("GetProvisionerJobsRunningByWorkerIDs").Observe(time.Since(start).Seconds())
+	return jobs, err
+}
+
 func (m metricsStore) GetProvisionerLogsAfterID(ctx context.Context, arg database.GetProvisionerLogsAfterIDParams) ([]database.ProvisionerJobLog, error) {
 	start := time.Now()
 	logs, err := m.s.GetProvisionerLogsAfterID(ctx, arg)
@@ -760,9 +879,9 @@ func (m metricsStore) GetUserLatencyInsights(ctx context.Context, arg database.G
 	return r0, r1
 }
 
-func (m metricsStore) GetUserLinkByLinkedID(ctx context.Context, linkedID string) (database.UserLink, error) {
+func (m metricsStore) GetUserLinkByLinkedID(ctx context.Context, arg database.GetUserLinkByLinkedIDParams) (database.UserLink, error) {
 	start := time.Now()
-	link, err := m.s.GetUserLinkByLinkedID(ctx, linkedID)
+	link, err := m.s.GetUserLinkByLinkedID(ctx, arg)
 	m.queryLatencies.WithLabelValues("GetUserLinkByLinkedID").Observe(time.Since(start).Seconds())
 	return link, err
 }
@@ -774,6 +893,34 @@ func (m metricsStore) GetUserLinkByUserIDLoginType(ctx context.Context, arg data
 	return link, err
 }
 
+func (m metricsStore) GetUserLinksByLoginType(ctx context.Context, loginType database.LoginType) ([]database.UserLink, error) {
+	start := time.Now()
+	links, err := m.s.GetUserLinksByLoginType(ctx, loginType)
+	m.queryLatencies.WithLabelValues("GetUserLinksByLoginType").Observe(time.Since(start).Seconds())
+	return links, err
+}
+
+func (m metricsStore) GetUserLinksByUserID(ctx context.Context, userID uuid.UUID) ([]database.UserLink, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUserLinksByUserID(ctx, userID)
+	m.queryLatencies.WithLabelValues("GetUserLinksByUserID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetUserMFAByUserID(ctx context.Context, userID uuid.UUID) (database.UserMFA, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUserMFAByUserID(ctx, userID)
+	m.queryLatencies.WithLabelValues("GetUserMFAByUserID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetUserMFARecoveryCodesByUserID(ctx context.Context, userID uuid.UUID) ([]database.UserMFARecoveryCode, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUserMFARecoveryCodesByUserID(ctx, userID)
+	m.queryLatencies.WithLabelValues("GetUserMFARecoveryCodesByUserID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetUsers(ctx context.Context, arg database.GetUsersParams) ([]database.GetUsersRow, error) {
 	start := time.Now()
 	users, err := m.s.GetUsers(ctx, arg)
@@ -1131,6 +1278,34 @@ func (m metricsStore) InsertMissingGroups(ctx context.Context, arg database.Inse
 	return r0, r1
 }
 
+func (m metricsStore) InsertOAuth2ProviderApp(ctx context.Context, arg database.InsertOAuth2ProviderAppParams) (database.OAuth2ProviderApp, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertOAuth2ProviderApp(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertOAuth2ProviderApp").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) InsertOAuth2ProviderAppCode(ctx context.Context, arg database.InsertOAuth2ProviderAppCodeParams) (database.OAuth2ProviderAppCode, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertOAuth2ProviderAppCode(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertOAuth2ProviderAppCode").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) InsertOAuth2ProviderAppSecret(ctx context.Context, arg database.InsertOAuth2ProviderAppSecretParams) (database.OAuth2ProviderAppSecret, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertOAuth2ProviderAppSecret(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertOAuth2ProviderAppSecret").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) InsertOAuth2ProviderAppToken(ctx context.Context, arg database.InsertOAuth2ProviderAppTokenParams) (database.OAuth2ProviderAppToken, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertOAuth2ProviderAppToken(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertOAuth2ProviderAppToken").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) InsertOrganization(ctx context.Context, arg database.InsertOrganizationParams) (database.Organization, error) {
 	start := time.Now()
 	organization, err := m.s.InsertOrganization(ctx, arg)
@@ -1222,6 +1397,13 @@ func (m metricsStore) InsertUserLink(ctx context.Context, arg database.InsertUse
 	return link, err
 }
 
+func (m metricsStore) InsertUserMFARecoveryCode(ctx context.Context, arg database.InsertUserMFARecoveryCodeParams) (database.UserMFARecoveryCode, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertUserMFARecoveryCode(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertUserMFARecoveryCode").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) InsertWorkspace(ctx context.Context, arg database.InsertWorkspaceParams) (database.Workspace, error) {
 	start := time.Now()
 	workspace, err := m.s.InsertWorkspace(ctx, arg)
@@ -1313,6 +1495,13 @@ func (m metricsStore) InsertWorkspaceResourceMetadata(ctx context.Context, arg d
 	return metadata, err
 }
 
+func (m metricsStore) RecordUserMFAFailedAttempt(ctx context.Context, arg database.RecordUserMFAFailedAttemptParams) (database.UserMFA, error) {
+	start := time.Now()
+	mfa, err := m.s.RecordUserMFAFailedAttempt(ctx, arg)
+	m.queryLatencies.WithLabelValues("RecordUserMFAFailedAttempt").Observe(time.Since(start).Seconds())
+	return mfa, err
+}
+
 func (m metricsStore) RegisterWorkspaceProxy(ctx context.Context, arg database.RegisterWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	start := time.Now()
 	proxy, err := m.s.RegisterWorkspaceProxy(ctx, arg)
@@ -1320,6 +1509,13 @@ func (m metricsStore) RegisterWorkspaceProxy(ctx context.Context, arg database.R
 	return proxy, err
 }
 
+func (m metricsStore) ResetUserMFAFailedAttempts(ctx context.Context, userID uuid.UUID) error {
+	start := time.Now()
+	err := m.s.ResetUserMFAFailedAttempts(ctx, userID)
+	m.queryLatencies.WithLabelValues("ResetUserMFAFailedAttempts").Observe(time.Since(start).Seconds())
+	return err
+}
+
 func (m metricsStore) TryAcquireLock(ctx context.Context, pgTryAdvisoryXactLock int64) (bool, error) {
 	start := time.Now()
 	ok, err := m.s.TryAcquireLock(ctx, pgTryAdvisoryXactLock)
@@ -1369,6 +1565,27 @@ func (m metricsStore) UpdateMemberRoles(ctx context.Context, arg database.Update
 	return member, err
 }
 
+func (m metricsStore) UpdateOAuth2ProviderAppByID(ctx context.Context, arg database.UpdateOAuth2ProviderAppByIDParams) (database.OAuth2ProviderApp, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateOAuth2ProviderAppByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateOAuth2ProviderAppByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) UpdateOAuth2ProviderAppSecretByID(ctx context.Context, arg database.UpdateOAuth2ProviderAppSecretByIDParams) (database.OAuth2ProviderAppSecret, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateOAuth2ProviderAppSecretByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateOAuth2ProviderAppSecretByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) UpdateProvisionerDaemonLastSeenAt(ctx context.Context, arg database.UpdateProvisionerDaemonLastSeenAtParams) error {
+	start := time.Now()
+	err := m.s.UpdateProvisionerDaemonLastSeenAt(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateProvisionerDaemonLastSeenAt").Observe(time.Since(start).Seconds())
+	return err
+}
+
 func (m metricsStore) UpdateProvisionerJobByID(ctx context.Context, arg database.UpdateProvisionerJobByIDParams) error {
 	start := time.Now()
 	err := m.s.UpdateProvisionerJobByID(ctx, arg)
@@ -1502,6 +1719,13 @@ func (m metricsStore) UpdateUserLoginType(ctx context.Context, arg database.Upda
 	return r0, r1
 }
 
+func (m metricsStore) UpdateUserMFALastUsedStep(ctx context.Context, arg database.UpdateUserMFALastUsedStepParams) (database.UserMFA, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateUserMFALastUsedStep(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateUserMFALastUsedStep").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) UpdateUserProfile(ctx context.Context, arg database.UpdateUserProfileParams) (database.User, error) {
 	start := time.Now()
 	user, err := m.s.UpdateUserProfile(ctx, arg)
@@ -1709,6 +1933,20 @@ func (m metricsStore) UpsertTailnetCoordinator(ctx context.Context, id uuid.UUID
 	return m.s.UpsertTailnetCoordinator(ctx, id)
 }
 
+func (m metricsStore) UpsertUserMFA(ctx context.Context, arg database.UpsertUserMFAParams) (database.UserMFA, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertUserMFA(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertUserMFA").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) UseUserMFARecoveryCode(ctx context.Context, arg database.UseUserMFARecoveryCodeParams) (database.UserMFARecoveryCode, error) {
+	start := time.Now()
+	r0, r1 := m.s.UseUserMFARecoveryCode(ctx, arg)
+	m.queryLatencies.WithLabelValues("UseUserMFARecoveryCode").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetAuthorizedTemplates(ctx context.Context, arg database.GetTemplatesWithFilterParams, prepared rbac.PreparedAuthorized) ([]database.Template, error) {
 	start := time.Now()
 	templates, err := m.s.GetAuthorizedTemplates(ctx, arg, prepared)
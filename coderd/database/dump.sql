@@ -62,7 +62,10 @@ CREATE TYPE login_type AS ENUM (
     'github',
     'oidc',
     'token',
-    'none'
+    'none',
+    'oauth2_provider_app',
+    'service_account',
+    'ldap'
 );
 
 COMMENT ON TYPE login_type IS 'Specifies the method of authentication. "none" is a special case in which no authentication method is allowed.';
@@ -116,7 +119,10 @@ CREATE TYPE resource_type AS ENUM (
     'workspace_build',
     'license',
     'workspace_proxy',
-    'convert_login'
+    'convert_login',
+    'oauth2_provider_app',
+    'oauth2_provider_app_secret',
+    'user_mfa'
 );
 
 CREATE TYPE startup_script_behavior AS ENUM (
@@ -150,7 +156,8 @@ CREATE TYPE workspace_agent_log_source AS ENUM (
     'kubernetes_logs',
     'envbox',
     'envbuilder',
-    'external'
+    'external',
+    'egress_policy'
 );
 
 CREATE TYPE workspace_agent_subsystem AS ENUM (
@@ -173,6 +180,17 @@ CREATE TYPE workspace_transition AS ENUM (
     'delete'
 );
 
+CREATE FUNCTION delete_deleted_oauth2_provider_app_token_api_key() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+DECLARE
+BEGIN
+	DELETE FROM api_keys
+	WHERE id = OLD.api_key_id;
+	RETURN OLD;
+END;
+$$;
+
 CREATE FUNCTION delete_deleted_user_api_keys() RETURNS trigger
     LANGUAGE plpgsql
     AS $$
@@ -252,11 +270,20 @@ CREATE TABLE api_keys (
     lifetime_seconds bigint DEFAULT 86400 NOT NULL,
     ip_address inet DEFAULT '0.0.0.0'::inet NOT NULL,
     scope api_key_scope DEFAULT 'all'::api_key_scope NOT NULL,
-    token_name text DEFAULT ''::text NOT NULL
+    token_name text DEFAULT ''::text NOT NULL,
+    scopes text[] DEFAULT '{}'::text[] NOT NULL,
+    allow_list text[] DEFAULT '{}'::text[] NOT NULL,
+    provider_id text DEFAULT ''::text NOT NULL
 );
 
 COMMENT ON COLUMN api_keys.hashed_secret IS 'hashed_secret contains a SHA256 hash of the key secret. This is considered a secret and MUST NOT be returned from the API as it is used for API key encryption in app proxying code.';
 
+COMMENT ON COLUMN api_keys.scopes IS 'Fine-grained scopes that replace scope when not empty. Values are rbac scope names.';
+
+COMMENT ON COLUMN api_keys.allow_list IS 'IDs of the only resources the key can act on, in addition to the key owner. Empty allows all resources.';
+
+COMMENT ON COLUMN api_keys.provider_id IS 'The ID of the additional OIDC provider the key was created with. Used to refresh the OAuth token of the matching user link.';
+
 CREATE TABLE audit_logs (
     id uuid NOT NULL,
     "time" timestamp with time zone NOT NULL,
@@ -299,9 +326,18 @@ CREATE TABLE gitsshkeys (
     created_at timestamp with time zone NOT NULL,
     updated_at timestamp with time zone NOT NULL,
     private_key text NOT NULL,
-    public_key text NOT NULL
+    public_key text NOT NULL,
+    name text NOT NULL,
+    imported boolean DEFAULT false NOT NULL,
+    hosts text[] DEFAULT '{}'::text[] NOT NULL
 );
 
+COMMENT ON COLUMN gitsshkeys.name IS 'The name of the key, which is unique per user. The key named "default" is generated when the user is created.';
+
+COMMENT ON COLUMN gitsshkeys.imported IS 'Whether the private key was imported by the user instead of generated by Coder.';
+
+COMMENT ON COLUMN gitsshkeys.hosts IS 'Patterns of the hosts the key is offered to, such as "*.github.com". The key is offered to every host if it is empty.';
+
 CREATE TABLE group_members (
     user_id uuid NOT NULL,
     group_id uuid NOT NULL
@@ -341,6 +377,57 @@ CREATE SEQUENCE licenses_id_seq
 
 ALTER SEQUENCE licenses_id_seq OWNED BY licenses.id;
 
+CREATE TABLE oauth2_provider_app_codes (
+    id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    expires_at timestamp with time zone NOT NULL,
+    secret_prefix text NOT NULL,
+    hashed_secret bytea NOT NULL,
+    user_id uuid NOT NULL,
+    app_id uuid NOT NULL,
+    redirect_uri text NOT NULL,
+    code_challenge text NOT NULL
+);
+
+COMMENT ON TABLE oauth2_provider_app_codes IS 'Codes are meant to be exchanged for access tokens.';
+
+COMMENT ON COLUMN oauth2_provider_app_codes.code_challenge IS 'The S256 PKCE challenge sent with the authorization request. PKCE is required, so every code has one.';
+
+CREATE TABLE oauth2_provider_app_secrets (
+    id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    last_used_at timestamp with time zone,
+    hashed_secret bytea NOT NULL,
+    display_secret text NOT NULL,
+    app_id uuid NOT NULL
+);
+
+COMMENT ON COLUMN oauth2_provider_app_secrets.display_secret IS 'The tail end of the original secret so secrets can be differentiated.';
+
+CREATE TABLE oauth2_provider_app_tokens (
+    id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    expires_at timestamp with time zone NOT NULL,
+    hash_prefix text NOT NULL,
+    refresh_hash bytea NOT NULL,
+    app_secret_id uuid NOT NULL,
+    api_key_id text NOT NULL,
+    user_id uuid NOT NULL
+);
+
+COMMENT ON COLUMN oauth2_provider_app_tokens.refresh_hash IS 'Refresh tokens provide a way to refresh an access token (API key). An expired API key can be refreshed if this token is not yet expired, meaning this expiry can outlive an API key.';
+
+CREATE TABLE oauth2_provider_apps (
+    id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    name character varying(64) NOT NULL,
+    icon character varying(256) NOT NULL,
+    callback_url text NOT NULL
+);
+
+COMMENT ON TABLE oauth2_provider_apps IS 'A table used to configure apps that can use Coder as an OAuth2 provider, the reverse of what we are calling external authentication.';
+
 CREATE TABLE organization_members (
     user_id uuid NOT NULL,
     organization_id uuid NOT NULL,
@@ -396,9 +483,18 @@ CREATE TABLE provisioner_daemons (
     name character varying(64) NOT NULL,
     provisioners provisioner_type[] NOT NULL,
     replica_id uuid,
-    tags jsonb DEFAULT '{}'::jsonb NOT NULL
+    tags jsonb DEFAULT '{}'::jsonb NOT NULL,
+    last_seen_at timestamp with time zone,
+    version text DEFAULT ''::text NOT NULL,
+    provisioner_versions jsonb DEFAULT '{}'::jsonb NOT NULL
 );
 
+COMMENT ON COLUMN provisioner_daemons.last_seen_at IS 'The last time the daemon sent a heartbeat to coderd.';
+
+COMMENT ON COLUMN provisioner_daemons.version IS 'The Coder version of the daemon, as reported in its last heartbeat.';
+
+COMMENT ON COLUMN provisioner_daemons.provisioner_versions IS 'Maps each provisioner type served by the daemon to the version of its backing tool, e.g. the Terraform version.';
+
 CREATE TABLE provisioner_job_logs (
     job_id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -499,6 +595,8 @@ CREATE TABLE template_version_parameters (
     display_name text DEFAULT ''::text NOT NULL,
     display_order integer DEFAULT 0 NOT NULL,
     ephemeral boolean DEFAULT false NOT NULL,
+    visible_if text DEFAULT ''::text NOT NULL,
+    validation_rules jsonb DEFAULT '[]'::jsonb NOT NULL,
     CONSTRAINT validation_monotonic_order CHECK ((validation_monotonic = ANY (ARRAY['increasing'::text, 'decreasing'::text, ''::text])))
 );
 
@@ -534,6 +632,10 @@ COMMENT ON COLUMN template_version_parameters.display_order IS 'Specifies the or
 
 COMMENT ON COLUMN template_version_parameters.ephemeral IS 'The value of an ephemeral parameter will not be preserved between consecutive workspace builds.';
 
+COMMENT ON COLUMN template_version_parameters.visible_if IS 'Expression that controls whether the parameter is shown, based on the values of other parameters.';
+
+COMMENT ON COLUMN template_version_parameters.validation_rules IS 'Validation: expressions evaluated against the value and the values of other parameters.';
+
 CREATE TABLE template_version_variables (
     template_version_id uuid NOT NULL,
     name text NOT NULL,
@@ -590,11 +692,14 @@ CREATE TABLE users (
     avatar_url text,
     deleted boolean DEFAULT false NOT NULL,
     last_seen_at timestamp without time zone DEFAULT '0001-01-01 00:00:00'::timestamp without time zone NOT NULL,
-    quiet_hours_schedule text DEFAULT ''::text NOT NULL
+    quiet_hours_schedule text DEFAULT ''::text NOT NULL,
+    service_account_owner_group_id uuid
 );
 
 COMMENT ON COLUMN users.quiet_hours_schedule IS 'Daily (!) cron schedule (with optional CRON_TZ) signifying the start of the user''s quiet hours. If empty, the default quiet hours on the instance is used instead.';
 
+COMMENT ON COLUMN users.service_account_owner_group_id IS 'Members of this group can manage the service account and issue tokens for it. Only set for users with the service_account login type.';
+
 CREATE VIEW visible_users AS
  SELECT users.id,
     users.username,
@@ -646,7 +751,8 @@ CREATE TABLE templates (
     time_til_dormant bigint DEFAULT 0 NOT NULL,
     time_til_dormant_autodelete bigint DEFAULT 0 NOT NULL,
     autostop_requirement_days_of_week smallint DEFAULT 0 NOT NULL,
-    autostop_requirement_weeks bigint DEFAULT 0 NOT NULL
+    autostop_requirement_weeks bigint DEFAULT 0 NOT NULL,
+    egress_policy jsonb DEFAULT '{}'::jsonb NOT NULL
 );
 
 COMMENT ON COLUMN templates.default_ttl IS 'The default duration for autostop for workspaces created from this template.';
@@ -663,6 +769,8 @@ COMMENT ON COLUMN templates.autostop_requirement_days_of_week IS 'A bitmap of da
 
 COMMENT ON COLUMN templates.autostop_requirement_weeks IS 'The number of weeks between restarts. 0 or 1 weeks means "every week", 2 week means "every second week", etc. Weeks are counted from January 2, 2023, which is the first Monday of 2023. This is to ensure workspaces are started consistently for all customers on the same n-week cycles.';
 
+COMMENT ON COLUMN templates.egress_policy IS 'Allow and deny rules for the destinations that workspace agents forward connections to.';
+
 CREATE VIEW template_with_users AS
  SELECT templates.id,
     templates.created_at,
@@ -688,6 +796,7 @@ CREATE VIEW template_with_users AS
     templates.time_til_dormant_autodelete,
     templates.autostop_requirement_days_of_week,
     templates.autostop_requirement_weeks,
+    templates.egress_policy,
     COALESCE(visible_users.avatar_url, ''::text) AS created_by_avatar_url,
     COALESCE(visible_users.username, ''::text) AS created_by_username
    FROM (public.templates
@@ -701,9 +810,41 @@ CREATE TABLE user_links (
     linked_id text DEFAULT ''::text NOT NULL,
     oauth_access_token text DEFAULT ''::text NOT NULL,
     oauth_refresh_token text DEFAULT ''::text NOT NULL,
-    oauth_expiry timestamp with time zone DEFAULT '0001-01-01 00:00:00+00'::timestamp with time zone NOT NULL
+    oauth_expiry timestamp with time zone DEFAULT '0001-01-01 00:00:00+00'::timestamp with time zone NOT NULL,
+    provider_id text DEFAULT ''::text NOT NULL
 );
 
+COMMENT ON COLUMN user_links.provider_id IS 'The ID of the additional OIDC provider the link belongs to. Empty for the default OIDC provider and other login types.';
+
+CREATE TABLE user_mfa (
+    user_id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    totp_secret text NOT NULL,
+    enabled_at timestamp with time zone,
+    totp_last_used_step bigint DEFAULT 0 NOT NULL,
+    failed_attempts integer DEFAULT 0 NOT NULL,
+    last_failed_at timestamp with time zone
+);
+
+COMMENT ON TABLE user_mfa IS 'Multi-factor authentication enrollments of password users.';
+
+COMMENT ON COLUMN user_mfa.enabled_at IS 'Null while the enrollment is pending, until the user confirms it with a code from their authenticator.';
+
+COMMENT ON COLUMN user_mfa.totp_last_used_step IS 'The time step of the last accepted code, so codes cannot be replayed.';
+
+COMMENT ON COLUMN user_mfa.failed_attempts IS 'The number of invalid codes entered since the last valid one. Codes are rejected for a while once it reaches a limit.';
+
+CREATE TABLE user_mfa_recovery_codes (
+    id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    used_at timestamp with time zone,
+    hashed_code bytea NOT NULL
+);
+
+COMMENT ON TABLE user_mfa_recovery_codes IS 'Single use codes that replace a code from the authenticator of the user.';
+
 CREATE TABLE workspace_agent_logs (
     agent_id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -1047,7 +1188,7 @@ ALTER TABLE ONLY git_auth_links
     ADD CONSTRAINT git_auth_links_provider_id_user_id_key UNIQUE (provider_id, user_id);
 
 ALTER TABLE ONLY gitsshkeys
-    ADD CONSTRAINT gitsshkeys_pkey PRIMARY KEY (user_id);
+    ADD CONSTRAINT gitsshkeys_pkey PRIMARY KEY (user_id, name);
 
 ALTER TABLE ONLY group_members
     ADD CONSTRAINT group_members_user_id_group_id_key UNIQUE (user_id, group_id);
@@ -1064,6 +1205,30 @@ ALTER TABLE ONLY licenses
 ALTER TABLE ONLY licenses
     ADD CONSTRAINT licenses_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY oauth2_provider_app_codes
+    ADD CONSTRAINT oauth2_provider_app_codes_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY oauth2_provider_app_codes
+    ADD CONSTRAINT oauth2_provider_app_codes_secret_prefix_key UNIQUE (secret_prefix);
+
+ALTER TABLE ONLY oauth2_provider_app_secrets
+    ADD CONSTRAINT oauth2_provider_app_secrets_app_id_hashed_secret_key UNIQUE (app_id, hashed_secret);
+
+ALTER TABLE ONLY oauth2_provider_app_secrets
+    ADD CONSTRAINT oauth2_provider_app_secrets_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY oauth2_provider_app_tokens
+    ADD CONSTRAINT oauth2_provider_app_tokens_hash_prefix_key UNIQUE (hash_prefix);
+
+ALTER TABLE ONLY oauth2_provider_app_tokens
+    ADD CONSTRAINT oauth2_provider_app_tokens_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY oauth2_provider_apps
+    ADD CONSTRAINT oauth2_provider_apps_name_key UNIQUE (name);
+
+ALTER TABLE ONLY oauth2_provider_apps
+    ADD CONSTRAINT oauth2_provider_apps_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY organization_members
     ADD CONSTRAINT organization_members_pkey PRIMARY KEY (organization_id, user_id);
 
@@ -1101,7 +1266,7 @@ ALTER TABLE ONLY tailnet_agents
     ADD CONSTRAINT tailnet_agents_pkey PRIMARY KEY (id, coordinator_id);
 
 ALTER TABLE ONLY tailnet_clients
-    ADD CONSTRAINT tailnet_clients_pkey PRIMARY KEY (id, coordinator_id);
+    ADD CONSTRAINT tailnet_clients_pkey PRIMARY KEY (id, coordinator_id, agent_id);
 
 ALTER TABLE ONLY tailnet_coordinators
     ADD CONSTRAINT tailnet_coordinators_pkey PRIMARY KEY (id);
@@ -1122,7 +1287,16 @@ ALTER TABLE ONLY templates
     ADD CONSTRAINT templates_pkey PRIMARY KEY (id);
 
 ALTER TABLE ONLY user_links
-    ADD CONSTRAINT user_links_pkey PRIMARY KEY (user_id, login_type);
+    ADD CONSTRAINT user_links_pkey PRIMARY KEY (user_id, login_type, provider_id);
+
+ALTER TABLE ONLY user_mfa
+    ADD CONSTRAINT user_mfa_pkey PRIMARY KEY (user_id);
+
+ALTER TABLE ONLY user_mfa_recovery_codes
+    ADD CONSTRAINT user_mfa_recovery_codes_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY user_mfa_recovery_codes
+    ADD CONSTRAINT user_mfa_recovery_codes_user_id_hashed_code_key UNIQUE (user_id, hashed_code);
 
 ALTER TABLE ONLY users
     ADD CONSTRAINT users_pkey PRIMARY KEY (id);
@@ -1242,6 +1416,8 @@ CREATE TRIGGER tailnet_notify_client_change AFTER INSERT OR DELETE OR UPDATE ON
 
 CREATE TRIGGER tailnet_notify_coordinator_heartbeat AFTER INSERT OR UPDATE ON tailnet_coordinators FOR EACH ROW EXECUTE FUNCTION tailnet_notify_coordinator_heartbeat();
 
+CREATE TRIGGER trigger_delete_oauth2_provider_app_token AFTER DELETE ON oauth2_provider_app_tokens FOR EACH ROW EXECUTE FUNCTION delete_deleted_oauth2_provider_app_token_api_key();
+
 CREATE TRIGGER trigger_insert_apikeys BEFORE INSERT ON api_keys FOR EACH ROW EXECUTE FUNCTION insert_apikey_fail_if_user_deleted();
 
 CREATE TRIGGER trigger_update_users AFTER INSERT OR UPDATE ON users FOR EACH ROW WHEN ((new.deleted = true)) EXECUTE FUNCTION delete_deleted_user_api_keys();
@@ -1261,6 +1437,24 @@ ALTER TABLE ONLY group_members
 ALTER TABLE ONLY groups
     ADD CONSTRAINT groups_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY oauth2_provider_app_codes
+    ADD CONSTRAINT oauth2_provider_app_codes_app_id_fkey FOREIGN KEY (app_id) REFERENCES oauth2_provider_apps(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY oauth2_provider_app_codes
+    ADD CONSTRAINT oauth2_provider_app_codes_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY oauth2_provider_app_secrets
+    ADD CONSTRAINT oauth2_provider_app_secrets_app_id_fkey FOREIGN KEY (app_id) REFERENCES oauth2_provider_apps(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY oauth2_provider_app_tokens
+    ADD CONSTRAINT oauth2_provider_app_tokens_api_key_id_fkey FOREIGN KEY (api_key_id) REFERENCES api_keys(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY oauth2_provider_app_tokens
+    ADD CONSTRAINT oauth2_provider_app_tokens_app_secret_id_fkey FOREIGN KEY (app_secret_id) REFERENCES oauth2_provider_app_secrets(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY oauth2_provider_app_tokens
+    ADD CONSTRAINT oauth2_provider_app_tokens_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY organization_members
     ADD CONSTRAINT organization_members_organization_id_uuid_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
 
@@ -1306,6 +1500,15 @@ ALTER TABLE ONLY templates
 ALTER TABLE ONLY user_links
     ADD CONSTRAINT user_links_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY user_mfa
+    ADD CONSTRAINT user_mfa_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY user_mfa_recovery_codes
+    ADD CONSTRAINT user_mfa_recovery_codes_user_id_fkey FOREIGN KEY (user_id) REFERENCES user_mfa(user_id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY users
+    ADD CONSTRAINT users_service_account_owner_group_id_fkey FOREIGN KEY (service_account_owner_group_id) REFERENCES groups(id) ON DELETE SET NULL;
+
 ALTER TABLE ONLY workspace_agent_metadata
     ADD CONSTRAINT workspace_agent_metadata_workspace_agent_id_fkey FOREIGN KEY (workspace_agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 
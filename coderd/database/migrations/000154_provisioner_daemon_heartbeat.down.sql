@@ -0,0 +1,6 @@
+BEGIN;
+ALTER TABLE provisioner_daemons
+	DROP COLUMN last_seen_at,
+	DROP COLUMN version,
+	DROP COLUMN provisioner_versions;
+COMMIT;
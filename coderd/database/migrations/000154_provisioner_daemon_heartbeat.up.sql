@@ -0,0 +1,10 @@
+BEGIN;
+ALTER TABLE provisioner_daemons
+	ADD COLUMN last_seen_at timestamp with time zone NULL,
+	ADD COLUMN version text NOT NULL DEFAULT '',
+	ADD COLUMN provisioner_versions jsonb NOT NULL DEFAULT '{}'::jsonb;
+
+COMMENT ON COLUMN provisioner_daemons.last_seen_at IS 'The last time the daemon sent a heartbeat to coderd.';
+COMMENT ON COLUMN provisioner_daemons.version IS 'The Coder version of the daemon, as reported in its last heartbeat.';
+COMMENT ON COLUMN provisioner_daemons.provisioner_versions IS 'Maps each provisioner type served by the daemon to the version of its backing tool, e.g. the Terraform version.';
+COMMIT;
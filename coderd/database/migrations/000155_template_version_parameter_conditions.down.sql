@@ -0,0 +1,5 @@
+BEGIN;
+ALTER TABLE template_version_parameters
+	DROP COLUMN visible_if,
+	DROP COLUMN validation_rules;
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+ALTER TABLE template_version_parameters
+	ADD COLUMN visible_if text NOT NULL DEFAULT '',
+	ADD COLUMN validation_rules jsonb NOT NULL DEFAULT '[]'::jsonb;
+
+COMMENT ON COLUMN template_version_parameters.visible_if IS 'Expression that controls whether the parameter is shown, based on the values of other parameters.';
+COMMENT ON COLUMN template_version_parameters.validation_rules IS 'Validation: expressions evaluated against the value and the values of other parameters.';
+COMMIT;
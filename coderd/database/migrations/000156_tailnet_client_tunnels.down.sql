@@ -0,0 +1,14 @@
+BEGIN;
+
+-- Keep only the most recent tunnel of each client, since the old primary key
+-- only allows one.
+DELETE FROM tailnet_clients a
+USING tailnet_clients b
+WHERE a.id = b.id
+	AND a.coordinator_id = b.coordinator_id
+	AND (a.updated_at, a.agent_id) < (b.updated_at, b.agent_id);
+
+ALTER TABLE tailnet_clients DROP CONSTRAINT tailnet_clients_pkey;
+ALTER TABLE tailnet_clients ADD PRIMARY KEY (id, coordinator_id);
+
+COMMIT;
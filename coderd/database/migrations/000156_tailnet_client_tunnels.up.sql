@@ -0,0 +1,8 @@
+BEGIN;
+
+-- Clients using the v2 coordinator protocol may open tunnels to several agents
+-- through the same coordinator, so each tunnel gets its own row.
+ALTER TABLE tailnet_clients DROP CONSTRAINT tailnet_clients_pkey;
+ALTER TABLE tailnet_clients ADD PRIMARY KEY (id, coordinator_id, agent_id);
+
+COMMIT;
@@ -0,0 +1,27 @@
+BEGIN;
+
+ALTER TYPE workspace_agent_log_source ADD VALUE 'egress_policy';
+
+DROP VIEW template_with_users;
+
+ALTER TABLE templates ADD COLUMN egress_policy jsonb DEFAULT '{}'::jsonb NOT NULL;
+
+COMMENT ON COLUMN templates.egress_policy IS 'Allow and deny rules for the destinations that workspace agents forward connections to.';
+
+CREATE VIEW
+    template_with_users
+AS
+    SELECT
+        templates.*,
+		coalesce(visible_users.avatar_url, '') AS created_by_avatar_url,
+		coalesce(visible_users.username, '') AS created_by_username
+    FROM
+        templates
+    LEFT JOIN
+		visible_users
+	ON
+	    templates.created_by = visible_users.id;
+
+COMMENT ON VIEW template_with_users IS 'Joins in the username + avatar url of the created by user.';
+
+COMMIT;
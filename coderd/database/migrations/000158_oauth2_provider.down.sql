@@ -0,0 +1,15 @@
+-- It's not possible to delete enum values.
+BEGIN;
+
+DROP TRIGGER IF EXISTS trigger_delete_oauth2_provider_app_token ON oauth2_provider_app_tokens;
+DROP FUNCTION IF EXISTS delete_deleted_oauth2_provider_app_token_api_key;
+
+DROP TABLE oauth2_provider_app_tokens;
+DROP TABLE oauth2_provider_app_codes;
+DROP TABLE oauth2_provider_app_secrets;
+DROP TABLE oauth2_provider_apps;
+
+-- Keys issued to apps can no longer be refreshed or revoked through the app.
+DELETE FROM api_keys WHERE login_type = 'oauth2_provider_app';
+
+COMMIT;
@@ -0,0 +1,83 @@
+BEGIN;
+
+ALTER TYPE login_type ADD VALUE 'oauth2_provider_app';
+ALTER TYPE resource_type ADD VALUE 'oauth2_provider_app';
+ALTER TYPE resource_type ADD VALUE 'oauth2_provider_app_secret';
+
+CREATE TABLE oauth2_provider_apps (
+	id uuid NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL,
+	name varchar(64) NOT NULL,
+	icon varchar(256) NOT NULL,
+	callback_url text NOT NULL,
+	PRIMARY KEY (id),
+	UNIQUE(name)
+);
+
+COMMENT ON TABLE oauth2_provider_apps IS 'A table used to configure apps that can use Coder as an OAuth2 provider, the reverse of what we are calling external authentication.';
+
+CREATE TABLE oauth2_provider_app_secrets (
+	id uuid NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	last_used_at timestamp with time zone NULL,
+	hashed_secret bytea NOT NULL,
+	display_secret text NOT NULL,
+	app_id uuid NOT NULL REFERENCES oauth2_provider_apps (id) ON DELETE CASCADE,
+	PRIMARY KEY (id),
+	UNIQUE(app_id, hashed_secret)
+);
+
+COMMENT ON COLUMN oauth2_provider_app_secrets.display_secret IS 'The tail end of the original secret so secrets can be differentiated.';
+
+CREATE TABLE oauth2_provider_app_codes (
+	id uuid NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	expires_at timestamp with time zone NOT NULL,
+	secret_prefix text NOT NULL,
+	hashed_secret bytea NOT NULL,
+	user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	app_id uuid NOT NULL REFERENCES oauth2_provider_apps (id) ON DELETE CASCADE,
+	redirect_uri text NOT NULL,
+	code_challenge text NOT NULL,
+	PRIMARY KEY (id),
+	UNIQUE(secret_prefix)
+);
+
+COMMENT ON TABLE oauth2_provider_app_codes IS 'Codes are meant to be exchanged for access tokens.';
+COMMENT ON COLUMN oauth2_provider_app_codes.code_challenge IS 'The S256 PKCE challenge sent with the authorization request. PKCE is required, so every code has one.';
+
+CREATE TABLE oauth2_provider_app_tokens (
+	id uuid NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	expires_at timestamp with time zone NOT NULL,
+	hash_prefix text NOT NULL,
+	refresh_hash bytea NOT NULL,
+	app_secret_id uuid NOT NULL REFERENCES oauth2_provider_app_secrets (id) ON DELETE CASCADE,
+	api_key_id text NOT NULL REFERENCES api_keys (id) ON DELETE CASCADE,
+	user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	PRIMARY KEY (id),
+	UNIQUE(hash_prefix)
+);
+
+COMMENT ON COLUMN oauth2_provider_app_tokens.refresh_hash IS 'Refresh tokens provide a way to refresh an access token (API key). An expired API key can be refreshed if this token is not yet expired, meaning this expiry can outlive an API key.';
+
+-- Deleting an app or one of its secrets cascades to the tokens, but the API
+-- keys they were issued with must be revoked as well.
+CREATE FUNCTION delete_deleted_oauth2_provider_app_token_api_key() RETURNS trigger
+	LANGUAGE plpgsql
+	AS $$
+DECLARE
+BEGIN
+	DELETE FROM api_keys
+	WHERE id = OLD.api_key_id;
+	RETURN OLD;
+END;
+$$;
+
+CREATE TRIGGER trigger_delete_oauth2_provider_app_token
+AFTER DELETE ON oauth2_provider_app_tokens
+FOR EACH ROW
+EXECUTE PROCEDURE delete_deleted_oauth2_provider_app_token_api_key();
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE api_keys
+	DROP COLUMN scopes,
+	DROP COLUMN allow_list;
+
+COMMIT;
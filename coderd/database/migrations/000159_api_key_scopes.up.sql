@@ -0,0 +1,10 @@
+BEGIN;
+
+ALTER TABLE api_keys
+	ADD COLUMN scopes text[] NOT NULL DEFAULT '{}',
+	ADD COLUMN allow_list text[] NOT NULL DEFAULT '{}';
+
+COMMENT ON COLUMN api_keys.scopes IS 'Fine-grained scopes that replace scope when not empty. Values are rbac scope names.';
+COMMENT ON COLUMN api_keys.allow_list IS 'IDs of the only resources the key can act on, in addition to the key owner. Empty allows all resources.';
+
+COMMIT;
@@ -0,0 +1,6 @@
+-- It's not possible to delete enum values.
+BEGIN;
+
+ALTER TABLE users DROP COLUMN service_account_owner_group_id;
+
+COMMIT;
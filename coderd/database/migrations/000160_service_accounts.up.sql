@@ -0,0 +1,10 @@
+BEGIN;
+
+-- Service accounts cannot log in, tokens are issued for them instead.
+ALTER TYPE login_type ADD VALUE 'service_account';
+
+ALTER TABLE users ADD COLUMN service_account_owner_group_id uuid REFERENCES groups(id) ON DELETE SET NULL;
+
+COMMENT ON COLUMN users.service_account_owner_group_id IS 'Members of this group can manage the service account and issue tokens for it. Only set for users with the service_account login type.';
+
+COMMIT;
@@ -0,0 +1,12 @@
+BEGIN;
+
+ALTER TABLE api_keys DROP COLUMN provider_id;
+
+-- Links to additional providers cannot be represented without the column.
+DELETE FROM user_links WHERE provider_id != '';
+
+ALTER TABLE user_links DROP CONSTRAINT user_links_pkey;
+ALTER TABLE user_links DROP COLUMN provider_id;
+ALTER TABLE user_links ADD CONSTRAINT user_links_pkey PRIMARY KEY (user_id, login_type);
+
+COMMIT;
@@ -0,0 +1,16 @@
+BEGIN;
+
+-- Users can be linked to multiple OIDC providers at once, so the provider is
+-- part of the key of a link.
+ALTER TABLE user_links ADD COLUMN provider_id text NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN user_links.provider_id IS 'The ID of the additional OIDC provider the link belongs to. Empty for the default OIDC provider and other login types.';
+
+ALTER TABLE user_links DROP CONSTRAINT user_links_pkey;
+ALTER TABLE user_links ADD CONSTRAINT user_links_pkey PRIMARY KEY (user_id, login_type, provider_id);
+
+ALTER TABLE api_keys ADD COLUMN provider_id text NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN api_keys.provider_id IS 'The ID of the additional OIDC provider the key was created with. Used to refresh the OAuth token of the matching user link.';
+
+COMMIT;
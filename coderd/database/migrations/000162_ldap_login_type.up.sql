@@ -0,0 +1 @@
+ALTER TYPE login_type ADD VALUE 'ldap';
@@ -0,0 +1,7 @@
+-- It's not possible to delete enum values.
+BEGIN;
+
+DROP TABLE user_mfa_recovery_codes;
+DROP TABLE user_mfa;
+
+COMMIT;
@@ -0,0 +1,31 @@
+BEGIN;
+
+ALTER TYPE resource_type ADD VALUE 'user_mfa';
+
+CREATE TABLE user_mfa (
+	user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL,
+	totp_secret text NOT NULL,
+	enabled_at timestamp with time zone NULL,
+	totp_last_used_step bigint NOT NULL DEFAULT 0,
+	PRIMARY KEY (user_id)
+);
+
+COMMENT ON TABLE user_mfa IS 'Multi-factor authentication enrollments of password users.';
+COMMENT ON COLUMN user_mfa.enabled_at IS 'Null while the enrollment is pending, until the user confirms it with a code from their authenticator.';
+COMMENT ON COLUMN user_mfa.totp_last_used_step IS 'The time step of the last accepted code, so codes cannot be replayed.';
+
+CREATE TABLE user_mfa_recovery_codes (
+	id uuid NOT NULL,
+	user_id uuid NOT NULL REFERENCES user_mfa (user_id) ON DELETE CASCADE,
+	created_at timestamp with time zone NOT NULL,
+	used_at timestamp with time zone NULL,
+	hashed_code bytea NOT NULL,
+	PRIMARY KEY (id),
+	UNIQUE(user_id, hashed_code)
+);
+
+COMMENT ON TABLE user_mfa_recovery_codes IS 'Single use codes that replace a code from the authenticator of the user.';
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN;
+
+DELETE FROM gitsshkeys WHERE name != 'default';
+
+ALTER TABLE gitsshkeys DROP CONSTRAINT gitsshkeys_pkey;
+ALTER TABLE gitsshkeys DROP COLUMN name;
+ALTER TABLE gitsshkeys DROP COLUMN imported;
+ALTER TABLE gitsshkeys ADD CONSTRAINT gitsshkeys_pkey PRIMARY KEY (user_id);
+
+COMMIT;
@@ -0,0 +1,15 @@
+BEGIN;
+
+ALTER TABLE gitsshkeys DROP CONSTRAINT gitsshkeys_pkey;
+
+-- Existing keys become the default key of their user.
+ALTER TABLE gitsshkeys ADD COLUMN name text NOT NULL DEFAULT 'default';
+ALTER TABLE gitsshkeys ALTER COLUMN name DROP DEFAULT;
+ALTER TABLE gitsshkeys ADD COLUMN imported boolean NOT NULL DEFAULT false;
+
+ALTER TABLE gitsshkeys ADD CONSTRAINT gitsshkeys_pkey PRIMARY KEY (user_id, name);
+
+COMMENT ON COLUMN gitsshkeys.name IS 'The name of the key, which is unique per user. The key named "default" is generated when the user is created.';
+COMMENT ON COLUMN gitsshkeys.imported IS 'Whether the private key was imported by the user instead of generated by Coder.';
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE user_mfa
+	DROP COLUMN failed_attempts,
+	DROP COLUMN last_failed_at;
+
+COMMIT;
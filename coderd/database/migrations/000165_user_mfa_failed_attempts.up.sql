@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE user_mfa
+	ADD COLUMN failed_attempts integer NOT NULL DEFAULT 0,
+	ADD COLUMN last_failed_at timestamp with time zone NULL;
+
+COMMENT ON COLUMN user_mfa.failed_attempts IS 'The number of invalid codes entered since the last valid one. Codes are rejected for a while once it reaches a limit.';
+
+COMMIT;
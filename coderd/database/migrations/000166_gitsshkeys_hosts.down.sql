@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE gitsshkeys
+	DROP COLUMN hosts;
+
+COMMIT;
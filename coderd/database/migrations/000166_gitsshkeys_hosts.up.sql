@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE gitsshkeys
+	ADD COLUMN hosts text[] NOT NULL DEFAULT '{}'::text[];
+
+COMMENT ON COLUMN gitsshkeys.hosts IS 'Patterns of the hosts the key is offered to, such as "*.github.com". The key is offered to every host if it is empty.';
+
+COMMIT;
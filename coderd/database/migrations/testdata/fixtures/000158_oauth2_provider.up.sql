@@ -0,0 +1,52 @@
+INSERT INTO oauth2_provider_apps
+	(id, created_at, updated_at, name, icon, callback_url)
+VALUES
+	(
+		'b0a5b1e6-3c5e-4b8f-a1f3-4a7b3e0a6c2d',
+		'2023-11-01 12:00:00.000+02',
+		'2023-11-01 12:00:00.000+02',
+		'my-app',
+		'/icon/github.svg',
+		'http://localhost:3000'
+	);
+
+INSERT INTO oauth2_provider_app_secrets
+	(id, created_at, last_used_at, hashed_secret, display_secret, app_id)
+VALUES
+	(
+		'f2a2b6c1-8e2d-4a41-9a36-1c7d3f0e5b94',
+		'2023-11-01 12:00:00.000+02',
+		NULL,
+		'abc123'::bytea,
+		'*****abc123',
+		'b0a5b1e6-3c5e-4b8f-a1f3-4a7b3e0a6c2d'
+	);
+
+INSERT INTO oauth2_provider_app_codes
+	(id, created_at, expires_at, secret_prefix, hashed_secret, user_id, app_id, redirect_uri, code_challenge)
+VALUES
+	(
+		'3c9f8e2a-6d1b-4f7e-8a5c-2b4d6e8f0a1c',
+		'2023-11-01 12:00:00.000+02',
+		'2023-11-01 12:10:00.000+02',
+		'abcdefgh',
+		'abc123'::bytea,
+		'30095c71-380b-457a-8995-97b8ee6e5307',
+		'b0a5b1e6-3c5e-4b8f-a1f3-4a7b3e0a6c2d',
+		'http://localhost:3000',
+		'challenge'
+	);
+
+INSERT INTO oauth2_provider_app_tokens
+	(id, created_at, expires_at, hash_prefix, refresh_hash, app_secret_id, api_key_id, user_id)
+VALUES
+	(
+		'7e5d3b1a-9c8f-4e2d-b6a4-0f1e2d3c4b5a',
+		'2023-11-01 12:00:00.000+02',
+		'2023-12-01 12:00:00.000+02',
+		'abcdefgh',
+		'abc123'::bytea,
+		'f2a2b6c1-8e2d-4a41-9a36-1c7d3f0e5b94',
+		'WEG2T4MNno',
+		'30095c71-380b-457a-8995-97b8ee6e5307'
+	);
@@ -0,0 +1,22 @@
+INSERT INTO user_mfa
+	(user_id, created_at, updated_at, totp_secret, enabled_at, totp_last_used_step)
+VALUES
+	(
+		'30095c71-380b-457a-8995-97b8ee6e5307',
+		'2023-11-01 12:00:00.000+02',
+		'2023-11-01 12:05:00.000+02',
+		'JBSWY3DPEHPK3PXP',
+		'2023-11-01 12:05:00.000+02',
+		56627770
+	);
+
+INSERT INTO user_mfa_recovery_codes
+	(id, user_id, created_at, used_at, hashed_code)
+VALUES
+	(
+		'5a8e1c3f-2b7d-4e9a-8f6c-1d3b5e7a9c2e',
+		'30095c71-380b-457a-8995-97b8ee6e5307',
+		'2023-11-01 12:05:00.000+02',
+		NULL,
+		'abc123'::bytea
+	);
@@ -1,10 +1,14 @@
 package database
 
 import (
+	"bytes"
+	"fmt"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"golang.org/x/exp/maps"
 
 	"github.com/coder/coder/v2/coderd/database/dbtime"
@@ -98,6 +102,44 @@ func (s APIKeyScope) ToRBAC() rbac.ScopeName {
 	}
 }
 
+// RBACScope returns the scope the key acts with. Keys with fine-grained scopes
+// are limited to them and their allow list, otherwise the coarse scope is used.
+func (k APIKey) RBACScope() rbac.ExpandableScope {
+	if len(k.Scopes) == 0 && len(k.AllowList) == 0 {
+		return k.Scope.ToRBAC()
+	}
+
+	var allow []string
+	if len(k.AllowList) > 0 {
+		// The key owner is always allowed so the key can look up its own
+		// user.
+		allow = append([]string{k.UserID.String()}, k.AllowList...)
+	}
+	if len(k.Scopes) == 0 {
+		// Allow lists are only accepted with fine-grained scopes, because the
+		// coarse scopes act on resources that are not in any allow list, like
+		// new API keys. Deny everything rather than widening the key.
+		return rbac.Scope{
+			Role: rbac.Role{
+				Name:        fmt.Sprintf("Scope_none[%s]", strings.Join(allow, ",")),
+				DisplayName: "No permissions",
+				Site:        []rbac.Permission{},
+				Org:         map[string][]rbac.Permission{},
+				User:        []rbac.Permission{},
+			},
+			AllowIDList: allow,
+		}
+	}
+	names := make([]rbac.ScopeName, 0, len(k.Scopes))
+	for _, name := range k.Scopes {
+		names = append(names, rbac.ScopeName(name))
+	}
+	return rbac.ScopeSet{
+		Scopes:      names,
+		AllowIDList: allow,
+	}
+}
+
 func (k APIKey) RBACObject() rbac.Object {
 	return rbac.ResourceAPIKey.WithIDString(k.ID).
 		WithOwner(k.UserID.String())
@@ -216,6 +258,44 @@ func (p ProvisionerDaemon) RBACObject() rbac.Object {
 	return rbac.ResourceProvisionerDaemon.WithID(p.ID)
 }
 
+// ProvisionerDaemonStaleTimeout is how long a provisioner daemon may go
+// without sending a heartbeat before it is considered unhealthy. Daemons
+// send a heartbeat every 15 seconds by default.
+const ProvisionerDaemonStaleTimeout = 90 * time.Second
+
+// Healthy returns whether the daemon has sent a heartbeat within the
+// stale timeout.
+func (p ProvisionerDaemon) Healthy(staleTimeout time.Duration) bool {
+	if !p.LastSeenAt.Valid {
+		return false
+	}
+	return dbtime.Now().Sub(p.LastSeenAt.Time) <= staleTimeout
+}
+
+// CanAcquire returns whether the daemon is able to acquire a job with the
+// given provisioner and tags. This mirrors the matching performed by
+// AcquireProvisionerJob: the daemon must support the provisioner and
+// satisfy every job tag.
+func (p ProvisionerDaemon) CanAcquire(provisioner ProvisionerType, tags StringMap) bool {
+	supported := false
+	for _, daemonProvisioner := range p.Provisioners {
+		if daemonProvisioner == provisioner {
+			supported = true
+			break
+		}
+	}
+	if !supported {
+		return false
+	}
+	for key, value := range tags {
+		provided, ok := p.Tags[key]
+		if !ok || provided != value {
+			return false
+		}
+	}
+	return true
+}
+
 func (w WorkspaceProxy) RBACObject() rbac.Object {
 	return rbac.ResourceWorkspaceProxy.
 		WithID(w.ID)
@@ -225,6 +305,39 @@ func (w WorkspaceProxy) IsPrimary() bool {
 	return w.Name == "primary"
 }
 
+// RegionGateways returns the gateway of every DERP region of the given primary
+// replicas, which is the replica that meshes with the replicas of the other
+// regions. It's the replica with the lowest ID that can reach its peers, so
+// every replica elects the same one.
+func RegionGateways(replicas []Replica) map[int32]uuid.UUID {
+	gateways := map[int32]Replica{}
+	for _, replica := range replicas {
+		if !replica.Primary {
+			continue
+		}
+		gateway, ok := gateways[replica.RegionID]
+		if !ok {
+			gateways[replica.RegionID] = replica
+			continue
+		}
+		healthy, gatewayHealthy := replica.Error == "", gateway.Error == ""
+		if healthy != gatewayHealthy {
+			if healthy {
+				gateways[replica.RegionID] = replica
+			}
+			continue
+		}
+		if bytes.Compare(replica.ID[:], gateway.ID[:]) < 0 {
+			gateways[replica.RegionID] = replica
+		}
+	}
+	ids := make(map[int32]uuid.UUID, len(gateways))
+	for regionID, gateway := range gateways {
+		ids[regionID] = gateway.ID
+	}
+	return ids
+}
+
 func (f File) RBACObject() rbac.Object {
 	return rbac.ResourceFile.
 		WithID(f.ID).
@@ -260,10 +373,45 @@ func (u UserLink) RBACObject() rbac.Object {
 	return rbac.ResourceUserData.WithOwner(u.UserID.String()).WithID(u.UserID)
 }
 
+func (u UserMFA) RBACObject() rbac.Object {
+	return rbac.ResourceUserData.WithOwner(u.UserID.String()).WithID(u.UserID)
+}
+
+// AuditableUserMFA is an MFA enrollment with the username of the user it
+// belongs to, which is the target of audit logs.
+type AuditableUserMFA struct {
+	UserMFA
+	Username string `json:"username"`
+}
+
+// Auditable returns an object that can be used in audit logs.
+func (u UserMFA) Auditable(username string) AuditableUserMFA {
+	return AuditableUserMFA{
+		UserMFA:  u,
+		Username: username,
+	}
+}
+
 func (l License) RBACObject() rbac.Object {
 	return rbac.ResourceLicense.WithIDString(strconv.FormatInt(int64(l.ID), 10))
 }
 
+func (OAuth2ProviderApp) RBACObject() rbac.Object {
+	return rbac.ResourceOAuth2ProviderApp
+}
+
+func (OAuth2ProviderAppSecret) RBACObject() rbac.Object {
+	return rbac.ResourceOAuth2ProviderAppSecret
+}
+
+func (c OAuth2ProviderAppCode) RBACObject() rbac.Object {
+	return rbac.ResourceOAuth2ProviderAppCodeToken.WithOwner(c.UserID.String())
+}
+
+func (t OAuth2ProviderAppToken) RBACObject() rbac.Object {
+	return rbac.ResourceOAuth2ProviderAppCodeToken.WithOwner(t.UserID.String())
+}
+
 type WorkspaceAgentConnectionStatus struct {
 	Status           WorkspaceAgentStatus `json:"status"`
 	FirstConnectedAt *time.Time           `json:"first_connected_at"`
@@ -323,18 +471,19 @@ func ConvertUserRows(rows []GetUsersRow) []User {
 	users := make([]User, len(rows))
 	for i, r := range rows {
 		users[i] = User{
-			ID:             r.ID,
-			Email:          r.Email,
-			Username:       r.Username,
-			HashedPassword: r.HashedPassword,
-			CreatedAt:      r.CreatedAt,
-			UpdatedAt:      r.UpdatedAt,
-			Status:         r.Status,
-			RBACRoles:      r.RBACRoles,
-			LoginType:      r.LoginType,
-			AvatarURL:      r.AvatarURL,
-			Deleted:        r.Deleted,
-			LastSeenAt:     r.LastSeenAt,
+			ID:                         r.ID,
+			Email:                      r.Email,
+			Username:                   r.Username,
+			HashedPassword:             r.HashedPassword,
+			CreatedAt:                  r.CreatedAt,
+			UpdatedAt:                  r.UpdatedAt,
+			Status:                     r.Status,
+			RBACRoles:                  r.RBACRoles,
+			LoginType:                  r.LoginType,
+			AvatarURL:                  r.AvatarURL,
+			Deleted:                    r.Deleted,
+			LastSeenAt:                 r.LastSeenAt,
+			ServiceAccountOwnerGroupID: r.ServiceAccountOwnerGroupID,
 		}
 	}
 
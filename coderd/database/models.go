@@ -535,11 +535,14 @@ func AllLogSourceValues() []LogSource {
 type LoginType string
 
 const (
-	LoginTypePassword LoginType = "password"
-	LoginTypeGithub   LoginType = "github"
-	LoginTypeOIDC     LoginType = "oidc"
-	LoginTypeToken    LoginType = "token"
-	LoginTypeNone     LoginType = "none"
+	LoginTypePassword          LoginType = "password"
+	LoginTypeGithub            LoginType = "github"
+	LoginTypeOIDC              LoginType = "oidc"
+	LoginTypeToken             LoginType = "token"
+	LoginTypeNone              LoginType = "none"
+	LoginTypeOAuth2ProviderApp LoginType = "oauth2_provider_app"
+	LoginTypeServiceAccount    LoginType = "service_account"
+	LoginTypeLDAP              LoginType = "ldap"
 )
 
 func (e *LoginType) Scan(src interface{}) error {
@@ -583,7 +586,10 @@ func (e LoginType) Valid() bool {
 		LoginTypeGithub,
 		LoginTypeOIDC,
 		LoginTypeToken,
-		LoginTypeNone:
+		LoginTypeNone,
+		LoginTypeOAuth2ProviderApp,
+		LoginTypeServiceAccount,
+		LoginTypeLDAP:
 		return true
 	}
 	return false
@@ -596,6 +602,9 @@ func AllLoginTypeValues() []LoginType {
 		LoginTypeOIDC,
 		LoginTypeToken,
 		LoginTypeNone,
+		LoginTypeOAuth2ProviderApp,
+		LoginTypeServiceAccount,
+		LoginTypeLDAP,
 	}
 }
 
@@ -1014,18 +1023,21 @@ func AllProvisionerTypeValues() []ProvisionerType {
 type ResourceType string
 
 const (
-	ResourceTypeOrganization    ResourceType = "organization"
-	ResourceTypeTemplate        ResourceType = "template"
-	ResourceTypeTemplateVersion ResourceType = "template_version"
-	ResourceTypeUser            ResourceType = "user"
-	ResourceTypeWorkspace       ResourceType = "workspace"
-	ResourceTypeGitSshKey       ResourceType = "git_ssh_key"
-	ResourceTypeApiKey          ResourceType = "api_key"
-	ResourceTypeGroup           ResourceType = "group"
-	ResourceTypeWorkspaceBuild  ResourceType = "workspace_build"
-	ResourceTypeLicense         ResourceType = "license"
-	ResourceTypeWorkspaceProxy  ResourceType = "workspace_proxy"
-	ResourceTypeConvertLogin    ResourceType = "convert_login"
+	ResourceTypeOrganization            ResourceType = "organization"
+	ResourceTypeTemplate                ResourceType = "template"
+	ResourceTypeTemplateVersion         ResourceType = "template_version"
+	ResourceTypeUser                    ResourceType = "user"
+	ResourceTypeWorkspace               ResourceType = "workspace"
+	ResourceTypeGitSshKey               ResourceType = "git_ssh_key"
+	ResourceTypeApiKey                  ResourceType = "api_key"
+	ResourceTypeGroup                   ResourceType = "group"
+	ResourceTypeWorkspaceBuild          ResourceType = "workspace_build"
+	ResourceTypeLicense                 ResourceType = "license"
+	ResourceTypeWorkspaceProxy          ResourceType = "workspace_proxy"
+	ResourceTypeConvertLogin            ResourceType = "convert_login"
+	ResourceTypeOAuth2ProviderApp       ResourceType = "oauth2_provider_app"
+	ResourceTypeOAuth2ProviderAppSecret ResourceType = "oauth2_provider_app_secret"
+	ResourceTypeUserMFA                 ResourceType = "user_mfa"
 )
 
 func (e *ResourceType) Scan(src interface{}) error {
@@ -1076,7 +1088,10 @@ func (e ResourceType) Valid() bool {
 		ResourceTypeWorkspaceBuild,
 		ResourceTypeLicense,
 		ResourceTypeWorkspaceProxy,
-		ResourceTypeConvertLogin:
+		ResourceTypeConvertLogin,
+		ResourceTypeOAuth2ProviderApp,
+		ResourceTypeOAuth2ProviderAppSecret,
+		ResourceTypeUserMFA:
 		return true
 	}
 	return false
@@ -1096,6 +1111,9 @@ func AllResourceTypeValues() []ResourceType {
 		ResourceTypeLicense,
 		ResourceTypeWorkspaceProxy,
 		ResourceTypeConvertLogin,
+		ResourceTypeOAuth2ProviderApp,
+		ResourceTypeOAuth2ProviderAppSecret,
+		ResourceTypeUserMFA,
 	}
 }
 
@@ -1307,6 +1325,7 @@ const (
 	WorkspaceAgentLogSourceEnvbox         WorkspaceAgentLogSource = "envbox"
 	WorkspaceAgentLogSourceEnvbuilder     WorkspaceAgentLogSource = "envbuilder"
 	WorkspaceAgentLogSourceExternal       WorkspaceAgentLogSource = "external"
+	WorkspaceAgentLogSourceEgressPolicy   WorkspaceAgentLogSource = "egress_policy"
 )
 
 func (e *WorkspaceAgentLogSource) Scan(src interface{}) error {
@@ -1351,7 +1370,8 @@ func (e WorkspaceAgentLogSource) Valid() bool {
 		WorkspaceAgentLogSourceKubernetesLogs,
 		WorkspaceAgentLogSourceEnvbox,
 		WorkspaceAgentLogSourceEnvbuilder,
-		WorkspaceAgentLogSourceExternal:
+		WorkspaceAgentLogSourceExternal,
+		WorkspaceAgentLogSourceEgressPolicy:
 		return true
 	}
 	return false
@@ -1365,6 +1385,7 @@ func AllWorkspaceAgentLogSourceValues() []WorkspaceAgentLogSource {
 		WorkspaceAgentLogSourceEnvbox,
 		WorkspaceAgentLogSourceEnvbuilder,
 		WorkspaceAgentLogSourceExternal,
+		WorkspaceAgentLogSourceEgressPolicy,
 	}
 }
 
@@ -1571,6 +1592,36 @@ type APIKey struct {
 	IPAddress       pqtype.Inet `db:"ip_address" json:"ip_address"`
 	Scope           APIKeyScope `db:"scope" json:"scope"`
 	TokenName       string      `db:"token_name" json:"token_name"`
+	// Fine-grained scopes that replace scope when not empty. Values are rbac scope names.
+	Scopes []string `db:"scopes" json:"scopes"`
+	// IDs of the only resources the key can act on, in addition to the key owner. Empty allows all resources.
+	AllowList []string `db:"allow_list" json:"allow_list"`
+	// The ID of the additional OIDC provider the key was created with. Used to refresh the OAuth token of the matching user link.
+	ProviderID string `db:"provider_id" json:"provider_id"`
+}
+
+// Multi-factor authentication enrollments of password users.
+type UserMFA struct {
+	UserID     uuid.UUID `db:"user_id" json:"user_id"`
+	CreatedAt  time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at" json:"updated_at"`
+	TOTPSecret string    `db:"totp_secret" json:"totp_secret"`
+	// Null while the enrollment is pending, until the user confirms it with a code from their authenticator.
+	EnabledAt sql.NullTime `db:"enabled_at" json:"enabled_at"`
+	// The time step of the last accepted code, so codes cannot be replayed.
+	TOTPLastUsedStep int64 `db:"totp_last_used_step" json:"totp_last_used_step"`
+	// The number of invalid codes entered since the last valid one. Codes are rejected for a while once it reaches a limit.
+	FailedAttempts int32        `db:"failed_attempts" json:"failed_attempts"`
+	LastFailedAt   sql.NullTime `db:"last_failed_at" json:"last_failed_at"`
+}
+
+// Single use codes that replace a code from the authenticator of the user.
+type UserMFARecoveryCode struct {
+	ID         uuid.UUID    `db:"id" json:"id"`
+	UserID     uuid.UUID    `db:"user_id" json:"user_id"`
+	CreatedAt  time.Time    `db:"created_at" json:"created_at"`
+	UsedAt     sql.NullTime `db:"used_at" json:"used_at"`
+	HashedCode []byte       `db:"hashed_code" json:"hashed_code"`
 }
 
 type AuditLog struct {
@@ -1616,6 +1667,12 @@ type GitSSHKey struct {
 	UpdatedAt  time.Time `db:"updated_at" json:"updated_at"`
 	PrivateKey string    `db:"private_key" json:"private_key"`
 	PublicKey  string    `db:"public_key" json:"public_key"`
+	// The name of the key, which is unique per user. The key named "default" is generated when the user is created.
+	Name string `db:"name" json:"name"`
+	// Whether the private key was imported by the user instead of generated by Coder.
+	Imported bool `db:"imported" json:"imported"`
+	// Patterns of the hosts the key is offered to, such as "*.github.com". The key is offered to every host if it is empty.
+	Hosts []string `db:"hosts" json:"hosts"`
 }
 
 type Group struct {
@@ -1644,6 +1701,52 @@ type License struct {
 	UUID uuid.UUID `db:"uuid" json:"uuid"`
 }
 
+// A table used to configure apps that can use Coder as an OAuth2 provider, the reverse of what we are calling external authentication.
+type OAuth2ProviderApp struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
+	Name        string    `db:"name" json:"name"`
+	Icon        string    `db:"icon" json:"icon"`
+	CallbackURL string    `db:"callback_url" json:"callback_url"`
+}
+
+// Codes are meant to be exchanged for access tokens.
+type OAuth2ProviderAppCode struct {
+	ID           uuid.UUID `db:"id" json:"id"`
+	CreatedAt    time.Time `db:"created_at" json:"created_at"`
+	ExpiresAt    time.Time `db:"expires_at" json:"expires_at"`
+	SecretPrefix string    `db:"secret_prefix" json:"secret_prefix"`
+	HashedSecret []byte    `db:"hashed_secret" json:"hashed_secret"`
+	UserID       uuid.UUID `db:"user_id" json:"user_id"`
+	AppID        uuid.UUID `db:"app_id" json:"app_id"`
+	RedirectURI  string    `db:"redirect_uri" json:"redirect_uri"`
+	// The S256 PKCE challenge sent with the authorization request. PKCE is required, so every code has one.
+	CodeChallenge string `db:"code_challenge" json:"code_challenge"`
+}
+
+type OAuth2ProviderAppSecret struct {
+	ID           uuid.UUID    `db:"id" json:"id"`
+	CreatedAt    time.Time    `db:"created_at" json:"created_at"`
+	LastUsedAt   sql.NullTime `db:"last_used_at" json:"last_used_at"`
+	HashedSecret []byte       `db:"hashed_secret" json:"hashed_secret"`
+	// The tail end of the original secret so secrets can be differentiated.
+	DisplaySecret string    `db:"display_secret" json:"display_secret"`
+	AppID         uuid.UUID `db:"app_id" json:"app_id"`
+}
+
+type OAuth2ProviderAppToken struct {
+	ID         uuid.UUID `db:"id" json:"id"`
+	CreatedAt  time.Time `db:"created_at" json:"created_at"`
+	ExpiresAt  time.Time `db:"expires_at" json:"expires_at"`
+	HashPrefix string    `db:"hash_prefix" json:"hash_prefix"`
+	// Refresh tokens provide a way to refresh an access token (API key). An expired API key can be refreshed if this token is not yet expired, meaning this expiry can outlive an API key.
+	RefreshHash []byte    `db:"refresh_hash" json:"refresh_hash"`
+	AppSecretID uuid.UUID `db:"app_secret_id" json:"app_secret_id"`
+	APIKeyID    string    `db:"api_key_id" json:"api_key_id"`
+	UserID      uuid.UUID `db:"user_id" json:"user_id"`
+}
+
 type Organization struct {
 	ID          uuid.UUID `db:"id" json:"id"`
 	Name        string    `db:"name" json:"name"`
@@ -1700,6 +1803,12 @@ type ProvisionerDaemon struct {
 	Provisioners []ProvisionerType `db:"provisioners" json:"provisioners"`
 	ReplicaID    uuid.NullUUID     `db:"replica_id" json:"replica_id"`
 	Tags         StringMap         `db:"tags" json:"tags"`
+	// The last time the daemon sent a heartbeat to coderd.
+	LastSeenAt sql.NullTime `db:"last_seen_at" json:"last_seen_at"`
+	// The Coder version of the daemon, as reported in its last heartbeat.
+	Version string `db:"version" json:"version"`
+	// Maps each provisioner type served by the daemon to the version of its backing tool, e.g. the Terraform version.
+	ProvisionerVersions StringMap `db:"provisioner_versions" json:"provisioner_versions"`
 }
 
 type ProvisionerJob struct {
@@ -1800,6 +1909,7 @@ type Template struct {
 	TimeTilDormantAutoDelete      int64           `db:"time_til_dormant_autodelete" json:"time_til_dormant_autodelete"`
 	AutostopRequirementDaysOfWeek int16           `db:"autostop_requirement_days_of_week" json:"autostop_requirement_days_of_week"`
 	AutostopRequirementWeeks      int64           `db:"autostop_requirement_weeks" json:"autostop_requirement_weeks"`
+	EgressPolicy                  EgressPolicy    `db:"egress_policy" json:"egress_policy"`
 	CreatedByAvatarURL            sql.NullString  `db:"created_by_avatar_url" json:"created_by_avatar_url"`
 	CreatedByUsername             string          `db:"created_by_username" json:"created_by_username"`
 }
@@ -1836,6 +1946,8 @@ type TemplateTable struct {
 	AutostopRequirementDaysOfWeek int16 `db:"autostop_requirement_days_of_week" json:"autostop_requirement_days_of_week"`
 	// The number of weeks between restarts. 0 or 1 weeks means "every week", 2 week means "every second week", etc. Weeks are counted from January 2, 2023, which is the first Monday of 2023. This is to ensure workspaces are started consistently for all customers on the same n-week cycles.
 	AutostopRequirementWeeks int64 `db:"autostop_requirement_weeks" json:"autostop_requirement_weeks"`
+	// Allow and deny rules for the destinations that workspace agents forward connections to.
+	EgressPolicy EgressPolicy `db:"egress_policy" json:"egress_policy"`
 }
 
 // Joins in the username + avatar url of the created by user.
@@ -1889,6 +2001,10 @@ type TemplateVersionParameter struct {
 	DisplayOrder int32 `db:"display_order" json:"display_order"`
 	// The value of an ephemeral parameter will not be preserved between consecutive workspace builds.
 	Ephemeral bool `db:"ephemeral" json:"ephemeral"`
+	// Expression that controls whether the parameter is shown, based on the values of other parameters.
+	VisibleIf string `db:"visible_if" json:"visible_if"`
+	// Validation: expressions evaluated against the value and the values of other parameters.
+	ValidationRules json.RawMessage `db:"validation_rules" json:"validation_rules"`
 }
 
 type TemplateVersionTable struct {
@@ -1940,6 +2056,8 @@ type User struct {
 	LastSeenAt     time.Time      `db:"last_seen_at" json:"last_seen_at"`
 	// Daily (!) cron schedule (with optional CRON_TZ) signifying the start of the user's quiet hours. If empty, the default quiet hours on the instance is used instead.
 	QuietHoursSchedule string `db:"quiet_hours_schedule" json:"quiet_hours_schedule"`
+	// Members of this group can manage the service account and issue tokens for it. Only set for users with the service_account login type.
+	ServiceAccountOwnerGroupID uuid.NullUUID `db:"service_account_owner_group_id" json:"service_account_owner_group_id"`
 }
 
 type UserLink struct {
@@ -1949,6 +2067,8 @@ type UserLink struct {
 	OAuthAccessToken  string    `db:"oauth_access_token" json:"oauth_access_token"`
 	OAuthRefreshToken string    `db:"oauth_refresh_token" json:"oauth_refresh_token"`
 	OAuthExpiry       time.Time `db:"oauth_expiry" json:"oauth_expiry"`
+	// The ID of the additional OIDC provider the link belongs to. Empty for the default OIDC provider and other login types.
+	ProviderID string `db:"provider_id" json:"provider_id"`
 }
 
 // Visible fields of users are allowed to be joined with other tables for including context of other resources.
@@ -389,6 +389,31 @@ func TestQueuePosition(t *testing.T) {
 		require.Equal(t, job.QueuePosition, int64(index))
 		require.Equal(t, job.ProvisionerJob.ID, jobs[index].ID)
 	}
+
+	// Daemons that can acquire a tagged job can acquire the untagged jobs
+	// too, so it's queued behind them.
+	tagged := dbgen.ProvisionerJob(t, db, database.ProvisionerJob{
+		OrganizationID: org.ID,
+		Tags:           database.StringMap{"region": "eu"},
+	})
+	queued, err = db.GetProvisionerJobsByIDsWithQueuePosition(ctx, []uuid.UUID{tagged.ID})
+	require.NoError(t, err)
+	require.Len(t, queued, 1)
+	require.Equal(t, int64(jobCount), queued[0].QueuePosition)
+	require.Equal(t, int64(jobCount), queued[0].QueueSize)
+	require.Equal(t, int64(0), queued[0].MatchingDaemons)
+
+	// Untagged jobs aren't queued behind the tagged job, which only some
+	// daemons can acquire.
+	untagged := dbgen.ProvisionerJob(t, db, database.ProvisionerJob{
+		OrganizationID: org.ID,
+		Tags:           database.StringMap{},
+	})
+	queued, err = db.GetProvisionerJobsByIDsWithQueuePosition(ctx, []uuid.UUID{untagged.ID})
+	require.NoError(t, err)
+	require.Len(t, queued, 1)
+	require.Equal(t, int64(jobCount), queued[0].QueuePosition)
+	require.Equal(t, int64(jobCount), queued[0].QueueSize)
 }
 
 func TestUserLastSeenFilter(t *testing.T) {
@@ -54,7 +54,7 @@ func (q *sqlQuerier) DeleteApplicationConnectAPIKeysByUserID(ctx context.Context
 
 const getAPIKeyByID = `-- name: GetAPIKeyByID :one
 SELECT
-	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name
+	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, scopes, allow_list, provider_id
 FROM
 	api_keys
 WHERE
@@ -79,13 +79,16 @@ func (q *sqlQuerier) GetAPIKeyByID(ctx context.Context, id string) (APIKey, erro
 		&i.IPAddress,
 		&i.Scope,
 		&i.TokenName,
+		pq.Array(&i.Scopes),
+		pq.Array(&i.AllowList),
+		&i.ProviderID,
 	)
 	return i, err
 }
 
 const getAPIKeyByName = `-- name: GetAPIKeyByName :one
 SELECT
-	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name
+	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, scopes, allow_list, provider_id
 FROM
 	api_keys
 WHERE
@@ -118,12 +121,15 @@ func (q *sqlQuerier) GetAPIKeyByName(ctx context.Context, arg GetAPIKeyByNamePar
 		&i.IPAddress,
 		&i.Scope,
 		&i.TokenName,
+		pq.Array(&i.Scopes),
+		pq.Array(&i.AllowList),
+		&i.ProviderID,
 	)
 	return i, err
 }
 
 const getAPIKeysByLoginType = `-- name: GetAPIKeysByLoginType :many
-SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name FROM api_keys WHERE login_type = $1
+SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, scopes, allow_list, provider_id FROM api_keys WHERE login_type = $1
 `
 
 func (q *sqlQuerier) GetAPIKeysByLoginType(ctx context.Context, loginType LoginType) ([]APIKey, error) {
@@ -148,6 +154,9 @@ func (q *sqlQuerier) GetAPIKeysByLoginType(ctx context.Context, loginType LoginT
 			&i.IPAddress,
 			&i.Scope,
 			&i.TokenName,
+			pq.Array(&i.Scopes),
+			pq.Array(&i.AllowList),
+			&i.ProviderID,
 		); err != nil {
 			return nil, err
 		}
@@ -163,7 +172,7 @@ func (q *sqlQuerier) GetAPIKeysByLoginType(ctx context.Context, loginType LoginT
 }
 
 const getAPIKeysByUserID = `-- name: GetAPIKeysByUserID :many
-SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name FROM api_keys WHERE login_type = $1 AND user_id = $2
+SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, scopes, allow_list, provider_id FROM api_keys WHERE login_type = $1 AND user_id = $2
 `
 
 type GetAPIKeysByUserIDParams struct {
@@ -193,6 +202,9 @@ func (q *sqlQuerier) GetAPIKeysByUserID(ctx context.Context, arg GetAPIKeysByUse
 			&i.IPAddress,
 			&i.Scope,
 			&i.TokenName,
+			pq.Array(&i.Scopes),
+			pq.Array(&i.AllowList),
+			&i.ProviderID,
 		); err != nil {
 			return nil, err
 		}
@@ -208,7 +220,7 @@ func (q *sqlQuerier) GetAPIKeysByUserID(ctx context.Context, arg GetAPIKeysByUse
 }
 
 const getAPIKeysLastUsedAfter = `-- name: GetAPIKeysLastUsedAfter :many
-SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name FROM api_keys WHERE last_used > $1
+SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, scopes, allow_list, provider_id FROM api_keys WHERE last_used > $1
 `
 
 func (q *sqlQuerier) GetAPIKeysLastUsedAfter(ctx context.Context, lastUsed time.Time) ([]APIKey, error) {
@@ -233,6 +245,9 @@ func (q *sqlQuerier) GetAPIKeysLastUsedAfter(ctx context.Context, lastUsed time.
 			&i.IPAddress,
 			&i.Scope,
 			&i.TokenName,
+			pq.Array(&i.Scopes),
+			pq.Array(&i.AllowList),
+			&i.ProviderID,
 		); err != nil {
 			return nil, err
 		}
@@ -261,7 +276,10 @@ INSERT INTO
 		updated_at,
 		login_type,
 		scope,
-		token_name
+		token_name,
+		scopes,
+		allow_list,
+		provider_id
 	)
 VALUES
 	($1,
@@ -270,7 +288,7 @@ VALUES
 	     WHEN 0 THEN 86400
 		 ELSE $2::bigint
 	 END
-	 , $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name
+	 , $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15) RETURNING id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, scopes, allow_list, provider_id
 `
 
 type InsertAPIKeyParams struct {
@@ -286,6 +304,9 @@ type InsertAPIKeyParams struct {
 	LoginType       LoginType   `db:"login_type" json:"login_type"`
 	Scope           APIKeyScope `db:"scope" json:"scope"`
 	TokenName       string      `db:"token_name" json:"token_name"`
+	Scopes          []string    `db:"scopes" json:"scopes"`
+	AllowList       []string    `db:"allow_list" json:"allow_list"`
+	ProviderID      string      `db:"provider_id" json:"provider_id"`
 }
 
 func (q *sqlQuerier) InsertAPIKey(ctx context.Context, arg InsertAPIKeyParams) (APIKey, error) {
@@ -302,6 +323,9 @@ func (q *sqlQuerier) InsertAPIKey(ctx context.Context, arg InsertAPIKeyParams) (
 		arg.LoginType,
 		arg.Scope,
 		arg.TokenName,
+		pq.Array(arg.Scopes),
+		pq.Array(arg.AllowList),
+		arg.ProviderID,
 	)
 	var i APIKey
 	err := row.Scan(
@@ -317,6 +341,9 @@ func (q *sqlQuerier) InsertAPIKey(ctx context.Context, arg InsertAPIKeyParams) (
 		&i.IPAddress,
 		&i.Scope,
 		&i.TokenName,
+		pq.Array(&i.Scopes),
+		pq.Array(&i.AllowList),
+		&i.ProviderID,
 	)
 	return i, err
 }
@@ -358,6 +385,7 @@ SELECT
     users.status AS user_status,
     users.rbac_roles AS user_roles,
     users.avatar_url AS user_avatar_url,
+    users.login_type AS user_login_type,
     COUNT(audit_logs.*) OVER () AS count
 FROM
     audit_logs
@@ -492,6 +520,7 @@ type GetAuditLogsOffsetRow struct {
 	UserStatus       NullUserStatus  `db:"user_status" json:"user_status"`
 	UserRoles        pq.StringArray  `db:"user_roles" json:"user_roles"`
 	UserAvatarUrl    sql.NullString  `db:"user_avatar_url" json:"user_avatar_url"`
+	UserLoginType    NullLoginType   `db:"user_login_type" json:"user_login_type"`
 	Count            int64           `db:"count" json:"count"`
 }
 
@@ -541,6 +570,7 @@ func (q *sqlQuerier) GetAuditLogsOffset(ctx context.Context, arg GetAuditLogsOff
 			&i.UserStatus,
 			&i.UserRoles,
 			&i.UserAvatarUrl,
+			&i.UserLoginType,
 			&i.Count,
 		); err != nil {
 			return nil, err
@@ -921,24 +951,36 @@ DELETE FROM
 	gitsshkeys
 WHERE
 	user_id = $1
+	AND name = $2
 `
 
-func (q *sqlQuerier) DeleteGitSSHKey(ctx context.Context, userID uuid.UUID) error {
-	_, err := q.db.ExecContext(ctx, deleteGitSSHKey, userID)
+type DeleteGitSSHKeyParams struct {
+	UserID uuid.UUID `db:"user_id" json:"user_id"`
+	Name   string    `db:"name" json:"name"`
+}
+
+func (q *sqlQuerier) DeleteGitSSHKey(ctx context.Context, arg DeleteGitSSHKeyParams) error {
+	_, err := q.db.ExecContext(ctx, deleteGitSSHKey, arg.UserID, arg.Name)
 	return err
 }
 
 const getGitSSHKey = `-- name: GetGitSSHKey :one
 SELECT
-	user_id, created_at, updated_at, private_key, public_key
+	user_id, created_at, updated_at, private_key, public_key, name, imported, hosts
 FROM
 	gitsshkeys
 WHERE
 	user_id = $1
+	AND name = $2
 `
 
-func (q *sqlQuerier) GetGitSSHKey(ctx context.Context, userID uuid.UUID) (GitSSHKey, error) {
-	row := q.db.QueryRowContext(ctx, getGitSSHKey, userID)
+type GetGitSSHKeyParams struct {
+	UserID uuid.UUID `db:"user_id" json:"user_id"`
+	Name   string    `db:"name" json:"name"`
+}
+
+func (q *sqlQuerier) GetGitSSHKey(ctx context.Context, arg GetGitSSHKeyParams) (GitSSHKey, error) {
+	row := q.db.QueryRowContext(ctx, getGitSSHKey, arg.UserID, arg.Name)
 	var i GitSSHKey
 	err := row.Scan(
 		&i.UserID,
@@ -946,10 +988,56 @@ func (q *sqlQuerier) GetGitSSHKey(ctx context.Context, userID uuid.UUID) (GitSSH
 		&i.UpdatedAt,
 		&i.PrivateKey,
 		&i.PublicKey,
+		&i.Name,
+		&i.Imported,
+		pq.Array(&i.Hosts),
 	)
 	return i, err
 }
 
+const getGitSSHKeysByUserID = `-- name: GetGitSSHKeysByUserID :many
+SELECT
+	user_id, created_at, updated_at, private_key, public_key, name, imported, hosts
+FROM
+	gitsshkeys
+WHERE
+	user_id = $1
+ORDER BY
+	created_at ASC, name ASC
+`
+
+func (q *sqlQuerier) GetGitSSHKeysByUserID(ctx context.Context, userID uuid.UUID) ([]GitSSHKey, error) {
+	rows, err := q.db.QueryContext(ctx, getGitSSHKeysByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GitSSHKey
+	for rows.Next() {
+		var i GitSSHKey
+		if err := rows.Scan(
+			&i.UserID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.PrivateKey,
+			&i.PublicKey,
+			&i.Name,
+			&i.Imported,
+			pq.Array(&i.Hosts),
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const insertGitSSHKey = `-- name: InsertGitSSHKey :one
 INSERT INTO
 	gitsshkeys (
@@ -957,10 +1045,13 @@ INSERT INTO
 		created_at,
 		updated_at,
 		private_key,
-		public_key
+		public_key,
+		name,
+		imported,
+		hosts
 	)
 VALUES
-	($1, $2, $3, $4, $5) RETURNING user_id, created_at, updated_at, private_key, public_key
+	($1, $2, $3, $4, $5, $6, $7, $8) RETURNING user_id, created_at, updated_at, private_key, public_key, name, imported, hosts
 `
 
 type InsertGitSSHKeyParams struct {
@@ -969,6 +1060,9 @@ type InsertGitSSHKeyParams struct {
 	UpdatedAt  time.Time `db:"updated_at" json:"updated_at"`
 	PrivateKey string    `db:"private_key" json:"private_key"`
 	PublicKey  string    `db:"public_key" json:"public_key"`
+	Name       string    `db:"name" json:"name"`
+	Imported   bool      `db:"imported" json:"imported"`
+	Hosts      []string  `db:"hosts" json:"hosts"`
 }
 
 func (q *sqlQuerier) InsertGitSSHKey(ctx context.Context, arg InsertGitSSHKeyParams) (GitSSHKey, error) {
@@ -978,6 +1072,9 @@ func (q *sqlQuerier) InsertGitSSHKey(ctx context.Context, arg InsertGitSSHKeyPar
 		arg.UpdatedAt,
 		arg.PrivateKey,
 		arg.PublicKey,
+		arg.Name,
+		arg.Imported,
+		pq.Array(arg.Hosts),
 	)
 	var i GitSSHKey
 	err := row.Scan(
@@ -986,6 +1083,9 @@ func (q *sqlQuerier) InsertGitSSHKey(ctx context.Context, arg InsertGitSSHKeyPar
 		&i.UpdatedAt,
 		&i.PrivateKey,
 		&i.PublicKey,
+		&i.Name,
+		&i.Imported,
+		pq.Array(&i.Hosts),
 	)
 	return i, err
 }
@@ -994,28 +1094,34 @@ const updateGitSSHKey = `-- name: UpdateGitSSHKey :one
 UPDATE
 	gitsshkeys
 SET
-	updated_at = $2,
-	private_key = $3,
-	public_key = $4
+	updated_at = $3,
+	private_key = $4,
+	public_key = $5,
+	imported = $6
 WHERE
 	user_id = $1
+	AND name = $2
 RETURNING
-	user_id, created_at, updated_at, private_key, public_key
+	user_id, created_at, updated_at, private_key, public_key, name, imported, hosts
 `
 
 type UpdateGitSSHKeyParams struct {
 	UserID     uuid.UUID `db:"user_id" json:"user_id"`
+	Name       string    `db:"name" json:"name"`
 	UpdatedAt  time.Time `db:"updated_at" json:"updated_at"`
 	PrivateKey string    `db:"private_key" json:"private_key"`
 	PublicKey  string    `db:"public_key" json:"public_key"`
+	Imported   bool      `db:"imported" json:"imported"`
 }
 
 func (q *sqlQuerier) UpdateGitSSHKey(ctx context.Context, arg UpdateGitSSHKeyParams) (GitSSHKey, error) {
 	row := q.db.QueryRowContext(ctx, updateGitSSHKey,
 		arg.UserID,
+		arg.Name,
 		arg.UpdatedAt,
 		arg.PrivateKey,
 		arg.PublicKey,
+		arg.Imported,
 	)
 	var i GitSSHKey
 	err := row.Scan(
@@ -1024,6 +1130,9 @@ func (q *sqlQuerier) UpdateGitSSHKey(ctx context.Context, arg UpdateGitSSHKeyPar
 		&i.UpdatedAt,
 		&i.PrivateKey,
 		&i.PublicKey,
+		&i.Name,
+		&i.Imported,
+		pq.Array(&i.Hosts),
 	)
 	return i, err
 }
@@ -1066,7 +1175,7 @@ func (q *sqlQuerier) DeleteGroupMembersByOrgAndUser(ctx context.Context, arg Del
 
 const getGroupMembers = `-- name: GetGroupMembers :many
 SELECT
-	users.id, users.email, users.username, users.hashed_password, users.created_at, users.updated_at, users.status, users.rbac_roles, users.login_type, users.avatar_url, users.deleted, users.last_seen_at, users.quiet_hours_schedule
+	users.id, users.email, users.username, users.hashed_password, users.created_at, users.updated_at, users.status, users.rbac_roles, users.login_type, users.avatar_url, users.deleted, users.last_seen_at, users.quiet_hours_schedule, users.service_account_owner_group_id
 FROM
 	users
 LEFT JOIN
@@ -1115,6 +1224,7 @@ func (q *sqlQuerier) GetGroupMembers(ctx context.Context, groupID uuid.UUID) ([]
 			&i.Deleted,
 			&i.LastSeenAt,
 			&i.QuietHoursSchedule,
+			&i.ServiceAccountOwnerGroupID,
 		); err != nil {
 			return nil, err
 		}
@@ -2087,6 +2197,509 @@ func (q *sqlQuerier) TryAcquireLock(ctx context.Context, pgTryAdvisoryXactLock i
 	return pg_try_advisory_xact_lock, err
 }
 
+const deleteOAuth2ProviderAppByID = `-- name: DeleteOAuth2ProviderAppByID :exec
+DELETE FROM oauth2_provider_apps WHERE id = $1
+`
+
+func (q *sqlQuerier) DeleteOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteOAuth2ProviderAppByID, id)
+	return err
+}
+
+const deleteOAuth2ProviderAppCodeByID = `-- name: DeleteOAuth2ProviderAppCodeByID :one
+DELETE FROM oauth2_provider_app_codes WHERE id = $1 RETURNING id, created_at, expires_at, secret_prefix, hashed_secret, user_id, app_id, redirect_uri, code_challenge
+`
+
+func (q *sqlQuerier) DeleteOAuth2ProviderAppCodeByID(ctx context.Context, id uuid.UUID) (OAuth2ProviderAppCode, error) {
+	row := q.db.QueryRowContext(ctx, deleteOAuth2ProviderAppCodeByID, id)
+	var i OAuth2ProviderAppCode
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.SecretPrefix,
+		&i.HashedSecret,
+		&i.UserID,
+		&i.AppID,
+		&i.RedirectURI,
+		&i.CodeChallenge,
+	)
+	return i, err
+}
+
+const deleteOAuth2ProviderAppSecretByID = `-- name: DeleteOAuth2ProviderAppSecretByID :exec
+DELETE FROM oauth2_provider_app_secrets WHERE id = $1
+`
+
+func (q *sqlQuerier) DeleteOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteOAuth2ProviderAppSecretByID, id)
+	return err
+}
+
+const deleteOAuth2ProviderAppTokenByPrefix = `-- name: DeleteOAuth2ProviderAppTokenByPrefix :one
+DELETE FROM oauth2_provider_app_tokens WHERE hash_prefix = $1 RETURNING id, created_at, expires_at, hash_prefix, refresh_hash, app_secret_id, api_key_id, user_id
+`
+
+func (q *sqlQuerier) DeleteOAuth2ProviderAppTokenByPrefix(ctx context.Context, hashPrefix string) (OAuth2ProviderAppToken, error) {
+	row := q.db.QueryRowContext(ctx, deleteOAuth2ProviderAppTokenByPrefix, hashPrefix)
+	var i OAuth2ProviderAppToken
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.HashPrefix,
+		&i.RefreshHash,
+		&i.AppSecretID,
+		&i.APIKeyID,
+		&i.UserID,
+	)
+	return i, err
+}
+
+const getOAuth2ProviderAppByID = `-- name: GetOAuth2ProviderAppByID :one
+SELECT id, created_at, updated_at, name, icon, callback_url FROM oauth2_provider_apps WHERE id = $1
+`
+
+func (q *sqlQuerier) GetOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) (OAuth2ProviderApp, error) {
+	row := q.db.QueryRowContext(ctx, getOAuth2ProviderAppByID, id)
+	var i OAuth2ProviderApp
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Icon,
+		&i.CallbackURL,
+	)
+	return i, err
+}
+
+const getOAuth2ProviderAppCodeByID = `-- name: GetOAuth2ProviderAppCodeByID :one
+SELECT id, created_at, expires_at, secret_prefix, hashed_secret, user_id, app_id, redirect_uri, code_challenge FROM oauth2_provider_app_codes WHERE id = $1
+`
+
+func (q *sqlQuerier) GetOAuth2ProviderAppCodeByID(ctx context.Context, id uuid.UUID) (OAuth2ProviderAppCode, error) {
+	row := q.db.QueryRowContext(ctx, getOAuth2ProviderAppCodeByID, id)
+	var i OAuth2ProviderAppCode
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.SecretPrefix,
+		&i.HashedSecret,
+		&i.UserID,
+		&i.AppID,
+		&i.RedirectURI,
+		&i.CodeChallenge,
+	)
+	return i, err
+}
+
+const getOAuth2ProviderAppCodeByPrefix = `-- name: GetOAuth2ProviderAppCodeByPrefix :one
+SELECT id, created_at, expires_at, secret_prefix, hashed_secret, user_id, app_id, redirect_uri, code_challenge FROM oauth2_provider_app_codes WHERE secret_prefix = $1
+`
+
+func (q *sqlQuerier) GetOAuth2ProviderAppCodeByPrefix(ctx context.Context, secretPrefix string) (OAuth2ProviderAppCode, error) {
+	row := q.db.QueryRowContext(ctx, getOAuth2ProviderAppCodeByPrefix, secretPrefix)
+	var i OAuth2ProviderAppCode
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.SecretPrefix,
+		&i.HashedSecret,
+		&i.UserID,
+		&i.AppID,
+		&i.RedirectURI,
+		&i.CodeChallenge,
+	)
+	return i, err
+}
+
+const getOAuth2ProviderAppSecretByID = `-- name: GetOAuth2ProviderAppSecretByID :one
+SELECT id, created_at, last_used_at, hashed_secret, display_secret, app_id FROM oauth2_provider_app_secrets WHERE id = $1
+`
+
+func (q *sqlQuerier) GetOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) (OAuth2ProviderAppSecret, error) {
+	row := q.db.QueryRowContext(ctx, getOAuth2ProviderAppSecretByID, id)
+	var i OAuth2ProviderAppSecret
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+		&i.HashedSecret,
+		&i.DisplaySecret,
+		&i.AppID,
+	)
+	return i, err
+}
+
+const getOAuth2ProviderAppSecretsByAppID = `-- name: GetOAuth2ProviderAppSecretsByAppID :many
+SELECT id, created_at, last_used_at, hashed_secret, display_secret, app_id FROM oauth2_provider_app_secrets WHERE app_id = $1 ORDER BY (created_at, id) ASC
+`
+
+func (q *sqlQuerier) GetOAuth2ProviderAppSecretsByAppID(ctx context.Context, appID uuid.UUID) ([]OAuth2ProviderAppSecret, error) {
+	rows, err := q.db.QueryContext(ctx, getOAuth2ProviderAppSecretsByAppID, appID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []OAuth2ProviderAppSecret
+	for rows.Next() {
+		var i OAuth2ProviderAppSecret
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.LastUsedAt,
+			&i.HashedSecret,
+			&i.DisplaySecret,
+			&i.AppID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getOAuth2ProviderAppTokenByPrefix = `-- name: GetOAuth2ProviderAppTokenByPrefix :one
+SELECT id, created_at, expires_at, hash_prefix, refresh_hash, app_secret_id, api_key_id, user_id FROM oauth2_provider_app_tokens WHERE hash_prefix = $1
+`
+
+func (q *sqlQuerier) GetOAuth2ProviderAppTokenByPrefix(ctx context.Context, hashPrefix string) (OAuth2ProviderAppToken, error) {
+	row := q.db.QueryRowContext(ctx, getOAuth2ProviderAppTokenByPrefix, hashPrefix)
+	var i OAuth2ProviderAppToken
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.HashPrefix,
+		&i.RefreshHash,
+		&i.AppSecretID,
+		&i.APIKeyID,
+		&i.UserID,
+	)
+	return i, err
+}
+
+const getOAuth2ProviderApps = `-- name: GetOAuth2ProviderApps :many
+SELECT id, created_at, updated_at, name, icon, callback_url FROM oauth2_provider_apps ORDER BY (name, id) ASC
+`
+
+func (q *sqlQuerier) GetOAuth2ProviderApps(ctx context.Context) ([]OAuth2ProviderApp, error) {
+	rows, err := q.db.QueryContext(ctx, getOAuth2ProviderApps)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []OAuth2ProviderApp
+	for rows.Next() {
+		var i OAuth2ProviderApp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Name,
+			&i.Icon,
+			&i.CallbackURL,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertOAuth2ProviderApp = `-- name: InsertOAuth2ProviderApp :one
+INSERT INTO oauth2_provider_apps (
+	id,
+	created_at,
+	updated_at,
+	name,
+	icon,
+	callback_url
+) VALUES(
+	$1,
+	$2,
+	$3,
+	$4,
+	$5,
+	$6
+) RETURNING id, created_at, updated_at, name, icon, callback_url
+`
+
+type InsertOAuth2ProviderAppParams struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
+	Name        string    `db:"name" json:"name"`
+	Icon        string    `db:"icon" json:"icon"`
+	CallbackURL string    `db:"callback_url" json:"callback_url"`
+}
+
+func (q *sqlQuerier) InsertOAuth2ProviderApp(ctx context.Context, arg InsertOAuth2ProviderAppParams) (OAuth2ProviderApp, error) {
+	row := q.db.QueryRowContext(ctx, insertOAuth2ProviderApp,
+		arg.ID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.Name,
+		arg.Icon,
+		arg.CallbackURL,
+	)
+	var i OAuth2ProviderApp
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Icon,
+		&i.CallbackURL,
+	)
+	return i, err
+}
+
+const insertOAuth2ProviderAppCode = `-- name: InsertOAuth2ProviderAppCode :one
+INSERT INTO oauth2_provider_app_codes (
+	id,
+	created_at,
+	expires_at,
+	secret_prefix,
+	hashed_secret,
+	user_id,
+	app_id,
+	redirect_uri,
+	code_challenge
+) VALUES(
+	$1,
+	$2,
+	$3,
+	$4,
+	$5,
+	$6,
+	$7,
+	$8,
+	$9
+) RETURNING id, created_at, expires_at, secret_prefix, hashed_secret, user_id, app_id, redirect_uri, code_challenge
+`
+
+type InsertOAuth2ProviderAppCodeParams struct {
+	ID            uuid.UUID `db:"id" json:"id"`
+	CreatedAt     time.Time `db:"created_at" json:"created_at"`
+	ExpiresAt     time.Time `db:"expires_at" json:"expires_at"`
+	SecretPrefix  string    `db:"secret_prefix" json:"secret_prefix"`
+	HashedSecret  []byte    `db:"hashed_secret" json:"hashed_secret"`
+	UserID        uuid.UUID `db:"user_id" json:"user_id"`
+	AppID         uuid.UUID `db:"app_id" json:"app_id"`
+	RedirectURI   string    `db:"redirect_uri" json:"redirect_uri"`
+	CodeChallenge string    `db:"code_challenge" json:"code_challenge"`
+}
+
+func (q *sqlQuerier) InsertOAuth2ProviderAppCode(ctx context.Context, arg InsertOAuth2ProviderAppCodeParams) (OAuth2ProviderAppCode, error) {
+	row := q.db.QueryRowContext(ctx, insertOAuth2ProviderAppCode,
+		arg.ID,
+		arg.CreatedAt,
+		arg.ExpiresAt,
+		arg.SecretPrefix,
+		arg.HashedSecret,
+		arg.UserID,
+		arg.AppID,
+		arg.RedirectURI,
+		arg.CodeChallenge,
+	)
+	var i OAuth2ProviderAppCode
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.SecretPrefix,
+		&i.HashedSecret,
+		&i.UserID,
+		&i.AppID,
+		&i.RedirectURI,
+		&i.CodeChallenge,
+	)
+	return i, err
+}
+
+const insertOAuth2ProviderAppSecret = `-- name: InsertOAuth2ProviderAppSecret :one
+INSERT INTO oauth2_provider_app_secrets (
+	id,
+	created_at,
+	hashed_secret,
+	display_secret,
+	app_id
+) VALUES(
+	$1,
+	$2,
+	$3,
+	$4,
+	$5
+) RETURNING id, created_at, last_used_at, hashed_secret, display_secret, app_id
+`
+
+type InsertOAuth2ProviderAppSecretParams struct {
+	ID            uuid.UUID `db:"id" json:"id"`
+	CreatedAt     time.Time `db:"created_at" json:"created_at"`
+	HashedSecret  []byte    `db:"hashed_secret" json:"hashed_secret"`
+	DisplaySecret string    `db:"display_secret" json:"display_secret"`
+	AppID         uuid.UUID `db:"app_id" json:"app_id"`
+}
+
+func (q *sqlQuerier) InsertOAuth2ProviderAppSecret(ctx context.Context, arg InsertOAuth2ProviderAppSecretParams) (OAuth2ProviderAppSecret, error) {
+	row := q.db.QueryRowContext(ctx, insertOAuth2ProviderAppSecret,
+		arg.ID,
+		arg.CreatedAt,
+		arg.HashedSecret,
+		arg.DisplaySecret,
+		arg.AppID,
+	)
+	var i OAuth2ProviderAppSecret
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+		&i.HashedSecret,
+		&i.DisplaySecret,
+		&i.AppID,
+	)
+	return i, err
+}
+
+const insertOAuth2ProviderAppToken = `-- name: InsertOAuth2ProviderAppToken :one
+INSERT INTO oauth2_provider_app_tokens (
+	id,
+	created_at,
+	expires_at,
+	hash_prefix,
+	refresh_hash,
+	app_secret_id,
+	api_key_id,
+	user_id
+) VALUES(
+	$1,
+	$2,
+	$3,
+	$4,
+	$5,
+	$6,
+	$7,
+	$8
+) RETURNING id, created_at, expires_at, hash_prefix, refresh_hash, app_secret_id, api_key_id, user_id
+`
+
+type InsertOAuth2ProviderAppTokenParams struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+	ExpiresAt   time.Time `db:"expires_at" json:"expires_at"`
+	HashPrefix  string    `db:"hash_prefix" json:"hash_prefix"`
+	RefreshHash []byte    `db:"refresh_hash" json:"refresh_hash"`
+	AppSecretID uuid.UUID `db:"app_secret_id" json:"app_secret_id"`
+	APIKeyID    string    `db:"api_key_id" json:"api_key_id"`
+	UserID      uuid.UUID `db:"user_id" json:"user_id"`
+}
+
+func (q *sqlQuerier) InsertOAuth2ProviderAppToken(ctx context.Context, arg InsertOAuth2ProviderAppTokenParams) (OAuth2ProviderAppToken, error) {
+	row := q.db.QueryRowContext(ctx, insertOAuth2ProviderAppToken,
+		arg.ID,
+		arg.CreatedAt,
+		arg.ExpiresAt,
+		arg.HashPrefix,
+		arg.RefreshHash,
+		arg.AppSecretID,
+		arg.APIKeyID,
+		arg.UserID,
+	)
+	var i OAuth2ProviderAppToken
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.HashPrefix,
+		&i.RefreshHash,
+		&i.AppSecretID,
+		&i.APIKeyID,
+		&i.UserID,
+	)
+	return i, err
+}
+
+const updateOAuth2ProviderAppByID = `-- name: UpdateOAuth2ProviderAppByID :one
+UPDATE oauth2_provider_apps SET
+	updated_at = $2,
+	name = $3,
+	icon = $4,
+	callback_url = $5
+WHERE id = $1 RETURNING id, created_at, updated_at, name, icon, callback_url
+`
+
+type UpdateOAuth2ProviderAppByIDParams struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
+	Name        string    `db:"name" json:"name"`
+	Icon        string    `db:"icon" json:"icon"`
+	CallbackURL string    `db:"callback_url" json:"callback_url"`
+}
+
+func (q *sqlQuerier) UpdateOAuth2ProviderAppByID(ctx context.Context, arg UpdateOAuth2ProviderAppByIDParams) (OAuth2ProviderApp, error) {
+	row := q.db.QueryRowContext(ctx, updateOAuth2ProviderAppByID,
+		arg.ID,
+		arg.UpdatedAt,
+		arg.Name,
+		arg.Icon,
+		arg.CallbackURL,
+	)
+	var i OAuth2ProviderApp
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Icon,
+		&i.CallbackURL,
+	)
+	return i, err
+}
+
+const updateOAuth2ProviderAppSecretByID = `-- name: UpdateOAuth2ProviderAppSecretByID :one
+UPDATE oauth2_provider_app_secrets SET
+	last_used_at = $2
+WHERE id = $1 RETURNING id, created_at, last_used_at, hashed_secret, display_secret, app_id
+`
+
+type UpdateOAuth2ProviderAppSecretByIDParams struct {
+	ID         uuid.UUID    `db:"id" json:"id"`
+	LastUsedAt sql.NullTime `db:"last_used_at" json:"last_used_at"`
+}
+
+func (q *sqlQuerier) UpdateOAuth2ProviderAppSecretByID(ctx context.Context, arg UpdateOAuth2ProviderAppSecretByIDParams) (OAuth2ProviderAppSecret, error) {
+	row := q.db.QueryRowContext(ctx, updateOAuth2ProviderAppSecretByID,
+		arg.ID,
+		arg.LastUsedAt,
+	)
+	var i OAuth2ProviderAppSecret
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+		&i.HashedSecret,
+		&i.DisplaySecret,
+		&i.AppID,
+	)
+	return i, err
+}
+
 const getOrganizationIDsByMemberIDs = `-- name: GetOrganizationIDsByMemberIDs :many
 SELECT
     user_id, array_agg(organization_id) :: uuid [ ] AS "organization_IDs"
@@ -2480,7 +3093,7 @@ func (q *sqlQuerier) GetParameterSchemasByJobID(ctx context.Context, jobID uuid.
 
 const getProvisionerDaemons = `-- name: GetProvisionerDaemons :many
 SELECT
-	id, created_at, updated_at, name, provisioners, replica_id, tags
+	id, created_at, updated_at, name, provisioners, replica_id, tags, last_seen_at, version, provisioner_versions
 FROM
 	provisioner_daemons
 `
@@ -2502,6 +3115,9 @@ func (q *sqlQuerier) GetProvisionerDaemons(ctx context.Context) ([]ProvisionerDa
 			pq.Array(&i.Provisioners),
 			&i.ReplicaID,
 			&i.Tags,
+			&i.LastSeenAt,
+			&i.Version,
+			&i.ProvisionerVersions,
 		); err != nil {
 			return nil, err
 		}
@@ -2526,7 +3142,7 @@ INSERT INTO
 		tags
 	)
 VALUES
-	($1, $2, $3, $4, $5) RETURNING id, created_at, updated_at, name, provisioners, replica_id, tags
+	($1, $2, $3, $4, $5) RETURNING id, created_at, updated_at, name, provisioners, replica_id, tags, last_seen_at, version, provisioner_versions
 `
 
 type InsertProvisionerDaemonParams struct {
@@ -2554,10 +3170,41 @@ func (q *sqlQuerier) InsertProvisionerDaemon(ctx context.Context, arg InsertProv
 		pq.Array(&i.Provisioners),
 		&i.ReplicaID,
 		&i.Tags,
+		&i.LastSeenAt,
+		&i.Version,
+		&i.ProvisionerVersions,
 	)
 	return i, err
 }
 
+const updateProvisionerDaemonLastSeenAt = `-- name: UpdateProvisionerDaemonLastSeenAt :exec
+UPDATE
+	provisioner_daemons
+SET
+	last_seen_at = $1,
+	version = $2,
+	provisioner_versions = $3
+WHERE
+	id = $4
+`
+
+type UpdateProvisionerDaemonLastSeenAtParams struct {
+	LastSeenAt          sql.NullTime `db:"last_seen_at" json:"last_seen_at"`
+	Version             string       `db:"version" json:"version"`
+	ProvisionerVersions StringMap    `db:"provisioner_versions" json:"provisioner_versions"`
+	ID                  uuid.UUID    `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) UpdateProvisionerDaemonLastSeenAt(ctx context.Context, arg UpdateProvisionerDaemonLastSeenAtParams) error {
+	_, err := q.db.ExecContext(ctx, updateProvisionerDaemonLastSeenAt,
+		arg.LastSeenAt,
+		arg.Version,
+		arg.ProvisionerVersions,
+		arg.ID,
+	)
+	return err
+}
+
 const getProvisionerLogsAfterID = `-- name: GetProvisionerLogsAfterID :many
 SELECT
 	job_id, created_at, source, level, stage, output, id
@@ -2737,6 +3384,60 @@ func (q *sqlQuerier) AcquireProvisionerJob(ctx context.Context, arg AcquireProvi
 	return i, err
 }
 
+const getActiveTemplateVersionJobTags = `-- name: GetActiveTemplateVersionJobTags :many
+SELECT
+	templates.id AS template_id,
+	templates.name AS template_name,
+	provisioner_jobs.provisioner,
+	provisioner_jobs.tags
+FROM
+	templates
+JOIN
+	template_versions ON template_versions.id = templates.active_version_id
+JOIN
+	provisioner_jobs ON provisioner_jobs.id = template_versions.job_id
+WHERE
+	templates.deleted = false
+`
+
+type GetActiveTemplateVersionJobTagsRow struct {
+	TemplateID   uuid.UUID       `db:"template_id" json:"template_id"`
+	TemplateName string          `db:"template_name" json:"template_name"`
+	Provisioner  ProvisionerType `db:"provisioner" json:"provisioner"`
+	Tags         StringMap       `db:"tags" json:"tags"`
+}
+
+// Returns the provisioner and tags of the job that imported the active
+// version of every template. These are the requirements a provisioner daemon
+// must satisfy to build workspaces from the template.
+func (q *sqlQuerier) GetActiveTemplateVersionJobTags(ctx context.Context) ([]GetActiveTemplateVersionJobTagsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getActiveTemplateVersionJobTags)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetActiveTemplateVersionJobTagsRow
+	for rows.Next() {
+		var i GetActiveTemplateVersionJobTagsRow
+		if err := rows.Scan(
+			&i.TemplateID,
+			&i.TemplateName,
+			&i.Provisioner,
+			&i.Tags,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getHungProvisionerJobs = `-- name: GetHungProvisionerJobs :many
 SELECT
 	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata
@@ -2879,40 +3580,93 @@ func (q *sqlQuerier) GetProvisionerJobsByIDs(ctx context.Context, ids []uuid.UUI
 const getProvisionerJobsByIDsWithQueuePosition = `-- name: GetProvisionerJobsByIDsWithQueuePosition :many
 WITH unstarted_jobs AS (
     SELECT
-        id, created_at
+        id, created_at, provisioner, tags
     FROM
         provisioner_jobs
     WHERE
         started_at IS NULL
 ),
+-- Provisioner daemons acquire jobs with a subset of their tags, so every
+-- daemon that can acquire a job can acquire the jobs with the same
+-- provisioner and a subset of its tags as well. The job waits behind those.
 queue_position AS (
     SELECT
-        id,
-        ROW_NUMBER() OVER (ORDER BY created_at ASC) AS queue_position
+        job.id,
+        COUNT(*) FILTER (
+            WHERE (other.created_at, other.id) <= (job.created_at, job.id)
+        ) AS queue_position,
+        COUNT(*) AS queue_size
     FROM
-        unstarted_jobs
+        unstarted_jobs job
+    INNER JOIN
+        unstarted_jobs other
+    ON
+        other.provisioner = job.provisioner
+        AND other.tags <@ job.tags
+    WHERE
+        job.id = ANY($1 :: uuid [ ])
+    GROUP BY
+        job.id
 ),
-queue_size AS (
-	SELECT COUNT(*) as count FROM unstarted_jobs
+-- Healthy provisioner daemons that are able to acquire the job. The interval
+-- matches database.ProvisionerDaemonStaleTimeout.
+matching_daemons AS (
+    SELECT
+        pj.id AS job_id,
+        pd.id AS daemon_id
+    FROM
+        provisioner_jobs pj
+    INNER JOIN
+        provisioner_daemons pd
+    ON
+        pj.provisioner = ANY(pd.provisioners)
+        AND pj.tags <@ pd.tags
+        AND pd.last_seen_at > NOW() - INTERVAL '90 seconds'
+    WHERE
+        pj.id = ANY($1 :: uuid [ ])
 )
 SELECT
 	pj.id, pj.created_at, pj.updated_at, pj.started_at, pj.canceled_at, pj.completed_at, pj.error, pj.organization_id, pj.initiator_id, pj.provisioner, pj.storage_method, pj.type, pj.input, pj.worker_id, pj.file_id, pj.tags, pj.error_code, pj.trace_metadata,
     COALESCE(qp.queue_position, 0) AS queue_position,
-    COALESCE(qs.count, 0) AS queue_size
+    COALESCE(qp.queue_size, 0) AS queue_size,
+    (
+        SELECT
+            COUNT(*)
+        FROM
+            matching_daemons md
+        WHERE
+            md.job_id = pj.id
+    ) AS matching_daemons,
+    -- The number of jobs that the matching daemons are running, which have
+    -- to complete before the daemons acquire queued jobs.
+    (
+        SELECT
+            COUNT(*)
+        FROM
+            provisioner_jobs running
+        INNER JOIN
+            matching_daemons md
+        ON
+            md.daemon_id = running.worker_id
+        WHERE
+            md.job_id = pj.id
+            AND running.started_at IS NOT NULL
+            AND running.completed_at IS NULL
+    ) AS running_jobs
 FROM
 	provisioner_jobs pj
 LEFT JOIN
 	queue_position qp ON qp.id = pj.id
-LEFT JOIN
-	queue_size qs ON TRUE
 WHERE
 	pj.id = ANY($1 :: uuid [ ])
 `
 
 type GetProvisionerJobsByIDsWithQueuePositionRow struct {
-	ProvisionerJob ProvisionerJob `db:"provisioner_job" json:"provisioner_job"`
-	QueuePosition  int64          `db:"queue_position" json:"queue_position"`
-	QueueSize      int64          `db:"queue_size" json:"queue_size"`
+	ProvisionerJob  ProvisionerJob `db:"provisioner_job" json:"provisioner_job"`
+	QueuePosition   int64          `db:"queue_position" json:"queue_position"`
+	QueueSize       int64          `db:"queue_size" json:"queue_size"`
+	MatchingDaemons int64          `db:"matching_daemons" json:"matching_daemons"`
+	RunningJobs     int64          `db:"running_jobs" json:"running_jobs"`
 }
 
 func (q *sqlQuerier) GetProvisionerJobsByIDsWithQueuePosition(ctx context.Context, ids []uuid.UUID) ([]GetProvisionerJobsByIDsWithQueuePositionRow, error) {
@@ -2945,6 +3699,54 @@ func (q *sqlQuerier) GetProvisionerJobsByIDsWithQueuePosition(ctx context.Contex
 			&i.ProvisionerJob.TraceMetadata,
 			&i.QueuePosition,
 			&i.QueueSize,
+			&i.MatchingDaemons,
+			&i.RunningJobs,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getProvisionerJobsCreatedAfter = `-- name: GetProvisionerJobsCreatedAfter :many
+SELECT id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata FROM provisioner_jobs WHERE created_at > $1
+`
+
+func (q *sqlQuerier) GetProvisionerJobsCreatedAfter(ctx context.Context, createdAt time.Time) ([]ProvisionerJob, error) {
+	rows, err := q.db.QueryContext(ctx, getProvisionerJobsCreatedAfter, createdAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProvisionerJob
+	for rows.Next() {
+		var i ProvisionerJob
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.StartedAt,
+			&i.CanceledAt,
+			&i.CompletedAt,
+			&i.Error,
+			&i.OrganizationID,
+			&i.InitiatorID,
+			&i.Provisioner,
+			&i.StorageMethod,
+			&i.Type,
+			&i.Input,
+			&i.WorkerID,
+			&i.FileID,
+			&i.Tags,
+			&i.ErrorCode,
+			&i.TraceMetadata,
 		); err != nil {
 			return nil, err
 		}
@@ -2959,12 +3761,19 @@ func (q *sqlQuerier) GetProvisionerJobsByIDsWithQueuePosition(ctx context.Contex
 	return items, nil
 }
 
-const getProvisionerJobsCreatedAfter = `-- name: GetProvisionerJobsCreatedAfter :many
-SELECT id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata FROM provisioner_jobs WHERE created_at > $1
+const getProvisionerJobsRunningByWorkerIDs = `-- name: GetProvisionerJobsRunningByWorkerIDs :many
+SELECT
+	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata
+FROM
+	provisioner_jobs
+WHERE
+	worker_id = ANY($1 :: uuid [ ])
+	AND started_at IS NOT NULL
+	AND completed_at IS NULL
 `
 
-func (q *sqlQuerier) GetProvisionerJobsCreatedAfter(ctx context.Context, createdAt time.Time) ([]ProvisionerJob, error) {
-	rows, err := q.db.QueryContext(ctx, getProvisionerJobsCreatedAfter, createdAt)
+func (q *sqlQuerier) GetProvisionerJobsRunningByWorkerIDs(ctx context.Context, workerIds []uuid.UUID) ([]ProvisionerJob, error) {
+	rows, err := q.db.QueryContext(ctx, getProvisionerJobsRunningByWorkerIDs, pq.Array(workerIds))
 	if err != nil {
 		return nil, err
 	}
@@ -3992,13 +4801,14 @@ func (q *sqlQuerier) DeleteTailnetAgent(ctx context.Context, arg DeleteTailnetAg
 const deleteTailnetClient = `-- name: DeleteTailnetClient :one
 DELETE
 FROM tailnet_clients
-WHERE id = $1 and coordinator_id = $2
+WHERE id = $1 and coordinator_id = $2 and agent_id = $3
 RETURNING id, coordinator_id
 `
 
 type DeleteTailnetClientParams struct {
 	ID            uuid.UUID `db:"id" json:"id"`
 	CoordinatorID uuid.UUID `db:"coordinator_id" json:"coordinator_id"`
+	AgentID       uuid.UUID `db:"agent_id" json:"agent_id"`
 }
 
 type DeleteTailnetClientRow struct {
@@ -4007,7 +4817,7 @@ type DeleteTailnetClientRow struct {
 }
 
 func (q *sqlQuerier) DeleteTailnetClient(ctx context.Context, arg DeleteTailnetClientParams) (DeleteTailnetClientRow, error) {
-	row := q.db.QueryRowContext(ctx, deleteTailnetClient, arg.ID, arg.CoordinatorID)
+	row := q.db.QueryRowContext(ctx, deleteTailnetClient, arg.ID, arg.CoordinatorID, arg.AgentID)
 	var i DeleteTailnetClientRow
 	err := row.Scan(&i.ID, &i.CoordinatorID)
 	return i, err
@@ -4198,7 +5008,7 @@ INSERT INTO
 )
 VALUES
 	($1, $2, $3, $4, now() at time zone 'utc')
-ON CONFLICT (id, coordinator_id)
+ON CONFLICT (id, coordinator_id, agent_id)
 DO UPDATE SET
 	id = $1,
 	coordinator_id = $2,
@@ -4317,7 +5127,7 @@ func (q *sqlQuerier) GetTemplateAverageBuildTime(ctx context.Context, arg GetTem
 
 const getTemplateByID = `-- name: GetTemplateByID :one
 SELECT
-	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, created_by_avatar_url, created_by_username
+	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, egress_policy, created_by_avatar_url, created_by_username
 FROM
 	template_with_users
 WHERE
@@ -4354,6 +5164,7 @@ func (q *sqlQuerier) GetTemplateByID(ctx context.Context, id uuid.UUID) (Templat
 		&i.TimeTilDormantAutoDelete,
 		&i.AutostopRequirementDaysOfWeek,
 		&i.AutostopRequirementWeeks,
+		&i.EgressPolicy,
 		&i.CreatedByAvatarURL,
 		&i.CreatedByUsername,
 	)
@@ -4362,7 +5173,7 @@ func (q *sqlQuerier) GetTemplateByID(ctx context.Context, id uuid.UUID) (Templat
 
 const getTemplateByOrganizationAndName = `-- name: GetTemplateByOrganizationAndName :one
 SELECT
-	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, created_by_avatar_url, created_by_username
+	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, egress_policy, created_by_avatar_url, created_by_username
 FROM
 	template_with_users AS templates
 WHERE
@@ -4407,6 +5218,7 @@ func (q *sqlQuerier) GetTemplateByOrganizationAndName(ctx context.Context, arg G
 		&i.TimeTilDormantAutoDelete,
 		&i.AutostopRequirementDaysOfWeek,
 		&i.AutostopRequirementWeeks,
+		&i.EgressPolicy,
 		&i.CreatedByAvatarURL,
 		&i.CreatedByUsername,
 	)
@@ -4414,7 +5226,7 @@ func (q *sqlQuerier) GetTemplateByOrganizationAndName(ctx context.Context, arg G
 }
 
 const getTemplates = `-- name: GetTemplates :many
-SELECT id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, created_by_avatar_url, created_by_username FROM template_with_users AS templates
+SELECT id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, egress_policy, created_by_avatar_url, created_by_username FROM template_with_users AS templates
 ORDER BY (name, id) ASC
 `
 
@@ -4452,6 +5264,7 @@ func (q *sqlQuerier) GetTemplates(ctx context.Context) ([]Template, error) {
 			&i.TimeTilDormantAutoDelete,
 			&i.AutostopRequirementDaysOfWeek,
 			&i.AutostopRequirementWeeks,
+			&i.EgressPolicy,
 			&i.CreatedByAvatarURL,
 			&i.CreatedByUsername,
 		); err != nil {
@@ -4470,7 +5283,7 @@ func (q *sqlQuerier) GetTemplates(ctx context.Context) ([]Template, error) {
 
 const getTemplatesWithFilter = `-- name: GetTemplatesWithFilter :many
 SELECT
-	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, created_by_avatar_url, created_by_username
+	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, egress_policy, created_by_avatar_url, created_by_username
 FROM
 	template_with_users AS templates
 WHERE
@@ -4545,6 +5358,7 @@ func (q *sqlQuerier) GetTemplatesWithFilter(ctx context.Context, arg GetTemplate
 			&i.TimeTilDormantAutoDelete,
 			&i.AutostopRequirementDaysOfWeek,
 			&i.AutostopRequirementWeeks,
+			&i.EgressPolicy,
 			&i.CreatedByAvatarURL,
 			&i.CreatedByUsername,
 		); err != nil {
@@ -4692,19 +5506,21 @@ SET
 	name = $4,
 	icon = $5,
 	display_name = $6,
-	allow_user_cancel_workspace_jobs = $7
+	allow_user_cancel_workspace_jobs = $7,
+	egress_policy = $8
 WHERE
 	id = $1
 `
 
 type UpdateTemplateMetaByIDParams struct {
-	ID                           uuid.UUID `db:"id" json:"id"`
-	UpdatedAt                    time.Time `db:"updated_at" json:"updated_at"`
-	Description                  string    `db:"description" json:"description"`
-	Name                         string    `db:"name" json:"name"`
-	Icon                         string    `db:"icon" json:"icon"`
-	DisplayName                  string    `db:"display_name" json:"display_name"`
-	AllowUserCancelWorkspaceJobs bool      `db:"allow_user_cancel_workspace_jobs" json:"allow_user_cancel_workspace_jobs"`
+	ID                           uuid.UUID    `db:"id" json:"id"`
+	UpdatedAt                    time.Time    `db:"updated_at" json:"updated_at"`
+	Description                  string       `db:"description" json:"description"`
+	Name                         string       `db:"name" json:"name"`
+	Icon                         string       `db:"icon" json:"icon"`
+	DisplayName                  string       `db:"display_name" json:"display_name"`
+	AllowUserCancelWorkspaceJobs bool         `db:"allow_user_cancel_workspace_jobs" json:"allow_user_cancel_workspace_jobs"`
+	EgressPolicy                 EgressPolicy `db:"egress_policy" json:"egress_policy"`
 }
 
 func (q *sqlQuerier) UpdateTemplateMetaByID(ctx context.Context, arg UpdateTemplateMetaByIDParams) error {
@@ -4716,6 +5532,7 @@ func (q *sqlQuerier) UpdateTemplateMetaByID(ctx context.Context, arg UpdateTempl
 		arg.Icon,
 		arg.DisplayName,
 		arg.AllowUserCancelWorkspaceJobs,
+		arg.EgressPolicy,
 	)
 	return err
 }
@@ -4770,7 +5587,7 @@ func (q *sqlQuerier) UpdateTemplateScheduleByID(ctx context.Context, arg UpdateT
 }
 
 const getTemplateVersionParameters = `-- name: GetTemplateVersionParameters :many
-SELECT template_version_id, name, description, type, mutable, default_value, icon, options, validation_regex, validation_min, validation_max, validation_error, validation_monotonic, required, display_name, display_order, ephemeral FROM template_version_parameters WHERE template_version_id = $1 ORDER BY display_order ASC, LOWER(name) ASC
+SELECT template_version_id, name, description, type, mutable, default_value, icon, options, validation_regex, validation_min, validation_max, validation_error, validation_monotonic, required, display_name, display_order, ephemeral, visible_if, validation_rules FROM template_version_parameters WHERE template_version_id = $1 ORDER BY display_order ASC, LOWER(name) ASC
 `
 
 func (q *sqlQuerier) GetTemplateVersionParameters(ctx context.Context, templateVersionID uuid.UUID) ([]TemplateVersionParameter, error) {
@@ -4800,6 +5617,8 @@ func (q *sqlQuerier) GetTemplateVersionParameters(ctx context.Context, templateV
 			&i.DisplayName,
 			&i.DisplayOrder,
 			&i.Ephemeral,
+			&i.VisibleIf,
+			&i.ValidationRules,
 		); err != nil {
 			return nil, err
 		}
@@ -4833,7 +5652,9 @@ INSERT INTO
         required,
         display_name,
         display_order,
-        ephemeral
+        ephemeral,
+        visible_if,
+        validation_rules
     )
 VALUES
     (
@@ -4853,8 +5674,10 @@ VALUES
         $14,
         $15,
         $16,
-        $17
-    ) RETURNING template_version_id, name, description, type, mutable, default_value, icon, options, validation_regex, validation_min, validation_max, validation_error, validation_monotonic, required, display_name, display_order, ephemeral
+        $17,
+        $18,
+        $19
+    ) RETURNING template_version_id, name, description, type, mutable, default_value, icon, options, validation_regex, validation_min, validation_max, validation_error, validation_monotonic, required, display_name, display_order, ephemeral, visible_if, validation_rules
 `
 
 type InsertTemplateVersionParameterParams struct {
@@ -4875,6 +5698,8 @@ type InsertTemplateVersionParameterParams struct {
 	DisplayName         string          `db:"display_name" json:"display_name"`
 	DisplayOrder        int32           `db:"display_order" json:"display_order"`
 	Ephemeral           bool            `db:"ephemeral" json:"ephemeral"`
+	VisibleIf           string          `db:"visible_if" json:"visible_if"`
+	ValidationRules     json.RawMessage `db:"validation_rules" json:"validation_rules"`
 }
 
 func (q *sqlQuerier) InsertTemplateVersionParameter(ctx context.Context, arg InsertTemplateVersionParameterParams) (TemplateVersionParameter, error) {
@@ -4896,6 +5721,8 @@ func (q *sqlQuerier) InsertTemplateVersionParameter(ctx context.Context, arg Ins
 		arg.DisplayName,
 		arg.DisplayOrder,
 		arg.Ephemeral,
+		arg.VisibleIf,
+		arg.ValidationRules,
 	)
 	var i TemplateVersionParameter
 	err := row.Scan(
@@ -4916,6 +5743,8 @@ func (q *sqlQuerier) InsertTemplateVersionParameter(ctx context.Context, arg Ins
 		&i.DisplayName,
 		&i.DisplayOrder,
 		&i.Ephemeral,
+		&i.VisibleIf,
+		&i.ValidationRules,
 	)
 	return i, err
 }
@@ -5450,15 +6279,20 @@ func (q *sqlQuerier) InsertTemplateVersionVariable(ctx context.Context, arg Inse
 
 const getUserLinkByLinkedID = `-- name: GetUserLinkByLinkedID :one
 SELECT
-	user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry
+	user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, provider_id
 FROM
 	user_links
 WHERE
-	linked_id = $1
+	linked_id = $1 AND provider_id = $2
 `
 
-func (q *sqlQuerier) GetUserLinkByLinkedID(ctx context.Context, linkedID string) (UserLink, error) {
-	row := q.db.QueryRowContext(ctx, getUserLinkByLinkedID, linkedID)
+type GetUserLinkByLinkedIDParams struct {
+	LinkedID   string `db:"linked_id" json:"linked_id"`
+	ProviderID string `db:"provider_id" json:"provider_id"`
+}
+
+func (q *sqlQuerier) GetUserLinkByLinkedID(ctx context.Context, arg GetUserLinkByLinkedIDParams) (UserLink, error) {
+	row := q.db.QueryRowContext(ctx, getUserLinkByLinkedID, arg.LinkedID, arg.ProviderID)
 	var i UserLink
 	err := row.Scan(
 		&i.UserID,
@@ -5467,26 +6301,28 @@ func (q *sqlQuerier) GetUserLinkByLinkedID(ctx context.Context, linkedID string)
 		&i.OAuthAccessToken,
 		&i.OAuthRefreshToken,
 		&i.OAuthExpiry,
+		&i.ProviderID,
 	)
 	return i, err
 }
 
 const getUserLinkByUserIDLoginType = `-- name: GetUserLinkByUserIDLoginType :one
 SELECT
-	user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry
+	user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, provider_id
 FROM
 	user_links
 WHERE
-	user_id = $1 AND login_type = $2
+	user_id = $1 AND login_type = $2 AND provider_id = $3
 `
 
 type GetUserLinkByUserIDLoginTypeParams struct {
-	UserID    uuid.UUID `db:"user_id" json:"user_id"`
-	LoginType LoginType `db:"login_type" json:"login_type"`
+	UserID     uuid.UUID `db:"user_id" json:"user_id"`
+	LoginType  LoginType `db:"login_type" json:"login_type"`
+	ProviderID string    `db:"provider_id" json:"provider_id"`
 }
 
 func (q *sqlQuerier) GetUserLinkByUserIDLoginType(ctx context.Context, arg GetUserLinkByUserIDLoginTypeParams) (UserLink, error) {
-	row := q.db.QueryRowContext(ctx, getUserLinkByUserIDLoginType, arg.UserID, arg.LoginType)
+	row := q.db.QueryRowContext(ctx, getUserLinkByUserIDLoginType, arg.UserID, arg.LoginType, arg.ProviderID)
 	var i UserLink
 	err := row.Scan(
 		&i.UserID,
@@ -5495,10 +6331,91 @@ func (q *sqlQuerier) GetUserLinkByUserIDLoginType(ctx context.Context, arg GetUs
 		&i.OAuthAccessToken,
 		&i.OAuthRefreshToken,
 		&i.OAuthExpiry,
+		&i.ProviderID,
 	)
 	return i, err
 }
 
+const getUserLinksByLoginType = `-- name: GetUserLinksByLoginType :many
+SELECT
+	user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, provider_id
+FROM
+	user_links
+WHERE
+	login_type = $1
+`
+
+func (q *sqlQuerier) GetUserLinksByLoginType(ctx context.Context, loginType LoginType) ([]UserLink, error) {
+	rows, err := q.db.QueryContext(ctx, getUserLinksByLoginType, loginType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UserLink
+	for rows.Next() {
+		var i UserLink
+		if err := rows.Scan(
+			&i.UserID,
+			&i.LoginType,
+			&i.LinkedID,
+			&i.OAuthAccessToken,
+			&i.OAuthRefreshToken,
+			&i.OAuthExpiry,
+			&i.ProviderID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserLinksByUserID = `-- name: GetUserLinksByUserID :many
+SELECT
+	user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, provider_id
+FROM
+	user_links
+WHERE
+	user_id = $1
+`
+
+func (q *sqlQuerier) GetUserLinksByUserID(ctx context.Context, userID uuid.UUID) ([]UserLink, error) {
+	rows, err := q.db.QueryContext(ctx, getUserLinksByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UserLink
+	for rows.Next() {
+		var i UserLink
+		if err := rows.Scan(
+			&i.UserID,
+			&i.LoginType,
+			&i.LinkedID,
+			&i.OAuthAccessToken,
+			&i.OAuthRefreshToken,
+			&i.OAuthExpiry,
+			&i.ProviderID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const insertUserLink = `-- name: InsertUserLink :one
 INSERT INTO
 	user_links (
@@ -5507,10 +6424,11 @@ INSERT INTO
 		linked_id,
 		oauth_access_token,
 		oauth_refresh_token,
-		oauth_expiry
+		oauth_expiry,
+		provider_id
 	)
 VALUES
-	( $1, $2, $3, $4, $5, $6 ) RETURNING user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry
+	( $1, $2, $3, $4, $5, $6, $7 ) RETURNING user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, provider_id
 `
 
 type InsertUserLinkParams struct {
@@ -5520,6 +6438,7 @@ type InsertUserLinkParams struct {
 	OAuthAccessToken  string    `db:"oauth_access_token" json:"oauth_access_token"`
 	OAuthRefreshToken string    `db:"oauth_refresh_token" json:"oauth_refresh_token"`
 	OAuthExpiry       time.Time `db:"oauth_expiry" json:"oauth_expiry"`
+	ProviderID        string    `db:"provider_id" json:"provider_id"`
 }
 
 func (q *sqlQuerier) InsertUserLink(ctx context.Context, arg InsertUserLinkParams) (UserLink, error) {
@@ -5530,6 +6449,7 @@ func (q *sqlQuerier) InsertUserLink(ctx context.Context, arg InsertUserLinkParam
 		arg.OAuthAccessToken,
 		arg.OAuthRefreshToken,
 		arg.OAuthExpiry,
+		arg.ProviderID,
 	)
 	var i UserLink
 	err := row.Scan(
@@ -5539,6 +6459,7 @@ func (q *sqlQuerier) InsertUserLink(ctx context.Context, arg InsertUserLinkParam
 		&i.OAuthAccessToken,
 		&i.OAuthRefreshToken,
 		&i.OAuthExpiry,
+		&i.ProviderID,
 	)
 	return i, err
 }
@@ -5551,7 +6472,7 @@ SET
 	oauth_refresh_token = $2,
 	oauth_expiry = $3
 WHERE
-	user_id = $4 AND login_type = $5 RETURNING user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry
+	user_id = $4 AND login_type = $5 AND provider_id = $6 RETURNING user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, provider_id
 `
 
 type UpdateUserLinkParams struct {
@@ -5560,6 +6481,7 @@ type UpdateUserLinkParams struct {
 	OAuthExpiry       time.Time `db:"oauth_expiry" json:"oauth_expiry"`
 	UserID            uuid.UUID `db:"user_id" json:"user_id"`
 	LoginType         LoginType `db:"login_type" json:"login_type"`
+	ProviderID        string    `db:"provider_id" json:"provider_id"`
 }
 
 func (q *sqlQuerier) UpdateUserLink(ctx context.Context, arg UpdateUserLinkParams) (UserLink, error) {
@@ -5569,6 +6491,7 @@ func (q *sqlQuerier) UpdateUserLink(ctx context.Context, arg UpdateUserLinkParam
 		arg.OAuthExpiry,
 		arg.UserID,
 		arg.LoginType,
+		arg.ProviderID,
 	)
 	var i UserLink
 	err := row.Scan(
@@ -5578,6 +6501,7 @@ func (q *sqlQuerier) UpdateUserLink(ctx context.Context, arg UpdateUserLinkParam
 		&i.OAuthAccessToken,
 		&i.OAuthRefreshToken,
 		&i.OAuthExpiry,
+		&i.ProviderID,
 	)
 	return i, err
 }
@@ -5588,17 +6512,18 @@ UPDATE
 SET
 	linked_id = $1
 WHERE
-	user_id = $2 AND login_type = $3 RETURNING user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry
+	user_id = $2 AND login_type = $3 AND provider_id = $4 RETURNING user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, provider_id
 `
 
 type UpdateUserLinkedIDParams struct {
-	LinkedID  string    `db:"linked_id" json:"linked_id"`
-	UserID    uuid.UUID `db:"user_id" json:"user_id"`
-	LoginType LoginType `db:"login_type" json:"login_type"`
+	LinkedID   string    `db:"linked_id" json:"linked_id"`
+	UserID     uuid.UUID `db:"user_id" json:"user_id"`
+	LoginType  LoginType `db:"login_type" json:"login_type"`
+	ProviderID string    `db:"provider_id" json:"provider_id"`
 }
 
 func (q *sqlQuerier) UpdateUserLinkedID(ctx context.Context, arg UpdateUserLinkedIDParams) (UserLink, error) {
-	row := q.db.QueryRowContext(ctx, updateUserLinkedID, arg.LinkedID, arg.UserID, arg.LoginType)
+	row := q.db.QueryRowContext(ctx, updateUserLinkedID, arg.LinkedID, arg.UserID, arg.LoginType, arg.ProviderID)
 	var i UserLink
 	err := row.Scan(
 		&i.UserID,
@@ -5607,6 +6532,286 @@ func (q *sqlQuerier) UpdateUserLinkedID(ctx context.Context, arg UpdateUserLinke
 		&i.OAuthAccessToken,
 		&i.OAuthRefreshToken,
 		&i.OAuthExpiry,
+		&i.ProviderID,
+	)
+	return i, err
+}
+
+const deleteUserMFAByUserID = `-- name: DeleteUserMFAByUserID :exec
+DELETE FROM user_mfa WHERE user_id = $1
+`
+
+func (q *sqlQuerier) DeleteUserMFAByUserID(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteUserMFAByUserID, userID)
+	return err
+}
+
+const deleteUserMFARecoveryCodesByUserID = `-- name: DeleteUserMFARecoveryCodesByUserID :exec
+DELETE FROM user_mfa_recovery_codes WHERE user_id = $1
+`
+
+func (q *sqlQuerier) DeleteUserMFARecoveryCodesByUserID(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteUserMFARecoveryCodesByUserID, userID)
+	return err
+}
+
+const enableUserMFA = `-- name: EnableUserMFA :one
+UPDATE user_mfa SET
+	updated_at = $2,
+	enabled_at = $2,
+	totp_last_used_step = $3
+WHERE user_id = $1 AND enabled_at IS NULL
+RETURNING user_id, created_at, updated_at, totp_secret, enabled_at, totp_last_used_step, failed_attempts, last_failed_at
+`
+
+type EnableUserMFAParams struct {
+	UserID           uuid.UUID `db:"user_id" json:"user_id"`
+	UpdatedAt        time.Time `db:"updated_at" json:"updated_at"`
+	TOTPLastUsedStep int64     `db:"totp_last_used_step" json:"totp_last_used_step"`
+}
+
+func (q *sqlQuerier) EnableUserMFA(ctx context.Context, arg EnableUserMFAParams) (UserMFA, error) {
+	row := q.db.QueryRowContext(ctx, enableUserMFA, arg.UserID, arg.UpdatedAt, arg.TOTPLastUsedStep)
+	var i UserMFA
+	err := row.Scan(
+		&i.UserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.TOTPSecret,
+		&i.EnabledAt,
+		&i.TOTPLastUsedStep,
+		&i.FailedAttempts,
+		&i.LastFailedAt,
+	)
+	return i, err
+}
+
+const getUserMFAByUserID = `-- name: GetUserMFAByUserID :one
+SELECT user_id, created_at, updated_at, totp_secret, enabled_at, totp_last_used_step, failed_attempts, last_failed_at FROM user_mfa WHERE user_id = $1
+`
+
+func (q *sqlQuerier) GetUserMFAByUserID(ctx context.Context, userID uuid.UUID) (UserMFA, error) {
+	row := q.db.QueryRowContext(ctx, getUserMFAByUserID, userID)
+	var i UserMFA
+	err := row.Scan(
+		&i.UserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.TOTPSecret,
+		&i.EnabledAt,
+		&i.TOTPLastUsedStep,
+		&i.FailedAttempts,
+		&i.LastFailedAt,
+	)
+	return i, err
+}
+
+const getUserMFARecoveryCodesByUserID = `-- name: GetUserMFARecoveryCodesByUserID :many
+SELECT id, user_id, created_at, used_at, hashed_code FROM user_mfa_recovery_codes WHERE user_id = $1 ORDER BY created_at ASC
+`
+
+func (q *sqlQuerier) GetUserMFARecoveryCodesByUserID(ctx context.Context, userID uuid.UUID) ([]UserMFARecoveryCode, error) {
+	rows, err := q.db.QueryContext(ctx, getUserMFARecoveryCodesByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UserMFARecoveryCode
+	for rows.Next() {
+		var i UserMFARecoveryCode
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.CreatedAt,
+			&i.UsedAt,
+			&i.HashedCode,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertUserMFARecoveryCode = `-- name: InsertUserMFARecoveryCode :one
+INSERT INTO user_mfa_recovery_codes (
+	id,
+	user_id,
+	created_at,
+	hashed_code
+) VALUES (
+	$1,
+	$2,
+	$3,
+	$4
+) RETURNING id, user_id, created_at, used_at, hashed_code
+`
+
+type InsertUserMFARecoveryCodeParams struct {
+	ID         uuid.UUID `db:"id" json:"id"`
+	UserID     uuid.UUID `db:"user_id" json:"user_id"`
+	CreatedAt  time.Time `db:"created_at" json:"created_at"`
+	HashedCode []byte    `db:"hashed_code" json:"hashed_code"`
+}
+
+func (q *sqlQuerier) InsertUserMFARecoveryCode(ctx context.Context, arg InsertUserMFARecoveryCodeParams) (UserMFARecoveryCode, error) {
+	row := q.db.QueryRowContext(ctx, insertUserMFARecoveryCode, arg.ID, arg.UserID, arg.CreatedAt, arg.HashedCode)
+	var i UserMFARecoveryCode
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.UsedAt,
+		&i.HashedCode,
+	)
+	return i, err
+}
+
+const recordUserMFAFailedAttempt = `-- name: RecordUserMFAFailedAttempt :one
+UPDATE user_mfa SET
+	failed_attempts = CASE WHEN last_failed_at > $1 :: timestamptz THEN failed_attempts + 1 ELSE 1 END,
+	last_failed_at = $2
+WHERE user_id = $3
+RETURNING user_id, created_at, updated_at, totp_secret, enabled_at, totp_last_used_step, failed_attempts, last_failed_at
+`
+
+type RecordUserMFAFailedAttemptParams struct {
+	FailedAfter  time.Time    `db:"failed_after" json:"failed_after"`
+	LastFailedAt sql.NullTime `db:"last_failed_at" json:"last_failed_at"`
+	UserID       uuid.UUID    `db:"user_id" json:"user_id"`
+}
+
+// Failures before @failed_after no longer count, so the counter restarts.
+func (q *sqlQuerier) RecordUserMFAFailedAttempt(ctx context.Context, arg RecordUserMFAFailedAttemptParams) (UserMFA, error) {
+	row := q.db.QueryRowContext(ctx, recordUserMFAFailedAttempt, arg.FailedAfter, arg.LastFailedAt, arg.UserID)
+	var i UserMFA
+	err := row.Scan(
+		&i.UserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.TOTPSecret,
+		&i.EnabledAt,
+		&i.TOTPLastUsedStep,
+		&i.FailedAttempts,
+		&i.LastFailedAt,
+	)
+	return i, err
+}
+
+const resetUserMFAFailedAttempts = `-- name: ResetUserMFAFailedAttempts :exec
+UPDATE user_mfa SET
+	failed_attempts = 0,
+	last_failed_at = NULL
+WHERE user_id = $1
+`
+
+func (q *sqlQuerier) ResetUserMFAFailedAttempts(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, resetUserMFAFailedAttempts, userID)
+	return err
+}
+
+const updateUserMFALastUsedStep = `-- name: UpdateUserMFALastUsedStep :one
+UPDATE user_mfa SET
+	totp_last_used_step = $2
+WHERE user_id = $1 AND totp_last_used_step < $2
+RETURNING user_id, created_at, updated_at, totp_secret, enabled_at, totp_last_used_step, failed_attempts, last_failed_at
+`
+
+type UpdateUserMFALastUsedStepParams struct {
+	UserID           uuid.UUID `db:"user_id" json:"user_id"`
+	TOTPLastUsedStep int64     `db:"totp_last_used_step" json:"totp_last_used_step"`
+}
+
+// Codes are only accepted once, so the step must increase.
+func (q *sqlQuerier) UpdateUserMFALastUsedStep(ctx context.Context, arg UpdateUserMFALastUsedStepParams) (UserMFA, error) {
+	row := q.db.QueryRowContext(ctx, updateUserMFALastUsedStep, arg.UserID, arg.TOTPLastUsedStep)
+	var i UserMFA
+	err := row.Scan(
+		&i.UserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.TOTPSecret,
+		&i.EnabledAt,
+		&i.TOTPLastUsedStep,
+		&i.FailedAttempts,
+		&i.LastFailedAt,
+	)
+	return i, err
+}
+
+const upsertUserMFA = `-- name: UpsertUserMFA :one
+INSERT INTO user_mfa (
+	user_id,
+	created_at,
+	updated_at,
+	totp_secret
+) VALUES (
+	$1,
+	$2,
+	$3,
+	$4
+) ON CONFLICT (user_id) DO UPDATE SET
+	created_at = $2,
+	updated_at = $3,
+	totp_secret = $4,
+	enabled_at = NULL,
+	totp_last_used_step = 0
+WHERE user_mfa.enabled_at IS NULL
+RETURNING user_id, created_at, updated_at, totp_secret, enabled_at, totp_last_used_step, failed_attempts, last_failed_at
+`
+
+type UpsertUserMFAParams struct {
+	UserID     uuid.UUID `db:"user_id" json:"user_id"`
+	CreatedAt  time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at" json:"updated_at"`
+	TOTPSecret string    `db:"totp_secret" json:"totp_secret"`
+}
+
+// Starts a new enrollment, replacing a previous pending one.
+func (q *sqlQuerier) UpsertUserMFA(ctx context.Context, arg UpsertUserMFAParams) (UserMFA, error) {
+	row := q.db.QueryRowContext(ctx, upsertUserMFA, arg.UserID, arg.CreatedAt, arg.UpdatedAt, arg.TOTPSecret)
+	var i UserMFA
+	err := row.Scan(
+		&i.UserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.TOTPSecret,
+		&i.EnabledAt,
+		&i.TOTPLastUsedStep,
+		&i.FailedAttempts,
+		&i.LastFailedAt,
+	)
+	return i, err
+}
+
+const useUserMFARecoveryCode = `-- name: UseUserMFARecoveryCode :one
+UPDATE user_mfa_recovery_codes SET
+	used_at = $3
+WHERE user_id = $1 AND hashed_code = $2 AND used_at IS NULL
+RETURNING id, user_id, created_at, used_at, hashed_code
+`
+
+type UseUserMFARecoveryCodeParams struct {
+	UserID     uuid.UUID    `db:"user_id" json:"user_id"`
+	HashedCode []byte       `db:"hashed_code" json:"hashed_code"`
+	UsedAt     sql.NullTime `db:"used_at" json:"used_at"`
+}
+
+func (q *sqlQuerier) UseUserMFARecoveryCode(ctx context.Context, arg UseUserMFARecoveryCodeParams) (UserMFARecoveryCode, error) {
+	row := q.db.QueryRowContext(ctx, useUserMFARecoveryCode, arg.UserID, arg.HashedCode, arg.UsedAt)
+	var i UserMFARecoveryCode
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.UsedAt,
+		&i.HashedCode,
 	)
 	return i, err
 }
@@ -5618,6 +6823,8 @@ FROM
 	users
 WHERE
 	status = 'active'::user_status AND deleted = false
+	-- Service accounts are not counted against the licensed user limit.
+	AND login_type != 'service_account'::login_type
 `
 
 func (q *sqlQuerier) GetActiveUserCount(ctx context.Context) (int64, error) {
@@ -5692,7 +6899,7 @@ func (q *sqlQuerier) GetAuthorizationUserRoles(ctx context.Context, userID uuid.
 
 const getUserByEmailOrUsername = `-- name: GetUserByEmailOrUsername :one
 SELECT
-	id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule
+	id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, service_account_owner_group_id
 FROM
 	users
 WHERE
@@ -5724,13 +6931,14 @@ func (q *sqlQuerier) GetUserByEmailOrUsername(ctx context.Context, arg GetUserBy
 		&i.Deleted,
 		&i.LastSeenAt,
 		&i.QuietHoursSchedule,
+		&i.ServiceAccountOwnerGroupID,
 	)
 	return i, err
 }
 
 const getUserByID = `-- name: GetUserByID :one
 SELECT
-	id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule
+	id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, service_account_owner_group_id
 FROM
 	users
 WHERE
@@ -5756,6 +6964,7 @@ func (q *sqlQuerier) GetUserByID(ctx context.Context, id uuid.UUID) (User, error
 		&i.Deleted,
 		&i.LastSeenAt,
 		&i.QuietHoursSchedule,
+		&i.ServiceAccountOwnerGroupID,
 	)
 	return i, err
 }
@@ -5778,7 +6987,7 @@ func (q *sqlQuerier) GetUserCount(ctx context.Context) (int64, error) {
 
 const getUsers = `-- name: GetUsers :many
 SELECT
-	id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, COUNT(*) OVER() AS count
+	id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, service_account_owner_group_id, COUNT(*) OVER() AS count
 FROM
 	users
 WHERE
@@ -5862,20 +7071,21 @@ type GetUsersParams struct {
 }
 
 type GetUsersRow struct {
-	ID                 uuid.UUID      `db:"id" json:"id"`
-	Email              string         `db:"email" json:"email"`
-	Username           string         `db:"username" json:"username"`
-	HashedPassword     []byte         `db:"hashed_password" json:"hashed_password"`
-	CreatedAt          time.Time      `db:"created_at" json:"created_at"`
-	UpdatedAt          time.Time      `db:"updated_at" json:"updated_at"`
-	Status             UserStatus     `db:"status" json:"status"`
-	RBACRoles          pq.StringArray `db:"rbac_roles" json:"rbac_roles"`
-	LoginType          LoginType      `db:"login_type" json:"login_type"`
-	AvatarURL          sql.NullString `db:"avatar_url" json:"avatar_url"`
-	Deleted            bool           `db:"deleted" json:"deleted"`
-	LastSeenAt         time.Time      `db:"last_seen_at" json:"last_seen_at"`
-	QuietHoursSchedule string         `db:"quiet_hours_schedule" json:"quiet_hours_schedule"`
-	Count              int64          `db:"count" json:"count"`
+	ID                         uuid.UUID      `db:"id" json:"id"`
+	Email                      string         `db:"email" json:"email"`
+	Username                   string         `db:"username" json:"username"`
+	HashedPassword             []byte         `db:"hashed_password" json:"hashed_password"`
+	CreatedAt                  time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt                  time.Time      `db:"updated_at" json:"updated_at"`
+	Status                     UserStatus     `db:"status" json:"status"`
+	RBACRoles                  pq.StringArray `db:"rbac_roles" json:"rbac_roles"`
+	LoginType                  LoginType      `db:"login_type" json:"login_type"`
+	AvatarURL                  sql.NullString `db:"avatar_url" json:"avatar_url"`
+	Deleted                    bool           `db:"deleted" json:"deleted"`
+	LastSeenAt                 time.Time      `db:"last_seen_at" json:"last_seen_at"`
+	QuietHoursSchedule         string         `db:"quiet_hours_schedule" json:"quiet_hours_schedule"`
+	ServiceAccountOwnerGroupID uuid.NullUUID  `db:"service_account_owner_group_id" json:"service_account_owner_group_id"`
+	Count                      int64          `db:"count" json:"count"`
 }
 
 // This will never return deleted users.
@@ -5911,6 +7121,7 @@ func (q *sqlQuerier) GetUsers(ctx context.Context, arg GetUsersParams) ([]GetUse
 			&i.Deleted,
 			&i.LastSeenAt,
 			&i.QuietHoursSchedule,
+			&i.ServiceAccountOwnerGroupID,
 			&i.Count,
 		); err != nil {
 			return nil, err
@@ -5927,7 +7138,7 @@ func (q *sqlQuerier) GetUsers(ctx context.Context, arg GetUsersParams) ([]GetUse
 }
 
 const getUsersByIDs = `-- name: GetUsersByIDs :many
-SELECT id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule FROM users WHERE id = ANY($1 :: uuid [ ])
+SELECT id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, service_account_owner_group_id FROM users WHERE id = ANY($1 :: uuid [ ])
 `
 
 // This shouldn't check for deleted, because it's frequently used
@@ -5956,6 +7167,7 @@ func (q *sqlQuerier) GetUsersByIDs(ctx context.Context, ids []uuid.UUID) ([]User
 			&i.Deleted,
 			&i.LastSeenAt,
 			&i.QuietHoursSchedule,
+			&i.ServiceAccountOwnerGroupID,
 		); err != nil {
 			return nil, err
 		}
@@ -5980,21 +7192,23 @@ INSERT INTO
 		created_at,
 		updated_at,
 		rbac_roles,
-		login_type
+		login_type,
+		service_account_owner_group_id
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule
+	($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, service_account_owner_group_id
 `
 
 type InsertUserParams struct {
-	ID             uuid.UUID      `db:"id" json:"id"`
-	Email          string         `db:"email" json:"email"`
-	Username       string         `db:"username" json:"username"`
-	HashedPassword []byte         `db:"hashed_password" json:"hashed_password"`
-	CreatedAt      time.Time      `db:"created_at" json:"created_at"`
-	UpdatedAt      time.Time      `db:"updated_at" json:"updated_at"`
-	RBACRoles      pq.StringArray `db:"rbac_roles" json:"rbac_roles"`
-	LoginType      LoginType      `db:"login_type" json:"login_type"`
+	ID                         uuid.UUID      `db:"id" json:"id"`
+	Email                      string         `db:"email" json:"email"`
+	Username                   string         `db:"username" json:"username"`
+	HashedPassword             []byte         `db:"hashed_password" json:"hashed_password"`
+	CreatedAt                  time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt                  time.Time      `db:"updated_at" json:"updated_at"`
+	RBACRoles                  pq.StringArray `db:"rbac_roles" json:"rbac_roles"`
+	LoginType                  LoginType      `db:"login_type" json:"login_type"`
+	ServiceAccountOwnerGroupID uuid.NullUUID  `db:"service_account_owner_group_id" json:"service_account_owner_group_id"`
 }
 
 func (q *sqlQuerier) InsertUser(ctx context.Context, arg InsertUserParams) (User, error) {
@@ -6007,6 +7221,7 @@ func (q *sqlQuerier) InsertUser(ctx context.Context, arg InsertUserParams) (User
 		arg.UpdatedAt,
 		arg.RBACRoles,
 		arg.LoginType,
+		arg.ServiceAccountOwnerGroupID,
 	)
 	var i User
 	err := row.Scan(
@@ -6023,6 +7238,7 @@ func (q *sqlQuerier) InsertUser(ctx context.Context, arg InsertUserParams) (User
 		&i.Deleted,
 		&i.LastSeenAt,
 		&i.QuietHoursSchedule,
+		&i.ServiceAccountOwnerGroupID,
 	)
 	return i, err
 }
@@ -6118,7 +7334,7 @@ SET
 	last_seen_at = $2,
 	updated_at = $3
 WHERE
-	id = $1 RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule
+	id = $1 RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, service_account_owner_group_id
 `
 
 type UpdateUserLastSeenAtParams struct {
@@ -6144,6 +7360,7 @@ func (q *sqlQuerier) UpdateUserLastSeenAt(ctx context.Context, arg UpdateUserLas
 		&i.Deleted,
 		&i.LastSeenAt,
 		&i.QuietHoursSchedule,
+		&i.ServiceAccountOwnerGroupID,
 	)
 	return i, err
 }
@@ -6161,7 +7378,7 @@ SET
 		'':: bytea
 	END
 WHERE
-	id = $2 RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule
+	id = $2 RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, service_account_owner_group_id
 `
 
 type UpdateUserLoginTypeParams struct {
@@ -6186,6 +7403,7 @@ func (q *sqlQuerier) UpdateUserLoginType(ctx context.Context, arg UpdateUserLogi
 		&i.Deleted,
 		&i.LastSeenAt,
 		&i.QuietHoursSchedule,
+		&i.ServiceAccountOwnerGroupID,
 	)
 	return i, err
 }
@@ -6199,7 +7417,7 @@ SET
 	avatar_url = $4,
 	updated_at = $5
 WHERE
-	id = $1 RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule
+	id = $1 RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, service_account_owner_group_id
 `
 
 type UpdateUserProfileParams struct {
@@ -6233,6 +7451,7 @@ func (q *sqlQuerier) UpdateUserProfile(ctx context.Context, arg UpdateUserProfil
 		&i.Deleted,
 		&i.LastSeenAt,
 		&i.QuietHoursSchedule,
+		&i.ServiceAccountOwnerGroupID,
 	)
 	return i, err
 }
@@ -6244,7 +7463,7 @@ SET
 	quiet_hours_schedule = $2
 WHERE
 	id = $1
-RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule
+RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, service_account_owner_group_id
 `
 
 type UpdateUserQuietHoursScheduleParams struct {
@@ -6269,6 +7488,7 @@ func (q *sqlQuerier) UpdateUserQuietHoursSchedule(ctx context.Context, arg Updat
 		&i.Deleted,
 		&i.LastSeenAt,
 		&i.QuietHoursSchedule,
+		&i.ServiceAccountOwnerGroupID,
 	)
 	return i, err
 }
@@ -6281,7 +7501,7 @@ SET
 	rbac_roles = ARRAY(SELECT DISTINCT UNNEST($1 :: text[]))
 WHERE
 	id = $2
-RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule
+RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, service_account_owner_group_id
 `
 
 type UpdateUserRolesParams struct {
@@ -6306,6 +7526,7 @@ func (q *sqlQuerier) UpdateUserRoles(ctx context.Context, arg UpdateUserRolesPar
 		&i.Deleted,
 		&i.LastSeenAt,
 		&i.QuietHoursSchedule,
+		&i.ServiceAccountOwnerGroupID,
 	)
 	return i, err
 }
@@ -6317,7 +7538,7 @@ SET
 	status = $2,
 	updated_at = $3
 WHERE
-	id = $1 RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule
+	id = $1 RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, service_account_owner_group_id
 `
 
 type UpdateUserStatusParams struct {
@@ -6343,6 +7564,7 @@ func (q *sqlQuerier) UpdateUserStatus(ctx context.Context, arg UpdateUserStatusP
 		&i.Deleted,
 		&i.LastSeenAt,
 		&i.QuietHoursSchedule,
+		&i.ServiceAccountOwnerGroupID,
 	)
 	return i, err
 }
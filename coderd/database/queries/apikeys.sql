@@ -44,7 +44,10 @@ INSERT INTO
 		updated_at,
 		login_type,
 		scope,
-		token_name
+		token_name,
+		scopes,
+		allow_list,
+		provider_id
 	)
 VALUES
 	(@id,
@@ -53,7 +56,7 @@ VALUES
 	     WHEN 0 THEN 86400
 		 ELSE @lifetime_seconds::bigint
 	 END
-	 , @hashed_secret, @ip_address, @user_id, @last_used, @expires_at, @created_at, @updated_at, @login_type, @scope, @token_name) RETURNING *;
+	 , @hashed_secret, @ip_address, @user_id, @last_used, @expires_at, @created_at, @updated_at, @login_type, @scope, @token_name, @scopes, @allow_list, @provider_id) RETURNING *;
 
 -- name: UpdateAPIKeyByID :exec
 UPDATE
@@ -9,6 +9,7 @@ SELECT
     users.status AS user_status,
     users.rbac_roles AS user_roles,
     users.avatar_url AS user_avatar_url,
+    users.login_type AS user_login_type,
     COUNT(audit_logs.*) OVER () AS count
 FROM
     audit_logs
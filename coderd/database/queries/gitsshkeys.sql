@@ -5,10 +5,13 @@ INSERT INTO
 		created_at,
 		updated_at,
 		private_key,
-		public_key
+		public_key,
+		name,
+		imported,
+		hosts
 	)
 VALUES
-	($1, $2, $3, $4, $5) RETURNING *;
+	($1, $2, $3, $4, $5, $6, $7, $8) RETURNING *;
 
 -- name: GetGitSSHKey :one
 SELECT
@@ -16,17 +19,30 @@ SELECT
 FROM
 	gitsshkeys
 WHERE
-	user_id = $1;
+	user_id = $1
+	AND name = $2;
+
+-- name: GetGitSSHKeysByUserID :many
+SELECT
+	*
+FROM
+	gitsshkeys
+WHERE
+	user_id = $1
+ORDER BY
+	created_at ASC, name ASC;
 
 -- name: UpdateGitSSHKey :one
 UPDATE
 	gitsshkeys
 SET
-	updated_at = $2,
-	private_key = $3,
-	public_key = $4
+	updated_at = $3,
+	private_key = $4,
+	public_key = $5,
+	imported = $6
 WHERE
 	user_id = $1
+	AND name = $2
 RETURNING
 	*;
 
@@ -34,4 +50,5 @@ RETURNING
 DELETE FROM
 	gitsshkeys
 WHERE
-	user_id = $1;
+	user_id = $1
+	AND name = $2;
@@ -15,3 +15,13 @@ INSERT INTO
 	)
 VALUES
 	($1, $2, $3, $4, $5) RETURNING *;
+
+-- name: UpdateProvisionerDaemonLastSeenAt :exec
+UPDATE
+	provisioner_daemons
+SET
+	last_seen_at = @last_seen_at,
+	version = @version,
+	provisioner_versions = @provisioner_versions
+WHERE
+	id = @id;
@@ -50,32 +50,83 @@ WHERE
 -- name: GetProvisionerJobsByIDsWithQueuePosition :many
 WITH unstarted_jobs AS (
     SELECT
-        id, created_at
+        id, created_at, provisioner, tags
     FROM
         provisioner_jobs
     WHERE
         started_at IS NULL
 ),
+-- Provisioner daemons acquire jobs with a subset of their tags, so every
+-- daemon that can acquire a job can acquire the jobs with the same
+-- provisioner and a subset of its tags as well. The job waits behind those.
 queue_position AS (
     SELECT
-        id,
-        ROW_NUMBER() OVER (ORDER BY created_at ASC) AS queue_position
+        job.id,
+        COUNT(*) FILTER (
+            WHERE (other.created_at, other.id) <= (job.created_at, job.id)
+        ) AS queue_position,
+        COUNT(*) AS queue_size
     FROM
-        unstarted_jobs
+        unstarted_jobs job
+    INNER JOIN
+        unstarted_jobs other
+    ON
+        other.provisioner = job.provisioner
+        AND other.tags <@ job.tags
+    WHERE
+        job.id = ANY(@ids :: uuid [ ])
+    GROUP BY
+        job.id
 ),
-queue_size AS (
-	SELECT COUNT(*) as count FROM unstarted_jobs
+-- Healthy provisioner daemons that are able to acquire the job. The interval
+-- matches database.ProvisionerDaemonStaleTimeout.
+matching_daemons AS (
+    SELECT
+        pj.id AS job_id,
+        pd.id AS daemon_id
+    FROM
+        provisioner_jobs pj
+    INNER JOIN
+        provisioner_daemons pd
+    ON
+        pj.provisioner = ANY(pd.provisioners)
+        AND pj.tags <@ pd.tags
+        AND pd.last_seen_at > NOW() - INTERVAL '90 seconds'
+    WHERE
+        pj.id = ANY(@ids :: uuid [ ])
 )
 SELECT
 	sqlc.embed(pj),
     COALESCE(qp.queue_position, 0) AS queue_position,
-    COALESCE(qs.count, 0) AS queue_size
+    COALESCE(qp.queue_size, 0) AS queue_size,
+    (
+        SELECT
+            COUNT(*)
+        FROM
+            matching_daemons md
+        WHERE
+            md.job_id = pj.id
+    ) AS matching_daemons,
+    -- The number of jobs that the matching daemons are running, which have
+    -- to complete before the daemons acquire queued jobs.
+    (
+        SELECT
+            COUNT(*)
+        FROM
+            provisioner_jobs running
+        INNER JOIN
+            matching_daemons md
+        ON
+            md.daemon_id = running.worker_id
+        WHERE
+            md.job_id = pj.id
+            AND running.started_at IS NOT NULL
+            AND running.completed_at IS NULL
+    ) AS running_jobs
 FROM
 	provisioner_jobs pj
 LEFT JOIN
 	queue_position qp ON qp.id = pj.id
-LEFT JOIN
-	queue_size qs ON TRUE
 WHERE
 	pj.id = ANY(@ids :: uuid [ ]);
 
@@ -138,3 +189,31 @@ WHERE
 	updated_at < $1
 	AND started_at IS NOT NULL
 	AND completed_at IS NULL;
+
+-- name: GetProvisionerJobsRunningByWorkerIDs :many
+SELECT
+	*
+FROM
+	provisioner_jobs
+WHERE
+	worker_id = ANY(@worker_ids :: uuid [ ])
+	AND started_at IS NOT NULL
+	AND completed_at IS NULL;
+
+-- Returns the provisioner and tags of the job that imported the active
+-- version of every template. These are the requirements a provisioner daemon
+-- must satisfy to build workspaces from the template.
+-- name: GetActiveTemplateVersionJobTags :many
+SELECT
+	templates.id AS template_id,
+	templates.name AS template_name,
+	provisioner_jobs.provisioner,
+	provisioner_jobs.tags
+FROM
+	templates
+JOIN
+	template_versions ON template_versions.id = templates.active_version_id
+JOIN
+	provisioner_jobs ON provisioner_jobs.id = template_versions.job_id
+WHERE
+	templates.deleted = false;
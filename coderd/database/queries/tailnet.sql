@@ -9,7 +9,7 @@ INSERT INTO
 )
 VALUES
 	($1, $2, $3, $4, now() at time zone 'utc')
-ON CONFLICT (id, coordinator_id)
+ON CONFLICT (id, coordinator_id, agent_id)
 DO UPDATE SET
 	id = $1,
 	coordinator_id = $2,
@@ -40,7 +40,7 @@ RETURNING *;
 -- name: DeleteTailnetClient :one
 DELETE
 FROM tailnet_clients
-WHERE id = $1 and coordinator_id = $2
+WHERE id = $1 and coordinator_id = $2 and agent_id = $3
 RETURNING id, coordinator_id;
 
 -- name: DeleteTailnetAgent :one
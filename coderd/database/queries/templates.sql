@@ -104,7 +104,8 @@ SET
 	name = $4,
 	icon = $5,
 	display_name = $6,
-	allow_user_cancel_workspace_jobs = $7
+	allow_user_cancel_workspace_jobs = $7,
+	egress_policy = $8
 WHERE
 	id = $1
 ;
@@ -4,7 +4,7 @@ SELECT
 FROM
 	user_links
 WHERE
-	linked_id = $1;
+	linked_id = $1 AND provider_id = $2;
 
 -- name: GetUserLinkByUserIDLoginType :one
 SELECT
@@ -12,7 +12,23 @@ SELECT
 FROM
 	user_links
 WHERE
-	user_id = $1 AND login_type = $2;
+	user_id = $1 AND login_type = $2 AND provider_id = $3;
+
+-- name: GetUserLinksByLoginType :many
+SELECT
+	*
+FROM
+	user_links
+WHERE
+	login_type = $1;
+
+-- name: GetUserLinksByUserID :many
+SELECT
+	*
+FROM
+	user_links
+WHERE
+	user_id = $1;
 
 -- name: InsertUserLink :one
 INSERT INTO
@@ -22,10 +38,11 @@ INSERT INTO
 		linked_id,
 		oauth_access_token,
 		oauth_refresh_token,
-		oauth_expiry
+		oauth_expiry,
+		provider_id
 	)
 VALUES
-	( $1, $2, $3, $4, $5, $6 ) RETURNING *;
+	( $1, $2, $3, $4, $5, $6, $7 ) RETURNING *;
 
 -- name: UpdateUserLinkedID :one
 UPDATE
@@ -33,7 +50,7 @@ UPDATE
 SET
 	linked_id = $1
 WHERE
-	user_id = $2 AND login_type = $3 RETURNING *;
+	user_id = $2 AND login_type = $3 AND provider_id = $4 RETURNING *;
 
 -- name: UpdateUserLink :one
 UPDATE
@@ -43,4 +60,4 @@ SET
 	oauth_refresh_token = $2,
 	oauth_expiry = $3
 WHERE
-	user_id = $4 AND login_type = $5 RETURNING *;
+	user_id = $4 AND login_type = $5 AND provider_id = $6 RETURNING *;
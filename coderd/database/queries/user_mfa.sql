@@ -0,0 +1,81 @@
+-- name: GetUserMFAByUserID :one
+SELECT * FROM user_mfa WHERE user_id = $1;
+
+-- name: UpsertUserMFA :one
+-- Starts a new enrollment, replacing a previous pending one. No row is
+-- returned if the user already enabled MFA.
+INSERT INTO user_mfa (
+	user_id,
+	created_at,
+	updated_at,
+	totp_secret
+) VALUES (
+	$1,
+	$2,
+	$3,
+	$4
+) ON CONFLICT (user_id) DO UPDATE SET
+	created_at = $2,
+	updated_at = $3,
+	totp_secret = $4,
+	enabled_at = NULL,
+	totp_last_used_step = 0
+WHERE user_mfa.enabled_at IS NULL
+RETURNING *;
+
+-- name: EnableUserMFA :one
+UPDATE user_mfa SET
+	updated_at = $2,
+	enabled_at = $2,
+	totp_last_used_step = $3
+WHERE user_id = $1 AND enabled_at IS NULL
+RETURNING *;
+
+-- name: UpdateUserMFALastUsedStep :one
+-- Codes are only accepted once, so the step must increase.
+UPDATE user_mfa SET
+	totp_last_used_step = $2
+WHERE user_id = $1 AND totp_last_used_step < $2
+RETURNING *;
+
+-- name: RecordUserMFAFailedAttempt :one
+-- Failures before @failed_after no longer count, so the counter restarts.
+UPDATE user_mfa SET
+	failed_attempts = CASE WHEN last_failed_at > @failed_after :: timestamptz THEN failed_attempts + 1 ELSE 1 END,
+	last_failed_at = @last_failed_at
+WHERE user_id = @user_id
+RETURNING *;
+
+-- name: ResetUserMFAFailedAttempts :exec
+UPDATE user_mfa SET
+	failed_attempts = 0,
+	last_failed_at = NULL
+WHERE user_id = $1;
+
+-- name: DeleteUserMFAByUserID :exec
+DELETE FROM user_mfa WHERE user_id = $1;
+
+-- name: GetUserMFARecoveryCodesByUserID :many
+SELECT * FROM user_mfa_recovery_codes WHERE user_id = $1 ORDER BY created_at ASC;
+
+-- name: InsertUserMFARecoveryCode :one
+INSERT INTO user_mfa_recovery_codes (
+	id,
+	user_id,
+	created_at,
+	hashed_code
+) VALUES (
+	$1,
+	$2,
+	$3,
+	$4
+) RETURNING *;
+
+-- name: UseUserMFARecoveryCode :one
+UPDATE user_mfa_recovery_codes SET
+	used_at = $3
+WHERE user_id = $1 AND hashed_code = $2 AND used_at IS NULL
+RETURNING *;
+
+-- name: DeleteUserMFARecoveryCodesByUserID :exec
+DELETE FROM user_mfa_recovery_codes WHERE user_id = $1;
@@ -54,7 +54,9 @@ SELECT
 FROM
 	users
 WHERE
-	status = 'active'::user_status AND deleted = false;
+	status = 'active'::user_status AND deleted = false
+	-- Service accounts are not counted against the licensed user limit.
+	AND login_type != 'service_account'::login_type;
 
 -- name: InsertUser :one
 INSERT INTO
@@ -66,10 +68,11 @@ INSERT INTO
 		created_at,
 		updated_at,
 		rbac_roles,
-		login_type
+		login_type,
+		service_account_owner_group_id
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8) RETURNING *;
+	($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING *;
 
 -- name: UpdateUserProfile :one
 UPDATE
@@ -58,6 +58,33 @@ func (t TemplateACL) Value() (driver.Value, error) {
 	return json.Marshal(t)
 }
 
+// EgressPolicy restricts the destinations that workspace agents forward
+// connections to. It's stored as JSON in the format of codersdk.EgressPolicy.
+type EgressPolicy struct {
+	DefaultAction string       `json:"default_action,omitempty"`
+	Rules         []EgressRule `json:"rules,omitempty"`
+}
+
+type EgressRule struct {
+	Action string   `json:"action"`
+	CIDR   string   `json:"cidr,omitempty"`
+	Ports  []string `json:"ports,omitempty"`
+}
+
+func (p *EgressPolicy) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case string:
+		return json.Unmarshal([]byte(v), &p)
+	case []byte:
+		return json.Unmarshal(v, &p)
+	}
+	return xerrors.Errorf("unexpected type %T", src)
+}
+
+func (p EgressPolicy) Value() (driver.Value, error) {
+	return json.Marshal(p)
+}
+
 type StringMap map[string]string
 
 func (m *StringMap) Scan(src interface{}) error {
@@ -5,10 +5,15 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/google/uuid"
+	"tailscale.com/ipn/ipnstate"
+
 	"github.com/coder/coder/v2/coderd/healthcheck"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/util/ptr"
 	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/tailnet"
 )
 
 // @Summary Debug Info Wireguard Coordinator
@@ -22,6 +27,107 @@ func (api *API) debugCoordinator(rw http.ResponseWriter, r *http.Request) {
 	(*api.TailnetCoordinator.Load()).ServeHTTPDebug(rw, r)
 }
 
+// @Summary Debug tailnet status
+// @ID debug-tailnet-status
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Debug
+// @Success 200 {object} codersdk.TailnetStatus
+// @Router /debug/tailnet [get]
+func (api *API) debugTailnetStatus(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	status, err := (*api.TailnetCoordinator.Load()).Status(ctx)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching coordinator status.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	// Coordinators that are shared by replicas don't know the names of
+	// agents on other replicas.
+	for i, peer := range status.Peers {
+		if !peer.Agent || peer.Name != "" {
+			continue
+		}
+		agent, err := api.Database.GetWorkspaceAgentByID(ctx, peer.ID)
+		if err != nil {
+			continue
+		}
+		status.Peers[i].Name = agent.Name
+	}
+
+	var conns *ipnstate.Status
+	if serverTailnet, ok := api.agentProvider.(*ServerTailnet); ok {
+		conns = serverTailnet.Status()
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, convertTailnetStatus(api.ID, status, conns))
+}
+
+func convertTailnetStatus(replicaID uuid.UUID, status *tailnet.CoordinatorStatus, conns *ipnstate.Status) codersdk.TailnetStatus {
+	res := codersdk.TailnetStatus{
+		HA:        status.HA,
+		ReplicaID: replicaID,
+		Peers:     make([]codersdk.TailnetPeer, 0, len(status.Peers)),
+		Tunnels:   make([]codersdk.TailnetTunnel, 0, len(status.Tunnels)),
+	}
+	for _, peer := range status.Peers {
+		p := codersdk.TailnetPeer{
+			ID:        peer.ID,
+			Name:      peer.Name,
+			Type:      codersdk.TailnetPeerTypeClient,
+			Connected: peer.Connected,
+			Endpoints: []string{},
+		}
+		if peer.Agent {
+			p.Type = codersdk.TailnetPeerTypeAgent
+		}
+		if peer.CoordinatorID != uuid.Nil {
+			p.CoordinatorID = ptr.Ref(peer.CoordinatorID)
+		}
+		if !peer.LastUpdate.IsZero() {
+			p.LastNodeUpdateAt = ptr.Ref(peer.LastUpdate)
+		}
+		if peer.Node != nil {
+			p.PreferredDERP = peer.Node.PreferredDERP
+			if peer.Node.Endpoints != nil {
+				p.Endpoints = peer.Node.Endpoints
+			}
+			if conns != nil {
+				if ps, ok := conns.Peer[peer.Node.Key]; ok {
+					p.Connection = convertTailnetConnection(ps)
+				}
+			}
+		}
+		res.Peers = append(res.Peers, p)
+	}
+	for _, tunnel := range status.Tunnels {
+		res.Tunnels = append(res.Tunnels, codersdk.TailnetTunnel{
+			Src: tunnel.Src,
+			Dst: tunnel.Dst,
+		})
+	}
+	return res
+}
+
+func convertTailnetConnection(ps *ipnstate.PeerStatus) *codersdk.TailnetConnection {
+	conn := &codersdk.TailnetConnection{
+		Path:    codersdk.TailnetPathDERP,
+		Relay:   ps.Relay,
+		RxBytes: ps.RxBytes,
+		TxBytes: ps.TxBytes,
+	}
+	if ps.CurAddr != "" {
+		conn.Path = codersdk.TailnetPathDirect
+		conn.Address = ps.CurAddr
+	}
+	if !ps.LastHandshake.IsZero() {
+		conn.LastHandshakeAt = ptr.Ref(ps.LastHandshake)
+	}
+	return conn
+}
+
 // @Summary Debug Info Deployment Health
 // @ID debug-info-deployment-health
 // @Security CoderSessionToken
@@ -7,11 +7,18 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/agent"
 	"github.com/coder/coder/v2/coderd/coderdtest"
 	"github.com/coder/coder/v2/coderd/healthcheck"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/provisioner/echo"
 	"github.com/coder/coder/v2/testutil"
 )
 
@@ -108,6 +115,77 @@ func TestDebugHealth(t *testing.T) {
 	})
 }
 
+func TestDebugTailnetStatus(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{
+		IncludeProvisionerDaemon: true,
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+	authToken := uuid.NewString()
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:          echo.ParseComplete,
+		ProvisionPlan:  echo.PlanComplete,
+		ProvisionApply: echo.ProvisionApplyWithAgent(authToken),
+	})
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+	agentClient := agentsdk.New(client.URL)
+	agentClient.SetSessionToken(authToken)
+	agentCloser := agent.New(agent.Options{
+		Client: agentClient,
+		Logger: slogtest.Make(t, nil).Named("agent").Leveled(slog.LevelDebug),
+	})
+	defer func() {
+		_ = agentCloser.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	resources := coderdtest.AwaitWorkspaceAgents(t, client, workspace.ID)
+	agentID := resources[0].Agents[0].ID
+	conn, err := client.DialWorkspaceAgent(ctx, agentID, nil)
+	require.NoError(t, err)
+	defer func() {
+		_ = conn.Close()
+	}()
+	require.True(t, conn.AwaitReachable(ctx))
+
+	var status codersdk.TailnetStatus
+	require.Eventually(t, func() bool {
+		status, err = client.DebugTailnetStatus(ctx)
+		if !assert.NoError(t, err) {
+			return false
+		}
+		return len(status.Tunnels) > 0
+	}, testutil.WaitShort, testutil.IntervalFast)
+
+	require.False(t, status.HA)
+	require.NotEmpty(t, status.Peers)
+	agentPeer := status.Peers[0]
+	require.Equal(t, agentID, agentPeer.ID)
+	require.Equal(t, codersdk.TailnetPeerTypeAgent, agentPeer.Type)
+	require.Contains(t, agentPeer.Name, resources[0].Agents[0].Name)
+	require.True(t, agentPeer.Connected)
+	require.NotNil(t, agentPeer.LastNodeUpdateAt)
+	for _, tunnel := range status.Tunnels {
+		require.Equal(t, agentID, tunnel.Dst)
+	}
+
+	t.Run("NotOwner", func(t *testing.T) {
+		t.Parallel()
+		member, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		_, err := member.DebugTailnetStatus(ctx)
+		var sdkErr *codersdk.Error
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, http.StatusNotFound, sdkErr.StatusCode())
+	})
+}
+
 func TestDebugWebsocket(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,43 @@
+package devtunnel
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/wgtunnel/tunnelsdk"
+)
+
+// ReadOrGenerateServerKey reads the base64 encoded wireguard private key of a
+// tunnel server from the given file. If the file doesn't exist, a key is
+// generated and written to it, so the tunnel URLs of clients stay the same
+// across restarts of the server.
+func ReadOrGenerateServerKey(path string) (tunnelsdk.Key, error) {
+	raw, err := os.ReadFile(path)
+	if err == nil {
+		key, err := tunnelsdk.ParsePrivateKey(strings.TrimSpace(string(raw)))
+		if err != nil {
+			return tunnelsdk.Key{}, xerrors.Errorf("parse private key in %q: %w", path, err)
+		}
+		return key, nil
+	}
+	if !os.IsNotExist(err) {
+		return tunnelsdk.Key{}, xerrors.Errorf("read private key: %w", err)
+	}
+
+	key, err := tunnelsdk.GeneratePrivateKey()
+	if err != nil {
+		return tunnelsdk.Key{}, xerrors.Errorf("generate private key: %w", err)
+	}
+	err = os.MkdirAll(filepath.Dir(path), 0o700)
+	if err != nil {
+		return tunnelsdk.Key{}, xerrors.Errorf("mkdirall %q: %w", filepath.Dir(path), err)
+	}
+	err = os.WriteFile(path, []byte(key.String()), 0o600)
+	if err != nil {
+		return tunnelsdk.Key{}, xerrors.Errorf("write private key: %w", err)
+	}
+	return key, nil
+}
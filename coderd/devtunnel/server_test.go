@@ -0,0 +1,39 @@
+package devtunnel_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/devtunnel"
+)
+
+func TestReadOrGenerateServerKey(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Generate", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "nested", "key")
+		key, err := devtunnel.ReadOrGenerateServerKey(path)
+		require.NoError(t, err)
+		require.True(t, key.IsPrivate())
+
+		// The key is kept across calls.
+		read, err := devtunnel.ReadOrGenerateServerKey(path)
+		require.NoError(t, err)
+		require.Equal(t, key.String(), read.String())
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "key")
+		err := os.WriteFile(path, []byte("not a key"), 0o600)
+		require.NoError(t, err)
+		_, err = devtunnel.ReadOrGenerateServerKey(path)
+		require.Error(t, err)
+	})
+}
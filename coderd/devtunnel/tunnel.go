@@ -112,6 +112,22 @@ func readOrGenerateConfig(customTunnelHost string) (Config, error) {
 		return Config{}, xerrors.Errorf("unmarshal config: %w", err)
 	}
 
+	// The tunnel host changed, so the cached node is of no use. The tunnel
+	// URL changes with the host anyway, so start over.
+	if customTunnelHost != "" && cfg.Tunnel.HostnameHTTPS != customTunnelHost {
+		cfg, err := GenerateConfig(customTunnelHost)
+		if err != nil {
+			return Config{}, xerrors.Errorf("generate config: %w", err)
+		}
+
+		err = writeConfig(cfg)
+		if err != nil {
+			return Config{}, xerrors.Errorf("write config: %w", err)
+		}
+
+		return cfg, nil
+	}
+
 	if cfg.Version == 0 {
 		_, _ = fmt.Println()
 		_, _ = fmt.Println(cliui.DefaultStyles.Error.Render("You're running a deprecated tunnel version!"))
@@ -145,6 +161,22 @@ func GenerateConfig(customTunnelHost string) (Config, error) {
 	}
 	pubNoisePublicKey := priv.NoisePublicKey()
 
+	// A custom tunnel host is often a self-hosted tunnel server that can't be
+	// pinged, and falling back to a hosted node would defeat its purpose.
+	if customTunnelHost != "" {
+		nodes, err := Nodes(customTunnelHost)
+		if err != nil {
+			return Config{}, xerrors.Errorf("get nodes: %w", err)
+		}
+		_, _ = fmt.Printf("Using tunnel at %s.\n", cliui.DefaultStyles.Keyword.Render(customTunnelHost))
+		return Config{
+			Version:    tunnelsdk.TunnelVersion2,
+			PrivateKey: privNoisePublicKey,
+			PublicKey:  pubNoisePublicKey,
+			Tunnel:     nodes[0],
+		}, nil
+	}
+
 	spin := spinner.New(spinner.CharSets[39], 350*time.Millisecond)
 	spin.Suffix = " Finding the closest tunnel region..."
 	spin.Start()
@@ -1,6 +1,7 @@
-package gitauth
+package externalauth
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -8,6 +9,7 @@ import (
 	"net/http"
 	"net/url"
 	"regexp"
+	"strings"
 	"time"
 
 	"golang.org/x/oauth2"
@@ -28,15 +30,23 @@ type OAuth2Config interface {
 	TokenSource(context.Context, *oauth2.Token) oauth2.TokenSource
 }
 
-// Config is used for authentication for Git operations.
+// Config is used for authentication with external OAuth2 providers, such
+// as Git hosts, issue trackers or cloud consoles.
 type Config struct {
 	OAuth2Config
 	// ID is a unique identifier for the authenticator.
 	ID string
-	// Regex is a regexp that URLs will match against.
+	// Regex is a regexp that Git URLs will match against. It is nil for
+	// providers that are not Git hosts, which are never used to
+	// authenticate Git operations.
 	Regex *regexp.Regexp
-	// Type is the type of provider.
+	// Type is the type of provider. Types other than the known Git
+	// providers are generic OAuth2 providers.
 	Type codersdk.GitProvider
+	// DisplayName is shown to users when they authenticate.
+	DisplayName string
+	// DisplayIcon is a URL to an icon shown next to the display name.
+	DisplayIcon string
 	// NoRefresh stops Coder from using the refresh token
 	// to renew the access token.
 	//
@@ -55,6 +65,9 @@ type Config struct {
 	// InstallationsURL is an API endpoint that returns a list of
 	// installations for the user. This is used for GitHub Apps.
 	AppInstallationsURL string
+	// SSHKeysURL is an API endpoint that public SSH keys are uploaded to.
+	// Uploading keys is unsupported if empty.
+	SSHKeysURL string
 	// DeviceAuth is set if the provider uses the device flow.
 	DeviceAuth *DeviceAuth
 }
@@ -220,24 +233,56 @@ func (c *Config) AppInstallations(ctx context.Context, token string) ([]codersdk
 	return installs, true, nil
 }
 
+// UploadSSHKey registers a public SSH key with the provider for the user that
+// owns the token. The title is shown to the user by the provider.
+func (c *Config) UploadSSHKey(ctx context.Context, token string, title string, publicKey string) error {
+	if c.SSHKeysURL == "" {
+		return xerrors.Errorf("provider %q does not support uploading ssh keys", c.ID)
+	}
+	body, err := json.Marshal(map[string]string{
+		"title": title,
+		"key":   strings.TrimSpace(publicKey),
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.SSHKeysURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	req.Header.Set("Content-Type", "application/json")
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusCreated {
+		data, _ := io.ReadAll(res.Body)
+		return xerrors.Errorf("status %d: body: %s", res.StatusCode, data)
+	}
+	return nil
+}
+
+// IsGit returns true if the provider is used to authenticate Git
+// operations.
+func (c *Config) IsGit() bool {
+	return c.Regex != nil
+}
+
 // ConvertConfig converts the SDK configuration entry format
 // to the parsed and ready-to-consume in coderd provider type.
 func ConvertConfig(entries []codersdk.GitAuthConfig, accessURL *url.URL) ([]*Config, error) {
 	ids := map[string]struct{}{}
 	configs := []*Config{}
 	for _, entry := range entries {
-		var typ codersdk.GitProvider
-		switch codersdk.GitProvider(entry.Type) {
-		case codersdk.GitProviderAzureDevops:
-			typ = codersdk.GitProviderAzureDevops
-		case codersdk.GitProviderBitBucket:
-			typ = codersdk.GitProviderBitBucket
-		case codersdk.GitProviderGitHub:
-			typ = codersdk.GitProviderGitHub
-		case codersdk.GitProviderGitLab:
-			typ = codersdk.GitProviderGitLab
-		default:
-			return nil, xerrors.Errorf("unknown git provider type: %q", entry.Type)
+		typ := codersdk.GitProvider(entry.Type)
+		if typ == "" {
+			return nil, xerrors.New("type must be provided for each external auth provider")
+		}
+		_, knownGitProvider := endpoint[typ]
+		if !knownGitProvider && (entry.AuthURL == "" || entry.TokenURL == "") {
+			return nil, xerrors.Errorf("%q is not a known git provider type: auth_url and token_url must be provided", entry.Type)
 		}
 		if entry.ID == "" {
 			// Default to the type.
@@ -294,6 +339,14 @@ func ConvertConfig(entries []codersdk.GitAuthConfig, accessURL *url.URL) ([]*Con
 		if entry.AppInstallationsURL == "" {
 			entry.AppInstallationsURL = appInstallationsURL[typ]
 		}
+		if entry.SSHKeysURL == "" && entry.AuthURL == "" {
+			// The default is only used for the SaaS host, since keys
+			// must not be sent to a different host than the provider.
+			entry.SSHKeysURL = sshKeysURL[typ]
+		}
+		if entry.DisplayName == "" {
+			entry.DisplayName = typ.Pretty()
+		}
 
 		var oauthConfig OAuth2Config = oc
 		// Azure DevOps uses JWT token authentication!
@@ -306,10 +359,13 @@ func ConvertConfig(entries []codersdk.GitAuthConfig, accessURL *url.URL) ([]*Con
 			ID:                  entry.ID,
 			Regex:               regex,
 			Type:                typ,
+			DisplayName:         entry.DisplayName,
+			DisplayIcon:         entry.DisplayIcon,
 			NoRefresh:           entry.NoRefresh,
 			ValidateURL:         entry.ValidateURL,
 			AppInstallationsURL: entry.AppInstallationsURL,
 			AppInstallURL:       entry.AppInstallURL,
+			SSHKeysURL:          entry.SSHKeysURL,
 		}
 
 		if entry.DeviceFlow {
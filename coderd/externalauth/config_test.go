@@ -1,13 +1,15 @@
-package gitauth_test
+package externalauth_test
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/oauth2"
 	"golang.org/x/xerrors"
@@ -15,7 +17,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbfake"
 	"github.com/coder/coder/v2/coderd/database/dbgen"
-	"github.com/coder/coder/v2/coderd/gitauth"
+	"github.com/coder/coder/v2/coderd/externalauth"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/testutil"
 )
@@ -24,7 +26,7 @@ func TestRefreshToken(t *testing.T) {
 	t.Parallel()
 	t.Run("FalseIfNoRefresh", func(t *testing.T) {
 		t.Parallel()
-		config := &gitauth.Config{
+		config := &externalauth.Config{
 			NoRefresh: true,
 		}
 		_, refreshed, err := config.RefreshToken(context.Background(), nil, database.GitAuthLink{
@@ -35,7 +37,7 @@ func TestRefreshToken(t *testing.T) {
 	})
 	t.Run("FalseIfTokenSourceFails", func(t *testing.T) {
 		t.Parallel()
-		config := &gitauth.Config{
+		config := &externalauth.Config{
 			OAuth2Config: &testutil.OAuth2Config{
 				TokenSourceFunc: func() (*oauth2.Token, error) {
 					return nil, xerrors.New("failure")
@@ -52,7 +54,7 @@ func TestRefreshToken(t *testing.T) {
 			w.WriteHeader(http.StatusInternalServerError)
 			w.Write([]byte("Failure"))
 		}))
-		config := &gitauth.Config{
+		config := &externalauth.Config{
 			OAuth2Config: &testutil.OAuth2Config{},
 			ValidateURL:  srv.URL,
 		}
@@ -65,7 +67,7 @@ func TestRefreshToken(t *testing.T) {
 			w.WriteHeader(http.StatusUnauthorized)
 			w.Write([]byte("Not permitted"))
 		}))
-		config := &gitauth.Config{
+		config := &externalauth.Config{
 			OAuth2Config: &testutil.OAuth2Config{},
 			ValidateURL:  srv.URL,
 		}
@@ -86,7 +88,7 @@ func TestRefreshToken(t *testing.T) {
 			}
 			w.WriteHeader(http.StatusOK)
 		}))
-		config := &gitauth.Config{
+		config := &externalauth.Config{
 			ID: "test",
 			OAuth2Config: &testutil.OAuth2Config{
 				Token: &oauth2.Token{
@@ -114,7 +116,7 @@ func TestRefreshToken(t *testing.T) {
 			close(validated)
 		}))
 		accessToken := "testing"
-		config := &gitauth.Config{
+		config := &externalauth.Config{
 			OAuth2Config: &testutil.OAuth2Config{
 				Token: &oauth2.Token{
 					AccessToken: accessToken,
@@ -131,7 +133,7 @@ func TestRefreshToken(t *testing.T) {
 	})
 	t.Run("Updates", func(t *testing.T) {
 		t.Parallel()
-		config := &gitauth.Config{
+		config := &externalauth.Config{
 			ID: "test",
 			OAuth2Config: &testutil.OAuth2Config{
 				Token: &oauth2.Token{
@@ -155,14 +157,21 @@ func TestConvertYAML(t *testing.T) {
 	for _, tc := range []struct {
 		Name   string
 		Input  []codersdk.GitAuthConfig
-		Output []*gitauth.Config
+		Output []*externalauth.Config
 		Error  string
 	}{{
-		Name: "InvalidType",
+		Name: "NoType",
 		Input: []codersdk.GitAuthConfig{{
-			Type: "moo",
+			ClientID: "example",
 		}},
-		Error: "unknown git provider type",
+		Error: "type must be provided",
+	}, {
+		Name: "GenericNoEndpoints",
+		Input: []codersdk.GitAuthConfig{{
+			Type:     "jira",
+			ClientID: "example",
+		}},
+		Error: "auth_url and token_url must be provided",
 	}, {
 		Name: "InvalidID",
 		Input: []codersdk.GitAuthConfig{{
@@ -208,7 +217,7 @@ func TestConvertYAML(t *testing.T) {
 		tc := tc
 		t.Run(tc.Name, func(t *testing.T) {
 			t.Parallel()
-			output, err := gitauth.ConvertConfig(tc.Input, &url.URL{})
+			output, err := externalauth.ConvertConfig(tc.Input, &url.URL{})
 			if tc.Error != "" {
 				require.Error(t, err)
 				require.Contains(t, err.Error(), tc.Error)
@@ -218,9 +227,28 @@ func TestConvertYAML(t *testing.T) {
 		})
 	}
 
+	t.Run("Generic", func(t *testing.T) {
+		t.Parallel()
+		config, err := externalauth.ConvertConfig([]codersdk.GitAuthConfig{{
+			Type:         "jira",
+			ClientID:     "id",
+			ClientSecret: "secret",
+			AuthURL:      "https://auth.com",
+			TokenURL:     "https://token.com",
+			DisplayIcon:  "/icon/jira.svg",
+		}}, &url.URL{})
+		require.NoError(t, err)
+		require.Len(t, config, 1)
+		require.Equal(t, "jira", config[0].ID)
+		require.Equal(t, "jira", config[0].DisplayName)
+		require.Equal(t, "/icon/jira.svg", config[0].DisplayIcon)
+		require.False(t, config[0].IsGit())
+		require.Equal(t, "https://auth.com?client_id=id&redirect_uri=%2Fgitauth%2Fjira%2Fcallback&response_type=code", config[0].AuthCodeURL(""))
+	})
+
 	t.Run("CustomScopesAndEndpoint", func(t *testing.T) {
 		t.Parallel()
-		config, err := gitauth.ConvertConfig([]codersdk.GitAuthConfig{{
+		config, err := externalauth.ConvertConfig([]codersdk.GitAuthConfig{{
 			Type:         string(codersdk.GitProviderGitLab),
 			ClientID:     "id",
 			ClientSecret: "secret",
@@ -230,5 +258,60 @@ func TestConvertYAML(t *testing.T) {
 		}}, &url.URL{})
 		require.NoError(t, err)
 		require.Equal(t, "https://auth.com?client_id=id&redirect_uri=%2Fgitauth%2Fgitlab%2Fcallback&response_type=code&scope=read", config[0].AuthCodeURL(""))
+		// Keys must not be uploaded to the SaaS host of a self-hosted provider.
+		require.Empty(t, config[0].SSHKeysURL)
+	})
+
+	t.Run("DefaultSSHKeysURL", func(t *testing.T) {
+		t.Parallel()
+		config, err := externalauth.ConvertConfig([]codersdk.GitAuthConfig{{
+			Type:         string(codersdk.GitProviderGitHub),
+			ClientID:     "id",
+			ClientSecret: "secret",
+		}}, &url.URL{})
+		require.NoError(t, err)
+		require.Equal(t, "https://api.github.com/user/keys", config[0].SSHKeysURL)
+	})
+}
+
+func TestUploadSSHKey(t *testing.T) {
+	t.Parallel()
+	t.Run("Unsupported", func(t *testing.T) {
+		t.Parallel()
+		config := &externalauth.Config{
+			ID: "bitbucket",
+		}
+		err := config.UploadSSHKey(context.Background(), "token", "title", "key")
+		require.ErrorContains(t, err, "does not support")
+	})
+	t.Run("Uploads", func(t *testing.T) {
+		t.Parallel()
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+			var body map[string]string
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, map[string]string{"title": "Coder", "key": "ssh-ed25519 AAAA"}, body)
+			w.WriteHeader(http.StatusCreated)
+		}))
+		t.Cleanup(srv.Close)
+		config := &externalauth.Config{
+			SSHKeysURL: srv.URL,
+		}
+		err := config.UploadSSHKey(context.Background(), "token", "Coder", "ssh-ed25519 AAAA\n")
+		require.NoError(t, err)
+	})
+	t.Run("ServerError", func(t *testing.T) {
+		t.Parallel()
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = w.Write([]byte("key is already in use"))
+		}))
+		t.Cleanup(srv.Close)
+		config := &externalauth.Config{
+			SSHKeysURL: srv.URL,
+		}
+		err := config.UploadSSHKey(context.Background(), "token", "Coder", "ssh-ed25519 AAAA")
+		require.ErrorContains(t, err, "key is already in use")
 	})
 }
@@ -1,4 +1,4 @@
-package gitauth
+package externalauth
 
 import (
 	"context"
@@ -40,6 +40,14 @@ var validateURL = map[codersdk.GitProvider]string{
 	codersdk.GitProviderBitBucket: "https://api.bitbucket.org/2.0/user",
 }
 
+// sshKeysURL contains the endpoints that register public SSH keys of the
+// user. GitHub requires the "write:public_key" scope, and GitLab the "api"
+// scope.
+var sshKeysURL = map[codersdk.GitProvider]string{
+	codersdk.GitProviderGitHub: "https://api.github.com/user/keys",
+	codersdk.GitProviderGitLab: "https://gitlab.com/api/v4/user/keys",
+}
+
 var deviceAuthURL = map[codersdk.GitProvider]string{
 	codersdk.GitProviderGitHub: "https://github.com/login/device/code",
 }
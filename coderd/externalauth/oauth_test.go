@@ -1,4 +1,4 @@
-package gitauth_test
+package externalauth_test
 
 import (
 	"testing"
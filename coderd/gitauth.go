@@ -10,7 +10,7 @@ import (
 
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
-	"github.com/coder/coder/v2/coderd/gitauth"
+	"github.com/coder/coder/v2/coderd/externalauth"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
 	"github.com/coder/coder/v2/codersdk"
@@ -29,11 +29,15 @@ func (api *API) gitAuthByID(w http.ResponseWriter, r *http.Request) {
 	apiKey := httpmw.APIKey(r)
 	ctx := r.Context()
 
+	displayName := config.DisplayName
+	if displayName == "" {
+		displayName = config.Type.Pretty()
+	}
 	res := codersdk.GitAuth{
 		Authenticated:    false,
 		Device:           config.DeviceAuth != nil,
 		AppInstallURL:    config.AppInstallURL,
-		Type:             config.Type.Pretty(),
+		Type:             displayName,
 		AppInstallations: []codersdk.GitAuthAppInstallation{},
 	}
 
@@ -189,7 +193,7 @@ func (*API) gitAuthDeviceByID(rw http.ResponseWriter, r *http.Request) {
 	httpapi.Write(ctx, rw, http.StatusOK, deviceAuth)
 }
 
-func (api *API) gitAuthCallback(gitAuthConfig *gitauth.Config) http.HandlerFunc {
+func (api *API) gitAuthCallback(gitAuthConfig *externalauth.Config) http.HandlerFunc {
 	return func(rw http.ResponseWriter, r *http.Request) {
 		var (
 			ctx    = r.Context()
@@ -18,11 +18,12 @@ import (
 
 	"github.com/coder/coder/v2/coderd/coderdtest"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
-	"github.com/coder/coder/v2/coderd/gitauth"
+	"github.com/coder/coder/v2/coderd/externalauth"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/codersdk/agentsdk"
 	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/provisionersdk/proto"
 	"github.com/coder/coder/v2/testutil"
 )
 
@@ -31,7 +32,7 @@ func TestGitAuthByID(t *testing.T) {
 	t.Run("Unauthenticated", func(t *testing.T) {
 		t.Parallel()
 		client := coderdtest.New(t, &coderdtest.Options{
-			GitAuthConfigs: []*gitauth.Config{{
+			GitAuthConfigs: []*externalauth.Config{{
 				ID:           "test",
 				OAuth2Config: &testutil.OAuth2Config{},
 				Type:         codersdk.GitProviderGitHub,
@@ -47,7 +48,7 @@ func TestGitAuthByID(t *testing.T) {
 		// still return that the provider is authenticated.
 		t.Parallel()
 		client := coderdtest.New(t, &coderdtest.Options{
-			GitAuthConfigs: []*gitauth.Config{{
+			GitAuthConfigs: []*externalauth.Config{{
 				ID:           "test",
 				OAuth2Config: &testutil.OAuth2Config{},
 				// AzureDevops doesn't have a user endpoint!
@@ -71,7 +72,7 @@ func TestGitAuthByID(t *testing.T) {
 		}))
 		defer validateSrv.Close()
 		client := coderdtest.New(t, &coderdtest.Options{
-			GitAuthConfigs: []*gitauth.Config{{
+			GitAuthConfigs: []*externalauth.Config{{
 				ID:           "test",
 				ValidateURL:  validateSrv.URL,
 				OAuth2Config: &testutil.OAuth2Config{},
@@ -111,7 +112,7 @@ func TestGitAuthByID(t *testing.T) {
 		}))
 		defer srv.Close()
 		client := coderdtest.New(t, &coderdtest.Options{
-			GitAuthConfigs: []*gitauth.Config{{
+			GitAuthConfigs: []*externalauth.Config{{
 				ID:                  "test",
 				ValidateURL:         srv.URL + "/user",
 				AppInstallationsURL: srv.URL + "/installs",
@@ -137,7 +138,7 @@ func TestGitAuthDevice(t *testing.T) {
 	t.Run("NotSupported", func(t *testing.T) {
 		t.Parallel()
 		client := coderdtest.New(t, &coderdtest.Options{
-			GitAuthConfigs: []*gitauth.Config{{
+			GitAuthConfigs: []*externalauth.Config{{
 				ID: "test",
 			}},
 		})
@@ -156,9 +157,9 @@ func TestGitAuthDevice(t *testing.T) {
 		}))
 		defer srv.Close()
 		client := coderdtest.New(t, &coderdtest.Options{
-			GitAuthConfigs: []*gitauth.Config{{
+			GitAuthConfigs: []*externalauth.Config{{
 				ID: "test",
-				DeviceAuth: &gitauth.DeviceAuth{
+				DeviceAuth: &externalauth.DeviceAuth{
 					ClientID: "test",
 					CodeURL:  srv.URL,
 					Scopes:   []string{"repo"},
@@ -172,7 +173,7 @@ func TestGitAuthDevice(t *testing.T) {
 	})
 	t.Run("ExchangeCode", func(t *testing.T) {
 		t.Parallel()
-		resp := gitauth.ExchangeDeviceCodeResponse{
+		resp := externalauth.ExchangeDeviceCodeResponse{
 			Error: "authorization_pending",
 		}
 		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -180,9 +181,9 @@ func TestGitAuthDevice(t *testing.T) {
 		}))
 		defer srv.Close()
 		client := coderdtest.New(t, &coderdtest.Options{
-			GitAuthConfigs: []*gitauth.Config{{
+			GitAuthConfigs: []*externalauth.Config{{
 				ID: "test",
-				DeviceAuth: &gitauth.DeviceAuth{
+				DeviceAuth: &externalauth.DeviceAuth{
 					ClientID: "test",
 					TokenURL: srv.URL,
 					Scopes:   []string{"repo"},
@@ -198,7 +199,7 @@ func TestGitAuthDevice(t *testing.T) {
 		require.Equal(t, http.StatusBadRequest, sdkErr.StatusCode())
 		require.Equal(t, "authorization_pending", sdkErr.Detail)
 
-		resp = gitauth.ExchangeDeviceCodeResponse{
+		resp = externalauth.ExchangeDeviceCodeResponse{
 			AccessToken: "hey",
 		}
 
@@ -220,7 +221,7 @@ func TestGitAuthCallback(t *testing.T) {
 		t.Parallel()
 		client := coderdtest.New(t, &coderdtest.Options{
 			IncludeProvisionerDaemon: true,
-			GitAuthConfigs:           []*gitauth.Config{},
+			GitAuthConfigs:           []*externalauth.Config{},
 		})
 		user := coderdtest.CreateFirstUser(t, client)
 		authToken := uuid.NewString()
@@ -245,7 +246,7 @@ func TestGitAuthCallback(t *testing.T) {
 		t.Parallel()
 		client := coderdtest.New(t, &coderdtest.Options{
 			IncludeProvisionerDaemon: true,
-			GitAuthConfigs: []*gitauth.Config{{
+			GitAuthConfigs: []*externalauth.Config{{
 				OAuth2Config: &testutil.OAuth2Config{},
 				ID:           "github",
 				Regex:        regexp.MustCompile(`github\.com`),
@@ -274,7 +275,7 @@ func TestGitAuthCallback(t *testing.T) {
 		t.Parallel()
 		client := coderdtest.New(t, &coderdtest.Options{
 			IncludeProvisionerDaemon: true,
-			GitAuthConfigs: []*gitauth.Config{{
+			GitAuthConfigs: []*externalauth.Config{{
 				OAuth2Config: &testutil.OAuth2Config{},
 				ID:           "github",
 				Regex:        regexp.MustCompile(`github\.com`),
@@ -288,7 +289,7 @@ func TestGitAuthCallback(t *testing.T) {
 		t.Parallel()
 		client := coderdtest.New(t, &coderdtest.Options{
 			IncludeProvisionerDaemon: true,
-			GitAuthConfigs: []*gitauth.Config{{
+			GitAuthConfigs: []*externalauth.Config{{
 				OAuth2Config: &testutil.OAuth2Config{},
 				ID:           "github",
 				Regex:        regexp.MustCompile(`github\.com`),
@@ -314,7 +315,7 @@ func TestGitAuthCallback(t *testing.T) {
 		defer srv.Close()
 		client := coderdtest.New(t, &coderdtest.Options{
 			IncludeProvisionerDaemon: true,
-			GitAuthConfigs: []*gitauth.Config{{
+			GitAuthConfigs: []*externalauth.Config{{
 				ValidateURL:  srv.URL,
 				OAuth2Config: &testutil.OAuth2Config{},
 				ID:           "github",
@@ -366,7 +367,7 @@ func TestGitAuthCallback(t *testing.T) {
 		t.Parallel()
 		client := coderdtest.New(t, &coderdtest.Options{
 			IncludeProvisionerDaemon: true,
-			GitAuthConfigs: []*gitauth.Config{{
+			GitAuthConfigs: []*externalauth.Config{{
 				OAuth2Config: &testutil.OAuth2Config{
 					Token: &oauth2.Token{
 						AccessToken:  "token",
@@ -416,7 +417,7 @@ func TestGitAuthCallback(t *testing.T) {
 		t.Parallel()
 		client := coderdtest.New(t, &coderdtest.Options{
 			IncludeProvisionerDaemon: true,
-			GitAuthConfigs: []*gitauth.Config{{
+			GitAuthConfigs: []*externalauth.Config{{
 				OAuth2Config: &testutil.OAuth2Config{},
 				ID:           "github",
 				Regex:        regexp.MustCompile(`github\.com`),
@@ -461,3 +462,117 @@ func TestGitAuthCallback(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+func TestWorkspaceAgentsExternalAuth(t *testing.T) {
+	t.Parallel()
+	client := coderdtest.New(t, &coderdtest.Options{
+		IncludeProvisionerDaemon: true,
+		GitAuthConfigs: []*externalauth.Config{{
+			OAuth2Config: &testutil.OAuth2Config{},
+			ID:           "github",
+			Regex:        regexp.MustCompile(`github\.com`),
+			Type:         codersdk.GitProviderGitHub,
+		}, {
+			OAuth2Config: &testutil.OAuth2Config{},
+			ID:           "jira",
+			Type:         "jira",
+			DisplayName:  "Jira",
+		}},
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+	authToken := uuid.NewString()
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse: echo.ParseComplete,
+		ProvisionPlan: []*proto.Response{{
+			Type: &proto.Response_Plan{
+				Plan: &proto.PlanComplete{
+					GitAuthProviders: []string{"jira"},
+				},
+			},
+		}},
+		ProvisionApply: echo.ProvisionApplyWithAgent(authToken),
+	})
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+	agentClient := agentsdk.New(client.URL)
+	agentClient.SetSessionToken(authToken)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	t.Run("InvalidQuery", func(t *testing.T) {
+		t.Parallel()
+		_, err := agentClient.ExternalAuth(ctx, agentsdk.ExternalAuthRequest{})
+		var apiError *codersdk.Error
+		require.ErrorAs(t, err, &apiError)
+		require.Equal(t, http.StatusBadRequest, apiError.StatusCode())
+
+		_, err = agentClient.ExternalAuth(ctx, agentsdk.ExternalAuthRequest{
+			ID:    "jira",
+			Match: "github.com/coder/coder",
+		})
+		require.ErrorAs(t, err, &apiError)
+		require.Equal(t, http.StatusBadRequest, apiError.StatusCode())
+	})
+
+	t.Run("UnknownID", func(t *testing.T) {
+		t.Parallel()
+		_, err := agentClient.ExternalAuth(ctx, agentsdk.ExternalAuthRequest{
+			ID: "gitlab",
+		})
+		var apiError *codersdk.Error
+		require.ErrorAs(t, err, &apiError)
+		require.Equal(t, http.StatusNotFound, apiError.StatusCode())
+	})
+
+	t.Run("NotRequiredByTemplate", func(t *testing.T) {
+		t.Parallel()
+		_, err := agentClient.ExternalAuth(ctx, agentsdk.ExternalAuthRequest{
+			ID: "github",
+		})
+		var apiError *codersdk.Error
+		require.ErrorAs(t, err, &apiError)
+		require.Equal(t, http.StatusForbidden, apiError.StatusCode())
+	})
+
+	t.Run("Match", func(t *testing.T) {
+		t.Parallel()
+		token, err := agentClient.ExternalAuth(ctx, agentsdk.ExternalAuthRequest{
+			Match: "github.com/coder/coder",
+		})
+		require.NoError(t, err)
+		require.Empty(t, token.AccessToken)
+		require.True(t, strings.HasSuffix(token.URL, "/gitauth/github"))
+
+		// Providers without a regex are never matched against Git URLs.
+		_, err = agentClient.ExternalAuth(ctx, agentsdk.ExternalAuthRequest{
+			Match: "jira",
+		})
+		var apiError *codersdk.Error
+		require.ErrorAs(t, err, &apiError)
+		require.Equal(t, http.StatusNotFound, apiError.StatusCode())
+	})
+
+	t.Run("ByID", func(t *testing.T) {
+		t.Parallel()
+		token, err := agentClient.ExternalAuth(ctx, agentsdk.ExternalAuthRequest{
+			ID: "jira",
+		})
+		require.NoError(t, err)
+		require.Empty(t, token.AccessToken)
+		require.Equal(t, "jira", token.Type)
+		require.True(t, strings.HasSuffix(token.URL, "/gitauth/jira"))
+
+		resp := coderdtest.RequestGitAuthCallback(t, "jira", client)
+		require.Equal(t, http.StatusTemporaryRedirect, resp.StatusCode)
+
+		token, err = agentClient.ExternalAuth(ctx, agentsdk.ExternalAuthRequest{
+			ID: "jira",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "access_token", token.AccessToken)
+		require.Empty(t, token.URL)
+	})
+}
@@ -1,11 +1,19 @@
 package coderd
 
 import (
+	"database/sql"
+	"errors"
+	"fmt"
 	"net/http"
+	"path"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
 
 	"github.com/coder/coder/v2/coderd/audit"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/externalauth"
 	"github.com/coder/coder/v2/coderd/gitsshkey"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
@@ -22,6 +30,25 @@ import (
 // @Success 200 {object} codersdk.GitSSHKey
 // @Router /users/{user}/gitsshkey [put]
 func (api *API) regenerateGitSSHKey(rw http.ResponseWriter, r *http.Request) {
+	api.regenerateNamedGitSSHKey(rw, r, gitsshkey.DefaultName)
+}
+
+// @Summary Regenerate user Git SSH key by name
+// @ID regenerate-user-git-ssh-key-by-name
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Param keyname path string true "Key name"
+// @Success 200 {object} codersdk.GitSSHKey
+// @Router /users/{user}/gitsshkeys/{keyname} [put]
+func (api *API) regenerateGitSSHKeyByName(rw http.ResponseWriter, r *http.Request) {
+	api.regenerateNamedGitSSHKey(rw, r, chi.URLParam(r, "keyname"))
+}
+
+// regenerateNamedGitSSHKey replaces the key pair of a named key with a
+// generated one. Imported keys stop being imported.
+func (api *API) regenerateNamedGitSSHKey(rw http.ResponseWriter, r *http.Request, keyName string) {
 	var (
 		ctx               = r.Context()
 		user              = httpmw.UserParam(r)
@@ -35,7 +62,14 @@ func (api *API) regenerateGitSSHKey(rw http.ResponseWriter, r *http.Request) {
 	)
 	defer commitAudit()
 
-	oldKey, err := api.Database.GetGitSSHKey(ctx, user.ID)
+	oldKey, err := api.Database.GetGitSSHKey(ctx, database.GetGitSSHKeyParams{
+		UserID: user.ID,
+		Name:   keyName,
+	})
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
 	if err != nil {
 		httpapi.InternalServerError(rw, err)
 		return
@@ -54,9 +88,11 @@ func (api *API) regenerateGitSSHKey(rw http.ResponseWriter, r *http.Request) {
 
 	newKey, err := api.Database.UpdateGitSSHKey(ctx, database.UpdateGitSSHKeyParams{
 		UserID:     user.ID,
+		Name:       keyName,
 		UpdatedAt:  dbtime.Now(),
 		PrivateKey: privateKey,
 		PublicKey:  publicKey,
+		Imported:   false,
 	})
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
@@ -68,13 +104,7 @@ func (api *API) regenerateGitSSHKey(rw http.ResponseWriter, r *http.Request) {
 
 	aReq.New = newKey
 
-	httpapi.Write(ctx, rw, http.StatusOK, codersdk.GitSSHKey{
-		UserID:    newKey.UserID,
-		CreatedAt: newKey.CreatedAt,
-		UpdatedAt: newKey.UpdatedAt,
-		// No need to return the private key to the user
-		PublicKey: newKey.PublicKey,
-	})
+	httpapi.Write(ctx, rw, http.StatusOK, convertGitSSHKey(newKey))
 }
 
 // @Summary Get user Git SSH key
@@ -89,7 +119,10 @@ func (api *API) gitSSHKey(rw http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	user := httpmw.UserParam(r)
 
-	gitSSHKey, err := api.Database.GetGitSSHKey(ctx, user.ID)
+	gitSSHKey, err := api.Database.GetGitSSHKey(ctx, database.GetGitSSHKeyParams{
+		UserID: user.ID,
+		Name:   gitsshkey.DefaultName,
+	})
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
 			Message: "Internal error fetching user's SSH key.",
@@ -98,53 +131,343 @@ func (api *API) gitSSHKey(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	httpapi.Write(ctx, rw, http.StatusOK, codersdk.GitSSHKey{
-		UserID:    gitSSHKey.UserID,
-		CreatedAt: gitSSHKey.CreatedAt,
-		UpdatedAt: gitSSHKey.UpdatedAt,
-		// No need to return the private key to the user
-		PublicKey: gitSSHKey.PublicKey,
-	})
+	httpapi.Write(ctx, rw, http.StatusOK, convertGitSSHKey(gitSSHKey))
 }
 
-// @Summary Get workspace agent Git SSH key
-// @ID get-workspace-agent-git-ssh-key
+// @Summary Get user Git SSH keys
+// @ID get-user-git-ssh-keys
 // @Security CoderSessionToken
 // @Produce json
-// @Tags Agents
-// @Success 200 {object} agentsdk.GitSSHKey
-// @Router /workspaceagents/me/gitsshkey [get]
-func (api *API) agentGitSSHKey(rw http.ResponseWriter, r *http.Request) {
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Success 200 {array} codersdk.GitSSHKey
+// @Router /users/{user}/gitsshkeys [get]
+func (api *API) gitSSHKeys(rw http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	agent := httpmw.WorkspaceAgent(r)
-	resource, err := api.Database.GetWorkspaceResourceByID(ctx, agent.ResourceID)
+	user := httpmw.UserParam(r)
+
+	keys, err := api.Database.GetGitSSHKeysByUserID(ctx, user.ID)
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
-			Message: "Internal error fetching workspace resource.",
+			Message: "Internal error fetching user's SSH keys.",
 			Detail:  err.Error(),
 		})
 		return
 	}
 
-	job, err := api.Database.GetWorkspaceBuildByJobID(ctx, resource.JobID)
+	converted := make([]codersdk.GitSSHKey, 0, len(keys))
+	for _, key := range keys {
+		converted = append(converted, convertGitSSHKey(key))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, converted)
+}
+
+// @Summary Create user Git SSH key
+// @ID create-user-git-ssh-key
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Param request body codersdk.CreateGitSSHKeyRequest true "Create Git SSH key request"
+// @Success 201 {object} codersdk.GitSSHKey
+// @Router /users/{user}/gitsshkeys [post]
+func (api *API) postGitSSHKey(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		user              = httpmw.UserParam(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.GitSSHKey](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionCreate,
+		})
+	)
+	defer commitAudit()
+
+	var req codersdk.CreateGitSSHKeyRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	hosts := make([]string, 0, len(req.Hosts))
+	for _, host := range req.Hosts {
+		// The pattern is checked against an empty name to validate it.
+		_, err := path.Match(strings.TrimPrefix(host, "!"), "")
+		if host == "" || host == "!" || err != nil {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: fmt.Sprintf("Invalid host pattern %q.", host),
+			})
+			return
+		}
+		hosts = append(hosts, host)
+	}
+
+	var (
+		privateKey string
+		publicKey  string
+		err        error
+	)
+	if req.PrivateKey != "" {
+		if req.Algorithm != "" {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "An algorithm cannot be specified when importing a private key.",
+			})
+			return
+		}
+		privateKey, publicKey, err = gitsshkey.Import(req.PrivateKey)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Invalid private key.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+	} else {
+		algorithm := api.SSHKeygenAlgorithm
+		if req.Algorithm != "" {
+			algorithm, err = gitsshkey.ParseAlgorithm(req.Algorithm)
+			if err != nil {
+				httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+					Message: "Invalid algorithm.",
+					Detail:  err.Error(),
+				})
+				return
+			}
+		}
+		privateKey, publicKey, err = gitsshkey.Generate(algorithm)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error generating a new SSH keypair.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+	}
+
+	key, err := api.Database.InsertGitSSHKey(ctx, database.InsertGitSSHKeyParams{
+		UserID:     user.ID,
+		CreatedAt:  dbtime.Now(),
+		UpdatedAt:  dbtime.Now(),
+		PrivateKey: privateKey,
+		PublicKey:  publicKey,
+		Name:       req.Name,
+		Imported:   req.PrivateKey != "",
+		Hosts:      hosts,
+	})
+	if database.IsUniqueViolation(err) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("An SSH key named %q already exists.", req.Name),
+		})
+		return
+	}
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
-			Message: "Internal error fetching workspace build.",
+			Message: "Internal error creating git SSH key.",
 			Detail:  err.Error(),
 		})
 		return
 	}
 
-	workspace, err := api.Database.GetWorkspaceByID(ctx, job.WorkspaceID)
+	aReq.New = key
+
+	httpapi.Write(ctx, rw, http.StatusCreated, convertGitSSHKey(key))
+}
+
+// @Summary Delete user Git SSH key
+// @ID delete-user-git-ssh-key
+// @Security CoderSessionToken
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Param keyname path string true "Key name"
+// @Success 204
+// @Router /users/{user}/gitsshkeys/{keyname} [delete]
+func (api *API) deleteGitSSHKey(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		user              = httpmw.UserParam(r)
+		keyName           = chi.URLParam(r, "keyname")
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.GitSSHKey](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionDelete,
+		})
+	)
+	defer commitAudit()
+
+	if keyName == gitsshkey.DefaultName {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "The default SSH key cannot be deleted, only regenerated.",
+		})
+		return
+	}
+
+	key, err := api.Database.GetGitSSHKey(ctx, database.GetGitSSHKeyParams{
+		UserID: user.ID,
+		Name:   keyName,
+	})
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
-			Message: "Internal error fetching workspace.",
+			Message: "Internal error fetching git SSH key.",
 			Detail:  err.Error(),
 		})
 		return
 	}
+	aReq.Old = key
 
-	gitSSHKey, err := api.Database.GetGitSSHKey(ctx, workspace.OwnerID)
+	err = api.Database.DeleteGitSSHKey(ctx, database.DeleteGitSSHKeyParams{
+		UserID: user.ID,
+		Name:   keyName,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting git SSH key.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusNoContent, nil)
+}
+
+// @Summary Upload user Git SSH key to external auth provider
+// @ID upload-user-git-ssh-key-to-external-auth-provider
+// @Security CoderSessionToken
+// @Accept json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Param keyname path string true "Key name"
+// @Param request body codersdk.UploadGitSSHKeyRequest true "Upload Git SSH key request"
+// @Success 204
+// @Router /users/{user}/gitsshkeys/{keyname}/upload [post]
+func (api *API) uploadGitSSHKey(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx     = r.Context()
+		user    = httpmw.UserParam(r)
+		keyName = chi.URLParam(r, "keyname")
+		auditor = api.Auditor.Load()
+		// The key doesn't change, but registering it grants access to
+		// repositories, so the upload is recorded.
+		aReq, commitAudit = audit.InitRequest[database.GitSSHKey](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionWrite,
+		})
+	)
+	defer commitAudit()
+
+	var req codersdk.UploadGitSSHKeyRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	var config *externalauth.Config
+	for _, c := range api.GitAuthConfigs {
+		if c.ID == req.ExternalAuthProviderID {
+			config = c
+			break
+		}
+	}
+	if config == nil {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: fmt.Sprintf("External auth provider %q not found.", req.ExternalAuthProviderID),
+		})
+		return
+	}
+	if config.SSHKeysURL == "" {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("%s does not support uploading SSH keys.", config.DisplayName),
+		})
+		return
+	}
+
+	key, err := api.Database.GetGitSSHKey(ctx, database.GetGitSSHKeyParams{
+		UserID: user.ID,
+		Name:   keyName,
+	})
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching git SSH key.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	link, err := api.Database.GetGitAuthLink(ctx, database.GetGitAuthLinkParams{
+		ProviderID: config.ID,
+		UserID:     user.ID,
+	})
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to get git auth link.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	valid := false
+	if err == nil {
+		link, valid, err = config.RefreshToken(ctx, api.Database, link)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Failed to refresh git auth token.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+	}
+	if !valid {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("You must authenticate with %s before uploading SSH keys.", config.DisplayName),
+		})
+		return
+	}
+
+	aReq.Old = key
+	aReq.New = key
+
+	title := fmt.Sprintf("Coder %s (%s)", api.AccessURL.Host, key.Name)
+	err = config.UploadSSHKey(ctx, link.OAuthAccessToken, title, key.PublicKey)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadGateway, codersdk.Response{
+			Message: fmt.Sprintf("Failed to upload the SSH key to %s.", config.DisplayName),
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusNoContent, nil)
+}
+
+// @Summary Get workspace agent Git SSH key
+// @ID get-workspace-agent-git-ssh-key
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Agents
+// @Success 200 {object} agentsdk.GitSSHKey
+// @Router /workspaceagents/me/gitsshkey [get]
+func (api *API) agentGitSSHKey(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace, ok := api.agentWorkspace(rw, r)
+	if !ok {
+		return
+	}
+
+	gitSSHKey, err := api.Database.GetGitSSHKey(ctx, database.GetGitSSHKeyParams{
+		UserID: workspace.OwnerID,
+		Name:   gitsshkey.DefaultName,
+	})
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
 			Message: "Internal error fetching git SSH key.",
@@ -154,7 +477,92 @@ func (api *API) agentGitSSHKey(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	httpapi.Write(ctx, rw, http.StatusOK, agentsdk.GitSSHKey{
+		Name:       gitSSHKey.Name,
 		PublicKey:  gitSSHKey.PublicKey,
 		PrivateKey: gitSSHKey.PrivateKey,
+		Hosts:      gitSSHKey.Hosts,
 	})
 }
+
+// @Summary Get workspace agent Git SSH keys
+// @ID get-workspace-agent-git-ssh-keys
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Agents
+// @Success 200 {array} agentsdk.GitSSHKey
+// @Router /workspaceagents/me/gitsshkeys [get]
+func (api *API) agentGitSSHKeys(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace, ok := api.agentWorkspace(rw, r)
+	if !ok {
+		return
+	}
+
+	keys, err := api.Database.GetGitSSHKeysByUserID(ctx, workspace.OwnerID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching git SSH keys.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	converted := make([]agentsdk.GitSSHKey, 0, len(keys))
+	for _, key := range keys {
+		converted = append(converted, agentsdk.GitSSHKey{
+			Name:       key.Name,
+			PublicKey:  key.PublicKey,
+			PrivateKey: key.PrivateKey,
+			Hosts:      key.Hosts,
+		})
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, converted)
+}
+
+// agentWorkspace returns the workspace of the authenticated agent. A response
+// is written if it can't be found.
+func (api *API) agentWorkspace(rw http.ResponseWriter, r *http.Request) (database.Workspace, bool) {
+	ctx := r.Context()
+	agent := httpmw.WorkspaceAgent(r)
+	resource, err := api.Database.GetWorkspaceResourceByID(ctx, agent.ResourceID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace resource.",
+			Detail:  err.Error(),
+		})
+		return database.Workspace{}, false
+	}
+
+	job, err := api.Database.GetWorkspaceBuildByJobID(ctx, resource.JobID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace build.",
+			Detail:  err.Error(),
+		})
+		return database.Workspace{}, false
+	}
+
+	workspace, err := api.Database.GetWorkspaceByID(ctx, job.WorkspaceID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace.",
+			Detail:  err.Error(),
+		})
+		return database.Workspace{}, false
+	}
+	return workspace, true
+}
+
+func convertGitSSHKey(key database.GitSSHKey) codersdk.GitSSHKey {
+	return codersdk.GitSSHKey{
+		UserID:    key.UserID,
+		CreatedAt: key.CreatedAt,
+		UpdatedAt: key.UpdatedAt,
+		// No need to return the private key to the user
+		PublicKey: key.PublicKey,
+		Name:      key.Name,
+		KeyType:   gitsshkey.KeyType(key.PublicKey),
+		Imported:  key.Imported,
+		Hosts:     key.Hosts,
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding/pem"
+	"errors"
 	"flag"
 	"io"
 	"strings"
@@ -21,6 +22,10 @@ import (
 	"golang.org/x/xerrors"
 )
 
+// DefaultName is the name of the key generated for every user. It can be
+// regenerated, but not deleted.
+const DefaultName = "default"
+
 type Algorithm string
 
 const (
@@ -77,6 +82,32 @@ func Generate(algo Algorithm) (privateKey string, publicKey string, err error) {
 	}
 }
 
+// Import validates a private key in the PEM or OpenSSH format and returns it
+// with the public key in the authorized key format. Keys protected by a
+// passphrase are rejected, since they can't be used non-interactively.
+func Import(privateKey string) (string, string, error) {
+	privateKey = strings.TrimSpace(privateKey) + "\n"
+	signer, err := ssh.ParsePrivateKey([]byte(privateKey))
+	if err != nil {
+		var passphraseErr *ssh.PassphraseMissingError
+		if errors.As(err, &passphraseErr) {
+			return "", "", xerrors.New("private key must not be protected by a passphrase")
+		}
+		return "", "", xerrors.Errorf("parse private key: %w", err)
+	}
+	return privateKey, string(ssh.MarshalAuthorizedKey(signer.PublicKey())), nil
+}
+
+// KeyType returns the type of a public key in the authorized key format,
+// such as "ssh-ed25519". An empty string is returned if the key is invalid.
+func KeyType(publicKey string) string {
+	key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(publicKey))
+	if err != nil {
+		return ""
+	}
+	return key.Type()
+}
+
 // ed25519KeyGen returns an ED25519-based SSH private key.
 func ed25519KeyGen() (privateKey string, publicKey string, err error) {
 	_, privateKeyRaw, err := ed25519.GenerateKey(entropy())
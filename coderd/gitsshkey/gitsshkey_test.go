@@ -54,6 +54,26 @@ func TestGitSSHKeys(t *testing.T) {
 		_, err = gitsshkey.ParseAlgorithm("")
 		require.Error(t, err, "empty string should fail")
 	})
+	t.Run("Import", func(t *testing.T) {
+		t.Parallel()
+		pv, pb, err := gitsshkey.Generate(gitsshkey.AlgorithmECDSA)
+		require.NoError(t, err)
+		importedPrivate, importedPublic, err := gitsshkey.Import(pv)
+		require.NoError(t, err)
+		require.Equal(t, pv, importedPrivate)
+		require.Equal(t, pb, importedPublic)
+		require.Equal(t, "ecdsa-sha2-nistp256", gitsshkey.KeyType(importedPublic))
+
+		_, _, err = gitsshkey.Import("not a key")
+		require.Error(t, err)
+	})
+	t.Run("KeyType", func(t *testing.T) {
+		t.Parallel()
+		_, pb, err := gitsshkey.Generate(gitsshkey.AlgorithmEd25519)
+		require.NoError(t, err)
+		require.Equal(t, ssh.KeyAlgoED25519, gitsshkey.KeyType(pb))
+		require.Empty(t, gitsshkey.KeyType("invalid"))
+	})
 }
 
 func BenchmarkGenerate(b *testing.B) {
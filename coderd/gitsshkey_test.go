@@ -2,6 +2,10 @@ package coderd_test
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/google/uuid"
@@ -11,7 +15,9 @@ import (
 	"github.com/coder/coder/v2/coderd/audit"
 	"github.com/coder/coder/v2/coderd/coderdtest"
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/externalauth"
 	"github.com/coder/coder/v2/coderd/gitsshkey"
+	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/codersdk/agentsdk"
 	"github.com/coder/coder/v2/provisioner/echo"
 	"github.com/coder/coder/v2/testutil"
@@ -98,6 +104,231 @@ func TestGitSSHKey(t *testing.T) {
 	})
 }
 
+func TestGitSSHKeys(t *testing.T) {
+	t.Parallel()
+	t.Run("Default", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{
+			SSHKeygenAlgorithm: gitsshkey.AlgorithmEd25519,
+		})
+		_ = coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		keys, err := client.GitSSHKeys(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+		require.Equal(t, gitsshkey.DefaultName, keys[0].Name)
+		require.Equal(t, "ssh-ed25519", keys[0].KeyType)
+		require.False(t, keys[0].Imported)
+
+		err = client.DeleteGitSSHKey(ctx, codersdk.Me, gitsshkey.DefaultName)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+	t.Run("Generate", func(t *testing.T) {
+		t.Parallel()
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{
+			SSHKeygenAlgorithm: gitsshkey.AlgorithmEd25519,
+			Auditor:            auditor,
+		})
+		_ = coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		key, err := client.CreateGitSSHKey(ctx, codersdk.Me, codersdk.CreateGitSSHKeyRequest{
+			Name:      "work",
+			Algorithm: string(gitsshkey.AlgorithmECDSA),
+		})
+		require.NoError(t, err)
+		require.Equal(t, "work", key.Name)
+		require.Equal(t, "ecdsa-sha2-nistp256", key.KeyType)
+		require.False(t, key.Imported)
+		require.Equal(t, database.AuditActionCreate, auditor.AuditLogs()[len(auditor.AuditLogs())-1].Action)
+
+		_, err = client.CreateGitSSHKey(ctx, codersdk.Me, codersdk.CreateGitSSHKeyRequest{
+			Name: "work",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+
+		_, err = client.CreateGitSSHKey(ctx, codersdk.Me, codersdk.CreateGitSSHKeyRequest{
+			Name:      "invalid",
+			Algorithm: "dsa",
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		keys, err := client.GitSSHKeys(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Len(t, keys, 2)
+		require.Equal(t, gitsshkey.DefaultName, keys[0].Name)
+		require.Equal(t, "work", keys[1].Name)
+
+		err = client.DeleteGitSSHKey(ctx, codersdk.Me, "work")
+		require.NoError(t, err)
+		require.Equal(t, database.AuditActionDelete, auditor.AuditLogs()[len(auditor.AuditLogs())-1].Action)
+
+		keys, err = client.GitSSHKeys(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+
+		err = client.DeleteGitSSHKey(ctx, codersdk.Me, "work")
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+	t.Run("Import", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		privateKey, publicKey, err := gitsshkey.Generate(gitsshkey.AlgorithmEd25519)
+		require.NoError(t, err)
+		key, err := client.CreateGitSSHKey(ctx, codersdk.Me, codersdk.CreateGitSSHKeyRequest{
+			Name:       "imported",
+			PrivateKey: privateKey,
+		})
+		require.NoError(t, err)
+		require.True(t, key.Imported)
+		require.Equal(t, publicKey, key.PublicKey)
+
+		_, err = client.CreateGitSSHKey(ctx, codersdk.Me, codersdk.CreateGitSSHKeyRequest{
+			Name:       "invalid",
+			PrivateKey: "not a key",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+	t.Run("Hosts", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		key, err := client.CreateGitSSHKey(ctx, codersdk.Me, codersdk.CreateGitSSHKeyRequest{
+			Name:  "work",
+			Hosts: []string{"*.example.com", "!public.example.com"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"*.example.com", "!public.example.com"}, key.Hosts)
+
+		key, err = client.GitSSHKey(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Empty(t, key.Hosts)
+
+		_, err = client.CreateGitSSHKey(ctx, codersdk.Me, codersdk.CreateGitSSHKeyRequest{
+			Name:  "invalid",
+			Hosts: []string{"[example.com"},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+	t.Run("RegenerateNamed", func(t *testing.T) {
+		t.Parallel()
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{
+			Auditor: auditor,
+		})
+		_ = coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		privateKey, _, err := gitsshkey.Generate(gitsshkey.AlgorithmEd25519)
+		require.NoError(t, err)
+		imported, err := client.CreateGitSSHKey(ctx, codersdk.Me, codersdk.CreateGitSSHKeyRequest{
+			Name:       "imported",
+			PrivateKey: privateKey,
+			Hosts:      []string{"github.com"},
+		})
+		require.NoError(t, err)
+
+		// Regenerated keys are no longer imported, but keep their hosts.
+		regenerated, err := client.RegenerateNamedGitSSHKey(ctx, codersdk.Me, "imported")
+		require.NoError(t, err)
+		require.NotEqual(t, imported.PublicKey, regenerated.PublicKey)
+		require.False(t, regenerated.Imported)
+		require.Equal(t, imported.Hosts, regenerated.Hosts)
+		require.Equal(t, database.AuditActionWrite, auditor.AuditLogs()[len(auditor.AuditLogs())-1].Action)
+
+		_, err = client.RegenerateNamedGitSSHKey(ctx, codersdk.Me, "unknown")
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+}
+
+func TestUploadGitSSHKey(t *testing.T) {
+	t.Parallel()
+
+	uploaded := make(chan map[string]string, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer access_token", r.Header.Get("Authorization"))
+		var body map[string]string
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		uploaded <- body
+		w.WriteHeader(http.StatusCreated)
+	}))
+	t.Cleanup(srv.Close)
+
+	auditor := audit.NewMock()
+	client := coderdtest.New(t, &coderdtest.Options{
+		Auditor: auditor,
+		GitAuthConfigs: []*externalauth.Config{{
+			OAuth2Config: &testutil.OAuth2Config{},
+			ID:           "github",
+			Type:         codersdk.GitProviderGitHub,
+			DisplayName:  "GitHub",
+			SSHKeysURL:   srv.URL,
+		}, {
+			OAuth2Config: &testutil.OAuth2Config{},
+			ID:           "bitbucket",
+			Type:         codersdk.GitProviderBitBucket,
+			DisplayName:  "Bitbucket",
+		}},
+	})
+	_ = coderdtest.CreateFirstUser(t, client)
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	req := codersdk.UploadGitSSHKeyRequest{ExternalAuthProviderID: "github"}
+	var apiErr *codersdk.Error
+
+	// The user must authenticate with the provider first.
+	err := client.UploadGitSSHKey(ctx, codersdk.Me, gitsshkey.DefaultName, req)
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	resp := coderdtest.RequestGitAuthCallback(t, "github", client)
+	require.Equal(t, http.StatusTemporaryRedirect, resp.StatusCode)
+
+	err = client.UploadGitSSHKey(ctx, codersdk.Me, "unknown", req)
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+	err = client.UploadGitSSHKey(ctx, codersdk.Me, gitsshkey.DefaultName, codersdk.UploadGitSSHKeyRequest{
+		ExternalAuthProviderID: "bitbucket",
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	err = client.UploadGitSSHKey(ctx, codersdk.Me, gitsshkey.DefaultName, req)
+	require.NoError(t, err)
+	require.Equal(t, database.AuditActionWrite, auditor.AuditLogs()[len(auditor.AuditLogs())-1].Action)
+
+	key, err := client.GitSSHKey(ctx, codersdk.Me)
+	require.NoError(t, err)
+	var body map[string]string
+	select {
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for upload")
+	case body = <-uploaded:
+	}
+	require.Equal(t, strings.TrimSpace(key.PublicKey), body["key"])
+	require.Contains(t, body["title"], gitsshkey.DefaultName)
+}
+
 func TestAgentGitSSHKey(t *testing.T) {
 	t.Parallel()
 
@@ -126,3 +357,43 @@ func TestAgentGitSSHKey(t *testing.T) {
 	require.NoError(t, err)
 	require.NotEmpty(t, agentKey.PrivateKey)
 }
+
+func TestAgentGitSSHKeys(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{
+		IncludeProvisionerDaemon: true,
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+	authToken := uuid.NewString()
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:          echo.ParseComplete,
+		ProvisionPlan:  echo.PlanComplete,
+		ProvisionApply: echo.ProvisionApplyWithAgent(authToken),
+	})
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	_, err := client.CreateGitSSHKey(ctx, codersdk.Me, codersdk.CreateGitSSHKeyRequest{
+		Name:  "work",
+		Hosts: []string{"*.example.com"},
+	})
+	require.NoError(t, err)
+
+	agentClient := agentsdk.New(client.URL)
+	agentClient.SetSessionToken(authToken)
+
+	agentKeys, err := agentClient.GitSSHKeys(ctx)
+	require.NoError(t, err)
+	require.Len(t, agentKeys, 2)
+	require.Equal(t, gitsshkey.DefaultName, agentKeys[0].Name)
+	require.Equal(t, "work", agentKeys[1].Name)
+	require.Equal(t, []string{"*.example.com"}, agentKeys[1].Hosts)
+	for _, key := range agentKeys {
+		require.NotEmpty(t, key.PrivateKey)
+	}
+}
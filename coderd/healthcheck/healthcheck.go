@@ -20,6 +20,9 @@ const (
 	SectionAccessURL string = "AccessURL"
 	SectionWebsocket string = "Websocket"
 	SectionDatabase  string = "Database"
+
+	SectionProvisionerDaemons string = "ProvisionerDaemons"
+	SectionReplicas           string = "Replicas"
 )
 
 type Checker interface {
@@ -27,6 +30,8 @@ type Checker interface {
 	AccessURL(ctx context.Context, opts *AccessURLReportOptions) AccessURLReport
 	Websocket(ctx context.Context, opts *WebsocketReportOptions) WebsocketReport
 	Database(ctx context.Context, opts *DatabaseReportOptions) DatabaseReport
+	ProvisionerDaemons(ctx context.Context, opts *ProvisionerDaemonsReportOptions) ProvisionerDaemonsReport
+	Replicas(ctx context.Context, opts *ReplicasReportOptions) ReplicasReport
 }
 
 // @typescript-generate Report
@@ -43,6 +48,9 @@ type Report struct {
 	Websocket WebsocketReport `json:"websocket"`
 	Database  DatabaseReport  `json:"database"`
 
+	ProvisionerDaemons ProvisionerDaemonsReport `json:"provisioner_daemons"`
+	Replicas           ReplicasReport           `json:"replicas"`
+
 	// The Coder version of the server that the report was generated on.
 	CoderVersion string `json:"coder_version"`
 }
@@ -80,6 +88,16 @@ func (defaultChecker) Database(ctx context.Context, opts *DatabaseReportOptions)
 	return report
 }
 
+func (defaultChecker) ProvisionerDaemons(ctx context.Context, opts *ProvisionerDaemonsReportOptions) (report ProvisionerDaemonsReport) {
+	report.Run(ctx, opts)
+	return report
+}
+
+func (defaultChecker) Replicas(ctx context.Context, opts *ReplicasReportOptions) (report ReplicasReport) {
+	report.Run(ctx, opts)
+	return report
+}
+
 func Run(ctx context.Context, opts *ReportOptions) *Report {
 	var (
 		wg     sync.WaitGroup
@@ -148,6 +166,34 @@ func Run(ctx context.Context, opts *ReportOptions) *Report {
 		})
 	}()
 
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer func() {
+			if err := recover(); err != nil {
+				report.ProvisionerDaemons.Error = ptr.Ref(fmt.Sprint(err))
+			}
+		}()
+
+		report.ProvisionerDaemons = opts.Checker.ProvisionerDaemons(ctx, &ProvisionerDaemonsReportOptions{
+			DB: opts.DB,
+		})
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer func() {
+			if err := recover(); err != nil {
+				report.Replicas.Error = ptr.Ref(fmt.Sprint(err))
+			}
+		}()
+
+		report.Replicas = opts.Checker.Replicas(ctx, &ReplicasReportOptions{
+			DB: opts.DB,
+		})
+	}()
+
 	report.CoderVersion = buildinfo.Version()
 	wg.Wait()
 
@@ -164,6 +210,12 @@ func Run(ctx context.Context, opts *ReportOptions) *Report {
 	if !report.Database.Healthy {
 		report.FailingSections = append(report.FailingSections, SectionDatabase)
 	}
+	if !report.ProvisionerDaemons.Healthy {
+		report.FailingSections = append(report.FailingSections, SectionProvisionerDaemons)
+	}
+	if !report.Replicas.Healthy {
+		report.FailingSections = append(report.FailingSections, SectionReplicas)
+	}
 
 	report.Healthy = len(report.FailingSections) == 0
 	return &report
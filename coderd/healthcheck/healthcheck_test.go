@@ -14,6 +14,9 @@ type testChecker struct {
 	AccessURLReport healthcheck.AccessURLReport
 	WebsocketReport healthcheck.WebsocketReport
 	DatabaseReport  healthcheck.DatabaseReport
+
+	ProvisionerDaemonsReport healthcheck.ProvisionerDaemonsReport
+	ReplicasReport           healthcheck.ReplicasReport
 }
 
 func (c *testChecker) DERP(context.Context, *healthcheck.DERPReportOptions) healthcheck.DERPReport {
@@ -32,6 +35,14 @@ func (c *testChecker) Database(context.Context, *healthcheck.DatabaseReportOptio
 	return c.DatabaseReport
 }
 
+func (c *testChecker) ProvisionerDaemons(context.Context, *healthcheck.ProvisionerDaemonsReportOptions) healthcheck.ProvisionerDaemonsReport {
+	return c.ProvisionerDaemonsReport
+}
+
+func (c *testChecker) Replicas(context.Context, *healthcheck.ReplicasReportOptions) healthcheck.ReplicasReport {
+	return c.ReplicasReport
+}
+
 func TestHealthcheck(t *testing.T) {
 	t.Parallel()
 
@@ -55,6 +66,12 @@ func TestHealthcheck(t *testing.T) {
 			DatabaseReport: healthcheck.DatabaseReport{
 				Healthy: true,
 			},
+			ProvisionerDaemonsReport: healthcheck.ProvisionerDaemonsReport{
+				Healthy: true,
+			},
+			ReplicasReport: healthcheck.ReplicasReport{
+				Healthy: true,
+			},
 		},
 		healthy:         true,
 		failingSections: nil,
@@ -73,6 +90,12 @@ func TestHealthcheck(t *testing.T) {
 			DatabaseReport: healthcheck.DatabaseReport{
 				Healthy: true,
 			},
+			ProvisionerDaemonsReport: healthcheck.ProvisionerDaemonsReport{
+				Healthy: true,
+			},
+			ReplicasReport: healthcheck.ReplicasReport{
+				Healthy: true,
+			},
 		},
 		healthy:         false,
 		failingSections: []string{healthcheck.SectionDERP},
@@ -91,6 +114,12 @@ func TestHealthcheck(t *testing.T) {
 			DatabaseReport: healthcheck.DatabaseReport{
 				Healthy: true,
 			},
+			ProvisionerDaemonsReport: healthcheck.ProvisionerDaemonsReport{
+				Healthy: true,
+			},
+			ReplicasReport: healthcheck.ReplicasReport{
+				Healthy: true,
+			},
 		},
 		healthy:         false,
 		failingSections: []string{healthcheck.SectionAccessURL},
@@ -109,6 +138,12 @@ func TestHealthcheck(t *testing.T) {
 			DatabaseReport: healthcheck.DatabaseReport{
 				Healthy: true,
 			},
+			ProvisionerDaemonsReport: healthcheck.ProvisionerDaemonsReport{
+				Healthy: true,
+			},
+			ReplicasReport: healthcheck.ReplicasReport{
+				Healthy: true,
+			},
 		},
 		healthy:         false,
 		failingSections: []string{healthcheck.SectionWebsocket},
@@ -127,9 +162,63 @@ func TestHealthcheck(t *testing.T) {
 			DatabaseReport: healthcheck.DatabaseReport{
 				Healthy: false,
 			},
+			ProvisionerDaemonsReport: healthcheck.ProvisionerDaemonsReport{
+				Healthy: true,
+			},
+			ReplicasReport: healthcheck.ReplicasReport{
+				Healthy: true,
+			},
 		},
 		healthy:         false,
 		failingSections: []string{healthcheck.SectionDatabase},
+	}, {
+		name: "ProvisionerDaemonsFail",
+		checker: &testChecker{
+			DERPReport: healthcheck.DERPReport{
+				Healthy: true,
+			},
+			AccessURLReport: healthcheck.AccessURLReport{
+				Healthy: true,
+			},
+			WebsocketReport: healthcheck.WebsocketReport{
+				Healthy: true,
+			},
+			DatabaseReport: healthcheck.DatabaseReport{
+				Healthy: true,
+			},
+			ProvisionerDaemonsReport: healthcheck.ProvisionerDaemonsReport{
+				Healthy: false,
+			},
+			ReplicasReport: healthcheck.ReplicasReport{
+				Healthy: true,
+			},
+		},
+		healthy:         false,
+		failingSections: []string{healthcheck.SectionProvisionerDaemons},
+	}, {
+		name: "ReplicasFail",
+		checker: &testChecker{
+			DERPReport: healthcheck.DERPReport{
+				Healthy: true,
+			},
+			AccessURLReport: healthcheck.AccessURLReport{
+				Healthy: true,
+			},
+			WebsocketReport: healthcheck.WebsocketReport{
+				Healthy: true,
+			},
+			DatabaseReport: healthcheck.DatabaseReport{
+				Healthy: true,
+			},
+			ProvisionerDaemonsReport: healthcheck.ProvisionerDaemonsReport{
+				Healthy: true,
+			},
+			ReplicasReport: healthcheck.ReplicasReport{
+				Healthy: false,
+			},
+		},
+		healthy:         false,
+		failingSections: []string{healthcheck.SectionReplicas},
 	}, {
 		name:    "AllFail",
 		checker: &testChecker{},
@@ -139,6 +228,8 @@ func TestHealthcheck(t *testing.T) {
 			healthcheck.SectionAccessURL,
 			healthcheck.SectionWebsocket,
 			healthcheck.SectionDatabase,
+			healthcheck.SectionProvisionerDaemons,
+			healthcheck.SectionReplicas,
 		},
 	}} {
 		c := c
@@ -154,6 +245,8 @@ func TestHealthcheck(t *testing.T) {
 			assert.Equal(t, c.checker.DERPReport.Healthy, report.DERP.Healthy)
 			assert.Equal(t, c.checker.AccessURLReport.Healthy, report.AccessURL.Healthy)
 			assert.Equal(t, c.checker.WebsocketReport.Healthy, report.Websocket.Healthy)
+			assert.Equal(t, c.checker.ProvisionerDaemonsReport.Healthy, report.ProvisionerDaemons.Healthy)
+			assert.Equal(t, c.checker.ReplicasReport.Healthy, report.Replicas.Healthy)
 			assert.NotZero(t, report.Time)
 			assert.NotZero(t, report.CoderVersion)
 		})
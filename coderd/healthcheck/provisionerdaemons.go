@@ -0,0 +1,99 @@
+package healthcheck
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+)
+
+// @typescript-generate ProvisionerDaemonsReport
+type ProvisionerDaemonsReport struct {
+	Healthy bool `json:"healthy"`
+	// Daemons is the number of registered provisioner daemons.
+	Daemons int `json:"daemons"`
+	// HealthyDaemons is the number of daemons that have sent a heartbeat
+	// recently.
+	HealthyDaemons int `json:"healthy_daemons"`
+	// Versions maps each Coder version reported by a healthy daemon to the
+	// number of daemons running it.
+	Versions map[string]int `json:"versions"`
+	// UnservedTemplates lists templates whose active version cannot be built
+	// because no healthy daemon matches its provisioner and tags.
+	UnservedTemplates []ProvisionerDaemonsUnservedTemplate `json:"unserved_templates"`
+	Error             *string                              `json:"error"`
+}
+
+// @typescript-generate ProvisionerDaemonsUnservedTemplate
+type ProvisionerDaemonsUnservedTemplate struct {
+	TemplateID   string            `json:"template_id"`
+	TemplateName string            `json:"template_name"`
+	Provisioner  string            `json:"provisioner"`
+	Tags         map[string]string `json:"tags"`
+}
+
+type ProvisionerDaemonsReportOptions struct {
+	DB database.Store
+	// StaleTimeout is how long a daemon may go without a heartbeat before it
+	// is considered unhealthy. Defaults to
+	// database.ProvisionerDaemonStaleTimeout.
+	StaleTimeout time.Duration
+}
+
+func (r *ProvisionerDaemonsReport) Run(ctx context.Context, opts *ProvisionerDaemonsReportOptions) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	r.Versions = map[string]int{}
+	r.UnservedTemplates = []ProvisionerDaemonsUnservedTemplate{}
+
+	staleTimeout := opts.StaleTimeout
+	if staleTimeout == 0 {
+		staleTimeout = database.ProvisionerDaemonStaleTimeout
+	}
+
+	daemons, err := opts.DB.GetProvisionerDaemons(ctx)
+	if err != nil {
+		r.Error = convertError(xerrors.Errorf("get provisioner daemons: %w", err))
+		return
+	}
+	r.Daemons = len(daemons)
+
+	healthy := make([]database.ProvisionerDaemon, 0, len(daemons))
+	for _, daemon := range daemons {
+		if !daemon.Healthy(staleTimeout) {
+			continue
+		}
+		healthy = append(healthy, daemon)
+		r.Versions[daemon.Version]++
+	}
+	r.HealthyDaemons = len(healthy)
+
+	templates, err := opts.DB.GetActiveTemplateVersionJobTags(ctx)
+	if err != nil {
+		r.Error = convertError(xerrors.Errorf("get active template version job tags: %w", err))
+		return
+	}
+	for _, template := range templates {
+		served := false
+		for _, daemon := range healthy {
+			if daemon.CanAcquire(template.Provisioner, template.Tags) {
+				served = true
+				break
+			}
+		}
+		if served {
+			continue
+		}
+		r.UnservedTemplates = append(r.UnservedTemplates, ProvisionerDaemonsUnservedTemplate{
+			TemplateID:   template.TemplateID.String(),
+			TemplateName: template.TemplateName,
+			Provisioner:  string(template.Provisioner),
+			Tags:         template.Tags,
+		})
+	}
+
+	r.Healthy = len(r.UnservedTemplates) == 0
+}
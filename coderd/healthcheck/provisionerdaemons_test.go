@@ -0,0 +1,118 @@
+package healthcheck_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbmock"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/healthcheck"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestProvisionerDaemons(t *testing.T) {
+	t.Parallel()
+
+	var (
+		now     = dbtime.Now()
+		healthy = database.ProvisionerDaemon{
+			ID:           uuid.New(),
+			Provisioners: []database.ProvisionerType{database.ProvisionerTypeTerraform},
+			Tags:         database.StringMap{"scope": "organization"},
+			LastSeenAt:   sql.NullTime{Time: now, Valid: true},
+			Version:      "v2.1.0",
+		}
+		stale = database.ProvisionerDaemon{
+			ID:           uuid.New(),
+			Provisioners: []database.ProvisionerType{database.ProvisionerTypeTerraform},
+			Tags:         database.StringMap{"scope": "organization", "gpu": "true"},
+			LastSeenAt:   sql.NullTime{Time: now.Add(-time.Hour), Valid: true},
+			Version:      "v2.0.0",
+		}
+		orgTemplate = database.GetActiveTemplateVersionJobTagsRow{
+			TemplateID:   uuid.New(),
+			TemplateName: "docker",
+			Provisioner:  database.ProvisionerTypeTerraform,
+			Tags:         database.StringMap{"scope": "organization"},
+		}
+		gpuTemplate = database.GetActiveTemplateVersionJobTagsRow{
+			TemplateID:   uuid.New(),
+			TemplateName: "gpu",
+			Provisioner:  database.ProvisionerTypeTerraform,
+			Tags:         database.StringMap{"scope": "organization", "gpu": "true"},
+		}
+	)
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx, cancel = context.WithTimeout(context.Background(), testutil.WaitShort)
+			report      = healthcheck.ProvisionerDaemonsReport{}
+			db          = dbmock.NewMockStore(gomock.NewController(t))
+		)
+		defer cancel()
+
+		db.EXPECT().GetProvisionerDaemons(gomock.Any()).Return([]database.ProvisionerDaemon{healthy, stale}, nil)
+		db.EXPECT().GetActiveTemplateVersionJobTags(gomock.Any()).Return([]database.GetActiveTemplateVersionJobTagsRow{orgTemplate}, nil)
+
+		report.Run(ctx, &healthcheck.ProvisionerDaemonsReportOptions{DB: db})
+
+		assert.True(t, report.Healthy)
+		assert.Equal(t, 2, report.Daemons)
+		assert.Equal(t, 1, report.HealthyDaemons)
+		assert.Equal(t, map[string]int{"v2.1.0": 1}, report.Versions)
+		assert.Empty(t, report.UnservedTemplates)
+		assert.Nil(t, report.Error)
+	})
+
+	t.Run("Unserved", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx, cancel = context.WithTimeout(context.Background(), testutil.WaitShort)
+			report      = healthcheck.ProvisionerDaemonsReport{}
+			db          = dbmock.NewMockStore(gomock.NewController(t))
+		)
+		defer cancel()
+
+		db.EXPECT().GetProvisionerDaemons(gomock.Any()).Return([]database.ProvisionerDaemon{healthy, stale}, nil)
+		db.EXPECT().GetActiveTemplateVersionJobTags(gomock.Any()).Return([]database.GetActiveTemplateVersionJobTagsRow{orgTemplate, gpuTemplate}, nil)
+
+		report.Run(ctx, &healthcheck.ProvisionerDaemonsReportOptions{DB: db})
+
+		assert.False(t, report.Healthy)
+		require.Len(t, report.UnservedTemplates, 1)
+		assert.Equal(t, gpuTemplate.TemplateID.String(), report.UnservedTemplates[0].TemplateID)
+		assert.Equal(t, "gpu", report.UnservedTemplates[0].TemplateName)
+		assert.Nil(t, report.Error)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx, cancel = context.WithTimeout(context.Background(), testutil.WaitShort)
+			report      = healthcheck.ProvisionerDaemonsReport{}
+			db          = dbmock.NewMockStore(gomock.NewController(t))
+		)
+		defer cancel()
+
+		db.EXPECT().GetProvisionerDaemons(gomock.Any()).Return(nil, xerrors.New("database error"))
+
+		report.Run(ctx, &healthcheck.ProvisionerDaemonsReportOptions{DB: db})
+
+		assert.False(t, report.Healthy)
+		require.NotNil(t, report.Error)
+		assert.Contains(t, *report.Error, "database error")
+	})
+}
@@ -0,0 +1,98 @@
+package healthcheck
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/util/ptr"
+)
+
+// ReplicaStaleTimeout is how long a replica may go without updating itself
+// before it is considered dead. Replicas update every few seconds.
+const ReplicaStaleTimeout = 30 * time.Second
+
+// @typescript-generate ReplicasReport
+type ReplicasReport struct {
+	Healthy bool `json:"healthy"`
+	// Replicas are the primary replicas that are alive, by region. It's empty
+	// if the deployment doesn't run multiple replicas.
+	Replicas []ReplicaReport `json:"replicas"`
+	Error    *string         `json:"error"`
+}
+
+// @typescript-generate ReplicaReport
+type ReplicaReport struct {
+	ID           uuid.UUID `json:"id" format:"uuid"`
+	Hostname     string    `json:"hostname"`
+	Version      string    `json:"version"`
+	RegionID     int32     `json:"region_id"`
+	RelayAddress string    `json:"relay_address"`
+	// Gateway is true if the replica meshes its DERP server with the replicas
+	// of other regions.
+	Gateway           bool  `json:"gateway"`
+	DatabaseLatencyMS int64 `json:"database_latency_ms"`
+	Healthy           bool  `json:"healthy"`
+	// Error is the error the replica encountered when dialing the DERP
+	// servers of the replicas it meshes with.
+	Error *string `json:"error"`
+}
+
+type ReplicasReportOptions struct {
+	DB database.Store
+	// StaleTimeout defaults to ReplicaStaleTimeout.
+	StaleTimeout time.Duration
+}
+
+func (r *ReplicasReport) Run(ctx context.Context, opts *ReplicasReportOptions) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	r.Replicas = []ReplicaReport{}
+
+	staleTimeout := opts.StaleTimeout
+	if staleTimeout == 0 {
+		staleTimeout = ReplicaStaleTimeout
+	}
+
+	replicas, err := opts.DB.GetReplicasUpdatedAfter(ctx, dbtime.Now().Add(-staleTimeout))
+	if err != nil {
+		r.Error = convertError(xerrors.Errorf("get replicas: %w", err))
+		return
+	}
+
+	gateways := database.RegionGateways(replicas)
+	r.Healthy = true
+	for _, replica := range replicas {
+		// Workspace proxies report their own health.
+		if !replica.Primary || replica.StoppedAt.Valid {
+			continue
+		}
+		report := ReplicaReport{
+			ID:                replica.ID,
+			Hostname:          replica.Hostname,
+			Version:           replica.Version,
+			RegionID:          replica.RegionID,
+			RelayAddress:      replica.RelayAddress,
+			Gateway:           gateways[replica.RegionID] == replica.ID,
+			DatabaseLatencyMS: (time.Duration(replica.DatabaseLatency) * time.Microsecond).Milliseconds(),
+			Healthy:           replica.Error == "",
+		}
+		if replica.Error != "" {
+			report.Error = ptr.Ref(replica.Error)
+			r.Healthy = false
+		}
+		r.Replicas = append(r.Replicas, report)
+	}
+	sort.Slice(r.Replicas, func(i, j int) bool {
+		if r.Replicas[i].RegionID != r.Replicas[j].RegionID {
+			return r.Replicas[i].RegionID < r.Replicas[j].RegionID
+		}
+		return r.Replicas[i].Hostname < r.Replicas[j].Hostname
+	})
+}
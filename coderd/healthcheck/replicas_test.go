@@ -0,0 +1,133 @@
+package healthcheck_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbmock"
+	"github.com/coder/coder/v2/coderd/healthcheck"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestReplicas(t *testing.T) {
+	t.Parallel()
+
+	var (
+		usGateway = database.Replica{
+			ID:              uuid.MustParse("00000000-0000-0000-0000-000000000001"),
+			Hostname:        "us-1",
+			RegionID:        999,
+			RelayAddress:    "http://10.0.0.1:8080",
+			Version:         "v2.1.0",
+			DatabaseLatency: 2000,
+			Primary:         true,
+		}
+		us = database.Replica{
+			ID:           uuid.MustParse("00000000-0000-0000-0000-000000000002"),
+			Hostname:     "us-2",
+			RegionID:     999,
+			RelayAddress: "http://10.0.0.2:8080",
+			Primary:      true,
+		}
+		euGateway = database.Replica{
+			ID:           uuid.MustParse("00000000-0000-0000-0000-000000000004"),
+			Hostname:     "eu-1",
+			RegionID:     998,
+			RelayAddress: "http://10.1.0.1:8080",
+			Primary:      true,
+		}
+		euFailing = database.Replica{
+			ID:           uuid.MustParse("00000000-0000-0000-0000-000000000003"),
+			Hostname:     "eu-2",
+			RegionID:     998,
+			RelayAddress: "http://10.1.0.2:8080",
+			Error:        "Failed to dial peers: relay us-1 (http://10.0.0.1:8080): timeout",
+			Primary:      true,
+		}
+		proxy = database.Replica{
+			ID:       uuid.New(),
+			Hostname: "proxy",
+			RegionID: 10001,
+			Primary:  false,
+		}
+	)
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx, cancel = context.WithTimeout(context.Background(), testutil.WaitShort)
+			report      = healthcheck.ReplicasReport{}
+			db          = dbmock.NewMockStore(gomock.NewController(t))
+		)
+		defer cancel()
+
+		db.EXPECT().GetReplicasUpdatedAfter(gomock.Any(), gomock.Any()).Return([]database.Replica{us, usGateway, euGateway, proxy}, nil)
+
+		report.Run(ctx, &healthcheck.ReplicasReportOptions{DB: db})
+
+		assert.True(t, report.Healthy)
+		assert.Nil(t, report.Error)
+		require.Len(t, report.Replicas, 3)
+		assert.Equal(t, "eu-1", report.Replicas[0].Hostname)
+		assert.True(t, report.Replicas[0].Gateway)
+		assert.Equal(t, "us-1", report.Replicas[1].Hostname)
+		assert.True(t, report.Replicas[1].Gateway)
+		assert.EqualValues(t, 2, report.Replicas[1].DatabaseLatencyMS)
+		assert.Equal(t, "us-2", report.Replicas[2].Hostname)
+		assert.False(t, report.Replicas[2].Gateway)
+	})
+
+	t.Run("Failing", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx, cancel = context.WithTimeout(context.Background(), testutil.WaitShort)
+			report      = healthcheck.ReplicasReport{}
+			db          = dbmock.NewMockStore(gomock.NewController(t))
+		)
+		defer cancel()
+
+		db.EXPECT().GetReplicasUpdatedAfter(gomock.Any(), gomock.Any()).Return([]database.Replica{usGateway, euGateway, euFailing}, nil)
+
+		report.Run(ctx, &healthcheck.ReplicasReportOptions{DB: db})
+
+		assert.False(t, report.Healthy)
+		require.Len(t, report.Replicas, 3)
+		// The failing replica has the lowest ID in its region, but can't be
+		// its gateway.
+		assert.Equal(t, "eu-1", report.Replicas[0].Hostname)
+		assert.True(t, report.Replicas[0].Gateway)
+		assert.Equal(t, "eu-2", report.Replicas[1].Hostname)
+		assert.False(t, report.Replicas[1].Gateway)
+		assert.False(t, report.Replicas[1].Healthy)
+		require.NotNil(t, report.Replicas[1].Error)
+		assert.Contains(t, *report.Replicas[1].Error, "Failed to dial peers")
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx, cancel = context.WithTimeout(context.Background(), testutil.WaitShort)
+			report      = healthcheck.ReplicasReport{}
+			db          = dbmock.NewMockStore(gomock.NewController(t))
+		)
+		defer cancel()
+
+		db.EXPECT().GetReplicasUpdatedAfter(gomock.Any(), gomock.Any()).Return(nil, xerrors.New("database error"))
+
+		report.Run(ctx, &healthcheck.ReplicasReportOptions{DB: db})
+
+		assert.False(t, report.Healthy)
+		require.NotNil(t, report.Error)
+		assert.Contains(t, *report.Error, "database error")
+	})
+}
@@ -76,13 +76,15 @@ func UserAuthorization(r *http.Request) Authorization {
 type OAuth2Configs struct {
 	Github OAuth2Config
 	OIDC   OAuth2Config
+	// OIDCProviders are the additional OIDC providers keyed by ID.
+	OIDCProviders map[string]OAuth2Config
 }
 
 func (c *OAuth2Configs) IsZero() bool {
 	if c == nil {
 		return true
 	}
-	return c.Github == nil && c.OIDC == nil
+	return c.Github == nil && c.OIDC == nil && len(c.OIDCProviders) == 0
 }
 
 const (
@@ -245,8 +247,9 @@ func ExtractAPIKey(rw http.ResponseWriter, r *http.Request, cfg ExtractAPIKeyCon
 		var err error
 		//nolint:gocritic // System needs to fetch UserLink to check if it's valid.
 		link, err = cfg.DB.GetUserLinkByUserIDLoginType(dbauthz.AsSystemRestricted(ctx), database.GetUserLinkByUserIDLoginTypeParams{
-			UserID:    key.UserID,
-			LoginType: key.LoginType,
+			UserID:     key.UserID,
+			LoginType:  key.LoginType,
+			ProviderID: key.ProviderID,
 		})
 		if err != nil {
 			return write(http.StatusInternalServerError, codersdk.Response{
@@ -270,6 +273,9 @@ func ExtractAPIKey(rw http.ResponseWriter, r *http.Request, cfg ExtractAPIKeyCon
 				oauthConfig = cfg.OAuth2Configs.Github
 			case database.LoginTypeOIDC:
 				oauthConfig = cfg.OAuth2Configs.OIDC
+				if key.ProviderID != "" {
+					oauthConfig = cfg.OAuth2Configs.OIDCProviders[key.ProviderID]
+				}
 			default:
 				return write(http.StatusInternalServerError, codersdk.Response{
 					Message: internalErrorMessage,
@@ -367,6 +373,7 @@ func ExtractAPIKey(rw http.ResponseWriter, r *http.Request, cfg ExtractAPIKeyCon
 			link, err = cfg.DB.UpdateUserLink(dbauthz.AsSystemRestricted(ctx), database.UpdateUserLinkParams{
 				UserID:            link.UserID,
 				LoginType:         link.LoginType,
+				ProviderID:        link.ProviderID,
 				OAuthAccessToken:  link.OAuthAccessToken,
 				OAuthRefreshToken: link.OAuthRefreshToken,
 				OAuthExpiry:       link.OAuthExpiry,
@@ -438,7 +445,7 @@ func ExtractAPIKey(rw http.ResponseWriter, r *http.Request, cfg ExtractAPIKeyCon
 			ID:     key.UserID.String(),
 			Roles:  rbac.RoleNames(roles.Roles),
 			Groups: roles.Groups,
-			Scope:  rbac.ScopeName(key.Scope),
+			Scope:  key.RBACScope(),
 		}.WithCachedASTValue(),
 	}
 
@@ -6,22 +6,22 @@ import (
 
 	"github.com/go-chi/chi/v5"
 
-	"github.com/coder/coder/v2/coderd/gitauth"
+	"github.com/coder/coder/v2/coderd/externalauth"
 	"github.com/coder/coder/v2/coderd/httpapi"
 )
 
 type gitAuthParamContextKey struct{}
 
-func GitAuthParam(r *http.Request) *gitauth.Config {
-	config, ok := r.Context().Value(gitAuthParamContextKey{}).(*gitauth.Config)
+func GitAuthParam(r *http.Request) *externalauth.Config {
+	config, ok := r.Context().Value(gitAuthParamContextKey{}).(*externalauth.Config)
 	if !ok {
 		panic("developer error: gitauth param middleware not provided")
 	}
 	return config
 }
 
-func ExtractGitAuthParam(configs []*gitauth.Config) func(next http.Handler) http.Handler {
-	configByID := make(map[string]*gitauth.Config)
+func ExtractGitAuthParam(configs []*externalauth.Config) func(next http.Handler) http.Handler {
+	configByID := make(map[string]*externalauth.Config)
 	for _, c := range configs {
 		configByID[c.ID] = c
 	}
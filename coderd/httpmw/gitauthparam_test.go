@@ -9,7 +9,7 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/require"
 
-	"github.com/coder/coder/v2/coderd/gitauth"
+	"github.com/coder/coder/v2/coderd/externalauth"
 	"github.com/coder/coder/v2/coderd/httpmw"
 )
 
@@ -24,7 +24,7 @@ func TestGitAuthParam(t *testing.T) {
 		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, routeCtx))
 		res := httptest.NewRecorder()
 
-		httpmw.ExtractGitAuthParam([]*gitauth.Config{{
+		httpmw.ExtractGitAuthParam([]*externalauth.Config{{
 			ID: "my-id",
 		}})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			require.Equal(t, "my-id", httpmw.GitAuthParam(r).ID)
@@ -42,7 +42,7 @@ func TestGitAuthParam(t *testing.T) {
 		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, routeCtx))
 		res := httptest.NewRecorder()
 
-		httpmw.ExtractGitAuthParam([]*gitauth.Config{})(nil).ServeHTTP(res, r)
+		httpmw.ExtractGitAuthParam([]*externalauth.Config{})(nil).ServeHTTP(res, r)
 
 		require.Equal(t, http.StatusNotFound, res.Result().StatusCode)
 	})
@@ -0,0 +1,329 @@
+package ldap
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	goldap "github.com/go-ldap/ldap/v3"
+	"golang.org/x/xerrors"
+)
+
+var (
+	// ErrInvalidCredentials is returned when the username or password is
+	// wrong. Both cases are indistinguishable to not reveal which users
+	// exist.
+	ErrInvalidCredentials = xerrors.New("invalid username or password")
+	// ErrUserNotFound is returned by Lookup when the user no longer exists
+	// or no longer matches the user filter.
+	ErrUserNotFound = xerrors.New("user not found in directory")
+)
+
+// Config configures how users are found and authenticated in a directory.
+type Config struct {
+	// URL is the "ldap://" or "ldaps://" URL of the server.
+	URL string
+	// StartTLS upgrades "ldap://" connections to TLS before binding. It is
+	// required for "ldap://" URLs, since passwords would otherwise be sent
+	// in plaintext.
+	StartTLS bool
+	// TLSConfig is used for "ldaps://" URLs and StartTLS.
+	TLSConfig *tls.Config
+	// BindDN and BindPassword are the credentials of the service account
+	// used to search for users and groups.
+	BindDN       string
+	BindPassword string
+	// BaseDN is the subtree to search for users.
+	BaseDN string
+	// UserFilter restricts the entries that are considered users, e.g.
+	// "(objectClass=person)".
+	UserFilter string
+	// UsernameAttribute is the attribute users log in with, e.g. "uid" or
+	// "sAMAccountName".
+	UsernameAttribute string
+	// EmailAttribute is the attribute containing the email of users.
+	EmailAttribute string
+	// GroupAttribute is the attribute of users and groups that lists the DNs
+	// of the groups they are a member of, e.g. "memberOf". Groups are not
+	// resolved if it is empty.
+	GroupAttribute string
+}
+
+// User is a user found in the directory.
+type User struct {
+	DN       string
+	Username string
+	Email    string
+	// Groups are the common names of the groups the user is a member of,
+	// directly or through nested groups.
+	Groups []string
+}
+
+// Validate checks that the URL and user filter are valid and that
+// connections are encrypted.
+func (c *Config) Validate() error {
+	_, err := goldap.CompileFilter(c.userFilter())
+	if err != nil {
+		return xerrors.Errorf("parse user filter: %w", err)
+	}
+	u, err := url.Parse(c.URL)
+	if err != nil {
+		return xerrors.Errorf("parse url: %w", err)
+	}
+	switch u.Scheme {
+	case "ldap":
+		if !c.StartTLS {
+			return xerrors.New("ldap:// URLs require StartTLS, otherwise passwords are sent in plaintext. Use an ldaps:// URL or enable StartTLS")
+		}
+	case "ldaps":
+		if c.StartTLS {
+			return xerrors.New("StartTLS cannot be used with an ldaps:// URL")
+		}
+	default:
+		return xerrors.Errorf("unsupported scheme %q, must be \"ldap\" or \"ldaps\"", u.Scheme)
+	}
+	return nil
+}
+
+// Connect dials the server and binds as the service account. Callers must
+// close the connection.
+func (c *Config) Connect(ctx context.Context) (*Conn, error) {
+	err := c.Validate()
+	if err != nil {
+		return nil, err
+	}
+	conn, err := Dial(ctx, c.URL, c.TLSConfig)
+	if err != nil {
+		return nil, err
+	}
+	if c.StartTLS {
+		u, _ := url.Parse(c.URL)
+		err = conn.StartTLS(tlsConfigFor(c.TLSConfig, u.Hostname()))
+		if err != nil {
+			_ = conn.Close()
+			return nil, xerrors.Errorf("start tls: %w", err)
+		}
+	}
+	err = c.bindServiceAccount(conn)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// Authenticate verifies the password of the user with the username and
+// returns the user.
+func (c *Config) Authenticate(ctx context.Context, username, password string) (User, error) {
+	// An empty password is an unauthenticated bind, which succeeds for any
+	// DN.
+	if username == "" || password == "" {
+		return User{}, ErrInvalidCredentials
+	}
+
+	conn, err := c.Connect(ctx)
+	if err != nil {
+		return User{}, err
+	}
+	defer conn.Close()
+
+	entries, err := search(conn, goldap.NewSearchRequest(
+		c.BaseDN, goldap.ScopeWholeSubtree, goldap.NeverDerefAliases, 2, 0, false,
+		fmt.Sprintf("(&%s(%s=%s))", c.userFilter(), c.UsernameAttribute, goldap.EscapeFilter(username)),
+		c.attributes(), nil,
+	))
+	if err != nil && !IsResultCode(err, goldap.LDAPResultSizeLimitExceeded) {
+		return User{}, xerrors.Errorf("search user: %w", err)
+	}
+	if len(entries) == 0 {
+		return User{}, ErrInvalidCredentials
+	}
+	if len(entries) > 1 {
+		return User{}, xerrors.Errorf("username %q matches multiple entries, the user filter or username attribute is not unique", username)
+	}
+	entry := entries[0]
+
+	err = conn.Bind(entry.DN, password)
+	if err != nil {
+		if IsResultCode(err, goldap.LDAPResultInvalidCredentials) {
+			return User{}, ErrInvalidCredentials
+		}
+		return User{}, xerrors.Errorf("bind user: %w", err)
+	}
+	// Groups are resolved with the permissions of the service account,
+	// which users may not have.
+	err = c.bindServiceAccount(conn)
+	if err != nil {
+		return User{}, err
+	}
+	return c.user(conn, entry)
+}
+
+// Lookup returns the user with the DN using a connection returned by
+// Connect. ErrUserNotFound is returned if the user no longer exists, was
+// moved out of the base DN or no longer matches the user filter, e.g.
+// because the account was disabled.
+func (c *Config) Lookup(_ context.Context, conn *Conn, dn string) (User, error) {
+	if !c.inBaseDN(dn) {
+		return User{}, ErrUserNotFound
+	}
+	entries, err := search(conn, goldap.NewSearchRequest(
+		dn, goldap.ScopeBaseObject, goldap.NeverDerefAliases, 0, 0, false,
+		c.userFilter(), c.attributes(), nil,
+	))
+	if err != nil {
+		if IsResultCode(err, goldap.LDAPResultNoSuchObject) {
+			return User{}, ErrUserNotFound
+		}
+		return User{}, xerrors.Errorf("search user: %w", err)
+	}
+	if len(entries) == 0 {
+		return User{}, ErrUserNotFound
+	}
+	return c.user(conn, entries[0])
+}
+
+// LookupUsername returns the user with the username using a connection
+// returned by Connect. It finds users whose DN changed because they were
+// renamed or moved within the base DN. ErrUserNotFound is returned if no
+// entry matches.
+func (c *Config) LookupUsername(_ context.Context, conn *Conn, username string) (User, error) {
+	entries, err := search(conn, goldap.NewSearchRequest(
+		c.BaseDN, goldap.ScopeWholeSubtree, goldap.NeverDerefAliases, 2, 0, false,
+		fmt.Sprintf("(&%s(%s=%s))", c.userFilter(), c.UsernameAttribute, goldap.EscapeFilter(username)),
+		c.attributes(), nil,
+	))
+	if err != nil && !IsResultCode(err, goldap.LDAPResultSizeLimitExceeded) {
+		return User{}, xerrors.Errorf("search user: %w", err)
+	}
+	if len(entries) == 0 {
+		return User{}, ErrUserNotFound
+	}
+	if len(entries) > 1 {
+		return User{}, xerrors.Errorf("username %q matches multiple entries, the user filter or username attribute is not unique", username)
+	}
+	return c.user(conn, entries[0])
+}
+
+// inBaseDN reports whether the DN is the base DN or below it.
+func (c *Config) inBaseDN(dn string) bool {
+	if c.BaseDN == "" {
+		return true
+	}
+	base, err := goldap.ParseDN(c.BaseDN)
+	if err != nil {
+		return false
+	}
+	parsed, err := goldap.ParseDN(dn)
+	if err != nil {
+		return false
+	}
+	return base.EqualFold(parsed) || base.AncestorOfFold(parsed)
+}
+
+func (c *Config) user(conn *Conn, entry *goldap.Entry) (User, error) {
+	groups, err := c.groups(conn, entry)
+	if err != nil {
+		return User{}, err
+	}
+	return User{
+		DN:       entry.DN,
+		Username: entry.GetEqualFoldAttributeValue(c.UsernameAttribute),
+		Email:    entry.GetEqualFoldAttributeValue(c.EmailAttribute),
+		Groups:   groups,
+	}, nil
+}
+
+// groups resolves the groups of the entry, including groups that its groups
+// are members of. Directories such as Active Directory only list direct
+// memberships in the group attribute.
+func (c *Config) groups(conn *Conn, entry *goldap.Entry) ([]string, error) {
+	if c.GroupAttribute == "" {
+		return nil, nil
+	}
+
+	var (
+		queue = entry.GetEqualFoldAttributeValues(c.GroupAttribute)
+		// DNs are case insensitive. Tracking visited groups also guards
+		// against cycles in the membership graph.
+		visited = map[string]struct{}{}
+		names   = make([]string, 0, len(queue))
+	)
+	for len(queue) > 0 {
+		dn := queue[0]
+		queue = queue[1:]
+		key := strings.ToLower(dn)
+		if _, ok := visited[key]; ok {
+			continue
+		}
+		visited[key] = struct{}{}
+
+		groups, err := search(conn, goldap.NewSearchRequest(
+			dn, goldap.ScopeBaseObject, goldap.NeverDerefAliases, 0, 0, false,
+			"(objectClass=*)", []string{"cn", c.GroupAttribute}, nil,
+		))
+		if err != nil && !IsResultCode(err, goldap.LDAPResultNoSuchObject) {
+			return nil, xerrors.Errorf("search group %q: %w", dn, err)
+		}
+		if len(groups) == 0 {
+			// The membership references a group that was deleted or that
+			// the service account cannot see.
+			continue
+		}
+		name := groups[0].GetEqualFoldAttributeValue("cn")
+		if name == "" {
+			name = rdnValue(dn)
+		}
+		names = append(names, name)
+		queue = append(queue, groups[0].GetEqualFoldAttributeValues(c.GroupAttribute)...)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// bindServiceAccount binds as the service account, or anonymously if no
+// bind password is configured.
+func (c *Config) bindServiceAccount(conn *Conn) error {
+	var err error
+	if c.BindPassword == "" {
+		err = conn.UnauthenticatedBind(c.BindDN)
+	} else {
+		err = conn.Bind(c.BindDN, c.BindPassword)
+	}
+	if err != nil {
+		return xerrors.Errorf("bind service account: %w", err)
+	}
+	return nil
+}
+
+// search returns the entries of the search. Entries found before an error
+// are returned with it, e.g. when the size limit is exceeded.
+func search(conn *Conn, req *goldap.SearchRequest) ([]*goldap.Entry, error) {
+	result, err := conn.Search(req)
+	if result == nil {
+		return nil, err
+	}
+	return result.Entries, err
+}
+
+func (c *Config) userFilter() string {
+	filter := strings.TrimSpace(c.UserFilter)
+	if filter == "" {
+		return "(objectClass=*)"
+	}
+	if !strings.HasPrefix(filter, "(") {
+		filter = "(" + filter + ")"
+	}
+	return filter
+}
+
+func (c *Config) attributes() []string {
+	attributes := []string{c.UsernameAttribute, c.EmailAttribute}
+	if c.GroupAttribute != "" {
+		attributes = append(attributes, c.GroupAttribute)
+	}
+	return attributes
+}
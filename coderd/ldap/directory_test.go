@@ -0,0 +1,258 @@
+package ldap_test
+
+import (
+	"testing"
+
+	goldap "github.com/go-ldap/ldap/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/ldap"
+	"github.com/coder/coder/v2/coderd/ldap/ldaptest"
+	"github.com/coder/coder/v2/testutil"
+)
+
+const (
+	baseDN       = "DC=example,DC=com"
+	bindDN       = "CN=coder,OU=Services,DC=example,DC=com"
+	bindPassword = "service-password"
+	aliceDN      = "CN=Alice,OU=Users,DC=example,DC=com"
+)
+
+// setupDirectory adds Alice, who is a member of "Developers", which is
+// nested in "Engineering". "Engineering" and "Everyone" are nested in each
+// other.
+func setupDirectory(t *testing.T, options *ldaptest.Options) (*ldaptest.Server, *ldap.Config) {
+	t.Helper()
+
+	srv := ldaptest.New(t, options)
+	srv.AddEntry(bindDN, "objectClass", "person", "sAMAccountName", "coder")
+	srv.SetPassword(bindDN, bindPassword)
+	srv.AddEntry(aliceDN,
+		"objectClass", "person",
+		"sAMAccountName", "alice",
+		"mail", "alice@example.com",
+		"memberOf", "CN=Developers,OU=Groups,DC=example,DC=com",
+		"memberOf", "CN=Deleted,OU=Groups,DC=example,DC=com",
+	)
+	srv.SetPassword(aliceDN, "alice-password")
+	srv.AddEntry("CN=Developers,OU=Groups,DC=example,DC=com",
+		"objectClass", "group",
+		"cn", "Developers",
+		"memberOf", "cn=engineering,ou=groups,dc=example,dc=com",
+	)
+	srv.AddEntry("CN=Engineering,OU=Groups,DC=example,DC=com",
+		"objectClass", "group",
+		"cn", "Engineering",
+		"memberOf", "CN=Everyone,OU=Groups,DC=example,DC=com",
+	)
+	srv.AddEntry("CN=Everyone,OU=Groups,DC=example,DC=com",
+		"objectClass", "group",
+		"cn", "Everyone",
+		"memberOf", "CN=Engineering,OU=Groups,DC=example,DC=com",
+	)
+
+	return srv, &ldap.Config{
+		URL:               srv.URL,
+		StartTLS:          !srv.LDAPS(),
+		TLSConfig:         srv.TLSConfig,
+		BindDN:            bindDN,
+		BindPassword:      bindPassword,
+		BaseDN:            baseDN,
+		UserFilter:        "(objectClass=person)",
+		UsernameAttribute: "sAMAccountName",
+		EmailAttribute:    "mail",
+		GroupAttribute:    "memberOf",
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("NestedGroups", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		_, cfg := setupDirectory(t, nil)
+
+		user, err := cfg.Authenticate(ctx, "alice", "alice-password")
+		require.NoError(t, err)
+		require.Equal(t, ldap.User{
+			DN:       aliceDN,
+			Username: "alice",
+			Email:    "alice@example.com",
+			Groups:   []string{"Developers", "Engineering", "Everyone"},
+		}, user)
+	})
+
+	t.Run("NoGroupAttribute", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		_, cfg := setupDirectory(t, nil)
+		cfg.GroupAttribute = ""
+
+		user, err := cfg.Authenticate(ctx, "alice", "alice-password")
+		require.NoError(t, err)
+		require.Empty(t, user.Groups)
+	})
+
+	t.Run("WrongPassword", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		_, cfg := setupDirectory(t, nil)
+
+		_, err := cfg.Authenticate(ctx, "alice", "wrong")
+		require.ErrorIs(t, err, ldap.ErrInvalidCredentials)
+	})
+
+	t.Run("EmptyPassword", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		_, cfg := setupDirectory(t, nil)
+
+		// The server accepts unauthenticated binds, so this must be
+		// rejected before binding.
+		_, err := cfg.Authenticate(ctx, "alice", "")
+		require.ErrorIs(t, err, ldap.ErrInvalidCredentials)
+	})
+
+	t.Run("UnknownUser", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		_, cfg := setupDirectory(t, nil)
+
+		_, err := cfg.Authenticate(ctx, "bob", "alice-password")
+		require.ErrorIs(t, err, ldap.ErrInvalidCredentials)
+	})
+
+	t.Run("FilterInjection", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		_, cfg := setupDirectory(t, nil)
+
+		_, err := cfg.Authenticate(ctx, "al*", "alice-password")
+		require.ErrorIs(t, err, ldap.ErrInvalidCredentials)
+	})
+
+	t.Run("UserFilter", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		_, cfg := setupDirectory(t, nil)
+		cfg.UserFilter = "(memberOf=CN=Admins,OU=Groups,DC=example,DC=com)"
+
+		_, err := cfg.Authenticate(ctx, "alice", "alice-password")
+		require.ErrorIs(t, err, ldap.ErrInvalidCredentials)
+	})
+
+	t.Run("WrongServiceAccount", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		_, cfg := setupDirectory(t, nil)
+		cfg.BindPassword = "wrong"
+
+		_, err := cfg.Authenticate(ctx, "alice", "alice-password")
+		require.Error(t, err)
+		require.NotErrorIs(t, err, ldap.ErrInvalidCredentials)
+		require.True(t, ldap.IsResultCode(err, goldap.LDAPResultInvalidCredentials))
+	})
+
+	t.Run("StartTLS", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		_, cfg := setupDirectory(t, &ldaptest.Options{RequireTLS: true})
+
+		// Passwords must never be sent in plaintext.
+		cfg.StartTLS = false
+		_, err := cfg.Authenticate(ctx, "alice", "alice-password")
+		require.ErrorContains(t, err, "require StartTLS")
+
+		cfg.StartTLS = true
+		user, err := cfg.Authenticate(ctx, "alice", "alice-password")
+		require.NoError(t, err)
+		require.Equal(t, aliceDN, user.DN)
+	})
+
+	t.Run("LDAPS", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		_, cfg := setupDirectory(t, &ldaptest.Options{LDAPS: true, RequireTLS: true})
+
+		user, err := cfg.Authenticate(ctx, "alice", "alice-password")
+		require.NoError(t, err)
+		require.Equal(t, aliceDN, user.DN)
+
+		// The certificate of the server must be trusted.
+		cfg.TLSConfig = nil
+		_, err = cfg.Authenticate(ctx, "alice", "alice-password")
+		require.ErrorContains(t, err, "certificate")
+	})
+}
+
+func TestLookup(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	srv, cfg := setupDirectory(t, nil)
+
+	conn, err := cfg.Connect(ctx)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	user, err := cfg.Lookup(ctx, conn, aliceDN)
+	require.NoError(t, err)
+	require.Equal(t, "alice", user.Username)
+	require.Equal(t, []string{"Developers", "Engineering", "Everyone"}, user.Groups)
+
+	// Entries outside of the base DN are not users, even if the service
+	// account can see them.
+	const outsideDN = "CN=Alice,OU=Users,DC=other,DC=com"
+	srv.AddEntry(outsideDN, "objectClass", "person", "sAMAccountName", "alice")
+	_, err = cfg.Lookup(ctx, conn, outsideDN)
+	require.ErrorIs(t, err, ldap.ErrUserNotFound)
+
+	// Users that no longer match the user filter are treated as removed.
+	srv.AddEntry(aliceDN, "objectClass", "disabledPerson", "sAMAccountName", "alice")
+	_, err = cfg.Lookup(ctx, conn, aliceDN)
+	require.ErrorIs(t, err, ldap.ErrUserNotFound)
+
+	srv.DeleteEntry(aliceDN)
+	_, err = cfg.Lookup(ctx, conn, aliceDN)
+	require.ErrorIs(t, err, ldap.ErrUserNotFound)
+}
+
+func TestLookupUsername(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	srv, cfg := setupDirectory(t, nil)
+
+	conn, err := cfg.Connect(ctx)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// Alice was moved to another organizational unit.
+	const movedDN = "CN=Alice,OU=Engineers,DC=example,DC=com"
+	srv.DeleteEntry(aliceDN)
+	srv.AddEntry(movedDN, "objectClass", "person", "sAMAccountName", "alice", "mail", "alice@example.com")
+	user, err := cfg.LookupUsername(ctx, conn, "alice")
+	require.NoError(t, err)
+	require.Equal(t, movedDN, user.DN)
+	require.Equal(t, "alice@example.com", user.Email)
+
+	_, err = cfg.LookupUsername(ctx, conn, "bob")
+	require.ErrorIs(t, err, ldap.ErrUserNotFound)
+
+	// Entries outside of the base DN are not found.
+	srv.DeleteEntry(movedDN)
+	srv.AddEntry("CN=Alice,OU=Users,DC=other,DC=com", "objectClass", "person", "sAMAccountName", "alice")
+	_, err = cfg.LookupUsername(ctx, conn, "alice")
+	require.ErrorIs(t, err, ldap.ErrUserNotFound)
+}
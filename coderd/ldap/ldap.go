@@ -0,0 +1,108 @@
+// Package ldap authenticates users against a directory such as Active
+// Directory, using github.com/go-ldap/ldap/v3 as the client.
+package ldap
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/url"
+
+	goldap "github.com/go-ldap/ldap/v3"
+	"golang.org/x/xerrors"
+)
+
+// Conn is a connection to the directory. It is closed when the context it
+// was dialed with is done.
+type Conn struct {
+	*goldap.Conn
+	stop chan struct{}
+}
+
+// Close sends an unbind request and closes the connection.
+func (c *Conn) Close() error {
+	select {
+	case <-c.stop:
+	default:
+		close(c.stop)
+	}
+	return c.Conn.Close()
+}
+
+// Dial connects to the server at the URL, which must use the "ldap" or
+// "ldaps" scheme. The TLS config is used for "ldaps" URLs.
+func Dial(ctx context.Context, rawURL string, tlsConfig *tls.Config) (*Conn, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, xerrors.Errorf("parse url: %w", err)
+	}
+	host := u.Host
+	var dialer interface {
+		DialContext(ctx context.Context, network, address string) (net.Conn, error)
+	}
+	switch u.Scheme {
+	case "ldap":
+		if u.Port() == "" {
+			host = net.JoinHostPort(u.Hostname(), goldap.DefaultLdapPort)
+		}
+		dialer = &net.Dialer{}
+	case "ldaps":
+		if u.Port() == "" {
+			host = net.JoinHostPort(u.Hostname(), goldap.DefaultLdapsPort)
+		}
+		dialer = &tls.Dialer{Config: tlsConfigFor(tlsConfig, u.Hostname())}
+	default:
+		return nil, xerrors.Errorf("unsupported scheme %q, must be \"ldap\" or \"ldaps\"", u.Scheme)
+	}
+	netConn, err := dialer.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return nil, xerrors.Errorf("dial %q: %w", host, err)
+	}
+
+	conn := &Conn{
+		Conn: goldap.NewConn(netConn, u.Scheme == "ldaps"),
+		stop: make(chan struct{}),
+	}
+	conn.Start()
+	// The client does not take contexts, so pending operations are
+	// interrupted by closing the connection instead.
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = conn.Conn.Close()
+		case <-conn.stop:
+		}
+	}()
+	return conn, nil
+}
+
+// IsResultCode reports whether err wraps an error returned by the server
+// with the result code, e.g. goldap.LDAPResultInvalidCredentials.
+func IsResultCode(err error, code uint16) bool {
+	var ldapErr *goldap.Error
+	return xerrors.As(err, &ldapErr) && ldapErr.ResultCode == code
+}
+
+// rdnValue returns the value of the first relative distinguished name of
+// the DN, e.g. "Admins" for "CN=Admins,OU=Groups,DC=example,DC=com".
+func rdnValue(dn string) string {
+	parsed, err := goldap.ParseDN(dn)
+	if err != nil || len(parsed.RDNs) == 0 || len(parsed.RDNs[0].Attributes) == 0 {
+		return ""
+	}
+	return parsed.RDNs[0].Attributes[0].Value
+}
+
+func tlsConfigFor(tlsConfig *tls.Config, serverName string) *tls.Config {
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{
+			MinVersion: tls.VersionTLS12,
+		}
+	} else {
+		tlsConfig = tlsConfig.Clone()
+	}
+	if tlsConfig.ServerName == "" {
+		tlsConfig.ServerName = serverName
+	}
+	return tlsConfig
+}
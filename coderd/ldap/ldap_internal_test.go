@@ -0,0 +1,16 @@
+package ldap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRDNValue(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "Admins", rdnValue("CN=Admins,OU=Groups,DC=example,DC=com"))
+	require.Equal(t, "Smith, John", rdnValue(`CN=Smith\, John,OU=Users,DC=example,DC=com`))
+	require.Equal(t, "a+b", rdnValue(`cn=a\2bb`))
+	require.Equal(t, "", rdnValue("invalid"))
+}
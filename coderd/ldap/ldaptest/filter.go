@@ -0,0 +1,203 @@
+package ldaptest
+
+import (
+	"strconv"
+	"strings"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	goldap "github.com/go-ldap/ldap/v3"
+	"golang.org/x/xerrors"
+)
+
+// Matching rules of Active Directory that compare the bits of an integer
+// attribute, e.g. to exclude disabled accounts with
+// "(!(userAccountControl:1.2.840.113556.1.4.803:=2))".
+const (
+	matchingRuleBitAnd = "1.2.840.113556.1.4.803"
+	matchingRuleBitOr  = "1.2.840.113556.1.4.804"
+)
+
+// filter is a decoded search filter.
+type filter struct {
+	Type ber.Tag
+	// Children are the operands of and, or and not filters.
+	Children []filter
+	// Attribute is the attribute the filter applies to.
+	Attribute string
+	// Value is the asserted value of equality, ordering, approximate and
+	// extensible filters.
+	Value string
+	// Initial, Any and Final are the parts of a substrings filter.
+	Initial string
+	Any     []string
+	Final   string
+	// MatchingRule and DNAttributes are used by extensible filters.
+	MatchingRule string
+	DNAttributes bool
+}
+
+// decodeFilter decodes the protocol encoding of a filter.
+func decodeFilter(p *ber.Packet) (filter, error) {
+	if p.ClassType != ber.ClassContext {
+		return filter{}, xerrors.Errorf("filter has class %d, want context", p.ClassType)
+	}
+	f := filter{Type: p.Tag}
+	switch f.Type {
+	case goldap.FilterAnd, goldap.FilterOr, goldap.FilterNot:
+		for _, child := range p.Children {
+			decoded, err := decodeFilter(child)
+			if err != nil {
+				return filter{}, err
+			}
+			f.Children = append(f.Children, decoded)
+		}
+		if f.Type == goldap.FilterNot && len(f.Children) != 1 {
+			return filter{}, xerrors.New("not filter must have exactly one operand")
+		}
+	case goldap.FilterPresent:
+		f.Attribute = p.Data.String()
+	case goldap.FilterSubstrings:
+		if len(p.Children) != 2 {
+			return filter{}, xerrors.New("malformed substrings filter")
+		}
+		f.Attribute = p.Children[0].Data.String()
+		for _, part := range p.Children[1].Children {
+			switch part.Tag {
+			case goldap.FilterSubstringsInitial:
+				f.Initial = part.Data.String()
+			case goldap.FilterSubstringsAny:
+				f.Any = append(f.Any, part.Data.String())
+			case goldap.FilterSubstringsFinal:
+				f.Final = part.Data.String()
+			}
+		}
+	case goldap.FilterExtensibleMatch:
+		for _, part := range p.Children {
+			switch part.Tag {
+			case 1:
+				f.MatchingRule = part.Data.String()
+			case 2:
+				f.Attribute = part.Data.String()
+			case 3:
+				f.Value = part.Data.String()
+			case 4:
+				f.DNAttributes = part.Data.Len() == 1 && part.Data.Bytes()[0] != 0
+			}
+		}
+	case goldap.FilterEqualityMatch, goldap.FilterGreaterOrEqual, goldap.FilterLessOrEqual, goldap.FilterApproxMatch:
+		if len(p.Children) != 2 {
+			return filter{}, xerrors.New("malformed attribute value assertion")
+		}
+		f.Attribute = p.Children[0].Data.String()
+		f.Value = p.Children[1].Data.String()
+	default:
+		return filter{}, xerrors.Errorf("unknown filter type %d", p.Tag)
+	}
+	return f, nil
+}
+
+// match reports whether the entry matches the filter. Values are compared
+// case insensitively, as most directory attributes are. Integers are
+// compared numerically by ordering filters.
+func (f filter) match(entry *goldap.Entry) bool {
+	switch f.Type {
+	case goldap.FilterAnd:
+		for _, child := range f.Children {
+			if !child.match(entry) {
+				return false
+			}
+		}
+		return true
+	case goldap.FilterOr:
+		for _, child := range f.Children {
+			if child.match(entry) {
+				return true
+			}
+		}
+		return false
+	case goldap.FilterNot:
+		return !f.Children[0].match(entry)
+	case goldap.FilterPresent:
+		if strings.EqualFold(f.Attribute, "objectClass") {
+			return true
+		}
+		return len(entry.GetEqualFoldAttributeValues(f.Attribute)) > 0
+	}
+
+	values := entry.GetEqualFoldAttributeValues(f.Attribute)
+	if f.Type == goldap.FilterExtensibleMatch && f.DNAttributes {
+		dn, err := goldap.ParseDN(entry.DN)
+		if err == nil && len(dn.RDNs) > 0 {
+			for _, attr := range dn.RDNs[0].Attributes {
+				values = append(values, attr.Value)
+			}
+		}
+	}
+	for _, value := range values {
+		if f.matchValue(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f filter) matchValue(value string) bool {
+	switch f.Type {
+	case goldap.FilterEqualityMatch, goldap.FilterApproxMatch:
+		return strings.EqualFold(value, f.Value)
+	case goldap.FilterSubstrings:
+		value = strings.ToLower(value)
+		if !strings.HasPrefix(value, strings.ToLower(f.Initial)) {
+			return false
+		}
+		value = value[len(f.Initial):]
+		for _, part := range f.Any {
+			i := strings.Index(value, strings.ToLower(part))
+			if i < 0 {
+				return false
+			}
+			value = value[i+len(part):]
+		}
+		return strings.HasSuffix(value, strings.ToLower(f.Final))
+	case goldap.FilterGreaterOrEqual, goldap.FilterLessOrEqual:
+		cmp := compareValues(value, f.Value)
+		if f.Type == goldap.FilterGreaterOrEqual {
+			return cmp >= 0
+		}
+		return cmp <= 0
+	case goldap.FilterExtensibleMatch:
+		switch f.MatchingRule {
+		case matchingRuleBitAnd, matchingRuleBitOr:
+			have, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return false
+			}
+			want, err := strconv.ParseInt(f.Value, 10, 64)
+			if err != nil {
+				return false
+			}
+			if f.MatchingRule == matchingRuleBitAnd {
+				return have&want == want
+			}
+			return have&want != 0
+		default:
+			return strings.EqualFold(value, f.Value)
+		}
+	}
+	return false
+}
+
+func compareValues(a, b string) int {
+	ai, aErr := strconv.ParseInt(a, 10, 64)
+	bi, bErr := strconv.ParseInt(b, 10, 64)
+	if aErr == nil && bErr == nil {
+		switch {
+		case ai < bi:
+			return -1
+		case ai > bi:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
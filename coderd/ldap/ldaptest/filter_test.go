@@ -0,0 +1,61 @@
+package ldaptest
+
+import (
+	"testing"
+
+	goldap "github.com/go-ldap/ldap/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterMatch(t *testing.T) {
+	t.Parallel()
+
+	entry := goldap.NewEntry("CN=Alice,OU=Users,DC=example,DC=com", map[string][]string{
+		"objectClass":        {"top", "person"},
+		"sAMAccountName":     {"alice"},
+		"mail":               {"alice@example.com"},
+		"userAccountControl": {"512"},
+		"description":        {"a (b) *c*"},
+	})
+
+	for _, tc := range []struct {
+		Filter string
+		Match  bool
+	}{
+		{"(objectClass=person)", true},
+		{"(OBJECTCLASS=Person)", true},
+		{"(objectClass=group)", false},
+		{"(mail=*)", true},
+		{"(telephoneNumber=*)", false},
+		{"(mail=alice@*)", true},
+		{"(mail=*@example.com)", true},
+		{"(mail=a*e@*.com)", true},
+		{"(mail=bob@*)", false},
+		{"(&(objectClass=person)(sAMAccountName=alice))", true},
+		{"(&(objectClass=person)(sAMAccountName=bob))", false},
+		{"(|(sAMAccountName=bob)(sAMAccountName=alice))", true},
+		{"(!(sAMAccountName=alice))", false},
+		{"(userAccountControl>=500)", true},
+		{"(userAccountControl<=500)", false},
+		{"(description=a \\28b\\29 \\2ac\\2a)", true},
+		{"(userAccountControl:1.2.840.113556.1.4.803:=2)", false},
+		{"(userAccountControl:1.2.840.113556.1.4.803:=512)", true},
+		{"(userAccountControl:1.2.840.113556.1.4.804:=514)", true},
+		{"(&(objectClass=person)(!(userAccountControl:1.2.840.113556.1.4.803:=2)))", true},
+		{"(cn:dn:=Alice)", true},
+		{"(sAMAccountName=" + goldap.EscapeFilter("*") + ")", false},
+	} {
+		tc := tc
+		t.Run(tc.Filter, func(t *testing.T) {
+			t.Parallel()
+
+			// Filters are matched as the server receives them from the
+			// client.
+			packet, err := goldap.CompileFilter(tc.Filter)
+			require.NoError(t, err)
+			f, err := decodeFilter(packet)
+			require.NoError(t, err)
+			require.Equal(t, tc.Match, f.match(entry))
+		})
+	}
+}
@@ -0,0 +1,420 @@
+// Package ldaptest provides an in-process LDAP server for tests.
+package ldaptest
+
+import (
+	"bufio"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	goldap "github.com/go-ldap/ldap/v3"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/testutil"
+)
+
+// oidStartTLS is the name of the StartTLS extended operation.
+const oidStartTLS = "1.3.6.1.4.1.1466.20037"
+
+type Options struct {
+	// LDAPS serves TLS from the start of the connection. Otherwise TLS is
+	// only available through StartTLS.
+	LDAPS bool
+	// RequireTLS rejects binds on connections that are not encrypted.
+	RequireTLS bool
+}
+
+// Server is an LDAP server that supports simple binds, searches and
+// StartTLS. Searches require a successful bind.
+type Server struct {
+	// URL is the "ldap://" or "ldaps://" URL of the server.
+	URL string
+	// TLSConfig trusts the certificate of the server.
+	TLSConfig *tls.Config
+
+	t          testing.TB
+	options    Options
+	serverTLS  *tls.Config
+	listener   net.Listener
+	wg         sync.WaitGroup
+	mutex      sync.Mutex
+	closed     bool
+	conns      map[net.Conn]struct{}
+	entries    map[string]*goldap.Entry
+	passwords  map[string]string
+	searchDNs  []string
+	closedOnce sync.Once
+}
+
+// New starts a server that is closed when the test finishes.
+func New(t testing.TB, options *Options) *Server {
+	t.Helper()
+	if options == nil {
+		options = &Options{}
+	}
+
+	cert := testutil.GenerateTLSCertificate(t, "localhost")
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(t, err)
+	pool := x509.NewCertPool()
+	pool.AddCert(leaf)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	s := &Server{
+		URL: "ldap://" + listener.Addr().String(),
+		TLSConfig: &tls.Config{
+			MinVersion: tls.VersionTLS12,
+			RootCAs:    pool,
+		},
+		t:       t,
+		options: *options,
+		serverTLS: &tls.Config{
+			MinVersion:   tls.VersionTLS12,
+			Certificates: []tls.Certificate{cert},
+		},
+		listener:  listener,
+		conns:     map[net.Conn]struct{}{},
+		entries:   map[string]*goldap.Entry{},
+		passwords: map[string]string{},
+	}
+	if options.LDAPS {
+		s.URL = "ldaps://" + listener.Addr().String()
+	}
+
+	s.wg.Add(1)
+	go s.serve()
+	t.Cleanup(s.Close)
+	return s
+}
+
+// LDAPS reports whether the server serves TLS from the start of the
+// connection. Otherwise clients must use StartTLS.
+func (s *Server) LDAPS() bool {
+	return s.options.LDAPS
+}
+
+// AddEntry adds or replaces the entry with the DN. Attributes may be
+// passed as pairs of names and values, e.g. "cn", "Alice", "memberOf", dn.
+// Repeated names add values to the attribute.
+func (s *Server) AddEntry(dn string, attributes ...string) {
+	require.True(s.t, len(attributes)%2 == 0, "attributes must be pairs of names and values")
+
+	entry := &goldap.Entry{DN: dn}
+	for i := 0; i < len(attributes); i += 2 {
+		name, value := attributes[i], attributes[i+1]
+		added := false
+		for _, attr := range entry.Attributes {
+			if strings.EqualFold(attr.Name, name) {
+				attr.Values = append(attr.Values, value)
+				added = true
+			}
+		}
+		if !added {
+			entry.Attributes = append(entry.Attributes, goldap.NewEntryAttribute(name, []string{value}))
+		}
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.entries[normalizeDN(dn)] = entry
+}
+
+// SetPassword sets the password the entry with the DN binds with.
+func (s *Server) SetPassword(dn, password string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.passwords[normalizeDN(dn)] = password
+}
+
+// DeleteEntry removes the entry with the DN.
+func (s *Server) DeleteEntry(dn string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.entries, normalizeDN(dn))
+	delete(s.passwords, normalizeDN(dn))
+}
+
+// SearchBaseDNs returns the base DNs of all searches received so far.
+func (s *Server) SearchBaseDNs() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]string(nil), s.searchDNs...)
+}
+
+// Close stops the server and closes all connections.
+func (s *Server) Close() {
+	s.closedOnce.Do(func() {
+		_ = s.listener.Close()
+		s.mutex.Lock()
+		s.closed = true
+		for conn := range s.conns {
+			_ = conn.Close()
+		}
+		s.mutex.Unlock()
+		s.wg.Wait()
+	})
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		if s.options.LDAPS {
+			conn = tls.Server(conn, s.serverTLS)
+		}
+		s.mutex.Lock()
+		if s.closed {
+			s.mutex.Unlock()
+			_ = conn.Close()
+			return
+		}
+		s.conns[conn] = struct{}{}
+		s.mutex.Unlock()
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.handle(conn)
+		}()
+	}
+}
+
+// session is the state of a single connection.
+type session struct {
+	conn    net.Conn
+	reader  *bufio.Reader
+	tls     bool
+	boundDN string
+}
+
+func (s *Server) handle(conn net.Conn) {
+	sess := &session{
+		conn:   conn,
+		reader: bufio.NewReader(conn),
+		tls:    s.options.LDAPS,
+	}
+	defer func() {
+		s.mutex.Lock()
+		delete(s.conns, sess.conn)
+		s.mutex.Unlock()
+		_ = sess.conn.Close()
+	}()
+
+	for {
+		message, err := ber.ReadPacket(sess.reader)
+		if err != nil || len(message.Children) < 2 {
+			return
+		}
+		id, ok := message.Children[0].Value.(int64)
+		op := message.Children[1]
+		if !ok || op.ClassType != ber.ClassApplication {
+			return
+		}
+		switch op.Tag {
+		case goldap.ApplicationBindRequest:
+			s.write(sess, id, encodeResult(goldap.ApplicationBindResponse, s.bind(sess, op)))
+		case goldap.ApplicationSearchRequest:
+			entries, result := s.search(sess, op)
+			for _, entry := range entries {
+				s.write(sess, id, encodeEntry(entry))
+			}
+			s.write(sess, id, encodeResult(goldap.ApplicationSearchResultDone, result))
+		case goldap.ApplicationExtendedRequest:
+			if len(op.Children) == 0 || op.Children[0].Data.String() != oidStartTLS || sess.tls {
+				s.write(sess, id, encodeResult(goldap.ApplicationExtendedResponse, &goldap.Error{
+					ResultCode: goldap.LDAPResultProtocolError,
+					Err:        xerrors.New("unsupported extended operation"),
+				}))
+				continue
+			}
+			s.write(sess, id, encodeResult(goldap.ApplicationExtendedResponse, nil))
+			tlsConn := tls.Server(sess.conn, s.serverTLS)
+			if err := tlsConn.Handshake(); err != nil {
+				return
+			}
+			s.mutex.Lock()
+			delete(s.conns, sess.conn)
+			s.conns[tlsConn] = struct{}{}
+			s.mutex.Unlock()
+			sess.conn = tlsConn
+			sess.reader = bufio.NewReader(tlsConn)
+			sess.tls = true
+		default:
+			// Includes unbind requests, after which the client closes the
+			// connection.
+			return
+		}
+	}
+}
+
+func (*Server) write(sess *session, id int64, op *ber.Packet) {
+	message := ber.NewSequence("LDAP Response")
+	message.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, id, "MessageID"))
+	message.AppendChild(op)
+	_, _ = sess.conn.Write(message.Bytes())
+}
+
+func (s *Server) bind(sess *session, op *ber.Packet) *goldap.Error {
+	if len(op.Children) < 3 {
+		return &goldap.Error{ResultCode: goldap.LDAPResultProtocolError, Err: xerrors.New("malformed bind request")}
+	}
+	if s.options.RequireTLS && !sess.tls {
+		return &goldap.Error{ResultCode: goldap.LDAPResultConfidentialityRequired, Err: xerrors.New("TLS is required")}
+	}
+	dn, password := op.Children[1].Data.String(), op.Children[2].Data.String()
+	if password == "" {
+		// Unauthenticated bind, which real servers allow for any DN.
+		sess.boundDN = ""
+		return nil
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	want, ok := s.passwords[normalizeDN(dn)]
+	if !ok || want != password {
+		sess.boundDN = ""
+		return &goldap.Error{ResultCode: goldap.LDAPResultInvalidCredentials, Err: xerrors.New("invalid credentials")}
+	}
+	sess.boundDN = dn
+	return nil
+}
+
+func (s *Server) search(sess *session, op *ber.Packet) ([]*goldap.Entry, *goldap.Error) {
+	if len(op.Children) < 8 {
+		return nil, &goldap.Error{ResultCode: goldap.LDAPResultProtocolError, Err: xerrors.New("malformed search request")}
+	}
+	if sess.boundDN == "" {
+		return nil, &goldap.Error{ResultCode: goldap.LDAPResultInsufficientAccessRights, Err: xerrors.New("bind required")}
+	}
+	baseDN := op.Children[0].Data.String()
+	scope, _ := op.Children[1].Value.(int64)
+	sizeLimit, _ := op.Children[3].Value.(int64)
+	filter, err := decodeFilter(op.Children[6])
+	if err != nil {
+		return nil, &goldap.Error{ResultCode: goldap.LDAPResultProtocolError, Err: err}
+	}
+	var attributes []string
+	for _, attr := range op.Children[7].Children {
+		attributes = append(attributes, attr.Data.String())
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.searchDNs = append(s.searchDNs, baseDN)
+
+	base := normalizeDN(baseDN)
+	if !s.exists(base) {
+		return nil, &goldap.Error{ResultCode: goldap.LDAPResultNoSuchObject, Err: xerrors.New("no such object")}
+	}
+
+	var entries []*goldap.Entry
+	for key, entry := range s.entries {
+		if !inScope(key, base, int(scope)) || !filter.match(entry) {
+			continue
+		}
+		if sizeLimit > 0 && int64(len(entries)) == sizeLimit {
+			return entries, &goldap.Error{ResultCode: goldap.LDAPResultSizeLimitExceeded, Err: xerrors.New("size limit exceeded")}
+		}
+		entries = append(entries, selectAttributes(entry, attributes))
+	}
+	return entries, nil
+}
+
+// encodeResult encodes an LDAPResult as the protocol operation with the
+// application tag. A nil error is a success.
+func encodeResult(tag ber.Tag, result *goldap.Error) *ber.Packet {
+	code, message := uint16(goldap.LDAPResultSuccess), ""
+	if result != nil {
+		code, message = result.ResultCode, result.Err.Error()
+	}
+	op := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "")
+	op.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(code), "resultCode"))
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "matchedDN"))
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, message, "diagnosticMessage"))
+	return op
+}
+
+// encodeEntry encodes a SearchResultEntry.
+func encodeEntry(entry *goldap.Entry) *ber.Packet {
+	attributes := ber.NewSequence("attributes")
+	for _, attr := range entry.Attributes {
+		values := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "vals")
+		for _, value := range attr.Values {
+			values.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, value, ""))
+		}
+		attribute := ber.NewSequence("attribute")
+		attribute.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, attr.Name, "type"))
+		attribute.AppendChild(values)
+		attributes.AppendChild(attribute)
+	}
+	op := ber.Encode(ber.ClassApplication, ber.TypeConstructed, goldap.ApplicationSearchResultEntry, nil, "")
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, entry.DN, "objectName"))
+	op.AppendChild(attributes)
+	return op
+}
+
+// exists reports whether the DN is an entry or an ancestor of one, so tests
+// do not need to add the entries of the directory tree.
+func (s *Server) exists(dn string) bool {
+	if dn == "" {
+		return true
+	}
+	for key := range s.entries {
+		if key == dn || strings.HasSuffix(key, ","+dn) {
+			return true
+		}
+	}
+	return false
+}
+
+func inScope(dn, base string, scope int) bool {
+	switch scope {
+	case goldap.ScopeBaseObject:
+		return dn == base
+	case goldap.ScopeSingleLevel:
+		_, parent, _ := strings.Cut(dn, ",")
+		return parent == base
+	default:
+		return base == "" || dn == base || strings.HasSuffix(dn, ","+base)
+	}
+}
+
+func selectAttributes(entry *goldap.Entry, attributes []string) *goldap.Entry {
+	if len(attributes) == 0 {
+		return entry
+	}
+	selected := &goldap.Entry{DN: entry.DN}
+	for _, attr := range entry.Attributes {
+		for _, name := range attributes {
+			if name == "*" || strings.EqualFold(attr.Name, name) {
+				selected.Attributes = append(selected.Attributes, attr)
+				break
+			}
+		}
+	}
+	return selected
+}
+
+// normalizeDN lowercases the DN and removes spaces around separators, so
+// equivalent DNs compare equal.
+func normalizeDN(dn string) string {
+	if dn == "" {
+		return ""
+	}
+	parts := strings.Split(dn, ",")
+	for i, part := range parts {
+		name, value, _ := strings.Cut(part, "=")
+		parts[i] = strings.TrimSpace(name) + "=" + strings.TrimSpace(value)
+	}
+	return strings.ToLower(strings.Join(parts, ","))
+}
@@ -0,0 +1,196 @@
+package coderd
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/go-multierror"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/ldap"
+	"github.com/coder/coder/v2/coderd/rbac"
+)
+
+// startLDAPSync periodically syncs LDAP users with the directory until the
+// returned function is called.
+func (api *API) startLDAPSync(ctx context.Context, interval time.Duration) func() {
+	logger := api.Logger.Named("ldapsync")
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	done := make(chan struct{})
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer close(done)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			startTime := time.Now()
+			err := api.syncLDAPUsers(ctx, logger)
+			if err != nil && ctx.Err() == nil {
+				logger.Error(ctx, "sync ldap users", slog.Error(err))
+				continue
+			}
+			logger.Debug(ctx, "syncing ldap users is done", slog.F("execution_time", time.Since(startTime)))
+		}
+	}()
+
+	return func() {
+		cancelFunc()
+		<-done
+	}
+}
+
+// ldapSyncSuspendGuard is the number of users a sync may suspend at once
+// regardless of how many LDAP users there are. Beyond it, a sync that would
+// suspend more than half of the LDAP users suspends none, since that is more
+// likely a misconfigured base DN or user filter, or a directory outage, than
+// actual departures.
+const ldapSyncSuspendGuard = 10
+
+// syncLDAPUsers suspends users that were removed from the directory or no
+// longer match the user filter, and updates the groups and roles of the
+// others. Users are not reactivated, as they may have been suspended by an
+// admin. Errors of individual users are returned after the others were
+// synced.
+func (api *API) syncLDAPUsers(ctx context.Context, logger slog.Logger) error {
+	cfg := api.LDAPConfig
+	//nolint:gocritic // The sync is a system function.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+
+	links, err := api.Database.GetUserLinksByLoginType(ctx, database.LoginTypeLDAP)
+	if err != nil {
+		return xerrors.Errorf("get ldap user links: %w", err)
+	}
+	if len(links) == 0 {
+		return nil
+	}
+
+	conn, err := cfg.Connect(ctx)
+	if err != nil {
+		return xerrors.Errorf("connect: %w", err)
+	}
+	defer conn.Close()
+
+	var (
+		merr    error
+		active  int
+		removed []database.User
+	)
+	for _, link := range links {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		user, err := api.Database.GetUserByID(ctx, link.UserID)
+		if err != nil {
+			merr = multierror.Append(merr, xerrors.Errorf("get user %q: %w", link.UserID, err))
+			continue
+		}
+		if user.Deleted || user.Status == database.UserStatusSuspended || user.LoginType != database.LoginTypeLDAP {
+			continue
+		}
+		active++
+
+		entry, err := api.lookupLDAPUser(ctx, conn, user, link)
+		if errors.Is(err, ldap.ErrUserNotFound) {
+			removed = append(removed, user)
+			continue
+		}
+		if err != nil {
+			merr = multierror.Append(merr, xerrors.Errorf("lookup %q: %w", user.Username, err))
+			continue
+		}
+
+		err = api.Database.InTx(func(tx database.Store) error {
+			return api.syncLDAPGroupsAndRoles(ctx, logger, tx, user.ID, entry)
+		}, nil)
+		if err != nil {
+			merr = multierror.Append(merr, xerrors.Errorf("sync groups and roles of %q: %w", user.Username, err))
+		}
+	}
+
+	if len(removed) > ldapSyncSuspendGuard && len(removed)*2 > active {
+		return multierror.Append(merr, xerrors.Errorf(
+			"refusing to suspend %d of %d ldap users at once, check that the base dn and user filter are correct", len(removed), active,
+		))
+	}
+	for _, user := range removed {
+		_, err = api.Database.UpdateUserStatus(ctx, database.UpdateUserStatusParams{
+			ID:        user.ID,
+			Status:    database.UserStatusSuspended,
+			UpdatedAt: dbtime.Now(),
+		})
+		if err != nil {
+			merr = multierror.Append(merr, xerrors.Errorf("suspend user %q: %w", user.Username, err))
+			continue
+		}
+		logger.Info(ctx, "user was removed from the directory and has been suspended",
+			slog.F("username", user.Username))
+	}
+	return merr
+}
+
+// lookupLDAPUser returns the directory entry of the user. The DN of the link
+// changes when the user is renamed or moved in the directory, so the entry
+// is searched by username before the user is considered removed. Entries
+// found this way must have the email of the user, as usernames may be
+// reassigned.
+func (api *API) lookupLDAPUser(ctx context.Context, conn *ldap.Conn, user database.User, link database.UserLink) (ldap.User, error) {
+	cfg := api.LDAPConfig
+	entry, err := cfg.Lookup(ctx, conn, link.LinkedID)
+	if !errors.Is(err, ldap.ErrUserNotFound) {
+		return entry, err
+	}
+	entry, err = cfg.LookupUsername(ctx, conn, user.Username)
+	if err != nil {
+		return ldap.User{}, err
+	}
+	if !strings.EqualFold(entry.Email, user.Email) {
+		return ldap.User{}, ldap.ErrUserNotFound
+	}
+	_, err = api.Database.UpdateUserLinkedID(ctx, database.UpdateUserLinkedIDParams{
+		UserID:     link.UserID,
+		LoginType:  link.LoginType,
+		ProviderID: link.ProviderID,
+		LinkedID:   entry.DN,
+	})
+	if err != nil {
+		return ldap.User{}, xerrors.Errorf("update linked id: %w", err)
+	}
+	return entry, nil
+}
+
+func (api *API) syncLDAPGroupsAndRoles(ctx context.Context, logger slog.Logger, tx database.Store, userID uuid.UUID, entry ldap.User) error {
+	cfg := api.LDAPConfig
+	groups, roles := cfg.mapGroups(entry.Groups)
+	if cfg.GroupSyncEnabled() {
+		err := api.Options.SetUserGroups(ctx, logger, tx, userID, groups, cfg.CreateMissingGroups)
+		if err != nil {
+			return xerrors.Errorf("set user groups: %w", err)
+		}
+	}
+	if cfg.RoleSyncEnabled() {
+		filtered := make([]string, 0, len(roles))
+		for _, role := range roles {
+			if _, err := rbac.RoleByName(role); err == nil {
+				filtered = append(filtered, role)
+			}
+		}
+		err := api.Options.SetUserSiteRoles(ctx, logger, tx, userID, filtered)
+		if err != nil {
+			return xerrors.Errorf("set user site roles: %w", err)
+		}
+	}
+	return nil
+}
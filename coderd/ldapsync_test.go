@@ -0,0 +1,109 @@
+package coderd_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestLDAPSync(t *testing.T) {
+	t.Parallel()
+
+	srv, cfg := setupLDAP(t)
+	cfg.SyncInterval = testutil.IntervalFast
+	client := coderdtest.New(t, &coderdtest.Options{LDAPConfig: cfg})
+	_ = coderdtest.CreateFirstUser(t, client)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	ldapClient := codersdk.New(client.URL)
+	res, err := ldapClient.LoginWithLDAP(ctx, codersdk.LoginWithLDAPRequest{
+		Username: "alice",
+		Password: "alice-password",
+	})
+	require.NoError(t, err)
+	ldapClient.SetSessionToken(res.SessionToken)
+	user, err := ldapClient.User(ctx, codersdk.Me)
+	require.NoError(t, err)
+	require.Equal(t, codersdk.UserStatusActive, user.Status)
+
+	srv.DeleteEntry(ldapAliceDN)
+	require.Eventually(t, func() bool {
+		user, err := client.User(ctx, user.ID.String())
+		return err == nil && user.Status == codersdk.UserStatusSuspended
+	}, testutil.WaitShort, testutil.IntervalFast)
+}
+
+func TestLDAPSyncMovedUsersAndSuspendGuard(t *testing.T) {
+	t.Parallel()
+
+	db, pubsub := dbtestutil.NewDB(t)
+	srv, cfg := setupLDAP(t)
+	cfg.SyncInterval = testutil.IntervalFast
+
+	// Alice was moved to another organizational unit since she last logged
+	// in.
+	const movedDN = "CN=Alice,OU=Engineers,DC=example,DC=com"
+	srv.DeleteEntry(ldapAliceDN)
+	srv.AddEntry(movedDN,
+		"objectClass", "person",
+		"sAMAccountName", "alice",
+		"mail", "alice@example.com",
+	)
+	alice := dbgen.User(t, db, database.User{
+		Username:  "alice",
+		Email:     "alice@example.com",
+		LoginType: database.LoginTypeLDAP,
+	})
+	dbgen.UserLink(t, db, database.UserLink{
+		UserID:    alice.ID,
+		LoginType: database.LoginTypeLDAP,
+		LinkedID:  ldapAliceDN,
+	})
+	// Most other users are gone from the directory, as if the base DN had
+	// been misconfigured.
+	removed := make([]database.User, 0, 12)
+	for i := 0; i < cap(removed); i++ {
+		user := dbgen.User(t, db, database.User{
+			LoginType: database.LoginTypeLDAP,
+		})
+		dbgen.UserLink(t, db, database.UserLink{
+			UserID:    user.ID,
+			LoginType: database.LoginTypeLDAP,
+			LinkedID:  fmt.Sprintf("CN=%s,OU=Users,DC=example,DC=com", user.Username),
+		})
+		removed = append(removed, user)
+	}
+
+	_ = coderdtest.New(t, &coderdtest.Options{
+		Database:   db,
+		Pubsub:     pubsub,
+		LDAPConfig: cfg,
+	})
+
+	ctx := context.Background()
+	require.Eventually(t, func() bool {
+		link, err := db.GetUserLinkByUserIDLoginType(ctx, database.GetUserLinkByUserIDLoginTypeParams{
+			UserID:    alice.ID,
+			LoginType: database.LoginTypeLDAP,
+		})
+		return err == nil && link.LinkedID == movedDN
+	}, testutil.WaitShort, testutil.IntervalFast)
+
+	user, err := db.GetUserByID(ctx, alice.ID)
+	require.NoError(t, err)
+	require.Equal(t, database.UserStatusActive, user.Status)
+	for _, user := range removed {
+		user, err := db.GetUserByID(ctx, user.ID)
+		require.NoError(t, err)
+		require.Equal(t, database.UserStatusActive, user.Status, "users must not be suspended at once")
+	}
+}
@@ -0,0 +1,876 @@
+package coderd
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/justinas/nosurf"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/apikey"
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/cryptorand"
+	"github.com/coder/coder/v2/site"
+)
+
+const (
+	// oauth2ProviderCodeLifetime is how long an authorization code can be
+	// exchanged for a token after the user allowed the app.
+	oauth2ProviderCodeLifetime = 10 * time.Minute
+	// oauth2ProviderCodeChallengeMethod is the only PKCE method accepted. The
+	// "plain" method offers no protection if the authorization request leaks.
+	oauth2ProviderCodeChallengeMethod = "S256"
+)
+
+// @Summary Get OAuth2 applications
+// @ID get-oauth2-applications
+// @Security CoderSessionToken
+// @Produce json
+// @Tags OAuth2
+// @Success 200 {array} codersdk.OAuth2ProviderApp
+// @Router /oauth2-provider/apps [get]
+func (api *API) oAuth2ProviderApps(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	dbApps, err := api.Database.GetOAuth2ProviderApps(ctx)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching OAuth2 apps.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, api.convertOAuth2ProviderApps(dbApps))
+}
+
+// @Summary Get OAuth2 application
+// @ID get-oauth2-application
+// @Security CoderSessionToken
+// @Produce json
+// @Tags OAuth2
+// @Param app path string true "App ID"
+// @Success 200 {object} codersdk.OAuth2ProviderApp
+// @Router /oauth2-provider/apps/{app} [get]
+func (api *API) oAuth2ProviderApp(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	app := httpmw.OAuth2ProviderApp(r)
+	httpapi.Write(ctx, rw, http.StatusOK, api.convertOAuth2ProviderApp(app))
+}
+
+// @Summary Create OAuth2 application
+// @ID create-oauth2-application
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags OAuth2
+// @Param request body codersdk.PostOAuth2ProviderAppRequest true "The OAuth2 application to create."
+// @Success 201 {object} codersdk.OAuth2ProviderApp
+// @Router /oauth2-provider/apps [post]
+func (api *API) postOAuth2ProviderApp(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.OAuth2ProviderApp](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionCreate,
+		})
+	)
+	defer commitAudit()
+
+	var req codersdk.PostOAuth2ProviderAppRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	app, err := api.Database.InsertOAuth2ProviderApp(ctx, database.InsertOAuth2ProviderAppParams{
+		ID:          uuid.New(),
+		CreatedAt:   dbtime.Now(),
+		UpdatedAt:   dbtime.Now(),
+		Name:        req.Name,
+		Icon:        req.Icon,
+		CallbackURL: req.CallbackURL,
+	})
+	if database.IsUniqueViolation(err, database.UniqueOauth2ProviderAppsNameKey) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("An OAuth2 app with name %q already exists.", req.Name),
+			Validations: []codersdk.ValidationError{{
+				Field:  "name",
+				Detail: "This value is already in use and should be unique.",
+			}},
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating OAuth2 app.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = app
+
+	httpapi.Write(ctx, rw, http.StatusCreated, api.convertOAuth2ProviderApp(app))
+}
+
+// @Summary Update OAuth2 application
+// @ID update-oauth2-application
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags OAuth2
+// @Param app path string true "App ID"
+// @Param request body codersdk.PutOAuth2ProviderAppRequest true "Update an OAuth2 application."
+// @Success 200 {object} codersdk.OAuth2ProviderApp
+// @Router /oauth2-provider/apps/{app} [put]
+func (api *API) putOAuth2ProviderApp(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		app               = httpmw.OAuth2ProviderApp(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.OAuth2ProviderApp](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionWrite,
+		})
+	)
+	aReq.Old = app
+	defer commitAudit()
+
+	var req codersdk.PutOAuth2ProviderAppRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	app, err := api.Database.UpdateOAuth2ProviderAppByID(ctx, database.UpdateOAuth2ProviderAppByIDParams{
+		ID:          app.ID,
+		UpdatedAt:   dbtime.Now(),
+		Name:        req.Name,
+		Icon:        req.Icon,
+		CallbackURL: req.CallbackURL,
+	})
+	if database.IsUniqueViolation(err, database.UniqueOauth2ProviderAppsNameKey) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("An OAuth2 app with name %q already exists.", req.Name),
+			Validations: []codersdk.ValidationError{{
+				Field:  "name",
+				Detail: "This value is already in use and should be unique.",
+			}},
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating OAuth2 app.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = app
+
+	httpapi.Write(ctx, rw, http.StatusOK, api.convertOAuth2ProviderApp(app))
+}
+
+// @Summary Delete OAuth2 application
+// @ID delete-oauth2-application
+// @Security CoderSessionToken
+// @Tags OAuth2
+// @Param app path string true "App ID"
+// @Success 204
+// @Router /oauth2-provider/apps/{app} [delete]
+func (api *API) deleteOAuth2ProviderApp(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		app               = httpmw.OAuth2ProviderApp(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.OAuth2ProviderApp](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionDelete,
+		})
+	)
+	aReq.Old = app
+	defer commitAudit()
+
+	// Deleting the app also deletes its secrets, codes, and tokens, as well
+	// as the API keys that were issued to it.
+	err := api.Database.DeleteOAuth2ProviderAppByID(ctx, app.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting OAuth2 app.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusNoContent, nil)
+}
+
+// @Summary Get OAuth2 application secrets
+// @ID get-oauth2-application-secrets
+// @Security CoderSessionToken
+// @Produce json
+// @Tags OAuth2
+// @Param app path string true "App ID"
+// @Success 200 {array} codersdk.OAuth2ProviderAppSecret
+// @Router /oauth2-provider/apps/{app}/secrets [get]
+func (api *API) oAuth2ProviderAppSecrets(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	app := httpmw.OAuth2ProviderApp(r)
+
+	dbSecrets, err := api.Database.GetOAuth2ProviderAppSecretsByAppID(ctx, app.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching OAuth2 app secrets.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	secrets := make([]codersdk.OAuth2ProviderAppSecret, 0, len(dbSecrets))
+	for _, secret := range dbSecrets {
+		secrets = append(secrets, codersdk.OAuth2ProviderAppSecret{
+			ID:                    secret.ID,
+			LastUsedAt:            codersdk.NullTime{NullTime: secret.LastUsedAt},
+			ClientSecretTruncated: secret.DisplaySecret,
+		})
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, secrets)
+}
+
+// @Summary Create OAuth2 application secret
+// @ID create-oauth2-application-secret
+// @Security CoderSessionToken
+// @Produce json
+// @Tags OAuth2
+// @Param app path string true "App ID"
+// @Success 201 {object} codersdk.OAuth2ProviderAppSecretFull
+// @Router /oauth2-provider/apps/{app}/secrets [post]
+func (api *API) postOAuth2ProviderAppSecret(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		app               = httpmw.OAuth2ProviderApp(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.OAuth2ProviderAppSecret](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionCreate,
+		})
+	)
+	defer commitAudit()
+
+	secret, err := cryptorand.String(40)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to generate OAuth2 client secret.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	hashed := sha256.Sum256([]byte(secret))
+
+	dbSecret, err := api.Database.InsertOAuth2ProviderAppSecret(ctx, database.InsertOAuth2ProviderAppSecretParams{
+		ID:           uuid.New(),
+		CreatedAt:    dbtime.Now(),
+		HashedSecret: hashed[:],
+		// Only the last few characters are kept so admins can tell secrets
+		// apart without being able to use them.
+		DisplaySecret: "*****" + secret[len(secret)-6:],
+		AppID:         app.ID,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating OAuth2 app secret.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = dbSecret
+
+	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.OAuth2ProviderAppSecretFull{
+		ID:               dbSecret.ID,
+		ClientSecretFull: secret,
+	})
+}
+
+// @Summary Delete OAuth2 application secret
+// @ID delete-oauth2-application-secret
+// @Security CoderSessionToken
+// @Tags OAuth2
+// @Param app path string true "App ID"
+// @Param secretID path string true "Secret ID"
+// @Success 204
+// @Router /oauth2-provider/apps/{app}/secrets/{secretID} [delete]
+func (api *API) deleteOAuth2ProviderAppSecret(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		secret            = httpmw.OAuth2ProviderAppSecret(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.OAuth2ProviderAppSecret](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionDelete,
+		})
+	)
+	aReq.Old = secret
+	defer commitAudit()
+
+	// Tokens issued with the secret and their API keys are deleted with it.
+	err := api.Database.DeleteOAuth2ProviderAppSecretByID(ctx, secret.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting OAuth2 app secret.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusNoContent, nil)
+}
+
+// oauth2AuthorizeParams are the query parameters of an authorization request,
+// see RFC 6749 section 4.1.1 and RFC 7636 section 4.3.
+type oauth2AuthorizeParams struct {
+	app         database.OAuth2ProviderApp
+	redirectURL *url.URL
+	// redirectURI is the redirect_uri parameter as sent by the app, which is
+	// empty when the app relies on its callback URL. The app must send the
+	// same value when exchanging the code, see RFC 6749 section 4.1.3.
+	redirectURI   string
+	state         string
+	codeChallenge string
+}
+
+// parseOAuth2AuthorizeParams validates an authorization request. Until the
+// redirect URI is known to belong to the app, errors must be shown to the user
+// instead of being sent to the redirect URI.
+func (api *API) parseOAuth2AuthorizeParams(r *http.Request) (oauth2AuthorizeParams, error) {
+	ctx := r.Context()
+	query := r.URL.Query()
+
+	clientID, err := uuid.Parse(query.Get("client_id"))
+	if err != nil {
+		return oauth2AuthorizeParams{}, xerrors.New("The client_id parameter is missing or invalid.")
+	}
+	app, err := api.Database.GetOAuth2ProviderAppByID(ctx, clientID)
+	if httpapi.Is404Error(err) {
+		return oauth2AuthorizeParams{}, xerrors.New("The client_id parameter does not match any application.")
+	}
+	if err != nil {
+		return oauth2AuthorizeParams{}, xerrors.Errorf("Internal error fetching application: %s", err)
+	}
+
+	callbackURL, err := url.Parse(app.CallbackURL)
+	if err != nil {
+		return oauth2AuthorizeParams{}, xerrors.Errorf("The application has an invalid callback URL: %s", err)
+	}
+	redirectURL := callbackURL
+	redirectURI := query.Get("redirect_uri")
+	if redirectURI != "" {
+		redirectURL, err = url.Parse(redirectURI)
+		if err != nil {
+			return oauth2AuthorizeParams{}, xerrors.New("The redirect_uri parameter is invalid.")
+		}
+		// The redirect may add to the path and query of the registered
+		// callback, but it must not go anywhere else.
+		if redirectURL.Scheme != callbackURL.Scheme ||
+			redirectURL.Host != callbackURL.Host ||
+			redirectURL.User != nil ||
+			!oauth2RedirectPathAllowed(callbackURL.Path, redirectURL.Path) {
+			return oauth2AuthorizeParams{}, xerrors.New("The redirect_uri parameter does not match the application's callback URL.")
+		}
+	}
+
+	params := oauth2AuthorizeParams{
+		app:           app,
+		redirectURL:   redirectURL,
+		redirectURI:   redirectURI,
+		state:         query.Get("state"),
+		codeChallenge: query.Get("code_challenge"),
+	}
+	if codersdk.OAuth2ProviderResponseType(query.Get("response_type")) != codersdk.OAuth2ProviderResponseTypeCode {
+		return params, xerrors.New("The response_type parameter must be \"code\".")
+	}
+	if params.state == "" {
+		return params, xerrors.New("The state parameter is required.")
+	}
+	if params.codeChallenge == "" {
+		return params, xerrors.New("The code_challenge parameter is required.")
+	}
+	if query.Get("code_challenge_method") != oauth2ProviderCodeChallengeMethod {
+		return params, xerrors.Errorf("The code_challenge_method parameter must be %q.", oauth2ProviderCodeChallengeMethod)
+	}
+	return params, nil
+}
+
+// oauth2RedirectPathAllowed reports whether a redirect path is the callback path
+// or below it. Dot segments are rejected rather than resolved, since browsers
+// and apps may not resolve them the same way.
+func oauth2RedirectPathAllowed(callbackPath, redirectPath string) bool {
+	if redirectPath == "" {
+		redirectPath = "/"
+	}
+	for _, segment := range strings.Split(redirectPath, "/") {
+		if segment == "." || segment == ".." {
+			return false
+		}
+	}
+	callbackSegments := strings.Split(strings.TrimSuffix(callbackPath, "/"), "/")
+	redirectSegments := strings.Split(redirectPath, "/")
+	if len(redirectSegments) < len(callbackSegments) {
+		return false
+	}
+	for i, segment := range callbackSegments {
+		if redirectSegments[i] != segment {
+			return false
+		}
+	}
+	return true
+}
+
+// getOAuth2ProviderAppAuthorize renders the page where a user allows an app
+// to act on their behalf.
+func (api *API) getOAuth2ProviderAppAuthorize(rw http.ResponseWriter, r *http.Request) {
+	apiKey := httpmw.APIKey(r)
+
+	params, err := api.parseOAuth2AuthorizeParams(r)
+	if err != nil {
+		site.RenderStaticErrorPage(rw, r, site.ErrorPageData{
+			Status:       http.StatusBadRequest,
+			Title:        "Invalid authorization request",
+			Description:  err.Error(),
+			DashboardURL: api.AccessURL.String(),
+		})
+		return
+	}
+
+	// nolint:gocritic // The user may not be able to read their own user.
+	user, err := api.Database.GetUserByID(dbauthz.AsSystemRestricted(r.Context()), apiKey.UserID)
+	if err != nil {
+		site.RenderStaticErrorPage(rw, r, site.ErrorPageData{
+			Status:       http.StatusInternalServerError,
+			Title:        "Internal error fetching user",
+			Description:  err.Error(),
+			DashboardURL: api.AccessURL.String(),
+		})
+		return
+	}
+
+	site.RenderOAuthAllowPage(rw, r, site.RenderOAuthAllowData{
+		AppIcon:     params.app.Icon,
+		AppName:     params.app.Name,
+		CancelURI:   oauth2RedirectURL(params.redirectURL, "error", "access_denied", params.state),
+		RedirectURI: params.redirectURL.String(),
+		Username:    user.Username,
+	})
+}
+
+// postOAuth2ProviderAppAuthorize is called when the user allows the app. It
+// issues an authorization code and sends the user back to the app.
+func (api *API) postOAuth2ProviderAppAuthorize(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	apiKey := httpmw.APIKey(r)
+
+	// The CSRF middleware does not enforce tokens yet, but allowing an app
+	// must never happen from another site. Requests authenticated by the
+	// header instead of the cookie cannot be forged by a browser.
+	if _, err := r.Cookie(codersdk.SessionTokenCookie); err == nil &&
+		!nosurf.VerifyToken(nosurf.Token(r), r.PostFormValue(nosurf.FormFieldName)) {
+		site.RenderStaticErrorPage(rw, r, site.ErrorPageData{
+			Status:       http.StatusBadRequest,
+			Title:        "Invalid authorization request",
+			Description:  "The request is missing a valid CSRF token. Please go back to the application and try again.",
+			DashboardURL: api.AccessURL.String(),
+		})
+		return
+	}
+
+	params, err := api.parseOAuth2AuthorizeParams(r)
+	if err != nil {
+		site.RenderStaticErrorPage(rw, r, site.ErrorPageData{
+			Status:       http.StatusBadRequest,
+			Title:        "Invalid authorization request",
+			Description:  err.Error(),
+			DashboardURL: api.AccessURL.String(),
+		})
+		return
+	}
+
+	code, err := newOAuth2ProviderSecret()
+	if err != nil {
+		site.RenderStaticErrorPage(rw, r, site.ErrorPageData{
+			Status:       http.StatusInternalServerError,
+			Title:        "Failed to generate authorization code",
+			Description:  err.Error(),
+			DashboardURL: api.AccessURL.String(),
+		})
+		return
+	}
+	_, err = api.Database.InsertOAuth2ProviderAppCode(ctx, database.InsertOAuth2ProviderAppCodeParams{
+		ID:            uuid.New(),
+		CreatedAt:     dbtime.Now(),
+		ExpiresAt:     dbtime.Now().Add(oauth2ProviderCodeLifetime),
+		SecretPrefix:  code.prefix,
+		HashedSecret:  code.hashed,
+		UserID:        apiKey.UserID,
+		AppID:         params.app.ID,
+		RedirectURI:   params.redirectURI,
+		CodeChallenge: params.codeChallenge,
+	})
+	if err != nil {
+		site.RenderStaticErrorPage(rw, r, site.ErrorPageData{
+			Status:       http.StatusInternalServerError,
+			Title:        "Failed to create authorization code",
+			Description:  err.Error(),
+			DashboardURL: api.AccessURL.String(),
+		})
+		return
+	}
+
+	http.Redirect(rw, r, oauth2RedirectURL(params.redirectURL, "code", code.formatted, params.state), http.StatusFound)
+}
+
+// postOAuth2ProviderAppToken exchanges an authorization code or a refresh token
+// for an API key, see RFC 6749 sections 4.1.3 and 6.
+func (api *API) postOAuth2ProviderAppToken(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	// Tokens must never be cached, see RFC 6749 section 5.1.
+	rw.Header().Set("Cache-Control", "no-store")
+	rw.Header().Set("Pragma", "no-cache")
+
+	if err := r.ParseForm(); err != nil {
+		writeOAuth2TokenError(rw, r, http.StatusBadRequest, "invalid_request", "The request body could not be parsed.")
+		return
+	}
+
+	// Clients may authenticate with either HTTP basic auth or the request
+	// body, see RFC 6749 section 2.3.1.
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		clientID = r.PostForm.Get("client_id")
+		clientSecret = r.PostForm.Get("client_secret")
+	}
+	// nolint:gocritic // There is no user until the client is authenticated.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	app, secret, ok := api.authenticateOAuth2ProviderClient(ctx, rw, r, clientID, clientSecret)
+	if !ok {
+		return
+	}
+
+	var token codersdk.OAuth2TokenResponse
+	err := api.Database.InTx(func(tx database.Store) error {
+		var (
+			userID uuid.UUID
+			err    error
+		)
+		switch codersdk.OAuth2ProviderGrantType(r.PostForm.Get("grant_type")) {
+		case codersdk.OAuth2ProviderGrantTypeAuthorizationCode:
+			userID, err = oauth2ProviderExchangeCode(ctx, tx, r, app)
+		case codersdk.OAuth2ProviderGrantTypeRefreshToken:
+			userID, err = oauth2ProviderExchangeRefreshToken(ctx, tx, r, app)
+		default:
+			return errOAuth2UnsupportedGrantType
+		}
+		if err != nil {
+			return err
+		}
+
+		token, err = api.createOAuth2ProviderAppToken(ctx, tx, r, app, secret, userID)
+		return err
+	}, nil)
+	var invalidGrant oauth2InvalidGrantError
+	switch {
+	case xerrors.Is(err, errOAuth2UnsupportedGrantType):
+		writeOAuth2TokenError(rw, r, http.StatusBadRequest, "unsupported_grant_type", `The grant_type must be "authorization_code" or "refresh_token".`)
+		return
+	case xerrors.As(err, &invalidGrant):
+		writeOAuth2TokenError(rw, r, http.StatusBadRequest, "invalid_grant", invalidGrant.Error())
+		return
+	case err != nil:
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error exchanging OAuth2 grant.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	_, err = api.Database.UpdateOAuth2ProviderAppSecretByID(ctx, database.UpdateOAuth2ProviderAppSecretByIDParams{
+		ID:         secret.ID,
+		LastUsedAt: sql.NullTime{Time: dbtime.Now(), Valid: true},
+	})
+	if err != nil {
+		api.Logger.Warn(ctx, "update oauth2 app secret last used")
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, token)
+}
+
+var errOAuth2UnsupportedGrantType = xerrors.New("unsupported grant type")
+
+// oauth2InvalidGrantError is returned when the code or refresh token sent by a
+// client cannot be exchanged, as opposed to an internal error.
+type oauth2InvalidGrantError string
+
+func (e oauth2InvalidGrantError) Error() string {
+	return string(e)
+}
+
+// authenticateOAuth2ProviderClient checks the client credentials sent to the
+// token endpoint and returns the app and the secret that matched.
+func (api *API) authenticateOAuth2ProviderClient(ctx context.Context, rw http.ResponseWriter, r *http.Request, clientID, clientSecret string) (database.OAuth2ProviderApp, database.OAuth2ProviderAppSecret, bool) {
+	appID, err := uuid.Parse(clientID)
+	if err != nil || clientSecret == "" {
+		writeOAuth2TokenError(rw, r, http.StatusUnauthorized, "invalid_client", "The client credentials are missing or invalid.")
+		return database.OAuth2ProviderApp{}, database.OAuth2ProviderAppSecret{}, false
+	}
+	app, err := api.Database.GetOAuth2ProviderAppByID(ctx, appID)
+	if httpapi.Is404Error(err) {
+		writeOAuth2TokenError(rw, r, http.StatusUnauthorized, "invalid_client", "The client credentials are missing or invalid.")
+		return database.OAuth2ProviderApp{}, database.OAuth2ProviderAppSecret{}, false
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching OAuth2 app.",
+			Detail:  err.Error(),
+		})
+		return database.OAuth2ProviderApp{}, database.OAuth2ProviderAppSecret{}, false
+	}
+	secrets, err := api.Database.GetOAuth2ProviderAppSecretsByAppID(ctx, app.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching OAuth2 app secrets.",
+			Detail:  err.Error(),
+		})
+		return database.OAuth2ProviderApp{}, database.OAuth2ProviderAppSecret{}, false
+	}
+	hashed := sha256.Sum256([]byte(clientSecret))
+	for _, secret := range secrets {
+		if subtle.ConstantTimeCompare(hashed[:], secret.HashedSecret) == 1 {
+			return app, secret, true
+		}
+	}
+	writeOAuth2TokenError(rw, r, http.StatusUnauthorized, "invalid_client", "The client credentials are missing or invalid.")
+	return database.OAuth2ProviderApp{}, database.OAuth2ProviderAppSecret{}, false
+}
+
+// oauth2ProviderExchangeCode validates an authorization code and deletes it so
+// it cannot be used again. It returns the user the code was issued for.
+func oauth2ProviderExchangeCode(ctx context.Context, db database.Store, r *http.Request, app database.OAuth2ProviderApp) (uuid.UUID, error) {
+	prefix, secret, ok := parseOAuth2ProviderSecret(r.PostForm.Get("code"))
+	if !ok {
+		return uuid.Nil, oauth2InvalidGrantError("The authorization code is invalid.")
+	}
+	code, err := db.GetOAuth2ProviderAppCodeByPrefix(ctx, prefix)
+	if httpapi.Is404Error(err) {
+		return uuid.Nil, oauth2InvalidGrantError("The authorization code is invalid.")
+	}
+	if err != nil {
+		return uuid.Nil, xerrors.Errorf("get oauth2 app code: %w", err)
+	}
+	hashed := sha256.Sum256([]byte(secret))
+	if subtle.ConstantTimeCompare(hashed[:], code.HashedSecret) != 1 || code.AppID != app.ID {
+		return uuid.Nil, oauth2InvalidGrantError("The authorization code is invalid.")
+	}
+	if code.ExpiresAt.Before(dbtime.Now()) {
+		return uuid.Nil, oauth2InvalidGrantError("The authorization code has expired.")
+	}
+	// The redirect_uri is required if it was sent with the authorization
+	// request, and must be identical, see RFC 6749 section 4.1.3.
+	if code.RedirectURI != "" && r.PostForm.Get("redirect_uri") != code.RedirectURI {
+		return uuid.Nil, oauth2InvalidGrantError("The redirect_uri does not match the authorization request.")
+	}
+	challenge := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if subtle.ConstantTimeCompare([]byte(base64.RawURLEncoding.EncodeToString(challenge[:])), []byte(code.CodeChallenge)) != 1 {
+		return uuid.Nil, oauth2InvalidGrantError("The code_verifier does not match the code_challenge.")
+	}
+	// Codes can only be used once. If another request exchanged the code
+	// concurrently, it has already been deleted.
+	_, err = db.DeleteOAuth2ProviderAppCodeByID(ctx, code.ID)
+	if httpapi.Is404Error(err) {
+		return uuid.Nil, oauth2InvalidGrantError("The authorization code is invalid.")
+	}
+	if err != nil {
+		return uuid.Nil, xerrors.Errorf("delete oauth2 app code: %w", err)
+	}
+	return code.UserID, nil
+}
+
+// oauth2ProviderExchangeRefreshToken validates a refresh token and revokes the
+// API key it was issued with. It returns the user the token was issued for.
+func oauth2ProviderExchangeRefreshToken(ctx context.Context, db database.Store, r *http.Request, app database.OAuth2ProviderApp) (uuid.UUID, error) {
+	prefix, secret, ok := parseOAuth2ProviderSecret(r.PostForm.Get("refresh_token"))
+	if !ok {
+		return uuid.Nil, oauth2InvalidGrantError("The refresh token is invalid.")
+	}
+	token, err := db.GetOAuth2ProviderAppTokenByPrefix(ctx, prefix)
+	if httpapi.Is404Error(err) {
+		return uuid.Nil, oauth2InvalidGrantError("The refresh token is invalid.")
+	}
+	if err != nil {
+		return uuid.Nil, xerrors.Errorf("get oauth2 app token: %w", err)
+	}
+	hashed := sha256.Sum256([]byte(secret))
+	if subtle.ConstantTimeCompare(hashed[:], token.RefreshHash) != 1 {
+		return uuid.Nil, oauth2InvalidGrantError("The refresh token is invalid.")
+	}
+	// The token may have been issued with another of the app's secrets.
+	tokenSecret, err := db.GetOAuth2ProviderAppSecretByID(ctx, token.AppSecretID)
+	if err != nil {
+		return uuid.Nil, xerrors.Errorf("get oauth2 app secret: %w", err)
+	}
+	if tokenSecret.AppID != app.ID {
+		return uuid.Nil, oauth2InvalidGrantError("The refresh token is invalid.")
+	}
+	if token.ExpiresAt.Before(dbtime.Now()) {
+		return uuid.Nil, oauth2InvalidGrantError("The refresh token has expired.")
+	}
+	// Refresh tokens can only be used once. Deleting the token revokes the API
+	// key it was issued with, and if another request exchanged the token
+	// concurrently, it has already been deleted.
+	_, err = db.DeleteOAuth2ProviderAppTokenByPrefix(ctx, prefix)
+	if httpapi.Is404Error(err) {
+		return uuid.Nil, oauth2InvalidGrantError("The refresh token is invalid.")
+	}
+	if err != nil {
+		return uuid.Nil, xerrors.Errorf("delete oauth2 app token: %w", err)
+	}
+	return token.UserID, nil
+}
+
+// createOAuth2ProviderAppToken issues an API key for the user bound to the app,
+// along with a refresh token for it.
+func (api *API) createOAuth2ProviderAppToken(ctx context.Context, db database.Store, r *http.Request, app database.OAuth2ProviderApp, secret database.OAuth2ProviderAppSecret, userID uuid.UUID) (codersdk.OAuth2TokenResponse, error) {
+	key, sessionToken, err := apikey.Generate(apikey.CreateParams{
+		UserID:           userID,
+		LoginType:        database.LoginTypeOAuth2ProviderApp,
+		DeploymentValues: api.DeploymentValues,
+		RemoteAddr:       r.RemoteAddr,
+		// The token name shows up in the user's token list, where they can
+		// revoke the app's access.
+		TokenName: app.Name,
+	})
+	if err != nil {
+		return codersdk.OAuth2TokenResponse{}, xerrors.Errorf("generate api key: %w", err)
+	}
+	newKey, err := db.InsertAPIKey(ctx, key)
+	if err != nil {
+		return codersdk.OAuth2TokenResponse{}, xerrors.Errorf("insert api key: %w", err)
+	}
+
+	refresh, err := newOAuth2ProviderSecret()
+	if err != nil {
+		return codersdk.OAuth2TokenResponse{}, xerrors.Errorf("generate refresh token: %w", err)
+	}
+	_, err = db.InsertOAuth2ProviderAppToken(ctx, database.InsertOAuth2ProviderAppTokenParams{
+		ID:          uuid.New(),
+		CreatedAt:   dbtime.Now(),
+		ExpiresAt:   dbtime.Now().Add(api.DeploymentValues.MaxTokenLifetime.Value()),
+		HashPrefix:  refresh.prefix,
+		RefreshHash: refresh.hashed,
+		AppSecretID: secret.ID,
+		APIKeyID:    newKey.ID,
+		UserID:      userID,
+	})
+	if err != nil {
+		return codersdk.OAuth2TokenResponse{}, xerrors.Errorf("insert oauth2 app token: %w", err)
+	}
+
+	return codersdk.OAuth2TokenResponse{
+		AccessToken:  sessionToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    newKey.LifetimeSeconds,
+		RefreshToken: refresh.formatted,
+	}, nil
+}
+
+func writeOAuth2TokenError(rw http.ResponseWriter, r *http.Request, status int, code, description string) {
+	httpapi.Write(r.Context(), rw, status, codersdk.OAuth2TokenError{
+		Error:            code,
+		ErrorDescription: description,
+	})
+}
+
+// oauth2ProviderSecret is an authorization code or refresh token. Only the
+// prefix is stored as-is so the secret can be looked up without storing it.
+type oauth2ProviderSecret struct {
+	formatted string
+	prefix    string
+	hashed    []byte
+}
+
+func newOAuth2ProviderSecret() (oauth2ProviderSecret, error) {
+	prefix, err := cryptorand.String(8)
+	if err != nil {
+		return oauth2ProviderSecret{}, err
+	}
+	secret, err := cryptorand.String(40)
+	if err != nil {
+		return oauth2ProviderSecret{}, err
+	}
+	hashed := sha256.Sum256([]byte(secret))
+	return oauth2ProviderSecret{
+		formatted: fmt.Sprintf("coder_%s_%s", prefix, secret),
+		prefix:    prefix,
+		hashed:    hashed[:],
+	}, nil
+}
+
+func parseOAuth2ProviderSecret(formatted string) (prefix string, secret string, ok bool) {
+	parts := strings.Split(formatted, "_")
+	if len(parts) != 3 || parts[0] != "coder" || parts[1] == "" || parts[2] == "" {
+		return "", "", false
+	}
+	return parts[1], parts[2], true
+}
+
+// oauth2RedirectURL adds the result of an authorization request to the app's
+// redirect URL, keeping any query parameters it already has.
+func oauth2RedirectURL(redirectURL *url.URL, key, value, state string) string {
+	u := *redirectURL
+	query := u.Query()
+	query.Set(key, value)
+	query.Set("state", state)
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+func (api *API) convertOAuth2ProviderApp(app database.OAuth2ProviderApp) codersdk.OAuth2ProviderApp {
+	return codersdk.OAuth2ProviderApp{
+		ID:          app.ID,
+		Name:        app.Name,
+		CallbackURL: app.CallbackURL,
+		Icon:        app.Icon,
+		Endpoints: codersdk.OAuth2AppEndpoints{
+			Authorization: api.AccessURL.ResolveReference(&url.URL{Path: "/oauth2/authorize"}).String(),
+			Token:         api.AccessURL.ResolveReference(&url.URL{Path: "/oauth2/tokens"}).String(),
+		},
+	}
+}
+
+func (api *API) convertOAuth2ProviderApps(dbApps []database.OAuth2ProviderApp) []codersdk.OAuth2ProviderApp {
+	apps := make([]codersdk.OAuth2ProviderApp, 0, len(dbApps))
+	for _, app := range dbApps {
+		apps = append(apps, api.convertOAuth2ProviderApp(app))
+	}
+	return apps
+}
@@ -0,0 +1,399 @@
+package coderd_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"html"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestOAuth2ProviderApps(t *testing.T) {
+	t.Parallel()
+
+	t.Run("CRUD", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		app, err := client.PostOAuth2ProviderApp(ctx, codersdk.PostOAuth2ProviderAppRequest{
+			Name:        "my-app",
+			CallbackURL: "http://localhost:3000",
+		})
+		require.NoError(t, err)
+		require.Equal(t, client.URL.String()+"/oauth2/authorize", app.Endpoints.Authorization)
+		require.Equal(t, client.URL.String()+"/oauth2/tokens", app.Endpoints.Token)
+
+		// Names must be unique.
+		_, err = client.PostOAuth2ProviderApp(ctx, codersdk.PostOAuth2ProviderAppRequest{
+			Name:        "my-app",
+			CallbackURL: "http://localhost:3000",
+		})
+		var sdkErr *codersdk.Error
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, http.StatusConflict, sdkErr.StatusCode())
+
+		// Members can see apps, since they are asked to authorize them.
+		apps, err := member.OAuth2ProviderApps(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []codersdk.OAuth2ProviderApp{app}, apps)
+
+		// But only owners can change them.
+		_, err = member.PostOAuth2ProviderApp(ctx, codersdk.PostOAuth2ProviderAppRequest{
+			Name:        "other-app",
+			CallbackURL: "http://localhost:3000",
+		})
+		require.Error(t, err)
+		_, err = member.PostOAuth2ProviderAppSecret(ctx, app.ID)
+		require.Error(t, err)
+
+		app, err = client.PutOAuth2ProviderApp(ctx, app.ID, codersdk.PutOAuth2ProviderAppRequest{
+			Name:        "new-name",
+			CallbackURL: "http://localhost:3001",
+			Icon:        "/icon/github.svg",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "new-name", app.Name)
+		got, err := client.OAuth2ProviderApp(ctx, app.ID)
+		require.NoError(t, err)
+		require.Equal(t, app, got)
+
+		secret, err := client.PostOAuth2ProviderAppSecret(ctx, app.ID)
+		require.NoError(t, err)
+		secrets, err := client.OAuth2ProviderAppSecrets(ctx, app.ID)
+		require.NoError(t, err)
+		require.Len(t, secrets, 1)
+		require.Equal(t, secret.ID, secrets[0].ID)
+		require.NotContains(t, secrets[0].ClientSecretTruncated, secret.ClientSecretFull)
+		require.False(t, secrets[0].LastUsedAt.Valid)
+
+		err = client.DeleteOAuth2ProviderAppSecret(ctx, app.ID, secret.ID)
+		require.NoError(t, err)
+		// Secrets cannot be reached through another app.
+		err = client.DeleteOAuth2ProviderAppSecret(ctx, uuid.New(), secret.ID)
+		require.Error(t, err)
+
+		err = client.DeleteOAuth2ProviderApp(ctx, app.ID)
+		require.NoError(t, err)
+		apps, err = client.OAuth2ProviderApps(ctx)
+		require.NoError(t, err)
+		require.Empty(t, apps)
+	})
+}
+
+func TestOAuth2ProviderFlow(t *testing.T) {
+	t.Parallel()
+
+	setup := func(t *testing.T) (*codersdk.Client, *codersdk.Client, *oauth2.Config) {
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		app, err := client.PostOAuth2ProviderApp(ctx, codersdk.PostOAuth2ProviderAppRequest{
+			Name:        "my-app",
+			CallbackURL: "http://localhost:3000/callback",
+		})
+		require.NoError(t, err)
+		secret, err := client.PostOAuth2ProviderAppSecret(ctx, app.ID)
+		require.NoError(t, err)
+
+		return client, member, &oauth2.Config{
+			ClientID:     app.ID.String(),
+			ClientSecret: secret.ClientSecretFull,
+			Endpoint: oauth2.Endpoint{
+				AuthURL:  app.Endpoints.Authorization,
+				TokenURL: app.Endpoints.Token,
+			},
+			RedirectURL: app.CallbackURL,
+		}
+	}
+
+	// authorize allows the app as the user and returns the code from the
+	// redirect back to the app.
+	authorize := func(ctx context.Context, t *testing.T, user *codersdk.Client, authURL string) (*http.Response, url.Values) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, authURL, nil)
+		require.NoError(t, err)
+		req.Header.Set(codersdk.SessionTokenHeader, user.SessionToken())
+		httpClient := &http.Client{
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		}
+		res, err := httpClient.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		if res.StatusCode != http.StatusFound {
+			return res, nil
+		}
+		location, err := url.Parse(res.Header.Get("Location"))
+		require.NoError(t, err)
+		return res, location.Query()
+	}
+
+	challenge := func(verifier string) []oauth2.AuthCodeOption {
+		hashed := sha256.Sum256([]byte(verifier))
+		return []oauth2.AuthCodeOption{
+			oauth2.SetAuthURLParam("code_challenge", base64.RawURLEncoding.EncodeToString(hashed[:])),
+			oauth2.SetAuthURLParam("code_challenge_method", "S256"),
+		}
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		_, member, config := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		verifier := "a-verifier-that-is-long-enough-to-satisfy-rfc-7636"
+		res, query := authorize(ctx, t, member, config.AuthCodeURL("some-state", challenge(verifier)...))
+		require.Equal(t, http.StatusFound, res.StatusCode)
+		require.Equal(t, "some-state", query.Get("state"))
+
+		// The code cannot be exchanged without the verifier.
+		_, err := config.Exchange(ctx, query.Get("code"), oauth2.SetAuthURLParam("code_verifier", "wrong"))
+		require.Error(t, err)
+
+		token, err := config.Exchange(ctx, query.Get("code"), oauth2.SetAuthURLParam("code_verifier", verifier))
+		require.NoError(t, err)
+		require.NotEmpty(t, token.RefreshToken)
+
+		// Codes can only be used once.
+		_, err = config.Exchange(ctx, query.Get("code"), oauth2.SetAuthURLParam("code_verifier", verifier))
+		require.Error(t, err)
+
+		// The access token acts as the user.
+		appClient := codersdk.New(member.URL)
+		appClient.SetSessionToken(token.AccessToken)
+		me, err := member.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		appMe, err := appClient.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Equal(t, me.ID, appMe.ID)
+
+		// Refreshing revokes the previous access token.
+		refreshed, err := config.TokenSource(ctx, &oauth2.Token{RefreshToken: token.RefreshToken}).Token()
+		require.NoError(t, err)
+		require.NotEqual(t, token.AccessToken, refreshed.AccessToken)
+		_, err = appClient.User(ctx, codersdk.Me)
+		require.Error(t, err)
+		appClient.SetSessionToken(refreshed.AccessToken)
+		_, err = appClient.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+
+		// The user can revoke the app from their token list.
+		tokens, err := member.Tokens(ctx, codersdk.Me, codersdk.TokensFilter{})
+		require.NoError(t, err)
+		require.Len(t, tokens, 1)
+		require.Equal(t, codersdk.LoginTypeOAuth2ProviderApp, tokens[0].LoginType)
+		require.Equal(t, "my-app", tokens[0].TokenName)
+		err = member.DeleteAPIKey(ctx, codersdk.Me, tokens[0].ID)
+		require.NoError(t, err)
+		_, err = appClient.User(ctx, codersdk.Me)
+		require.Error(t, err)
+		_, err = config.TokenSource(ctx, &oauth2.Token{RefreshToken: refreshed.RefreshToken}).Token()
+		require.Error(t, err)
+	})
+
+	t.Run("InvalidRequest", func(t *testing.T) {
+		t.Parallel()
+		_, member, config := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		for name, authURL := range map[string]string{
+			"NoChallenge":    config.AuthCodeURL("some-state"),
+			"NoState":        config.AuthCodeURL("", challenge("verifier")...),
+			"PlainChallenge": config.AuthCodeURL("some-state", oauth2.SetAuthURLParam("code_challenge", "verifier"), oauth2.SetAuthURLParam("code_challenge_method", "plain")),
+			"OtherRedirect":  config.AuthCodeURL("some-state", append(challenge("verifier"), oauth2.SetAuthURLParam("redirect_uri", "http://example.com/callback"))...),
+			"DotRedirect":    config.AuthCodeURL("some-state", append(challenge("verifier"), oauth2.SetAuthURLParam("redirect_uri", "http://localhost:3000/callback/../other"))...),
+			"PrefixRedirect": config.AuthCodeURL("some-state", append(challenge("verifier"), oauth2.SetAuthURLParam("redirect_uri", "http://localhost:3000/callbackother"))...),
+		} {
+			res, _ := authorize(ctx, t, member, authURL)
+			require.Equal(t, http.StatusBadRequest, res.StatusCode, name)
+		}
+	})
+
+	t.Run("RedirectURI", func(t *testing.T) {
+		t.Parallel()
+		_, member, config := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		verifier := "a-verifier-that-is-long-enough-to-satisfy-rfc-7636"
+		redirectURI := "http://localhost:3000/callback/sub"
+		res, query := authorize(ctx, t, member, config.AuthCodeURL("some-state", append(challenge(verifier), oauth2.SetAuthURLParam("redirect_uri", redirectURI))...))
+		require.Equal(t, http.StatusFound, res.StatusCode)
+		location, err := url.Parse(res.Header.Get("Location"))
+		require.NoError(t, err)
+		require.Equal(t, "/callback/sub", location.Path)
+
+		// The code can only be exchanged with the same redirect_uri.
+		for _, other := range []string{"", config.RedirectURL} {
+			otherConfig := *config
+			otherConfig.RedirectURL = other
+			_, err = otherConfig.Exchange(ctx, query.Get("code"), oauth2.SetAuthURLParam("code_verifier", verifier))
+			require.Error(t, err)
+		}
+		config.RedirectURL = redirectURI
+		_, err = config.Exchange(ctx, query.Get("code"), oauth2.SetAuthURLParam("code_verifier", verifier))
+		require.NoError(t, err)
+	})
+
+	t.Run("CSRF", func(t *testing.T) {
+		t.Parallel()
+		_, member, config := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		jar, err := cookiejar.New(nil)
+		require.NoError(t, err)
+		jar.SetCookies(member.URL, []*http.Cookie{{
+			Name:  codersdk.SessionTokenCookie,
+			Value: member.SessionToken(),
+		}})
+		httpClient := &http.Client{
+			Jar: jar,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		}
+		authURL := config.AuthCodeURL("some-state", challenge("a-verifier-that-is-long-enough-to-satisfy-rfc-7636")...)
+		post := func(csrfToken string) *http.Response {
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, authURL, strings.NewReader(url.Values{"csrf_token": {csrfToken}}.Encode()))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			res, err := httpClient.Do(req)
+			require.NoError(t, err)
+			_ = res.Body.Close()
+			return res
+		}
+
+		// A form posted by another site has no CSRF token.
+		res := post("")
+		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+
+		// The consent page cannot be framed and includes the token.
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, authURL, nil)
+		require.NoError(t, err)
+		res, err = httpClient.Do(req)
+		require.NoError(t, err)
+		body, err := io.ReadAll(res.Body)
+		_ = res.Body.Close()
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.Equal(t, "DENY", res.Header.Get("X-Frame-Options"))
+		require.Equal(t, "frame-ancestors 'none'", res.Header.Get("Content-Security-Policy"))
+		match := regexp.MustCompile(`name="csrf_token" value="([^"]+)"`).FindSubmatch(body)
+		require.Len(t, match, 2)
+
+		res = post(html.UnescapeString(string(match[1])))
+		require.Equal(t, http.StatusFound, res.StatusCode)
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		t.Parallel()
+		_, member, config := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		// exchangeConcurrently runs exchange from several goroutines at once
+		// and returns the tokens of the exchanges that succeeded.
+		exchangeConcurrently := func(exchange func() (*oauth2.Token, error)) []*oauth2.Token {
+			var (
+				wg     sync.WaitGroup
+				mu     sync.Mutex
+				tokens []*oauth2.Token
+			)
+			for i := 0; i < 5; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					token, err := exchange()
+					if err != nil {
+						// Losing the race is an invalid grant, not an
+						// internal error.
+						var retrieveErr *oauth2.RetrieveError
+						if assert.ErrorAs(t, err, &retrieveErr) {
+							assert.Equal(t, http.StatusBadRequest, retrieveErr.Response.StatusCode)
+							assert.Equal(t, "invalid_grant", retrieveErr.ErrorCode)
+						}
+						return
+					}
+					mu.Lock()
+					tokens = append(tokens, token)
+					mu.Unlock()
+				}()
+			}
+			wg.Wait()
+			return tokens
+		}
+
+		verifier := "a-verifier-that-is-long-enough-to-satisfy-rfc-7636"
+		_, query := authorize(ctx, t, member, config.AuthCodeURL("some-state", challenge(verifier)...))
+		tokens := exchangeConcurrently(func() (*oauth2.Token, error) {
+			return config.Exchange(ctx, query.Get("code"), oauth2.SetAuthURLParam("code_verifier", verifier))
+		})
+		require.Len(t, tokens, 1, "a code can only be exchanged once")
+
+		refreshToken := tokens[0].RefreshToken
+		tokens = exchangeConcurrently(func() (*oauth2.Token, error) {
+			return config.TokenSource(ctx, &oauth2.Token{RefreshToken: refreshToken}).Token()
+		})
+		require.Len(t, tokens, 1, "a refresh token can only be exchanged once")
+	})
+
+	t.Run("InvalidClient", func(t *testing.T) {
+		t.Parallel()
+		_, member, config := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		verifier := "a-verifier-that-is-long-enough-to-satisfy-rfc-7636"
+		_, query := authorize(ctx, t, member, config.AuthCodeURL("some-state", challenge(verifier)...))
+
+		wrongSecret := *config
+		wrongSecret.ClientSecret = "wrong"
+		_, err := wrongSecret.Exchange(ctx, query.Get("code"), oauth2.SetAuthURLParam("code_verifier", verifier))
+		var retrieveErr *oauth2.RetrieveError
+		require.ErrorAs(t, err, &retrieveErr)
+		require.Equal(t, http.StatusUnauthorized, retrieveErr.Response.StatusCode)
+	})
+
+	t.Run("DeleteApp", func(t *testing.T) {
+		t.Parallel()
+		client, member, config := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		verifier := "a-verifier-that-is-long-enough-to-satisfy-rfc-7636"
+		_, query := authorize(ctx, t, member, config.AuthCodeURL("some-state", challenge(verifier)...))
+		token, err := config.Exchange(ctx, query.Get("code"), oauth2.SetAuthURLParam("code_verifier", verifier))
+		require.NoError(t, err)
+
+		err = client.DeleteOAuth2ProviderApp(ctx, uuid.MustParse(config.ClientID))
+		require.NoError(t, err)
+
+		// Deleting the app revokes everything it was issued.
+		appClient := codersdk.New(member.URL)
+		appClient.SetSessionToken(token.AccessToken)
+		_, err = appClient.User(ctx, codersdk.Me)
+		require.Error(t, err)
+		tokens, err := member.Tokens(ctx, codersdk.Me, codersdk.TokensFilter{})
+		require.NoError(t, err)
+		require.Empty(t, tokens)
+	})
+}
@@ -0,0 +1,147 @@
+package parameter
+
+import (
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/tryfunc"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/function/stdlib"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// functions is the subset of Terraform's built-in functions that may be used
+// in parameter expressions. They are pure and cheap to evaluate.
+var functions = map[string]function.Function{
+	"abs":          stdlib.AbsoluteFunc,
+	"can":          tryfunc.CanFunc,
+	"concat":       stdlib.ConcatFunc,
+	"contains":     stdlib.ContainsFunc,
+	"distinct":     stdlib.DistinctFunc,
+	"element":      stdlib.ElementFunc,
+	"index":        stdlib.IndexFunc,
+	"join":         stdlib.JoinFunc,
+	"jsondecode":   stdlib.JSONDecodeFunc,
+	"keys":         stdlib.KeysFunc,
+	"length":       stdlib.LengthFunc,
+	"lookup":       stdlib.LookupFunc,
+	"lower":        stdlib.LowerFunc,
+	"max":          stdlib.MaxFunc,
+	"min":          stdlib.MinFunc,
+	"regex":        stdlib.RegexFunc,
+	"regexall":     stdlib.RegexAllFunc,
+	"regexreplace": stdlib.RegexReplaceFunc,
+	"replace":      stdlib.ReplaceFunc,
+	"split":        stdlib.SplitFunc,
+	"strlen":       stdlib.StrlenFunc,
+	"substr":       stdlib.SubstrFunc,
+	"tobool":       stdlib.MakeToFunc(cty.Bool),
+	"tonumber":     stdlib.MakeToFunc(cty.Number),
+	"tostring":     stdlib.MakeToFunc(cty.String),
+	"trimprefix":   stdlib.TrimPrefixFunc,
+	"trimspace":    stdlib.TrimSpaceFunc,
+	"trimsuffix":   stdlib.TrimSuffixFunc,
+	"try":          tryfunc.TryFunc,
+	"upper":        stdlib.UpperFunc,
+}
+
+// Evaluator evaluates the visibility conditions and dynamic validation rules
+// of template version parameters. The Terraform provisioner extracts these
+// from coder_parameter data sources, rewriting references to other
+// parameters as parameters["name"]. Like coder_parameter values in
+// Terraform, every parameter value is a string.
+type Evaluator struct {
+	parameters cty.Value
+}
+
+// NewEvaluator returns an evaluator for the given parameters. values holds
+// the value of each parameter by name; parameters without a value use their
+// default value.
+func NewEvaluator(params []codersdk.TemplateVersionParameter, values map[string]string) *Evaluator {
+	attrs := make(map[string]cty.Value, len(params))
+	for _, param := range params {
+		value, ok := values[param.Name]
+		if !ok {
+			value = param.DefaultValue
+		}
+		attrs[param.Name] = cty.StringVal(value)
+	}
+	return &Evaluator{
+		parameters: cty.ObjectVal(attrs),
+	}
+}
+
+// Visible returns whether the parameter is shown given the values of the
+// other parameters. The condition may evaluate to a bool, or to a number as
+// with the Terraform count meta-argument, where zero hides the parameter.
+func (e *Evaluator) Visible(param codersdk.TemplateVersionParameter) (bool, error) {
+	if param.VisibleIf == "" {
+		return true, nil
+	}
+	value, err := e.evaluate(param.VisibleIf, nil)
+	if err != nil {
+		return false, xerrors.Errorf("evaluate visibility of parameter %q: %w", param.Name, err)
+	}
+	if value.Type() == cty.Number {
+		return value.AsBigFloat().Sign() > 0, nil
+	}
+	visible, err := convert.Convert(value, cty.Bool)
+	if err != nil {
+		return false, xerrors.Errorf("visibility of parameter %q must be a bool or number: %w", param.Name, err)
+	}
+	return visible.True(), nil
+}
+
+// Validate checks value against the dynamic validation rules of the
+// parameter. Static rules such as regex or min and max are checked by
+// codersdk.ValidateWorkspaceBuildParameter.
+func (e *Evaluator) Validate(param codersdk.TemplateVersionParameter, value string) error {
+	self := cty.ObjectVal(map[string]cty.Value{
+		"value": cty.StringVal(value),
+	})
+	for _, rule := range param.ValidationRules {
+		result, err := e.evaluate(rule.Condition, &self)
+		if err != nil {
+			return xerrors.Errorf("evaluate validation rule: %w", err)
+		}
+		ok, err := convert.Convert(result, cty.Bool)
+		if err != nil {
+			return xerrors.Errorf("validation rule %q must be a bool: %w", rule.Condition, err)
+		}
+		if ok.True() {
+			continue
+		}
+		if rule.Error != "" {
+			return xerrors.New(rule.Error)
+		}
+		return xerrors.Errorf("value does not satisfy %q", rule.Condition)
+	}
+	return nil
+}
+
+func (e *Evaluator) evaluate(expression string, self *cty.Value) (cty.Value, error) {
+	expr, diags := hclsyntax.ParseExpression([]byte(expression), "expression", hcl.InitialPos)
+	if diags.HasErrors() {
+		return cty.NilVal, xerrors.Errorf("parse %q: %s", expression, diags.Error())
+	}
+	variables := map[string]cty.Value{
+		"parameters": e.parameters,
+	}
+	if self != nil {
+		variables["self"] = *self
+	}
+	value, diags := expr.Value(&hcl.EvalContext{
+		Variables: variables,
+		Functions: functions,
+	})
+	if diags.HasErrors() {
+		return cty.NilVal, xerrors.Errorf("evaluate %q: %s", expression, diags.Error())
+	}
+	if value.IsNull() || !value.IsWhollyKnown() {
+		return cty.NilVal, xerrors.Errorf("%q has no value", expression)
+	}
+	return value, nil
+}
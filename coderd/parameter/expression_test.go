@@ -0,0 +1,86 @@
+package parameter_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/parameter"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+func TestEvaluator(t *testing.T) {
+	t.Parallel()
+
+	params := []codersdk.TemplateVersionParameter{{
+		Name:         "machine",
+		DefaultValue: "cpu",
+	}, {
+		Name:         "gpu_count",
+		DefaultValue: "1",
+		VisibleIf:    `parameters["machine"] == "gpu" ? 1 : 0`,
+		ValidationRules: []codersdk.TemplateVersionParameterValidationRule{{
+			Condition: `tonumber(self.value) <= 4`,
+			Error:     "At most 4 GPUs are available.",
+		}},
+	}, {
+		Name:         "disk_size",
+		DefaultValue: "10",
+		VisibleIf:    `parameters["machine"] != ""`,
+		ValidationRules: []codersdk.TemplateVersionParameterValidationRule{{
+			Condition: `parameters["machine"] != "gpu" || self.value >= 50`,
+		}},
+	}}
+
+	t.Run("Visible", func(t *testing.T) {
+		t.Parallel()
+
+		evaluator := parameter.NewEvaluator(params, nil)
+		visible, err := evaluator.Visible(params[0])
+		require.NoError(t, err)
+		require.True(t, visible)
+		visible, err = evaluator.Visible(params[1])
+		require.NoError(t, err)
+		require.False(t, visible)
+		visible, err = evaluator.Visible(params[2])
+		require.NoError(t, err)
+		require.True(t, visible)
+
+		evaluator = parameter.NewEvaluator(params, map[string]string{"machine": "gpu"})
+		visible, err = evaluator.Visible(params[1])
+		require.NoError(t, err)
+		require.True(t, visible)
+	})
+
+	t.Run("Validate", func(t *testing.T) {
+		t.Parallel()
+
+		evaluator := parameter.NewEvaluator(params, map[string]string{"machine": "gpu"})
+		require.NoError(t, evaluator.Validate(params[1], "4"))
+		err := evaluator.Validate(params[1], "8")
+		require.ErrorContains(t, err, "At most 4 GPUs are available.")
+
+		require.NoError(t, evaluator.Validate(params[2], "50"))
+		err = evaluator.Validate(params[2], "10")
+		require.ErrorContains(t, err, "does not satisfy")
+
+		evaluator = parameter.NewEvaluator(params, nil)
+		require.NoError(t, evaluator.Validate(params[2], "10"))
+	})
+
+	t.Run("InvalidExpression", func(t *testing.T) {
+		t.Parallel()
+
+		evaluator := parameter.NewEvaluator(params, nil)
+		_, err := evaluator.Visible(codersdk.TemplateVersionParameter{
+			Name:      "broken",
+			VisibleIf: `parameters["missing"] == "x"`,
+		})
+		require.Error(t, err)
+		_, err = evaluator.Visible(codersdk.TemplateVersionParameter{
+			Name:      "broken",
+			VisibleIf: `"text"`,
+		})
+		require.Error(t, err)
+	})
+}
@@ -32,7 +32,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/database/pubsub"
-	"github.com/coder/coder/v2/coderd/gitauth"
+	"github.com/coder/coder/v2/coderd/externalauth"
 	"github.com/coder/coder/v2/coderd/httpmw"
 	"github.com/coder/coder/v2/coderd/schedule"
 	"github.com/coder/coder/v2/coderd/telemetry"
@@ -50,8 +50,10 @@ var (
 )
 
 type Options struct {
-	OIDCConfig     httpmw.OAuth2Config
-	GitAuthConfigs []*gitauth.Config
+	OIDCConfig httpmw.OAuth2Config
+	// OIDCProviders are the additional OIDC providers keyed by ID.
+	OIDCProviders  map[string]httpmw.OAuth2Config
+	GitAuthConfigs []*externalauth.Config
 	// TimeNowFn is only used in tests
 	TimeNowFn func() time.Time
 }
@@ -61,7 +63,7 @@ type server struct {
 	ID                          uuid.UUID
 	Logger                      slog.Logger
 	Provisioners                []database.ProvisionerType
-	GitAuthConfigs              []*gitauth.Config
+	GitAuthConfigs              []*externalauth.Config
 	Tags                        json.RawMessage
 	Database                    database.Store
 	Pubsub                      pubsub.Pubsub
@@ -75,6 +77,7 @@ type server struct {
 
 	AcquireJobDebounce time.Duration
 	OIDCConfig         httpmw.OAuth2Config
+	OIDCProviders      map[string]httpmw.OAuth2Config
 
 	TimeNowFn func() time.Time
 }
@@ -131,6 +134,7 @@ func NewServer(
 		DeploymentValues:            deploymentValues,
 		AcquireJobDebounce:          acquireJobDebounce,
 		OIDCConfig:                  options.OIDCConfig,
+		OIDCProviders:               options.OIDCProviders,
 		TimeNowFn:                   options.TimeNowFn,
 	}, nil
 }
@@ -263,12 +267,9 @@ func (s *server) AcquireJob(ctx context.Context, _ *proto.Empty) (*proto.Acquire
 			return nil, failJob(fmt.Sprintf("publish workspace update: %s", err))
 		}
 
-		var workspaceOwnerOIDCAccessToken string
-		if s.OIDCConfig != nil {
-			workspaceOwnerOIDCAccessToken, err = obtainOIDCAccessToken(ctx, s.Database, s.OIDCConfig, owner.ID)
-			if err != nil {
-				return nil, failJob(fmt.Sprintf("obtain OIDC access token: %s", err))
-			}
+		workspaceOwnerOIDCAccessToken, err := s.obtainOwnerOIDCAccessToken(ctx, owner.ID)
+		if err != nil {
+			return nil, failJob(fmt.Sprintf("obtain OIDC access token: %s", err))
 		}
 
 		var sessionToken string
@@ -307,7 +308,7 @@ func (s *server) AcquireJob(ctx context.Context, _ *proto.Empty) (*proto.Acquire
 			if err != nil {
 				return nil, failJob(fmt.Sprintf("acquire git auth link: %s", err))
 			}
-			var config *gitauth.Config
+			var config *externalauth.Config
 			for _, c := range s.GitAuthConfigs {
 				if c.ID != p {
 					continue
@@ -869,6 +870,14 @@ func (s *server) CompleteJob(ctx context.Context, completed *proto.CompletedJob)
 			if err != nil {
 				return nil, xerrors.Errorf("marshal parameter options: %w", err)
 			}
+			validationRules := richParameter.ValidationRules
+			if validationRules == nil {
+				validationRules = []*sdkproto.RichParameterValidationRule{}
+			}
+			validationRulesJSON, err := json.Marshal(validationRules)
+			if err != nil {
+				return nil, xerrors.Errorf("marshal parameter validation rules: %w", err)
+			}
 
 			var validationMin, validationMax sql.NullInt32
 			if richParameter.ValidationMin != nil {
@@ -902,6 +911,8 @@ func (s *server) CompleteJob(ctx context.Context, completed *proto.CompletedJob)
 				Required:            richParameter.Required,
 				DisplayOrder:        richParameter.Order,
 				Ephemeral:           richParameter.Ephemeral,
+				VisibleIf:           richParameter.VisibleIf,
+				ValidationRules:     validationRulesJSON,
 			})
 			if err != nil {
 				return nil, xerrors.Errorf("insert parameter: %w", err)
@@ -1180,6 +1191,26 @@ func (s *server) CompleteJob(ctx context.Context, completed *proto.CompletedJob)
 	return &proto.Empty{}, nil
 }
 
+// Heartbeat records that the provisioner daemon is alive along with the
+// versions it reported.
+func (s *server) Heartbeat(ctx context.Context, request *proto.HeartbeatRequest) (*proto.Empty, error) {
+	//nolint:gocritic // Provisionerd has specific authz rules.
+	ctx = dbauthz.AsProvisionerd(ctx)
+	err := s.Database.UpdateProvisionerDaemonLastSeenAt(ctx, database.UpdateProvisionerDaemonLastSeenAtParams{
+		ID: s.ID,
+		LastSeenAt: sql.NullTime{
+			Time:  s.timeNow(),
+			Valid: true,
+		},
+		Version:             request.Version,
+		ProvisionerVersions: request.ProvisionerVersions,
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("update provisioner daemon last seen at: %w", err)
+	}
+	return &proto.Empty{}, nil
+}
+
 func (s *server) startTrace(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
 	return s.Tracer.Start(ctx, name, append(opts, trace.WithAttributes(
 		semconv.ServiceNameKey.String("coderd.provisionerd"),
@@ -1436,12 +1467,36 @@ func deleteSessionToken(ctx context.Context, db database.Store, workspace databa
 	return nil
 }
 
+// obtainOwnerOIDCAccessToken returns the access token of the first OIDC
+// provider the user is linked to. The default provider is tried first, then
+// the additional providers ordered by ID.
+func (s *server) obtainOwnerOIDCAccessToken(ctx context.Context, userID uuid.UUID) (string, error) {
+	if s.OIDCConfig != nil {
+		token, err := obtainOIDCAccessToken(ctx, s.Database, s.OIDCConfig, "", userID)
+		if err != nil || token != "" {
+			return token, err
+		}
+	}
+
+	providerIDs := maps.Keys(s.OIDCProviders)
+	slices.Sort(providerIDs)
+	for _, providerID := range providerIDs {
+		token, err := obtainOIDCAccessToken(ctx, s.Database, s.OIDCProviders[providerID], providerID, userID)
+		if err != nil || token != "" {
+			return token, err
+		}
+	}
+	return "", nil
+}
+
 // obtainOIDCAccessToken returns a valid OpenID Connect access token
 // for the user if it's able to obtain one, otherwise it returns an empty string.
-func obtainOIDCAccessToken(ctx context.Context, db database.Store, oidcConfig httpmw.OAuth2Config, userID uuid.UUID) (string, error) {
+// The provider ID is empty for the default provider.
+func obtainOIDCAccessToken(ctx context.Context, db database.Store, oidcConfig httpmw.OAuth2Config, providerID string, userID uuid.UUID) (string, error) {
 	link, err := db.GetUserLinkByUserIDLoginType(ctx, database.GetUserLinkByUserIDLoginTypeParams{
-		UserID:    userID,
-		LoginType: database.LoginTypeOIDC,
+		UserID:     userID,
+		LoginType:  database.LoginTypeOIDC,
+		ProviderID: providerID,
 	})
 	if errors.Is(err, sql.ErrNoRows) {
 		err = nil
@@ -1469,6 +1524,7 @@ func obtainOIDCAccessToken(ctx context.Context, db database.Store, oidcConfig ht
 		link, err = db.UpdateUserLink(ctx, database.UpdateUserLinkParams{
 			UserID:            userID,
 			LoginType:         database.LoginTypeOIDC,
+			ProviderID:        providerID,
 			OAuthAccessToken:  link.OAuthAccessToken,
 			OAuthRefreshToken: link.OAuthRefreshToken,
 			OAuthExpiry:       link.OAuthExpiry,
@@ -13,6 +13,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbfake"
 	"github.com/coder/coder/v2/coderd/database/dbgen"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpmw"
 	"github.com/coder/coder/v2/testutil"
 )
 
@@ -22,7 +23,7 @@ func TestObtainOIDCAccessToken(t *testing.T) {
 	t.Run("NoToken", func(t *testing.T) {
 		t.Parallel()
 		db := dbfake.New()
-		_, err := obtainOIDCAccessToken(ctx, db, nil, uuid.Nil)
+		_, err := obtainOIDCAccessToken(ctx, db, nil, "", uuid.Nil)
 		require.NoError(t, err)
 	})
 	t.Run("InvalidConfig", func(t *testing.T) {
@@ -35,7 +36,7 @@ func TestObtainOIDCAccessToken(t *testing.T) {
 			LoginType:   database.LoginTypeOIDC,
 			OAuthExpiry: dbtime.Now().Add(-time.Hour),
 		})
-		_, err := obtainOIDCAccessToken(ctx, db, &oauth2.Config{}, user.ID)
+		_, err := obtainOIDCAccessToken(ctx, db, &oauth2.Config{}, "", user.ID)
 		require.NoError(t, err)
 	})
 	t.Run("Exchange", func(t *testing.T) {
@@ -51,7 +52,7 @@ func TestObtainOIDCAccessToken(t *testing.T) {
 			Token: &oauth2.Token{
 				AccessToken: "token",
 			},
-		}, user.ID)
+		}, "", user.ID)
 		require.NoError(t, err)
 		link, err := db.GetUserLinkByUserIDLoginType(ctx, database.GetUserLinkByUserIDLoginTypeParams{
 			UserID:    user.ID,
@@ -60,4 +61,35 @@ func TestObtainOIDCAccessToken(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, "token", link.OAuthAccessToken)
 	})
+	t.Run("Provider", func(t *testing.T) {
+		t.Parallel()
+		db := dbfake.New()
+		user := dbgen.User(t, db, database.User{})
+		dbgen.UserLink(t, db, database.UserLink{
+			UserID:      user.ID,
+			LoginType:   database.LoginTypeOIDC,
+			ProviderID:  "okta",
+			OAuthExpiry: dbtime.Now().Add(-time.Hour),
+		})
+		srv := &server{
+			Database: db,
+			OIDCProviders: map[string]httpmw.OAuth2Config{
+				"okta": &testutil.OAuth2Config{
+					Token: &oauth2.Token{
+						AccessToken: "okta-token",
+					},
+				},
+			},
+		}
+		token, err := srv.obtainOwnerOIDCAccessToken(ctx, user.ID)
+		require.NoError(t, err)
+		require.Equal(t, "okta-token", token)
+		link, err := db.GetUserLinkByUserIDLoginType(ctx, database.GetUserLinkByUserIDLoginTypeParams{
+			UserID:     user.ID,
+			LoginType:  database.LoginTypeOIDC,
+			ProviderID: "okta",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "okta-token", link.OAuthAccessToken)
+	})
 }
@@ -24,7 +24,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbgen"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/database/pubsub"
-	"github.com/coder/coder/v2/coderd/gitauth"
+	"github.com/coder/coder/v2/coderd/externalauth"
 	"github.com/coder/coder/v2/coderd/provisionerdserver"
 	"github.com/coder/coder/v2/coderd/schedule"
 	"github.com/coder/coder/v2/coderd/telemetry"
@@ -126,7 +126,7 @@ func TestAcquireJob(t *testing.T) {
 		gitAuthProvider := "github"
 		srv, db, ps := setup(t, false, &overrides{
 			deploymentValues: dv,
-			gitAuthConfigs: []*gitauth.Config{{
+			gitAuthConfigs: []*externalauth.Config{{
 				ID:           gitAuthProvider,
 				OAuth2Config: &testutil.OAuth2Config{},
 			}},
@@ -921,7 +921,7 @@ func TestCompleteJob(t *testing.T) {
 		srvID := uuid.New()
 		srv, db, _ := setup(t, false, &overrides{
 			id: &srvID,
-			gitAuthConfigs: []*gitauth.Config{{
+			gitAuthConfigs: []*externalauth.Config{{
 				ID: "github",
 			}},
 		})
@@ -1653,9 +1653,46 @@ func TestInsertWorkspaceResource(t *testing.T) {
 	})
 }
 
+func TestHeartbeat(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		now := dbtime.Now()
+		srvID := uuid.New()
+		srv, db, _ := setup(t, false, &overrides{
+			id:        &srvID,
+			timeNowFn: func() time.Time { return now },
+		})
+		_, err := db.InsertProvisionerDaemon(ctx, database.InsertProvisionerDaemonParams{
+			ID:           srvID,
+			CreatedAt:    now,
+			Name:         "test",
+			Provisioners: []database.ProvisionerType{database.ProvisionerTypeEcho},
+		})
+		require.NoError(t, err)
+
+		_, err = srv.Heartbeat(ctx, &proto.HeartbeatRequest{
+			Version: "v2.1.0",
+			ProvisionerVersions: map[string]string{
+				"terraform": "1.5.5",
+			},
+		})
+		require.NoError(t, err)
+
+		daemons, err := db.GetProvisionerDaemons(ctx)
+		require.NoError(t, err)
+		require.Len(t, daemons, 1)
+		require.True(t, daemons[0].LastSeenAt.Valid)
+		require.Equal(t, now, daemons[0].LastSeenAt.Time)
+		require.Equal(t, "v2.1.0", daemons[0].Version)
+		require.Equal(t, "1.5.5", daemons[0].ProvisionerVersions["terraform"])
+	})
+}
+
 type overrides struct {
 	deploymentValues            *codersdk.DeploymentValues
-	gitAuthConfigs              []*gitauth.Config
+	gitAuthConfigs              []*externalauth.Config
 	id                          *uuid.UUID
 	templateScheduleStore       *atomic.Pointer[schedule.TemplateScheduleStore]
 	userQuietHoursScheduleStore *atomic.Pointer[schedule.UserQuietHoursScheduleStore]
@@ -1667,7 +1704,7 @@ func setup(t *testing.T, ignoreLogErrors bool, ov *overrides) (proto.DRPCProvisi
 	db := dbfake.New()
 	ps := pubsub.NewInMemory()
 	deploymentValues := &codersdk.DeploymentValues{}
-	var gitAuthConfigs []*gitauth.Config
+	var gitAuthConfigs []*externalauth.Config
 	srvID := uuid.New()
 	tss := testTemplateScheduleStore()
 	uqhss := testUserQuietHoursScheduleStore()
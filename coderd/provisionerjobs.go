@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"sort"
 	"strconv"
+	"time"
 
 	"github.com/google/uuid"
 	"golang.org/x/xerrors"
@@ -225,6 +226,21 @@ func convertProvisionerJob(pj database.GetProvisionerJobsByIDsWithQueuePositionR
 	return job
 }
 
+// estimateProvisionerJobStart returns when a pending job is expected to start.
+// The jobs that the daemons able to run it are running and the jobs ahead of it
+// in the queue are spread across those daemons, each taking buildTime.
+func estimateProvisionerJobStart(pj database.GetProvisionerJobsByIDsWithQueuePositionRow, buildTime time.Duration, now time.Time) *time.Time {
+	if pj.ProvisionerJob.StartedAt.Valid || pj.ProvisionerJob.CompletedAt.Valid {
+		return nil
+	}
+	if pj.QueuePosition == 0 || pj.MatchingDaemons == 0 || buildTime <= 0 {
+		return nil
+	}
+	rounds := (pj.RunningJobs + pj.QueuePosition - 1) / pj.MatchingDaemons
+	startAt := now.Add(time.Duration(rounds) * buildTime)
+	return &startAt
+}
+
 func fetchAndWriteLogs(ctx context.Context, db database.Store, jobID uuid.UUID, after int64, rw http.ResponseWriter) {
 	logs, err := db.GetProvisionerLogsAfterID(ctx, database.GetProvisionerLogsAfterIDParams{
 		JobID:        jobID,
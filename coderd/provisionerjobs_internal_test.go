@@ -22,6 +22,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbmock"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/database/pubsub"
+	"github.com/coder/coder/v2/coderd/util/ptr"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/provisionersdk"
 	"github.com/coder/coder/v2/testutil"
@@ -135,6 +136,64 @@ func TestConvertProvisionerJob_Unit(t *testing.T) {
 	}
 }
 
+func TestEstimateProvisionerJobStart(t *testing.T) {
+	t.Parallel()
+
+	now := dbtime.Now()
+	buildTime := time.Minute
+	started := database.ProvisionerJob{
+		StartedAt: sql.NullTime{Time: now, Valid: true},
+	}
+
+	testCases := []struct {
+		name     string
+		input    database.GetProvisionerJobsByIDsWithQueuePositionRow
+		expected *time.Time
+	}{
+		{
+			name:  "started",
+			input: database.GetProvisionerJobsByIDsWithQueuePositionRow{ProvisionerJob: started, QueuePosition: 1, MatchingDaemons: 1},
+		},
+		{
+			name:  "no daemons",
+			input: database.GetProvisionerJobsByIDsWithQueuePositionRow{QueuePosition: 1},
+		},
+		{
+			name:     "next",
+			input:    database.GetProvisionerJobsByIDsWithQueuePositionRow{QueuePosition: 1, MatchingDaemons: 1},
+			expected: &now,
+		},
+		{
+			name:     "behind one round",
+			input:    database.GetProvisionerJobsByIDsWithQueuePositionRow{QueuePosition: 3, MatchingDaemons: 2},
+			expected: ptr.Ref(now.Add(buildTime)),
+		},
+		{
+			name:     "behind two rounds",
+			input:    database.GetProvisionerJobsByIDsWithQueuePositionRow{QueuePosition: 5, MatchingDaemons: 2},
+			expected: ptr.Ref(now.Add(2 * buildTime)),
+		},
+		{
+			name:     "idle daemon",
+			input:    database.GetProvisionerJobsByIDsWithQueuePositionRow{QueuePosition: 1, MatchingDaemons: 2, RunningJobs: 1},
+			expected: &now,
+		},
+		{
+			name:     "behind running jobs",
+			input:    database.GetProvisionerJobsByIDsWithQueuePositionRow{QueuePosition: 1, MatchingDaemons: 2, RunningJobs: 2},
+			expected: ptr.Ref(now.Add(buildTime)),
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			actual := estimateProvisionerJobStart(testCase.input, buildTime, now)
+			assert.Equal(t, testCase.expected, actual)
+		})
+	}
+}
+
 func Test_logFollower_completeBeforeFollow(t *testing.T) {
 	t.Parallel()
 	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitShort)
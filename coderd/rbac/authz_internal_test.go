@@ -1021,6 +1021,70 @@ func TestAuthorizeScope(t *testing.T) {
 			{resource: ResourceWorkspace.InOrg(unusedID).WithOwner("not-me"), actions: []Action{ActionCreate}, allow: false},
 		},
 	)
+
+	// Fine-grained scopes combine, and are limited by their allow list.
+	templateID := uuid.New()
+	user = Subject{
+		ID: "me",
+		Roles: Roles{
+			must(RoleByName(RoleOwner())),
+		},
+		Scope: ScopeSet{
+			Scopes:      []ScopeName{ScopeWorkspaceRead, ScopeTemplatePush},
+			AllowIDList: []string{workspaceID.String(), templateID.String(), ""},
+		},
+	}
+
+	testAuthorize(t, "Admin_ScopeSet", user,
+		// Operations outside of the scopes are rejected even for owners.
+		cases(func(c authTestCase) authTestCase {
+			c.actions = []Action{ActionCreate, ActionUpdate, ActionDelete}
+			c.allow = false
+			c.resource = c.resource.WithID(workspaceID)
+			return c
+		}, []authTestCase{
+			{resource: ResourceWorkspace.InOrg(defOrg).WithOwner(user.ID)},
+			{resource: ResourceWorkspace.InOrg(defOrg).WithOwner("not-me")},
+		}),
+		[]authTestCase{
+			{resource: ResourceWorkspace.WithID(workspaceID).InOrg(defOrg).WithOwner(user.ID), actions: []Action{ActionRead}, allow: true},
+			{resource: ResourceWorkspace.WithID(uuid.New()).InOrg(defOrg).WithOwner(user.ID), actions: []Action{ActionRead}, allow: false},
+			{resource: ResourceTemplate.WithID(templateID).InOrg(defOrg), actions: []Action{ActionRead, ActionUpdate}, allow: true},
+			{resource: ResourceTemplate.WithID(uuid.New()).InOrg(defOrg), actions: []Action{ActionRead, ActionUpdate}, allow: false},
+			{resource: ResourceTemplate.WithID(templateID).InOrg(defOrg), actions: []Action{ActionDelete}, allow: false},
+			{resource: ResourceFile.WithOwner(user.ID), actions: []Action{ActionCreate}, allow: true},
+			// Organizations and uploaded files are not limited by the allow
+			// list.
+			{resource: ResourceFile.WithID(uuid.New()).WithOwner(user.ID), actions: []Action{ActionRead}, allow: true},
+			{resource: ResourceOrganization.WithID(defOrg).InOrg(defOrg), actions: []Action{ActionRead}, allow: true},
+			{resource: ResourceOrganization.WithID(defOrg).InOrg(defOrg), actions: []Action{ActionUpdate}, allow: false},
+			{resource: ResourceDeploymentValues, actions: []Action{ActionRead}, allow: false},
+		},
+	)
+}
+
+func TestScopeSet(t *testing.T) {
+	t.Parallel()
+
+	_, err := ScopeSet{}.Expand()
+	require.Error(t, err, "empty scope set")
+	_, err = ScopeSet{Scopes: []ScopeName{ScopeAll}}.Expand()
+	require.Error(t, err, "coarse scopes cannot be combined")
+
+	a := ScopeSet{Scopes: []ScopeName{ScopeWorkspaceRead, ScopeTemplateRead}, AllowIDList: []string{"b", "a"}}
+	b := ScopeSet{Scopes: []ScopeName{ScopeTemplateRead, ScopeWorkspaceRead}, AllowIDList: []string{"a", "b"}}
+	require.Equal(t, a.Name(), b.Name(), "names are order independent")
+	require.NotEqual(t, a.Name(), ScopeSet{Scopes: a.Scopes}.Name(), "names include the allow list")
+
+	scope, err := ScopeSet{Scopes: []ScopeName{ScopeWorkspaceRead}}.Expand()
+	require.NoError(t, err)
+	require.Equal(t, []string{WildcardSymbol}, scope.AllowIDList)
+	scope, err = a.Expand()
+	require.NoError(t, err)
+	require.Equal(t, []string{"b", "a", "organization:*", "file:*"}, scope.AllowIDList)
+
+	require.Contains(t, FineGrainedScopes(), ScopeWorkspaceStart)
+	require.NotContains(t, FineGrainedScopes(), ScopeAll)
 }
 
 // cases applies a given function to all test cases. This makes generalities easier to create.
@@ -193,6 +193,27 @@ var (
 	ResourceTailnetCoordinator = Object{
 		Type: "tailnet_coordinator",
 	}
+
+	// ResourceOAuth2ProviderApp is a third-party app registered with Coder's
+	// OAuth2 provider.
+	//	create/update/delete = Register, edit, or remove an app.
+	//	read = View an app, e.g. on the consent page.
+	ResourceOAuth2ProviderApp = Object{
+		Type: "oauth2_app",
+	}
+
+	// ResourceOAuth2ProviderAppSecret is a client secret of an OAuth2 app.
+	// Secrets are site wide and only managed by admins.
+	ResourceOAuth2ProviderAppSecret = Object{
+		Type: "oauth2_app_secret",
+	}
+
+	// ResourceOAuth2ProviderAppCodeToken is an authorization code or refresh
+	// token issued to an OAuth2 app on behalf of a user. These are owned by
+	// the user that authorized the app.
+	ResourceOAuth2ProviderAppCodeToken = Object{
+		Type: "oauth2_app_code_token",
+	}
 )
 
 // ResourceUserObject is a helper function to create a user object for authz checks.
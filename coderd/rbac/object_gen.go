@@ -11,6 +11,9 @@ func AllResources() []Object {
 		ResourceFile,
 		ResourceGroup,
 		ResourceLicense,
+		ResourceOAuth2ProviderApp,
+		ResourceOAuth2ProviderAppCodeToken,
+		ResourceOAuth2ProviderAppSecret,
 		ResourceOrgRoleAssignment,
 		ResourceOrganization,
 		ResourceOrganizationMember,
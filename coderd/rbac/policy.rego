@@ -161,6 +161,13 @@ scope_allow_list {
 	input.object.id in input.subject.scope.allow_list
 }
 
+# An entry of the form '<resource type>:*' allows every resource of that type.
+# The type is always known, so partial compilations never include it either.
+scope_allow_list {
+	not "*" in input.subject.scope.allow_list
+	concat(":", [input.object.type, "*"]) in input.subject.scope.allow_list
+}
+
 # The allow block is quite simple. Any set with `-1` cascades down in levels.
 # Authorization looks for any `allow` statement that is true. Multiple can be true!
 # Note that the absence of `allow` means "unauthorized".
@@ -148,6 +148,8 @@ func ReloadBuiltinRoles(opts *RoleOptions) {
 			ResourceRoleAssignment.Type: {ActionRead},
 			// All users can see the provisioner daemons.
 			ResourceProvisionerDaemon.Type: {ActionRead},
+			// All users can see the OAuth2 apps they are asked to authorize.
+			ResourceOAuth2ProviderApp.Type: {ActionRead},
 		}),
 		Org: map[string][]Permission{},
 		User: append(allPermsExcept(ResourceWorkspaceDormant, ResourceUser, ResourceOrganizationMember),
@@ -336,6 +336,42 @@ func TestRolePermissions(t *testing.T) {
 				false: {userAdmin, otherOrgAdmin, otherOrgMember, templateAdmin, memberMe},
 			},
 		},
+		{
+			Name:     "OAuth2ProviderApp",
+			Actions:  []rbac.Action{rbac.ActionCreate, rbac.ActionUpdate, rbac.ActionDelete},
+			Resource: rbac.ResourceOAuth2ProviderApp,
+			AuthorizeMap: map[bool][]authSubject{
+				true:  {owner},
+				false: {orgAdmin, orgMemberMe, otherOrgAdmin, otherOrgMember, memberMe, templateAdmin, userAdmin},
+			},
+		},
+		{
+			Name:     "ReadOAuth2ProviderApp",
+			Actions:  []rbac.Action{rbac.ActionRead},
+			Resource: rbac.ResourceOAuth2ProviderApp,
+			AuthorizeMap: map[bool][]authSubject{
+				true:  {owner, orgAdmin, orgMemberMe, otherOrgAdmin, otherOrgMember, memberMe, templateAdmin, userAdmin},
+				false: {},
+			},
+		},
+		{
+			Name:     "OAuth2ProviderAppSecret",
+			Actions:  rbac.AllActions(),
+			Resource: rbac.ResourceOAuth2ProviderAppSecret,
+			AuthorizeMap: map[bool][]authSubject{
+				true:  {owner},
+				false: {orgAdmin, orgMemberMe, otherOrgAdmin, otherOrgMember, memberMe, templateAdmin, userAdmin},
+			},
+		},
+		{
+			Name:     "OAuth2ProviderAppCodeToken",
+			Actions:  rbac.AllActions(),
+			Resource: rbac.ResourceOAuth2ProviderAppCodeToken.WithID(uuid.New()).WithOwner(currentUser.String()),
+			AuthorizeMap: map[bool][]authSubject{
+				true:  {owner, orgMemberMe, memberMe},
+				false: {orgAdmin, otherOrgAdmin, otherOrgMember, templateAdmin, userAdmin},
+			},
+		},
 	}
 
 	for _, c := range testCases {
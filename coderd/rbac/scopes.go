@@ -2,6 +2,8 @@ package rbac
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/google/uuid"
 
@@ -34,9 +36,63 @@ func WorkspaceAgentScope(workspaceID, ownerID uuid.UUID) Scope {
 const (
 	ScopeAll                ScopeName = "all"
 	ScopeApplicationConnect ScopeName = "application_connect"
+
+	// Fine-grained scopes are named "<resource>:<operation>" and can be
+	// combined on a single token with a ScopeSet.
+	ScopeUserRead       ScopeName = "user:read"
+	ScopeWorkspaceRead  ScopeName = "workspace:read"
+	ScopeWorkspaceStart ScopeName = "workspace:start"
+	ScopeTemplateRead   ScopeName = "template:read"
+	ScopeTemplatePush   ScopeName = "template:push"
 )
 
-// TODO: Support passing in scopeID list for allowlisting resources.
+// scopeBasePermissions are granted by every fine-grained scope. Nearly every
+// route looks up the calling user and their organization before doing
+// anything else, so a scope without them would be useless.
+var scopeBasePermissions = map[string][]Action{
+	ResourceUser.Type:               {ActionRead},
+	ResourceOrganization.Type:       {ActionRead},
+	ResourceOrganizationMember.Type: {ActionRead},
+}
+
+// allowListExemptTypes are resource types that fine-grained scopes can act on
+// regardless of their allow list. Routes look up the caller's organization
+// before anything else, and template versions are pushed from newly uploaded
+// files whose IDs cannot be known when the token is created. The permissions
+// of the scopes still apply.
+var allowListExemptTypes = []string{
+	ResourceOrganization.Type,
+	ResourceFile.Type,
+}
+
+// allowListType returns an allow list entry that allows every resource of the
+// given type.
+func allowListType(resourceType string) string {
+	return fmt.Sprintf("%s:%s", resourceType, WildcardSymbol)
+}
+
+// fineGrainedScope builds a scope from the base permissions plus the given
+// site wide permissions.
+func fineGrainedScope(name ScopeName, displayName string, perms map[string][]Action) Scope {
+	site := make(map[string][]Action, len(scopeBasePermissions)+len(perms))
+	for k, v := range scopeBasePermissions {
+		site[k] = v
+	}
+	for k, v := range perms {
+		site[k] = append(site[k], v...)
+	}
+	return Scope{
+		Role: Role{
+			Name:        fmt.Sprintf("Scope_%s", name),
+			DisplayName: displayName,
+			Site:        Permissions(site),
+			Org:         map[string][]Permission{},
+			User:        []Permission{},
+		},
+		AllowIDList: []string{WildcardSymbol},
+	}
+}
+
 var builtinScopes = map[ScopeName]Scope{
 	// ScopeAll is a special scope that allows access to all resources. During
 	// authorize checks it is usually not used directly and skips scope checks.
@@ -65,6 +121,42 @@ var builtinScopes = map[ScopeName]Scope{
 		},
 		AllowIDList: []string{WildcardSymbol},
 	},
+
+	ScopeUserRead: fineGrainedScope(ScopeUserRead, "Read users", nil),
+	ScopeWorkspaceRead: fineGrainedScope(ScopeWorkspaceRead, "Read workspaces", map[string][]Action{
+		ResourceWorkspace.Type: {ActionRead},
+		ResourceTemplate.Type:  {ActionRead},
+	}),
+	// Starting and stopping a workspace are both updates to the workspace, so
+	// this scope can also stop workspaces.
+	ScopeWorkspaceStart: fineGrainedScope(ScopeWorkspaceStart, "Start and stop workspaces", map[string][]Action{
+		ResourceWorkspace.Type:      {ActionRead, ActionUpdate},
+		ResourceWorkspaceBuild.Type: {ActionUpdate},
+		ResourceTemplate.Type:       {ActionRead},
+	}),
+	ScopeTemplateRead: fineGrainedScope(ScopeTemplateRead, "Read templates", map[string][]Action{
+		ResourceTemplate.Type: {ActionRead},
+	}),
+	ScopeTemplatePush: fineGrainedScope(ScopeTemplatePush, "Push template versions", map[string][]Action{
+		ResourceTemplate.Type:          {ActionCreate, ActionRead, ActionUpdate},
+		ResourceFile.Type:              {ActionCreate, ActionRead},
+		ResourceProvisionerDaemon.Type: {ActionRead},
+	}),
+}
+
+// FineGrainedScopes returns the names of the scopes that can be combined in a
+// ScopeSet, sorted by name.
+func FineGrainedScopes() []ScopeName {
+	names := make([]ScopeName, 0, len(builtinScopes))
+	for name := range builtinScopes {
+		if name.FineGrained() {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return names[i] < names[j]
+	})
+	return names
 }
 
 type ExpandableScope interface {
@@ -84,6 +176,88 @@ func (name ScopeName) Name() string {
 	return string(name)
 }
 
+// FineGrained returns true if the scope is one of the "<resource>:<operation>"
+// scopes that can be combined in a ScopeSet.
+func (name ScopeName) FineGrained() bool {
+	_, ok := builtinScopes[name]
+	return ok && strings.Contains(string(name), ":")
+}
+
+// ScopeSet is the union of several fine-grained scopes, optionally limited to
+// the resources in AllowIDList. An empty AllowIDList allows all resources.
+type ScopeSet struct {
+	Scopes      []ScopeName
+	AllowIDList []string
+}
+
+func (s ScopeSet) Expand() (Scope, error) {
+	if len(s.Scopes) == 0 {
+		return Scope{}, xerrors.New("scope set must contain at least one scope")
+	}
+
+	actions := map[string][]Action{}
+	for _, name := range s.Scopes {
+		if !name.FineGrained() {
+			return Scope{}, xerrors.Errorf("scope %q cannot be combined with other scopes", name)
+		}
+		for _, perm := range builtinScopes[name].Site {
+			actions[perm.ResourceType] = append(actions[perm.ResourceType], perm.Action)
+		}
+	}
+	for resource, acts := range actions {
+		sort.Slice(acts, func(i, j int) bool {
+			return acts[i] < acts[j]
+		})
+		actions[resource] = uniqueActions(acts)
+	}
+
+	allow := []string{WildcardSymbol}
+	if len(s.AllowIDList) > 0 {
+		allow = append([]string{}, s.AllowIDList...)
+		for _, resourceType := range allowListExemptTypes {
+			allow = append(allow, allowListType(resourceType))
+		}
+	}
+	return Scope{
+		Role: Role{
+			Name:        s.Name(),
+			DisplayName: "Fine-grained scopes",
+			Site:        Permissions(actions),
+			Org:         map[string][]Permission{},
+			User:        []Permission{},
+		},
+		AllowIDList: allow,
+	}, nil
+}
+
+// Name includes the allow list so subjects with different allow lists are
+// never considered equal.
+func (s ScopeSet) Name() string {
+	names := make([]string, 0, len(s.Scopes))
+	for _, name := range s.Scopes {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+	name := fmt.Sprintf("Scope_%s", strings.Join(names, ","))
+	if len(s.AllowIDList) > 0 {
+		ids := append([]string{}, s.AllowIDList...)
+		sort.Strings(ids)
+		name += fmt.Sprintf("[%s]", strings.Join(ids, ","))
+	}
+	return name
+}
+
+func uniqueActions(sorted []Action) []Action {
+	unique := sorted[:0]
+	for i, act := range sorted {
+		if i > 0 && sorted[i-1] == act {
+			continue
+		}
+		unique = append(unique, act)
+	}
+	return unique
+}
+
 // Scope acts the exact same as a Role with the addition that is can also
 // apply an AllowIDList. Any resource being checked against a Scope will
 // reject any resource that is not in the AllowIDList.
@@ -17,6 +17,7 @@ import (
 	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/xerrors"
 	"tailscale.com/derp"
+	"tailscale.com/ipn/ipnstate"
 	"tailscale.com/tailcfg"
 
 	"cdr.dev/slog"
@@ -425,6 +426,11 @@ func (c *netConnCloser) Close() error {
 	return c.Conn.Close()
 }
 
+// Status returns the state of the connections of the server to agents.
+func (s *ServerTailnet) Status() *ipnstate.Status {
+	return s.conn.Status()
+}
+
 func (s *ServerTailnet) Close() error {
 	s.cancel()
 	_ = s.cache.Close()
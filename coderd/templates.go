@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"reflect"
 	"sort"
 	"time"
 
@@ -14,6 +15,7 @@ import (
 
 	"github.com/coder/coder/v2/coderd/audit"
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/httpapi"
@@ -549,6 +551,14 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 	if req.TimeTilDormantAutoDeleteMillis < 0 {
 		validErrs = append(validErrs, codersdk.ValidationError{Field: "locked_ttl_ms", Detail: "Must be a positive integer."})
 	}
+	egressPolicy := template.EgressPolicy
+	if req.EgressPolicy != nil {
+		err = req.EgressPolicy.Validate()
+		if err != nil {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: "egress_policy", Detail: err.Error()})
+		}
+		egressPolicy = db2sdk.DatabaseEgressPolicy(*req.EgressPolicy)
+	}
 
 	if len(validErrs) > 0 {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
@@ -573,7 +583,8 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 			req.AutostopRequirement.Weeks == scheduleOpts.AutostopRequirement.Weeks &&
 			req.FailureTTLMillis == time.Duration(template.FailureTTL).Milliseconds() &&
 			req.TimeTilDormantMillis == time.Duration(template.TimeTilDormant).Milliseconds() &&
-			req.TimeTilDormantAutoDeleteMillis == time.Duration(template.TimeTilDormantAutoDelete).Milliseconds() {
+			req.TimeTilDormantAutoDeleteMillis == time.Duration(template.TimeTilDormantAutoDelete).Milliseconds() &&
+			reflect.DeepEqual(db2sdk.EgressPolicy(egressPolicy), db2sdk.EgressPolicy(template.EgressPolicy)) {
 			return nil
 		}
 
@@ -592,6 +603,7 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 			Description:                  req.Description,
 			Icon:                         req.Icon,
 			AllowUserCancelWorkspaceJobs: req.AllowUserCancelWorkspaceJobs,
+			EgressPolicy:                 egressPolicy,
 		})
 		if err != nil {
 			return xerrors.Errorf("update template metadata: %w", err)
@@ -776,5 +788,6 @@ func (api *API) convertTemplate(
 			DaysOfWeek: codersdk.BitmapToWeekdays(uint8(template.AutostopRequirementDaysOfWeek)),
 			Weeks:      autostopRequirementWeeks,
 		},
+		EgressPolicy: db2sdk.EgressPolicy(template.EgressPolicy),
 	}
 }
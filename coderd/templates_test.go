@@ -965,6 +965,73 @@ func TestPatchTemplateMeta(t *testing.T) {
 		assert.Equal(t, updated.Icon, "")
 	})
 
+	t.Run("EgressPolicy", func(t *testing.T) {
+		t.Parallel()
+
+		t.Run("OK", func(t *testing.T) {
+			t.Parallel()
+
+			client := coderdtest.New(t, nil)
+			user := coderdtest.CreateFirstUser(t, client)
+			version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+			template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+			require.Equal(t, codersdk.EgressPolicy{}, template.EgressPolicy)
+
+			ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+			defer cancel()
+
+			policy := codersdk.EgressPolicy{
+				DefaultAction: codersdk.EgressActionDeny,
+				Rules: []codersdk.EgressRule{{
+					Action: codersdk.EgressActionAllow,
+					CIDR:   "10.0.0.0/8",
+					Ports:  []string{"443", "8000-8999"},
+				}},
+			}
+			updated, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+				EgressPolicy: &policy,
+			})
+			require.NoError(t, err)
+			require.Equal(t, policy, updated.EgressPolicy)
+
+			// Other updates leave the egress policy as is.
+			updated, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+				Description: "new description",
+			})
+			require.NoError(t, err)
+			require.Equal(t, policy, updated.EgressPolicy)
+
+			template, err = client.Template(ctx, template.ID)
+			require.NoError(t, err)
+			require.Equal(t, policy, template.EgressPolicy)
+		})
+
+		t.Run("Invalid", func(t *testing.T) {
+			t.Parallel()
+
+			client := coderdtest.New(t, nil)
+			user := coderdtest.CreateFirstUser(t, client)
+			version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+			template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+			ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+			defer cancel()
+
+			_, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+				EgressPolicy: &codersdk.EgressPolicy{
+					Rules: []codersdk.EgressRule{{
+						Action: codersdk.EgressActionDeny,
+						CIDR:   "not-a-cidr",
+					}},
+				},
+			})
+			var apiErr *codersdk.Error
+			require.ErrorAs(t, err, &apiErr)
+			require.Len(t, apiErr.Validations, 1)
+			assert.Equal(t, "egress_policy", apiErr.Validations[0].Field)
+		})
+	})
+
 	t.Run("AutostopRequirement", func(t *testing.T) {
 		t.Parallel()
 
@@ -21,7 +21,7 @@ import (
 	"github.com/coder/coder/v2/coderd/audit"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
-	"github.com/coder/coder/v2/coderd/gitauth"
+	"github.com/coder/coder/v2/coderd/externalauth"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
 	"github.com/coder/coder/v2/coderd/parameter"
@@ -290,7 +290,7 @@ func (api *API) templateVersionGitAuth(rw http.ResponseWriter, r *http.Request)
 	rawProviders := templateVersion.GitAuthProviders
 	providers := make([]codersdk.TemplateVersionGitAuth, 0)
 	for _, rawProvider := range rawProviders {
-		var config *gitauth.Config
+		var config *externalauth.Config
 		for _, provider := range api.GitAuthConfigs {
 			if provider.ID == rawProvider {
 				config = provider
@@ -318,6 +318,8 @@ func (api *API) templateVersionGitAuth(rw http.ResponseWriter, r *http.Request)
 		provider := codersdk.TemplateVersionGitAuth{
 			ID:              config.ID,
 			Type:            config.Type,
+			DisplayName:     config.DisplayName,
+			DisplayIcon:     config.DisplayIcon,
 			AuthenticateURL: redirectURL.String(),
 		}
 
@@ -0,0 +1,121 @@
+// Package totp implements time-based one-time passwords (RFC 6238) as
+// generated by authenticator apps.
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1" //#nosec // RFC 6238 uses SHA-1 by default, which authenticator apps expect.
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+const (
+	// Digits is the length of a code.
+	Digits = 6
+	// Period is the time a code is valid for.
+	Period = 30 * time.Second
+	// Skew is the number of periods before and after the current one that
+	// codes are accepted for, to allow for clock drift.
+	Skew = 1
+
+	// secretSize is the recommended size of HMAC-SHA1 keys in RFC 4226.
+	secretSize = 20
+)
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret returns a random base32 encoded secret.
+func GenerateSecret() (string, error) {
+	secret := make([]byte, secretSize)
+	_, err := rand.Read(secret)
+	if err != nil {
+		return "", xerrors.Errorf("read random bytes: %w", err)
+	}
+	return encoding.EncodeToString(secret), nil
+}
+
+// Step returns the time step of the time, which is the counter codes are
+// derived from.
+func Step(t time.Time) int64 {
+	return t.Unix() / int64(Period/time.Second)
+}
+
+// Code returns the code for the base32 encoded secret at the time step.
+func Code(secret string, step int64) (string, error) {
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return "", err
+	}
+	return code(key, step), nil
+}
+
+// Validate reports whether the code is valid at the time, and returns the
+// time step it was generated for. Codes for steps at or before lastUsedStep
+// are rejected, so each code can only be used once.
+func Validate(secret, input string, now time.Time, lastUsedStep int64) (int64, bool) {
+	input = strings.ReplaceAll(strings.TrimSpace(input), " ", "")
+	if len(input) != Digits {
+		return 0, false
+	}
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return 0, false
+	}
+
+	current := Step(now)
+	for step := current - Skew; step <= current+Skew; step++ {
+		if step <= lastUsedStep {
+			continue
+		}
+		if subtle.ConstantTimeCompare([]byte(code(key, step)), []byte(input)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// URL returns the "otpauth://" URL that authenticator apps enroll with,
+// usually by scanning it as a QR code.
+func URL(issuer, account, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(Digits))
+	query.Set("period", fmt.Sprint(int64(Period/time.Second)))
+	return (&url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + issuer + ":" + account,
+		RawQuery: query.Encode(),
+	}).String()
+}
+
+func decodeSecret(secret string) ([]byte, error) {
+	key, err := encoding.DecodeString(strings.ToUpper(strings.TrimRight(secret, "=")))
+	if err != nil {
+		return nil, xerrors.Errorf("decode secret: %w", err)
+	}
+	return key, nil
+}
+
+func code(key []byte, step int64) string {
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(step))
+	mac := hmac.New(sha1.New, key)
+	_, _ = mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	// Dynamic truncation as described in RFC 4226 section 5.3.
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", Digits, value%1000000)
+}
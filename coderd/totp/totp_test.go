@@ -0,0 +1,94 @@
+package totp_test
+
+import (
+	"encoding/base32"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/totp"
+)
+
+func TestCode(t *testing.T) {
+	t.Parallel()
+
+	// The SHA-1 test vectors of RFC 6238, truncated to six digits.
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+	for _, tc := range []struct {
+		Time int64
+		Code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	} {
+		code, err := totp.Code(secret, totp.Step(time.Unix(tc.Time, 0)))
+		require.NoError(t, err)
+		require.Equal(t, tc.Code, code, "time %d", tc.Time)
+	}
+
+	_, err := totp.Code("not base32!", 1)
+	require.Error(t, err)
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	secret, err := totp.GenerateSecret()
+	require.NoError(t, err)
+	now := time.Now()
+	step := totp.Step(now)
+	code := func(step int64) string {
+		c, err := totp.Code(secret, step)
+		require.NoError(t, err)
+		return c
+	}
+
+	got, ok := totp.Validate(secret, code(step), now, 0)
+	require.True(t, ok)
+	require.Equal(t, step, got)
+
+	// Clock drift of a single period is allowed.
+	got, ok = totp.Validate(secret, code(step-1), now, 0)
+	require.True(t, ok)
+	require.Equal(t, step-1, got)
+	_, ok = totp.Validate(secret, code(step+1), now, 0)
+	require.True(t, ok)
+	_, ok = totp.Validate(secret, code(step-2), now, 0)
+	require.False(t, ok)
+
+	// Used codes cannot be replayed.
+	_, ok = totp.Validate(secret, code(step), now, step)
+	require.False(t, ok)
+	_, ok = totp.Validate(secret, code(step+1), now, step)
+	require.True(t, ok)
+
+	// Spaces are ignored, as apps often display codes in groups.
+	c := code(step)
+	_, ok = totp.Validate(secret, c[:3]+" "+c[3:], now, 0)
+	require.True(t, ok)
+
+	_, ok = totp.Validate(secret, "", now, 0)
+	require.False(t, ok)
+	_, ok = totp.Validate(secret, "12345", now, 0)
+	require.False(t, ok)
+}
+
+func TestURL(t *testing.T) {
+	t.Parallel()
+
+	u, err := url.Parse(totp.URL("Coder", "alice@example.com", "JBSWY3DPEHPK3PXP"))
+	require.NoError(t, err)
+	require.Equal(t, "otpauth", u.Scheme)
+	require.Equal(t, "totp", u.Host)
+	require.Equal(t, "/Coder:alice@example.com", u.Path)
+	require.Equal(t, "JBSWY3DPEHPK3PXP", u.Query().Get("secret"))
+	require.Equal(t, "Coder", u.Query().Get("issuer"))
+	require.Equal(t, "6", u.Query().Get("digits"))
+	require.Equal(t, "30", u.Query().Get("period"))
+}
@@ -30,6 +30,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/ldap"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/userpassword"
 	"github.com/coder/coder/v2/codersdk"
@@ -88,7 +89,7 @@ func (api *API) postConvertLoginType(rw http.ResponseWriter, r *http.Request) {
 	switch req.ToType {
 	case codersdk.LoginTypeGithub, codersdk.LoginTypeOIDC:
 		// Allowed!
-	case codersdk.LoginTypeNone, codersdk.LoginTypePassword, codersdk.LoginTypeToken:
+	case codersdk.LoginTypeNone, codersdk.LoginTypePassword, codersdk.LoginTypeToken, codersdk.LoginTypeServiceAccount, codersdk.LoginTypeLDAP:
 		// These login types are not allowed to be converted to at this time.
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
 			Message: fmt.Sprintf("Cannot convert to login type %q.", req.ToType),
@@ -221,6 +222,14 @@ func (api *API) postLogin(rw http.ResponseWriter, r *http.Request) {
 	)
 	aReq.Old = database.APIKey{}
 	defer commitAudit()
+	// Only committed when the login completes an MFA enrollment.
+	mfaReq, commitMFAAudit := audit.InitRequest[database.AuditableUserMFA](rw, &audit.RequestParams{
+		Audit:   *auditor,
+		Log:     api.Logger,
+		Request: r,
+		Action:  database.AuditActionWrite,
+	})
+	defer commitMFAAudit()
 
 	var loginWithPassword codersdk.LoginWithPasswordRequest
 	if !httpapi.Read(ctx, rw, r, &loginWithPassword) {
@@ -236,6 +245,11 @@ func (api *API) postLogin(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	recoveryCodes, ok := api.verifyLoginMFA(ctx, rw, mfaReq, user, loginWithPassword.MFACode)
+	if !ok {
+		return
+	}
+
 	userSubj := rbac.Subject{
 		ID:     user.ID.String(),
 		Roles:  rbac.RoleNames(roles.Roles),
@@ -264,7 +278,8 @@ func (api *API) postLogin(rw http.ResponseWriter, r *http.Request) {
 	http.SetCookie(rw, cookie)
 
 	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.LoginWithPasswordResponse{
-		SessionToken: cookie.Value,
+		SessionToken:     cookie.Value,
+		MFARecoveryCodes: recoveryCodes,
 	})
 }
 
@@ -466,6 +481,22 @@ func (api *API) userAuthMethods(rw http.ResponseWriter, r *http.Request) {
 	if api.OIDCConfig != nil {
 		iconURL = api.OIDCConfig.IconURL
 	}
+	oidcProviders := make([]codersdk.OIDCProviderAuthMethod, 0, len(api.OIDCProviders))
+	for _, provider := range api.OIDCProviders {
+		oidcProviders = append(oidcProviders, codersdk.OIDCProviderAuthMethod{
+			ID:         provider.ID,
+			SignInText: provider.SignInText,
+			IconURL:    provider.IconURL,
+		})
+	}
+
+	var ldapAuthMethod codersdk.LDAPAuthMethod
+	if api.LDAPConfig != nil {
+		ldapAuthMethod = codersdk.LDAPAuthMethod{
+			AuthMethod: codersdk.AuthMethod{Enabled: true},
+			SignInText: api.LDAPConfig.SignInText,
+		}
+	}
 
 	httpapi.Write(r.Context(), rw, http.StatusOK, codersdk.AuthMethods{
 		Password: codersdk.AuthMethod{
@@ -477,6 +508,8 @@ func (api *API) userAuthMethods(rw http.ResponseWriter, r *http.Request) {
 			SignInText: signInText,
 			IconURL:    iconURL,
 		},
+		OIDCProviders: oidcProviders,
+		LDAP:          ldapAuthMethod,
 	})
 }
 
@@ -605,7 +638,7 @@ func (api *API) userOAuth2Github(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, link, err := findLinkedUser(ctx, api.Database, githubLinkedID(ghUser), verifiedEmail.GetEmail())
+	user, link, err := findLinkedUser(ctx, api.Database, "", githubLinkedID(ghUser), verifiedEmail.GetEmail())
 	if err != nil {
 		logger.Error(ctx, "oauth2: unable to find linked user", slog.F("gh_user", ghUser.Name), slog.Error(err))
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
@@ -666,6 +699,10 @@ func (api *API) userOAuth2Github(rw http.ResponseWriter, r *http.Request) {
 type OIDCConfig struct {
 	httpmw.OAuth2Config
 
+	// ID identifies an additional OIDC provider in its callback URL and
+	// user links. It is empty for the default provider.
+	ID string
+
 	Provider *oidc.Provider
 	Verifier *oidc.IDTokenVerifier
 	// EmailDomains are the domains to enforce when a user authenticates.
@@ -731,8 +768,19 @@ func (cfg OIDCConfig) RoleSyncEnabled() bool {
 // @Success 307
 // @Router /users/oidc/callback [get]
 func (api *API) userOIDC(rw http.ResponseWriter, r *http.Request) {
+	api.oidcLogin(rw, r, api.OIDCConfig)
+}
+
+// userOIDCProvider handles the callback of an additional OIDC provider.
+func (api *API) userOIDCProvider(cfg *OIDCConfig) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		api.oidcLogin(rw, r, cfg)
+	}
+}
+
+func (api *API) oidcLogin(rw http.ResponseWriter, r *http.Request, cfg *OIDCConfig) {
 	var (
-		// userOIDC is a system function.
+		// oidcLogin is a system function.
 		//nolint:gocritic
 		ctx               = dbauthz.AsSystemRestricted(r.Context())
 		state             = httpmw.OAuth2(r)
@@ -756,7 +804,7 @@ func (api *API) userOIDC(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	idToken, err := api.OIDCConfig.Verifier.Verify(ctx, rawIDToken)
+	idToken, err := cfg.Verifier.Verify(ctx, rawIDToken)
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
 			Message: "Failed to verify OIDC token.",
@@ -797,8 +845,8 @@ func (api *API) userOIDC(rw http.ResponseWriter, r *http.Request) {
 	// Some providers (e.g. ADFS) do not support custom OIDC claims in the
 	// UserInfo endpoint, so we allow users to disable it and only rely on the
 	// ID token.
-	if !api.OIDCConfig.IgnoreUserInfo {
-		userInfo, err := api.OIDCConfig.Provider.UserInfo(ctx, oauth2.StaticTokenSource(state.Token))
+	if !cfg.IgnoreUserInfo {
+		userInfo, err := cfg.Provider.UserInfo(ctx, oauth2.StaticTokenSource(state.Token))
 		if err == nil {
 			userInfoClaims := map[string]interface{}{}
 			err = userInfo.Claims(&userInfoClaims)
@@ -841,13 +889,13 @@ func (api *API) userOIDC(rw http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	usernameRaw, ok := claims[api.OIDCConfig.UsernameField]
+	usernameRaw, ok := claims[cfg.UsernameField]
 	var username string
 	if ok {
 		username, _ = usernameRaw.(string)
 	}
 
-	emailRaw, ok := claims[api.OIDCConfig.EmailField]
+	emailRaw, ok := claims[cfg.EmailField]
 	if !ok {
 		// Email is an optional claim in OIDC and
 		// instead the email is frequently sent in
@@ -871,11 +919,13 @@ func (api *API) userOIDC(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var emailVerified bool
 	verifiedRaw, ok := claims["email_verified"]
 	if ok {
 		verified, ok := verifiedRaw.(bool)
+		emailVerified = ok && verified
 		if ok && !verified {
-			if !api.OIDCConfig.IgnoreEmailVerified {
+			if !cfg.IgnoreEmailVerified {
 				httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
 					Message: fmt.Sprintf("Verify the %q email address on your OIDC provider to authenticate!", email),
 				})
@@ -889,10 +939,10 @@ func (api *API) userOIDC(rw http.ResponseWriter, r *http.Request) {
 	var groups []string
 	// If the GroupField is the empty string, then groups from OIDC are not used.
 	// This is so we can support manual group assignment.
-	if api.OIDCConfig.GroupField != "" {
+	if cfg.GroupField != "" {
 		usingGroups = true
-		groupsRaw, ok := claims[api.OIDCConfig.GroupField]
-		if ok && api.OIDCConfig.GroupField != "" {
+		groupsRaw, ok := claims[cfg.GroupField]
+		if ok && cfg.GroupField != "" {
 			// Convert the []interface{} we get to a []string.
 			groupsInterface, ok := groupsRaw.([]interface{})
 			if ok {
@@ -910,7 +960,7 @@ func (api *API) userOIDC(rw http.ResponseWriter, r *http.Request) {
 						return
 					}
 
-					if mappedGroup, ok := api.OIDCConfig.GroupMapping[group]; ok {
+					if mappedGroup, ok := cfg.GroupMapping[group]; ok {
 						group = mappedGroup
 					}
 
@@ -944,9 +994,9 @@ func (api *API) userOIDC(rw http.ResponseWriter, r *http.Request) {
 		username = httpapi.UsernameFrom(username)
 	}
 
-	if len(api.OIDCConfig.EmailDomain) > 0 {
+	if len(cfg.EmailDomain) > 0 {
 		ok = false
-		for _, domain := range api.OIDCConfig.EmailDomain {
+		for _, domain := range cfg.EmailDomain {
 			if strings.HasSuffix(strings.ToLower(email), strings.ToLower(domain)) {
 				ok = true
 				break
@@ -954,7 +1004,7 @@ func (api *API) userOIDC(rw http.ResponseWriter, r *http.Request) {
 		}
 		if !ok {
 			httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
-				Message: fmt.Sprintf("Your email %q is not in domains %q !", email, api.OIDCConfig.EmailDomain),
+				Message: fmt.Sprintf("Your email %q is not in domains %q !", email, cfg.EmailDomain),
 			})
 			return
 		}
@@ -966,7 +1016,7 @@ func (api *API) userOIDC(rw http.ResponseWriter, r *http.Request) {
 		picture, _ = pictureRaw.(string)
 	}
 
-	user, link, err := findLinkedUser(ctx, api.Database, oidcLinkedID(idToken), email)
+	user, link, err := findLinkedUser(ctx, api.Database, cfg.ID, oidcLinkedID(idToken), email)
 	if err != nil {
 		logger.Error(ctx, "oauth2: unable to find linked user", slog.F("email", email), slog.Error(err))
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
@@ -976,9 +1026,43 @@ func (api *API) userOIDC(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	roles := api.OIDCConfig.UserRolesDefault
-	if api.OIDCConfig.RoleSyncEnabled() {
-		rolesRow, ok := claims[api.OIDCConfig.UserRoleField]
+	// Any of the configured providers can assert any email, so an account
+	// is only linked by email to an additional provider, or to the default
+	// provider when it already signs in with another one, if this provider
+	// verified the address and is limited to the deployment's email domains.
+	// Accounts without any link, e.g. created by SCIM or the CLI, keep
+	// linking to the default provider by email.
+	linkedByEmail := user.ID != uuid.Nil && link.LinkedID != oidcLinkedID(idToken)
+	if linkedByEmail && user.LoginType == database.LoginTypeOIDC {
+		otherProvider := cfg.ID != ""
+		if !otherProvider && link.UserID == uuid.Nil {
+			links, err := api.Database.GetUserLinksByUserID(ctx, user.ID)
+			if err != nil {
+				logger.Error(ctx, "oauth2: unable to get user links", slog.F("user_id", user.ID), slog.Error(err))
+				httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+					Message: "Failed to get user links.",
+					Detail:  err.Error(),
+				})
+				return
+			}
+			for _, l := range links {
+				if l.ProviderID != cfg.ID {
+					otherProvider = true
+					break
+				}
+			}
+		}
+		if otherProvider && (!emailVerified || len(cfg.EmailDomain) == 0) {
+			httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+				Message: fmt.Sprintf("An account with the email %q already exists. Sign in with the OIDC provider it was created with.", email),
+			})
+			return
+		}
+	}
+
+	roles := cfg.UserRolesDefault
+	if cfg.RoleSyncEnabled() {
+		rolesRow, ok := claims[cfg.UserRoleField]
 		if !ok {
 			// If no claim is provided than we can assume the user is just
 			// a member. This is because there is no way to tell the difference
@@ -1020,7 +1104,7 @@ func (api *API) userOIDC(rw http.ResponseWriter, r *http.Request) {
 				return
 			}
 
-			if mappedRoles, ok := api.OIDCConfig.UserRoleMapping[role]; ok {
+			if mappedRoles, ok := cfg.UserRoleMapping[role]; ok {
 				if len(mappedRoles) == 0 {
 					continue
 				}
@@ -1045,16 +1129,17 @@ func (api *API) userOIDC(rw http.ResponseWriter, r *http.Request) {
 		State:               state,
 		LinkedID:            oidcLinkedID(idToken),
 		LoginType:           database.LoginTypeOIDC,
-		AllowSignups:        api.OIDCConfig.AllowSignups,
+		ProviderID:          cfg.ID,
+		AllowSignups:        cfg.AllowSignups,
 		Email:               email,
 		Username:            username,
 		AvatarURL:           picture,
 		UsingGroups:         usingGroups,
-		UsingRoles:          api.OIDCConfig.RoleSyncEnabled(),
+		UsingRoles:          cfg.RoleSyncEnabled(),
 		Roles:               roles,
 		Groups:              groups,
-		CreateMissingGroups: api.OIDCConfig.CreateMissingGroups,
-		GroupFilter:         api.OIDCConfig.GroupFilter,
+		CreateMissingGroups: cfg.CreateMissingGroups,
+		GroupFilter:         cfg.GroupFilter,
 	}).SetInitAuditRequest(func(params *audit.RequestParams) (*audit.Request[database.User], func()) {
 		return audit.InitRequest[database.User](rw, params)
 	})
@@ -1123,12 +1208,214 @@ func mergeClaims(a, b map[string]interface{}) map[string]interface{} {
 	return c
 }
 
+type LDAPConfig struct {
+	ldap.Config
+
+	AllowSignups bool
+	// GroupMapping controls how groups in the directory get mapped to groups
+	// within Coder.
+	// map[ldapGroupName]coderGroupName
+	GroupMapping map[string]string
+	// CreateMissingGroups controls whether groups in the directory are
+	// automatically created in Coder if they are missing.
+	CreateMissingGroups bool
+	// UserRoleMapping controls which roles members of groups in the directory
+	// get within Coder.
+	// map[ldapGroupName][]coderRoleName
+	UserRoleMapping map[string][]string
+	// UserRolesDefault is the default set of roles to assign to a user if role sync
+	// is enabled.
+	UserRolesDefault []string
+	// SyncInterval is how often users are synced with the directory. Users
+	// are only synced when they log in if it is zero.
+	SyncInterval time.Duration
+	// SignInText is the text to display on the LDAP login form.
+	SignInText string
+}
+
+func (cfg LDAPConfig) GroupSyncEnabled() bool {
+	return cfg.GroupAttribute != ""
+}
+
+func (cfg LDAPConfig) RoleSyncEnabled() bool {
+	return cfg.GroupSyncEnabled() && len(cfg.UserRoleMapping) > 0
+}
+
+// mapGroups returns the Coder groups and roles of the directory groups.
+func (cfg LDAPConfig) mapGroups(ldapGroups []string) (groups []string, roles []string) {
+	groups = make([]string, 0, len(ldapGroups))
+	roles = append([]string{}, cfg.UserRolesDefault...)
+	for _, group := range ldapGroups {
+		roles = append(roles, cfg.UserRoleMapping[group]...)
+		if mappedGroup, ok := cfg.GroupMapping[group]; ok {
+			group = mappedGroup
+		}
+		groups = append(groups, group)
+	}
+	return groups, roles
+}
+
+// Authenticates the user with the username and password of their account in
+// the LDAP directory.
+//
+// @Summary Log in user with LDAP
+// @ID log-in-user-with-ldap
+// @Accept json
+// @Produce json
+// @Tags Authorization
+// @Param request body codersdk.LoginWithLDAPRequest true "Login request"
+// @Success 201 {object} codersdk.LoginWithPasswordResponse
+// @Router /users/ldap/login [post]
+func (api *API) postLDAPLogin(rw http.ResponseWriter, r *http.Request) {
+	var (
+		// postLDAPLogin is a system function.
+		//nolint:gocritic
+		ctx               = dbauthz.AsSystemRestricted(r.Context())
+		auditor           = api.Auditor.Load()
+		logger            = api.Logger.Named(userAuthLoggerName)
+		aReq, commitAudit = audit.InitRequest[database.APIKey](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionLogin,
+		})
+	)
+	aReq.Old = database.APIKey{}
+	defer commitAudit()
+
+	cfg := api.LDAPConfig
+	if cfg == nil {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: "LDAP authentication is not enabled.",
+		})
+		return
+	}
+
+	var req codersdk.LoginWithLDAPRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	entry, err := cfg.Authenticate(ctx, req.Username, req.Password)
+	if err != nil {
+		if errors.Is(err, ldap.ErrInvalidCredentials) {
+			httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+				Message: "Incorrect username or password.",
+			})
+			return
+		}
+		logger.Error(ctx, "ldap: unable to authenticate user", slog.F("username", req.Username), slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to authenticate with LDAP.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if entry.Email == "" {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Your directory entry has no %q attribute with an email.", cfg.EmailAttribute),
+		})
+		return
+	}
+
+	username := entry.Username
+	if httpapi.NameValid(username) != nil {
+		username = httpapi.UsernameFrom(username)
+	}
+
+	user, link, err := findLinkedUser(ctx, api.Database, "", entry.DN, entry.Email)
+	if err != nil {
+		logger.Error(ctx, "ldap: unable to find linked user", slog.F("email", entry.Email), slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to find linked user.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if user.Status == database.UserStatusSuspended {
+		httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+			Message: "Your account is suspended. Contact an admin to reactivate your account.",
+		})
+		return
+	}
+	// The DN changes when the user is moved or renamed in the directory.
+	if link.UserID != uuid.Nil && link.LinkedID != entry.DN {
+		link, err = api.Database.UpdateUserLinkedID(ctx, database.UpdateUserLinkedIDParams{
+			UserID:     link.UserID,
+			LoginType:  link.LoginType,
+			ProviderID: link.ProviderID,
+			LinkedID:   entry.DN,
+		})
+		if err != nil {
+			logger.Error(ctx, "ldap: unable to update linked id", slog.F("user_id", user.ID), slog.Error(err))
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error.",
+			})
+			return
+		}
+	}
+
+	groups, roles := cfg.mapGroups(entry.Groups)
+	params := (&oauthLoginParams{
+		User: user,
+		Link: link,
+		// There are no OAuth tokens to store in the link.
+		State:               httpmw.OAuth2State{Token: &oauth2.Token{}},
+		LinkedID:            entry.DN,
+		LoginType:           database.LoginTypeLDAP,
+		AllowSignups:        cfg.AllowSignups,
+		Email:               entry.Email,
+		Username:            username,
+		UsingGroups:         cfg.GroupSyncEnabled(),
+		CreateMissingGroups: cfg.CreateMissingGroups,
+		Groups:              groups,
+		UsingRoles:          cfg.RoleSyncEnabled(),
+		Roles:               roles,
+	}).SetInitAuditRequest(func(params *audit.RequestParams) (*audit.Request[database.User], func()) {
+		return audit.InitRequest[database.User](rw, params)
+	})
+	cookies, key, err := api.oauthLogin(r, params)
+	defer params.CommitAuditLogs()
+	var httpErr httpError
+	if xerrors.As(err, &httpErr) {
+		// The login form renders JSON errors itself.
+		httpErr.renderStaticPage = false
+		httpErr.Write(rw, r)
+		return
+	}
+	if err != nil {
+		logger.Error(ctx, "ldap: login failed", slog.F("user", user.Username), slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to process LDAP login.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = key
+	aReq.UserID = key.UserID
+
+	var sessionToken string
+	for _, cookie := range cookies {
+		if cookie.Name == codersdk.SessionTokenCookie {
+			sessionToken = cookie.Value
+		}
+		http.SetCookie(rw, cookie)
+	}
+
+	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.LoginWithPasswordResponse{
+		SessionToken: sessionToken,
+	})
+}
+
 type oauthLoginParams struct {
 	User      database.User
 	Link      database.UserLink
 	State     httpmw.OAuth2State
 	LinkedID  string
 	LoginType database.LoginType
+	// ProviderID is the ID of the additional OIDC provider the user
+	// authenticated with. Empty for the default provider.
+	ProviderID string
 
 	// The following are necessary in order to
 	// create new users.
@@ -1331,6 +1618,7 @@ func (api *API) oauthLogin(r *http.Request, params *oauthLoginParams) ([]*http.C
 			link, err = tx.InsertUserLink(dbauthz.AsSystemRestricted(ctx), database.InsertUserLinkParams{
 				UserID:            user.ID,
 				LoginType:         params.LoginType,
+				ProviderID:        params.ProviderID,
 				LinkedID:          params.LinkedID,
 				OAuthAccessToken:  params.State.Token.AccessToken,
 				OAuthRefreshToken: params.State.Token.RefreshToken,
@@ -1346,6 +1634,7 @@ func (api *API) oauthLogin(r *http.Request, params *oauthLoginParams) ([]*http.C
 			link, err = tx.UpdateUserLink(dbauthz.AsSystemRestricted(ctx), database.UpdateUserLinkParams{
 				UserID:            user.ID,
 				LoginType:         params.LoginType,
+				ProviderID:        params.ProviderID,
 				OAuthAccessToken:  params.State.Token.AccessToken,
 				OAuthRefreshToken: params.State.Token.RefreshToken,
 				OAuthExpiry:       params.State.Token.Expiry,
@@ -1481,6 +1770,7 @@ func (api *API) oauthLogin(r *http.Request, params *oauthLoginParams) ([]*http.C
 		cookie, newKey, err := api.createAPIKey(dbauthz.AsSystemRestricted(ctx), apikey.CreateParams{
 			UserID:           user.ID,
 			LoginType:        params.LoginType,
+			ProviderID:       params.ProviderID,
 			DeploymentValues: api.DeploymentValues,
 			RemoteAddr:       r.RemoteAddr,
 		})
@@ -1607,12 +1897,17 @@ func oidcLinkedID(tok *oidc.IDToken) string {
 
 // findLinkedUser tries to find a user by their unique OAuth-linked ID.
 // If it doesn't not find it, it returns the user by their email.
-func findLinkedUser(ctx context.Context, db database.Store, linkedID string, emails ...string) (database.User, database.UserLink, error) {
+func findLinkedUser(ctx context.Context, db database.Store, providerID string, linkedID string, emails ...string) (database.User, database.UserLink, error) {
 	var (
 		user database.User
 		link database.UserLink
 	)
-	link, err := db.GetUserLinkByLinkedID(ctx, linkedID)
+	// Several OIDC providers can share an issuer, so the linked ID is only
+	// unique per provider.
+	link, err := db.GetUserLinkByLinkedID(ctx, database.GetUserLinkByLinkedIDParams{
+		LinkedID:   linkedID,
+		ProviderID: providerID,
+	})
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		return user, link, xerrors.Errorf("get user auth by linked ID: %w", err)
 	}
@@ -1651,8 +1946,9 @@ func findLinkedUser(ctx context.Context, db database.Store, linkedID string, ema
 	// again except this time we search by user_id and login_type. It's
 	// possible that a user_link exists without a populated 'linked_id'.
 	link, err = db.GetUserLinkByUserIDLoginType(ctx, database.GetUserLinkByUserIDLoginTypeParams{
-		UserID:    user.ID,
-		LoginType: user.LoginType,
+		UserID:     user.ID,
+		LoginType:  user.LoginType,
+		ProviderID: providerID,
 	})
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		return database.User{}, database.UserLink{}, xerrors.Errorf("get user link by user id and login type: %w", err)
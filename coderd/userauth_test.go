@@ -23,8 +23,11 @@ import (
 	"github.com/coder/coder/v2/coderd/coderdtest"
 	"github.com/coder/coder/v2/coderd/coderdtest/oidctest"
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbgen"
 	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/ldap"
+	"github.com/coder/coder/v2/coderd/ldap/ldaptest"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/testutil"
 )
@@ -1004,6 +1007,367 @@ func TestUserOIDC(t *testing.T) {
 	})
 }
 
+// nolint:bodyclose
+func TestUserOIDCProviders(t *testing.T) {
+	t.Parallel()
+
+	defaultIDP := oidctest.NewFakeIDP(t, oidctest.WithServing())
+	defaultCfg := defaultIDP.OIDCConfig(t, nil, func(cfg *coderd.OIDCConfig) {
+		cfg.AllowSignups = true
+	})
+	oktaIDP := oidctest.NewFakeIDP(t, oidctest.WithServing())
+	oktaCfg := oktaIDP.OIDCConfig(t, nil, func(cfg *coderd.OIDCConfig) {
+		cfg.ID = "okta"
+		cfg.SignInText = "Sign in with Okta"
+		cfg.UsernameField = "login"
+		cfg.EmailDomain = []string{"coder.com"}
+	})
+	// Any provider can assert any email, so only providers limited to the
+	// deployment's email domains link to existing accounts.
+	rogueIDP := oidctest.NewFakeIDP(t, oidctest.WithServing())
+	rogueCfg := rogueIDP.OIDCConfig(t, nil, func(cfg *coderd.OIDCConfig) {
+		cfg.ID = "rogue"
+		cfg.AllowSignups = true
+	})
+
+	client, _, api := coderdtest.NewWithAPI(t, &coderdtest.Options{
+		OIDCConfig:    defaultCfg,
+		OIDCProviders: []*coderd.OIDCConfig{oktaCfg, rogueCfg},
+	})
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	methods, err := client.AuthMethods(ctx)
+	require.NoError(t, err)
+	require.True(t, methods.OIDC.Enabled)
+	require.Equal(t, []codersdk.OIDCProviderAuthMethod{{
+		ID:         "okta",
+		SignInText: "Sign in with Okta",
+	}, {
+		ID: "rogue",
+	}}, methods.OIDCProviders)
+
+	// Signups are disabled for the provider.
+	_, resp := oktaIDP.AttemptLogin(t, client, jwt.MapClaims{
+		"email": "alice@coder.com",
+		"sub":   "okta-alice",
+		"login": "alice",
+	})
+	require.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	defaultClient, _ := defaultIDP.Login(t, client, jwt.MapClaims{
+		"email":              "alice@coder.com",
+		"sub":                "default-alice",
+		"preferred_username": "alice",
+	})
+	defaultUser, err := defaultClient.User(ctx, codersdk.Me)
+	require.NoError(t, err)
+
+	// The existing user is only linked by email if the provider verified
+	// it.
+	_, resp = oktaIDP.AttemptLogin(t, client, jwt.MapClaims{
+		"email": "alice@coder.com",
+		"sub":   "okta-alice",
+		"login": "alice",
+	})
+	require.Equal(t, http.StatusForbidden, resp.StatusCode)
+	_, resp = rogueIDP.AttemptLogin(t, client, jwt.MapClaims{
+		"email":          "alice@coder.com",
+		"email_verified": true,
+		"sub":            "rogue-alice",
+	})
+	require.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	oktaClient, _ := oktaIDP.Login(t, client, jwt.MapClaims{
+		"email":          "alice@coder.com",
+		"email_verified": true,
+		"sub":            "okta-alice",
+		"login":          "alice",
+	})
+	oktaUser, err := oktaClient.User(ctx, codersdk.Me)
+	require.NoError(t, err)
+	require.Equal(t, defaultUser.ID, oktaUser.ID)
+
+	//nolint:gocritic // Testing
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+	for _, providerID := range []string{"", "okta"} {
+		link, err := api.Database.GetUserLinkByUserIDLoginType(sysCtx, database.GetUserLinkByUserIDLoginTypeParams{
+			UserID:     oktaUser.ID,
+			LoginType:  database.LoginTypeOIDC,
+			ProviderID: providerID,
+		})
+		require.NoError(t, err)
+		require.Equal(t, providerID, link.ProviderID)
+	}
+
+	// The token of the provider is refreshed with the provider.
+	oidctest.NewLoginHelper(client, oktaIDP).ForceRefresh(t, api.Database, oktaClient, jwt.MapClaims{
+		"email":          "alice@coder.com",
+		"email_verified": true,
+		"sub":            "okta-alice",
+		"login":          "alice",
+	})
+}
+
+func TestUserOIDCProvidersExistingUser(t *testing.T) {
+	t.Parallel()
+
+	defaultIDP := oidctest.NewFakeIDP(t, oidctest.WithServing())
+	defaultCfg := defaultIDP.OIDCConfig(t, nil)
+	oktaIDP := oidctest.NewFakeIDP(t, oidctest.WithServing())
+	oktaCfg := oktaIDP.OIDCConfig(t, nil, func(cfg *coderd.OIDCConfig) {
+		cfg.ID = "okta"
+		cfg.EmailDomain = []string{"coder.com"}
+	})
+
+	client := coderdtest.New(t, &coderdtest.Options{
+		OIDCConfig:    defaultCfg,
+		OIDCProviders: []*coderd.OIDCConfig{oktaCfg},
+	})
+	owner := coderdtest.CreateFirstUser(t, client)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	// Users created by SCIM or the CLI have no link yet, and are linked to
+	// the default provider by email.
+	alice, err := client.CreateUser(ctx, codersdk.CreateUserRequest{
+		Email:          "alice@coder.com",
+		Username:       "alice",
+		UserLoginType:  codersdk.LoginTypeOIDC,
+		OrganizationID: owner.OrganizationID,
+	})
+	require.NoError(t, err)
+
+	aliceClient, _ := defaultIDP.Login(t, client, jwt.MapClaims{
+		"email": "alice@coder.com",
+		"sub":   "default-alice",
+	})
+	aliceUser, err := aliceClient.User(ctx, codersdk.Me)
+	require.NoError(t, err)
+	require.Equal(t, alice.ID, aliceUser.ID)
+
+	// Once the user signs in with another provider, the default provider
+	// must verify the email to link to the account.
+	bob, err := client.CreateUser(ctx, codersdk.CreateUserRequest{
+		Email:          "bob@coder.com",
+		Username:       "bob",
+		UserLoginType:  codersdk.LoginTypeOIDC,
+		OrganizationID: owner.OrganizationID,
+	})
+	require.NoError(t, err)
+
+	bobClient, _ := oktaIDP.Login(t, client, jwt.MapClaims{
+		"email":          "bob@coder.com",
+		"email_verified": true,
+		"sub":            "okta-bob",
+	})
+	bobUser, err := bobClient.User(ctx, codersdk.Me)
+	require.NoError(t, err)
+	require.Equal(t, bob.ID, bobUser.ID)
+
+	_, resp := defaultIDP.AttemptLogin(t, client, jwt.MapClaims{
+		"email": "bob@coder.com",
+		"sub":   "default-bob",
+	})
+	require.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
+
+const (
+	ldapBindDN  = "CN=coder,OU=Services,DC=example,DC=com"
+	ldapAliceDN = "CN=Alice,OU=Users,DC=example,DC=com"
+)
+
+func setupLDAP(t *testing.T) (*ldaptest.Server, *coderd.LDAPConfig) {
+	t.Helper()
+
+	srv := ldaptest.New(t, nil)
+	srv.AddEntry(ldapBindDN, "objectClass", "person")
+	srv.SetPassword(ldapBindDN, "service-password")
+	srv.AddEntry(ldapAliceDN,
+		"objectClass", "person",
+		"sAMAccountName", "alice",
+		"mail", "alice@example.com",
+	)
+	srv.SetPassword(ldapAliceDN, "alice-password")
+
+	return srv, &coderd.LDAPConfig{
+		Config: ldap.Config{
+			URL:               srv.URL,
+			StartTLS:          true,
+			TLSConfig:         srv.TLSConfig,
+			BindDN:            ldapBindDN,
+			BindPassword:      "service-password",
+			BaseDN:            "DC=example,DC=com",
+			UserFilter:        "(objectClass=person)",
+			UsernameAttribute: "sAMAccountName",
+			EmailAttribute:    "mail",
+		},
+		AllowSignups: true,
+		SignInText:   "Active Directory",
+	}
+}
+
+func TestUserLDAP(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		_, cfg := setupLDAP(t)
+		client := coderdtest.New(t, &coderdtest.Options{LDAPConfig: cfg})
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		methods, err := client.AuthMethods(ctx)
+		require.NoError(t, err)
+		require.True(t, methods.LDAP.Enabled)
+		require.Equal(t, "Active Directory", methods.LDAP.SignInText)
+
+		ldapClient := codersdk.New(client.URL)
+		res, err := ldapClient.LoginWithLDAP(ctx, codersdk.LoginWithLDAPRequest{
+			Username: "alice",
+			Password: "alice-password",
+		})
+		require.NoError(t, err)
+		ldapClient.SetSessionToken(res.SessionToken)
+
+		user, err := ldapClient.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Equal(t, "alice", user.Username)
+		require.Equal(t, "alice@example.com", user.Email)
+		require.Equal(t, codersdk.LoginTypeLDAP, user.LoginType)
+
+		// Logging in again uses the same account.
+		res, err = ldapClient.LoginWithLDAP(ctx, codersdk.LoginWithLDAPRequest{
+			Username: "alice",
+			Password: "alice-password",
+		})
+		require.NoError(t, err)
+		ldapClient.SetSessionToken(res.SessionToken)
+		again, err := ldapClient.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Equal(t, user.ID, again.ID)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := codersdk.New(client.URL).LoginWithLDAP(ctx, codersdk.LoginWithLDAPRequest{
+			Username: "alice",
+			Password: "alice-password",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("WrongPassword", func(t *testing.T) {
+		t.Parallel()
+
+		_, cfg := setupLDAP(t)
+		client := coderdtest.New(t, &coderdtest.Options{LDAPConfig: cfg})
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := codersdk.New(client.URL).LoginWithLDAP(ctx, codersdk.LoginWithLDAPRequest{
+			Username: "alice",
+			Password: "wrong",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+	})
+
+	t.Run("SignupsDisabled", func(t *testing.T) {
+		t.Parallel()
+
+		_, cfg := setupLDAP(t)
+		cfg.AllowSignups = false
+		client := coderdtest.New(t, &coderdtest.Options{LDAPConfig: cfg})
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := codersdk.New(client.URL).LoginWithLDAP(ctx, codersdk.LoginWithLDAPRequest{
+			Username: "alice",
+			Password: "alice-password",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+
+	t.Run("EntryMoved", func(t *testing.T) {
+		t.Parallel()
+
+		srv, cfg := setupLDAP(t)
+		client := coderdtest.New(t, &coderdtest.Options{LDAPConfig: cfg})
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		ldapClient := codersdk.New(client.URL)
+		res, err := ldapClient.LoginWithLDAP(ctx, codersdk.LoginWithLDAPRequest{
+			Username: "alice",
+			Password: "alice-password",
+		})
+		require.NoError(t, err)
+		ldapClient.SetSessionToken(res.SessionToken)
+		user, err := ldapClient.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+
+		// The account is found by email and linked to the new DN.
+		movedDN := "CN=Alice,OU=Engineering,DC=example,DC=com"
+		srv.DeleteEntry(ldapAliceDN)
+		srv.AddEntry(movedDN,
+			"objectClass", "person",
+			"sAMAccountName", "alice",
+			"mail", "alice@example.com",
+		)
+		srv.SetPassword(movedDN, "alice-password")
+
+		res, err = ldapClient.LoginWithLDAP(ctx, codersdk.LoginWithLDAPRequest{
+			Username: "alice",
+			Password: "alice-password",
+		})
+		require.NoError(t, err)
+		ldapClient.SetSessionToken(res.SessionToken)
+		moved, err := ldapClient.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Equal(t, user.ID, moved.ID)
+	})
+
+	t.Run("Suspended", func(t *testing.T) {
+		t.Parallel()
+
+		_, cfg := setupLDAP(t)
+		client := coderdtest.New(t, &coderdtest.Options{LDAPConfig: cfg})
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		ldapClient := codersdk.New(client.URL)
+		res, err := ldapClient.LoginWithLDAP(ctx, codersdk.LoginWithLDAPRequest{
+			Username: "alice",
+			Password: "alice-password",
+		})
+		require.NoError(t, err)
+		ldapClient.SetSessionToken(res.SessionToken)
+		user, err := ldapClient.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+
+		_, err = client.UpdateUserStatus(ctx, user.ID.String(), codersdk.UserStatusSuspended)
+		require.NoError(t, err)
+
+		_, err = ldapClient.LoginWithLDAP(ctx, codersdk.LoginWithLDAPRequest{
+			Username: "alice",
+			Password: "alice-password",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+	})
+}
+
 func TestUserLogout(t *testing.T) {
 	t.Parallel()
 
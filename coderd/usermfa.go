@@ -0,0 +1,715 @@
+package coderd
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/totp"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/cryptorand"
+)
+
+const (
+	// mfaRecoveryCodeCount is the number of recovery codes created when an
+	// enrollment is verified.
+	mfaRecoveryCodeCount = 10
+	// mfaTOTPIssuer is the name authenticator apps display for the account.
+	mfaTOTPIssuer = "Coder"
+	// mfaMaxFailedAttempts is the number of invalid codes after which codes
+	// are rejected for mfaLockoutDuration, so they cannot be guessed.
+	mfaMaxFailedAttempts = 5
+	mfaLockoutDuration   = 15 * time.Minute
+)
+
+// errMFALockedOut is returned by checkMFACode when too many invalid codes
+// were entered.
+var errMFALockedOut = xerrors.New("too many invalid codes")
+
+// @Summary Get user MFA status
+// @ID get-user-mfa-status
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Success 200 {object} codersdk.UserMFA
+// @Router /users/{user}/mfa [get]
+func (api *API) userMFA(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := httpmw.UserParam(r)
+
+	status := codersdk.UserMFA{
+		Required: api.DeploymentValues.RequireMFA.Value(),
+	}
+	mfa, err := api.Database.GetUserMFAByUserID(ctx, user.ID)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching user's MFA enrollment.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if err == nil && mfa.EnabledAt.Valid {
+		codes, err := api.Database.GetUserMFARecoveryCodesByUserID(ctx, user.ID)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching user's MFA recovery codes.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		status.Enabled = true
+		status.EnabledAt = &mfa.EnabledAt.Time
+		for _, code := range codes {
+			if !code.UsedAt.Valid {
+				status.RecoveryCodesRemaining++
+			}
+		}
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, status)
+}
+
+// @Summary Enroll user TOTP authenticator
+// @Description Starts the enrollment of an authenticator app, replacing a previous pending enrollment. The enrollment must be verified with a code from the app before it is enabled.
+// @ID enroll-user-totp-authenticator
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Success 201 {object} codersdk.TOTPEnrollment
+// @Router /users/{user}/mfa/totp [post]
+func (api *API) postUserTOTP(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		user              = httpmw.UserParam(r)
+		apiKey            = httpmw.APIKey(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.AuditableUserMFA](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionCreate,
+		})
+	)
+	defer commitAudit()
+
+	// Enrolling an authenticator for someone else would let them log in
+	// as that user.
+	if apiKey.UserID != user.ID {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "Users can only enroll their own authenticator.",
+		})
+		return
+	}
+
+	mfa, enrollment, ok := api.beginTOTPEnrollment(ctx, rw, user)
+	if !ok {
+		return
+	}
+	aReq.New = mfa.Auditable(user.Username)
+
+	httpapi.Write(ctx, rw, http.StatusCreated, enrollment)
+}
+
+// @Summary Verify user TOTP authenticator
+// @Description Enables the pending enrollment of an authenticator app and returns new recovery codes, which are only shown once.
+// @ID verify-user-totp-authenticator
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Param request body codersdk.VerifyTOTPRequest true "Verify request"
+// @Success 200 {object} codersdk.MFARecoveryCodes
+// @Router /users/{user}/mfa/totp/verify [post]
+func (api *API) postUserTOTPVerify(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		user              = httpmw.UserParam(r)
+		apiKey            = httpmw.APIKey(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.AuditableUserMFA](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionWrite,
+		})
+	)
+	defer commitAudit()
+
+	var req codersdk.VerifyTOTPRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	if apiKey.UserID != user.ID {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "Users can only enroll their own authenticator.",
+		})
+		return
+	}
+
+	mfa, err := api.Database.GetUserMFAByUserID(ctx, user.ID)
+	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching user's MFA enrollment.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if err != nil || mfa.EnabledAt.Valid {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "There is no pending authenticator enrollment to verify.",
+		})
+		return
+	}
+	aReq.Old = mfa.Auditable(user.Username)
+
+	step, valid := totp.Validate(mfa.TOTPSecret, req.Code, dbtime.Now(), 0)
+	if !valid {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid code.",
+			Validations: []codersdk.ValidationError{{
+				Field:  "code",
+				Detail: "The code does not match the authenticator, or it expired.",
+			}},
+		})
+		return
+	}
+
+	// Other sessions may have been created by someone who knows the
+	// password, which is what MFA protects against.
+	enabled, recoveryCodes, err := api.enableMFA(ctx, mfa, step, apiKey.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error enabling MFA.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = enabled.Auditable(user.Username)
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.MFARecoveryCodes{
+		RecoveryCodes: recoveryCodes,
+	})
+}
+
+// @Summary Reset user MFA
+// @Description Removes the authenticator and recovery codes of the user. Users can remove their own with a current code unless the deployment requires MFA.
+// @ID reset-user-mfa
+// @Security CoderSessionToken
+// @Accept json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Param request body codersdk.ResetUserMFARequest false "Reset request"
+// @Success 204
+// @Router /users/{user}/mfa [delete]
+func (api *API) deleteUserMFA(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		user              = httpmw.UserParam(r)
+		apiKey            = httpmw.APIKey(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.AuditableUserMFA](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionDelete,
+		})
+	)
+	defer commitAudit()
+
+	var req codersdk.ResetUserMFARequest
+	if r.ContentLength != 0 && !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	self := apiKey.UserID == user.ID
+	if self {
+		if api.DeploymentValues.RequireMFA.Value() {
+			httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+				Message: "Multi-factor authentication is required by the deployment. Ask an admin to reset it if you lost access to your authenticator.",
+			})
+			return
+		}
+	} else {
+		// Resetting is an admin action on the user, but the enrollment is
+		// user data, which user admins cannot modify otherwise.
+		if !api.Authorize(r, rbac.ActionUpdate, user) {
+			httpapi.ResourceNotFound(rw)
+			return
+		}
+		//nolint:gocritic // Authorized above.
+		ctx = dbauthz.AsSystemRestricted(ctx)
+	}
+
+	mfa, err := api.Database.GetUserMFAByUserID(ctx, user.ID)
+	if xerrors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "User has not enrolled in multi-factor authentication.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching user's MFA enrollment.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.Old = mfa.Auditable(user.Username)
+
+	// A stolen session must not be enough to remove the second factor.
+	// Pending enrollments do not protect the account yet.
+	if self && mfa.EnabledAt.Valid {
+		if req.Code == "" {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "A multi-factor authentication code is required to remove your authenticator.",
+				Validations: []codersdk.ValidationError{{
+					Field:  "code",
+					Detail: "Enter a current code from your authenticator app, or a recovery code.",
+				}},
+			})
+			return
+		}
+		valid, err := api.checkMFACode(ctx, mfa, req.Code)
+		if errors.Is(err, errMFALockedOut) {
+			writeMFALockedOut(ctx, rw)
+			return
+		}
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error checking MFA code.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		if !valid {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Invalid multi-factor authentication code.",
+				Validations: []codersdk.ValidationError{{
+					Field:  "code",
+					Detail: "Enter a current code from your authenticator app, or a recovery code.",
+				}},
+			})
+			return
+		}
+	}
+
+	// Recovery codes are deleted with the enrollment.
+	err = api.Database.DeleteUserMFAByUserID(ctx, user.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error resetting MFA.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusNoContent, nil)
+}
+
+// Starts the enrollment of an authenticator app for a user that cannot log
+// in before they enroll, because the deployment requires MFA. The enrollment
+// is completed by logging in with a code from the app.
+//
+// @Summary Enroll TOTP authenticator with password
+// @ID enroll-totp-authenticator-with-password
+// @Accept json
+// @Produce json
+// @Tags Authorization
+// @Param request body codersdk.LoginWithPasswordRequest true "Login request"
+// @Success 201 {object} codersdk.TOTPEnrollment
+// @Router /users/login/mfa/enroll [post]
+func (api *API) postLoginMFAEnroll(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.AuditableUserMFA](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionCreate,
+		})
+	)
+	defer commitAudit()
+
+	var req codersdk.LoginWithPasswordRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	user, _, ok := api.loginRequest(ctx, rw, req)
+	aReq.UserID = user.ID
+	if !ok {
+		return
+	}
+
+	if !api.DeploymentValues.RequireMFA.Value() {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Multi-factor authentication is not required. Log in to enroll an authenticator from your account settings.",
+		})
+		return
+	}
+
+	//nolint:gocritic // The user is not logged in yet.
+	mfa, enrollment, ok := api.beginTOTPEnrollment(dbauthz.AsSystemRestricted(ctx), rw, user)
+	if !ok {
+		return
+	}
+	aReq.New = mfa.Auditable(user.Username)
+
+	httpapi.Write(ctx, rw, http.StatusCreated, enrollment)
+}
+
+// verifyLoginMFA checks the second factor of a password login. When the
+// deployment requires MFA, a code for a pending enrollment completes the
+// enrollment, and the new recovery codes are returned.
+func (api *API) verifyLoginMFA(ctx context.Context, rw http.ResponseWriter, aReq *audit.Request[database.AuditableUserMFA], user database.User, code string) ([]string, bool) {
+	//nolint:gocritic // The user is not logged in yet.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+
+	mfa, err := api.Database.GetUserMFAByUserID(ctx, user.ID)
+	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+		api.Logger.Error(ctx, "unable to fetch user mfa", slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error.",
+		})
+		return nil, false
+	}
+	enrolled := err == nil
+
+	if !enrolled || !mfa.EnabledAt.Valid {
+		if !api.DeploymentValues.RequireMFA.Value() {
+			return nil, true
+		}
+		if !enrolled || code == "" {
+			httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+				Message: "Multi-factor authentication is required. Enroll an authenticator app to log in.",
+				Validations: []codersdk.ValidationError{{
+					Field:  codersdk.MFAEnrollmentValidationField,
+					Detail: "An authenticator must be enrolled.",
+				}},
+			})
+			return nil, false
+		}
+		step, valid := totp.Validate(mfa.TOTPSecret, code, dbtime.Now(), 0)
+		if !valid {
+			writeInvalidMFACode(ctx, rw)
+			return nil, false
+		}
+		enabled, recoveryCodes, err := api.enableMFA(ctx, mfa, step, "")
+		if err != nil {
+			api.Logger.Error(ctx, "unable to enable user mfa", slog.Error(err))
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error.",
+			})
+			return nil, false
+		}
+		aReq.UserID = user.ID
+		aReq.Old = mfa.Auditable(user.Username)
+		aReq.New = enabled.Auditable(user.Username)
+		return recoveryCodes, true
+	}
+
+	if code == "" {
+		httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+			Message: "Enter a code from your authenticator app, or a recovery code.",
+			Validations: []codersdk.ValidationError{{
+				Field:  codersdk.MFACodeValidationField,
+				Detail: "A code is required.",
+			}},
+		})
+		return nil, false
+	}
+	valid, err := api.checkMFACode(ctx, mfa, code)
+	if errors.Is(err, errMFALockedOut) {
+		writeMFALockedOut(ctx, rw)
+		return nil, false
+	}
+	if err != nil {
+		api.Logger.Error(ctx, "unable to check mfa code", slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error.",
+		})
+		return nil, false
+	}
+	if !valid {
+		writeInvalidMFACode(ctx, rw)
+		return nil, false
+	}
+	return nil, true
+}
+
+func writeInvalidMFACode(ctx context.Context, rw http.ResponseWriter) {
+	httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+		Message: "Invalid multi-factor authentication code.",
+		Validations: []codersdk.ValidationError{{
+			Field:  codersdk.MFACodeValidationField,
+			Detail: "The code does not match the authenticator, expired, or was already used.",
+		}},
+	})
+}
+
+func writeMFALockedOut(ctx context.Context, rw http.ResponseWriter) {
+	httpapi.Write(ctx, rw, http.StatusTooManyRequests, codersdk.Response{
+		Message: fmt.Sprintf("Too many invalid multi-factor authentication codes. Try again in %s.", mfaLockoutDuration),
+	})
+}
+
+// checkMFACode validates a code from the authenticator or a recovery code,
+// and marks it as used. Invalid codes are counted per user, and
+// errMFALockedOut is returned without checking the code once there were
+// too many recently.
+func (api *API) checkMFACode(ctx context.Context, mfa database.UserMFA, code string) (bool, error) {
+	now := dbtime.Now()
+	if mfa.FailedAttempts >= mfaMaxFailedAttempts && mfa.LastFailedAt.Valid && mfa.LastFailedAt.Time.Add(mfaLockoutDuration).After(now) {
+		return false, errMFALockedOut
+	}
+
+	valid, err := api.useMFACode(ctx, mfa, code, now)
+	if err != nil {
+		return false, err
+	}
+	if !valid {
+		_, err = api.Database.RecordUserMFAFailedAttempt(ctx, database.RecordUserMFAFailedAttemptParams{
+			UserID:       mfa.UserID,
+			FailedAfter:  now.Add(-mfaLockoutDuration),
+			LastFailedAt: sql.NullTime{Time: now, Valid: true},
+		})
+		if err != nil {
+			return false, xerrors.Errorf("record failed attempt: %w", err)
+		}
+		return false, nil
+	}
+	if mfa.FailedAttempts > 0 {
+		err = api.Database.ResetUserMFAFailedAttempts(ctx, mfa.UserID)
+		if err != nil {
+			return false, xerrors.Errorf("reset failed attempts: %w", err)
+		}
+	}
+	return true, nil
+}
+
+func (api *API) useMFACode(ctx context.Context, mfa database.UserMFA, code string, now time.Time) (bool, error) {
+	step, valid := totp.Validate(mfa.TOTPSecret, code, now, mfa.TOTPLastUsedStep)
+	if valid {
+		_, err := api.Database.UpdateUserMFALastUsedStep(ctx, database.UpdateUserMFALastUsedStepParams{
+			UserID:           mfa.UserID,
+			TOTPLastUsedStep: step,
+		})
+		if xerrors.Is(err, sql.ErrNoRows) {
+			// A concurrent login used the code first.
+			return false, nil
+		}
+		if err != nil {
+			return false, xerrors.Errorf("update last used step: %w", err)
+		}
+		return true, nil
+	}
+
+	_, err := api.Database.UseUserMFARecoveryCode(ctx, database.UseUserMFARecoveryCodeParams{
+		UserID:     mfa.UserID,
+		HashedCode: hashMFARecoveryCode(code),
+		UsedAt:     sql.NullTime{Time: now, Valid: true},
+	})
+	if xerrors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, xerrors.Errorf("use recovery code: %w", err)
+	}
+	return true, nil
+}
+
+// beginTOTPEnrollment creates a pending enrollment with a new secret.
+func (api *API) beginTOTPEnrollment(ctx context.Context, rw http.ResponseWriter, user database.User) (database.UserMFA, codersdk.TOTPEnrollment, bool) {
+	if user.LoginType != database.LoginTypePassword {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Multi-factor authentication is only available to users that log in with a password. Your identity provider manages it for other login types.",
+		})
+		return database.UserMFA{}, codersdk.TOTPEnrollment{}, false
+	}
+
+	existing, err := api.Database.GetUserMFAByUserID(ctx, user.ID)
+	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching user's MFA enrollment.",
+			Detail:  err.Error(),
+		})
+		return database.UserMFA{}, codersdk.TOTPEnrollment{}, false
+	}
+	if err == nil && existing.EnabledAt.Valid {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: "An authenticator is already enrolled. Reset multi-factor authentication before enrolling a new one.",
+		})
+		return database.UserMFA{}, codersdk.TOTPEnrollment{}, false
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error generating secret.",
+			Detail:  err.Error(),
+		})
+		return database.UserMFA{}, codersdk.TOTPEnrollment{}, false
+	}
+	now := dbtime.Now()
+	mfa, err := api.Database.UpsertUserMFA(ctx, database.UpsertUserMFAParams{
+		UserID:     user.ID,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+		TOTPSecret: secret,
+	})
+	if xerrors.Is(err, sql.ErrNoRows) {
+		// Another request enabled an authenticator since the check above.
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: "An authenticator is already enrolled. Reset multi-factor authentication before enrolling a new one.",
+		})
+		return database.UserMFA{}, codersdk.TOTPEnrollment{}, false
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating MFA enrollment.",
+			Detail:  err.Error(),
+		})
+		return database.UserMFA{}, codersdk.TOTPEnrollment{}, false
+	}
+
+	return mfa, codersdk.TOTPEnrollment{
+		Secret: secret,
+		URL:    totp.URL(mfaTOTPIssuer, user.Email, secret),
+	}, true
+}
+
+// enableMFA enables a pending enrollment and replaces the recovery codes of
+// the user. Sessions of the user other than the one with the ID are
+// revoked, since they were created without a second factor.
+func (api *API) enableMFA(ctx context.Context, mfa database.UserMFA, step int64, keepAPIKeyID string) (database.UserMFA, []string, error) {
+	recoveryCodes := make([]string, 0, mfaRecoveryCodeCount)
+	for i := 0; i < mfaRecoveryCodeCount; i++ {
+		code, err := cryptorand.StringCharset(cryptorand.Human, 10)
+		if err != nil {
+			return database.UserMFA{}, nil, xerrors.Errorf("generate recovery code: %w", err)
+		}
+		recoveryCodes = append(recoveryCodes, code[:5]+"-"+code[5:])
+	}
+
+	var enabled database.UserMFA
+	err := api.Database.InTx(func(tx database.Store) error {
+		now := dbtime.Now()
+		var err error
+		enabled, err = tx.EnableUserMFA(ctx, database.EnableUserMFAParams{
+			UserID:           mfa.UserID,
+			UpdatedAt:        now,
+			TOTPLastUsedStep: step,
+		})
+		if err != nil {
+			return xerrors.Errorf("enable mfa: %w", err)
+		}
+		err = tx.DeleteUserMFARecoveryCodesByUserID(ctx, mfa.UserID)
+		if err != nil {
+			return xerrors.Errorf("delete recovery codes: %w", err)
+		}
+		for _, code := range recoveryCodes {
+			_, err = tx.InsertUserMFARecoveryCode(ctx, database.InsertUserMFARecoveryCodeParams{
+				ID:         uuid.New(),
+				UserID:     mfa.UserID,
+				CreatedAt:  now,
+				HashedCode: hashMFARecoveryCode(code),
+			})
+			if err != nil {
+				return xerrors.Errorf("insert recovery code: %w", err)
+			}
+		}
+		return revokePasswordSessions(ctx, tx, mfa.UserID, keepAPIKeyID)
+	}, nil)
+	if err != nil {
+		return database.UserMFA{}, nil, err
+	}
+	return enabled, recoveryCodes, nil
+}
+
+// hashMFARecoveryCode hashes the code ignoring case and separators, so
+// codes can be entered as they are displayed or without formatting.
+func hashMFARecoveryCode(code string) []byte {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(code)))
+	hashed := sha256.Sum256([]byte(normalized))
+	return hashed[:]
+}
+
+// revokePasswordSessions deletes the sessions a user created by logging in
+// with a password, except the one with the ID. Tokens are kept.
+func revokePasswordSessions(ctx context.Context, db database.Store, userID uuid.UUID, keepAPIKeyID string) error {
+	keys, err := db.GetAPIKeysByUserID(ctx, database.GetAPIKeysByUserIDParams{
+		LoginType: database.LoginTypePassword,
+		UserID:    userID,
+	})
+	if err != nil {
+		return xerrors.Errorf("get sessions: %w", err)
+	}
+	for _, key := range keys {
+		if key.ID == keepAPIKeyID {
+			continue
+		}
+		err = db.DeleteAPIKeyByID(ctx, key.ID)
+		if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+			return xerrors.Errorf("delete session %q: %w", key.ID, err)
+		}
+	}
+	return nil
+}
+
+// revokeSessionsWithoutMFA revokes the sessions of password users that
+// have not enabled MFA. It runs when the deployment requires MFA, since
+// those sessions were created without a second factor.
+func (api *API) revokeSessionsWithoutMFA(ctx context.Context) error {
+	//nolint:gocritic // Revoking sessions is a system function.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+
+	keys, err := api.Database.GetAPIKeysByLoginType(ctx, database.LoginTypePassword)
+	if err != nil {
+		return xerrors.Errorf("get sessions: %w", err)
+	}
+	enabled := make(map[uuid.UUID]bool)
+	for _, key := range keys {
+		isEnabled, ok := enabled[key.UserID]
+		if !ok {
+			mfa, err := api.Database.GetUserMFAByUserID(ctx, key.UserID)
+			if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+				return xerrors.Errorf("get mfa of user %q: %w", key.UserID, err)
+			}
+			isEnabled = err == nil && mfa.EnabledAt.Valid
+			enabled[key.UserID] = isEnabled
+		}
+		if isEnabled {
+			continue
+		}
+		err = api.Database.DeleteAPIKeyByID(ctx, key.ID)
+		if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+			return xerrors.Errorf("delete session %q: %w", key.ID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,370 @@
+package coderd_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/totp"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestUserMFA(t *testing.T) {
+	t.Parallel()
+
+	t.Run("EnrollAndLogin", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		memberClient, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		secret, recoveryCodes := enableTOTP(ctx, t, memberClient)
+		require.Len(t, recoveryCodes, 10)
+
+		status, err := memberClient.UserMFA(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.True(t, status.Enabled)
+		require.NotNil(t, status.EnabledAt)
+		require.False(t, status.Required)
+		require.Equal(t, 10, status.RecoveryCodesRemaining)
+
+		req := codersdk.LoginWithPasswordRequest{
+			Email:    member.Email,
+			Password: "SomeSecurePassword!",
+		}
+		_, err = memberClient.LoginWithPassword(ctx, req)
+		require.True(t, codersdk.IsMFACodeRequired(err), "expected code to be required, got %v", err)
+
+		req.MFACode = "000000"
+		_, err = memberClient.LoginWithPassword(ctx, req)
+		require.True(t, codersdk.IsMFACodeRequired(err), "expected invalid code, got %v", err)
+
+		// The code used to verify the enrollment cannot be used again, so
+		// use the next one.
+		req.MFACode = totpCode(t, secret, totp.Step(time.Now())+1)
+		_, err = memberClient.LoginWithPassword(ctx, req)
+		require.NoError(t, err)
+
+		_, err = memberClient.LoginWithPassword(ctx, req)
+		require.True(t, codersdk.IsMFACodeRequired(err), "expected replayed code to fail, got %v", err)
+	})
+
+	t.Run("RecoveryCode", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		memberClient, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		_, recoveryCodes := enableTOTP(ctx, t, memberClient)
+
+		req := codersdk.LoginWithPasswordRequest{
+			Email:    member.Email,
+			Password: "SomeSecurePassword!",
+			MFACode:  recoveryCodes[0],
+		}
+		_, err := memberClient.LoginWithPassword(ctx, req)
+		require.NoError(t, err)
+
+		_, err = memberClient.LoginWithPassword(ctx, req)
+		require.True(t, codersdk.IsMFACodeRequired(err), "expected used recovery code to fail, got %v", err)
+
+		status, err := memberClient.UserMFA(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Equal(t, 9, status.RecoveryCodesRemaining)
+	})
+
+	t.Run("Required", func(t *testing.T) {
+		t.Parallel()
+		dv := coderdtest.DeploymentValues(t)
+		client := coderdtest.New(t, &coderdtest.Options{
+			DeploymentValues: dv,
+		})
+		owner := coderdtest.CreateFirstUser(t, client)
+		memberClient, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		// Require MFA once the helpers above logged in with passwords.
+		dv.RequireMFA = true
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		req := codersdk.LoginWithPasswordRequest{
+			Email:    member.Email,
+			Password: "SomeSecurePassword!",
+		}
+		_, err := memberClient.LoginWithPassword(ctx, req)
+		require.True(t, codersdk.IsMFAEnrollmentRequired(err), "expected enrollment to be required, got %v", err)
+
+		enrollment, err := memberClient.EnrollTOTPWithPassword(ctx, req)
+		require.NoError(t, err)
+		require.Contains(t, enrollment.URL, enrollment.Secret)
+
+		req.MFACode = totpCode(t, enrollment.Secret, totp.Step(time.Now()))
+		resp, err := memberClient.LoginWithPassword(ctx, req)
+		require.NoError(t, err)
+		require.Len(t, resp.MFARecoveryCodes, 10)
+
+		memberClient.SetSessionToken(resp.SessionToken)
+		status, err := memberClient.UserMFA(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.True(t, status.Enabled)
+		require.True(t, status.Required)
+
+		// Users cannot remove the authenticator the deployment requires.
+		err = memberClient.ResetUserMFA(ctx, codersdk.Me, codersdk.ResetUserMFARequest{
+			Code: resp.MFARecoveryCodes[0],
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+
+	t.Run("SelfReset", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		memberClient, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		_, recoveryCodes := enableTOTP(ctx, t, memberClient)
+
+		// A session alone is not enough to remove the authenticator.
+		var apiErr *codersdk.Error
+		err := memberClient.ResetUserMFA(ctx, codersdk.Me, codersdk.ResetUserMFARequest{})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		err = memberClient.ResetUserMFA(ctx, codersdk.Me, codersdk.ResetUserMFARequest{Code: "000000"})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		err = memberClient.ResetUserMFA(ctx, codersdk.Me, codersdk.ResetUserMFARequest{Code: recoveryCodes[0]})
+		require.NoError(t, err)
+		status, err := memberClient.UserMFA(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.False(t, status.Enabled)
+	})
+
+	t.Run("FailedAttemptLimit", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		memberClient, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		_, recoveryCodes := enableTOTP(ctx, t, memberClient)
+
+		req := codersdk.LoginWithPasswordRequest{
+			Email:    member.Email,
+			Password: "SomeSecurePassword!",
+			MFACode:  "000000",
+		}
+		for i := 0; i < 5; i++ {
+			_, err := memberClient.LoginWithPassword(ctx, req)
+			require.True(t, codersdk.IsMFACodeRequired(err), "expected invalid code, got %v", err)
+		}
+
+		// Even valid codes are rejected once the limit is reached.
+		req.MFACode = recoveryCodes[0]
+		_, err := memberClient.LoginWithPassword(ctx, req)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusTooManyRequests, apiErr.StatusCode())
+	})
+
+	t.Run("RevokeOtherSessions", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		memberClient, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		resp, err := memberClient.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+			Email:    member.Email,
+			Password: "SomeSecurePassword!",
+		})
+		require.NoError(t, err)
+		otherClient := codersdk.New(client.URL)
+		otherClient.SetSessionToken(resp.SessionToken)
+		_, err = otherClient.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+
+		enableTOTP(ctx, t, memberClient)
+
+		// The session that enabled MFA is kept.
+		_, err = memberClient.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		_, err = otherClient.User(ctx, codersdk.Me)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+	})
+
+	t.Run("RequiredRevokesSessions", func(t *testing.T) {
+		t.Parallel()
+		db, pubsub := dbtestutil.NewDB(t)
+		client := coderdtest.New(t, &coderdtest.Options{
+			Database: db,
+			Pubsub:   pubsub,
+		})
+		owner := coderdtest.CreateFirstUser(t, client)
+		memberClient, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		mfaClient, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		enableTOTP(ctx, t, mfaClient)
+
+		// Another replica starts requiring MFA.
+		dv := coderdtest.DeploymentValues(t)
+		dv.RequireMFA = true
+		requiredClient := coderdtest.New(t, &coderdtest.Options{
+			Database:         db,
+			Pubsub:           pubsub,
+			DeploymentValues: dv,
+		})
+
+		memberClient.URL = requiredClient.URL
+		_, err := memberClient.User(ctx, codersdk.Me)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+
+		mfaClient.URL = requiredClient.URL
+		_, err = mfaClient.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+	})
+
+	t.Run("EnrollOtherUser", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		_, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		_, err := client.EnrollUserTOTP(ctx, member.ID.String())
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+
+	t.Run("EnrollAfterEnabled", func(t *testing.T) {
+		t.Parallel()
+		db, pubsub := dbtestutil.NewDB(t)
+		// The check for an enabled authenticator passes as if another
+		// request enabled it concurrently.
+		client := coderdtest.New(t, &coderdtest.Options{
+			Database: pendingMFAStore{Store: db},
+			Pubsub:   pubsub,
+		})
+		owner := coderdtest.CreateFirstUser(t, client)
+		memberClient, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		secret, _ := enableTOTP(ctx, t, memberClient)
+
+		_, err := memberClient.EnrollUserTOTP(ctx, codersdk.Me)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+
+		// The enabled authenticator is kept.
+		mfa, err := db.GetUserMFAByUserID(ctx, member.ID)
+		require.NoError(t, err)
+		require.True(t, mfa.EnabledAt.Valid)
+		require.Equal(t, secret, mfa.TOTPSecret)
+	})
+
+	t.Run("AdminReset", func(t *testing.T) {
+		t.Parallel()
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{Auditor: auditor})
+		owner := coderdtest.CreateFirstUser(t, client)
+		memberClient, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		enableTOTP(ctx, t, memberClient)
+
+		numLogs := len(auditor.AuditLogs())
+		// Admins do not need a code of the user.
+		err := client.ResetUserMFA(ctx, member.ID.String(), codersdk.ResetUserMFARequest{})
+		require.NoError(t, err)
+
+		require.Len(t, auditor.AuditLogs(), numLogs+1)
+		log := auditor.AuditLogs()[numLogs]
+		require.Equal(t, database.AuditActionDelete, log.Action)
+		require.Equal(t, database.ResourceTypeUserMFA, log.ResourceType)
+		require.Equal(t, member.ID, log.ResourceID)
+		require.Equal(t, member.Username, log.ResourceTarget)
+
+		_, err = memberClient.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+			Email:    member.Email,
+			Password: "SomeSecurePassword!",
+		})
+		require.NoError(t, err)
+
+		status, err := memberClient.UserMFA(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.False(t, status.Enabled)
+	})
+}
+
+// enableTOTP enrolls and verifies an authenticator for the user of the
+// client, returning its secret and the recovery codes.
+func enableTOTP(ctx context.Context, t *testing.T, client *codersdk.Client) (string, []string) {
+	t.Helper()
+
+	enrollment, err := client.EnrollUserTOTP(ctx, codersdk.Me)
+	require.NoError(t, err)
+	codes, err := client.VerifyUserTOTP(ctx, codersdk.Me, codersdk.VerifyTOTPRequest{
+		Code: totpCode(t, enrollment.Secret, totp.Step(time.Now())),
+	})
+	require.NoError(t, err)
+	return enrollment.Secret, codes.RecoveryCodes
+}
+
+func totpCode(t *testing.T, secret string, step int64) string {
+	t.Helper()
+
+	code, err := totp.Code(secret, step)
+	require.NoError(t, err)
+	return code
+}
+
+// pendingMFAStore reports every MFA enrollment as pending.
+type pendingMFAStore struct {
+	database.Store
+}
+
+func (s pendingMFAStore) GetUserMFAByUserID(ctx context.Context, userID uuid.UUID) (database.UserMFA, error) {
+	mfa, err := s.Store.GetUserMFAByUserID(ctx, userID)
+	mfa.EnabledAt = sql.NullTime{}
+	return mfa, err
+}
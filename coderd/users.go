@@ -304,6 +304,13 @@ func (api *API) postUser(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if req.UserLoginType != codersdk.LoginTypeServiceAccount && req.ServiceAccountOwnerGroupID != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("An owner group can only be set for service accounts, not %q users.", req.UserLoginType),
+		})
+		return
+	}
+
 	// If password auth is disabled, don't allow new users to be
 	// created with a password!
 	if api.DeploymentValues.DisablePasswordAuth && req.UserLoginType == codersdk.LoginTypePassword {
@@ -391,6 +398,38 @@ func (api *API) postUser(rw http.ResponseWriter, r *http.Request) {
 		loginType = database.LoginTypeOIDC
 	case codersdk.LoginTypeGithub:
 		loginType = database.LoginTypeGithub
+	case codersdk.LoginTypeLDAP:
+		loginType = database.LoginTypeLDAP
+	case codersdk.LoginTypeServiceAccount:
+		if req.ServiceAccountOwnerGroupID == nil {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Service accounts must be owned by a group.",
+				Validations: []codersdk.ValidationError{{
+					Field:  "service_account_owner_group_id",
+					Detail: "This value is required for service accounts.",
+				}},
+			})
+			return
+		}
+		group, err := api.Database.GetGroupByID(ctx, *req.ServiceAccountOwnerGroupID)
+		if httpapi.Is404Error(err) || (err == nil && group.OrganizationID != req.OrganizationID) {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: fmt.Sprintf("Group %q does not exist in the user's organization.", req.ServiceAccountOwnerGroupID),
+				Validations: []codersdk.ValidationError{{
+					Field:  "service_account_owner_group_id",
+					Detail: "Must be the ID of a group in the user's organization.",
+				}},
+			})
+			return
+		}
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching group.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		loginType = database.LoginTypeServiceAccount
 	default:
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
 			Message: fmt.Sprintf("Unsupported login type %q for manually creating new users.", req.UserLoginType),
@@ -1110,6 +1149,12 @@ func (api *API) CreateUser(ctx context.Context, store database.Store, req Create
 			RBACRoles: []string{},
 			LoginType: req.LoginType,
 		}
+		if req.ServiceAccountOwnerGroupID != nil {
+			params.ServiceAccountOwnerGroupID = uuid.NullUUID{
+				UUID:  *req.ServiceAccountOwnerGroupID,
+				Valid: true,
+			}
+		}
 		// If a user signs up with OAuth, they can have no password!
 		if req.Password != "" {
 			hashedPassword, err := userpassword.Hash(req.Password)
@@ -1135,6 +1180,8 @@ func (api *API) CreateUser(ctx context.Context, store database.Store, req Create
 			UpdatedAt:  dbtime.Now(),
 			PrivateKey: privateKey,
 			PublicKey:  publicKey,
+			Name:       gitsshkey.DefaultName,
+			Hosts:      []string{},
 		})
 		if err != nil {
 			return xerrors.Errorf("insert user gitsshkey: %w", err)
@@ -1185,7 +1232,7 @@ func findUser(id uuid.UUID, users []database.User) *database.User {
 }
 
 func convertAPIKey(k database.APIKey) codersdk.APIKey {
-	return codersdk.APIKey{
+	key := codersdk.APIKey{
 		ID:              k.ID,
 		UserID:          k.UserID,
 		LastUsed:        k.LastUsed,
@@ -1197,4 +1244,13 @@ func convertAPIKey(k database.APIKey) codersdk.APIKey {
 		LifetimeSeconds: k.LifetimeSeconds,
 		TokenName:       k.TokenName,
 	}
+	for _, name := range k.Scopes {
+		key.Scopes = append(key.Scopes, codersdk.APIKeyScope(name))
+	}
+	for _, id := range k.AllowList {
+		if id, err := uuid.Parse(id); err == nil {
+			key.AllowList = append(key.AllowList, id)
+		}
+	}
+	return key
 }
@@ -0,0 +1,210 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/apikey"
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/workloadidentity"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// CI providers such as GitHub Actions and GitLab issue OIDC tokens to
+// pipelines. Similar to instance identity for agents, a pipeline can trade
+// its token for a short-lived session token of the user an admin mapped it
+// to, so no long-lived token needs to be stored as a secret.
+//
+// @Summary Exchange workload identity token
+// @ID exchange-workload-identity-token
+// @Accept json
+// @Produce json
+// @Tags Authorization
+// @Param request body codersdk.WorkloadIdentityExchangeRequest true "Exchange request"
+// @Success 201 {object} codersdk.WorkloadIdentityExchangeResponse
+// @Router /users/workload-identity/exchange [post]
+func (api *API) postWorkloadIdentityExchange(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		auditor           = api.Auditor.Load()
+		logger            = api.Logger.Named(userAuthLoggerName)
+		aReq, commitAudit = audit.InitRequest[database.APIKey](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionLogin,
+		})
+	)
+	aReq.Old = database.APIKey{}
+	defer commitAudit()
+
+	var req codersdk.WorkloadIdentityExchangeRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	var provider *workloadidentity.Provider
+	for _, p := range api.WorkloadIdentityProviders {
+		if p.ID == req.ProviderID {
+			provider = p
+			break
+		}
+	}
+	if provider == nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Workload identity provider %q is not configured.", req.ProviderID),
+			Validations: []codersdk.ValidationError{{
+				Field:  "provider_id",
+				Detail: "Unknown provider.",
+			}},
+		})
+		return
+	}
+
+	claims, err := provider.Verify(ctx, req.Token)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+			Message: "Invalid workload identity token.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rule, ok := provider.Match(claims)
+	if !ok {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "No rule of the workload identity provider matches the token.",
+		})
+		return
+	}
+
+	//nolint:gocritic // The rule configured by an admin maps the token to the user.
+	user, err := api.Database.GetUserByEmailOrUsername(dbauthz.AsSystemRestricted(ctx), database.GetUserByEmailOrUsernameParams{
+		Username: rule.Username,
+	})
+	if err != nil {
+		if xerrors.Is(err, sql.ErrNoRows) {
+			httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+				Message: fmt.Sprintf("User %q mapped by the workload identity provider does not exist.", rule.Username),
+			})
+			return
+		}
+		logger.Error(ctx, "unable to fetch user by username", slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error.",
+		})
+		return
+	}
+	aReq.UserID = user.ID
+
+	//nolint:gocritic // System needs to fetch user roles in order to issue the token.
+	roles, err := api.Database.GetAuthorizationUserRoles(dbauthz.AsSystemRestricted(ctx), user.ID)
+	if err != nil {
+		logger.Error(ctx, "unable to fetch authorization user roles", slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error.",
+		})
+		return
+	}
+	// Dormant users are activated when the token is first used.
+	if roles.Status == database.UserStatusSuspended {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: fmt.Sprintf("User %q is suspended.", user.Username),
+		})
+		return
+	}
+
+	api.deleteExpiredWorkloadIdentityKeys(ctx, user.ID, provider.ID)
+
+	scopes := make([]string, 0, len(rule.Scopes))
+	for _, scope := range rule.Scopes {
+		scopes = append(scopes, string(scope))
+	}
+
+	userSubj := rbac.Subject{
+		ID:     user.ID.String(),
+		Roles:  rbac.RoleNames(roles.Roles),
+		Groups: roles.Groups,
+		Scope:  rbac.ScopeAll,
+	}
+	lifeTime := codersdk.WorkloadIdentityTokenLifetime
+	expiresAt := dbtime.Now().Add(lifeTime)
+	//nolint:gocritic // Creating the API key as the user instead of as system.
+	cookie, key, err := api.createAPIKey(dbauthz.As(ctx, userSubj), apikey.CreateParams{
+		UserID:           user.ID,
+		LoginType:        database.LoginTypeToken,
+		RemoteAddr:       r.RemoteAddr,
+		DeploymentValues: api.DeploymentValues,
+		ExpiresAt:        expiresAt,
+		LifetimeSeconds:  int64(lifeTime.Seconds()),
+		Scope:            database.APIKeyScopeAll,
+		Scopes:           scopes,
+		// Token names are unique per user, and every exchange issues a
+		// new token.
+		TokenName: fmt.Sprintf("%s-%s", provider.ID, uuid.NewString()),
+	})
+	if err != nil {
+		logger.Error(ctx, "unable to create API key", slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to create API key.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = *key
+
+	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.WorkloadIdentityExchangeResponse{
+		SessionToken: cookie.Value,
+		ExpiresAt:    expiresAt,
+		Username:     user.Username,
+	})
+}
+
+// deleteExpiredWorkloadIdentityKeys deletes the expired tokens that earlier
+// exchanges issued to the user for the provider. Every exchange issues a new
+// token, since pipelines matching the same rule may run concurrently, so
+// nothing else would remove them.
+func (api *API) deleteExpiredWorkloadIdentityKeys(ctx context.Context, userID uuid.UUID, providerID string) {
+	//nolint:gocritic // The pipeline is not authenticated yet.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	keys, err := api.Database.GetAPIKeysByUserID(ctx, database.GetAPIKeysByUserIDParams{
+		LoginType: database.LoginTypeToken,
+		UserID:    userID,
+	})
+	if err != nil {
+		api.Logger.Warn(ctx, "get api keys of workload identity user", slog.Error(err))
+		return
+	}
+	now := dbtime.Now()
+	for _, key := range keys {
+		if key.ExpiresAt.After(now) {
+			continue
+		}
+		// Only the names generated by exchanges, so expired tokens the
+		// user created with a similar name are kept.
+		suffix, ok := strings.CutPrefix(key.TokenName, providerID+"-")
+		if !ok {
+			continue
+		}
+		if _, err := uuid.Parse(suffix); err != nil {
+			continue
+		}
+		err = api.Database.DeleteAPIKeyByID(ctx, key.ID)
+		if err != nil {
+			api.Logger.Warn(ctx, "delete expired workload identity api key", slog.F("id", key.ID), slog.Error(err))
+		}
+	}
+}
@@ -0,0 +1,162 @@
+// Package workloadidentity verifies OIDC tokens issued to CI pipelines and
+// other workloads by trusted external issuers, and maps them to Coder users.
+package workloadidentity
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// Provider is a trusted external OIDC issuer.
+type Provider struct {
+	ID       string
+	Issuer   string
+	Audience string
+	Rules    []codersdk.WorkloadIdentityRule
+	// HTTPClient is used to discover the signing keys of the issuer.
+	// http.DefaultClient is used if nil.
+	HTTPClient *http.Client
+
+	mutex    sync.Mutex
+	verifier *oidc.IDTokenVerifier
+}
+
+// Verify checks the signature, issuer, audience and expiry of the raw JWT
+// and returns its claims.
+func (p *Provider) Verify(ctx context.Context, raw string) (map[string]interface{}, error) {
+	verifier, err := p.getVerifier(ctx)
+	if err != nil {
+		return nil, err
+	}
+	token, err := verifier.Verify(ctx, raw)
+	if err != nil {
+		return nil, xerrors.Errorf("verify token: %w", err)
+	}
+	claims := map[string]interface{}{}
+	err = token.Claims(&claims)
+	if err != nil {
+		return nil, xerrors.Errorf("decode claims: %w", err)
+	}
+	return claims, nil
+}
+
+// Match returns the first rule whose claims all match the given claims.
+func (p *Provider) Match(claims map[string]interface{}) (codersdk.WorkloadIdentityRule, bool) {
+	for _, rule := range p.Rules {
+		if matchClaims(rule.Claims, claims) {
+			return rule, true
+		}
+	}
+	return codersdk.WorkloadIdentityRule{}, false
+}
+
+// getVerifier lazily discovers the issuer, so an issuer that is unreachable
+// at startup does not prevent the server from starting. Discovery happens
+// without holding the mutex, so a slow issuer only delays the exchanges that
+// wait for it; concurrent discoveries keep the first verifier.
+func (p *Provider) getVerifier(ctx context.Context) (*oidc.IDTokenVerifier, error) {
+	p.mutex.Lock()
+	verifier := p.verifier
+	p.mutex.Unlock()
+	if verifier != nil {
+		return verifier, nil
+	}
+
+	if p.HTTPClient != nil {
+		ctx = oidc.ClientContext(ctx, p.HTTPClient)
+	}
+	provider, err := oidc.NewProvider(ctx, p.Issuer)
+	if err != nil {
+		return nil, xerrors.Errorf("discover issuer %q: %w", p.Issuer, err)
+	}
+	verifier = provider.Verifier(&oidc.Config{
+		ClientID: p.Audience,
+	})
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.verifier == nil {
+		p.verifier = verifier
+	}
+	return p.verifier, nil
+}
+
+func matchClaims(want map[string]string, claims map[string]interface{}) bool {
+	for name, expected := range want {
+		value, ok := claims[name]
+		if !ok {
+			return false
+		}
+		actual := fmt.Sprint(value)
+		if prefix, ok := strings.CutSuffix(expected, "*"); ok {
+			if !strings.HasPrefix(actual, prefix) {
+				return false
+			}
+			continue
+		}
+		if actual != expected {
+			return false
+		}
+	}
+	return true
+}
+
+// ConvertConfig converts the deployment configuration into providers.
+func ConvertConfig(entries []codersdk.WorkloadIdentityProvider) ([]*Provider, error) {
+	ids := map[string]struct{}{}
+	providers := []*Provider{}
+	for _, entry := range entries {
+		if valid := httpapi.NameValid(entry.ID); valid != nil {
+			return nil, xerrors.Errorf("workload identity provider %q doesn't have a valid id: %w", entry.ID, valid)
+		}
+		if _, exists := ids[entry.ID]; exists {
+			return nil, xerrors.Errorf("multiple workload identity providers exist with the id %q. specify a unique id for each", entry.ID)
+		}
+		ids[entry.ID] = struct{}{}
+
+		if entry.Issuer == "" {
+			return nil, xerrors.Errorf("%q workload identity provider: issuer must be provided", entry.ID)
+		}
+		if entry.Audience == "" {
+			return nil, xerrors.Errorf("%q workload identity provider: audience must be provided", entry.ID)
+		}
+		for i, rule := range entry.Rules {
+			if len(rule.Claims) == 0 {
+				// A rule without claims would map every token the issuer
+				// has ever signed for the audience to the user.
+				return nil, xerrors.Errorf("%q workload identity provider: rule %d must match at least one claim", entry.ID, i)
+			}
+			if rule.Username == "" {
+				return nil, xerrors.Errorf("%q workload identity provider: rule %d must specify a username", entry.ID, i)
+			}
+			if len(rule.Scopes) == 0 {
+				// Without a scope, the token could do anything the user
+				// can, including creating long-lived tokens.
+				return nil, xerrors.Errorf("%q workload identity provider: rule %d must specify at least one scope", entry.ID, i)
+			}
+			for _, scope := range rule.Scopes {
+				if !rbac.ScopeName(scope).FineGrained() {
+					return nil, xerrors.Errorf("%q workload identity provider: rule %d: scope %q is not a fine-grained scope", entry.ID, i, scope)
+				}
+			}
+		}
+
+		providers = append(providers, &Provider{
+			ID:       entry.ID,
+			Issuer:   entry.Issuer,
+			Audience: entry.Audience,
+			Rules:    entry.Rules,
+		})
+	}
+	return providers, nil
+}
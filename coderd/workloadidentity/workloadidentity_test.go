@@ -0,0 +1,161 @@
+package workloadidentity_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/workloadidentity"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+func TestConvertConfig(t *testing.T) {
+	t.Parallel()
+
+	valid := func() codersdk.WorkloadIdentityProvider {
+		return codersdk.WorkloadIdentityProvider{
+			ID:       "github",
+			Issuer:   "https://token.actions.githubusercontent.com",
+			Audience: "https://coder.example.com",
+			Rules: []codersdk.WorkloadIdentityRule{{
+				Claims:   map[string]string{"repository": "coder/coder"},
+				Username: "ci-bot",
+				Scopes:   []codersdk.APIKeyScope{"template:read"},
+			}},
+		}
+	}
+
+	for _, tc := range []struct {
+		Name   string
+		Mutate func(p *codersdk.WorkloadIdentityProvider)
+		Error  string
+	}{{
+		Name:   "Valid",
+		Mutate: func(p *codersdk.WorkloadIdentityProvider) {},
+	}, {
+		Name:   "InvalidID",
+		Mutate: func(p *codersdk.WorkloadIdentityProvider) { p.ID = "not valid" },
+		Error:  "doesn't have a valid id",
+	}, {
+		Name:   "NoIssuer",
+		Mutate: func(p *codersdk.WorkloadIdentityProvider) { p.Issuer = "" },
+		Error:  "issuer must be provided",
+	}, {
+		Name:   "NoAudience",
+		Mutate: func(p *codersdk.WorkloadIdentityProvider) { p.Audience = "" },
+		Error:  "audience must be provided",
+	}, {
+		Name:   "NoClaims",
+		Mutate: func(p *codersdk.WorkloadIdentityProvider) { p.Rules[0].Claims = nil },
+		Error:  "must match at least one claim",
+	}, {
+		Name:   "NoUsername",
+		Mutate: func(p *codersdk.WorkloadIdentityProvider) { p.Rules[0].Username = "" },
+		Error:  "must specify a username",
+	}, {
+		Name:   "NoScopes",
+		Mutate: func(p *codersdk.WorkloadIdentityProvider) { p.Rules[0].Scopes = nil },
+		Error:  "must specify at least one scope",
+	}, {
+		Name: "CoarseScope",
+		Mutate: func(p *codersdk.WorkloadIdentityProvider) {
+			p.Rules[0].Scopes = []codersdk.APIKeyScope{codersdk.APIKeyScopeAll}
+		},
+		Error: "is not a fine-grained scope",
+	}} {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			entry := valid()
+			tc.Mutate(&entry)
+			providers, err := workloadidentity.ConvertConfig([]codersdk.WorkloadIdentityProvider{entry})
+			if tc.Error == "" {
+				require.NoError(t, err)
+				require.Len(t, providers, 1)
+				return
+			}
+			require.ErrorContains(t, err, tc.Error)
+		})
+	}
+
+	t.Run("DuplicateID", func(t *testing.T) {
+		t.Parallel()
+		_, err := workloadidentity.ConvertConfig([]codersdk.WorkloadIdentityProvider{valid(), valid()})
+		require.ErrorContains(t, err, "multiple workload identity providers")
+	})
+}
+
+func TestProviderMatch(t *testing.T) {
+	t.Parallel()
+
+	provider := &workloadidentity.Provider{
+		Rules: []codersdk.WorkloadIdentityRule{{
+			Claims: map[string]string{
+				"repository": "coder/coder",
+				"ref":        "refs/heads/main",
+			},
+			Username: "deployer",
+		}, {
+			Claims: map[string]string{
+				"repository": "coder/*",
+			},
+			Username: "ci-bot",
+		}, {
+			Claims: map[string]string{
+				"run_attempt": "1",
+			},
+			Username: "first-attempt",
+		}},
+	}
+
+	for _, tc := range []struct {
+		Name     string
+		Claims   map[string]interface{}
+		Username string
+	}{{
+		Name: "Exact",
+		Claims: map[string]interface{}{
+			"repository": "coder/coder",
+			"ref":        "refs/heads/main",
+		},
+		Username: "deployer",
+	}, {
+		Name: "FirstRuleWins",
+		Claims: map[string]interface{}{
+			"repository":  "coder/coder",
+			"ref":         "refs/heads/main",
+			"run_attempt": float64(1),
+		},
+		Username: "deployer",
+	}, {
+		Name: "Prefix",
+		Claims: map[string]interface{}{
+			"repository": "coder/coder",
+			"ref":        "refs/heads/feature",
+		},
+		Username: "ci-bot",
+	}, {
+		Name: "NonString",
+		Claims: map[string]interface{}{
+			"run_attempt": float64(1),
+		},
+		Username: "first-attempt",
+	}, {
+		Name: "NoMatch",
+		Claims: map[string]interface{}{
+			"repository": "someone/else",
+		},
+	}} {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			rule, ok := provider.Match(tc.Claims)
+			if tc.Username == "" {
+				require.False(t, ok)
+				return
+			}
+			require.True(t, ok)
+			require.Equal(t, tc.Username, rule.Username)
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package coderd_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/coderdtest/oidctest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/workloadidentity"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkloadIdentityExchange(t *testing.T) {
+	t.Parallel()
+
+	const (
+		issuer   = "https://ci.example.com"
+		audience = "https://coder.example.com"
+	)
+
+	setup := func(t *testing.T) (*oidctest.FakeIDP, *codersdk.Client, database.Store, *audit.MockAuditor, codersdk.User) {
+		t.Helper()
+
+		idp := oidctest.NewFakeIDP(t, oidctest.WithIssuer(issuer))
+		auditor := audit.NewMock()
+		db, pubsub := dbtestutil.NewDB(t)
+		client := coderdtest.New(t, &coderdtest.Options{
+			Database: db,
+			Pubsub:   pubsub,
+			Auditor:  auditor,
+			WorkloadIdentityProviders: []*workloadidentity.Provider{{
+				ID:       "ci",
+				Issuer:   issuer,
+				Audience: audience,
+				Rules: []codersdk.WorkloadIdentityRule{{
+					Claims:   map[string]string{"repository": "coder/*", "ref": "refs/heads/main"},
+					Username: "deployer",
+					Scopes:   []codersdk.APIKeyScope{codersdk.APIKeyScopeUserRead},
+				}, {
+					Claims:   map[string]string{"repository": "coder/missing"},
+					Username: "missing",
+					Scopes:   []codersdk.APIKeyScope{codersdk.APIKeyScopeUserRead},
+				}},
+				HTTPClient: idp.HTTPClient(nil),
+			}},
+		})
+		first := coderdtest.CreateFirstUser(t, client)
+		_, user := coderdtest.CreateAnotherUserMutators(t, client, first.OrganizationID, nil, func(r *codersdk.CreateUserRequest) {
+			r.Username = "deployer"
+		})
+		return idp, client, db, auditor, user
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		idp, client, _, auditor, user := setup(t)
+
+		res, err := client.ExchangeWorkloadIdentity(ctx, codersdk.WorkloadIdentityExchangeRequest{
+			ProviderID: "ci",
+			Token: idp.EncodeClaims(t, jwt.MapClaims{
+				"aud":        audience,
+				"repository": "coder/coder",
+				"ref":        "refs/heads/main",
+			}),
+		})
+		require.NoError(t, err)
+		require.Equal(t, user.Username, res.Username)
+		require.WithinDuration(t, res.ExpiresAt, time.Now().Add(codersdk.WorkloadIdentityTokenLifetime), time.Minute)
+
+		exchanged := codersdk.New(client.URL)
+		exchanged.SetSessionToken(res.SessionToken)
+		me, err := exchanged.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Equal(t, user.ID, me.ID)
+
+		keys, err := client.Tokens(ctx, user.ID.String(), codersdk.TokensFilter{})
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+		require.Equal(t, []codersdk.APIKeyScope{codersdk.APIKeyScopeUserRead}, keys[0].Scopes)
+
+		// Members may create tokens, but the scopes of the rule do not allow it.
+		_, err = exchanged.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{})
+		var sdkErr *codersdk.Error
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, http.StatusForbidden, sdkErr.StatusCode())
+
+		logs := auditor.AuditLogs()
+		require.NotEmpty(t, logs)
+		require.Equal(t, database.AuditActionLogin, logs[len(logs)-1].Action)
+		require.Equal(t, user.ID, logs[len(logs)-1].UserID)
+	})
+
+	t.Run("DeletesExpiredTokens", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		idp, client, db, _, user := setup(t)
+		exchange := func() codersdk.WorkloadIdentityExchangeResponse {
+			res, err := client.ExchangeWorkloadIdentity(ctx, codersdk.WorkloadIdentityExchangeRequest{
+				ProviderID: "ci",
+				Token: idp.EncodeClaims(t, jwt.MapClaims{
+					"aud":        audience,
+					"repository": "coder/coder",
+					"ref":        "refs/heads/main",
+				}),
+			})
+			require.NoError(t, err)
+			return res
+		}
+
+		expired := exchange()
+		keyID, _, ok := strings.Cut(expired.SessionToken, "-")
+		require.True(t, ok)
+		key, err := db.GetAPIKeyByID(dbauthz.AsSystemRestricted(ctx), keyID)
+		require.NoError(t, err)
+		err = db.UpdateAPIKeyByID(dbauthz.AsSystemRestricted(ctx), database.UpdateAPIKeyByIDParams{
+			ID:        key.ID,
+			LastUsed:  key.LastUsed,
+			ExpiresAt: dbtime.Now().Add(-time.Minute),
+			IPAddress: key.IPAddress,
+		})
+		require.NoError(t, err)
+
+		_ = exchange()
+		keys, err := client.Tokens(ctx, user.ID.String(), codersdk.TokensFilter{})
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+		require.NotEqual(t, keyID, keys[0].ID)
+	})
+
+	t.Run("UnknownProvider", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		idp, client, _, _, _ := setup(t)
+
+		_, err := client.ExchangeWorkloadIdentity(ctx, codersdk.WorkloadIdentityExchangeRequest{
+			ProviderID: "other",
+			Token:      idp.EncodeClaims(t, jwt.MapClaims{"aud": audience}),
+		})
+		var sdkErr *codersdk.Error
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, http.StatusBadRequest, sdkErr.StatusCode())
+	})
+
+	t.Run("WrongAudience", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		idp, client, _, _, _ := setup(t)
+
+		_, err := client.ExchangeWorkloadIdentity(ctx, codersdk.WorkloadIdentityExchangeRequest{
+			ProviderID: "ci",
+			Token: idp.EncodeClaims(t, jwt.MapClaims{
+				"aud":        "https://other.example.com",
+				"repository": "coder/coder",
+				"ref":        "refs/heads/main",
+			}),
+		})
+		var sdkErr *codersdk.Error
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, http.StatusUnauthorized, sdkErr.StatusCode())
+	})
+
+	t.Run("NoMatchingRule", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		idp, client, _, _, _ := setup(t)
+
+		_, err := client.ExchangeWorkloadIdentity(ctx, codersdk.WorkloadIdentityExchangeRequest{
+			ProviderID: "ci",
+			Token: idp.EncodeClaims(t, jwt.MapClaims{
+				"aud":        audience,
+				"repository": "coder/coder",
+				"ref":        "refs/heads/feature",
+			}),
+		})
+		var sdkErr *codersdk.Error
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, http.StatusForbidden, sdkErr.StatusCode())
+	})
+
+	t.Run("MissingUser", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		idp, client, _, _, _ := setup(t)
+
+		_, err := client.ExchangeWorkloadIdentity(ctx, codersdk.WorkloadIdentityExchangeRequest{
+			ProviderID: "ci",
+			Token: idp.EncodeClaims(t, jwt.MapClaims{
+				"aud":        audience,
+				"repository": "coder/missing",
+			}),
+		})
+		var sdkErr *codersdk.Error
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, http.StatusForbidden, sdkErr.StatusCode())
+	})
+
+	t.Run("Suspended", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		idp, client, _, _, user := setup(t)
+		_, err := client.UpdateUserStatus(ctx, user.Username, codersdk.UserStatusSuspended)
+		require.NoError(t, err)
+
+		_, err = client.ExchangeWorkloadIdentity(ctx, codersdk.WorkloadIdentityExchangeRequest{
+			ProviderID: "ci",
+			Token: idp.EncodeClaims(t, jwt.MapClaims{
+				"aud":        audience,
+				"repository": "coder/coder",
+				"ref":        "refs/heads/main",
+			}),
+		})
+		var sdkErr *codersdk.Error
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, http.StatusForbidden, sdkErr.StatusCode())
+	})
+}
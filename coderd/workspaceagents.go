@@ -32,9 +32,10 @@ import (
 
 	"cdr.dev/slog"
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
-	"github.com/coder/coder/v2/coderd/gitauth"
+	"github.com/coder/coder/v2/coderd/externalauth"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
 	"github.com/coder/coder/v2/coderd/rbac"
@@ -42,6 +43,7 @@ import (
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/codersdk/agentsdk"
 	"github.com/coder/coder/v2/tailnet"
+	tailnetproto "github.com/coder/coder/v2/tailnet/proto"
 )
 
 // @Summary Get workspace agent by ID
@@ -150,6 +152,15 @@ func (api *API) workspaceAgentManifest(rw http.ResponseWriter, r *http.Request)
 		})
 		return
 	}
+	//nolint:gocritic // The scope of agents doesn't include their template.
+	template, err := api.Database.GetTemplateByID(dbauthz.AsSystemRestricted(ctx), workspace.TemplateID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace template.",
+			Detail:  err.Error(),
+		})
+		return
+	}
 
 	vscodeProxyURI := strings.ReplaceAll(api.AppHostname, "*",
 		fmt.Sprintf("%s://{{port}}--%s--%s--%s",
@@ -162,12 +173,20 @@ func (api *API) workspaceAgentManifest(rw http.ResponseWriter, r *http.Request)
 		vscodeProxyURI += fmt.Sprintf(":%s", api.AccessURL.Port())
 	}
 
+	// Only Git providers are used by the agent to configure Git.
+	gitAuthConfigs := 0
+	for _, config := range api.GitAuthConfigs {
+		if config.IsGit() {
+			gitAuthConfigs++
+		}
+	}
+
 	httpapi.Write(ctx, rw, http.StatusOK, agentsdk.Manifest{
 		AgentID:                  apiAgent.ID,
 		Apps:                     convertApps(dbApps),
 		DERPMap:                  api.DERPMap(),
 		DERPForceWebSockets:      api.DeploymentValues.DERP.Config.ForceWebSockets.Value(),
-		GitAuthConfigs:           len(api.GitAuthConfigs),
+		GitAuthConfigs:           gitAuthConfigs,
 		EnvironmentVariables:     apiAgent.EnvironmentVariables,
 		StartupScript:            apiAgent.StartupScript,
 		Directory:                apiAgent.Directory,
@@ -178,6 +197,7 @@ func (api *API) workspaceAgentManifest(rw http.ResponseWriter, r *http.Request)
 		ShutdownScriptTimeout:    time.Duration(apiAgent.ShutdownScriptTimeoutSeconds) * time.Second,
 		DisableDirectConnections: api.DeploymentValues.DERP.Config.BlockDirect.Value(),
 		Metadata:                 convertWorkspaceAgentMetadataDesc(metadata),
+		EgressPolicy:             db2sdk.EgressPolicy(template.EgressPolicy),
 	})
 }
 
@@ -967,6 +987,7 @@ func (api *API) derpMapUpdates(rw http.ResponseWriter, r *http.Request) {
 // @ID coordinate-workspace-agent-via-tailnet
 // @Security CoderSessionToken
 // @Tags Agents
+// @Param version query string false "Version of the coordinator protocol, e.g. 2.0. Defaults to 1.0"
 // @Success 101
 // @Router /workspaceagents/me/coordinate [get]
 func (api *API) workspaceAgentCoordinate(rw http.ResponseWriter, r *http.Request) {
@@ -1026,6 +1047,20 @@ func (api *API) workspaceAgentCoordinate(rw http.ResponseWriter, r *http.Request
 		return nil
 	}
 
+	version := r.URL.Query().Get("version")
+	if version != "" {
+		err := tailnetproto.ValidateVersion(version)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Unknown or unsupported API version",
+				Validations: []codersdk.ValidationError{
+					{Field: "version", Detail: err.Error()},
+				},
+			})
+			return
+		}
+	}
+
 	err = ensureLatestBuild()
 	if err != nil {
 		api.Logger.Debug(ctx, "agent tried to connect from non-latest built",
@@ -1167,7 +1202,7 @@ func (api *API) workspaceAgentCoordinate(rw http.ResponseWriter, r *http.Request
 	closeChan := make(chan struct{})
 	go func() {
 		defer close(closeChan)
-		err := (*api.TailnetCoordinator.Load()).ServeAgent(wsNetConn, workspaceAgent.ID,
+		err := api.TailnetClientService.ServeAgent(ctx, version, wsNetConn, workspaceAgent.ID,
 			fmt.Sprintf("%s-%s-%s", owner.Username, workspace.Name, workspaceAgent.Name),
 		)
 		if err != nil {
@@ -1231,6 +1266,7 @@ func (api *API) workspaceAgentCoordinate(rw http.ResponseWriter, r *http.Request
 // @Security CoderSessionToken
 // @Tags Agents
 // @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Param version query string false "Version of the coordinator protocol, e.g. 2.0. Defaults to 1.0"
 // @Success 101
 // @Router /workspaceagents/{workspaceagent}/coordinate [get]
 func (api *API) workspaceAgentClientCoordinate(rw http.ResponseWriter, r *http.Request) {
@@ -1259,6 +1295,20 @@ func (api *API) workspaceAgentClientCoordinate(rw http.ResponseWriter, r *http.R
 	defer api.WebsocketWaitGroup.Done()
 	workspaceAgent := httpmw.WorkspaceAgentParam(r)
 
+	version := r.URL.Query().Get("version")
+	if version != "" {
+		err := tailnetproto.ValidateVersion(version)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Unknown or unsupported API version",
+				Validations: []codersdk.ValidationError{
+					{Field: "version", Detail: err.Error()},
+				},
+			})
+			return
+		}
+	}
+
 	conn, err := websocket.Accept(rw, r, nil)
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
@@ -1273,7 +1323,7 @@ func (api *API) workspaceAgentClientCoordinate(rw http.ResponseWriter, r *http.R
 	go httpapi.Heartbeat(ctx, conn)
 
 	defer conn.Close(websocket.StatusNormalClosure, "")
-	err = (*api.TailnetCoordinator.Load()).ServeClient(wsNetConn, uuid.New(), workspaceAgent.ID)
+	err = api.TailnetClientService.ServeClient(ctx, version, wsNetConn, uuid.New(), workspaceAgent.ID)
 	if err != nil {
 		_ = conn.Close(websocket.StatusInternalError, err.Error())
 		return
@@ -2004,8 +2054,134 @@ func (api *API) workspaceAgentsGitAuth(rw http.ResponseWriter, r *http.Request)
 	// new token to be issued!
 	listen := r.URL.Query().Has("listen")
 
-	var gitAuthConfig *gitauth.Config
+	gitAuthConfig, ok := api.matchGitAuthConfig(ctx, rw, gitURL)
+	if !ok {
+		return
+	}
+	resp, ok := api.workspaceAgentsExternalAuthToken(rw, r, gitAuthConfig, listen)
+	if !ok {
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, agentsdk.GitAuthResponse{
+		Username: resp.Username,
+		Password: resp.Password,
+		URL:      resp.URL,
+	})
+}
+
+// workspaceAgentsExternalAuth returns the access token of the workspace
+// owner for an external auth provider.
+//
+// @Summary Get workspace agent external auth
+// @ID get-workspace-agent-external-auth
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Agents
+// @Param id query string false "Provider ID"
+// @Param match query string false "Git URL to match against the Git providers"
+// @Param listen query bool false "Wait for a new token to be issued"
+// @Success 200 {object} agentsdk.ExternalAuthResponse
+// @Router /workspaceagents/me/external-auth [get]
+func (api *API) workspaceAgentsExternalAuth(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+	id := query.Get("id")
+	match := query.Get("match")
+
+	var config *externalauth.Config
+	switch {
+	case id != "" && match != "":
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Only one of 'id' or 'match' query parameters can be provided.",
+		})
+		return
+	case id != "":
+		for _, c := range api.GitAuthConfigs {
+			if c.ID == id {
+				config = c
+				break
+			}
+		}
+		if config == nil {
+			httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+				Message: fmt.Sprintf("No external auth provider with the id %q is configured.", id),
+			})
+			return
+		}
+		// Workspaces only get tokens for the providers their template
+		// requires, and not every token the owner has authorized.
+		if !api.templateVersionRequiresExternalAuth(rw, r, config.ID) {
+			return
+		}
+	case match != "":
+		var ok bool
+		config, ok = api.matchGitAuthConfig(ctx, rw, match)
+		if !ok {
+			return
+		}
+	default:
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Missing 'id' or 'match' query parameter!",
+		})
+		return
+	}
+
+	resp, ok := api.workspaceAgentsExternalAuthToken(rw, r, config, query.Has("listen"))
+	if !ok {
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// templateVersionRequiresExternalAuth reports whether the template version
+// of the latest build of the agent's workspace requires the provider.
+func (api *API) templateVersionRequiresExternalAuth(rw http.ResponseWriter, r *http.Request, providerID string) bool {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgent(r)
+	resource, err := api.Database.GetWorkspaceResourceByID(ctx, workspaceAgent.ResourceID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to get workspace resource.",
+			Detail:  err.Error(),
+		})
+		return false
+	}
+	build, err := api.Database.GetWorkspaceBuildByJobID(ctx, resource.JobID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to get build.",
+			Detail:  err.Error(),
+		})
+		return false
+	}
+	//nolint:gocritic // The scope of agents doesn't include their template.
+	templateVersion, err := api.Database.GetTemplateVersionByID(dbauthz.AsSystemRestricted(ctx), build.TemplateVersionID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to get template version.",
+			Detail:  err.Error(),
+		})
+		return false
+	}
+	if !slices.Contains(templateVersion.GitAuthProviders, providerID) {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: fmt.Sprintf("The template of this workspace does not require the external auth provider %q.", providerID),
+		})
+		return false
+	}
+	return true
+}
+
+// matchGitAuthConfig returns the last Git provider whose regex matches the
+// Git URL.
+func (api *API) matchGitAuthConfig(ctx context.Context, rw http.ResponseWriter, gitURL string) (*externalauth.Config, bool) {
+	var gitAuthConfig *externalauth.Config
+	regexURLs := make([]string, 0, len(api.GitAuthConfigs))
 	for _, gitAuth := range api.GitAuthConfigs {
+		if !gitAuth.IsGit() {
+			continue
+		}
+		regexURLs = append(regexURLs, fmt.Sprintf("%s=%q", gitAuth.ID, gitAuth.Regex.String()))
 		matches := gitAuth.Regex.MatchString(gitURL)
 		if !matches {
 			continue
@@ -2014,19 +2190,23 @@ func (api *API) workspaceAgentsGitAuth(rw http.ResponseWriter, r *http.Request)
 	}
 	if gitAuthConfig == nil {
 		detail := "No git providers are configured."
-		if len(api.GitAuthConfigs) > 0 {
-			regexURLs := make([]string, 0, len(api.GitAuthConfigs))
-			for _, gitAuth := range api.GitAuthConfigs {
-				regexURLs = append(regexURLs, fmt.Sprintf("%s=%q", gitAuth.ID, gitAuth.Regex.String()))
-			}
+		if len(regexURLs) > 0 {
 			detail = fmt.Sprintf("The configured git provider have regex filters that do not match the git url. Provider url regexs: %s", strings.Join(regexURLs, ","))
 		}
 		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
 			Message: fmt.Sprintf("No matching git provider found in Coder for the url %q.", gitURL),
 			Detail:  detail,
 		})
-		return
+		return nil, false
 	}
+	return gitAuthConfig, true
+}
+
+// workspaceAgentsExternalAuthToken returns the access token of the workspace
+// owner for the provider, or the URL to authenticate at if there is no valid
+// token. If listen is set, it waits for the owner to authenticate instead.
+func (api *API) workspaceAgentsExternalAuthToken(rw http.ResponseWriter, r *http.Request, config *externalauth.Config, listen bool) (agentsdk.ExternalAuthResponse, bool) {
+	ctx := r.Context()
 	workspaceAgent := httpmw.WorkspaceAgent(r)
 	// We must get the workspace to get the owner ID!
 	resource, err := api.Database.GetWorkspaceResourceByID(ctx, workspaceAgent.ResourceID)
@@ -2035,7 +2215,7 @@ func (api *API) workspaceAgentsGitAuth(rw http.ResponseWriter, r *http.Request)
 			Message: "Failed to get workspace resource.",
 			Detail:  err.Error(),
 		})
-		return
+		return agentsdk.ExternalAuthResponse{}, false
 	}
 	build, err := api.Database.GetWorkspaceBuildByJobID(ctx, resource.JobID)
 	if err != nil {
@@ -2043,7 +2223,7 @@ func (api *API) workspaceAgentsGitAuth(rw http.ResponseWriter, r *http.Request)
 			Message: "Failed to get build.",
 			Detail:  err.Error(),
 		})
-		return
+		return agentsdk.ExternalAuthResponse{}, false
 	}
 	workspace, err := api.Database.GetWorkspaceByID(ctx, build.WorkspaceID)
 	if err != nil {
@@ -2051,7 +2231,7 @@ func (api *API) workspaceAgentsGitAuth(rw http.ResponseWriter, r *http.Request)
 			Message: "Failed to get workspace.",
 			Detail:  err.Error(),
 		})
-		return
+		return agentsdk.ExternalAuthResponse{}, false
 	}
 
 	if listen {
@@ -2062,11 +2242,11 @@ func (api *API) workspaceAgentsGitAuth(rw http.ResponseWriter, r *http.Request)
 		for {
 			select {
 			case <-ctx.Done():
-				return
+				return agentsdk.ExternalAuthResponse{}, false
 			case <-ticker.C:
 			}
 			gitAuthLink, err := api.Database.GetGitAuthLink(ctx, database.GetGitAuthLinkParams{
-				ProviderID: gitAuthConfig.ID,
+				ProviderID: config.ID,
 				UserID:     workspace.OwnerID,
 			})
 			if err != nil {
@@ -2077,7 +2257,7 @@ func (api *API) workspaceAgentsGitAuth(rw http.ResponseWriter, r *http.Request)
 					Message: "Failed to get git auth link.",
 					Detail:  err.Error(),
 				})
-				return
+				return agentsdk.ExternalAuthResponse{}, false
 			}
 
 			// Expiry may be unset if the application doesn't configure tokens
@@ -2087,34 +2267,33 @@ func (api *API) workspaceAgentsGitAuth(rw http.ResponseWriter, r *http.Request)
 			if gitAuthLink.OAuthExpiry.Before(dbtime.Now()) && !gitAuthLink.OAuthExpiry.IsZero() {
 				continue
 			}
-			valid, _, err := gitAuthConfig.ValidateToken(ctx, gitAuthLink.OAuthAccessToken)
+			valid, _, err := config.ValidateToken(ctx, gitAuthLink.OAuthAccessToken)
 			if err != nil {
 				api.Logger.Warn(ctx, "failed to validate git auth token",
 					slog.F("workspace_owner_id", workspace.OwnerID.String()),
-					slog.F("validate_url", gitAuthConfig.ValidateURL),
+					slog.F("validate_url", config.ValidateURL),
 					slog.Error(err),
 				)
 			}
 			if !valid {
 				continue
 			}
-			httpapi.Write(ctx, rw, http.StatusOK, formatGitAuthAccessToken(gitAuthConfig.Type, gitAuthLink.OAuthAccessToken))
-			return
+			return formatExternalAuthAccessToken(config.Type, gitAuthLink.OAuthAccessToken), true
 		}
 	}
 
 	// This is the URL that will redirect the user with a state token.
-	redirectURL, err := api.AccessURL.Parse(fmt.Sprintf("/gitauth/%s", gitAuthConfig.ID))
+	redirectURL, err := api.AccessURL.Parse(fmt.Sprintf("/gitauth/%s", config.ID))
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
 			Message: "Failed to parse access URL.",
 			Detail:  err.Error(),
 		})
-		return
+		return agentsdk.ExternalAuthResponse{}, false
 	}
 
 	gitAuthLink, err := api.Database.GetGitAuthLink(ctx, database.GetGitAuthLinkParams{
-		ProviderID: gitAuthConfig.ID,
+		ProviderID: config.ID,
 		UserID:     workspace.OwnerID,
 	})
 	if err != nil {
@@ -2123,52 +2302,49 @@ func (api *API) workspaceAgentsGitAuth(rw http.ResponseWriter, r *http.Request)
 				Message: "Failed to get git auth link.",
 				Detail:  err.Error(),
 			})
-			return
+			return agentsdk.ExternalAuthResponse{}, false
 		}
 
-		httpapi.Write(ctx, rw, http.StatusOK, agentsdk.GitAuthResponse{
-			URL: redirectURL.String(),
-		})
-		return
+		return agentsdk.ExternalAuthResponse{
+			Type: string(config.Type),
+			URL:  redirectURL.String(),
+		}, true
 	}
 
-	gitAuthLink, updated, err := gitAuthConfig.RefreshToken(ctx, api.Database, gitAuthLink)
+	gitAuthLink, updated, err := config.RefreshToken(ctx, api.Database, gitAuthLink)
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
 			Message: "Failed to refresh git auth token.",
 			Detail:  err.Error(),
 		})
-		return
+		return agentsdk.ExternalAuthResponse{}, false
 	}
 	if !updated {
-		httpapi.Write(ctx, rw, http.StatusOK, agentsdk.GitAuthResponse{
-			URL: redirectURL.String(),
-		})
-		return
+		return agentsdk.ExternalAuthResponse{
+			Type: string(config.Type),
+			URL:  redirectURL.String(),
+		}, true
 	}
-	httpapi.Write(ctx, rw, http.StatusOK, formatGitAuthAccessToken(gitAuthConfig.Type, gitAuthLink.OAuthAccessToken))
+	return formatExternalAuthAccessToken(config.Type, gitAuthLink.OAuthAccessToken), true
 }
 
 // Provider types have different username/password formats.
-func formatGitAuthAccessToken(typ codersdk.GitProvider, token string) agentsdk.GitAuthResponse {
-	var resp agentsdk.GitAuthResponse
+func formatExternalAuthAccessToken(typ codersdk.GitProvider, token string) agentsdk.ExternalAuthResponse {
+	resp := agentsdk.ExternalAuthResponse{
+		AccessToken: token,
+		Type:        string(typ),
+	}
 	switch typ {
 	case codersdk.GitProviderGitLab:
 		// https://stackoverflow.com/questions/25409700/using-gitlab-token-to-clone-without-authentication
-		resp = agentsdk.GitAuthResponse{
-			Username: "oauth2",
-			Password: token,
-		}
+		resp.Username = "oauth2"
+		resp.Password = token
 	case codersdk.GitProviderBitBucket:
 		// https://support.atlassian.com/bitbucket-cloud/docs/use-oauth-on-bitbucket-cloud/#Cloning-a-repository-with-an-access-token
-		resp = agentsdk.GitAuthResponse{
-			Username: "x-token-auth",
-			Password: token,
-		}
+		resp.Username = "x-token-auth"
+		resp.Password = token
 	default:
-		resp = agentsdk.GitAuthResponse{
-			Username: token,
-		}
+		resp.Username = token
 	}
 	return resp
 }
@@ -845,6 +845,12 @@ func (api *API) convertWorkspaceBuild(
 	}
 	apiJob := convertProvisionerJob(job)
 	transition := codersdk.WorkspaceTransition(build.Transition)
+	if apiJob.Status == codersdk.ProvisionerJobPending {
+		buildTime := api.metricsCache.TemplateBuildTimeStats(workspace.TemplateID)[transition].P50
+		if buildTime != nil {
+			apiJob.EstimatedStartAt = estimateProvisionerJobStart(job, time.Duration(*buildTime)*time.Millisecond, dbtime.Now())
+		}
+	}
 	return codersdk.WorkspaceBuild{
 		ID:                  build.ID,
 		CreatedAt:           build.CreatedAt,
@@ -18,6 +18,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/db2sdk"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/parameter"
 	"github.com/coder/coder/v2/coderd/provisionerdserver"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/tracing"
@@ -513,27 +514,72 @@ func (b *Builder) getParameters() (names, values []string, err error) {
 	resolver := codersdk.ParameterResolver{
 		Rich: db2sdk.WorkspaceBuildParameters(lastBuildParameters),
 	}
+	tvps := make([]codersdk.TemplateVersionParameter, 0, len(templateVersionParameters))
 	for _, templateVersionParameter := range templateVersionParameters {
 		tvp, err := db2sdk.TemplateVersionParameter(templateVersionParameter)
 		if err != nil {
 			return nil, nil, BuildError{http.StatusInternalServerError, "failed to convert template version parameter", err}
 		}
+		tvps = append(tvps, tvp)
+	}
+	// Visibility conditions and validation rules are evaluated against the
+	// values this build would use, so that a parameter can depend on one that
+	// is declared after it.
+	evaluator := parameter.NewEvaluator(tvps, b.candidateParameterValues(tvps, resolver.Rich))
+	for _, tvp := range tvps {
+		visible, err := evaluator.Visible(tvp)
+		if err != nil {
+			return nil, nil, BuildError{http.StatusBadRequest, fmt.Sprintf("Unable to evaluate parameter %q", tvp.Name), err}
+		}
+		if !visible {
+			// Hidden parameters are not passed to the provisioner, just like
+			// Terraform omits a coder_parameter with a count of zero.
+			continue
+		}
 		value, err := resolver.ValidateResolve(
 			tvp,
-			b.findNewBuildParameterValue(templateVersionParameter.Name),
+			b.findNewBuildParameterValue(tvp.Name),
 		)
 		if err != nil {
 			// At this point, we've queried all the data we need from the database,
 			// so the only errors are problems with the request (missing data, failed
 			// validation, immutable parameters, etc.)
-			return nil, nil, BuildError{http.StatusBadRequest, fmt.Sprintf("Unable to validate parameter %q", templateVersionParameter.Name), err}
+			return nil, nil, BuildError{http.StatusBadRequest, fmt.Sprintf("Unable to validate parameter %q", tvp.Name), err}
 		}
-		names = append(names, templateVersionParameter.Name)
+		err = evaluator.Validate(tvp, value)
+		if err != nil {
+			return nil, nil, BuildError{http.StatusBadRequest, fmt.Sprintf("Unable to validate parameter %q", tvp.Name), err}
+		}
+		names = append(names, tvp.Name)
 		values = append(values, value)
 	}
 	return names, values, nil
 }
 
+// candidateParameterValues returns the value each parameter would take
+// without validation: the new value, then the value from the last build for
+// non-ephemeral parameters, then the default.
+func (b *Builder) candidateParameterValues(tvps []codersdk.TemplateVersionParameter, lastBuildParameters []codersdk.WorkspaceBuildParameter) map[string]string {
+	values := make(map[string]string, len(tvps))
+	for _, tvp := range tvps {
+		values[tvp.Name] = tvp.DefaultValue
+		if tvp.Ephemeral {
+			continue
+		}
+		for _, p := range lastBuildParameters {
+			if p.Name == tvp.Name {
+				values[tvp.Name] = p.Value
+			}
+		}
+	}
+	for _, p := range b.richParameterValues {
+		if _, ok := values[p.Name]; ok {
+			values[p.Name] = p.Value
+		}
+	}
+	return values
+}
+
 func (b *Builder) findNewBuildParameterValue(name string) *codersdk.WorkspaceBuildParameter {
 	for _, v := range b.richParameterValues {
 		if v.Name == name {
@@ -577,6 +577,108 @@ func TestWorkspaceBuildWithRichParameters(t *testing.T) {
 		_, _, err := uut.Build(ctx, mDB, nil)
 		req.NoError(err)
 	})
+
+	t.Run("HiddenParameterIsSkipped", func(t *testing.T) {
+		t.Parallel()
+
+		req := require.New(t)
+		asrt := assert.New(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		// new template revision
+		const hiddenParameterName = "hidden_parameter"
+		version2params := []database.TemplateVersionParameter{
+			{Name: firstParameterName, Description: firstParameterDescription, Mutable: true, Options: json.RawMessage("[]")},
+			{Name: secondParameterName, Description: secondParameterDescription, Mutable: true, Options: json.RawMessage("[]")},
+			{Name: immutableParameterName, Description: immutableParameterDescription, Mutable: false, Options: json.RawMessage("[]")},
+			{Name: hiddenParameterName, Mutable: false, Required: true, Options: json.RawMessage("[]"), VisibleIf: `parameters["first_parameter"] == "gpu"`},
+		}
+
+		expectedParams := map[string]string{
+			firstParameterName:     firstParameterValue,
+			secondParameterName:    secondParameterValue,
+			immutableParameterName: immutableParameterValue,
+		}
+
+		mDB := expectDB(t,
+			// Inputs
+			withTemplate,
+			withActiveVersion(version2params),
+			withLastBuildFound,
+			withRichParameters(initialBuildParameters),
+			withParameterSchemas(activeJobID, nil),
+
+			// Outputs
+			expectProvisionerJob(func(job database.InsertProvisionerJobParams) {}),
+			withInTx,
+			expectBuild(func(bld database.InsertWorkspaceBuildParams) {}),
+			expectBuildParameters(func(params database.InsertWorkspaceBuildParametersParams) {
+				asrt.Len(params.Name, len(expectedParams))
+				for i := range params.Name {
+					value, ok := expectedParams[params.Name[i]]
+					asrt.True(ok, "unexpected name %s", params.Name[i])
+					asrt.Equal(value, params.Value[i])
+				}
+			}),
+			withBuild,
+		)
+
+		ws := database.Workspace{ID: workspaceID, TemplateID: templateID, OwnerID: userID}
+		uut := wsbuilder.New(ws, database.WorkspaceTransitionStart).VersionID(activeVersionID)
+		_, _, err := uut.Build(ctx, mDB, nil)
+		req.NoError(err)
+	})
+
+	t.Run("ValidationRuleFails", func(t *testing.T) {
+		t.Parallel()
+
+		req := require.New(t)
+		asrt := assert.New(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		// new template revision
+		version2params := []database.TemplateVersionParameter{
+			{Name: firstParameterName, Description: firstParameterDescription, Mutable: true, Options: json.RawMessage("[]")},
+			{
+				Name: secondParameterName, Description: secondParameterDescription, Mutable: true, Options: json.RawMessage("[]"),
+				ValidationRules: json.RawMessage(`[{"condition":"self.value != parameters[\"first_parameter\"]","error":"must differ from the first parameter"}]`),
+			},
+			{Name: immutableParameterName, Description: immutableParameterDescription, Mutable: false, Options: json.RawMessage("[]")},
+		}
+
+		nextBuildParameters := []codersdk.WorkspaceBuildParameter{
+			{Name: secondParameterName, Value: firstParameterValue},
+		}
+
+		mDB := expectDB(t,
+			// Inputs
+			withTemplate,
+			withActiveVersion(version2params),
+			withLastBuildFound,
+			withRichParameters(initialBuildParameters),
+			withParameterSchemas(activeJobID, nil),
+
+			// Outputs
+			expectProvisionerJob(func(job database.InsertProvisionerJobParams) {}),
+			withInTx,
+			expectBuild(func(bld database.InsertWorkspaceBuildParams) {}),
+			// no build parameters, since we hit an error validating.
+		)
+
+		ws := database.Workspace{ID: workspaceID, TemplateID: templateID, OwnerID: userID}
+		uut := wsbuilder.New(ws, database.WorkspaceTransitionStart).
+			RichParameterValues(nextBuildParameters).
+			VersionID(activeVersionID)
+		_, _, err := uut.Build(ctx, mDB, nil)
+		bldErr := wsbuilder.BuildError{}
+		req.ErrorAs(err, &bldErr)
+		asrt.Equal(http.StatusBadRequest, bldErr.Status)
+		asrt.ErrorContains(err, "must differ from the first parameter")
+	})
 }
 
 type txExpect func(mTx *dbmock.MockStore)
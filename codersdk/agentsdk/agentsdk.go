@@ -9,7 +9,9 @@ import (
 	"net/http"
 	"net/http/cookiejar"
 	"net/url"
+	"path"
 	"strconv"
+	"strings"
 	"time"
 
 	"cloud.google.com/go/compute/metadata"
@@ -42,8 +44,36 @@ func (c *Client) SetSessionToken(token string) {
 }
 
 type GitSSHKey struct {
+	Name       string `json:"name"`
 	PublicKey  string `json:"public_key"`
 	PrivateKey string `json:"private_key"`
+	// Hosts are patterns of the hosts the key is offered to. The key is
+	// offered to every host if it's empty.
+	Hosts []string `json:"hosts"`
+}
+
+// MatchesHost returns whether the key should be offered to the host. Like
+// the Host keyword of ssh_config, a host must match at least one pattern
+// and none of the negated patterns.
+func (k GitSSHKey) MatchesHost(host string) bool {
+	if len(k.Hosts) == 0 {
+		return true
+	}
+	host = strings.ToLower(host)
+	matched := false
+	for _, pattern := range k.Hosts {
+		negated := strings.HasPrefix(pattern, "!")
+		pattern = strings.ToLower(strings.TrimPrefix(pattern, "!"))
+		ok, err := path.Match(pattern, host)
+		if err != nil || !ok {
+			continue
+		}
+		if negated {
+			return false
+		}
+		matched = true
+	}
+	return matched
 }
 
 // GitSSHKey will return the user's SSH key pair for the workspace.
@@ -62,6 +92,22 @@ func (c *Client) GitSSHKey(ctx context.Context) (GitSSHKey, error) {
 	return gitSSHKey, json.NewDecoder(res.Body).Decode(&gitSSHKey)
 }
 
+// GitSSHKeys will return all of the user's SSH key pairs for the workspace.
+func (c *Client) GitSSHKeys(ctx context.Context) ([]GitSSHKey, error) {
+	res, err := c.SDK.Request(ctx, http.MethodGet, "/api/v2/workspaceagents/me/gitsshkeys", nil)
+	if err != nil {
+		return nil, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, codersdk.ReadBodyAsError(res)
+	}
+
+	var gitSSHKeys []GitSSHKey
+	return gitSSHKeys, json.NewDecoder(res.Body).Decode(&gitSSHKeys)
+}
+
 // In the future, we may want to support sending back multiple values for
 // performance.
 type PostMetadataRequest = codersdk.WorkspaceAgentMetadataResult
@@ -99,6 +145,7 @@ type Manifest struct {
 	ShutdownScriptTimeout    time.Duration                                `json:"shutdown_script_timeout"`
 	DisableDirectConnections bool                                         `json:"disable_direct_connections"`
 	Metadata                 []codersdk.WorkspaceAgentMetadataDescription `json:"metadata"`
+	EgressPolicy             codersdk.EgressPolicy                        `json:"egress_policy"`
 }
 
 // Manifest fetches manifest for the currently authenticated workspace agent.
@@ -700,6 +747,55 @@ func (c *Client) GitAuth(ctx context.Context, gitURL string, listen bool) (GitAu
 	return authResp, json.NewDecoder(res.Body).Decode(&authResp)
 }
 
+type ExternalAuthRequest struct {
+	// ID is the ID of the external auth provider. The template of the
+	// workspace must require it.
+	ID string
+	// Match is a Git URL to match against the Git providers. Only one of ID
+	// or Match may be set.
+	Match string
+	// Listen waits for the workspace owner to authenticate if no valid
+	// token exists.
+	Listen bool
+}
+
+type ExternalAuthResponse struct {
+	// AccessToken is empty if the workspace owner must authenticate at URL.
+	AccessToken string `json:"access_token"`
+	Type        string `json:"type"`
+	URL         string `json:"url"`
+	// Username and Password are the credentials to use for Git operations.
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// ExternalAuth fetches the access token of the workspace owner for an
+// external auth provider.
+func (c *Client) ExternalAuth(ctx context.Context, req ExternalAuthRequest) (ExternalAuthResponse, error) {
+	q := url.Values{}
+	if req.ID != "" {
+		q.Set("id", req.ID)
+	}
+	if req.Match != "" {
+		q.Set("match", req.Match)
+	}
+	if req.Listen {
+		q.Set("listen", "true")
+	}
+	res, err := c.SDK.Request(ctx, http.MethodGet, "/api/v2/workspaceagents/me/external-auth?"+q.Encode(), nil)
+	if err != nil {
+		return ExternalAuthResponse{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return ExternalAuthResponse{}, codersdk.ReadBodyAsError(res)
+	}
+
+	var authResp ExternalAuthResponse
+	return authResp, json.NewDecoder(res.Body).Decode(&authResp)
+}
+
 type closeFunc func() error
 
 func (c closeFunc) Close() error {
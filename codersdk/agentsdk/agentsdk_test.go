@@ -0,0 +1,55 @@
+package agentsdk_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+)
+
+func TestGitSSHKeyMatchesHost(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		Name  string
+		Hosts []string
+		Host  string
+		Match bool
+	}{{
+		Name:  "NoPatterns",
+		Host:  "github.com",
+		Match: true,
+	}, {
+		Name:  "Exact",
+		Hosts: []string{"github.com"},
+		Host:  "GitHub.com",
+		Match: true,
+	}, {
+		Name:  "Wildcard",
+		Hosts: []string{"gitlab.com", "*.github.com"},
+		Host:  "ssh.github.com",
+		Match: true,
+	}, {
+		Name:  "NoMatch",
+		Hosts: []string{"*.github.com"},
+		Host:  "github.com",
+		Match: false,
+	}, {
+		Name:  "Negated",
+		Hosts: []string{"*.example.com", "!git.example.com"},
+		Host:  "git.example.com",
+		Match: false,
+	}, {
+		Name:  "OnlyNegated",
+		Hosts: []string{"!git.example.com"},
+		Host:  "github.com",
+		Match: false,
+	}} {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			key := agentsdk.GitSSHKey{Hosts: tc.Hosts}
+			require.Equal(t, tc.Match, key.MatchesHost(tc.Host))
+		})
+	}
+}
@@ -12,14 +12,20 @@ import (
 
 // APIKey: do not ever return the HashedSecret
 type APIKey struct {
-	ID              string      `json:"id" validate:"required"`
-	UserID          uuid.UUID   `json:"user_id" validate:"required" format:"uuid"`
-	LastUsed        time.Time   `json:"last_used" validate:"required" format:"date-time"`
-	ExpiresAt       time.Time   `json:"expires_at" validate:"required" format:"date-time"`
-	CreatedAt       time.Time   `json:"created_at" validate:"required" format:"date-time"`
-	UpdatedAt       time.Time   `json:"updated_at" validate:"required" format:"date-time"`
-	LoginType       LoginType   `json:"login_type" validate:"required" enums:"password,github,oidc,token"`
-	Scope           APIKeyScope `json:"scope" validate:"required" enums:"all,application_connect"`
+	ID        string      `json:"id" validate:"required"`
+	UserID    uuid.UUID   `json:"user_id" validate:"required" format:"uuid"`
+	LastUsed  time.Time   `json:"last_used" validate:"required" format:"date-time"`
+	ExpiresAt time.Time   `json:"expires_at" validate:"required" format:"date-time"`
+	CreatedAt time.Time   `json:"created_at" validate:"required" format:"date-time"`
+	UpdatedAt time.Time   `json:"updated_at" validate:"required" format:"date-time"`
+	LoginType LoginType   `json:"login_type" validate:"required" enums:"password,github,oidc,token,oauth2_provider_app,ldap"`
+	Scope     APIKeyScope `json:"scope" validate:"required" enums:"all,application_connect"`
+	// Scopes are the fine-grained scopes of the key. When set, they replace
+	// Scope.
+	Scopes []APIKeyScope `json:"scopes,omitempty" enums:"template:push,template:read,user:read,workspace:read,workspace:start"`
+	// AllowList limits the key to the listed resources, e.g. specific
+	// workspaces or templates. An empty list allows all resources.
+	AllowList       []uuid.UUID `json:"allow_list,omitempty" format:"uuid"`
 	TokenName       string      `json:"token_name" validate:"required"`
 	LifetimeSeconds int64       `json:"lifetime_seconds" validate:"required"`
 }
@@ -33,11 +39,21 @@ const (
 	LoginTypeGithub   LoginType = "github"
 	LoginTypeOIDC     LoginType = "oidc"
 	LoginTypeToken    LoginType = "token"
+	// LoginTypeOAuth2ProviderApp is used for API keys issued to OAuth2 apps
+	// that Coder is the authorization server for.
+	LoginTypeOAuth2ProviderApp LoginType = "oauth2_provider_app"
 	// LoginTypeNone is used if no login method is available for this user.
 	// If this is set, the user has no method of logging in.
 	// API keys can still be created by an owner and used by the user.
 	// These keys would use the `LoginTypeToken` type.
 	LoginTypeNone LoginType = "none"
+	// LoginTypeServiceAccount is used for non-human users created for CI and
+	// automation. Service accounts cannot log in, members of their owner
+	// group issue tokens for them instead.
+	LoginTypeServiceAccount LoginType = "service_account"
+	// LoginTypeLDAP is used for users that authenticate against an LDAP
+	// directory such as Active Directory.
+	LoginTypeLDAP LoginType = "ldap"
 )
 
 type APIKeyScope string
@@ -48,12 +64,26 @@ const (
 	// APIKeyScopeApplicationConnect is a scope that allows the user
 	// to connect to applications in a workspace.
 	APIKeyScopeApplicationConnect APIKeyScope = "application_connect"
+
+	// Fine-grained scopes can be combined on a single token, and may be
+	// limited to specific resources with an allow list.
+	APIKeyScopeUserRead       APIKeyScope = "user:read"
+	APIKeyScopeWorkspaceRead  APIKeyScope = "workspace:read"
+	APIKeyScopeWorkspaceStart APIKeyScope = "workspace:start"
+	APIKeyScopeTemplateRead   APIKeyScope = "template:read"
+	APIKeyScopeTemplatePush   APIKeyScope = "template:push"
 )
 
 type CreateTokenRequest struct {
 	Lifetime  time.Duration `json:"lifetime"`
 	Scope     APIKeyScope   `json:"scope" enums:"all,application_connect"`
 	TokenName string        `json:"token_name"`
+	// Scopes are fine-grained scopes to limit the token to. They cannot be
+	// combined with the application_connect scope.
+	Scopes []APIKeyScope `json:"scopes,omitempty" enums:"template:push,template:read,user:read,workspace:read,workspace:start"`
+	// AllowList limits the token to the listed resources, e.g. specific
+	// workspaces or templates. It requires at least one fine-grained scope.
+	AllowList []uuid.UUID `json:"allow_list,omitempty" format:"uuid"`
 }
 
 // GenerateAPIKeyResponse contains an API key for a user.
@@ -14,18 +14,22 @@ import (
 type ResourceType string
 
 const (
-	ResourceTypeTemplate        ResourceType = "template"
-	ResourceTypeTemplateVersion ResourceType = "template_version"
-	ResourceTypeUser            ResourceType = "user"
-	ResourceTypeWorkspace       ResourceType = "workspace"
-	ResourceTypeWorkspaceBuild  ResourceType = "workspace_build"
-	ResourceTypeGitSSHKey       ResourceType = "git_ssh_key"
-	ResourceTypeAPIKey          ResourceType = "api_key"
-	ResourceTypeGroup           ResourceType = "group"
-	ResourceTypeLicense         ResourceType = "license"
-	ResourceTypeConvertLogin    ResourceType = "convert_login"
-	ResourceTypeWorkspaceProxy  ResourceType = "workspace_proxy"
-	ResourceTypeOrganization    ResourceType = "organization"
+	ResourceTypeTemplate          ResourceType = "template"
+	ResourceTypeTemplateVersion   ResourceType = "template_version"
+	ResourceTypeUser              ResourceType = "user"
+	ResourceTypeWorkspace         ResourceType = "workspace"
+	ResourceTypeWorkspaceBuild    ResourceType = "workspace_build"
+	ResourceTypeGitSSHKey         ResourceType = "git_ssh_key"
+	ResourceTypeAPIKey            ResourceType = "api_key"
+	ResourceTypeGroup             ResourceType = "group"
+	ResourceTypeLicense           ResourceType = "license"
+	ResourceTypeConvertLogin      ResourceType = "convert_login"
+	ResourceTypeWorkspaceProxy    ResourceType = "workspace_proxy"
+	ResourceTypeOrganization      ResourceType = "organization"
+	ResourceTypeOAuth2ProviderApp ResourceType = "oauth2_provider_app"
+	// nolint:gosec // This is not a secret.
+	ResourceTypeOAuth2ProviderAppSecret ResourceType = "oauth2_provider_app_secret"
+	ResourceTypeUserMFA                 ResourceType = "user_mfa"
 )
 
 func (r ResourceType) FriendlyString() string {
@@ -56,6 +60,12 @@ func (r ResourceType) FriendlyString() string {
 		return "workspace proxy"
 	case ResourceTypeOrganization:
 		return "organization"
+	case ResourceTypeOAuth2ProviderApp:
+		return "oauth2 app"
+	case ResourceTypeOAuth2ProviderAppSecret:
+		return "oauth2 app secret"
+	case ResourceTypeUserMFA:
+		return "multi-factor authentication"
 	default:
 		return "unknown"
 	}
@@ -0,0 +1,93 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+type TailnetPeerType string
+
+const (
+	TailnetPeerTypeAgent  TailnetPeerType = "agent"
+	TailnetPeerTypeClient TailnetPeerType = "client"
+)
+
+// TailnetPath is how packets reach a peer.
+type TailnetPath string
+
+const (
+	// TailnetPathDirect is a peer-to-peer path.
+	TailnetPathDirect TailnetPath = "direct"
+	// TailnetPathDERP is a path relayed by a DERP server.
+	TailnetPathDERP TailnetPath = "derp"
+)
+
+// TailnetStatus is the connection graph of the tailnet coordinator.
+type TailnetStatus struct {
+	// HA is true if the coordinator is shared by replicas. Peers and tunnels
+	// of every replica are included.
+	HA bool `json:"ha"`
+	// ReplicaID is the replica that served the request. Connections of peers
+	// are those of this replica.
+	ReplicaID uuid.UUID       `json:"replica_id" format:"uuid"`
+	Peers     []TailnetPeer   `json:"peers"`
+	Tunnels   []TailnetTunnel `json:"tunnels"`
+}
+
+type TailnetPeer struct {
+	ID   uuid.UUID       `json:"id" format:"uuid"`
+	Name string          `json:"name"`
+	Type TailnetPeerType `json:"type" enums:"agent,client"`
+	// CoordinatorID is the replica the peer is coordinated by. It's omitted if
+	// the coordinator isn't shared by replicas.
+	CoordinatorID *uuid.UUID `json:"coordinator_id,omitempty" format:"uuid"`
+	// Connected is false for agents that clients have tunnels to, but that
+	// aren't connected to the coordinator.
+	Connected     bool     `json:"connected"`
+	PreferredDERP int      `json:"preferred_derp"`
+	Endpoints     []string `json:"endpoints"`
+	// LastNodeUpdateAt is the last time the peer sent its node to the
+	// coordinator.
+	LastNodeUpdateAt *time.Time `json:"last_node_update_at,omitempty" format:"date-time"`
+	// Connection is the connection of the replica that served the request to
+	// the peer, if it has one.
+	Connection *TailnetConnection `json:"connection,omitempty"`
+}
+
+type TailnetConnection struct {
+	Path TailnetPath `json:"path" enums:"direct,derp"`
+	// Address is the address of the peer if the path is direct.
+	Address string `json:"address,omitempty"`
+	// Relay is the DERP region that relays packets to the peer.
+	Relay           string     `json:"relay,omitempty"`
+	LastHandshakeAt *time.Time `json:"last_handshake_at,omitempty" format:"date-time"`
+	RxBytes         int64      `json:"rx_bytes"`
+	TxBytes         int64      `json:"tx_bytes"`
+}
+
+// TailnetTunnel is a tunnel from a client to an agent.
+type TailnetTunnel struct {
+	Src uuid.UUID `json:"src" format:"uuid"`
+	Dst uuid.UUID `json:"dst" format:"uuid"`
+}
+
+// DebugTailnetStatus returns the connection graph of the tailnet coordinator.
+func (c *Client) DebugTailnetStatus(ctx context.Context) (TailnetStatus, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/debug/tailnet", nil)
+	if err != nil {
+		return TailnetStatus{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return TailnetStatus{}, ReadBodyAsError(res)
+	}
+
+	var status TailnetStatus
+	return status, json.NewDecoder(res.Body).Decode(&status)
+}
@@ -129,51 +129,55 @@ type DeploymentValues struct {
 	DocsURL             clibase.URL  `json:"docs_url,omitempty"`
 	RedirectToAccessURL clibase.Bool `json:"redirect_to_access_url,omitempty"`
 	// HTTPAddress is a string because it may be set to zero to disable.
-	HTTPAddress                     clibase.String                  `json:"http_address,omitempty" typescript:",notnull"`
-	AutobuildPollInterval           clibase.Duration                `json:"autobuild_poll_interval,omitempty"`
-	JobHangDetectorInterval         clibase.Duration                `json:"job_hang_detector_interval,omitempty"`
-	DERP                            DERP                            `json:"derp,omitempty" typescript:",notnull"`
-	Prometheus                      PrometheusConfig                `json:"prometheus,omitempty" typescript:",notnull"`
-	Pprof                           PprofConfig                     `json:"pprof,omitempty" typescript:",notnull"`
-	ProxyTrustedHeaders             clibase.StringArray             `json:"proxy_trusted_headers,omitempty" typescript:",notnull"`
-	ProxyTrustedOrigins             clibase.StringArray             `json:"proxy_trusted_origins,omitempty" typescript:",notnull"`
-	CacheDir                        clibase.String                  `json:"cache_directory,omitempty" typescript:",notnull"`
-	InMemoryDatabase                clibase.Bool                    `json:"in_memory_database,omitempty" typescript:",notnull"`
-	PostgresURL                     clibase.String                  `json:"pg_connection_url,omitempty" typescript:",notnull"`
-	OAuth2                          OAuth2Config                    `json:"oauth2,omitempty" typescript:",notnull"`
-	OIDC                            OIDCConfig                      `json:"oidc,omitempty" typescript:",notnull"`
-	Telemetry                       TelemetryConfig                 `json:"telemetry,omitempty" typescript:",notnull"`
-	TLS                             TLSConfig                       `json:"tls,omitempty" typescript:",notnull"`
-	Trace                           TraceConfig                     `json:"trace,omitempty" typescript:",notnull"`
-	SecureAuthCookie                clibase.Bool                    `json:"secure_auth_cookie,omitempty" typescript:",notnull"`
-	StrictTransportSecurity         clibase.Int64                   `json:"strict_transport_security,omitempty" typescript:",notnull"`
-	StrictTransportSecurityOptions  clibase.StringArray             `json:"strict_transport_security_options,omitempty" typescript:",notnull"`
-	SSHKeygenAlgorithm              clibase.String                  `json:"ssh_keygen_algorithm,omitempty" typescript:",notnull"`
-	MetricsCacheRefreshInterval     clibase.Duration                `json:"metrics_cache_refresh_interval,omitempty" typescript:",notnull"`
-	AgentStatRefreshInterval        clibase.Duration                `json:"agent_stat_refresh_interval,omitempty" typescript:",notnull"`
-	AgentFallbackTroubleshootingURL clibase.URL                     `json:"agent_fallback_troubleshooting_url,omitempty" typescript:",notnull"`
-	BrowserOnly                     clibase.Bool                    `json:"browser_only,omitempty" typescript:",notnull"`
-	SCIMAPIKey                      clibase.String                  `json:"scim_api_key,omitempty" typescript:",notnull"`
-	Provisioner                     ProvisionerConfig               `json:"provisioner,omitempty" typescript:",notnull"`
-	RateLimit                       RateLimitConfig                 `json:"rate_limit,omitempty" typescript:",notnull"`
-	Experiments                     clibase.StringArray             `json:"experiments,omitempty" typescript:",notnull"`
-	UpdateCheck                     clibase.Bool                    `json:"update_check,omitempty" typescript:",notnull"`
-	MaxTokenLifetime                clibase.Duration                `json:"max_token_lifetime,omitempty" typescript:",notnull"`
-	Swagger                         SwaggerConfig                   `json:"swagger,omitempty" typescript:",notnull"`
-	Logging                         LoggingConfig                   `json:"logging,omitempty" typescript:",notnull"`
-	Dangerous                       DangerousConfig                 `json:"dangerous,omitempty" typescript:",notnull"`
-	DisablePathApps                 clibase.Bool                    `json:"disable_path_apps,omitempty" typescript:",notnull"`
-	SessionDuration                 clibase.Duration                `json:"max_session_expiry,omitempty" typescript:",notnull"`
-	DisableSessionExpiryRefresh     clibase.Bool                    `json:"disable_session_expiry_refresh,omitempty" typescript:",notnull"`
-	DisablePasswordAuth             clibase.Bool                    `json:"disable_password_auth,omitempty" typescript:",notnull"`
-	Support                         SupportConfig                   `json:"support,omitempty" typescript:",notnull"`
-	GitAuthProviders                clibase.Struct[[]GitAuthConfig] `json:"git_auth,omitempty" typescript:",notnull"`
-	SSHConfig                       SSHConfig                       `json:"config_ssh,omitempty" typescript:",notnull"`
-	WgtunnelHost                    clibase.String                  `json:"wgtunnel_host,omitempty" typescript:",notnull"`
-	DisableOwnerWorkspaceExec       clibase.Bool                    `json:"disable_owner_workspace_exec,omitempty" typescript:",notnull"`
-	ProxyHealthStatusInterval       clibase.Duration                `json:"proxy_health_status_interval,omitempty" typescript:",notnull"`
-	EnableTerraformDebugMode        clibase.Bool                    `json:"enable_terraform_debug_mode,omitempty" typescript:",notnull"`
-	UserQuietHoursSchedule          UserQuietHoursScheduleConfig    `json:"user_quiet_hours_schedule,omitempty" typescript:",notnull"`
+	HTTPAddress                     clibase.String                             `json:"http_address,omitempty" typescript:",notnull"`
+	AutobuildPollInterval           clibase.Duration                           `json:"autobuild_poll_interval,omitempty"`
+	JobHangDetectorInterval         clibase.Duration                           `json:"job_hang_detector_interval,omitempty"`
+	DERP                            DERP                                       `json:"derp,omitempty" typescript:",notnull"`
+	Prometheus                      PrometheusConfig                           `json:"prometheus,omitempty" typescript:",notnull"`
+	Pprof                           PprofConfig                                `json:"pprof,omitempty" typescript:",notnull"`
+	ProxyTrustedHeaders             clibase.StringArray                        `json:"proxy_trusted_headers,omitempty" typescript:",notnull"`
+	ProxyTrustedOrigins             clibase.StringArray                        `json:"proxy_trusted_origins,omitempty" typescript:",notnull"`
+	CacheDir                        clibase.String                             `json:"cache_directory,omitempty" typescript:",notnull"`
+	InMemoryDatabase                clibase.Bool                               `json:"in_memory_database,omitempty" typescript:",notnull"`
+	PostgresURL                     clibase.String                             `json:"pg_connection_url,omitempty" typescript:",notnull"`
+	OAuth2                          OAuth2Config                               `json:"oauth2,omitempty" typescript:",notnull"`
+	OIDC                            OIDCConfig                                 `json:"oidc,omitempty" typescript:",notnull"`
+	LDAP                            LDAPConfig                                 `json:"ldap,omitempty" typescript:",notnull"`
+	Telemetry                       TelemetryConfig                            `json:"telemetry,omitempty" typescript:",notnull"`
+	TLS                             TLSConfig                                  `json:"tls,omitempty" typescript:",notnull"`
+	Trace                           TraceConfig                                `json:"trace,omitempty" typescript:",notnull"`
+	SecureAuthCookie                clibase.Bool                               `json:"secure_auth_cookie,omitempty" typescript:",notnull"`
+	StrictTransportSecurity         clibase.Int64                              `json:"strict_transport_security,omitempty" typescript:",notnull"`
+	StrictTransportSecurityOptions  clibase.StringArray                        `json:"strict_transport_security_options,omitempty" typescript:",notnull"`
+	SSHKeygenAlgorithm              clibase.String                             `json:"ssh_keygen_algorithm,omitempty" typescript:",notnull"`
+	MetricsCacheRefreshInterval     clibase.Duration                           `json:"metrics_cache_refresh_interval,omitempty" typescript:",notnull"`
+	AgentStatRefreshInterval        clibase.Duration                           `json:"agent_stat_refresh_interval,omitempty" typescript:",notnull"`
+	AgentFallbackTroubleshootingURL clibase.URL                                `json:"agent_fallback_troubleshooting_url,omitempty" typescript:",notnull"`
+	BrowserOnly                     clibase.Bool                               `json:"browser_only,omitempty" typescript:",notnull"`
+	SCIMAPIKey                      clibase.String                             `json:"scim_api_key,omitempty" typescript:",notnull"`
+	Provisioner                     ProvisionerConfig                          `json:"provisioner,omitempty" typescript:",notnull"`
+	RateLimit                       RateLimitConfig                            `json:"rate_limit,omitempty" typescript:",notnull"`
+	Experiments                     clibase.StringArray                        `json:"experiments,omitempty" typescript:",notnull"`
+	UpdateCheck                     clibase.Bool                               `json:"update_check,omitempty" typescript:",notnull"`
+	MaxTokenLifetime                clibase.Duration                           `json:"max_token_lifetime,omitempty" typescript:",notnull"`
+	Swagger                         SwaggerConfig                              `json:"swagger,omitempty" typescript:",notnull"`
+	Logging                         LoggingConfig                              `json:"logging,omitempty" typescript:",notnull"`
+	Dangerous                       DangerousConfig                            `json:"dangerous,omitempty" typescript:",notnull"`
+	DisablePathApps                 clibase.Bool                               `json:"disable_path_apps,omitempty" typescript:",notnull"`
+	SessionDuration                 clibase.Duration                           `json:"max_session_expiry,omitempty" typescript:",notnull"`
+	DisableSessionExpiryRefresh     clibase.Bool                               `json:"disable_session_expiry_refresh,omitempty" typescript:",notnull"`
+	DisablePasswordAuth             clibase.Bool                               `json:"disable_password_auth,omitempty" typescript:",notnull"`
+	RequireMFA                      clibase.Bool                               `json:"require_mfa,omitempty" typescript:",notnull"`
+	Support                         SupportConfig                              `json:"support,omitempty" typescript:",notnull"`
+	GitAuthProviders                clibase.Struct[[]GitAuthConfig]            `json:"git_auth,omitempty" typescript:",notnull"`
+	WorkloadIdentityProviders       clibase.Struct[[]WorkloadIdentityProvider] `json:"workload_identity_providers,omitempty" typescript:",notnull"`
+	SSHConfig                       SSHConfig                                  `json:"config_ssh,omitempty" typescript:",notnull"`
+	WgtunnelHost                    clibase.String                             `json:"wgtunnel_host,omitempty" typescript:",notnull"`
+	DisableOwnerWorkspaceExec       clibase.Bool                               `json:"disable_owner_workspace_exec,omitempty" typescript:",notnull"`
+	ProxyHealthStatusInterval       clibase.Duration                           `json:"proxy_health_status_interval,omitempty" typescript:",notnull"`
+	ProxyVersionPolicy              clibase.String                             `json:"proxy_version_policy,omitempty" typescript:",notnull"`
+	EnableTerraformDebugMode        clibase.Bool                               `json:"enable_terraform_debug_mode,omitempty" typescript:",notnull"`
+	UserQuietHoursSchedule          UserQuietHoursScheduleConfig               `json:"user_quiet_hours_schedule,omitempty" typescript:",notnull"`
 
 	Config      clibase.YAMLConfigPath `json:"config,omitempty" typescript:",notnull"`
 	WriteConfig clibase.Bool           `json:"write_config,omitempty" typescript:",notnull"`
@@ -287,6 +291,55 @@ type OIDCConfig struct {
 	UserRolesDefault    clibase.StringArray                 `json:"user_roles_default" typescript:",notnull"`
 	SignInText          clibase.String                      `json:"sign_in_text" typescript:",notnull"`
 	IconURL             clibase.URL                         `json:"icon_url" typescript:",notnull"`
+	// Providers are additional OIDC providers users can sign in with.
+	Providers clibase.Struct[[]OIDCProviderConfig] `json:"providers" typescript:",notnull"`
+}
+
+type LDAPConfig struct {
+	URL               clibase.String                      `json:"url" typescript:",notnull"`
+	StartTLS          clibase.Bool                        `json:"start_tls" typescript:",notnull"`
+	TLSCAFile         clibase.String                      `json:"tls_ca_file" typescript:",notnull"`
+	BindDN            clibase.String                      `json:"bind_dn" typescript:",notnull"`
+	BindPassword      clibase.String                      `json:"bind_password" typescript:",notnull"`
+	BaseDN            clibase.String                      `json:"base_dn" typescript:",notnull"`
+	UserFilter        clibase.String                      `json:"user_filter" typescript:",notnull"`
+	UsernameAttribute clibase.String                      `json:"username_attribute" typescript:",notnull"`
+	EmailAttribute    clibase.String                      `json:"email_attribute" typescript:",notnull"`
+	GroupAttribute    clibase.String                      `json:"group_attribute" typescript:",notnull"`
+	GroupMapping      clibase.Struct[map[string]string]   `json:"group_mapping" typescript:",notnull"`
+	GroupAutoCreate   clibase.Bool                        `json:"group_auto_create" typescript:",notnull"`
+	UserRoleMapping   clibase.Struct[map[string][]string] `json:"user_role_mapping" typescript:",notnull"`
+	UserRolesDefault  clibase.StringArray                 `json:"user_roles_default" typescript:",notnull"`
+	AllowSignups      clibase.Bool                        `json:"allow_signups" typescript:",notnull"`
+	SyncInterval      clibase.Duration                    `json:"sync_interval" typescript:",notnull"`
+	SignInText        clibase.String                      `json:"sign_in_text" typescript:",notnull"`
+}
+
+// OIDCProviderConfig is an additional OIDC provider. Each provider has its
+// own login button and claim mapping, and users are linked to their account
+// by email across providers.
+type OIDCProviderConfig struct {
+	// ID identifies the provider in its callback URL and user links.
+	ID                  string              `json:"id" yaml:"id"`
+	IssuerURL           string              `json:"issuer_url" yaml:"issuerURL"`
+	ClientID            string              `json:"client_id" yaml:"clientID"`
+	ClientSecret        string              `json:"-" yaml:"clientSecret"`
+	Scopes              []string            `json:"scopes" yaml:"scopes"`
+	AuthURLParams       map[string]string   `json:"auth_url_params" yaml:"authURLParams"`
+	EmailDomain         []string            `json:"email_domain" yaml:"emailDomain"`
+	AllowSignups        bool                `json:"allow_signups" yaml:"allowSignups"`
+	IgnoreEmailVerified bool                `json:"ignore_email_verified" yaml:"ignoreEmailVerified"`
+	IgnoreUserInfo      bool                `json:"ignore_user_info" yaml:"ignoreUserInfo"`
+	UsernameField       string              `json:"username_field" yaml:"usernameField"`
+	EmailField          string              `json:"email_field" yaml:"emailField"`
+	GroupField          string              `json:"groups_field" yaml:"groupField"`
+	GroupMapping        map[string]string   `json:"group_mapping" yaml:"groupMapping"`
+	GroupAutoCreate     bool                `json:"group_auto_create" yaml:"enableGroupAutoCreate"`
+	UserRoleField       string              `json:"user_role_field" yaml:"userRoleField"`
+	UserRoleMapping     map[string][]string `json:"user_role_mapping" yaml:"userRoleMapping"`
+	UserRolesDefault    []string            `json:"user_roles_default" yaml:"userRoleDefault"`
+	SignInText          string              `json:"sign_in_text" yaml:"signInText"`
+	IconURL             string              `json:"icon_url" yaml:"iconURL"`
 }
 
 type TelemetryConfig struct {
@@ -315,6 +368,9 @@ type TraceConfig struct {
 	DataDog         clibase.Bool   `json:"data_dog" typescript:",notnull"`
 }
 
+// GitAuthConfig configures an external auth provider. Types other than the
+// GitProvider constants are generic OAuth2 providers, which require AuthURL
+// and TokenURL, and are only used for Git operations if Regex is set.
 type GitAuthConfig struct {
 	ID                  string   `json:"id"`
 	Type                string   `json:"type"`
@@ -330,6 +386,14 @@ type GitAuthConfig struct {
 	Scopes              []string `json:"scopes"`
 	DeviceFlow          bool     `json:"device_flow"`
 	DeviceCodeURL       string   `json:"device_code_url"`
+	// SSHKeysURL is an API endpoint that public SSH keys of the user are
+	// uploaded to. It defaults to the endpoint of GitHub or GitLab.
+	SSHKeysURL string `json:"ssh_keys_url"`
+	// DisplayName is shown to users when they authenticate. It defaults to
+	// the name of the provider type.
+	DisplayName string `json:"display_name"`
+	// DisplayIcon is a URL to an icon shown next to the display name.
+	DisplayIcon string `json:"display_icon"`
 }
 
 type ProvisionerConfig struct {
@@ -481,6 +545,10 @@ func (c *DeploymentValues) Options() clibase.OptionSet {
 			Name: "OIDC",
 			YAML: "oidc",
 		}
+		deploymentGroupLDAP = clibase.Group{
+			Name: "LDAP",
+			YAML: "ldap",
+		}
 		deploymentGroupTelemetry = clibase.Group{
 			Name: "Telemetry",
 			YAML: "telemetry",
@@ -1178,6 +1246,183 @@ when required by your organization's security policy.`,
 			Group:       &deploymentGroupOIDC,
 			YAML:        "iconURL",
 		},
+		{
+			Name:        "OIDC Providers",
+			Description: "Additional OIDC providers users can sign in with. Each provider has its own login button and claim mapping.",
+			Value:       &c.OIDC.Providers,
+			Group:       &deploymentGroupOIDC,
+			YAML:        "providers",
+			// Providers are only configurable with YAML.
+			Hidden: true,
+		},
+		// LDAP settings.
+		{
+			Name:        "LDAP URL",
+			Description: "URL of the LDAP server to authenticate users against, e.g. ldaps://ldap.example.com. LDAP authentication is disabled if empty.",
+			Flag:        "ldap-url",
+			Env:         "CODER_LDAP_URL",
+			Value:       &c.LDAP.URL,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "url",
+		},
+		{
+			Name:        "LDAP StartTLS",
+			Description: "Upgrade ldap:// connections to TLS with StartTLS before sending credentials. Required for ldap:// URLs.",
+			Flag:        "ldap-start-tls",
+			Env:         "CODER_LDAP_START_TLS",
+			Default:     "false",
+			Value:       &c.LDAP.StartTLS,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "startTLS",
+		},
+		{
+			Name:        "LDAP TLS CA File",
+			Description: "PEM encoded certificate authorities to verify the certificate of the LDAP server. The system certificate pool is used if empty.",
+			Flag:        "ldap-tls-ca-file",
+			Env:         "CODER_LDAP_TLS_CA_FILE",
+			Value:       &c.LDAP.TLSCAFile,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "tlsCAFile",
+		},
+		{
+			Name:        "LDAP Bind DN",
+			Description: "DN of the service account used to search for users and groups. The search is anonymous if empty.",
+			Flag:        "ldap-bind-dn",
+			Env:         "CODER_LDAP_BIND_DN",
+			Value:       &c.LDAP.BindDN,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "bindDN",
+		},
+		{
+			Name:        "LDAP Bind Password",
+			Description: "Password of the service account used to search for users and groups.",
+			Flag:        "ldap-bind-password",
+			Env:         "CODER_LDAP_BIND_PASSWORD",
+			Annotations: clibase.Annotations{}.Mark(annotationSecretKey, "true"),
+			Value:       &c.LDAP.BindPassword,
+			Group:       &deploymentGroupLDAP,
+		},
+		{
+			Name:        "LDAP Base DN",
+			Description: "DN of the subtree to search for users, e.g. DC=example,DC=com.",
+			Flag:        "ldap-base-dn",
+			Env:         "CODER_LDAP_BASE_DN",
+			Value:       &c.LDAP.BaseDN,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "baseDN",
+		},
+		{
+			Name:        "LDAP User Filter",
+			Description: "Filter that entries must match to be considered users. Users that no longer match are suspended by the directory sync.",
+			Flag:        "ldap-user-filter",
+			Env:         "CODER_LDAP_USER_FILTER",
+			Default:     "(objectClass=person)",
+			Value:       &c.LDAP.UserFilter,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "userFilter",
+		},
+		{
+			Name:        "LDAP Username Attribute",
+			Description: "Attribute that users log in with and that is used as their Coder username. Use sAMAccountName for Active Directory.",
+			Flag:        "ldap-username-attribute",
+			Env:         "CODER_LDAP_USERNAME_ATTRIBUTE",
+			Default:     "uid",
+			Value:       &c.LDAP.UsernameAttribute,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "usernameAttribute",
+		},
+		{
+			Name:        "LDAP Email Attribute",
+			Description: "Attribute that contains the email of users.",
+			Flag:        "ldap-email-attribute",
+			Env:         "CODER_LDAP_EMAIL_ATTRIBUTE",
+			Default:     "mail",
+			Value:       &c.LDAP.EmailAttribute,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "emailAttribute",
+		},
+		{
+			Name:        "LDAP Group Attribute",
+			Description: "Attribute of users and groups that lists the DNs of the groups they are a member of, e.g. memberOf. Nested groups are resolved. Group sync is disabled if empty.",
+			Flag:        "ldap-group-attribute",
+			Env:         "CODER_LDAP_GROUP_ATTRIBUTE",
+			// This value is intentionally blank. If this is empty, then LDAP
+			// group sync behavior is disabled.
+			Default: "",
+			Value:   &c.LDAP.GroupAttribute,
+			Group:   &deploymentGroupLDAP,
+			YAML:    "groupAttribute",
+		},
+		{
+			Name:        "LDAP Group Mapping",
+			Description: "A map of LDAP group common names and the group in Coder it should map to.",
+			Flag:        "ldap-group-mapping",
+			Env:         "CODER_LDAP_GROUP_MAPPING",
+			Default:     "{}",
+			Value:       &c.LDAP.GroupMapping,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "groupMapping",
+		},
+		{
+			Name:        "Enable LDAP Group Auto Create",
+			Description: "Automatically creates missing groups from a user's LDAP groups.",
+			Flag:        "ldap-group-auto-create",
+			Env:         "CODER_LDAP_GROUP_AUTO_CREATE",
+			Default:     "false",
+			Value:       &c.LDAP.GroupAutoCreate,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "enableGroupAutoCreate",
+		},
+		{
+			Name:        "LDAP User Role Mapping",
+			Description: "A map of LDAP group common names and the roles in Coder members of the group should have. Role sync is disabled if empty.",
+			Flag:        "ldap-user-role-mapping",
+			Env:         "CODER_LDAP_USER_ROLE_MAPPING",
+			Default:     "{}",
+			Value:       &c.LDAP.UserRoleMapping,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "userRoleMapping",
+		},
+		{
+			Name:        "LDAP User Role Default",
+			Description: "If user role sync is enabled, these roles are always included for all authenticated users. The 'member' role is always assigned.",
+			Flag:        "ldap-user-role-default",
+			Env:         "CODER_LDAP_USER_ROLE_DEFAULT",
+			Default:     "",
+			Value:       &c.LDAP.UserRolesDefault,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "userRoleDefault",
+		},
+		{
+			Name:        "LDAP Allow Signups",
+			Description: "Whether new users can sign up with LDAP.",
+			Flag:        "ldap-allow-signups",
+			Env:         "CODER_LDAP_ALLOW_SIGNUPS",
+			Default:     "true",
+			Value:       &c.LDAP.AllowSignups,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "allowSignups",
+		},
+		{
+			Name:        "LDAP Sync Interval",
+			Description: "How often users are synced with the directory. Users removed from the directory are suspended, and groups and roles are updated. Set to 0 to disable.",
+			Flag:        "ldap-sync-interval",
+			Env:         "CODER_LDAP_SYNC_INTERVAL",
+			Default:     time.Hour.String(),
+			Value:       &c.LDAP.SyncInterval,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "syncInterval",
+		},
+		{
+			Name:        "LDAP Sign In Text",
+			Description: "The text to show on the LDAP sign in form.",
+			Flag:        "ldap-sign-in-text",
+			Env:         "CODER_LDAP_SIGN_IN_TEXT",
+			Default:     "LDAP",
+			Value:       &c.LDAP.SignInText,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "signInText",
+		},
 		// Telemetry settings
 		{
 			Name:        "Telemetry Enable",
@@ -1656,6 +1901,16 @@ when required by your organization's security policy.`,
 			Group: &deploymentGroupNetworkingHTTP,
 			YAML:  "disablePasswordAuth",
 		},
+		{
+			Name:        "Require Multi-Factor Authentication",
+			Description: "Require users that sign in with a password to also enter a code from an authenticator app. Users that have not enrolled an authenticator are asked to enroll one the next time they sign in.",
+			Flag:        "require-mfa",
+			Env:         "CODER_REQUIRE_MFA",
+
+			Value: &c.RequireMFA,
+			Group: &deploymentGroupNetworkingHTTP,
+			YAML:  "requireMFA",
+		},
 		{
 			Name:          "Config Path",
 			Description:   `Specify a YAML file to load configuration from.`,
@@ -1718,6 +1973,14 @@ Write out the current server config as YAML to stdout.`,
 			Value:  &c.GitAuthProviders,
 			Hidden: true,
 		},
+		{
+			Name:        "Workload Identity Providers",
+			Description: "Trusted OIDC issuers, such as GitHub Actions or GitLab CI, whose tokens can be exchanged for short-lived session tokens.",
+			YAML:        "workloadIdentityProviders",
+			Value:       &c.WorkloadIdentityProviders,
+			// Providers are only configurable with YAML.
+			Hidden: true,
+		},
 		{
 			Name:        "Custom wgtunnel Host",
 			Description: `Hostname of HTTPS server that runs https://github.com/coder/wgtunnel. By default, this will pick the best available wgtunnel server hosted by Coder. e.g. "tunnel.example.com".`,
@@ -1738,6 +2001,16 @@ Write out the current server config as YAML to stdout.`,
 			Group:       &deploymentGroupNetworkingHTTP,
 			YAML:        "proxyHealthInterval",
 		},
+		{
+			Name:        "Proxy Version Policy",
+			Description: "What to do with workspace proxies whose version is incompatible with coderd. Proxies may be one minor version behind coderd. \"refuse\" rejects their registration, \"degrade\" only lets them serve DERP.",
+			Flag:        "proxy-version-policy",
+			Env:         "CODER_PROXY_VERSION_POLICY",
+			Default:     "refuse",
+			Value:       &c.ProxyVersionPolicy,
+			Group:       &deploymentGroupNetworkingHTTP,
+			YAML:        "proxyVersionPolicy",
+		},
 		{
 			Name:        "Default Quiet Hours Schedule",
 			Description: "The default daily cron schedule applied to users that haven't set a custom quiet hours schedule themselves. The quiet hours schedule determines when workspaces will be force stopped due to the template's max TTL, and will round the max TTL up to be within the user's quiet hours window (or default). The format is the same as the standard cron format, but the day-of-month, month and day-of-week must be *. Only one hour and minute can be specified (ranges or comma separated values are not supported).",
@@ -51,6 +51,9 @@ func TestDeploymentValues_HighlyConfigurable(t *testing.T) {
 		"OIDC Client Secret": {
 			yaml: true,
 		},
+		"LDAP Bind Password": {
+			yaml: true,
+		},
 		"Postgres Connection URL": {
 			yaml: true,
 		},
@@ -68,6 +71,14 @@ func TestDeploymentValues_HighlyConfigurable(t *testing.T) {
 			flag: true,
 			env:  true,
 		},
+		"Workload Identity Providers": {
+			flag: true,
+			env:  true,
+		},
+		"OIDC Providers": {
+			flag: true,
+			env:  true,
+		},
 	}
 
 	set := (&codersdk.DeploymentValues{}).Options()
@@ -0,0 +1,58 @@
+// Package drpc contains the transport shared by the dRPC clients of the
+// provisioner daemon and tailnet APIs.
+package drpc
+
+import (
+	"context"
+
+	"github.com/hashicorp/yamux"
+	"storj.io/drpc"
+	"storj.io/drpc/drpcconn"
+)
+
+// MultiplexedConn returns a multiplexed dRPC connection from a yamux Session.
+func MultiplexedConn(session *yamux.Session) drpc.Conn {
+	return &multiplexedDRPC{session}
+}
+
+// Allows concurrent requests on a single dRPC connection.
+// Required for calling functions concurrently.
+type multiplexedDRPC struct {
+	session *yamux.Session
+}
+
+func (m *multiplexedDRPC) Close() error {
+	return m.session.Close()
+}
+
+func (m *multiplexedDRPC) Closed() <-chan struct{} {
+	return m.session.CloseChan()
+}
+
+func (m *multiplexedDRPC) Invoke(ctx context.Context, rpc string, enc drpc.Encoding, inMessage, outMessage drpc.Message) error {
+	conn, err := m.session.Open()
+	if err != nil {
+		return err
+	}
+	dConn := drpcconn.New(conn)
+	defer func() {
+		_ = dConn.Close()
+	}()
+	return dConn.Invoke(ctx, rpc, enc, inMessage, outMessage)
+}
+
+func (m *multiplexedDRPC) NewStream(ctx context.Context, rpc string, enc drpc.Encoding) (drpc.Stream, error) {
+	conn, err := m.session.Open()
+	if err != nil {
+		return nil, err
+	}
+	dConn := drpcconn.New(conn)
+	stream, err := dConn.NewStream(ctx, rpc, enc)
+	if err == nil {
+		go func() {
+			<-stream.Context().Done()
+			_ = dConn.Close()
+		}()
+	}
+	return stream, err
+}
@@ -16,6 +16,35 @@ type GitSSHKey struct {
 	CreatedAt time.Time `json:"created_at" format:"date-time"`
 	UpdatedAt time.Time `json:"updated_at" format:"date-time"`
 	PublicKey string    `json:"public_key"`
+	// Name is unique per user. Every user has a key named "default".
+	Name string `json:"name"`
+	// KeyType is the type of the public key, such as "ssh-ed25519".
+	KeyType string `json:"key_type"`
+	// Imported is true if the private key was provided by the user instead
+	// of being generated by Coder.
+	Imported bool `json:"imported"`
+	// Hosts are patterns of the hosts the key is offered to, such as
+	// "*.github.com". The key is offered to every host if it's empty.
+	Hosts []string `json:"hosts"`
+}
+
+// CreateGitSSHKeyRequest creates a named key pair for the user. If a private
+// key is provided it's imported, otherwise a key pair is generated.
+type CreateGitSSHKeyRequest struct {
+	Name string `json:"name" validate:"required,username"`
+	// Algorithm of the generated key. It defaults to the algorithm
+	// configured for the deployment, and must be empty when importing.
+	Algorithm  string `json:"algorithm,omitempty" enums:"ed25519,ecdsa,rsa4096"`
+	PrivateKey string `json:"private_key,omitempty"`
+	// Hosts are patterns of the hosts the key is offered to. Patterns
+	// support "*" and "?" wildcards, and are negated by a leading "!".
+	Hosts []string `json:"hosts,omitempty"`
+}
+
+// UploadGitSSHKeyRequest registers the public key of a key pair with a git
+// provider, using the user's external auth token for that provider.
+type UploadGitSSHKeyRequest struct {
+	ExternalAuthProviderID string `json:"external_auth_provider_id" validate:"required"`
 }
 
 // GitSSHKey returns the user's git SSH public key.
@@ -49,3 +78,82 @@ func (c *Client) RegenerateGitSSHKey(ctx context.Context, user string) (GitSSHKe
 	var gitsshkey GitSSHKey
 	return gitsshkey, json.NewDecoder(res.Body).Decode(&gitsshkey)
 }
+
+// GitSSHKeys returns all of the user's git SSH public keys.
+func (c *Client) GitSSHKeys(ctx context.Context, user string) ([]GitSSHKey, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/users/%s/gitsshkeys", user), nil)
+	if err != nil {
+		return nil, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+
+	var gitsshkeys []GitSSHKey
+	return gitsshkeys, json.NewDecoder(res.Body).Decode(&gitsshkeys)
+}
+
+// CreateGitSSHKey generates or imports a named SSH key pair for the user.
+func (c *Client) CreateGitSSHKey(ctx context.Context, user string, req CreateGitSSHKeyRequest) (GitSSHKey, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/users/%s/gitsshkeys", user), req)
+	if err != nil {
+		return GitSSHKey{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusCreated {
+		return GitSSHKey{}, ReadBodyAsError(res)
+	}
+
+	var gitsshkey GitSSHKey
+	return gitsshkey, json.NewDecoder(res.Body).Decode(&gitsshkey)
+}
+
+// RegenerateNamedGitSSHKey generates a new key pair for a named SSH key of
+// the user. Imported keys are replaced by a generated key.
+func (c *Client) RegenerateNamedGitSSHKey(ctx context.Context, user string, name string) (GitSSHKey, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/users/%s/gitsshkeys/%s", user, name), nil)
+	if err != nil {
+		return GitSSHKey{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return GitSSHKey{}, ReadBodyAsError(res)
+	}
+
+	var gitsshkey GitSSHKey
+	return gitsshkey, json.NewDecoder(res.Body).Decode(&gitsshkey)
+}
+
+// DeleteGitSSHKey deletes a named SSH key pair of the user. The default key
+// cannot be deleted.
+func (c *Client) DeleteGitSSHKey(ctx context.Context, user string, name string) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/users/%s/gitsshkeys/%s", user, name), nil)
+	if err != nil {
+		return xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// UploadGitSSHKey registers the public key of a named SSH key pair with a
+// git provider.
+func (c *Client) UploadGitSSHKey(ctx context.Context, user string, name string, req UploadGitSSHKeyRequest) error {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/users/%s/gitsshkeys/%s/upload", user, name), req)
+	if err != nil {
+		return xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
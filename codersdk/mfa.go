@@ -0,0 +1,158 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+const (
+	// MFACodeValidationField is the field of the validation error a password
+	// login fails with when the user must enter a code from their
+	// authenticator.
+	MFACodeValidationField = "mfa_code"
+	// MFAEnrollmentValidationField is the field of the validation error a
+	// password login fails with when the deployment requires MFA and the
+	// user has not enrolled an authenticator yet.
+	MFAEnrollmentValidationField = "mfa_enrollment"
+)
+
+// UserMFA is the multi-factor authentication status of a user.
+type UserMFA struct {
+	Enabled   bool       `json:"enabled"`
+	EnabledAt *time.Time `json:"enabled_at,omitempty" format:"date-time"`
+	// Required is true when the deployment requires password users to use
+	// MFA, in which case it cannot be disabled by the user.
+	Required               bool `json:"required"`
+	RecoveryCodesRemaining int  `json:"recovery_codes_remaining"`
+}
+
+// TOTPEnrollment is a pending enrollment of an authenticator app. It must
+// be verified with a code from the app before it is enabled.
+type TOTPEnrollment struct {
+	Secret string `json:"secret"`
+	// URL is the "otpauth://" URL authenticator apps can scan as a QR code.
+	URL string `json:"url"`
+}
+
+type VerifyTOTPRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+// ResetUserMFARequest confirms the removal of the authenticator of the
+// user making the request. Admins resetting other users do not need a code.
+type ResetUserMFARequest struct {
+	// Code is a current code from the authenticator, or a recovery code.
+	Code string `json:"code,omitempty"`
+}
+
+// MFARecoveryCodes are single use codes that replace a code from the
+// authenticator. They are only returned when they are created.
+type MFARecoveryCodes struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// IsMFACodeRequired reports whether a password login failed because the
+// user must enter a code from their authenticator.
+func IsMFACodeRequired(err error) bool {
+	return hasValidationField(err, MFACodeValidationField)
+}
+
+// IsMFAEnrollmentRequired reports whether a password login failed because
+// the user must enroll an authenticator first. See EnrollTOTPWithPassword.
+func IsMFAEnrollmentRequired(err error) bool {
+	return hasValidationField(err, MFAEnrollmentValidationField)
+}
+
+func hasValidationField(err error, field string) bool {
+	var sdkErr *Error
+	if !xerrors.As(err, &sdkErr) || sdkErr.StatusCode() != http.StatusUnauthorized {
+		return false
+	}
+	for _, validation := range sdkErr.Validations {
+		if validation.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// UserMFA returns the multi-factor authentication status of the user.
+func (c *Client) UserMFA(ctx context.Context, user string) (UserMFA, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/users/%s/mfa", user), nil)
+	if err != nil {
+		return UserMFA{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return UserMFA{}, ReadBodyAsError(res)
+	}
+	var mfa UserMFA
+	return mfa, json.NewDecoder(res.Body).Decode(&mfa)
+}
+
+// EnrollUserTOTP starts the enrollment of an authenticator app, replacing
+// a previous pending enrollment. Users can only enroll themselves.
+func (c *Client) EnrollUserTOTP(ctx context.Context, user string) (TOTPEnrollment, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/users/%s/mfa/totp", user), nil)
+	if err != nil {
+		return TOTPEnrollment{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return TOTPEnrollment{}, ReadBodyAsError(res)
+	}
+	var enrollment TOTPEnrollment
+	return enrollment, json.NewDecoder(res.Body).Decode(&enrollment)
+}
+
+// VerifyUserTOTP enables the pending enrollment of an authenticator app,
+// and returns new recovery codes.
+func (c *Client) VerifyUserTOTP(ctx context.Context, user string, req VerifyTOTPRequest) (MFARecoveryCodes, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/users/%s/mfa/totp/verify", user), req)
+	if err != nil {
+		return MFARecoveryCodes{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return MFARecoveryCodes{}, ReadBodyAsError(res)
+	}
+	var codes MFARecoveryCodes
+	return codes, json.NewDecoder(res.Body).Decode(&codes)
+}
+
+// ResetUserMFA removes the authenticator and recovery codes of the user.
+// Admins use this when users lose access to their authenticator. Users
+// removing their own authenticator must confirm it with a code.
+func (c *Client) ResetUserMFA(ctx context.Context, user string, req ResetUserMFARequest) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/users/%s/mfa", user), req)
+	if err != nil {
+		return xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// EnrollTOTPWithPassword starts the enrollment of an authenticator app for
+// a user that cannot log in until they enroll, because the deployment
+// requires MFA. The enrollment is completed by logging in with a code from
+// the app.
+func (c *Client) EnrollTOTPWithPassword(ctx context.Context, req LoginWithPasswordRequest) (TOTPEnrollment, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/users/login/mfa/enroll", req)
+	if err != nil {
+		return TOTPEnrollment{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return TOTPEnrollment{}, ReadBodyAsError(res)
+	}
+	var enrollment TOTPEnrollment
+	return enrollment, json.NewDecoder(res.Body).Decode(&enrollment)
+}
@@ -0,0 +1,202 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+// OAuth2ProviderApp is an app that Coder acts as the OAuth2 authorization
+// server for.
+type OAuth2ProviderApp struct {
+	ID          uuid.UUID `json:"id" format:"uuid"`
+	Name        string    `json:"name"`
+	CallbackURL string    `json:"callback_url"`
+	Icon        string    `json:"icon"`
+
+	// Endpoints are included in the app response for easier discovery. The
+	// OAuth2 spec does not have a defined place to find these (for comparison,
+	// OIDC has a '/.well-known/openid-configuration' endpoint).
+	Endpoints OAuth2AppEndpoints `json:"endpoints"`
+}
+
+// OAuth2AppEndpoints are the URLs an OAuth2 client uses to complete the
+// authorization code flow.
+type OAuth2AppEndpoints struct {
+	Authorization string `json:"authorization"`
+	Token         string `json:"token"`
+}
+
+// OAuth2ProviderApps returns the applications configured to authenticate using
+// Coder as an OAuth2 provider.
+func (c *Client) OAuth2ProviderApps(ctx context.Context) ([]OAuth2ProviderApp, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/oauth2-provider/apps", nil)
+	if err != nil {
+		return nil, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var apps []OAuth2ProviderApp
+	return apps, json.NewDecoder(res.Body).Decode(&apps)
+}
+
+// OAuth2ProviderApp returns an application configured to authenticate using
+// Coder as an OAuth2 provider.
+func (c *Client) OAuth2ProviderApp(ctx context.Context, id uuid.UUID) (OAuth2ProviderApp, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/oauth2-provider/apps/%s", id), nil)
+	if err != nil {
+		return OAuth2ProviderApp{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return OAuth2ProviderApp{}, ReadBodyAsError(res)
+	}
+	var apps OAuth2ProviderApp
+	return apps, json.NewDecoder(res.Body).Decode(&apps)
+}
+
+type PostOAuth2ProviderAppRequest struct {
+	Name        string `json:"name" validate:"required,username"`
+	CallbackURL string `json:"callback_url" validate:"required,http_url"`
+	Icon        string `json:"icon" validate:"omitempty"`
+}
+
+// PostOAuth2ProviderApp adds an application that can authenticate using Coder
+// as an OAuth2 provider.
+func (c *Client) PostOAuth2ProviderApp(ctx context.Context, app PostOAuth2ProviderAppRequest) (OAuth2ProviderApp, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/oauth2-provider/apps", app)
+	if err != nil {
+		return OAuth2ProviderApp{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return OAuth2ProviderApp{}, ReadBodyAsError(res)
+	}
+	var resp OAuth2ProviderApp
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+type PutOAuth2ProviderAppRequest struct {
+	Name        string `json:"name" validate:"required,username"`
+	CallbackURL string `json:"callback_url" validate:"required,http_url"`
+	Icon        string `json:"icon" validate:"omitempty"`
+}
+
+// PutOAuth2ProviderApp updates an application that can authenticate using Coder
+// as an OAuth2 provider.
+func (c *Client) PutOAuth2ProviderApp(ctx context.Context, id uuid.UUID, app PutOAuth2ProviderAppRequest) (OAuth2ProviderApp, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/oauth2-provider/apps/%s", id), app)
+	if err != nil {
+		return OAuth2ProviderApp{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return OAuth2ProviderApp{}, ReadBodyAsError(res)
+	}
+	var resp OAuth2ProviderApp
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// DeleteOAuth2ProviderApp deletes an application, also invalidating any tokens
+// that were generated from it.
+func (c *Client) DeleteOAuth2ProviderApp(ctx context.Context, id uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/oauth2-provider/apps/%s", id), nil)
+	if err != nil {
+		return xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+type OAuth2ProviderAppSecretFull struct {
+	ID               uuid.UUID `json:"id" format:"uuid"`
+	ClientSecretFull string    `json:"client_secret_full"`
+}
+
+type OAuth2ProviderAppSecret struct {
+	ID                    uuid.UUID `json:"id" format:"uuid"`
+	LastUsedAt            NullTime  `json:"last_used_at" format:"date-time"`
+	ClientSecretTruncated string    `json:"client_secret_truncated"`
+}
+
+// OAuth2ProviderAppSecrets returns the truncated secrets for an OAuth2
+// application.
+func (c *Client) OAuth2ProviderAppSecrets(ctx context.Context, appID uuid.UUID) ([]OAuth2ProviderAppSecret, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/oauth2-provider/apps/%s/secrets", appID), nil)
+	if err != nil {
+		return []OAuth2ProviderAppSecret{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return []OAuth2ProviderAppSecret{}, ReadBodyAsError(res)
+	}
+	var resp []OAuth2ProviderAppSecret
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// PostOAuth2ProviderAppSecret creates a new secret for an OAuth2 application.
+// This is the only time the full secret will be revealed.
+func (c *Client) PostOAuth2ProviderAppSecret(ctx context.Context, appID uuid.UUID) (OAuth2ProviderAppSecretFull, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/oauth2-provider/apps/%s/secrets", appID), nil)
+	if err != nil {
+		return OAuth2ProviderAppSecretFull{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return OAuth2ProviderAppSecretFull{}, ReadBodyAsError(res)
+	}
+	var resp OAuth2ProviderAppSecretFull
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// DeleteOAuth2ProviderAppSecret deletes a secret from an OAuth2 application,
+// also invalidating any tokens that were generated with it.
+func (c *Client) DeleteOAuth2ProviderAppSecret(ctx context.Context, appID uuid.UUID, secretID uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/oauth2-provider/apps/%s/secrets/%s", appID, secretID), nil)
+	if err != nil {
+		return xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// OAuth2TokenError is the error body returned by the token endpoint, as
+// described in RFC 6749 section 5.2.
+type OAuth2TokenError struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+type OAuth2ProviderGrantType string
+
+const (
+	OAuth2ProviderGrantTypeAuthorizationCode OAuth2ProviderGrantType = "authorization_code"
+	OAuth2ProviderGrantTypeRefreshToken      OAuth2ProviderGrantType = "refresh_token"
+)
+
+type OAuth2ProviderResponseType string
+
+const (
+	OAuth2ProviderResponseTypeCode OAuth2ProviderResponseType = "code"
+)
+
+// OAuth2TokenResponse is returned by the token endpoint, as described in
+// RFC 6749 section 5.1. The access token is a Coder session token.
+type OAuth2TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+}
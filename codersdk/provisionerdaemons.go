@@ -16,8 +16,8 @@ import (
 	"golang.org/x/xerrors"
 	"nhooyr.io/websocket"
 
+	"github.com/coder/coder/v2/codersdk/drpc"
 	"github.com/coder/coder/v2/provisionerd/proto"
-	"github.com/coder/coder/v2/provisionersdk"
 )
 
 type LogSource string
@@ -42,6 +42,24 @@ type ProvisionerDaemon struct {
 	Name         string            `json:"name"`
 	Provisioners []ProvisionerType `json:"provisioners"`
 	Tags         map[string]string `json:"tags"`
+	// LastSeenAt is the last time the daemon sent a heartbeat.
+	LastSeenAt NullTime `json:"last_seen_at,omitempty" format:"date-time"`
+	// Healthy is true if the daemon has sent a heartbeat recently.
+	Healthy bool `json:"healthy"`
+	// Version is the Coder version the daemon is running.
+	Version string `json:"version"`
+	// ProvisionerVersions maps a provisioner type to the version of the
+	// underlying tool, e.g. "terraform": "1.5.5".
+	ProvisionerVersions map[string]string `json:"provisioner_versions"`
+	// CurrentJob is the job the daemon is running, if any.
+	CurrentJob *ProvisionerDaemonJob `json:"current_job,omitempty"`
+}
+
+// ProvisionerDaemonJob is a summary of a job running on a provisioner daemon.
+type ProvisionerDaemonJob struct {
+	ID        uuid.UUID            `json:"id" format:"uuid"`
+	Status    ProvisionerJobStatus `json:"status" enums:"pending,running,succeeded,canceling,canceled,failed"`
+	StartedAt time.Time            `json:"started_at" format:"date-time"`
 }
 
 // ProvisionerJobStatus represents the at-time state of a job.
@@ -87,6 +105,11 @@ type ProvisionerJob struct {
 	Tags          map[string]string    `json:"tags"`
 	QueuePosition int                  `json:"queue_position"`
 	QueueSize     int                  `json:"queue_size"`
+	// EstimatedStartAt is when a pending workspace build is expected to
+	// start, based on its queue position, the number of provisioner daemons
+	// able to run it and the jobs they're running, and the median build time
+	// of the template. It is omitted when no estimate is available.
+	EstimatedStartAt *time.Time `json:"estimated_start_at,omitempty" format:"date-time"`
 }
 
 // ProvisionerJobLog represents the provisioner log entry annotated with source and level.
@@ -238,7 +261,7 @@ func (c *Client) ServeProvisionerDaemon(ctx context.Context, req ServeProvisione
 		_ = wsNetConn.Close()
 		return nil, xerrors.Errorf("multiplex client: %w", err)
 	}
-	return proto.NewDRPCProvisionerDaemonClient(provisionersdk.MultiplexedConn(session)), nil
+	return proto.NewDRPCProvisionerDaemonClient(drpc.MultiplexedConn(session)), nil
 }
 
 // wsNetConn wraps net.Conn created by websocket.NetConn(). Cancel func
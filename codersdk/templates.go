@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/netip"
+	"strconv"
 	"strings"
 	"time"
 
@@ -50,6 +52,10 @@ type Template struct {
 	FailureTTLMillis               int64 `json:"failure_ttl_ms"`
 	TimeTilDormantMillis           int64 `json:"time_til_dormant_ms"`
 	TimeTilDormantAutoDeleteMillis int64 `json:"time_til_dormant_autodelete_ms"`
+
+	// EgressPolicy restricts the destinations that the agents of workspaces
+	// forward connections to.
+	EgressPolicy EgressPolicy `json:"egress_policy"`
 }
 
 // WeekdaysToBitmap converts a list of weekdays to a bitmap in accordance with
@@ -124,6 +130,101 @@ type TemplateAutostopRequirement struct {
 	Weeks int64 `json:"weeks"`
 }
 
+type EgressAction string
+
+const (
+	EgressActionAllow EgressAction = "allow"
+	EgressActionDeny  EgressAction = "deny"
+)
+
+// EgressPolicy restricts the destinations that workspace agents dial when
+// users port-forward, or forward connections through SSH. The first rule that
+// matches a destination decides whether it's allowed.
+type EgressPolicy struct {
+	// DefaultAction applies to destinations that no rule matches. It defaults
+	// to allow.
+	DefaultAction EgressAction `json:"default_action,omitempty" enums:"allow,deny"`
+	Rules         []EgressRule `json:"rules,omitempty"`
+}
+
+type EgressRule struct {
+	Action EgressAction `json:"action" enums:"allow,deny"`
+	// CIDR is the network of destinations the rule matches, e.g. 10.0.0.0/8.
+	// A single IP address only matches itself. If empty, all addresses match.
+	CIDR string `json:"cidr,omitempty"`
+	// Ports are the ports or port ranges of destinations the rule matches,
+	// e.g. 443 or 8000-8999. If empty, all ports match.
+	Ports []string `json:"ports,omitempty"`
+}
+
+// Validate returns an error if the policy has unknown actions, or CIDRs or
+// ports that can't be parsed.
+func (p EgressPolicy) Validate() error {
+	if p.DefaultAction != "" && !p.DefaultAction.valid() {
+		return xerrors.Errorf("default action %q must be %q or %q", p.DefaultAction, EgressActionAllow, EgressActionDeny)
+	}
+	for i, rule := range p.Rules {
+		if !rule.Action.valid() {
+			return xerrors.Errorf("rule %d: action %q must be %q or %q", i, rule.Action, EgressActionAllow, EgressActionDeny)
+		}
+		if rule.CIDR != "" {
+			_, err := ParseEgressCIDR(rule.CIDR)
+			if err != nil {
+				return xerrors.Errorf("rule %d: %w", i, err)
+			}
+		}
+		for _, ports := range rule.Ports {
+			_, _, err := ParseEgressPorts(ports)
+			if err != nil {
+				return xerrors.Errorf("rule %d: %w", i, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (a EgressAction) valid() bool {
+	return a == EgressActionAllow || a == EgressActionDeny
+}
+
+// ParseEgressCIDR parses the CIDR of an egress rule. A single IP address is
+// parsed as a prefix that only contains itself.
+func ParseEgressCIDR(s string) (netip.Prefix, error) {
+	if !strings.Contains(s, "/") {
+		addr, err := netip.ParseAddr(s)
+		if err != nil {
+			return netip.Prefix{}, xerrors.Errorf("parse address %q: %w", s, err)
+		}
+		return netip.PrefixFrom(addr, addr.BitLen()), nil
+	}
+	prefix, err := netip.ParsePrefix(s)
+	if err != nil {
+		return netip.Prefix{}, xerrors.Errorf("parse CIDR %q: %w", s, err)
+	}
+	return prefix.Masked(), nil
+}
+
+// ParseEgressPorts parses a port, or a range of ports in the form
+// <start>-<end>, of an egress rule.
+func ParseEgressPorts(s string) (start uint16, end uint16, err error) {
+	rawStart, rawEnd, isRange := strings.Cut(s, "-")
+	if !isRange {
+		rawEnd = rawStart
+	}
+	start64, err := strconv.ParseUint(rawStart, 10, 16)
+	if err != nil || start64 == 0 {
+		return 0, 0, xerrors.Errorf("invalid port %q", rawStart)
+	}
+	end64, err := strconv.ParseUint(rawEnd, 10, 16)
+	if err != nil || end64 == 0 {
+		return 0, 0, xerrors.Errorf("invalid port %q", rawEnd)
+	}
+	if start64 > end64 {
+		return 0, 0, xerrors.Errorf("port range %q must not end before it starts", s)
+	}
+	return uint16(start64), uint16(end64), nil
+}
+
 type TransitionStats struct {
 	P50 *int64 `example:"123"`
 	P95 *int64 `example:"146"`
@@ -201,6 +302,8 @@ type UpdateTemplateMeta struct {
 	// from the template. This is useful for preventing dormant workspaces being immediately
 	// deleted when updating the dormant_ttl field to a new, shorter value.
 	UpdateWorkspaceDormantAt bool `json:"update_workspace_dormant_at"`
+	// EgressPolicy replaces the egress policy of the template if set.
+	EgressPolicy *EgressPolicy `json:"egress_policy,omitempty"`
 }
 
 type TemplateExample struct {
@@ -36,6 +36,8 @@ type TemplateVersion struct {
 type TemplateVersionGitAuth struct {
 	ID              string      `json:"id"`
 	Type            GitProvider `json:"type"`
+	DisplayName     string      `json:"display_name"`
+	DisplayIcon     string      `json:"display_icon"`
 	AuthenticateURL string      `json:"authenticate_url"`
 	Authenticated   bool        `json:"authenticated"`
 }
@@ -65,6 +67,19 @@ type TemplateVersionParameter struct {
 	ValidationMonotonic  ValidationMonotonicOrder         `json:"validation_monotonic,omitempty" enums:"increasing,decreasing"`
 	Required             bool                             `json:"required"`
 	Ephemeral            bool                             `json:"ephemeral"`
+	// VisibleIf is an expression that controls whether the parameter is
+	// shown, based on the values of other parameters. See
+	// coderd/parameter for the evaluation rules.
+	VisibleIf       string                                   `json:"visible_if,omitempty"`
+	ValidationRules []TemplateVersionParameterValidationRule `json:"validation_rules,omitempty"`
+}
+
+// TemplateVersionParameterValidationRule is a dynamic validation rule. The
+// condition is an expression that must evaluate to true for the parameter
+// value to be accepted.
+type TemplateVersionParameterValidationRule struct {
+	Condition string `json:"condition"`
+	Error     string `json:"error"`
 }
 
 // TemplateVersionParameterOption represents a selectable option for a template parameter.
@@ -55,6 +55,9 @@ type User struct {
 	Roles           []Role      `json:"roles"`
 	AvatarURL       string      `json:"avatar_url" format:"uri"`
 	LoginType       LoginType   `json:"login_type"`
+	// ServiceAccountOwnerGroupID is only set for service accounts. Members of
+	// this group can issue tokens for the service account.
+	ServiceAccountOwnerGroupID *uuid.UUID `json:"service_account_owner_group_id,omitempty" format:"uuid"`
 }
 
 type GetUsersResponse struct {
@@ -86,6 +89,10 @@ type CreateUserRequest struct {
 	// Deprecated: Set UserLoginType=LoginTypeDisabled instead.
 	DisableLogin   bool      `json:"disable_login"`
 	OrganizationID uuid.UUID `json:"organization_id" validate:"" format:"uuid"`
+	// ServiceAccountOwnerGroupID is required when UserLoginType is
+	// LoginTypeServiceAccount. Members of the group can issue tokens for
+	// the service account.
+	ServiceAccountOwnerGroupID *uuid.UUID `json:"service_account_owner_group_id,omitempty" format:"uuid"`
 }
 
 type UpdateUserProfileRequest struct {
@@ -144,11 +151,24 @@ type ConvertLoginRequest struct {
 type LoginWithPasswordRequest struct {
 	Email    string `json:"email" validate:"required,email" format:"email"`
 	Password string `json:"password" validate:"required"`
+	// MFACode is a code from the authenticator of the user, or one of their
+	// recovery codes. It is required for users that enrolled in MFA.
+	MFACode string `json:"mfa_code,omitempty"`
+}
+
+// LoginWithLDAPRequest enables callers to authenticate with the username and
+// password of their account in the LDAP directory.
+type LoginWithLDAPRequest struct {
+	Username string `json:"username" validate:"required"`
+	Password string `json:"password" validate:"required"`
 }
 
 // LoginWithPasswordResponse contains a session token for the newly authenticated user.
 type LoginWithPasswordResponse struct {
 	SessionToken string `json:"session_token" validate:"required"`
+	// MFARecoveryCodes are returned once, when the login completed an MFA
+	// enrollment the deployment requires.
+	MFARecoveryCodes []string `json:"mfa_recovery_codes,omitempty"`
 }
 
 type OAuthConversionResponse struct {
@@ -167,6 +187,10 @@ type AuthMethods struct {
 	Password AuthMethod     `json:"password"`
 	Github   AuthMethod     `json:"github"`
 	OIDC     OIDCAuthMethod `json:"oidc"`
+	// OIDCProviders are the additional OIDC providers users can sign in
+	// with.
+	OIDCProviders []OIDCProviderAuthMethod `json:"oidc_providers"`
+	LDAP          LDAPAuthMethod           `json:"ldap"`
 }
 
 type AuthMethod struct {
@@ -183,6 +207,17 @@ type OIDCAuthMethod struct {
 	IconURL    string `json:"iconUrl"`
 }
 
+type OIDCProviderAuthMethod struct {
+	ID         string `json:"id"`
+	SignInText string `json:"signInText"`
+	IconURL    string `json:"iconUrl"`
+}
+
+type LDAPAuthMethod struct {
+	AuthMethod
+	SignInText string `json:"signInText"`
+}
+
 // HasFirstUser returns whether the first user has been created.
 func (c *Client) HasFirstUser(ctx context.Context) (bool, error) {
 	res, err := c.Request(ctx, http.MethodGet, "/api/v2/users/first", nil)
@@ -357,6 +392,26 @@ func (c *Client) LoginWithPassword(ctx context.Context, req LoginWithPasswordReq
 	return resp, nil
 }
 
+// LoginWithLDAP creates a session token authenticating with the username and
+// password of an LDAP directory account. Call `SetSessionToken()` to apply
+// the newly acquired token to the client.
+func (c *Client) LoginWithLDAP(ctx context.Context, req LoginWithLDAPRequest) (LoginWithPasswordResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/users/ldap/login", req)
+	if err != nil {
+		return LoginWithPasswordResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return LoginWithPasswordResponse{}, ReadBodyAsError(res)
+	}
+	var resp LoginWithPasswordResponse
+	err = json.NewDecoder(res.Body).Decode(&resp)
+	if err != nil {
+		return LoginWithPasswordResponse{}, err
+	}
+	return resp, nil
+}
+
 // ConvertLoginType will send a request to convert the user from password
 // based authentication to oauth based. The response has the oauth state code
 // to use in the oauth flow.
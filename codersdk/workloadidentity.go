@@ -0,0 +1,69 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// WorkloadIdentityProvider is a trusted external OIDC issuer, such as GitHub
+// Actions or GitLab CI, whose tokens can be exchanged for short-lived Coder
+// session tokens.
+type WorkloadIdentityProvider struct {
+	// ID identifies the provider in exchange requests.
+	ID string `json:"id" yaml:"id"`
+	// Issuer is the issuer URL of the provider. Signing keys are discovered
+	// from its OpenID configuration.
+	Issuer string `json:"issuer" yaml:"issuer"`
+	// Audience is the audience tokens must be issued for.
+	Audience string `json:"audience" yaml:"audience"`
+	// Rules map tokens to Coder users. The first rule whose claims all match
+	// the token is used.
+	Rules []WorkloadIdentityRule `json:"rules" yaml:"rules"`
+}
+
+// WorkloadIdentityRule maps tokens with matching claims to a Coder user.
+type WorkloadIdentityRule struct {
+	// Claims must all be equal to the claims of the token. A value ending in
+	// "*" matches any claim value with that prefix.
+	Claims map[string]string `json:"claims" yaml:"claims"`
+	// Username is the user or service account to issue the token for.
+	Username string `json:"username" yaml:"username"`
+	// Scopes are fine-grained scopes to limit the issued token to. At least
+	// one is required.
+	Scopes []APIKeyScope `json:"scopes" yaml:"scopes" enums:"template:push,template:read,user:read,workspace:read,workspace:start"`
+}
+
+// WorkloadIdentityTokenLifetime is the lifetime of session tokens issued in
+// exchange for workload identity tokens.
+const WorkloadIdentityTokenLifetime = 15 * time.Minute
+
+type WorkloadIdentityExchangeRequest struct {
+	// ProviderID is the ID of the configured provider that issued the token.
+	ProviderID string `json:"provider_id" validate:"required"`
+	// Token is the OIDC JWT issued by the provider.
+	Token string `json:"token" validate:"required"`
+}
+
+type WorkloadIdentityExchangeResponse struct {
+	SessionToken string    `json:"session_token"`
+	ExpiresAt    time.Time `json:"expires_at" format:"date-time"`
+	Username     string    `json:"username"`
+}
+
+// ExchangeWorkloadIdentity trades an OIDC JWT from a trusted external issuer
+// for a short-lived session token. Call `SetSessionToken()` to apply the
+// newly acquired token to the client.
+func (c *Client) ExchangeWorkloadIdentity(ctx context.Context, req WorkloadIdentityExchangeRequest) (WorkloadIdentityExchangeResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/users/workload-identity/exchange", req)
+	if err != nil {
+		return WorkloadIdentityExchangeResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return WorkloadIdentityExchangeResponse{}, ReadBodyAsError(res)
+	}
+	var resp WorkloadIdentityExchangeResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
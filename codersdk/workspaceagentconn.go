@@ -202,6 +202,13 @@ type WorkspaceAgentReconnectingPTYInit struct {
 	Command string
 }
 
+// WorkspaceAgentReconnectingPTYExit is returned by the agent once the command
+// of a reconnecting PTY exits on its own.
+// @typescript-ignore WorkspaceAgentReconnectingPTYExit
+type WorkspaceAgentReconnectingPTYExit struct {
+	ExitCode int `json:"exit_code"`
+}
+
 // ReconnectingPTYRequest is sent from the client to the server
 // to pipe data to a PTY.
 // @typescript-ignore ReconnectingPTYRequest
@@ -226,7 +233,7 @@ func (c *WorkspaceAgentConn) ReconnectingPTY(ctx context.Context, id uuid.UUID,
 	if err != nil {
 		return nil, err
 	}
-	data, err := json.Marshal(WorkspaceAgentReconnectingPTYInit{
+	err = WriteReconnectingPTYInit(conn, WorkspaceAgentReconnectingPTYInit{
 		ID:      id,
 		Height:  height,
 		Width:   width,
@@ -236,15 +243,21 @@ func (c *WorkspaceAgentConn) ReconnectingPTY(ctx context.Context, id uuid.UUID,
 		_ = conn.Close()
 		return nil, err
 	}
-	data = append(make([]byte, 2), data...)
-	binary.LittleEndian.PutUint16(data, uint16(len(data)-2))
+	return conn, nil
+}
 
-	_, err = conn.Write(data)
+// WriteReconnectingPTYInit writes the header that a connection to the
+// reconnecting PTY port of an agent starts with.
+func WriteReconnectingPTYInit(w io.Writer, init WorkspaceAgentReconnectingPTYInit) error {
+	data, err := json.Marshal(init)
 	if err != nil {
-		_ = conn.Close()
-		return nil, err
+		return err
 	}
-	return conn, nil
+	data = append(make([]byte, 2), data...)
+	binary.LittleEndian.PutUint16(data, uint16(len(data)-2))
+
+	_, err = w.Write(data)
+	return err
 }
 
 // SSH pipes the SSH protocol over the returned net.Conn.
@@ -270,7 +283,12 @@ func (c *WorkspaceAgentConn) SSHClient(ctx context.Context) (*ssh.Client, error)
 	if err != nil {
 		return nil, xerrors.Errorf("ssh: %w", err)
 	}
+	return NewWorkspaceAgentSSHClient(netConn)
+}
 
+// NewWorkspaceAgentSSHClient creates an SSH client over a connection to the
+// SSH server of a workspace agent.
+func NewWorkspaceAgentSSHClient(netConn net.Conn) (*ssh.Client, error) {
 	sshConn, channels, requests, err := ssh.NewClientConn(netConn, "localhost:22", &ssh.ClientConfig{
 		// SSH host validation isn't helpful, because obtaining a peer
 		// connection already signifies user-intent to dial a workspace.
@@ -361,6 +379,24 @@ func (c *WorkspaceAgentConn) ListeningPorts(ctx context.Context) (WorkspaceAgent
 	return resp, json.NewDecoder(res.Body).Decode(&resp)
 }
 
+// ReconnectingPTYExit waits for the command of the reconnecting PTY with the
+// given ID to exit, and returns its exit code.
+func (c *WorkspaceAgentConn) ReconnectingPTYExit(ctx context.Context, id uuid.UUID) (WorkspaceAgentReconnectingPTYExit, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	res, err := c.apiRequest(ctx, http.MethodGet, fmt.Sprintf("/api/v0/reconnecting-pty/%s/exit", id), nil)
+	if err != nil {
+		return WorkspaceAgentReconnectingPTYExit{}, xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceAgentReconnectingPTYExit{}, ReadBodyAsError(res)
+	}
+
+	var resp WorkspaceAgentReconnectingPTYExit
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
 // apiRequest makes a request to the workspace agent's HTTP API server.
 func (c *WorkspaceAgentConn) apiRequest(ctx context.Context, method, path string, body io.Reader) (*http.Response, error) {
 	ctx, span := tracing.StartSpan(ctx)
@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"net/http/cookiejar"
 	"net/netip"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
@@ -216,7 +217,17 @@ func (c *Client) WorkspaceAgentConnectionInfoGeneric(ctx context.Context) (Works
 }
 
 func (c *Client) WorkspaceAgentConnectionInfo(ctx context.Context, agentID uuid.UUID) (WorkspaceAgentConnectionInfo, error) {
-	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaceagents/%s/connection", agentID), nil)
+	return c.workspaceAgentConnectionInfo(ctx, c.URL, agentID)
+}
+
+// workspaceAgentConnectionInfo gets the connection info from the given server,
+// which is either coderd or a workspace proxy.
+func (c *Client) workspaceAgentConnectionInfo(ctx context.Context, serverURL *url.URL, agentID uuid.UUID) (WorkspaceAgentConnectionInfo, error) {
+	connectionURL, err := serverURL.Parse(fmt.Sprintf("/api/v2/workspaceagents/%s/connection", agentID))
+	if err != nil {
+		return WorkspaceAgentConnectionInfo{}, xerrors.Errorf("parse url: %w", err)
+	}
+	res, err := c.Request(ctx, http.MethodGet, connectionURL.String(), nil)
 	if err != nil {
 		return WorkspaceAgentConnectionInfo{}, err
 	}
@@ -235,6 +246,49 @@ type DialWorkspaceAgentOptions struct {
 	// BlockEndpoints forced a direct connection through DERP. The Client may
 	// have DisableDirect set which will override this value.
 	BlockEndpoints bool
+	// WorkspaceProxy is the name of the region whose DERP relay the
+	// connection prefers as its home. The relays of other regions are still
+	// used to reach peers that are homed on them.
+	WorkspaceProxy string
+	// WorkspaceProxyURL is the URL of the workspace proxy to coordinate
+	// through instead of coderd. The proxy relays to the coordinator of
+	// coderd, and prefers its own DERP region.
+	WorkspaceProxyURL *url.URL
+}
+
+// preferProxyDERPRegions returns a copy of the DERP map in which the regions
+// that aren't run by the given workspace proxy are avoided as the home region.
+// Regions that aren't run by any proxy belong to the primary. If no region
+// belongs to the proxy, e.g. because it has DERP disabled, the map is returned
+// as is.
+func preferProxyDERPRegions(derpMap *tailcfg.DERPMap, proxyName string) *tailcfg.DERPMap {
+	if derpMap == nil {
+		return nil
+	}
+	isProxyRegion := func(region *tailcfg.DERPRegion) bool {
+		if proxyName == PrimaryRegionName {
+			return !strings.HasPrefix(region.RegionCode, ProxyDERPRegionCode(""))
+		}
+		return region.RegionCode == ProxyDERPRegionCode(proxyName)
+	}
+	found := false
+	for _, region := range derpMap.Regions {
+		if isProxyRegion(region) {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return derpMap
+	}
+
+	preferred := derpMap.Clone()
+	for _, region := range preferred.Regions {
+		if !isProxyRegion(region) {
+			region.Avoid = true
+		}
+	}
+	return preferred
 }
 
 func (c *Client) DialWorkspaceAgent(ctx context.Context, agentID uuid.UUID, options *DialWorkspaceAgentOptions) (agentConn *WorkspaceAgentConn, err error) {
@@ -242,7 +296,11 @@ func (c *Client) DialWorkspaceAgent(ctx context.Context, agentID uuid.UUID, opti
 		options = &DialWorkspaceAgentOptions{}
 	}
 
-	connInfo, err := c.WorkspaceAgentConnectionInfo(ctx, agentID)
+	serverURL := c.URL
+	if options.WorkspaceProxyURL != nil {
+		serverURL = options.WorkspaceProxyURL
+	}
+	connInfo, err := c.workspaceAgentConnectionInfo(ctx, serverURL, agentID)
 	if err != nil {
 		return nil, xerrors.Errorf("get connection info: %w", err)
 	}
@@ -258,9 +316,13 @@ func (c *Client) DialWorkspaceAgent(ctx context.Context, agentID uuid.UUID, opti
 	if ok {
 		header = headerTransport.Header()
 	}
+	derpMap := connInfo.DERPMap
+	if options.WorkspaceProxy != "" {
+		derpMap = preferProxyDERPRegions(derpMap, options.WorkspaceProxy)
+	}
 	conn, err := tailnet.NewConn(&tailnet.Options{
 		Addresses:           []netip.Prefix{netip.PrefixFrom(ip, 128)},
-		DERPMap:             connInfo.DERPMap,
+		DERPMap:             derpMap,
 		DERPHeader:          &header,
 		DERPForceWebSockets: connInfo.DERPForceWebSockets,
 		Logger:              options.Logger,
@@ -288,7 +350,7 @@ func (c *Client) DialWorkspaceAgent(ctx context.Context, agentID uuid.UUID, opti
 		}
 	}()
 
-	coordinateURL, err := c.URL.Parse(fmt.Sprintf("/api/v2/workspaceagents/%s/coordinate", agentID))
+	coordinateURL, err := serverURL.Parse(fmt.Sprintf("/api/v2/workspaceagents/%s/coordinate", agentID))
 	if err != nil {
 		return nil, xerrors.Errorf("parse url: %w", err)
 	}
@@ -340,7 +402,7 @@ func (c *Client) DialWorkspaceAgent(ctx context.Context, agentID uuid.UUID, opti
 		}
 	}()
 
-	derpMapURL, err := c.URL.Parse("/api/v2/derp-map")
+	derpMapURL, err := serverURL.Parse("/api/v2/derp-map")
 	if err != nil {
 		return nil, xerrors.Errorf("parse url: %w", err)
 	}
@@ -379,7 +441,7 @@ func (c *Client) DialWorkspaceAgent(ctx context.Context, agentID uuid.UUID, opti
 				dec   = json.NewDecoder(nconn)
 			)
 			for {
-				var derpMap tailcfg.DERPMap
+				var derpMap *tailcfg.DERPMap
 				err := dec.Decode(&derpMap)
 				if xerrors.Is(err, context.Canceled) {
 					_ = ws.Close(websocket.StatusGoingAway, "")
@@ -391,9 +453,12 @@ func (c *Client) DialWorkspaceAgent(ctx context.Context, agentID uuid.UUID, opti
 					return
 				}
 
-				if !tailnet.CompareDERPMaps(conn.DERPMap(), &derpMap) {
+				if options.WorkspaceProxy != "" {
+					derpMap = preferProxyDERPRegions(derpMap, options.WorkspaceProxy)
+				}
+				if !tailnet.CompareDERPMaps(conn.DERPMap(), derpMap) {
 					options.Logger.Debug(ctx, "updating derp map due to detected changes")
-					conn.SetDERPMap(&derpMap)
+					conn.SetDERPMap(derpMap)
 				}
 			}
 		}
@@ -790,4 +855,5 @@ const (
 	WorkspaceAgentLogSourceEnvbox         WorkspaceAgentLogSource = "envbox"
 	WorkspaceAgentLogSourceEnvbuilder     WorkspaceAgentLogSource = "envbuilder"
 	WorkspaceAgentLogSourceExternal       WorkspaceAgentLogSource = "external"
+	WorkspaceAgentLogSourceEgressPolicy   WorkspaceAgentLogSource = "egress_policy"
 )
@@ -0,0 +1,50 @@
+package codersdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"tailscale.com/tailcfg"
+)
+
+func TestPreferProxyDERPRegions(t *testing.T) {
+	t.Parallel()
+
+	derpMap := &tailcfg.DERPMap{
+		Regions: map[int]*tailcfg.DERPRegion{
+			1:    {RegionID: 1, RegionCode: "coder", EmbeddedRelay: true},
+			2:    {RegionID: 2, RegionCode: "nyc"},
+			1001: {RegionID: 1001, RegionCode: ProxyDERPRegionCode("Sydney")},
+			1002: {RegionID: 1002, RegionCode: ProxyDERPRegionCode("frankfurt")},
+		},
+	}
+	avoided := func(m *tailcfg.DERPMap) []int {
+		var ids []int
+		for id, region := range m.Regions {
+			if region.Avoid {
+				ids = append(ids, id)
+			}
+		}
+		return ids
+	}
+
+	t.Run("Proxy", func(t *testing.T) {
+		t.Parallel()
+		preferred := preferProxyDERPRegions(derpMap, "sydney")
+		require.ElementsMatch(t, []int{1, 2, 1002}, avoided(preferred))
+		// The original map is left alone.
+		require.Empty(t, avoided(derpMap))
+	})
+
+	t.Run("Primary", func(t *testing.T) {
+		t.Parallel()
+		preferred := preferProxyDERPRegions(derpMap, PrimaryRegionName)
+		require.ElementsMatch(t, []int{1001, 1002}, avoided(preferred))
+	})
+
+	t.Run("NoDERP", func(t *testing.T) {
+		t.Parallel()
+		preferred := preferProxyDERPRegions(derpMap, "london")
+		require.Same(t, derpMap, preferred)
+	})
+}
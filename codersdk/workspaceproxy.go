@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"golang.org/x/xerrors"
@@ -176,6 +177,15 @@ func (c *Client) WorkspaceProxyByID(ctx context.Context, id uuid.UUID) (Workspac
 	return c.WorkspaceProxyByName(ctx, id.String())
 }
 
+// PrimaryRegionName is the name of the region served by coderd itself.
+const PrimaryRegionName = "primary"
+
+// ProxyDERPRegionCode is the code of the region a workspace proxy with DERP
+// enabled adds to the DERP map.
+func ProxyDERPRegionCode(proxyName string) string {
+	return fmt.Sprintf("coder_%s", strings.ToLower(proxyName))
+}
+
 type RegionTypes interface {
 	Region | WorkspaceProxy
 }
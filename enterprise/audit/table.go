@@ -16,15 +16,18 @@ import (
 // AuditableResources map (below) as our documentation - generated in scripts/auditdocgen/main.go -
 // depends upon it.
 var AuditActionMap = map[string][]codersdk.AuditAction{
-	"GitSSHKey":       {codersdk.AuditActionCreate},
-	"Template":        {codersdk.AuditActionWrite, codersdk.AuditActionDelete},
-	"TemplateVersion": {codersdk.AuditActionCreate, codersdk.AuditActionWrite},
-	"User":            {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
-	"Workspace":       {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
-	"WorkspaceBuild":  {codersdk.AuditActionStart, codersdk.AuditActionStop},
-	"Group":           {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
-	"APIKey":          {codersdk.AuditActionLogin, codersdk.AuditActionLogout, codersdk.AuditActionRegister, codersdk.AuditActionCreate, codersdk.AuditActionDelete},
-	"License":         {codersdk.AuditActionCreate, codersdk.AuditActionDelete},
+	"GitSSHKey":               {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"Template":                {codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"TemplateVersion":         {codersdk.AuditActionCreate, codersdk.AuditActionWrite},
+	"User":                    {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"Workspace":               {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"WorkspaceBuild":          {codersdk.AuditActionStart, codersdk.AuditActionStop},
+	"Group":                   {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"APIKey":                  {codersdk.AuditActionLogin, codersdk.AuditActionLogout, codersdk.AuditActionRegister, codersdk.AuditActionCreate, codersdk.AuditActionDelete},
+	"License":                 {codersdk.AuditActionCreate, codersdk.AuditActionDelete},
+	"OAuth2ProviderApp":       {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"OAuth2ProviderAppSecret": {codersdk.AuditActionCreate, codersdk.AuditActionDelete},
+	"UserMFA":                 {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
 }
 
 type Action string
@@ -56,6 +59,9 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"updated_at":  ActionIgnore, // Changes, but is implicit and not helpful in a diff.
 		"private_key": ActionSecret, // We don't want to expose private keys in diffs.
 		"public_key":  ActionTrack,  // Public keys are ok to expose in a diff.
+		"name":        ActionTrack,
+		"imported":    ActionTrack,
+		"hosts":       ActionTrack,
 	},
 	&database.Template{}: {
 		"id":                                ActionTrack,
@@ -73,6 +79,7 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"max_ttl":                           ActionTrack,
 		"autostop_requirement_days_of_week": ActionTrack,
 		"autostop_requirement_weeks":        ActionTrack,
+		"egress_policy":                     ActionTrack,
 		"created_by":                        ActionTrack,
 		"created_by_username":               ActionIgnore,
 		"created_by_avatar_url":             ActionIgnore,
@@ -101,19 +108,20 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"created_by_username":   ActionIgnore,
 	},
 	&database.User{}: {
-		"id":                   ActionTrack,
-		"email":                ActionTrack,
-		"username":             ActionTrack,
-		"hashed_password":      ActionSecret, // Do not expose a users hashed password.
-		"created_at":           ActionIgnore, // Never changes.
-		"updated_at":           ActionIgnore, // Changes, but is implicit and not helpful in a diff.
-		"status":               ActionTrack,
-		"rbac_roles":           ActionTrack,
-		"login_type":           ActionTrack,
-		"avatar_url":           ActionIgnore,
-		"last_seen_at":         ActionIgnore,
-		"deleted":              ActionTrack,
-		"quiet_hours_schedule": ActionTrack,
+		"id":                             ActionTrack,
+		"email":                          ActionTrack,
+		"username":                       ActionTrack,
+		"hashed_password":                ActionSecret, // Do not expose a users hashed password.
+		"created_at":                     ActionIgnore, // Never changes.
+		"updated_at":                     ActionIgnore, // Changes, but is implicit and not helpful in a diff.
+		"status":                         ActionTrack,
+		"rbac_roles":                     ActionTrack,
+		"login_type":                     ActionTrack,
+		"avatar_url":                     ActionIgnore,
+		"last_seen_at":                   ActionIgnore,
+		"deleted":                        ActionTrack,
+		"quiet_hours_schedule":           ActionTrack,
+		"service_account_owner_group_id": ActionTrack,
 	},
 	&database.Workspace{}: {
 		"id":                 ActionTrack,
@@ -171,6 +179,9 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"ip_address":       ActionIgnore,
 		"scope":            ActionIgnore,
 		"token_name":       ActionIgnore,
+		"scopes":           ActionTrack,
+		"allow_list":       ActionTrack,
+		"provider_id":      ActionIgnore,
 	},
 	&database.AuditOAuthConvertState{}: {
 		"created_at":      ActionTrack,
@@ -203,6 +214,33 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"derp_only":           ActionTrack,
 		"region_id":           ActionTrack,
 	},
+	&database.OAuth2ProviderApp{}: {
+		"id":           ActionIgnore,
+		"created_at":   ActionIgnore,
+		"updated_at":   ActionIgnore,
+		"name":         ActionTrack,
+		"icon":         ActionTrack,
+		"callback_url": ActionTrack,
+	},
+	&database.OAuth2ProviderAppSecret{}: {
+		"id":             ActionIgnore,
+		"created_at":     ActionIgnore,
+		"last_used_at":   ActionIgnore,
+		"hashed_secret":  ActionSecret,
+		"display_secret": ActionIgnore,
+		"app_id":         ActionIgnore,
+	},
+	&database.AuditableUserMFA{}: {
+		"user_id":             ActionIgnore,
+		"created_at":          ActionIgnore,
+		"updated_at":          ActionIgnore,
+		"totp_secret":         ActionSecret,
+		"enabled_at":          ActionTrack,
+		"totp_last_used_step": ActionIgnore,
+		"failed_attempts":     ActionIgnore,
+		"last_failed_at":      ActionIgnore,
+		"username":            ActionIgnore,
+	},
 }
 
 // auditMap converts a map of struct pointers to a map of struct names as
@@ -122,6 +122,14 @@ func (r *RootCmd) provisionerDaemonStart() *clibase.Cmd {
 				}
 			}()
 
+			provisionerVersions := map[string]string{}
+			tfVersion, err := terraform.ResolveVersion(ctx, "")
+			if err != nil {
+				logger.Warn(ctx, "unable to determine terraform version", slog.Error(err))
+			} else {
+				provisionerVersions[string(database.ProvisionerTypeTerraform)] = tfVersion.String()
+			}
+
 			logger.Info(ctx, "starting provisioner daemon", slog.F("tags", tags))
 
 			provisioners := provisionerd.Provisioners{
@@ -136,11 +144,12 @@ func (r *RootCmd) provisionerDaemonStart() *clibase.Cmd {
 					PreSharedKey: preSharedKey,
 				})
 			}, &provisionerd.Options{
-				Logger:          logger,
-				JobPollInterval: pollInterval,
-				JobPollJitter:   pollJitter,
-				UpdateInterval:  500 * time.Millisecond,
-				Provisioners:    provisioners,
+				Logger:              logger,
+				JobPollInterval:     pollInterval,
+				JobPollJitter:       pollJitter,
+				UpdateInterval:      500 * time.Millisecond,
+				Provisioners:        provisioners,
+				ProvisionerVersions: provisionerVersions,
 			})
 
 			var exitErr error
@@ -10,7 +10,6 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
-	"strings"
 	"sync"
 	"time"
 
@@ -36,6 +35,7 @@ import (
 	"github.com/coder/coder/v2/enterprise/derpmesh"
 	"github.com/coder/coder/v2/enterprise/replicasync"
 	"github.com/coder/coder/v2/enterprise/tailnet"
+	"github.com/coder/coder/v2/enterprise/wsproxy/wsproxysdk"
 	"github.com/coder/coder/v2/provisionerd/proto"
 	agpltailnet "github.com/coder/coder/v2/tailnet"
 )
@@ -50,6 +50,14 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 	if options.Keys == nil {
 		options.Keys = Keys
 	}
+	switch options.ProxyVersionPolicy {
+	case "":
+		options.ProxyVersionPolicy = wsproxysdk.VersionPolicyRefuse
+	case wsproxysdk.VersionPolicyRefuse, wsproxysdk.VersionPolicyDegrade:
+	default:
+		return nil, xerrors.Errorf("invalid workspace proxy version policy %q, must be %q or %q",
+			options.ProxyVersionPolicy, wsproxysdk.VersionPolicyRefuse, wsproxysdk.VersionPolicyDegrade)
+	}
 	if options.Options == nil {
 		options.Options = &coderd.Options{}
 	}
@@ -92,8 +100,12 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 	}
 
 	oauthConfigs := &httpmw.OAuth2Configs{
-		Github: options.GithubOAuth2Config,
-		OIDC:   options.OIDCConfig,
+		Github:        options.GithubOAuth2Config,
+		OIDC:          options.OIDCConfig,
+		OIDCProviders: map[string]httpmw.OAuth2Config{},
+	}
+	for _, oidcProvider := range options.OIDCProviders {
+		oauthConfigs.OIDCProviders[oidcProvider.ID] = oidcProvider
 	}
 	apiKeyMiddleware := httpmw.ExtractAPIKeyMW(httpmw.ExtractAPIKeyConfig{
 		DB:                          options.Database,
@@ -374,7 +386,11 @@ type Options struct {
 
 	EntitlementsUpdateInterval time.Duration
 	ProxyHealthInterval        time.Duration
-	Keys                       map[string]ed25519.PublicKey
+	// ProxyVersionPolicy is what to do with workspace proxies whose version
+	// is incompatible, see wsproxysdk.VersionPolicyRefuse and
+	// wsproxysdk.VersionPolicyDegrade. It defaults to refuse.
+	ProxyVersionPolicy string
+	Keys               map[string]ed25519.PublicKey
 
 	// optional pre-shared key for authentication of external provisioner daemons
 	ProvisionerDaemonPSK string
@@ -580,7 +596,7 @@ func (api *API) updateEntitlements(ctx context.Context) error {
 
 			api.replicaManager.SetCallback(func() {
 				addresses := make([]string, 0)
-				for _, replica := range api.replicaManager.Regional() {
+				for _, replica := range api.replicaManager.Meshed() {
 					addresses = append(addresses, replica.RelayAddress)
 				}
 				api.derpMesh.SetAddresses(addresses, false)
@@ -735,7 +751,7 @@ func derpMapper(logger slog.Logger, proxyHealth *proxyhealth.ProxyHealth) func(*
 			// unique by the database and the computed ID is greater than any
 			// existing ID in the DERP map.
 			regionID := int(startingRegionID) + int(status.Proxy.RegionID)
-			regionCode := fmt.Sprintf("coder_%s", strings.ToLower(status.Proxy.Name))
+			regionCode := codersdk.ProxyDERPRegionCode(status.Proxy.Name)
 			regionName := status.Proxy.DisplayName
 			if regionName == "" {
 				regionName = status.Proxy.Name
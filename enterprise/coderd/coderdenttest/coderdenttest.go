@@ -51,6 +51,7 @@ type Options struct {
 	SCIMAPIKey                  []byte
 	UserWorkspaceQuota          int
 	ProxyHealthInterval         time.Duration
+	ProxyVersionPolicy          string
 	LicenseOptions              *LicenseOptions
 	NoDefaultQuietHoursSchedule bool
 	DontAddLicense              bool
@@ -94,6 +95,7 @@ func NewWithAPI(t *testing.T, options *Options) (
 		EntitlementsUpdateInterval: options.EntitlementsUpdateInterval,
 		Keys:                       Keys,
 		ProxyHealthInterval:        options.ProxyHealthInterval,
+		ProxyVersionPolicy:         options.ProxyVersionPolicy,
 		DefaultQuietHoursSchedule:  oop.DeploymentValues.UserQuietHoursSchedule.DefaultSchedule.Value(),
 		ProvisionerDaemonPSK:       options.ProvisionerDaemonPSK,
 	})
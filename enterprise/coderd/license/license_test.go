@@ -304,6 +304,36 @@ func TestEntitlements(t *testing.T) {
 		require.True(t, entitlements.HasLicense)
 		require.Contains(t, entitlements.Warnings, "Your deployment has 2 active users but is only licensed for 1.")
 	})
+	t.Run("ServiceAccountsNotCounted", func(t *testing.T) {
+		t.Parallel()
+		db := dbfake.New()
+		for _, loginType := range []database.LoginType{database.LoginTypePassword, database.LoginTypeServiceAccount} {
+			user, err := db.InsertUser(context.Background(), database.InsertUserParams{
+				ID:        uuid.New(),
+				Username:  string(loginType),
+				LoginType: loginType,
+			})
+			require.NoError(t, err)
+			_, err = db.UpdateUserStatus(context.Background(), database.UpdateUserStatusParams{
+				ID:        user.ID,
+				Status:    database.UserStatusActive,
+				UpdatedAt: dbtime.Now(),
+			})
+			require.NoError(t, err)
+		}
+		db.InsertLicense(context.Background(), database.InsertLicenseParams{
+			JWT: coderdenttest.GenerateLicense(t, coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureUserLimit: 1,
+				},
+			}),
+			Exp: time.Now().Add(time.Hour),
+		})
+		entitlements, err := license.Entitlements(context.Background(), db, slog.Logger{}, 1, 1, coderdenttest.Keys, empty)
+		require.NoError(t, err)
+		require.Empty(t, entitlements.Warnings)
+		require.Equal(t, int64(1), *entitlements.Features[codersdk.FeatureUserLimit].Actual)
+	})
 	t.Run("MaximizeUserLimit", func(t *testing.T) {
 		t.Parallel()
 		db := dbfake.New()
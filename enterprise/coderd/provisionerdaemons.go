@@ -25,6 +25,7 @@ import (
 	"cdr.dev/slog"
 	"github.com/coder/coder/v2/coderd"
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
@@ -83,9 +84,41 @@ func (api *API) provisionerDaemons(rw http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	daemonIDs := make([]uuid.UUID, 0, len(daemons))
+	for _, daemon := range daemons {
+		daemonIDs = append(daemonIDs, daemon.ID)
+	}
+	// Running jobs are looked up as the system since the caller may not be
+	// able to read the workspace or template the job belongs to. Only the
+	// job ID and status are exposed.
+	//nolint:gocritic // Daemons have already been authorized above.
+	runningJobs, err := api.Database.GetProvisionerJobsRunningByWorkerIDs(dbauthz.AsSystemRestricted(ctx), daemonIDs)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching running provisioner jobs.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	jobsByWorker := make(map[uuid.UUID]database.ProvisionerJob, len(runningJobs))
+	for _, job := range runningJobs {
+		jobsByWorker[job.WorkerID.UUID] = job
+	}
 	apiDaemons := make([]codersdk.ProvisionerDaemon, 0)
 	for _, daemon := range daemons {
-		apiDaemons = append(apiDaemons, convertProvisionerDaemon(daemon))
+		apiDaemon := convertProvisionerDaemon(daemon)
+		if job, ok := jobsByWorker[daemon.ID]; ok {
+			status := codersdk.ProvisionerJobRunning
+			if job.CanceledAt.Valid {
+				status = codersdk.ProvisionerJobCanceling
+			}
+			apiDaemon.CurrentJob = &codersdk.ProvisionerDaemonJob{
+				ID:        job.ID,
+				Status:    status,
+				StartedAt: job.StartedAt.Time,
+			}
+		}
+		apiDaemons = append(apiDaemons, apiDaemon)
 	}
 	httpapi.Write(ctx, rw, http.StatusOK, apiDaemons)
 }
@@ -283,6 +316,7 @@ func (api *API) provisionerDaemonServe(rw http.ResponseWriter, r *http.Request)
 		provisionerdserver.Options{
 			GitAuthConfigs: api.GitAuthConfigs,
 			OIDCConfig:     api.OIDCConfig,
+			OIDCProviders:  api.AGPL.OIDCProviderConfigs(),
 		},
 	)
 	if err != nil {
@@ -316,11 +350,18 @@ func (api *API) provisionerDaemonServe(rw http.ResponseWriter, r *http.Request)
 
 func convertProvisionerDaemon(daemon database.ProvisionerDaemon) codersdk.ProvisionerDaemon {
 	result := codersdk.ProvisionerDaemon{
-		ID:        daemon.ID,
-		CreatedAt: daemon.CreatedAt,
-		UpdatedAt: daemon.UpdatedAt,
-		Name:      daemon.Name,
-		Tags:      daemon.Tags,
+		ID:                  daemon.ID,
+		CreatedAt:           daemon.CreatedAt,
+		UpdatedAt:           daemon.UpdatedAt,
+		Name:                daemon.Name,
+		Tags:                daemon.Tags,
+		LastSeenAt:          codersdk.NullTime{NullTime: daemon.LastSeenAt},
+		Healthy:             daemon.Healthy(database.ProvisionerDaemonStaleTimeout),
+		Version:             daemon.Version,
+		ProvisionerVersions: daemon.ProvisionerVersions,
+	}
+	if result.ProvisionerVersions == nil {
+		result.ProvisionerVersions = map[string]string{}
 	}
 	for _, provisionerType := range daemon.Provisioners {
 		result.Provisioners = append(result.Provisioners, codersdk.ProvisionerType(provisionerType))
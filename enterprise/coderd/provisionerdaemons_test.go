@@ -52,6 +52,49 @@ func TestProvisionerDaemonServe(t *testing.T) {
 		require.Len(t, daemons, 1)
 	})
 
+	t.Run("Heartbeat", func(t *testing.T) {
+		t.Parallel()
+		client, user := coderdenttest.New(t, &coderdenttest.Options{LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureExternalProvisionerDaemons: 1,
+			},
+		}})
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+		srv, err := client.ServeProvisionerDaemon(ctx, codersdk.ServeProvisionerDaemonRequest{
+			Organization: user.OrganizationID,
+			Provisioners: []codersdk.ProvisionerType{
+				codersdk.ProvisionerTypeEcho,
+			},
+			Tags: map[string]string{},
+		})
+		require.NoError(t, err)
+		defer srv.DRPCConn().Close()
+
+		daemons, err := client.ProvisionerDaemons(ctx)
+		require.NoError(t, err)
+		require.Len(t, daemons, 1)
+		require.False(t, daemons[0].Healthy)
+		require.False(t, daemons[0].LastSeenAt.Valid)
+
+		_, err = srv.Heartbeat(ctx, &provisionerdproto.HeartbeatRequest{
+			Version: "v2.1.0",
+			ProvisionerVersions: map[string]string{
+				"terraform": "1.5.5",
+			},
+		})
+		require.NoError(t, err)
+
+		daemons, err = client.ProvisionerDaemons(ctx)
+		require.NoError(t, err)
+		require.Len(t, daemons, 1)
+		require.True(t, daemons[0].Healthy)
+		require.True(t, daemons[0].LastSeenAt.Valid)
+		require.Equal(t, "v2.1.0", daemons[0].Version)
+		require.Equal(t, map[string]string{"terraform": "1.5.5"}, daemons[0].ProvisionerVersions)
+		require.Nil(t, daemons[0].CurrentJob)
+	})
+
 	t.Run("NoLicense", func(t *testing.T) {
 		t.Parallel()
 		client, user := coderdenttest.New(t, &coderdenttest.Options{DontAddLicense: true})
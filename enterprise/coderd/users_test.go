@@ -211,3 +211,85 @@ func TestUserQuietHours(t *testing.T) {
 		require.Equal(t, http.StatusNotFound, sdkErr.StatusCode())
 	})
 }
+
+func TestServiceAccounts(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OwnerGroupIssuesTokens", func(t *testing.T) {
+		t.Parallel()
+
+		client, user := coderdenttest.New(t, &coderdenttest.Options{LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureTemplateRBAC: 1,
+			},
+		}})
+		memberClient, member := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		otherClient, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		group, err := client.CreateGroup(ctx, user.OrganizationID, codersdk.CreateGroupRequest{
+			Name: "ci",
+		})
+		require.NoError(t, err)
+		_, err = client.PatchGroup(ctx, group.ID, codersdk.PatchGroupRequest{
+			AddUsers: []string{member.ID.String()},
+		})
+		require.NoError(t, err)
+
+		serviceAccount, err := client.CreateUser(ctx, codersdk.CreateUserRequest{
+			Email:                      "ci-bot@coder.com",
+			Username:                   "ci-bot",
+			OrganizationID:             user.OrganizationID,
+			UserLoginType:              codersdk.LoginTypeServiceAccount,
+			ServiceAccountOwnerGroupID: &group.ID,
+		})
+		require.NoError(t, err)
+		require.Equal(t, codersdk.LoginTypeServiceAccount, serviceAccount.LoginType)
+		require.NotNil(t, serviceAccount.ServiceAccountOwnerGroupID)
+		require.Equal(t, group.ID, *serviceAccount.ServiceAccountOwnerGroupID)
+
+		// Members of the owner group can issue tokens for the service account.
+		res, err := memberClient.CreateToken(ctx, serviceAccount.ID.String(), codersdk.CreateTokenRequest{})
+		require.NoError(t, err)
+
+		serviceAccountClient := codersdk.New(client.URL)
+		serviceAccountClient.SetSessionToken(res.Key)
+		me, err := serviceAccountClient.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Equal(t, serviceAccount.ID, me.ID)
+
+		// Everyone else cannot.
+		_, err = otherClient.CreateToken(ctx, serviceAccount.ID.String(), codersdk.CreateTokenRequest{})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		// Neither can a scoped key of a group member, because the scope
+		// doesn't allow creating tokens for the member itself.
+		scoped, err := memberClient.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+			Scopes: []codersdk.APIKeyScope{codersdk.APIKeyScopeUserRead},
+		})
+		require.NoError(t, err)
+		scopedClient := codersdk.New(client.URL)
+		scopedClient.SetSessionToken(scoped.Key)
+		_, err = scopedClient.CreateToken(ctx, serviceAccount.ID.String(), codersdk.CreateTokenRequest{})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+
+	t.Run("RequiresOwnerGroup", func(t *testing.T) {
+		t.Parallel()
+
+		client, user := coderdenttest.New(t, nil)
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := client.CreateUser(ctx, codersdk.CreateUserRequest{
+			Email:          "ci-bot@coder.com",
+			Username:       "ci-bot",
+			OrganizationID: user.OrganizationID,
+			UserLoginType:  codersdk.LoginTypeServiceAccount,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+}
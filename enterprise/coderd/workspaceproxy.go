@@ -574,12 +574,22 @@ func (api *API) workspaceProxyRegister(rw http.ResponseWriter, r *http.Request)
 	// Version check should be forced in non-dev builds and when running in
 	// tests.
 	shouldForceVersion := !buildinfo.IsDev() || flag.Lookup("test.v") != nil
-	if shouldForceVersion && req.Version != buildinfo.Version() {
-		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
-			Message: "Version mismatch.",
-			Detail:  fmt.Sprintf("Proxy version %q does not match primary server version %q", req.Version, buildinfo.Version()),
-		})
-		return
+	compatibility := wsproxysdk.VersionCompatible
+	if shouldForceVersion {
+		compatibility = wsproxysdk.CheckVersionCompatibility(buildinfo.Version(), req.Version)
+	}
+	derpOnly := req.DerpOnly
+	if compatibility == wsproxysdk.VersionIncompatible {
+		// Incompatible proxies may still relay DERP, which doesn't depend
+		// on the version of the primary.
+		if api.ProxyVersionPolicy != wsproxysdk.VersionPolicyDegrade || !req.DerpEnabled {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Version mismatch.",
+				Detail:  wsproxysdk.VersionCompatibilityMessage(compatibility, buildinfo.Version(), req.Version),
+			})
+			return
+		}
+		derpOnly = true
 	}
 
 	if err := validateProxyURL(req.AccessURL); err != nil {
@@ -623,7 +633,7 @@ func (api *API) workspaceProxyRegister(rw http.ResponseWriter, r *http.Request)
 			ID:               proxy.ID,
 			Url:              req.AccessURL,
 			DerpEnabled:      req.DerpEnabled,
-			DerpOnly:         req.DerpOnly,
+			DerpOnly:         derpOnly,
 			WildcardHostname: req.WildcardHostname,
 		})
 		if err != nil {
@@ -718,12 +728,14 @@ func (api *API) workspaceProxyRegister(rw http.ResponseWriter, r *http.Request)
 
 	// aReq.New = updatedProxy
 	httpapi.Write(ctx, rw, http.StatusCreated, wsproxysdk.RegisterWorkspaceProxyResponse{
-		AppSecurityKey:      api.AppSecurityKey.String(),
-		DERPMeshKey:         api.DERPServer.MeshKey(),
-		DERPRegionID:        regionID,
-		DERPMap:             api.AGPL.DERPMap(),
-		DERPForceWebSockets: api.DeploymentValues.DERP.Config.ForceWebSockets.Value(),
-		SiblingReplicas:     siblingsRes,
+		AppSecurityKey:       api.AppSecurityKey.String(),
+		DERPMeshKey:          api.DERPServer.MeshKey(),
+		DERPRegionID:         regionID,
+		DERPMap:              api.AGPL.DERPMap(),
+		DERPForceWebSockets:  api.DeploymentValues.DERP.Config.ForceWebSockets.Value(),
+		SiblingReplicas:      siblingsRes,
+		VersionCompatibility: compatibility,
+		DerpOnly:             derpOnly,
 	})
 
 	go api.forceWorkspaceProxyHealthUpdate(api.ctx)
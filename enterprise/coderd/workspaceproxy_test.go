@@ -443,6 +443,64 @@ func TestProxyRegisterDeregister(t *testing.T) {
 		require.Contains(t, sdkErr.Response.Message, "Version mismatch")
 	})
 
+	t.Run("DegradeMismatchingVersion", func(t *testing.T) {
+		t.Parallel()
+
+		dv := coderdtest.DeploymentValues(t)
+		dv.Experiments = []string{
+			string(codersdk.ExperimentMoons),
+			"*",
+		}
+		client, _ := coderdenttest.New(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				DeploymentValues: dv,
+			},
+			ProxyVersionPolicy: wsproxysdk.VersionPolicyDegrade,
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureWorkspaceProxy: 1,
+				},
+			},
+		})
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		createRes, err := client.CreateWorkspaceProxy(ctx, codersdk.CreateWorkspaceProxyRequest{
+			Name: "hi",
+		})
+		require.NoError(t, err)
+
+		proxyClient := wsproxysdk.New(client.URL)
+		proxyClient.SetSessionToken(createRes.ProxyToken)
+
+		req := wsproxysdk.RegisterWorkspaceProxyRequest{
+			AccessURL:           "https://proxy.coder.test",
+			WildcardHostname:    "*.proxy.coder.test",
+			DerpEnabled:         true,
+			ReplicaID:           uuid.New(),
+			ReplicaHostname:     "mars",
+			ReplicaError:        "",
+			ReplicaRelayAddress: "http://127.0.0.1:8080",
+			Version:             "v0.0.0",
+		}
+		registerRes, err := proxyClient.RegisterWorkspaceProxy(ctx, req)
+		require.NoError(t, err)
+		require.Equal(t, wsproxysdk.VersionIncompatible, registerRes.VersionCompatibility)
+		require.True(t, registerRes.DerpOnly)
+
+		proxy, err := client.WorkspaceProxyByID(ctx, createRes.Proxy.ID)
+		require.NoError(t, err)
+		require.True(t, proxy.DerpOnly)
+
+		// Without DERP there's nothing left to serve.
+		req.DerpEnabled = false
+		req.ReplicaID = uuid.New()
+		_, err = proxyClient.RegisterWorkspaceProxy(ctx, req)
+		var sdkErr *codersdk.Error
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, http.StatusBadRequest, sdkErr.StatusCode())
+		require.Contains(t, sdkErr.Response.Message, "Version mismatch")
+	})
+
 	t.Run("ReregisterUpdateReplica", func(t *testing.T) {
 		t.Parallel()
 
@@ -270,7 +270,7 @@ func (m *Manager) syncReplicas(ctx context.Context) error {
 	var wg sync.WaitGroup
 	var mu sync.Mutex
 	failed := make([]string, 0)
-	for _, peer := range m.Regional() {
+	for _, peer := range m.Meshed() {
 		wg.Add(1)
 		go func(peer database.Replica) {
 			defer wg.Done()
@@ -379,6 +379,38 @@ func (m *Manager) Regional() []database.Replica {
 	return m.InRegion(m.regionID())
 }
 
+// Meshed returns the replicas that this replica meshes its DERP server with,
+// excluding itself. Replicas mesh with every replica in their region, but
+// across regions only through the gateway of each region: a gateway meshes
+// with every primary replica of the other regions, and the other replicas mesh
+// with the gateways of the other regions. This keeps the number of connections
+// between regions linear in the number of replicas.
+//
+// DERP only forwards packets a single hop, so across regions a peer is only
+// reachable through the mesh if either end is connected to a gateway. Clients
+// connect to the home region of their peers, so this only matters for
+// connections that reach the wrong region, e.g. through a global load
+// balancer.
+func (m *Manager) Meshed() []database.Replica {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	gateways := database.RegionGateways(append([]database.Replica{m.self}, m.peers...))
+	isGateway := gateways[m.self.RegionID] == m.self.ID
+	replicas := make([]database.Replica, 0)
+	for _, replica := range m.peers {
+		switch {
+		case replica.RegionID == m.self.RegionID:
+		case !m.self.Primary || !replica.Primary:
+			// Workspace proxies only mesh within their region.
+			continue
+		case !isGateway && gateways[replica.RegionID] != replica.ID:
+			continue
+		}
+		replicas = append(replicas, replica)
+	}
+	return replicas
+}
+
 func (m *Manager) regionID() int32 {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
@@ -188,6 +188,71 @@ func TestReplica(t *testing.T) {
 		}, testutil.WaitShort, testutil.IntervalFast)
 		_ = server.Close()
 	})
+	t.Run("MeshesWithGateways", func(t *testing.T) {
+		// Ensures that replicas only mesh with the gateways of other
+		// regions, unless they are the gateway of their own region.
+		t.Parallel()
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer srv.Close()
+		insert := func(t *testing.T, db database.Store, id string, regionID int32) database.Replica {
+			replica, err := db.InsertReplica(context.Background(), database.InsertReplicaParams{
+				ID:           uuid.MustParse(id),
+				CreatedAt:    dbtime.Now(),
+				StartedAt:    dbtime.Now(),
+				UpdatedAt:    dbtime.Now(),
+				Hostname:     "something",
+				RelayAddress: srv.URL,
+				RegionID:     regionID,
+				Primary:      true,
+			})
+			require.NoError(t, err)
+			return replica
+		}
+		ids := func(replicas []database.Replica) []uuid.UUID {
+			ids := make([]uuid.UUID, 0, len(replicas))
+			for _, replica := range replicas {
+				ids = append(ids, replica.ID)
+			}
+			return ids
+		}
+
+		for _, tc := range []struct {
+			Name    string
+			SelfID  string
+			Gateway bool
+		}{
+			{Name: "Gateway", SelfID: "00000000-0000-0000-0000-000000000000", Gateway: true},
+			{Name: "NotGateway", SelfID: "00000000-0000-0000-0000-000000000009", Gateway: false},
+		} {
+			tc := tc
+			t.Run(tc.Name, func(t *testing.T) {
+				t.Parallel()
+				db, pubsub := dbtestutil.NewDB(t)
+				regional := insert(t, db, "00000000-0000-0000-0000-000000000001", 1)
+				otherGateway := insert(t, db, "00000000-0000-0000-0000-000000000002", 2)
+				other := insert(t, db, "00000000-0000-0000-0000-000000000003", 2)
+
+				ctx, cancelCtx := context.WithCancel(context.Background())
+				defer cancelCtx()
+				server, err := replicasync.New(ctx, slogtest.Make(t, nil), db, pubsub, &replicasync.Options{
+					ID:           uuid.MustParse(tc.SelfID),
+					RelayAddress: srv.URL,
+					RegionID:     1,
+				})
+				require.NoError(t, err)
+				defer server.Close()
+
+				expected := []uuid.UUID{regional.ID, otherGateway.ID}
+				if tc.Gateway {
+					expected = append(expected, other.ID)
+				}
+				require.ElementsMatch(t, expected, ids(server.Meshed()))
+				require.Empty(t, server.Self().Error)
+			})
+		}
+	})
 	t.Run("DeletesOld", func(t *testing.T) {
 		t.Parallel()
 		db, pubsub := dbtestutil.NewDB(t)
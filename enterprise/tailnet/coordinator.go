@@ -18,6 +18,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/pubsub"
 	"github.com/coder/coder/v2/codersdk"
 	agpl "github.com/coder/coder/v2/tailnet"
+	"github.com/coder/coder/v2/tailnet/proto"
 )
 
 // NewCoordinator creates a new high availability coordinator
@@ -64,6 +65,30 @@ func (c *haCoordinator) ServeMultiAgent(id uuid.UUID) agpl.MultiAgentConn {
 	return m
 }
 
+// Coordinate rejects v2 peers. The pubsub messages of this coordinator don't
+// carry the IDs of peers, which the v2 protocol needs, so the PostgreSQL
+// coordinator must be used instead.
+func (*haCoordinator) Coordinate(ctx context.Context, _ uuid.UUID, _ string, _ agpl.TunnelAuth) (chan<- *proto.CoordinateRequest, <-chan *proto.CoordinateResponse) {
+	reqs := make(chan *proto.CoordinateRequest, agpl.RequestBufferSize)
+	resps := make(chan *proto.CoordinateResponse, 1)
+	resps <- &proto.CoordinateResponse{Error: "the v2 coordinator protocol is not supported by this coordinator"}
+	close(resps)
+	go func() {
+		// Drain requests until the caller closes them, so it never blocks.
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case _, ok := <-reqs:
+				if !ok {
+					return
+				}
+			}
+		}
+	}()
+	return reqs, resps
+}
+
 func (c *haCoordinator) addClient(id uuid.UUID, q agpl.Queue) {
 	c.mutex.Lock()
 	c.clients[id] = q
@@ -700,6 +725,15 @@ func (c *haCoordinator) formatAgentUpdate(id uuid.UUID, node *agpl.Node) ([]byte
 	return buf.Bytes(), nil
 }
 
+// Status only includes the peers of this replica, since the peers of other
+// replicas are only known through their node updates.
+func (c *haCoordinator) Status(context.Context) (*agpl.CoordinatorStatus, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return agpl.StatusFromLocal(true, c.agentSockets, c.agentToConnectionSockets, c.nodes, c.agentNameCache), nil
+}
+
 func (c *haCoordinator) ServeHTTPDebug(w http.ResponseWriter, r *http.Request) {
 	c.mutex.RLock()
 	defer c.mutex.RUnlock()
@@ -24,6 +24,7 @@ import (
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/util/slice"
 	agpl "github.com/coder/coder/v2/tailnet"
+	"github.com/coder/coder/v2/tailnet/proto"
 )
 
 const (
@@ -190,6 +191,152 @@ func (c *pgCoord) ServeAgent(conn net.Conn, id uuid.UUID, name string) error {
 	return nil
 }
 
+// Coordinate serves a peer using the v2 protocol. Agents get a single connIO, like v1 agents; clients get a connIO
+// for each tunnel, so each tunnel is bound and mapped like a v1 client of that agent.
+func (c *pgCoord) Coordinate(ctx context.Context, id uuid.UUID, name string, a agpl.TunnelAuth) (chan<- *proto.CoordinateRequest, <-chan *proto.CoordinateResponse) {
+	reqs := make(chan *proto.CoordinateRequest, agpl.RequestBufferSize)
+	resps := make(chan *proto.CoordinateResponse, agpl.ResponseBufferSize)
+	go c.coordinate(ctx, id, name, a, reqs, resps)
+	return reqs, resps
+}
+
+func (c *pgCoord) coordinate(pCtx context.Context, id uuid.UUID, name string, a agpl.TunnelAuth, reqs <-chan *proto.CoordinateRequest, resps chan<- *proto.CoordinateResponse) {
+	ctx, cancel := context.WithCancel(pCtx)
+	logger := c.logger.With(slog.F("peer_id", id), slog.F("name", name))
+	pc := agpl.NewPeerConn(ctx, cancel, id, name, logger, resps, 0)
+	go pc.SendUpdates()
+	go func() {
+		// stop serving the peer when the coordinator closes
+		select {
+		case <-ctx.Done():
+		case <-c.ctx.Done():
+			cancel()
+		}
+	}()
+
+	h := &peerHandler{
+		coord:   c,
+		ctx:     ctx,
+		logger:  logger,
+		id:      id,
+		pc:      pc,
+		tunnels: make(map[uuid.UUID]*connIO),
+	}
+	defer h.close()
+	if _, ok := a.(agpl.AgentTunnelAuth); ok {
+		h.agentIO = newPeerConnIO(c.ctx, ctx, logger, c.bindings, uuid.Nil, id, pc)
+		if err := sendCtx(c.ctx, c.newConnections, h.agentIO); err != nil {
+			// can only be a context error, no need to log here.
+			_ = pc.Close()
+			return
+		}
+	}
+	kind, reason := agpl.HandleCoordinateRequests(ctx, logger, reqs, pc, a, h)
+	logger.Debug(ctx, "peer disconnected", slog.F("kind", kind.String()), slog.F("reason", reason))
+	_ = pc.Close()
+}
+
+// peerHandler applies the requests of a v2 peer by binding its node through each of its connIOs.  It's only used by
+// the goroutine that reads the peer's requests.
+type peerHandler struct {
+	coord  *pgCoord
+	ctx    context.Context
+	logger slog.Logger
+	id     uuid.UUID
+	pc     *agpl.PeerConn
+	node   *agpl.Node
+
+	// agentIO is the connIO of the peer if it's an agent.
+	agentIO *connIO
+	// tunnels maps the agents a client peer has tunnels to onto the connIO for each tunnel.
+	tunnels map[uuid.UUID]*connIO
+}
+
+func (h *peerHandler) UpdateSelf(node *agpl.Node) error {
+	h.node = node
+	if h.agentIO != nil {
+		return h.bind(h.agentIO)
+	}
+	for _, cIO := range h.tunnels {
+		if cIO.ctx.Err() != nil {
+			// the tunnel was closed while the coordinator was unhealthy, and is reopened by AddTunnel
+			continue
+		}
+		if err := h.bind(cIO); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *peerHandler) AddTunnel(dst uuid.UUID) error {
+	if h.agentIO != nil {
+		return xerrors.New("agents cannot open tunnels")
+	}
+	if cIO, ok := h.tunnels[dst]; ok && cIO.ctx.Err() == nil {
+		return nil
+	}
+	cIO := newPeerConnIO(h.coord.ctx, h.ctx, h.logger, h.coord.bindings, h.id, dst, h.pc)
+	if err := sendCtx(h.ctx, h.coord.newConnections, cIO); err != nil {
+		cIO.cancel()
+		return err
+	}
+	h.tunnels[dst] = cIO
+	if h.node == nil {
+		// the node is bound once the client sends it
+		return nil
+	}
+	return h.bind(cIO)
+}
+
+func (h *peerHandler) RemoveTunnel(dst uuid.UUID) error {
+	cIO, ok := h.tunnels[dst]
+	if !ok {
+		return nil
+	}
+	delete(h.tunnels, dst)
+	h.withdraw(cIO)
+	// the agent learns that the client went away from the withdrawn binding, but the client no longer gets updates
+	// about the agent, so we tell it here.
+	return h.pc.RemovePeer(dst, proto.CoordinateResponse_PeerUpdate_DISCONNECTED, "tunnel removed")
+}
+
+func (h *peerHandler) bind(cIO *connIO) error {
+	b := binding{
+		bKey: bKey{
+			client: cIO.client,
+			agent:  cIO.agent,
+		},
+		node: h.node,
+	}
+	return sendCtx(h.ctx, h.coord.bindings, b)
+}
+
+// withdraw stops the connIO and withdraws its binding.  We use the coordinator context, since the peer's context might
+// be canceled, but we still need to withdraw bindings.
+func (h *peerHandler) withdraw(cIO *connIO) {
+	cIO.cancel()
+	b := binding{
+		bKey: bKey{
+			client: cIO.client,
+			agent:  cIO.agent,
+		},
+	}
+	if err := sendCtx(h.coord.ctx, h.coord.bindings, b); err != nil {
+		h.logger.Debug(h.ctx, "coordinator context expired while withdrawing bindings", slog.Error(err))
+	}
+}
+
+func (h *peerHandler) close() {
+	if h.agentIO != nil {
+		h.withdraw(h.agentIO)
+	}
+	for dst, cIO := range h.tunnels {
+		delete(h.tunnels, dst)
+		h.withdraw(cIO)
+	}
+}
+
 func (c *pgCoord) Close() error {
 	c.logger.Info(c.ctx, "closing coordinator")
 	c.cancel()
@@ -198,8 +345,9 @@ func (c *pgCoord) Close() error {
 }
 
 // connIO manages the reading and writing to a connected client or agent.  Agent connIOs have their client field set to
-// uuid.Nil.  It reads node updates via its decoder, then pushes them onto the bindings channel.  It receives mappings
-// via its updates TrackedConn, which then writes them.
+// uuid.Nil.  For v1 connections, it reads node updates via its decoder, then pushes them onto the bindings channel.  It
+// receives mappings via its updates Queue, which then writes them.  v2 peers share one Queue across all their connIOs,
+// and their bindings are pushed by the peerHandler.
 type connIO struct {
 	pCtx     context.Context
 	ctx      context.Context
@@ -208,7 +356,7 @@ type connIO struct {
 	client   uuid.UUID
 	agent    uuid.UUID
 	decoder  *json.Decoder
-	updates  *agpl.TrackedConn
+	updates  agpl.Queue
 	bindings chan<- binding
 }
 
@@ -226,6 +374,7 @@ func newConnIO(pCtx context.Context,
 		logger = logger.With(slog.F("client_id", client))
 		id = client
 	}
+	tc := agpl.NewTrackedConn(ctx, cancel, conn, id, logger, name, 0)
 	c := &connIO{
 		pCtx:     pCtx,
 		ctx:      ctx,
@@ -234,15 +383,62 @@ func newConnIO(pCtx context.Context,
 		client:   client,
 		agent:    agent,
 		decoder:  json.NewDecoder(conn),
-		updates:  agpl.NewTrackedConn(ctx, cancel, conn, id, logger, name, 0),
+		updates:  tc,
 		bindings: bindings,
 	}
 	go c.recvLoop()
-	go c.updates.SendUpdates()
+	go tc.SendUpdates()
 	logger.Info(ctx, "serving connection")
 	return c
 }
 
+// newPeerConnIO creates a connIO for a v2 agent, or for one tunnel of a v2 client.  It's canceled when the peer
+// disconnects, or when the tunnel is removed.
+func newPeerConnIO(pCtx, peerCtx context.Context,
+	logger slog.Logger,
+	bindings chan<- binding,
+	client, agent uuid.UUID,
+	pc *agpl.PeerConn,
+) *connIO {
+	ctx, cancel := context.WithCancel(peerCtx)
+	logger = logger.With(slog.F("agent_id", agent))
+	if client != uuid.Nil {
+		logger = logger.With(slog.F("client_id", client))
+	}
+	logger.Info(ctx, "serving v2 connection")
+	return &connIO{
+		pCtx:     pCtx,
+		ctx:      ctx,
+		cancel:   cancel,
+		logger:   logger,
+		client:   client,
+		agent:    agent,
+		updates:  pc,
+		bindings: bindings,
+	}
+}
+
+// close closes the connection when the coordinator is unhealthy.  A v2 client shares its connection across the connIOs
+// of all its tunnels, so only this tunnel is closed: its binding is withdrawn and the client is told that the agent was
+// lost.  A v2 agent has a single connIO, so its connection is closed like a v1 connection.
+func (c *connIO) close() error {
+	pq, ok := c.updates.(agpl.PeerQueue)
+	if !ok || c.client == uuid.Nil {
+		return c.updates.Close()
+	}
+	c.cancel()
+	b := binding{
+		bKey: bKey{
+			client: c.client,
+			agent:  c.agent,
+		},
+	}
+	if err := sendCtx(c.pCtx, c.bindings, b); err != nil {
+		return err
+	}
+	return pq.RemovePeer(c.agent, proto.CoordinateResponse_PeerUpdate_LOST, "coordinator unhealthy")
+}
+
 func (c *connIO) recvLoop() {
 	defer func() {
 		// withdraw bindings when we exit.  We need to use the parent context here, since our own context might be
@@ -430,6 +626,7 @@ func (b *binder) writeOne(bnd binding) error {
 		_, err = b.store.DeleteTailnetClient(b.ctx, database.DeleteTailnetClientParams{
 			ID:            bnd.client,
 			CoordinatorID: b.coordinatorID,
+			AgentID:       bnd.agent,
 		})
 		b.logger.Debug(b.ctx, "deleted client binding",
 			slog.F("agent_id", bnd.agent), slog.F("client_id", bnd.client), slog.Error(err))
@@ -495,6 +692,9 @@ type mapper struct {
 
 	conns  map[bKey]*connIO
 	latest []mapping
+	// sent holds the peers whose nodes were last sent to the connections, so that v2 peers can be told which ones
+	// went away.
+	sent map[uuid.UUID]struct{}
 
 	heartbeats *heartbeats
 }
@@ -511,6 +711,7 @@ func newMapper(ctx context.Context, logger slog.Logger, mk mKey, h *heartbeats)
 		del:        make(chan *connIO),
 		update:     make(chan struct{}),
 		conns:      make(map[bKey]*connIO),
+		sent:       make(map[uuid.UUID]struct{}),
 		mappings:   make(chan []mapping),
 		heartbeats: h,
 	}
@@ -525,48 +726,58 @@ func (m *mapper) run() {
 			return
 		case c := <-m.add:
 			m.conns[bKey{c.client, c.agent}] = c
-			nodes := m.mappingsToNodes(m.latest)
-			if len(nodes) == 0 {
+			best := m.bestMappings(m.latest)
+			if len(best) == 0 {
 				m.logger.Debug(m.ctx, "skipping 0 length node update")
 				continue
 			}
-			if err := c.updates.Enqueue(nodes); err != nil {
+			for _, mpng := range best {
+				m.sent[mpng.peerID()] = struct{}{}
+			}
+			if err := enqueueMappings(c.updates, best, nil); err != nil {
 				m.logger.Error(m.ctx, "failed to enqueue node update", slog.Error(err))
 			}
 		case c := <-m.del:
 			delete(m.conns, bKey{c.client, c.agent})
 		case mappings := <-m.mappings:
 			m.latest = mappings
-			nodes := m.mappingsToNodes(mappings)
-			if len(nodes) == 0 {
-				m.logger.Debug(m.ctx, "skipping 0 length node update")
-				continue
-			}
-			for _, conn := range m.conns {
-				if err := conn.updates.Enqueue(nodes); err != nil {
-					m.logger.Error(m.ctx, "failed to enqueue node update", slog.Error(err))
-				}
-			}
+			m.updateAll("failed to enqueue node update")
 		case <-m.update:
-			nodes := m.mappingsToNodes(m.latest)
-			if len(nodes) == 0 {
-				m.logger.Debug(m.ctx, "skipping 0 length node update")
-				continue
-			}
-			for _, conn := range m.conns {
-				if err := conn.updates.Enqueue(nodes); err != nil {
-					m.logger.Error(m.ctx, "failed to enqueue triggered node update", slog.Error(err))
-				}
-			}
+			m.updateAll("failed to enqueue triggered node update")
 		}
 	}
 }
 
-// mappingsToNodes takes a set of mappings and resolves the best set of nodes.  We may get several mappings for a
-// particular connection, from different coordinators in the distributed system.  Furthermore, some coordinators
-// might be considered invalid on account of missing heartbeats.  We take the most recent mapping from a valid
-// coordinator as the "best" mapping.
-func (m *mapper) mappingsToNodes(mappings []mapping) []*agpl.Node {
+// updateAll sends the best mappings to every connection, and tells v2 peers about the peers that are no longer mapped.
+func (m *mapper) updateAll(errMsg string) {
+	best := m.bestMappings(m.latest)
+	sent := make(map[uuid.UUID]struct{}, len(best))
+	for _, mpng := range best {
+		sent[mpng.peerID()] = struct{}{}
+	}
+	var removed []uuid.UUID
+	for id := range m.sent {
+		if _, ok := sent[id]; !ok {
+			removed = append(removed, id)
+		}
+	}
+	m.sent = sent
+	if len(best) == 0 && len(removed) == 0 {
+		m.logger.Debug(m.ctx, "skipping 0 length node update")
+		return
+	}
+	for _, conn := range m.conns {
+		if err := enqueueMappings(conn.updates, best, removed); err != nil {
+			m.logger.Error(m.ctx, errMsg, slog.Error(err))
+		}
+	}
+}
+
+// bestMappings takes a set of mappings and resolves the best mapping for each connection.  We may get several
+// mappings for a particular connection, from different coordinators in the distributed system.  Furthermore, some
+// coordinators might be considered invalid on account of missing heartbeats.  We take the most recent mapping from a
+// valid coordinator as the "best" mapping.
+func (m *mapper) bestMappings(mappings []mapping) map[bKey]mapping {
 	mappings = m.heartbeats.filter(mappings)
 	best := make(map[bKey]mapping, len(mappings))
 	for _, m := range mappings {
@@ -576,11 +787,34 @@ func (m *mapper) mappingsToNodes(mappings []mapping) []*agpl.Node {
 			best[bk] = m
 		}
 	}
+	return best
+}
+
+// enqueueMappings sends the nodes of the mappings to the queue.  v1 connections get all the nodes in one update, and
+// empty updates are suppressed.  v2 peers get each node along with the ID of its peer, and are told which peers were
+// removed.
+func enqueueMappings(q agpl.Queue, best map[bKey]mapping, removed []uuid.UUID) error {
+	if pq, ok := q.(agpl.PeerQueue); ok {
+		for _, id := range removed {
+			if err := pq.RemovePeer(id, proto.CoordinateResponse_PeerUpdate_DISCONNECTED, "peer disconnected"); err != nil {
+				return err
+			}
+		}
+		for _, mpng := range best {
+			if err := pq.EnqueuePeer(mpng.peerID(), mpng.node); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if len(best) == 0 {
+		return nil
+	}
 	nodes := make([]*agpl.Node, 0, len(best))
-	for _, m := range best {
-		nodes = append(nodes, m.node)
+	for _, mpng := range best {
+		nodes = append(nodes, mpng.node)
 	}
-	return nodes
+	return q.Enqueue(nodes)
 }
 
 // querier is responsible for monitoring pubsub notifications and querying the database for the mappings that all
@@ -653,7 +887,7 @@ func (q *querier) newConn(c *connIO) {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 	if !q.healthy {
-		err := c.updates.Close()
+		err := c.close()
 		q.logger.Info(q.ctx, "closed incoming connection while unhealthy",
 			slog.Error(err),
 			slog.F("agent_id", c.agent),
@@ -983,7 +1217,8 @@ func (q *querier) updateAll() {
 }
 
 // unhealthyCloseAll marks the coordinator unhealthy and closes all connections.  We do this so that clients and agents
-// are forced to reconnect to the coordinator, and will hopefully land on a healthy coordinator.
+// are forced to reconnect to the coordinator, and will hopefully land on a healthy coordinator.  v2 clients only lose
+// their tunnels, see connIO.close.
 func (q *querier) unhealthyCloseAll() {
 	q.mu.Lock()
 	defer q.mu.Unlock()
@@ -991,7 +1226,7 @@ func (q *querier) unhealthyCloseAll() {
 	for c := range q.conns {
 		// close connections async so that we don't block the querier routine that responds to updates
 		go func(c *connIO) {
-			err := c.updates.Close()
+			err := c.close()
 			if err != nil {
 				q.logger.Debug(q.ctx, "error closing conn while unhealthy", slog.Error(err))
 			}
@@ -1069,6 +1304,14 @@ type mapping struct {
 	node        *agpl.Node
 }
 
+// peerID is the ID of the peer the node of the mapping belongs to.
+func (m mapping) peerID() uuid.UUID {
+	if m.client != uuid.Nil {
+		return m.client
+	}
+	return m.agent
+}
+
 // workQ allows scheduling work based on a key.  Multiple enqueue requests for the same key are coalesced, and
 // only one in-progress job per key is scheduled.
 type workQ[K mKey | bKey] struct {
@@ -1436,6 +1679,58 @@ func (c *pgCoord) ServeHTTPDebug(w http.ResponseWriter, r *http.Request) {
 	agpl.CoordinatorHTTPDebug(debug)(w, r)
 }
 
+// Status reads the peers and tunnels of every replica from the database.
+func (c *pgCoord) Status(ctx context.Context) (*agpl.CoordinatorStatus, error) {
+	agents, clients, err := c.querier.getAll(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("get all agents and clients: %w", err)
+	}
+	status := &agpl.CoordinatorStatus{HA: true}
+	peer := func(id, coordinatorID uuid.UUID, agent bool, updatedAt time.Time, rawNode json.RawMessage) agpl.PeerStatus {
+		p := agpl.PeerStatus{
+			ID:            id,
+			Agent:         agent,
+			CoordinatorID: coordinatorID,
+			Connected:     true,
+			LastUpdate:    updatedAt,
+		}
+		node := new(agpl.Node)
+		err := json.Unmarshal(rawNode, node)
+		if err != nil {
+			c.logger.Warn(ctx, "failed to unmarshal node", slog.F("peer_id", id), slog.Error(err))
+		} else {
+			p.Node = node
+		}
+		return p
+	}
+
+	for _, agent := range agents {
+		status.Peers = append(status.Peers, peer(agent.ID, agent.CoordinatorID, true, agent.UpdatedAt, agent.Node))
+	}
+	// Clients have a row for each of their tunnels, so only the most recently
+	// updated one is reported.
+	latestClients := map[uuid.UUID]database.TailnetClient{}
+	for agentID, conns := range clients {
+		if len(conns) == 0 {
+			continue
+		}
+		if _, ok := agents[agentID]; !ok {
+			status.Peers = append(status.Peers, agpl.PeerStatus{ID: agentID, Agent: true})
+		}
+		for _, conn := range conns {
+			status.Tunnels = append(status.Tunnels, agpl.TunnelStatus{Src: conn.ID, Dst: agentID})
+			if latest, ok := latestClients[conn.ID]; !ok || conn.UpdatedAt.After(latest.UpdatedAt) {
+				latestClients[conn.ID] = conn
+			}
+		}
+	}
+	for _, conn := range latestClients {
+		status.Peers = append(status.Peers, peer(conn.ID, conn.CoordinatorID, false, conn.UpdatedAt, conn.Node))
+	}
+	status.Sort()
+	return status, nil
+}
+
 func (c *pgCoord) htmlDebug(ctx context.Context) (agpl.HTMLDebug, error) {
 	now := time.Now()
 	data := agpl.HTMLDebug{}
@@ -27,6 +27,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/pubsub"
 	"github.com/coder/coder/v2/enterprise/tailnet"
 	agpl "github.com/coder/coder/v2/tailnet"
+	"github.com/coder/coder/v2/tailnet/proto"
 	"github.com/coder/coder/v2/testutil"
 )
 
@@ -519,6 +520,61 @@ func TestPGCoordinator_MultiAgent(t *testing.T) {
 	assertEventuallyNoAgents(ctx, t, store, agent1.id)
 }
 
+func TestPGCoordinatorDual_V2(t *testing.T) {
+	t.Parallel()
+	if !dbtestutil.WillUsePostgres() {
+		t.Skip("test only with postgres")
+	}
+	store, ps := dbtestutil.NewDB(t)
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitSuperLong)
+	defer cancel()
+	logger := slogtest.Make(t, nil).Leveled(slog.LevelDebug)
+	coord1, err := tailnet.NewPGCoord(ctx, logger.Named("coord1"), ps, store)
+	require.NoError(t, err)
+	defer coord1.Close()
+	coord2, err := tailnet.NewPGCoord(ctx, logger.Named("coord2"), ps, store)
+	require.NoError(t, err)
+	defer coord2.Close()
+
+	agent1 := newTestPeer(ctx, t, coord1, uuid.New(), agpl.AgentTunnelAuth{})
+	agent2 := newTestAgent(t, coord1, "agent2")
+	defer agent2.close()
+	client := newTestPeer(ctx, t, coord2, uuid.New(), agentsTunnelAuth{agent1.id, agent2.id})
+
+	// the client has tunnels to a v2 agent and a v1 agent at once
+	agent1.updateSelf(ctx, t, 1)
+	agent2.sendNode(&agpl.Node{PreferredDERP: 2})
+	client.addTunnel(ctx, t, agent1.id)
+	client.addTunnel(ctx, t, agent2.id)
+	client.updateSelf(ctx, t, 3)
+	client.assertEventuallyHasDERP(ctx, t, agent1.id, 1)
+	client.assertEventuallyHasDERP(ctx, t, agent2.id, 2)
+	agent1.assertEventuallyHasDERP(ctx, t, client.id, 3)
+	assertEventuallyHasDERPs(ctx, t, agent2, 3)
+
+	// removing a tunnel withdraws the client from that agent only
+	client.removeTunnel(ctx, t, agent2.id)
+	client.assertEventuallyRemoved(ctx, t, agent2.id)
+	assertEventuallyNoClientsForAgent(ctx, t, store, agent2.id)
+	clients, err := store.GetTailnetClientsForAgent(ctx, agent1.id)
+	require.NoError(t, err)
+	require.Len(t, clients, 1)
+
+	// the client is told when the agent goes away
+	agent1.disconnect(ctx, t)
+	client.assertEventuallyRemoved(ctx, t, agent1.id)
+	assertEventuallyNoAgents(ctx, t, store, agent1.id)
+
+	client.disconnect(ctx, t)
+	assertEventuallyNoClientsForAgent(ctx, t, store, agent1.id)
+
+	err = agent2.close()
+	require.NoError(t, err)
+	err = agent2.recvErr(ctx, t)
+	require.ErrorIs(t, err, io.ErrClosedPipe)
+	agent2.waitForClose(ctx, t)
+}
+
 func TestPGCoordinator_Unhealthy(t *testing.T) {
 	t.Parallel()
 
@@ -589,6 +645,63 @@ func TestPGCoordinator_Unhealthy(t *testing.T) {
 	}
 }
 
+// TestPGCoordinator_UnhealthyV2Client tests that a v2 client only loses its tunnels when the coordinator is unhealthy,
+// since it shares one connection across all of them.
+func TestPGCoordinator_UnhealthyV2Client(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitSuperLong)
+	defer cancel()
+	ctrl := gomock.NewController(t)
+	mStore := dbmock.NewMockStore(ctrl)
+	ps := pubsub.NewInMemory()
+	logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}).Leveled(slog.LevelDebug)
+
+	calls := make(chan struct{})
+	threeMissed := mStore.EXPECT().UpsertTailnetCoordinator(gomock.Any(), gomock.Any()).
+		Times(3).
+		Do(func(_ context.Context, _ uuid.UUID) { <-calls }).
+		Return(database.TailnetCoordinator{}, xerrors.New("test disconnect"))
+	// we stay unhealthy until the coordinator is closed
+	mStore.EXPECT().UpsertTailnetCoordinator(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		After(threeMissed).
+		Do(func(ctx context.Context, _ uuid.UUID) { <-ctx.Done() }).
+		Return(database.TailnetCoordinator{}, nil)
+	// extra calls we don't particularly care about for this test
+	mStore.EXPECT().CleanTailnetCoordinators(gomock.Any()).AnyTimes().Return(nil)
+	mStore.EXPECT().GetTailnetAgents(gomock.Any(), gomock.Any()).AnyTimes().Return(nil, nil)
+	mStore.EXPECT().DeleteTailnetClient(gomock.Any(), gomock.Any()).
+		AnyTimes().Return(database.DeleteTailnetClientRow{}, nil)
+	mStore.EXPECT().DeleteCoordinator(gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
+
+	uut, err := tailnet.NewPGCoord(ctx, logger, ps, mStore)
+	require.NoError(t, err)
+	defer func() {
+		err := uut.Close()
+		require.NoError(t, err)
+	}()
+	agent1, agent2 := uuid.New(), uuid.New()
+	client := newTestPeer(ctx, t, uut, uuid.New(), agentsTunnelAuth{agent1, agent2})
+	client.addTunnel(ctx, t, agent1)
+	for i := 0; i < 3; i++ {
+		select {
+		case <-ctx.Done():
+			t.Fatal("timeout")
+		case calls <- struct{}{}:
+			// OK
+		}
+	}
+	// the tunnel is lost, but the client stays connected
+	client.assertEventuallyLost(ctx, t, agent1)
+
+	// new tunnels are lost immediately
+	client.addTunnel(ctx, t, agent2)
+	client.assertEventuallyLost(ctx, t, agent2)
+
+	client.disconnect(ctx, t)
+}
+
 type testConn struct {
 	ws, serverWS net.Conn
 	nodeChan     chan []*agpl.Node
@@ -748,3 +861,116 @@ func (c *fakeCoordinator) agentNode(agentID uuid.UUID, node *agpl.Node) {
 	})
 	require.NoError(c.t, err)
 }
+
+// agentsTunnelAuth allows tunnels to any of the agents.
+type agentsTunnelAuth []uuid.UUID
+
+func (a agentsTunnelAuth) Authorize(dst uuid.UUID) bool {
+	return slices.Contains(a, dst)
+}
+
+// testPeer is a peer using the v2 coordinator protocol.
+type testPeer struct {
+	id    uuid.UUID
+	reqs  chan<- *proto.CoordinateRequest
+	resps <-chan *proto.CoordinateResponse
+}
+
+func newTestPeer(ctx context.Context, t *testing.T, coord agpl.Coordinator, id uuid.UUID, a agpl.TunnelAuth) *testPeer {
+	t.Helper()
+	reqs, resps := coord.Coordinate(ctx, id, id.String(), a)
+	return &testPeer{id: id, reqs: reqs, resps: resps}
+}
+
+func (p *testPeer) send(ctx context.Context, t *testing.T, req *proto.CoordinateRequest) {
+	t.Helper()
+	select {
+	case <-ctx.Done():
+		t.Fatalf("testPeer id %s: timeout sending request", p.id)
+	case p.reqs <- req:
+	}
+}
+
+func (p *testPeer) updateSelf(ctx context.Context, t *testing.T, preferredDERP int) {
+	t.Helper()
+	node, err := agpl.NodeToProto(&agpl.Node{PreferredDERP: preferredDERP})
+	require.NoError(t, err)
+	p.send(ctx, t, &proto.CoordinateRequest{UpdateSelf: &proto.CoordinateRequest_UpdateSelf{Node: node}})
+}
+
+func (p *testPeer) addTunnel(ctx context.Context, t *testing.T, dst uuid.UUID) {
+	t.Helper()
+	p.send(ctx, t, &proto.CoordinateRequest{AddTunnel: &proto.CoordinateRequest_Tunnel{Uuid: dst[:]}})
+}
+
+func (p *testPeer) removeTunnel(ctx context.Context, t *testing.T, dst uuid.UUID) {
+	t.Helper()
+	p.send(ctx, t, &proto.CoordinateRequest{RemoveTunnel: &proto.CoordinateRequest_Tunnel{Uuid: dst[:]}})
+}
+
+// disconnect gracefully disconnects, and waits for the coordinator to close the responses.
+func (p *testPeer) disconnect(ctx context.Context, t *testing.T) {
+	t.Helper()
+	p.send(ctx, t, &proto.CoordinateRequest{Disconnect: &proto.CoordinateRequest_Disconnect{}})
+	close(p.reqs)
+	for {
+		select {
+		case <-ctx.Done():
+			t.Fatalf("testPeer id %s: timeout waiting for responses to close", p.id)
+		case _, ok := <-p.resps:
+			if !ok {
+				return
+			}
+		}
+	}
+}
+
+func (p *testPeer) recvUpdates(ctx context.Context, t *testing.T) []*proto.CoordinateResponse_PeerUpdate {
+	t.Helper()
+	select {
+	case <-ctx.Done():
+		t.Fatalf("testPeer id %s: timeout receiving updates", p.id)
+		return nil
+	case resp, ok := <-p.resps:
+		require.True(t, ok, "responses closed")
+		require.Empty(t, resp.Error)
+		return resp.PeerUpdates
+	}
+}
+
+func (p *testPeer) assertEventuallyHasDERP(ctx context.Context, t *testing.T, peer uuid.UUID, preferredDERP int) {
+	t.Helper()
+	for {
+		for _, update := range p.recvUpdates(ctx, t) {
+			if !slices.Equal(update.Uuid, peer[:]) || update.Kind != proto.CoordinateResponse_PeerUpdate_NODE {
+				continue
+			}
+			if update.Node.PreferredDerp == int32(preferredDERP) {
+				return
+			}
+			t.Logf("expected DERP %d, got %d", preferredDERP, update.Node.PreferredDerp)
+		}
+	}
+}
+
+func (p *testPeer) assertEventuallyRemoved(ctx context.Context, t *testing.T, peer uuid.UUID) {
+	t.Helper()
+	for {
+		for _, update := range p.recvUpdates(ctx, t) {
+			if slices.Equal(update.Uuid, peer[:]) && update.Kind == proto.CoordinateResponse_PeerUpdate_DISCONNECTED {
+				return
+			}
+		}
+	}
+}
+
+func (p *testPeer) assertEventuallyLost(ctx context.Context, t *testing.T, peer uuid.UUID) {
+	t.Helper()
+	for {
+		for _, update := range p.recvUpdates(ctx, t) {
+			if slices.Equal(update.Uuid, peer[:]) && update.Kind == proto.CoordinateResponse_PeerUpdate_LOST {
+				return
+			}
+		}
+	}
+}
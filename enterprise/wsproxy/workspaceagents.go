@@ -0,0 +1,86 @@
+package wsproxy
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httputil"
+
+	"tailscale.com/tailcfg"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// workspaceAgentConnection returns the connection info of an agent from the
+// primary, so the user must be allowed to connect to it. The DERP region of
+// this proxy is preferred as the home region of the client, which keeps the
+// traffic of clients that coordinate through this proxy local to it.
+func (s *Server) workspaceAgentConnection(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	agentID, ok := httpmw.ParseUUIDParam(rw, r, "workspaceagent")
+	if !ok {
+		return
+	}
+
+	client := codersdk.New(s.DashboardURL)
+	client.HTTPClient = s.SDKClient.SDKClient.HTTPClient
+	client.SetSessionToken(httpmw.APITokenFromRequest(r))
+	connInfo, err := client.WorkspaceAgentConnectionInfo(ctx, agentID)
+	if err != nil {
+		var sdkErr *codersdk.Error
+		if errors.As(err, &sdkErr) {
+			httpapi.Write(ctx, rw, sdkErr.StatusCode(), sdkErr.Response)
+			return
+		}
+		httpapi.Write(ctx, rw, http.StatusBadGateway, codersdk.Response{
+			Message: "Failed to get connection info from the primary.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if s.Options.DERPEnabled {
+		connInfo.DERPMap = preferDERPRegion(connInfo.DERPMap, int(s.derpRegionID.Load()))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, connInfo)
+}
+
+// preferDERPRegion returns a copy of the DERP map in which every region but
+// the given one is avoided as the home region. If the region isn't in the map
+// the map is returned as is.
+func preferDERPRegion(derpMap *tailcfg.DERPMap, regionID int) *tailcfg.DERPMap {
+	if derpMap == nil {
+		return nil
+	}
+	if _, ok := derpMap.Regions[regionID]; !ok {
+		return derpMap
+	}
+	preferred := derpMap.Clone()
+	for id, region := range preferred.Regions {
+		region.Avoid = id != regionID
+	}
+	return preferred
+}
+
+// primaryProxy relays requests to the primary as they are, including the
+// credentials of the user. It's used for the coordinator and DERP map
+// websockets, which the primary authorizes and serves through its
+// coordinator, so peers are shared with every other replica and proxy.
+func (s *Server) primaryProxy() http.Handler {
+	proxy := httputil.NewSingleHostReverseProxy(s.DashboardURL)
+	director := proxy.Director
+	proxy.Director = func(r *http.Request) {
+		director(r)
+		r.Host = s.DashboardURL.Host
+	}
+	proxy.Transport = s.SDKClient.SDKClient.HTTPClient.Transport
+	proxy.ErrorHandler = func(rw http.ResponseWriter, r *http.Request, err error) {
+		s.Logger.Debug(r.Context(), "relay request to primary", slog.F("path", r.URL.Path), slog.Error(err))
+		httpapi.Write(r.Context(), rw, http.StatusBadGateway, codersdk.Response{
+			Message: "Failed to reach the primary.",
+			Detail:  err.Error(),
+		})
+	}
+	return proxy
+}
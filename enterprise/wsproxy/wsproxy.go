@@ -123,6 +123,11 @@ type Server struct {
 	// DERP
 	derpMesh      *derpmesh.Mesh
 	latestDERPMap atomic.Pointer[tailcfg.DERPMap]
+	derpRegionID  atomic.Int32
+
+	// versionDERPOnly is true if the primary only lets the proxy serve DERP
+	// because their versions are incompatible.
+	versionDERPOnly bool
 
 	// Used for graceful shutdown. Required for the dialer.
 	ctx           context.Context
@@ -236,6 +241,14 @@ func New(ctx context.Context, opts *Options) (*Server, error) {
 		return nil, xerrors.Errorf("handle register: %w", err)
 	}
 	derpServer.SetMeshKey(regResp.DERPMeshKey)
+	if regResp.DerpOnly && !opts.DERPOnly {
+		s.Logger.Warn(ctx, "primary coderd only allows serving DERP, because the versions are incompatible",
+			slog.F("version_compatibility", regResp.VersionCompatibility),
+			slog.F("version", buildinfo.Version()),
+		)
+		s.versionDERPOnly = true
+		opts.DERPOnly = true
+	}
 
 	secKey, err := workspaceapps.KeyFromString(regResp.AppSecurityKey)
 	if err != nil {
@@ -392,6 +405,16 @@ func New(ctx context.Context, opts *Options) (*Server, error) {
 		})
 	}
 
+	// CLI connections to agents coordinate through the proxy, so they can use
+	// its DERP region while the primary authorizes them.
+	r.Group(func(r chi.Router) {
+		r.Use(apiRateLimiter)
+		primaryProxy := s.primaryProxy()
+		r.Get("/api/v2/derp-map", primaryProxy.ServeHTTP)
+		r.Get("/api/v2/workspaceagents/{workspaceagent}/connection", s.workspaceAgentConnection)
+		r.Get("/api/v2/workspaceagents/{workspaceagent}/coordinate", primaryProxy.ServeHTTP)
+	})
+
 	r.Get("/api/v2/buildinfo", s.buildInfo)
 	r.Get("/healthz", func(w http.ResponseWriter, r *http.Request) { _, _ = w.Write([]byte("OK")) })
 	// TODO: @emyrk should this be authenticated or debounced?
@@ -458,6 +481,7 @@ func (s *Server) handleRegister(_ context.Context, res wsproxysdk.RegisterWorksp
 	s.derpMesh.SetAddresses(addresses, false)
 
 	s.latestDERPMap.Store(res.DERPMap)
+	s.derpRegionID.Store(res.DERPRegionID)
 
 	return nil
 }
@@ -523,10 +547,17 @@ func (s *Server) healthReport(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	// If we are in dev mode, never check versions.
-	if !buildinfo.IsDev() && !buildinfo.VersionsMatch(primaryBuild.Version, buildinfo.Version()) {
+	if !buildinfo.IsDev() {
 		// Version mismatches are not fatal, but should be reported.
+		compatibility := wsproxysdk.CheckVersionCompatibility(primaryBuild.Version, buildinfo.Version())
+		if compatibility != wsproxysdk.VersionCompatible {
+			report.Warnings = append(report.Warnings,
+				wsproxysdk.VersionCompatibilityMessage(compatibility, primaryBuild.Version, buildinfo.Version()))
+		}
+	}
+	if s.versionDERPOnly {
 		report.Warnings = append(report.Warnings,
-			fmt.Sprintf("version mismatch: primary coderd (%s) != workspace proxy (%s)", primaryBuild.Version, buildinfo.Version()))
+			"workspace proxy only serves DERP, because primary coderd found its version to be incompatible")
 	}
 
 	// TODO: We should hit the deployment config endpoint and do some config
@@ -3,6 +3,7 @@ package wsproxy_test
 import (
 	"fmt"
 	"net"
+	"net/http"
 	"testing"
 
 	"github.com/davecgh/go-spew/spew"
@@ -451,6 +452,129 @@ resourceLoop:
 	require.False(t, p2p)
 }
 
+func TestWorkspaceProxyCoordinate(t *testing.T) {
+	t.Parallel()
+
+	deploymentValues := coderdtest.DeploymentValues(t)
+	deploymentValues.Experiments = []string{
+		string(codersdk.ExperimentMoons),
+		"*",
+	}
+
+	client, closer, api, user := coderdenttest.NewWithAPI(t, &coderdenttest.Options{
+		Options: &coderdtest.Options{
+			DeploymentValues:         deploymentValues,
+			AppHostname:              "*.primary.test.coder.com",
+			IncludeProvisionerDaemon: true,
+			RealIPConfig: &httpmw.RealIPConfig{
+				TrustedOrigins: []*net.IPNet{{
+					IP:   net.ParseIP("127.0.0.1"),
+					Mask: net.CIDRMask(8, 32),
+				}},
+				TrustedHeaders: []string{
+					"CF-Connecting-IP",
+				},
+			},
+		},
+		LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureWorkspaceProxy: 1,
+			},
+		},
+	})
+	t.Cleanup(func() {
+		_ = closer.Close()
+	})
+
+	proxy := coderdenttest.NewWorkspaceProxy(t, api, client, &coderdenttest.ProxyOptions{
+		Name: "best-proxy",
+	})
+
+	// Create a workspace + agent.
+	authToken := uuid.NewString()
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:          echo.ParseComplete,
+		ProvisionApply: echo.ProvisionApplyWithAgent(authToken),
+	})
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+	agentClient := agentsdk.New(client.URL)
+	agentClient.SetSessionToken(authToken)
+	agentCloser := agent.New(agent.Options{
+		Client: agentClient,
+		Logger: slogtest.Make(t, nil).Named("agent").Leveled(slog.LevelDebug),
+	})
+	t.Cleanup(func() {
+		_ = agentCloser.Close()
+	})
+	resources := coderdtest.AwaitWorkspaceAgents(t, client, workspace.ID)
+	agentID := resources[0].Agents[0].ID
+
+	t.Run("ConnectionInfo", func(t *testing.T) {
+		t.Parallel()
+
+		proxyClient := codersdk.New(proxy.Options.AccessURL)
+		proxyClient.SetSessionToken(client.SessionToken())
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		connInfo, err := proxyClient.WorkspaceAgentConnectionInfo(ctx, agentID)
+		require.NoError(t, err)
+		require.NotNil(t, connInfo.DERPMap)
+
+		var preferred []string
+		for _, region := range connInfo.DERPMap.Regions {
+			if !region.Avoid {
+				preferred = append(preferred, region.RegionCode)
+			}
+		}
+		require.Equal(t, []string{codersdk.ProxyDERPRegionCode("best-proxy")}, preferred)
+	})
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		t.Parallel()
+
+		member, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		proxyClient := codersdk.New(proxy.Options.AccessURL)
+		proxyClient.SetSessionToken(member.SessionToken())
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := proxyClient.WorkspaceAgentConnectionInfo(ctx, agentID)
+		var sdkErr *codersdk.Error
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, http.StatusNotFound, sdkErr.StatusCode())
+	})
+
+	t.Run("Dial", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		conn, err := client.DialWorkspaceAgent(ctx, agentID, &codersdk.DialWorkspaceAgentOptions{
+			Logger: slogtest.Make(t, &slogtest.Options{
+				IgnoreErrors: true,
+			}).Named("client").Leveled(slog.LevelDebug),
+			// Force DERP.
+			BlockEndpoints:    true,
+			WorkspaceProxy:    "best-proxy",
+			WorkspaceProxyURL: proxy.Options.AccessURL,
+		})
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			err := conn.Close()
+			assert.NoError(t, err)
+		})
+
+		ok := conn.AwaitReachable(ctx)
+		require.True(t, ok)
+
+		_, p2p, _, err := conn.Ping(ctx)
+		require.NoError(t, err)
+		require.False(t, p2p)
+	})
+}
+
 func TestWorkspaceProxyWorkspaceApps_Wsconncache(t *testing.T) {
 	t.Parallel()
 
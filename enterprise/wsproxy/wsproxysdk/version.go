@@ -0,0 +1,97 @@
+package wsproxysdk
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/mod/semver"
+
+	"github.com/coder/coder/v2/buildinfo"
+)
+
+// VersionCompatibility describes whether a workspace proxy works with the
+// primary it registers with.
+type VersionCompatibility string
+
+const (
+	// VersionCompatible proxies have the same major and minor version as the
+	// primary.
+	VersionCompatible VersionCompatibility = "compatible"
+	// VersionDegraded proxies are one minor version behind the primary, which
+	// is expected while a deployment is upgraded. Features that were added in
+	// the newer version may not work through them.
+	VersionDegraded VersionCompatibility = "degraded"
+	// VersionIncompatible proxies may fail to verify app tokens or to speak
+	// the coordination protocol of the primary, so they must not serve apps.
+	VersionIncompatible VersionCompatibility = "incompatible"
+)
+
+const (
+	// VersionPolicyRefuse refuses the registration of incompatible proxies.
+	VersionPolicyRefuse = "refuse"
+	// VersionPolicyDegrade registers incompatible proxies as DERP-only, which
+	// doesn't depend on the version of the primary.
+	VersionPolicyDegrade = "degrade"
+)
+
+// CheckVersionCompatibility returns how compatible a proxy of the given
+// version is with the primary. Proxies may lag behind the primary by one minor
+// version, but must never be newer. Developer builds are only compatible with
+// the exact same build.
+func CheckVersionCompatibility(primaryVersion, proxyVersion string) VersionCompatibility {
+	if primaryVersion == proxyVersion {
+		return VersionCompatible
+	}
+	if buildinfo.IsDevVersion(primaryVersion) || buildinfo.IsDevVersion(proxyVersion) {
+		return VersionIncompatible
+	}
+	primaryMajor, primaryMinor, ok := majorMinor(primaryVersion)
+	if !ok {
+		return VersionIncompatible
+	}
+	proxyMajor, proxyMinor, ok := majorMinor(proxyVersion)
+	if !ok || primaryMajor != proxyMajor {
+		return VersionIncompatible
+	}
+	switch primaryMinor - proxyMinor {
+	case 0:
+		return VersionCompatible
+	case 1:
+		return VersionDegraded
+	default:
+		return VersionIncompatible
+	}
+}
+
+// VersionCompatibilityMessage describes the compatibility for health reports
+// and errors.
+func VersionCompatibilityMessage(compatibility VersionCompatibility, primaryVersion, proxyVersion string) string {
+	switch compatibility {
+	case VersionCompatible:
+		return fmt.Sprintf("workspace proxy (%s) is compatible with primary coderd (%s)", proxyVersion, primaryVersion)
+	case VersionDegraded:
+		return fmt.Sprintf("workspace proxy (%s) is one minor version behind primary coderd (%s), upgrade it to use newer features", proxyVersion, primaryVersion)
+	default:
+		return fmt.Sprintf("workspace proxy (%s) is incompatible with primary coderd (%s), it must have the same or the previous minor version", proxyVersion, primaryVersion)
+	}
+}
+
+func majorMinor(v string) (major int, minor int, ok bool) {
+	if !semver.IsValid(v) {
+		return 0, 0, false
+	}
+	parts := strings.SplitN(strings.TrimPrefix(semver.MajorMinor(v), "v"), ".", 2)
+	if len(parts) != 2 {
+		return 0, 0, false
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, false
+	}
+	minor, err = strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, false
+	}
+	return major, minor, true
+}
@@ -0,0 +1,38 @@
+package wsproxysdk_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/enterprise/wsproxy/wsproxysdk"
+)
+
+func TestCheckVersionCompatibility(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		Name     string
+		Primary  string
+		Proxy    string
+		Expected wsproxysdk.VersionCompatibility
+	}{
+		{Name: "Equal", Primary: "v2.1.0", Proxy: "v2.1.0", Expected: wsproxysdk.VersionCompatible},
+		{Name: "Patch", Primary: "v2.1.3", Proxy: "v2.1.0", Expected: wsproxysdk.VersionCompatible},
+		{Name: "NewerPatch", Primary: "v2.1.0", Proxy: "v2.1.3+abcdef", Expected: wsproxysdk.VersionCompatible},
+		{Name: "PreviousMinor", Primary: "v2.2.0", Proxy: "v2.1.5", Expected: wsproxysdk.VersionDegraded},
+		{Name: "OlderMinor", Primary: "v2.3.0", Proxy: "v2.1.0", Expected: wsproxysdk.VersionIncompatible},
+		{Name: "NewerMinor", Primary: "v2.1.0", Proxy: "v2.2.0", Expected: wsproxysdk.VersionIncompatible},
+		{Name: "Major", Primary: "v2.0.0", Proxy: "v1.9.0", Expected: wsproxysdk.VersionIncompatible},
+		{Name: "Invalid", Primary: "v2.1.0", Proxy: "latest", Expected: wsproxysdk.VersionIncompatible},
+		{Name: "SameDev", Primary: "v0.0.0-devel+abcdef", Proxy: "v0.0.0-devel+abcdef", Expected: wsproxysdk.VersionCompatible},
+		{Name: "OtherDev", Primary: "v0.0.0-devel+abcdef", Proxy: "v0.0.0-devel+123456", Expected: wsproxysdk.VersionIncompatible},
+		{Name: "DevProxy", Primary: "v2.1.0", Proxy: "v0.0.0-devel+abcdef", Expected: wsproxysdk.VersionIncompatible},
+	} {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tc.Expected, wsproxysdk.CheckVersionCompatibility(tc.Primary, tc.Proxy))
+		})
+	}
+}
@@ -215,6 +215,12 @@ type RegisterWorkspaceProxyResponse struct {
 	// SiblingReplicas is a list of all other replicas of the proxy that have
 	// not timed out.
 	SiblingReplicas []codersdk.Replica `json:"sibling_replicas"`
+	// VersionCompatibility is how compatible the primary found the version of
+	// the proxy.
+	VersionCompatibility VersionCompatibility `json:"version_compatibility"`
+	// DerpOnly is true if the proxy must only serve DERP, even if it didn't
+	// ask for it, because its version is incompatible with the primary.
+	DerpOnly bool `json:"derp_only"`
 }
 
 func (c *Client) RegisterWorkspaceProxy(ctx context.Context, req RegisterWorkspaceProxyRequest) (RegisterWorkspaceProxyResponse, error) {
@@ -397,6 +403,10 @@ func (c *Client) RegisterWorkspaceProxyLoop(ctx context.Context, opts RegisterWo
 			if res.DERPRegionID != originalRes.DERPRegionID {
 				failureFn(xerrors.New("DERP region ID has changed, proxy must be restarted"))
 			}
+			if res.DerpOnly != originalRes.DerpOnly {
+				failureFn(xerrors.New("DERP-only mode has changed, proxy must be restarted"))
+				return
+			}
 
 			err = opts.CallbackFn(ctx, res)
 			if err != nil {
@@ -0,0 +1,297 @@
+package terraform
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/provisionersdk/proto"
+)
+
+// parameterBlock is a coder_parameter data source read from source.
+type parameterBlock struct {
+	label string
+	name  string
+	block *hclsyntax.Block
+	file  *hcl.File
+}
+
+// loadRichParameters reads coder_parameter data sources from the module
+// without evaluating it. This recovers what a plan can't: the count
+// meta-argument becomes a visibility condition, and lifecycle pre- and
+// postconditions become validation rules that coderd can check before a
+// build starts. References to other parameters are rewritten as
+// parameters["name"] so the expressions no longer depend on Terraform
+// addresses.
+//
+// Parameters whose attributes are not literals carry only their name,
+// visibility condition and validation rules; their type is left empty.
+func loadRichParameters(workDirectory string) ([]*proto.RichParameter, error) {
+	paths, err := filepath.Glob(filepath.Join(workDirectory, "*.tf"))
+	if err != nil {
+		return nil, xerrors.Errorf("find terraform files: %w", err)
+	}
+	sort.Strings(paths)
+
+	parser := hclparse.NewParser()
+	var blocks []parameterBlock
+	for _, path := range paths {
+		file, diags := parser.ParseHCLFile(path)
+		if diags.HasErrors() {
+			return nil, xerrors.Errorf("parse %s: %s", filepath.Base(path), diags.Error())
+		}
+		body, ok := file.Body.(*hclsyntax.Body)
+		if !ok {
+			continue
+		}
+		for _, block := range body.Blocks {
+			if block.Type != "data" || len(block.Labels) != 2 || block.Labels[0] != "coder_parameter" {
+				continue
+			}
+			var name string
+			if attr, ok := block.Body.Attributes["name"]; ok {
+				_ = gohcl.DecodeExpression(attr.Expr, nil, &name)
+			}
+			if name == "" {
+				// The name isn't known until the plan, so neither
+				// rules nor references to this parameter can be
+				// resolved.
+				continue
+			}
+			blocks = append(blocks, parameterBlock{
+				label: block.Labels[1],
+				name:  name,
+				block: block,
+				file:  file,
+			})
+		}
+	}
+	if len(blocks) == 0 {
+		return nil, nil
+	}
+
+	names := make(map[string]string, len(blocks))
+	for _, b := range blocks {
+		names[b.label] = b.name
+	}
+
+	params := make([]*proto.RichParameter, 0, len(blocks))
+	for _, b := range blocks {
+		param := &proto.RichParameter{
+			Name: b.name,
+		}
+		if attr, ok := b.block.Body.Attributes["count"]; ok {
+			// A count that depends on anything but parameters is left to
+			// Terraform.
+			param.VisibleIf, _ = rewriteParameterReferences(b.file.Bytes, attr.Expr, names, false)
+		}
+		param.ValidationRules = parameterConditions(b, names)
+		decodeStaticParameter(b.block.Body, param)
+		params = append(params, param)
+	}
+	return params, nil
+}
+
+// parameterConditions converts the lifecycle pre- and postconditions of a
+// coder_parameter into validation rules. Conditions that reference anything
+// but parameters are skipped; Terraform still checks them during the build.
+func parameterConditions(b parameterBlock, names map[string]string) []*proto.RichParameterValidationRule {
+	var rules []*proto.RichParameterValidationRule
+	for _, lifecycle := range b.block.Body.Blocks {
+		if lifecycle.Type != "lifecycle" {
+			continue
+		}
+		for _, condition := range lifecycle.Body.Blocks {
+			if condition.Type != "precondition" && condition.Type != "postcondition" {
+				continue
+			}
+			attr, ok := condition.Body.Attributes["condition"]
+			if !ok {
+				continue
+			}
+			// Terraform only allows self in postconditions.
+			expression, err := rewriteParameterReferences(b.file.Bytes, attr.Expr, names, condition.Type == "postcondition")
+			if err != nil {
+				continue
+			}
+			rule := &proto.RichParameterValidationRule{
+				Condition: expression,
+			}
+			if attr, ok := condition.Body.Attributes["error_message"]; ok {
+				_ = gohcl.DecodeExpression(attr.Expr, nil, &rule.Error)
+			}
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// rewriteParameterReferences returns the source of expr with references to
+// coder_parameter values replaced by parameters["name"]. Any other reference
+// can't be evaluated outside of Terraform and is an error.
+func rewriteParameterReferences(src []byte, expr hclsyntax.Expression, names map[string]string, allowSelf bool) (string, error) {
+	type replacement struct {
+		start, end int
+		text       string
+	}
+	var replacements []replacement
+	for _, traversal := range expr.Variables() {
+		switch traversal.RootName() {
+		case "self":
+			if !allowSelf || len(traversal) != 2 || !isAttr(traversal[1], "value") {
+				return "", xerrors.Errorf("only self.value may be referenced in postconditions")
+			}
+		case "data":
+			name, ok := parameterReference(traversal, names)
+			if !ok {
+				return "", xerrors.Errorf("unsupported reference %q: only coder_parameter values may be referenced", traversalString(src, traversal))
+			}
+			rng := traversal.SourceRange()
+			replacements = append(replacements, replacement{
+				start: rng.Start.Byte,
+				end:   rng.End.Byte,
+				text:  fmt.Sprintf("parameters[%q]", name),
+			})
+		default:
+			return "", xerrors.Errorf("unsupported reference %q: only coder_parameter values may be referenced", traversalString(src, traversal))
+		}
+	}
+	sort.Slice(replacements, func(i, j int) bool {
+		return replacements[i].start < replacements[j].start
+	})
+
+	rng := expr.Range()
+	var out strings.Builder
+	offset := rng.Start.Byte
+	for _, r := range replacements {
+		_, _ = out.Write(src[offset:r.start])
+		_, _ = out.WriteString(r.text)
+		offset = r.end
+	}
+	_, _ = out.Write(src[offset:rng.End.Byte])
+	return strings.TrimSpace(out.String()), nil
+}
+
+// parameterReference matches data.coder_parameter.<label>.value, optionally
+// indexed as data.coder_parameter.<label>[0].value when count is set.
+func parameterReference(traversal hcl.Traversal, names map[string]string) (string, bool) {
+	if len(traversal) < 4 || !isAttr(traversal[1], "coder_parameter") {
+		return "", false
+	}
+	label, ok := traversal[2].(hcl.TraverseAttr)
+	if !ok {
+		return "", false
+	}
+	rest := traversal[3:]
+	if _, ok := rest[0].(hcl.TraverseIndex); ok {
+		rest = rest[1:]
+	}
+	if len(rest) != 1 || !isAttr(rest[0], "value") {
+		return "", false
+	}
+	name, ok := names[label.Name]
+	return name, ok
+}
+
+func isAttr(traverser hcl.Traverser, name string) bool {
+	attr, ok := traverser.(hcl.TraverseAttr)
+	return ok && attr.Name == name
+}
+
+func traversalString(src []byte, traversal hcl.Traversal) string {
+	rng := traversal.SourceRange()
+	return string(rng.SliceBytes(src))
+}
+
+// decodeStaticParameter fills in the attributes of param that a plan would
+// otherwise provide. If any attribute is not a literal, the type is left
+// empty to signal that the parameter can't be described from source alone.
+func decodeStaticParameter(body *hclsyntax.Body, param *proto.RichParameter) {
+	decoded := &proto.RichParameter{
+		Type:     "string",
+		Required: true,
+	}
+
+	ok := decodeAttribute(body, "display_name", &decoded.DisplayName) &&
+		decodeAttribute(body, "description", &decoded.Description) &&
+		decodeAttribute(body, "type", &decoded.Type) &&
+		decodeAttribute(body, "mutable", &decoded.Mutable) &&
+		decodeAttribute(body, "icon", &decoded.Icon) &&
+		decodeAttribute(body, "order", &decoded.Order) &&
+		decodeAttribute(body, "ephemeral", &decoded.Ephemeral)
+	if !ok {
+		return
+	}
+	if _, exists := body.Attributes["default"]; exists {
+		if !decodeAttribute(body, "default", &decoded.DefaultValue) {
+			return
+		}
+		decoded.Required = false
+	}
+
+	for _, block := range body.Blocks {
+		switch block.Type {
+		case "option":
+			option := &proto.RichParameterOption{}
+			if !decodeAttribute(block.Body, "name", &option.Name) ||
+				!decodeAttribute(block.Body, "description", &option.Description) ||
+				!decodeAttribute(block.Body, "value", &option.Value) ||
+				!decodeAttribute(block.Body, "icon", &option.Icon) {
+				return
+			}
+			decoded.Options = append(decoded.Options, option)
+		case "validation":
+			if !decodeAttribute(block.Body, "regex", &decoded.ValidationRegex) ||
+				!decodeAttribute(block.Body, "error", &decoded.ValidationError) ||
+				!decodeAttribute(block.Body, "monotonic", &decoded.ValidationMonotonic) {
+				return
+			}
+			for attr, target := range map[string]**int32{
+				"min": &decoded.ValidationMin,
+				"max": &decoded.ValidationMax,
+			} {
+				if _, exists := block.Body.Attributes[attr]; !exists {
+					continue
+				}
+				var value int32
+				if !decodeAttribute(block.Body, attr, &value) {
+					return
+				}
+				*target = PtrInt32(int(value))
+			}
+		}
+	}
+	param.DisplayName = decoded.DisplayName
+	param.Description = decoded.Description
+	param.Type = decoded.Type
+	param.Mutable = decoded.Mutable
+	param.DefaultValue = decoded.DefaultValue
+	param.Icon = decoded.Icon
+	param.Options = decoded.Options
+	param.ValidationRegex = decoded.ValidationRegex
+	param.ValidationError = decoded.ValidationError
+	param.ValidationMin = decoded.ValidationMin
+	param.ValidationMax = decoded.ValidationMax
+	param.ValidationMonotonic = decoded.ValidationMonotonic
+	param.Required = decoded.Required
+	param.Order = decoded.Order
+	param.Ephemeral = decoded.Ephemeral
+}
+
+// decodeAttribute decodes a literal attribute into target. A missing
+// attribute leaves target unchanged and is not an error.
+func decodeAttribute(body *hclsyntax.Body, name string, target interface{}) bool {
+	attr, ok := body.Attributes[name]
+	if !ok {
+		return true
+	}
+	diags := gohcl.DecodeExpression(attr.Expr, nil, target)
+	return !diags.HasErrors()
+}
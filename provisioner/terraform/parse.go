@@ -16,7 +16,7 @@ import (
 	"github.com/coder/coder/v2/provisionersdk/proto"
 )
 
-// Parse extracts Terraform variables from source-code.
+// Parse extracts Terraform variables and rich parameter rules from source-code.
 func (s *server) Parse(sess *provisionersdk.Session, _ *proto.ParseRequest, _ <-chan struct{}) *proto.ParseComplete {
 	ctx := sess.Context()
 	_, span := s.startTrace(ctx, tracing.FuncName())
@@ -46,8 +46,14 @@ func (s *server) Parse(sess *provisionersdk.Session, _ *proto.ParseRequest, _ <-
 		}
 		templateVariables = append(templateVariables, mv)
 	}
+
+	richParameters, err := loadRichParameters(sess.WorkDirectory)
+	if err != nil {
+		return provisionersdk.ParseErrorf("can't load rich parameters: %s", err)
+	}
 	return &proto.ParseComplete{
 		TemplateVariables: templateVariables,
+		RichParameters:    richParameters,
 	}
 }
 
@@ -201,6 +201,71 @@ func TestParse(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name: "rich-parameter-conditions",
+			Files: map[string]string{
+				"main.tf": `data "coder_parameter" "machine" {
+				name    = "machine"
+				default = "cpu"
+				option {
+					name  = "CPU"
+					value = "cpu"
+				}
+				option {
+					name  = "GPU"
+					value = "gpu"
+				}
+			}
+			data "coder_parameter" "gpus" {
+				count   = data.coder_parameter.machine.value == "gpu" ? 1 : 0
+				name    = "gpu_count"
+				type    = "number"
+				default = 1
+				mutable = true
+				lifecycle {
+					postcondition {
+						condition     = self.value <= 4
+						error_message = "At most 4 GPUs are available."
+					}
+					precondition {
+						condition = data.coder_workspace.me.owner != ""
+					}
+				}
+			}
+			data "coder_parameter" "image" {
+				name    = "image"
+				default = var.image
+			}`,
+			},
+			Response: &proto.ParseComplete{
+				RichParameters: []*proto.RichParameter{
+					{
+						Name:         "machine",
+						Type:         "string",
+						DefaultValue: "cpu",
+						Options: []*proto.RichParameterOption{
+							{Name: "CPU", Value: "cpu"},
+							{Name: "GPU", Value: "gpu"},
+						},
+					},
+					{
+						Name:         "gpu_count",
+						Type:         "number",
+						Mutable:      true,
+						DefaultValue: "1",
+						VisibleIf:    `parameters["machine"] == "gpu" ? 1 : 0`,
+						ValidationRules: []*proto.RichParameterValidationRule{
+							{Condition: "self.value <= 4", Error: "At most 4 GPUs are available."},
+						},
+					},
+					{
+						// The default is not a literal, so the parameter
+						// is left for the plan to describe.
+						Name: "image",
+					},
+				},
+			},
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/cli/safeexec"
+	"github.com/hashicorp/go-version"
 	semconv "go.opentelemetry.io/otel/semconv/v1.14.0"
 	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/xerrors"
@@ -68,6 +69,23 @@ func absoluteBinaryPath(ctx context.Context) (string, error) {
 	return absoluteBinary, nil
 }
 
+// ResolveVersion returns the version of Terraform that Serve will use when
+// binaryPath is empty: a compatible "terraform" binary on the $PATH, or
+// TerraformVersion which is installed otherwise.
+func ResolveVersion(ctx context.Context, binaryPath string) (*version.Version, error) {
+	if binaryPath != "" {
+		return versionFromBinaryPath(ctx, binaryPath)
+	}
+	absoluteBinary, err := absoluteBinaryPath(ctx)
+	if err != nil {
+		if xerrors.Is(err, context.Canceled) {
+			return nil, err
+		}
+		return TerraformVersion, nil
+	}
+	return versionFromBinaryPath(ctx, absoluteBinary)
+}
+
 // Serve starts a dRPC server on the provided transport speaking Terraform provisioner.
 func Serve(ctx context.Context, options *ServeOptions) error {
 	if options.BinaryPath == "" {
@@ -20,6 +20,7 @@ import (
 	"golang.org/x/xerrors"
 
 	"cdr.dev/slog"
+	"github.com/coder/coder/v2/buildinfo"
 	"github.com/coder/coder/v2/coderd/tracing"
 	"github.com/coder/coder/v2/coderd/util/ptr"
 	"github.com/coder/coder/v2/cryptorand"
@@ -53,7 +54,16 @@ type Options struct {
 	JobPollInterval     time.Duration
 	JobPollJitter       time.Duration
 	JobPollDebounce     time.Duration
+	HeartbeatInterval   time.Duration
 	Provisioners        Provisioners
+
+	// Version is the Coder version reported in heartbeats. Defaults to
+	// buildinfo.Version().
+	Version string
+	// ProvisionerVersions maps provisioner types to the version of the
+	// tool backing them, e.g. the Terraform version. It is reported in
+	// heartbeats.
+	ProvisionerVersions map[string]string
 }
 
 // New creates and starts a provisioner daemon.
@@ -76,6 +86,12 @@ func New(clientDialer Dialer, opts *Options) *Server {
 	if opts.LogBufferInterval == 0 {
 		opts.LogBufferInterval = 250 * time.Millisecond
 	}
+	if opts.HeartbeatInterval == 0 {
+		opts.HeartbeatInterval = 15 * time.Second
+	}
+	if opts.Version == "" {
+		opts.Version = buildinfo.Version()
+	}
 	if opts.TracerProvider == nil {
 		opts.TracerProvider = trace.NewNoopTracerProvider()
 	}
@@ -248,6 +264,47 @@ func (p *Server) connect(ctx context.Context) {
 			}
 		}
 	}()
+
+	go func() {
+		if p.isClosed() {
+			return
+		}
+		ticker := time.NewTicker(p.opts.HeartbeatInterval)
+		defer ticker.Stop()
+		p.heartbeat(ctx)
+		for {
+			client, ok := p.client()
+			if !ok {
+				return
+			}
+			select {
+			case <-p.closeContext.Done():
+				return
+			case <-client.DRPCConn().Closed():
+				return
+			case <-ticker.C:
+				p.heartbeat(ctx)
+			}
+		}
+	}()
+}
+
+// heartbeat reports liveness and version information to coderd.
+func (p *Server) heartbeat(ctx context.Context) {
+	client, ok := p.client()
+	if !ok {
+		return
+	}
+	_, err := client.Heartbeat(ctx, &proto.HeartbeatRequest{
+		Version:             p.opts.Version,
+		ProvisionerVersions: p.opts.ProvisionerVersions,
+	})
+	if err != nil {
+		if retryable(err) {
+			return
+		}
+		p.opts.Logger.Warn(ctx, "send heartbeat to coderd", slog.Error(err))
+	}
 }
 
 func (p *Server) nextInterval() time.Duration {
@@ -79,6 +79,51 @@ func TestProvisionerd(t *testing.T) {
 		require.NoError(t, closer.Close())
 	})
 
+	t.Run("Heartbeat", func(t *testing.T) {
+		t.Parallel()
+		done := make(chan struct{})
+		t.Cleanup(func() {
+			close(done)
+		})
+		heartbeats := make(chan *proto.HeartbeatRequest, 1)
+		server := provisionerd.New(func(ctx context.Context) (proto.DRPCProvisionerDaemonClient, error) {
+			return createProvisionerDaemonClient(t, done, provisionerDaemonTestServer{
+				acquireJob: func(ctx context.Context, _ *proto.Empty) (*proto.AcquiredJob, error) {
+					return &proto.AcquiredJob{}, nil
+				},
+				updateJob: noopUpdateJob,
+				heartbeat: func(ctx context.Context, req *proto.HeartbeatRequest) (*proto.Empty, error) {
+					select {
+					case heartbeats <- req:
+					default:
+					}
+					return &proto.Empty{}, nil
+				},
+			}), nil
+		}, &provisionerd.Options{
+			Logger:            slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}).Named("provisionerd").Leveled(slog.LevelDebug),
+			JobPollInterval:   50 * time.Millisecond,
+			HeartbeatInterval: 50 * time.Millisecond,
+			Version:           "v2.1.0",
+			ProvisionerVersions: map[string]string{
+				"terraform": "1.5.5",
+			},
+		})
+		t.Cleanup(func() {
+			_ = server.Close()
+		})
+
+		var req *proto.HeartbeatRequest
+		select {
+		case req = <-heartbeats:
+		case <-time.After(testutil.WaitShort):
+			t.Fatal("timed out waiting for heartbeat")
+		}
+		require.Equal(t, "v2.1.0", req.Version)
+		require.Equal(t, map[string]string{"terraform": "1.5.5"}, req.ProvisionerVersions)
+		require.NoError(t, server.Close())
+	})
+
 	t.Run("AcquireEmptyJob", func(t *testing.T) {
 		// The provisioner daemon is supposed to skip the job acquire if
 		// the job provided is empty. This is to show it successfully
@@ -344,6 +389,106 @@ func TestProvisionerd(t *testing.T) {
 		assert.True(t, didComplete.Load(), "should complete the job")
 	})
 
+	t.Run("TemplateImportParameterRules", func(t *testing.T) {
+		t.Parallel()
+		done := make(chan struct{})
+		t.Cleanup(func() {
+			close(done)
+		})
+		var (
+			didAcquireJob atomic.Bool
+			completeChan  = make(chan struct{})
+			completeOnce  sync.Once
+			completed     = make(chan *proto.CompletedJob, 1)
+		)
+
+		closer := createProvisionerd(t, func(ctx context.Context) (proto.DRPCProvisionerDaemonClient, error) {
+			return createProvisionerDaemonClient(t, done, provisionerDaemonTestServer{
+				acquireJob: func(ctx context.Context, _ *proto.Empty) (*proto.AcquiredJob, error) {
+					if !didAcquireJob.CAS(false, true) {
+						completeOnce.Do(func() { close(completeChan) })
+						return &proto.AcquiredJob{}, nil
+					}
+
+					return &proto.AcquiredJob{
+						JobId:                 "test",
+						Provisioner:           "someprovisioner",
+						TemplateSourceArchive: createTar(t, map[string]string{"test.txt": "content"}),
+						Type: &proto.AcquiredJob_TemplateImport_{
+							TemplateImport: &proto.AcquiredJob_TemplateImport{
+								Metadata: &sdkproto.Metadata{},
+							},
+						},
+					}, nil
+				},
+				updateJob: noopUpdateJob,
+				completeJob: func(ctx context.Context, job *proto.CompletedJob) (*proto.Empty, error) {
+					completed <- job
+					return &proto.Empty{}, nil
+				},
+			}), nil
+		}, provisionerd.Provisioners{
+			"someprovisioner": createProvisionerClient(t, done, provisionerTestServer{
+				parse: func(
+					_ *provisionersdk.Session,
+					_ *sdkproto.ParseRequest,
+					_ <-chan struct{},
+				) *sdkproto.ParseComplete {
+					return &sdkproto.ParseComplete{
+						RichParameters: []*sdkproto.RichParameter{{
+							Name: "machine",
+							Type: "string",
+							ValidationRules: []*sdkproto.RichParameterValidationRule{{
+								Condition: `self.value != ""`,
+							}},
+						}, {
+							Name:      "gpu_count",
+							Type:      "number",
+							VisibleIf: `parameters["machine"] == "gpu" ? 1 : 0`,
+						}, {
+							// Only known from source, so it can't be added.
+							Name:      "image",
+							VisibleIf: `parameters["machine"] == "gpu"`,
+						}},
+					}
+				},
+				plan: func(
+					_ *provisionersdk.Session,
+					_ *sdkproto.PlanRequest,
+					_ <-chan struct{},
+				) *sdkproto.PlanComplete {
+					return &sdkproto.PlanComplete{
+						Resources: []*sdkproto.Resource{},
+						Parameters: []*sdkproto.RichParameter{{
+							Name:         "machine",
+							Type:         "string",
+							DefaultValue: "cpu",
+						}},
+					}
+				},
+				apply: func(
+					_ *provisionersdk.Session,
+					_ *sdkproto.ApplyRequest,
+					_ <-chan struct{},
+				) *sdkproto.ApplyComplete {
+					t.Error("dry run should not apply")
+					return &sdkproto.ApplyComplete{}
+				},
+			}),
+		})
+
+		require.Condition(t, closedWithin(completeChan, testutil.WaitShort))
+		require.NoError(t, closer.Close())
+		job := <-completed
+		params := job.GetTemplateImport().GetRichParameters()
+		require.Len(t, params, 2)
+		require.Equal(t, "machine", params[0].Name)
+		require.Equal(t, "cpu", params[0].DefaultValue)
+		require.Len(t, params[0].ValidationRules, 1)
+		require.Equal(t, "gpu_count", params[1].Name)
+		require.Equal(t, `parameters["machine"] == "gpu" ? 1 : 0`, params[1].VisibleIf)
+	})
+
 	t.Run("TemplateDryRun", func(t *testing.T) {
 		t.Parallel()
 		done := make(chan struct{})
@@ -1222,6 +1367,7 @@ type provisionerDaemonTestServer struct {
 	updateJob   func(ctx context.Context, update *proto.UpdateJobRequest) (*proto.UpdateJobResponse, error)
 	failJob     func(ctx context.Context, job *proto.FailedJob) (*proto.Empty, error)
 	completeJob func(ctx context.Context, job *proto.CompletedJob) (*proto.Empty, error)
+	heartbeat   func(ctx context.Context, req *proto.HeartbeatRequest) (*proto.Empty, error)
 }
 
 func (p *provisionerDaemonTestServer) AcquireJob(ctx context.Context, empty *proto.Empty) (*proto.AcquiredJob, error) {
@@ -1248,3 +1394,10 @@ func (p *provisionerDaemonTestServer) FailJob(ctx context.Context, job *proto.Fa
 func (p *provisionerDaemonTestServer) CompleteJob(ctx context.Context, job *proto.CompletedJob) (*proto.Empty, error) {
 	return p.completeJob(ctx, job)
 }
+
+func (p *provisionerDaemonTestServer) Heartbeat(ctx context.Context, req *proto.HeartbeatRequest) (*proto.Empty, error) {
+	if p.heartbeat == nil {
+		return &proto.Empty{}, nil
+	}
+	return p.heartbeat(ctx, req)
+}
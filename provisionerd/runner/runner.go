@@ -517,7 +517,7 @@ func (r *Runner) runTemplateImport(ctx context.Context) (*proto.CompletedJob, *p
 		Stage:     "Parsing template parameters",
 		CreatedAt: time.Now().UnixMilli(),
 	})
-	templateVariables, readme, err := r.runTemplateImportParse(ctx)
+	parse, err := r.runTemplateImportParse(ctx)
 	if err != nil {
 		return nil, r.failedJobf("run parse: %s", err)
 	}
@@ -526,9 +526,9 @@ func (r *Runner) runTemplateImport(ctx context.Context) (*proto.CompletedJob, *p
 	// to store in database and filter valid ones.
 	updateResponse, err := r.update(ctx, &proto.UpdateJobRequest{
 		JobId:              r.job.JobId,
-		TemplateVariables:  templateVariables,
+		TemplateVariables:  parse.TemplateVariables,
 		UserVariableValues: r.job.GetTemplateImport().GetUserVariableValues(),
-		Readme:             readme,
+		Readme:             parse.Readme,
 	})
 	if err != nil {
 		return nil, r.failedJobf("update job: %s", err)
@@ -570,28 +570,26 @@ func (r *Runner) runTemplateImport(ctx context.Context) (*proto.CompletedJob, *p
 			TemplateImport: &proto.CompletedJob_TemplateImport{
 				StartResources:   startProvision.Resources,
 				StopResources:    stopProvision.Resources,
-				RichParameters:   startProvision.Parameters,
+				RichParameters:   mergeParsedParameters(startProvision.Parameters, parse.RichParameters),
 				GitAuthProviders: startProvision.GitAuthProviders,
 			},
 		},
 	}, nil
 }
 
-// Parses template variables and README from source.
-func (r *Runner) runTemplateImportParse(ctx context.Context) (
-	vars []*sdkproto.TemplateVariable, readme []byte, err error,
-) {
+// Parses template variables, rich parameter rules and README from source.
+func (r *Runner) runTemplateImportParse(ctx context.Context) (*sdkproto.ParseComplete, error) {
 	ctx, span := r.startTrace(ctx, tracing.FuncName())
 	defer span.End()
 
-	err = r.session.Send(&sdkproto.Request{Type: &sdkproto.Request_Parse{Parse: &sdkproto.ParseRequest{}}})
+	err := r.session.Send(&sdkproto.Request{Type: &sdkproto.Request_Parse{Parse: &sdkproto.ParseRequest{}}})
 	if err != nil {
-		return nil, nil, xerrors.Errorf("parse source: %w", err)
+		return nil, xerrors.Errorf("parse source: %w", err)
 	}
 	for {
 		msg, err := r.session.Recv()
 		if err != nil {
-			return nil, nil, xerrors.Errorf("recv parse source: %w", err)
+			return nil, xerrors.Errorf("recv parse source: %w", err)
 		}
 		switch msgType := msg.Type.(type) {
 		case *sdkproto.Response_Log:
@@ -611,21 +609,47 @@ func (r *Runner) runTemplateImportParse(ctx context.Context) (
 			pc := msgType.Parse
 			r.logger.Debug(context.Background(), "parse complete",
 				slog.F("template_variables", pc.TemplateVariables),
+				slog.F("rich_parameters", len(pc.RichParameters)),
 				slog.F("readme_len", len(pc.Readme)),
 				slog.F("error", pc.Error),
 			)
 			if pc.Error != "" {
-				return nil, nil, xerrors.Errorf("parse error: %s", pc.Error)
+				return nil, xerrors.Errorf("parse error: %s", pc.Error)
 			}
 
-			return msgType.Parse.TemplateVariables, msgType.Parse.Readme, nil
+			return pc, nil
 		default:
-			return nil, nil, xerrors.Errorf("invalid message type %q received from provisioner",
+			return nil, xerrors.Errorf("invalid message type %q received from provisioner",
 				reflect.TypeOf(msg.Type).String())
 		}
 	}
 }
 
+// mergeParsedParameters adds the visibility conditions and validation rules
+// found while parsing to the parameters revealed by the plan. Parameters that
+// the plan hides because of their visibility condition are added from source,
+// provided all of their attributes could be read there.
+func mergeParsedParameters(planned, parsed []*sdkproto.RichParameter) []*sdkproto.RichParameter {
+	merged := planned
+	for _, parsedParameter := range parsed {
+		var found bool
+		for _, plannedParameter := range planned {
+			if plannedParameter.Name != parsedParameter.Name {
+				continue
+			}
+			plannedParameter.VisibleIf = parsedParameter.VisibleIf
+			plannedParameter.ValidationRules = parsedParameter.ValidationRules
+			found = true
+			break
+		}
+		if found || parsedParameter.VisibleIf == "" || parsedParameter.Type == "" {
+			continue
+		}
+		merged = append(merged, parsedParameter)
+	}
+	return merged
+}
+
 type templateImportProvision struct {
 	Resources        []*sdkproto.Resource
 	Parameters       []*sdkproto.RichParameter
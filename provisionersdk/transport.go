@@ -5,7 +5,6 @@ import (
 	"net"
 	"sync"
 
-	"github.com/hashicorp/yamux"
 	"github.com/valyala/fasthttp/fasthttputil"
 	"storj.io/drpc"
 	"storj.io/drpc/drpcconn"
@@ -19,53 +18,6 @@ const (
 	MaxMessageSize = 4 << 20
 )
 
-// MultiplexedConn returns a multiplexed dRPC connection from a yamux Session.
-func MultiplexedConn(session *yamux.Session) drpc.Conn {
-	return &multiplexedDRPC{session}
-}
-
-// Allows concurrent requests on a single dRPC connection.
-// Required for calling functions concurrently.
-type multiplexedDRPC struct {
-	session *yamux.Session
-}
-
-func (m *multiplexedDRPC) Close() error {
-	return m.session.Close()
-}
-
-func (m *multiplexedDRPC) Closed() <-chan struct{} {
-	return m.session.CloseChan()
-}
-
-func (m *multiplexedDRPC) Invoke(ctx context.Context, rpc string, enc drpc.Encoding, inMessage, outMessage drpc.Message) error {
-	conn, err := m.session.Open()
-	if err != nil {
-		return err
-	}
-	dConn := drpcconn.New(conn)
-	defer func() {
-		_ = dConn.Close()
-	}()
-	return dConn.Invoke(ctx, rpc, enc, inMessage, outMessage)
-}
-
-func (m *multiplexedDRPC) NewStream(ctx context.Context, rpc string, enc drpc.Encoding) (drpc.Stream, error) {
-	conn, err := m.session.Open()
-	if err != nil {
-		return nil, err
-	}
-	dConn := drpcconn.New(conn)
-	stream, err := dConn.NewStream(ctx, rpc, enc)
-	if err == nil {
-		go func() {
-			<-stream.Context().Done()
-			_ = dConn.Close()
-		}()
-	}
-	return stream, err
-}
-
 func MemTransportPipe() (drpc.Conn, net.Listener) {
 	m := &memDRPC{
 		closed: make(chan struct{}),
@@ -123,6 +123,10 @@ func updateAuditDoc(doc []byte, auditableResourcesMap AuditableResourcesMap) ([]
 		if resourceName == "AuditableGroup" {
 			readableResourceName = "Group"
 		}
+		// AuditableUserMFA only adds the username, which is the target.
+		if resourceName == "AuditableUserMFA" {
+			readableResourceName = "UserMFA"
+		}
 
 		// Create a string of audit actions for each resource
 		var auditActions []string
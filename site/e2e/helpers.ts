@@ -408,6 +408,7 @@ const createTemplateVersionTar = async (
       templateVariables: [],
       error: "",
       readme: new Uint8Array(),
+      richParameters: [],
       ...response.parse,
     } as ParseComplete
     tar.addFile(
@@ -50,6 +50,17 @@ export interface RichParameterOption {
   icon: string
 }
 
+/**
+ * RichParameterValidationRule is a dynamic validation rule for a parameter.
+ * The condition is an HCL expression that must evaluate to true. It may
+ * reference the value being validated as self.value and the values of other
+ * parameters as parameters["name"].
+ */
+export interface RichParameterValidationRule {
+  condition: string
+  error: string
+}
+
 /** RichParameter represents a variable that is exposed. */
 export interface RichParameter {
   name: string
@@ -69,6 +80,15 @@ export interface RichParameter {
   displayName: string
   order: number
   ephemeral: boolean
+  /**
+   * visible_if is an HCL expression that controls whether the parameter is
+   * shown. It may reference the values of other parameters as
+   * parameters["name"] and evaluates to a bool, or to a number as with the
+   * Terraform count meta-argument, where zero hides the parameter. An empty
+   * expression means the parameter is always visible.
+   */
+  visibleIf: string
+  validationRules: RichParameterValidationRule[]
 }
 
 /** RichParameterValue holds the key/value mapping of a parameter. */
@@ -219,6 +239,12 @@ export interface ParseComplete {
   error: string
   templateVariables: TemplateVariable[]
   readme: Uint8Array
+  /**
+   * rich_parameters are extracted from the source without evaluating it.
+   * They carry visibility conditions and validation rules, and describe
+   * parameters that a plan with default values would not reveal.
+   */
+  richParameters: RichParameter[]
 }
 
 /** PlanRequest asks the provisioner to plan what resources & parameters it will create */
@@ -326,6 +352,21 @@ export const RichParameterOption = {
   },
 }
 
+export const RichParameterValidationRule = {
+  encode(
+    message: RichParameterValidationRule,
+    writer: _m0.Writer = _m0.Writer.create(),
+  ): _m0.Writer {
+    if (message.condition !== "") {
+      writer.uint32(10).string(message.condition)
+    }
+    if (message.error !== "") {
+      writer.uint32(18).string(message.error)
+    }
+    return writer
+  },
+}
+
 export const RichParameter = {
   encode(
     message: RichParameter,
@@ -379,6 +420,15 @@ export const RichParameter = {
     if (message.ephemeral === true) {
       writer.uint32(136).bool(message.ephemeral)
     }
+    if (message.visibleIf !== "") {
+      writer.uint32(146).string(message.visibleIf)
+    }
+    for (const v of message.validationRules) {
+      RichParameterValidationRule.encode(
+        v!,
+        writer.uint32(154).fork(),
+      ).ldelim()
+    }
     return writer
   },
 }
@@ -775,6 +825,9 @@ export const ParseComplete = {
     if (message.readme.length !== 0) {
       writer.uint32(26).bytes(message.readme)
     }
+    for (const v of message.richParameters) {
+      RichParameter.encode(v!, writer.uint32(34).fork()).ldelim()
+    }
     return writer
   },
 }
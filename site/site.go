@@ -50,6 +50,11 @@ var (
 	errorHTML string
 
 	errorTemplate *htmltemplate.Template
+
+	//go:embed static/oauth2allow.html
+	oauthHTML string
+
+	oauthTemplate *htmltemplate.Template
 )
 
 func init() {
@@ -58,6 +63,11 @@ func init() {
 	if err != nil {
 		panic(err)
 	}
+
+	oauthTemplate, err = htmltemplate.New("oauth2allow").Parse(oauthHTML)
+	if err != nil {
+		panic(err)
+	}
 }
 
 type Options struct {
@@ -777,6 +787,39 @@ func RenderStaticErrorPage(rw http.ResponseWriter, r *http.Request, data ErrorPa
 	}
 }
 
+// RenderOAuthAllowData contains the variables that are found in
+// site/static/oauth2allow.html.
+type RenderOAuthAllowData struct {
+	AppIcon     string
+	AppName     string
+	CancelURI   string
+	RedirectURI string
+	Username    string
+	// CSRFToken is set by RenderOAuthAllowPage.
+	CSRFToken string
+}
+
+// RenderOAuthAllowPage renders the page a user sees before allowing an OAuth2
+// app to act on their behalf, when Coder is the authorization server. Like the
+// error page it is rendered statically, since the form is posted back to the
+// authorize endpoint along with its query parameters.
+func RenderOAuthAllowPage(rw http.ResponseWriter, r *http.Request, data RenderOAuthAllowData) {
+	rw.Header().Set("Content-Type", "text/html; charset=utf-8")
+	// The page must not be framed, or another site could trick the user
+	// into clicking the allow button.
+	rw.Header().Set("Content-Security-Policy", "frame-ancestors 'none'")
+	rw.Header().Set("X-Frame-Options", "DENY")
+	data.CSRFToken = nosurf.Token(r)
+
+	err := oauthTemplate.Execute(rw, data)
+	if err != nil {
+		httpapi.Write(r.Context(), rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to render oauth page: " + err.Error(),
+		})
+		return
+	}
+}
+
 type binHashCache struct {
 	binFS http.FileSystem
 
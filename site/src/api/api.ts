@@ -91,11 +91,13 @@ export const provisioners: TypesGen.ProvisionerDaemon[] = [
 export const login = async (
   email: string,
   password: string,
+  mfaCode?: string,
 ): Promise<TypesGen.LoginWithPasswordResponse> => {
-  const payload = JSON.stringify({
+  const payload: TypesGen.LoginWithPasswordRequest = {
     email,
     password,
-  })
+    mfa_code: mfaCode,
+  }
 
   const response = await axios.post<TypesGen.LoginWithPasswordResponse>(
     "/api/v2/users/login",
@@ -108,6 +110,46 @@ export const login = async (
   return response.data
 }
 
+export const enrollTOTPWithPassword = async (
+  email: string,
+  password: string,
+): Promise<TypesGen.TOTPEnrollment> => {
+  const payload: TypesGen.LoginWithPasswordRequest = {
+    email,
+    password,
+  }
+
+  const response = await axios.post<TypesGen.TOTPEnrollment>(
+    "/api/v2/users/login/mfa/enroll",
+    payload,
+    {
+      headers: { ...CONTENT_TYPE_JSON },
+    },
+  )
+
+  return response.data
+}
+
+export const loginWithLDAP = async (
+  username: string,
+  password: string,
+): Promise<TypesGen.LoginWithPasswordResponse> => {
+  const payload: TypesGen.LoginWithLDAPRequest = {
+    username,
+    password,
+  }
+
+  const response = await axios.post<TypesGen.LoginWithPasswordResponse>(
+    "/api/v2/users/ldap/login",
+    payload,
+    {
+      headers: { ...CONTENT_TYPE_JSON },
+    },
+  )
+
+  return response.data
+}
+
 export const convertToOAUTH = async (request: TypesGen.ConvertLoginRequest) => {
   const response = await axios.post<TypesGen.OAuthConversionResponse>(
     "/api/v2/users/me/convert-login",
@@ -759,6 +801,43 @@ export const regenerateUserSSHKey = async (
   return response.data
 }
 
+export const getUserMFA = async (
+  userId = "me",
+): Promise<TypesGen.UserMFA> => {
+  const response = await axios.get<TypesGen.UserMFA>(
+    `/api/v2/users/${userId}/mfa`,
+  )
+  return response.data
+}
+
+export const enrollUserTOTP = async (
+  userId = "me",
+): Promise<TypesGen.TOTPEnrollment> => {
+  const response = await axios.post<TypesGen.TOTPEnrollment>(
+    `/api/v2/users/${userId}/mfa/totp`,
+  )
+  return response.data
+}
+
+export const verifyUserTOTP = async (
+  code: string,
+  userId = "me",
+): Promise<TypesGen.MFARecoveryCodes> => {
+  const payload: TypesGen.VerifyTOTPRequest = { code }
+  const response = await axios.post<TypesGen.MFARecoveryCodes>(
+    `/api/v2/users/${userId}/mfa/totp/verify`,
+    payload,
+  )
+  return response.data
+}
+
+export const resetUserMFA = async (
+  req: TypesGen.ResetUserMFARequest,
+  userId = "me",
+): Promise<void> => {
+  await axios.delete(`/api/v2/users/${userId}/mfa`, { data: req })
+}
+
 export const getWorkspaceBuilds = async (
   workspaceId: string,
   since: Date,
@@ -18,6 +18,8 @@ export interface APIKey {
   readonly updated_at: string
   readonly login_type: LoginType
   readonly scope: APIKeyScope
+  readonly scopes?: APIKeyScope[]
+  readonly allow_list?: string[]
   readonly token_name: string
   readonly lifetime_seconds: number
 }
@@ -115,6 +117,8 @@ export interface AuthMethods {
   readonly password: AuthMethod
   readonly github: AuthMethod
   readonly oidc: OIDCAuthMethod
+  readonly oidc_providers: OIDCProviderAuthMethod[]
+  readonly ldap: LDAPAuthMethod
 }
 
 // From codersdk/authorization.go
@@ -173,6 +177,14 @@ export interface CreateFirstUserResponse {
   readonly organization_id: string
 }
 
+// From codersdk/gitsshkey.go
+export interface CreateGitSSHKeyRequest {
+  readonly name: string
+  readonly algorithm?: string
+  readonly private_key?: string
+  readonly hosts?: string[]
+}
+
 // From codersdk/groups.go
 export interface CreateGroupRequest {
   readonly name: string
@@ -240,6 +252,8 @@ export interface CreateTokenRequest {
   readonly lifetime: number
   readonly scope: APIKeyScope
   readonly token_name: string
+  readonly scopes?: APIKeyScope[]
+  readonly allow_list?: string[]
 }
 
 // From codersdk/users.go
@@ -250,6 +264,7 @@ export interface CreateUserRequest {
   readonly login_type: LoginType
   readonly disable_login: boolean
   readonly organization_id: string
+  readonly service_account_owner_group_id?: string
 }
 
 // From codersdk/workspaces.go
@@ -366,6 +381,7 @@ export interface DeploymentValues {
   readonly pg_connection_url?: string
   readonly oauth2?: OAuth2Config
   readonly oidc?: OIDCConfig
+  readonly ldap?: LDAPConfig
   readonly telemetry?: TelemetryConfig
   readonly tls?: TLSConfig
   readonly trace?: TraceConfig
@@ -392,14 +408,19 @@ export interface DeploymentValues {
   readonly max_session_expiry?: number
   readonly disable_session_expiry_refresh?: boolean
   readonly disable_password_auth?: boolean
+  readonly require_mfa?: boolean
   readonly support?: SupportConfig
   // Named type "github.com/coder/coder/v2/cli/clibase.Struct[[]github.com/coder/coder/v2/codersdk.GitAuthConfig]" unknown, using "any"
   // eslint-disable-next-line @typescript-eslint/no-explicit-any -- External type
   readonly git_auth?: any
+  // Named type "github.com/coder/coder/v2/cli/clibase.Struct[[]github.com/coder/coder/v2/codersdk.WorkloadIdentityProvider]" unknown, using "any"
+  // eslint-disable-next-line @typescript-eslint/no-explicit-any -- External type
+  readonly workload_identity_providers?: any
   readonly config_ssh?: SSHConfig
   readonly wgtunnel_host?: string
   readonly disable_owner_workspace_exec?: boolean
   readonly proxy_health_status_interval?: number
+  readonly proxy_version_policy?: string
   readonly enable_terraform_debug_mode?: boolean
   readonly user_quiet_hours_schedule?: UserQuietHoursScheduleConfig
   // This is likely an enum in an external package ("github.com/coder/coder/v2/cli/clibase.YAMLConfigPath")
@@ -410,6 +431,19 @@ export interface DeploymentValues {
   readonly address?: any
 }
 
+// From codersdk/templates.go
+export interface EgressPolicy {
+  readonly default_action?: EgressAction
+  readonly rules?: EgressRule[]
+}
+
+// From codersdk/templates.go
+export interface EgressRule {
+  readonly action: EgressAction
+  readonly cidr?: string
+  readonly ports?: string[]
+}
+
 // From codersdk/deployment.go
 export interface Entitlements {
   readonly features: Record<FeatureName, Feature>
@@ -476,6 +510,9 @@ export interface GitAuthConfig {
   readonly scopes: string[]
   readonly device_flow: boolean
   readonly device_code_url: string
+  readonly ssh_keys_url: string
+  readonly display_name: string
+  readonly display_icon: string
 }
 
 // From codersdk/gitauth.go
@@ -506,6 +543,10 @@ export interface GitSSHKey {
   readonly created_at: string
   readonly updated_at: string
   readonly public_key: string
+  readonly name: string
+  readonly key_type: string
+  readonly imported: boolean
+  readonly hosts: string[]
 }
 
 // From codersdk/groups.go
@@ -538,6 +579,37 @@ export interface IssueReconnectingPTYSignedTokenResponse {
   readonly signed_token: string
 }
 
+// From codersdk/users.go
+export interface LDAPAuthMethod extends AuthMethod {
+  readonly signInText: string
+}
+
+// From codersdk/deployment.go
+export interface LDAPConfig {
+  readonly url: string
+  readonly start_tls: boolean
+  readonly tls_ca_file: string
+  readonly bind_dn: string
+  readonly bind_password: string
+  readonly base_dn: string
+  readonly user_filter: string
+  readonly username_attribute: string
+  readonly email_attribute: string
+  readonly group_attribute: string
+  // Named type "github.com/coder/coder/v2/cli/clibase.Struct[map[string]string]" unknown, using "any"
+  // eslint-disable-next-line @typescript-eslint/no-explicit-any -- External type
+  readonly group_mapping: any
+  readonly group_auto_create: boolean
+  // Named type "github.com/coder/coder/v2/cli/clibase.Struct[map[string][]string]" unknown, using "any"
+  // eslint-disable-next-line @typescript-eslint/no-explicit-any -- External type
+  readonly user_role_mapping: any
+  // This is likely an enum in an external package ("github.com/coder/coder/v2/cli/clibase.StringArray")
+  readonly user_roles_default: string[]
+  readonly allow_signups: boolean
+  readonly sync_interval: number
+  readonly sign_in_text: string
+}
+
 // From codersdk/licenses.go
 export interface License {
   readonly id: number
@@ -564,15 +636,28 @@ export interface LoggingConfig {
   readonly stackdriver: string
 }
 
+// From codersdk/users.go
+export interface LoginWithLDAPRequest {
+  readonly username: string
+  readonly password: string
+}
+
 // From codersdk/users.go
 export interface LoginWithPasswordRequest {
   readonly email: string
   readonly password: string
+  readonly mfa_code?: string
 }
 
 // From codersdk/users.go
 export interface LoginWithPasswordResponse {
   readonly session_token: string
+  readonly mfa_recovery_codes?: string[]
+}
+
+// From codersdk/mfa.go
+export interface MFARecoveryCodes {
+  readonly recovery_codes: string[]
 }
 
 // From codersdk/users.go
@@ -582,6 +667,12 @@ export interface MinimalUser {
   readonly avatar_url: string
 }
 
+// From codersdk/oauth2.go
+export interface OAuth2AppEndpoints {
+  readonly authorization: string
+  readonly token: string
+}
+
 // From codersdk/deployment.go
 export interface OAuth2Config {
   readonly github: OAuth2GithubConfig
@@ -600,6 +691,42 @@ export interface OAuth2GithubConfig {
   readonly enterprise_base_url: string
 }
 
+// From codersdk/oauth2.go
+export interface OAuth2ProviderApp {
+  readonly id: string
+  readonly name: string
+  readonly callback_url: string
+  readonly icon: string
+  readonly endpoints: OAuth2AppEndpoints
+}
+
+// From codersdk/oauth2.go
+export interface OAuth2ProviderAppSecret {
+  readonly id: string
+  readonly last_used_at?: string
+  readonly client_secret_truncated: string
+}
+
+// From codersdk/oauth2.go
+export interface OAuth2ProviderAppSecretFull {
+  readonly id: string
+  readonly client_secret_full: string
+}
+
+// From codersdk/oauth2.go
+export interface OAuth2TokenError {
+  readonly error: string
+  readonly error_description?: string
+}
+
+// From codersdk/oauth2.go
+export interface OAuth2TokenResponse {
+  readonly access_token: string
+  readonly token_type: string
+  readonly expires_in: number
+  readonly refresh_token: string
+}
+
 // From codersdk/users.go
 export interface OAuthConversionResponse {
   readonly state_string: string
@@ -649,6 +776,39 @@ export interface OIDCConfig {
   readonly user_roles_default: string[]
   readonly sign_in_text: string
   readonly icon_url: string
+  // Named type "github.com/coder/coder/v2/cli/clibase.Struct[[]github.com/coder/coder/v2/codersdk.OIDCProviderConfig]" unknown, using "any"
+  // eslint-disable-next-line @typescript-eslint/no-explicit-any -- External type
+  readonly providers: any
+}
+
+// From codersdk/users.go
+export interface OIDCProviderAuthMethod {
+  readonly id: string
+  readonly signInText: string
+  readonly iconUrl: string
+}
+
+// From codersdk/deployment.go
+export interface OIDCProviderConfig {
+  readonly id: string
+  readonly issuer_url: string
+  readonly client_id: string
+  readonly scopes: string[]
+  readonly auth_url_params: Record<string, string>
+  readonly email_domain: string[]
+  readonly allow_signups: boolean
+  readonly ignore_email_verified: boolean
+  readonly ignore_user_info: boolean
+  readonly username_field: string
+  readonly email_field: string
+  readonly groups_field: string
+  readonly group_mapping: Record<string, string>
+  readonly group_auto_create: boolean
+  readonly user_role_field: string
+  readonly user_role_mapping: Record<string, string[]>
+  readonly user_roles_default: string[]
+  readonly sign_in_text: string
+  readonly icon_url: string
 }
 
 // From codersdk/organizations.go
@@ -700,6 +860,13 @@ export interface PatchWorkspaceProxy {
   readonly regenerate_token: boolean
 }
 
+// From codersdk/oauth2.go
+export interface PostOAuth2ProviderAppRequest {
+  readonly name: string
+  readonly callback_url: string
+  readonly icon: string
+}
+
 // From codersdk/deployment.go
 export interface PprofConfig {
   readonly enable: boolean
@@ -736,6 +903,18 @@ export interface ProvisionerDaemon {
   readonly name: string
   readonly provisioners: ProvisionerType[]
   readonly tags: Record<string, string>
+  readonly last_seen_at?: string
+  readonly healthy: boolean
+  readonly version: string
+  readonly provisioner_versions: Record<string, string>
+  readonly current_job?: ProvisionerDaemonJob
+}
+
+// From codersdk/provisionerdaemons.go
+export interface ProvisionerDaemonJob {
+  readonly id: string
+  readonly status: ProvisionerJobStatus
+  readonly started_at: string
 }
 
 // From codersdk/provisionerdaemons.go
@@ -753,6 +932,7 @@ export interface ProvisionerJob {
   readonly tags: Record<string, string>
   readonly queue_position: number
   readonly queue_size: number
+  readonly estimated_start_at?: string
 }
 
 // From codersdk/provisionerdaemons.go
@@ -776,6 +956,13 @@ export interface PutExtendWorkspaceRequest {
   readonly deadline: string
 }
 
+// From codersdk/oauth2.go
+export interface PutOAuth2ProviderAppRequest {
+  readonly name: string
+  readonly callback_url: string
+  readonly icon: string
+}
+
 // From codersdk/deployment.go
 export interface RateLimitConfig {
   readonly disable_all: boolean
@@ -809,6 +996,11 @@ export interface Replica {
   readonly database_latency: number
 }
 
+// From codersdk/mfa.go
+export interface ResetUserMFARequest {
+  readonly code?: string
+}
+
 // From codersdk/client.go
 export interface Response {
   readonly message: string
@@ -888,6 +1080,49 @@ export interface TLSConfig {
   readonly client_key_file: string
 }
 
+// From codersdk/debug.go
+export interface TailnetConnection {
+  readonly path: TailnetPath
+  readonly address?: string
+  readonly relay?: string
+  readonly last_handshake_at?: string
+  readonly rx_bytes: number
+  readonly tx_bytes: number
+}
+
+// From codersdk/debug.go
+export interface TailnetPeer {
+  readonly id: string
+  readonly name: string
+  readonly type: TailnetPeerType
+  readonly coordinator_id?: string
+  readonly connected: boolean
+  readonly preferred_derp: number
+  readonly endpoints: string[]
+  readonly last_node_update_at?: string
+  readonly connection?: TailnetConnection
+}
+
+// From codersdk/debug.go
+export interface TailnetStatus {
+  readonly ha: boolean
+  readonly replica_id: string
+  readonly peers: TailnetPeer[]
+  readonly tunnels: TailnetTunnel[]
+}
+
+// From codersdk/debug.go
+export interface TailnetTunnel {
+  readonly src: string
+  readonly dst: string
+}
+
+// From codersdk/mfa.go
+export interface TOTPEnrollment {
+  readonly secret: string
+  readonly url: string
+}
+
 // From codersdk/deployment.go
 export interface TelemetryConfig {
   readonly enable: boolean
@@ -920,6 +1155,7 @@ export interface Template {
   readonly failure_ttl_ms: number
   readonly time_til_dormant_ms: number
   readonly time_til_dormant_autodelete_ms: number
+  readonly egress_policy: EgressPolicy
 }
 
 // From codersdk/templates.go
@@ -1040,6 +1276,8 @@ export interface TemplateVersion {
 export interface TemplateVersionGitAuth {
   readonly id: string
   readonly type: GitProvider
+  readonly display_name: string
+  readonly display_icon: string
   readonly authenticate_url: string
   readonly authenticated: boolean
 }
@@ -1062,6 +1300,8 @@ export interface TemplateVersionParameter {
   readonly validation_monotonic?: ValidationMonotonicOrder
   readonly required: boolean
   readonly ephemeral: boolean
+  readonly visible_if?: string
+  readonly validation_rules?: TemplateVersionParameterValidationRule[]
 }
 
 // From codersdk/templateversions.go
@@ -1072,6 +1312,12 @@ export interface TemplateVersionParameterOption {
   readonly icon: string
 }
 
+// From codersdk/templateversions.go
+export interface TemplateVersionParameterValidationRule {
+  readonly condition: string
+  readonly error: string
+}
+
 // From codersdk/templateversions.go
 export interface TemplateVersionVariable {
   readonly name: string
@@ -1158,6 +1404,7 @@ export interface UpdateTemplateMeta {
   readonly time_til_dormant_autodelete_ms?: number
   readonly update_workspace_last_used_at: boolean
   readonly update_workspace_dormant_at: boolean
+  readonly egress_policy?: EgressPolicy
 }
 
 // From codersdk/users.go
@@ -1202,6 +1449,11 @@ export interface UpdateWorkspaceTTLRequest {
   readonly ttl_ms?: number
 }
 
+// From codersdk/gitsshkey.go
+export interface UploadGitSSHKeyRequest {
+  readonly external_auth_provider_id: string
+}
+
 // From codersdk/files.go
 export interface UploadResponse {
   readonly hash: string
@@ -1219,6 +1471,7 @@ export interface User {
   readonly roles: Role[]
   readonly avatar_url: string
   readonly login_type: LoginType
+  readonly service_account_owner_group_id?: string
 }
 
 // From codersdk/insights.go
@@ -1255,6 +1508,14 @@ export interface UserLoginType {
   readonly login_type: LoginType
 }
 
+// From codersdk/mfa.go
+export interface UserMFA {
+  readonly enabled: boolean
+  readonly enabled_at?: string
+  readonly required: boolean
+  readonly recovery_codes_remaining: number
+}
+
 // From codersdk/deployment.go
 export interface UserQuietHoursScheduleConfig {
   readonly default_schedule: string
@@ -1292,6 +1553,39 @@ export interface VariableValue {
   readonly value: string
 }
 
+// From codersdk/mfa.go
+export interface VerifyTOTPRequest {
+  readonly code: string
+}
+
+// From codersdk/workloadidentity.go
+export interface WorkloadIdentityExchangeRequest {
+  readonly provider_id: string
+  readonly token: string
+}
+
+// From codersdk/workloadidentity.go
+export interface WorkloadIdentityExchangeResponse {
+  readonly session_token: string
+  readonly expires_at: string
+  readonly username: string
+}
+
+// From codersdk/workloadidentity.go
+export interface WorkloadIdentityProvider {
+  readonly id: string
+  readonly issuer: string
+  readonly audience: string
+  readonly rules: WorkloadIdentityRule[]
+}
+
+// From codersdk/workloadidentity.go
+export interface WorkloadIdentityRule {
+  readonly claims: Record<string, string>
+  readonly username: string
+  readonly scopes: APIKeyScope[]
+}
+
 // From codersdk/workspaces.go
 export interface Workspace {
   readonly id: string
@@ -1552,8 +1846,23 @@ export interface WorkspacesResponse {
 }
 
 // From codersdk/apikey.go
-export type APIKeyScope = "all" | "application_connect"
-export const APIKeyScopes: APIKeyScope[] = ["all", "application_connect"]
+export type APIKeyScope =
+  | "all"
+  | "application_connect"
+  | "template:push"
+  | "template:read"
+  | "user:read"
+  | "workspace:read"
+  | "workspace:start"
+export const APIKeyScopes: APIKeyScope[] = [
+  "all",
+  "application_connect",
+  "template:push",
+  "template:read",
+  "user:read",
+  "workspace:read",
+  "workspace:start",
+]
 
 // From codersdk/workspaceagents.go
 export type AgentSubsystem = "envbox" | "envbuilder" | "exectrace"
@@ -1607,6 +1916,10 @@ export const DisplayApps: DisplayApp[] = [
   "web_terminal",
 ]
 
+// From codersdk/templates.go
+export type EgressAction = "allow" | "deny"
+export const EgressActions: EgressAction[] = ["allow", "deny"]
+
 // From codersdk/deployment.go
 export type Entitlement = "entitled" | "grace_period" | "not_entitled"
 export const Entitlements: Entitlement[] = [
@@ -1695,16 +2008,41 @@ export type LogSource = "provisioner" | "provisioner_daemon"
 export const LogSources: LogSource[] = ["provisioner", "provisioner_daemon"]
 
 // From codersdk/apikey.go
-export type LoginType = "" | "github" | "none" | "oidc" | "password" | "token"
+export type LoginType =
+  | ""
+  | "github"
+  | "ldap"
+  | "none"
+  | "oauth2_provider_app"
+  | "oidc"
+  | "password"
+  | "service_account"
+  | "token"
 export const LoginTypes: LoginType[] = [
   "",
   "github",
+  "ldap",
   "none",
+  "oauth2_provider_app",
   "oidc",
   "password",
+  "service_account",
   "token",
 ]
 
+// From codersdk/oauth2.go
+export type OAuth2ProviderGrantType = "authorization_code" | "refresh_token"
+export const OAuth2ProviderGrantTypes: OAuth2ProviderGrantType[] = [
+  "authorization_code",
+  "refresh_token",
+]
+
+// From codersdk/oauth2.go
+export type OAuth2ProviderResponseType = "code"
+export const OAuth2ProviderResponseTypes: OAuth2ProviderResponseType[] = [
+  "code",
+]
+
 // From codersdk/provisionerdaemons.go
 export type ProvisionerJobStatus =
   | "canceled"
@@ -1803,10 +2141,13 @@ export type ResourceType =
   | "git_ssh_key"
   | "group"
   | "license"
+  | "oauth2_provider_app"
+  | "oauth2_provider_app_secret"
   | "organization"
   | "template"
   | "template_version"
   | "user"
+  | "user_mfa"
   | "workspace"
   | "workspace_build"
   | "workspace_proxy"
@@ -1816,10 +2157,13 @@ export const ResourceTypes: ResourceType[] = [
   "git_ssh_key",
   "group",
   "license",
+  "oauth2_provider_app",
+  "oauth2_provider_app_secret",
   "organization",
   "template",
   "template_version",
   "user",
+  "user_mfa",
   "workspace",
   "workspace_build",
   "workspace_proxy",
@@ -1833,6 +2177,14 @@ export const ServerSentEventTypes: ServerSentEventType[] = [
   "ping",
 ]
 
+// From codersdk/debug.go
+export type TailnetPath = "derp" | "direct"
+export const TailnetPaths: TailnetPath[] = ["derp", "direct"]
+
+// From codersdk/debug.go
+export type TailnetPeerType = "agent" | "client"
+export const TailnetPeerTypes: TailnetPeerType[] = ["agent", "client"]
+
 // From codersdk/insights.go
 export type TemplateAppsType = "app" | "builtin"
 export const TemplateAppsTypes: TemplateAppsType[] = ["app", "builtin"]
@@ -1883,6 +2235,7 @@ export const WorkspaceAgentLifecycles: WorkspaceAgentLifecycle[] = [
 
 // From codersdk/workspaceagents.go
 export type WorkspaceAgentLogSource =
+  | "egress_policy"
   | "envbox"
   | "envbuilder"
   | "external"
@@ -1890,6 +2243,7 @@ export type WorkspaceAgentLogSource =
   | "shutdown_script"
   | "startup_script"
 export const WorkspaceAgentLogSources: WorkspaceAgentLogSource[] = [
+  "egress_policy",
   "envbox",
   "envbuilder",
   "external",
@@ -2051,9 +2405,49 @@ export interface HealthcheckReport {
   readonly access_url: HealthcheckAccessURLReport
   readonly websocket: HealthcheckWebsocketReport
   readonly database: HealthcheckDatabaseReport
+  readonly provisioner_daemons: HealthcheckProvisionerDaemonsReport
+  readonly replicas: HealthcheckReplicasReport
   readonly coder_version: string
 }
 
+// From healthcheck/provisionerdaemons.go
+export interface HealthcheckProvisionerDaemonsReport {
+  readonly healthy: boolean
+  readonly daemons: number
+  readonly healthy_daemons: number
+  readonly versions: Record<string, number>
+  readonly unserved_templates: HealthcheckProvisionerDaemonsUnservedTemplate[]
+  readonly error?: string
+}
+
+// From healthcheck/provisionerdaemons.go
+export interface HealthcheckProvisionerDaemonsUnservedTemplate {
+  readonly template_id: string
+  readonly template_name: string
+  readonly provisioner: string
+  readonly tags: Record<string, string>
+}
+
+// From healthcheck/replicas.go
+export interface HealthcheckReplicaReport {
+  readonly id: string
+  readonly hostname: string
+  readonly version: string
+  readonly region_id: number
+  readonly relay_address: string
+  readonly gateway: boolean
+  readonly database_latency_ms: number
+  readonly healthy: boolean
+  readonly error?: string
+}
+
+// From healthcheck/replicas.go
+export interface HealthcheckReplicasReport {
+  readonly healthy: boolean
+  readonly replicas: HealthcheckReplicaReport[]
+  readonly error?: string
+}
+
 // From healthcheck/websocket.go
 export interface HealthcheckWebsocketReport {
   readonly healthy: boolean
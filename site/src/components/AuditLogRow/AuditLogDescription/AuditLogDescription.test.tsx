@@ -4,6 +4,7 @@ import {
   MockWorkspaceCreateAuditLogForDifferentOwner,
   MockAuditLogSuccessfulLogin,
   MockAuditLogUnsuccessfulLoginKnownUser,
+  MockUser,
 } from "testHelpers/entities"
 import { AuditLogDescription } from "./AuditLogDescription"
 import { AuditLogRow } from "../AuditLogRow"
@@ -32,6 +33,21 @@ describe("AuditLogDescription", () => {
     expect(screen.getByText("bruno-dev")).toBeDefined()
   })
 
+  it("renders the correct string for an audit log by a service account", async () => {
+    const AuditLogByServiceAccount = {
+      ...MockAuditLog,
+      user: {
+        ...MockUser,
+        login_type: "service_account" as const,
+      },
+    }
+    render(<AuditLogDescription auditLog={AuditLogByServiceAccount} />)
+
+    expect(
+      screen.getByText("TestUser (service account) created workspace"),
+    ).toBeDefined()
+  })
+
   it("renders the correct string for a workspace_build stop audit log", async () => {
     render(<AuditLogDescription auditLog={MockAuditLogWithWorkspaceBuild} />)
 
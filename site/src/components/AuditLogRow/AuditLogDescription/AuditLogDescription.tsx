@@ -22,8 +22,14 @@ export const AuditLogDescription: FC<{ auditLog: AuditLog }> = ({
     target = ""
   }
 
+  // make it clear when an action was taken by a service account rather than a person
+  const actor =
+    auditLog.user?.login_type === "service_account"
+      ? `${user} (service account)`
+      : user
+
   const truncatedDescription = auditLog.description
-    .replace("{user}", `${user}`)
+    .replace("{user}", `${actor}`)
     .replace("{target}", "")
 
   // logs for workspaces created on behalf of other users indicate ownership in the description
@@ -1,4 +1,5 @@
 import { Story } from "@storybook/react"
+import { GitProvider } from "api/typesGenerated"
 import { GitAuth, GitAuthProps } from "./GitAuth"
 
 export default {
@@ -55,3 +56,18 @@ BitbucketAuthenticated.args = {
   type: "bitbucket",
   authenticated: true,
 }
+
+export const GenericNotAuthenticated = Template.bind({})
+GenericNotAuthenticated.args = {
+  type: "jfrog" as GitProvider,
+  displayName: "JFrog",
+  displayIcon: "/icon/jfrog.svg",
+  authenticated: false,
+}
+
+export const GenericAuthenticated = Template.bind({})
+GenericAuthenticated.args = {
+  type: "jfrog" as GitProvider,
+  displayName: "JFrog",
+  authenticated: true,
+}
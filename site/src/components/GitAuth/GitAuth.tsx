@@ -3,6 +3,7 @@ import FormHelperText from "@mui/material/FormHelperText"
 import { SvgIconProps } from "@mui/material/SvgIcon"
 import Tooltip from "@mui/material/Tooltip"
 import GitHub from "@mui/icons-material/GitHub"
+import VpnKeyOutlined from "@mui/icons-material/VpnKeyOutlined"
 import * as TypesGen from "api/typesGenerated"
 import { AzureDevOpsIcon } from "components/Icons/AzureDevOpsIcon"
 import { BitbucketIcon } from "components/Icons/BitbucketIcon"
@@ -12,6 +13,8 @@ import { makeStyles } from "@mui/styles"
 
 export interface GitAuthProps {
   type: TypesGen.GitProvider
+  displayName?: string
+  displayIcon?: string
   authenticated: boolean
   authenticateURL: string
   error?: string
@@ -19,6 +22,8 @@ export interface GitAuthProps {
 
 export const GitAuth: FC<GitAuthProps> = ({
   type,
+  displayName,
+  displayIcon,
   authenticated,
   authenticateURL,
   error,
@@ -47,7 +52,13 @@ export const GitAuth: FC<GitAuthProps> = ({
       Icon = GitlabIcon
       break
     default:
-      throw new Error("invalid git provider: " + type)
+      // Generic OAuth2 providers, which are configured with a display
+      // name and icon instead.
+      prettyName = type
+      Icon = VpnKeyOutlined as (props: SvgIconProps) => JSX.Element
+  }
+  if (displayName) {
+    prettyName = displayName
   }
 
   return (
@@ -61,7 +72,13 @@ export const GitAuth: FC<GitAuthProps> = ({
           href={authenticateURL}
           variant="contained"
           size="large"
-          startIcon={<Icon />}
+          startIcon={
+            displayIcon ? (
+              <img src={displayIcon} alt="" className={styles.icon} />
+            ) : (
+              <Icon />
+            )
+          }
           disabled={authenticated}
           className={styles.button}
           color={error ? "error" : undefined}
@@ -90,4 +107,8 @@ const useStyles = makeStyles(() => ({
   button: {
     height: 52,
   },
+  icon: {
+    width: 20,
+    height: 20,
+  },
 }))
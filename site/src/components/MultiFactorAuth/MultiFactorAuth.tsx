@@ -0,0 +1,58 @@
+import { makeStyles } from "@mui/styles"
+import { FC } from "react"
+import { TOTPEnrollment } from "api/typesGenerated"
+import { CodeBlock } from "components/CodeBlock/CodeBlock"
+import { CodeExample } from "components/CodeExample/CodeExample"
+import { CopyButton } from "components/CopyButton/CopyButton"
+import { Stack } from "components/Stack/Stack"
+
+export const Language = {
+  secretDescription:
+    "Add your account to an authenticator app with this secret:",
+  urlDescription:
+    "Or with this URL, for example by converting it to a QR code:",
+  recoveryCodesDescription:
+    "Save these recovery codes somewhere safe. Each one can be used once instead of a code from your authenticator app, and they will not be shown again.",
+  codeLabel: "Authenticator code",
+  codeHelperText: "A code from your authenticator app, or a recovery code.",
+}
+
+export const TOTPEnrollmentDetails: FC<{ enrollment: TOTPEnrollment }> = ({
+  enrollment,
+}) => {
+  const styles = useStyles()
+
+  return (
+    <Stack spacing={1} className={styles.root}>
+      <p className={styles.description}>{Language.secretDescription}</p>
+      <CodeExample code={enrollment.secret} />
+      <p className={styles.description}>{Language.urlDescription}</p>
+      <CodeExample code={enrollment.url} />
+    </Stack>
+  )
+}
+
+export const MFARecoveryCodes: FC<{ codes: string[] }> = ({ codes }) => {
+  const styles = useStyles()
+
+  return (
+    <Stack spacing={1} className={styles.root}>
+      <p className={styles.description}>{Language.recoveryCodesDescription}</p>
+      <CodeBlock
+        lines={codes}
+        ctas={[<CopyButton key="copy" text={codes.join("\n")} />]}
+      />
+    </Stack>
+  )
+}
+
+const useStyles = makeStyles((theme) => ({
+  root: {
+    textAlign: "left",
+  },
+  description: {
+    margin: 0,
+    fontSize: 14,
+    color: theme.palette.text.secondary,
+  },
+}))
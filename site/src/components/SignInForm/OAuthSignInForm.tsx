@@ -75,6 +75,35 @@ export const OAuthSignInForm: FC<OAuthSignInFormProps> = ({
           </Button>
         </Link>
       )}
+
+      {authMethods?.oidc_providers.map((provider) => (
+        <Link
+          key={provider.id}
+          href={`/api/v2/users/oidc/${provider.id}/callback?redirect=${encodeURIComponent(
+            redirectTo,
+          )}`}
+        >
+          <Button
+            size="large"
+            startIcon={
+              provider.iconUrl ? (
+                <img
+                  alt="Open ID Connect icon"
+                  src={provider.iconUrl}
+                  className={styles.buttonIcon}
+                />
+              ) : (
+                <KeyIcon className={styles.buttonIcon} />
+              )
+            }
+            disabled={isSigningIn}
+            fullWidth
+            type="submit"
+          >
+            {provider.signInText || Language.oidcSignIn}
+          </Button>
+        </Link>
+      ))}
     </Box>
   )
 }
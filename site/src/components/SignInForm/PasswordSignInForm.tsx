@@ -5,26 +5,48 @@ import { LoadingButton } from "../LoadingButton/LoadingButton"
 import { Language } from "./SignInForm"
 import { FormikContextType, FormikTouched, useFormik } from "formik"
 import * as Yup from "yup"
-import { FC } from "react"
-import { BuiltInAuthFormValues } from "./SignInForm.types"
+import { FC, useEffect, useState } from "react"
+import { useQuery } from "@tanstack/react-query"
+import { enrollTOTPWithPassword } from "api/api"
+import { ErrorAlert } from "components/Alert/ErrorAlert"
+import {
+  Language as MFALanguage,
+  TOTPEnrollmentDetails,
+} from "components/MultiFactorAuth/MultiFactorAuth"
+import { BuiltInAuthFormValues, getMFAStep, MFAStep } from "./SignInForm.types"
 
 type PasswordSignInFormProps = {
-  onSubmit: (credentials: { email: string; password: string }) => void
+  onSubmit: (credentials: BuiltInAuthFormValues) => void
   initialTouched?: FormikTouched<BuiltInAuthFormValues>
   isSigningIn: boolean
+  error?: unknown
 }
 
 export const PasswordSignInForm: FC<PasswordSignInFormProps> = ({
   onSubmit,
   initialTouched,
   isSigningIn,
+  error,
 }) => {
+  // Once the password was accepted, the step sticks until the page is left,
+  // so that an invalid code does not hide the enrollment.
+  const [mfaStep, setMFAStep] = useState<MFAStep>()
+  useEffect(() => {
+    const step = getMFAStep(error)
+    if (step === "mfa_enrollment" || (step && !mfaStep)) {
+      setMFAStep(step)
+    }
+  }, [error, mfaStep])
+
   const validationSchema = Yup.object({
     email: Yup.string()
       .trim()
       .email(Language.emailInvalid)
       .required(Language.emailRequired),
     password: Yup.string(),
+    mfa_code: mfaStep
+      ? Yup.string().trim().required(Language.mfaCodeRequired)
+      : Yup.string(),
   })
 
   const form: FormikContextType<BuiltInAuthFormValues> =
@@ -32,12 +54,24 @@ export const PasswordSignInForm: FC<PasswordSignInFormProps> = ({
       initialValues: {
         email: "",
         password: "",
+        mfa_code: "",
       },
       validationSchema,
       onSubmit,
       initialTouched,
     })
-  const getFieldHelpers = getFormHelpers<BuiltInAuthFormValues>(form)
+  const getFieldHelpers = getFormHelpers<BuiltInAuthFormValues>(form, error)
+
+  // Enrolling again replaces the secret, so it is only fetched once.
+  const enrollment = useQuery({
+    queryKey: ["loginMFAEnrollment", form.values.email],
+    queryFn: () =>
+      enrollTOTPWithPassword(form.values.email, form.values.password),
+    enabled: mfaStep === "mfa_enrollment",
+    staleTime: Infinity,
+    refetchOnWindowFocus: false,
+    retry: false,
+  })
 
   return (
     <form onSubmit={form.handleSubmit}>
@@ -45,11 +79,12 @@ export const PasswordSignInForm: FC<PasswordSignInFormProps> = ({
         <TextField
           {...getFieldHelpers("email")}
           onChange={onChangeTrimmed(form)}
-          autoFocus
+          autoFocus={!mfaStep}
           autoComplete="email"
           fullWidth
           label={Language.emailLabel}
           type="email"
+          disabled={Boolean(mfaStep)}
         />
         <TextField
           {...getFieldHelpers("password")}
@@ -58,7 +93,24 @@ export const PasswordSignInForm: FC<PasswordSignInFormProps> = ({
           id="password"
           label={Language.passwordLabel}
           type="password"
+          disabled={Boolean(mfaStep)}
         />
+        {mfaStep === "mfa_enrollment" && enrollment.data && (
+          <TOTPEnrollmentDetails enrollment={enrollment.data} />
+        )}
+        {mfaStep === "mfa_enrollment" && enrollment.error !== null && (
+          <ErrorAlert error={enrollment.error} />
+        )}
+        {mfaStep && (
+          <TextField
+            {...getFieldHelpers("mfa_code", MFALanguage.codeHelperText)}
+            onChange={onChangeTrimmed(form)}
+            autoFocus
+            autoComplete="one-time-code"
+            fullWidth
+            label={MFALanguage.codeLabel}
+          />
+        )}
         <div>
           <LoadingButton
             size="large"
@@ -31,6 +31,8 @@ SigningIn.args = {
     password: { enabled: true },
     github: { enabled: true },
     oidc: { enabled: false, signInText: "", iconUrl: "" },
+    oidc_providers: [],
+    ldap: { enabled: false, signInText: "" },
   },
 }
 
@@ -58,6 +60,8 @@ WithGithub.args = {
     password: { enabled: true },
     github: { enabled: true },
     oidc: { enabled: false, signInText: "", iconUrl: "" },
+    oidc_providers: [],
+    ldap: { enabled: false, signInText: "" },
   },
 }
 
@@ -68,6 +72,8 @@ WithOIDC.args = {
     password: { enabled: true },
     github: { enabled: false },
     oidc: { enabled: true, signInText: "", iconUrl: "" },
+    oidc_providers: [],
+    ldap: { enabled: false, signInText: "" },
   },
 }
 
@@ -78,6 +84,8 @@ WithOIDCWithoutPassword.args = {
     password: { enabled: false },
     github: { enabled: false },
     oidc: { enabled: true, signInText: "", iconUrl: "" },
+    oidc_providers: [],
+    ldap: { enabled: false, signInText: "" },
   },
 }
 
@@ -88,6 +96,8 @@ WithoutAny.args = {
     password: { enabled: false },
     github: { enabled: false },
     oidc: { enabled: false, signInText: "", iconUrl: "" },
+    oidc_providers: [],
+    ldap: { enabled: false, signInText: "" },
   },
 }
 
@@ -98,5 +108,34 @@ WithGithubAndOIDC.args = {
     password: { enabled: true },
     github: { enabled: true },
     oidc: { enabled: true, signInText: "", iconUrl: "" },
+    oidc_providers: [],
+    ldap: { enabled: false, signInText: "" },
+  },
+}
+
+export const WithOIDCProviders = Template.bind({})
+WithOIDCProviders.args = {
+  ...SignedOut.args,
+  authMethods: {
+    password: { enabled: true },
+    github: { enabled: false },
+    oidc: { enabled: true, signInText: "", iconUrl: "" },
+    oidc_providers: [
+      { id: "okta", signInText: "Sign in with Okta", iconUrl: "" },
+      { id: "azure", signInText: "Sign in with Azure AD", iconUrl: "" },
+    ],
+    ldap: { enabled: false, signInText: "" },
+  },
+}
+
+export const WithLDAP = Template.bind({})
+WithLDAP.args = {
+  ...SignedOut.args,
+  authMethods: {
+    password: { enabled: true },
+    github: { enabled: false },
+    oidc: { enabled: false, signInText: "", iconUrl: "" },
+    oidc_providers: [],
+    ldap: { enabled: true, signInText: "Active Directory" },
   },
 }
@@ -6,7 +6,8 @@ import { useTranslation } from "react-i18next"
 import { Maybe } from "../Conditionals/Maybe"
 import { PasswordSignInForm } from "./PasswordSignInForm"
 import { OAuthSignInForm } from "./OAuthSignInForm"
-import { BuiltInAuthFormValues } from "./SignInForm.types"
+import { LDAPSignInForm } from "./LDAPSignInForm"
+import { BuiltInAuthFormValues, SignInCredentials } from "./SignInForm.types"
 import Button from "@mui/material/Button"
 import EmailIcon from "@mui/icons-material/EmailOutlined"
 import { Alert } from "components/Alert/Alert"
@@ -18,6 +19,10 @@ export const Language = {
   emailInvalid: "Please enter a valid email address.",
   emailRequired: "Please enter an email address.",
   passwordSignIn: "Sign In",
+  usernameLabel: "Username",
+  usernameRequired: "Please enter a username.",
+  ldapSignIn: "Sign In with LDAP",
+  mfaCodeRequired: "Please enter a code from your authenticator app.",
   githubSignIn: "GitHub",
   oidcSignIn: "OpenID Connect",
 }
@@ -71,7 +76,7 @@ export interface SignInFormProps {
   error?: unknown
   info?: string
   authMethods?: AuthMethods
-  onSubmit: (credentials: { email: string; password: string }) => void
+  onSubmit: (credentials: SignInCredentials) => void
   // initialTouched is only used for testing the error state of the form.
   initialTouched?: FormikTouched<BuiltInAuthFormValues>
 }
@@ -86,11 +91,16 @@ export const SignInForm: FC<React.PropsWithChildren<SignInFormProps>> = ({
   initialTouched,
 }) => {
   const oAuthEnabled = Boolean(
-    authMethods?.github.enabled || authMethods?.oidc.enabled,
+    authMethods?.github.enabled ||
+      authMethods?.oidc.enabled ||
+      authMethods?.oidc_providers.length,
   )
+  const ldapEnabled = authMethods?.ldap.enabled ?? false
   const passwordEnabled = authMethods?.password.enabled ?? true
-  // Hide password auth by default if any OAuth method is enabled
-  const [showPasswordAuth, setShowPasswordAuth] = useState(!oAuthEnabled)
+  // Hide password auth by default if any OAuth method or LDAP is enabled
+  const [showPasswordAuth, setShowPasswordAuth] = useState(
+    !oAuthEnabled && !ldapEnabled,
+  )
   const styles = useStyles()
   const commonTranslation = useTranslation("common")
   const loginPageTranslation = useTranslation("loginPage")
@@ -111,11 +121,30 @@ export const SignInForm: FC<React.PropsWithChildren<SignInFormProps>> = ({
           <Alert severity="info">{info}</Alert>
         </div>
       </Maybe>
+      <Maybe condition={ldapEnabled}>
+        <LDAPSignInForm
+          onSubmit={onSubmit}
+          isSigningIn={isSigningIn}
+          signInText={authMethods?.ldap.signInText}
+        />
+      </Maybe>
+      <Maybe
+        condition={
+          ldapEnabled && (oAuthEnabled || (passwordEnabled && showPasswordAuth))
+        }
+      >
+        <div className={styles.divider}>
+          <div className={styles.dividerLine} />
+          <div className={styles.dividerLabel}>Or</div>
+          <div className={styles.dividerLine} />
+        </div>
+      </Maybe>
       <Maybe condition={passwordEnabled && showPasswordAuth}>
         <PasswordSignInForm
           onSubmit={onSubmit}
           initialTouched={initialTouched}
           isSigningIn={isSigningIn}
+          error={error}
         />
       </Maybe>
       <Maybe condition={passwordEnabled && showPasswordAuth && oAuthEnabled}>
@@ -133,7 +162,7 @@ export const SignInForm: FC<React.PropsWithChildren<SignInFormProps>> = ({
         />
       </Maybe>
 
-      <Maybe condition={!passwordEnabled && !oAuthEnabled}>
+      <Maybe condition={!passwordEnabled && !oAuthEnabled && !ldapEnabled}>
         <Alert severity="error">No authentication methods configured!</Alert>
       </Maybe>
 
@@ -1,3 +1,5 @@
+import { isApiValidationError, mapApiErrorToFieldErrors } from "api/errors"
+
 /**
  * BuiltInAuthFormValues describes a form using built-in (email/password)
  * authentication. This form may not always be present depending on external
@@ -6,4 +8,46 @@
 export interface BuiltInAuthFormValues {
   email: string
   password: string
+  // mfa_code is asked for after the password is accepted, when the user
+  // enrolled an authenticator or the deployment requires one.
+  mfa_code?: string
+}
+
+/**
+ * LDAPAuthFormValues describes a form using the username and password of an
+ * account in the LDAP directory. It is only present when LDAP is enabled.
+ */
+export interface LDAPAuthFormValues {
+  username: string
+  password: string
+}
+
+/**
+ * SignInCredentials are submitted by either sign in form.
+ */
+export type SignInCredentials = BuiltInAuthFormValues | LDAPAuthFormValues
+
+export const isLDAPCredentials = (
+  credentials: SignInCredentials,
+): credentials is LDAPAuthFormValues => "username" in credentials
+
+/**
+ * MFAStep is the field of the validation error a password sign in fails with
+ * when it needs a code from an authenticator app ("mfa_code"), or when the
+ * deployment requires the user to enroll one first ("mfa_enrollment").
+ */
+export type MFAStep = "mfa_code" | "mfa_enrollment"
+
+export const getMFAStep = (error: unknown): MFAStep | undefined => {
+  if (!isApiValidationError(error)) {
+    return undefined
+  }
+  const fields = mapApiErrorToFieldErrors(error.response.data)
+  if (fields.mfa_enrollment) {
+    return "mfa_enrollment"
+  }
+  if (fields.mfa_code) {
+    return "mfa_code"
+  }
+  return undefined
 }
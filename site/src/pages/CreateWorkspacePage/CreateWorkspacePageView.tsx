@@ -149,6 +149,8 @@ export const CreateWorkspacePageView: FC<CreateWorkspacePageViewProps> = ({
                   authenticateURL={auth.authenticate_url}
                   authenticated={auth.authenticated}
                   type={auth.type}
+                  displayName={auth.display_name}
+                  displayIcon={auth.display_icon}
                   error={gitAuthErrors[auth.id]}
                 />
               ))}
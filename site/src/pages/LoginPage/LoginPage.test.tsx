@@ -3,6 +3,7 @@ import userEvent from "@testing-library/user-event"
 import { rest } from "msw"
 import { createMemoryRouter } from "react-router-dom"
 import { Language } from "../../components/SignInForm/SignInForm"
+import { Language as MFALanguage } from "components/MultiFactorAuth/MultiFactorAuth"
 import {
   render,
   renderWithRouter,
@@ -64,6 +65,8 @@ describe("LoginPage", () => {
       password: { enabled: true },
       github: { enabled: true },
       oidc: { enabled: true, signInText: "", iconUrl: "" },
+      oidc_providers: [],
+      ldap: { enabled: false, signInText: "" },
     }
 
     // Given
@@ -81,6 +84,89 @@ describe("LoginPage", () => {
     await screen.findByText(Language.githubSignIn)
   })
 
+  it("signs in with LDAP when enabled", async () => {
+    const authMethods: TypesGen.AuthMethods = {
+      password: { enabled: true },
+      github: { enabled: false },
+      oidc: { enabled: false, signInText: "", iconUrl: "" },
+      oidc_providers: [],
+      ldap: { enabled: true, signInText: "Active Directory" },
+    }
+    let request: TypesGen.LoginWithLDAPRequest | undefined
+
+    // Given
+    server.use(
+      rest.get("/api/v2/users/authmethods", async (req, res, ctx) => {
+        return res(ctx.status(200), ctx.json(authMethods))
+      }),
+      rest.post("/api/v2/users/ldap/login", async (req, res, ctx) => {
+        request = await req.json()
+        return res(ctx.status(401), ctx.json({ message: "Wrong password" }))
+      }),
+    )
+
+    // When
+    render(<LoginPage />)
+    await waitForLoaderToBeRemoved()
+
+    // Then
+    // Password authentication is hidden in favor of LDAP.
+    expect(screen.queryByText(Language.passwordSignIn)).not.toBeInTheDocument()
+    const username = screen.getByLabelText(Language.usernameLabel)
+    const password = screen.getByLabelText(Language.passwordLabel)
+    await userEvent.type(username, "alice")
+    await userEvent.type(password, "password")
+    fireEvent.click(await screen.findByText("Active Directory"))
+
+    await screen.findByText("Wrong password")
+    expect(request).toEqual({ username: "alice", password: "password" })
+  })
+
+  it("asks for an MFA code after the password", async () => {
+    const requests: TypesGen.LoginWithPasswordRequest[] = []
+
+    // Given
+    server.use(
+      rest.post("/api/v2/users/login", async (req, res, ctx) => {
+        const request: TypesGen.LoginWithPasswordRequest = await req.json()
+        requests.push(request)
+        if (!request.mfa_code) {
+          return res(
+            ctx.status(401),
+            ctx.json({
+              message: "Enter a code from your authenticator app.",
+              validations: [
+                { field: "mfa_code", detail: "A code is required." },
+              ],
+            }),
+          )
+        }
+        return res(ctx.status(401), ctx.json({ message: "Invalid code" }))
+      }),
+    )
+
+    // When
+    render(<LoginPage />)
+    await waitForLoaderToBeRemoved()
+    await userEvent.type(
+      screen.getByLabelText(Language.emailLabel),
+      "test@coder.com",
+    )
+    await userEvent.type(screen.getByLabelText(Language.passwordLabel), "pass")
+    fireEvent.click(await screen.findByText(Language.passwordSignIn))
+
+    // Then
+    const code = await screen.findByLabelText(MFALanguage.codeLabel)
+    await userEvent.type(code, "123456")
+    fireEvent.click(await screen.findByText(Language.passwordSignIn))
+    await screen.findByText("Invalid code")
+    expect(requests[1]).toEqual({
+      email: "test@coder.com",
+      password: "pass",
+      mfa_code: "123456",
+    })
+  })
+
   it("redirects to the setup page if there is no first user", async () => {
     // Given
     server.use(
@@ -115,6 +201,8 @@ describe("LoginPage", () => {
       password: { enabled: true },
       github: { enabled: true },
       oidc: { enabled: true, signInText: "", iconUrl: "" },
+      oidc_providers: [],
+      ldap: { enabled: false, signInText: "" },
     }
 
     // Given
@@ -1,10 +1,11 @@
 import { useAuth } from "components/AuthProvider/AuthProvider"
-import { FC } from "react"
+import { FC, useState } from "react"
 import { Helmet } from "react-helmet-async"
 import { useTranslation } from "react-i18next"
 import { Navigate, useLocation } from "react-router-dom"
-import { retrieveRedirect } from "../../utils/redirect"
+import { isServerRedirect, retrieveRedirect } from "../../utils/redirect"
 import { LoginPageView } from "./LoginPageView"
+import { AuthenticatedData } from "xServices/auth/authXService"
 
 export const LoginPage: FC = () => {
   const location = useLocation()
@@ -12,8 +13,27 @@ export const LoginPage: FC = () => {
   const redirectTo = retrieveRedirect(location.search)
   const commonTranslation = useTranslation("common")
   const loginPageTranslation = useTranslation("loginPage")
+  const [savedRecoveryCodes, setSavedRecoveryCodes] = useState(false)
 
   if (authState.matches("signedIn")) {
+    const { mfaRecoveryCodes } = authState.context.data as AuthenticatedData
+    if (mfaRecoveryCodes && !savedRecoveryCodes) {
+      return (
+        <LoginPageView
+          context={authState.context}
+          isLoading={false}
+          isSigningIn={false}
+          onSignIn={() => undefined}
+          mfaRecoveryCodes={mfaRecoveryCodes}
+          onContinue={() => setSavedRecoveryCodes(true)}
+        />
+      )
+    }
+    if (isServerRedirect(redirectTo)) {
+      // The router cannot render pages served by coderd.
+      window.location.href = redirectTo
+      return null
+    }
     return <Navigate to={redirectTo} replace />
   } else if (authState.matches("configuringTheFirstUser")) {
     return <Navigate to="/setup" />
@@ -29,8 +49,8 @@ export const LoginPage: FC = () => {
           context={authState.context}
           isLoading={authState.matches("loadingInitialAuthData")}
           isSigningIn={authState.matches("signingIn")}
-          onSignIn={({ email, password }) => {
-            authSend({ type: "SIGN_IN", email, password })
+          onSignIn={(credentials) => {
+            authSend({ type: "SIGN_IN", ...credentials })
           }}
         />
       </>
@@ -1,6 +1,6 @@
 import { action } from "@storybook/addon-actions"
 import { ComponentMeta, Story } from "@storybook/react"
-import { MockAuthMethods } from "testHelpers/entities"
+import { MockAuthMethods, mockApiError } from "testHelpers/entities"
 import { LoginPageView, LoginPageViewProps } from "./LoginPageView"
 
 export default {
@@ -57,3 +57,28 @@ SigningIn.args = {
     },
   },
 }
+
+export const MFACodeRequired = Template.bind({})
+MFACodeRequired.args = {
+  isLoading: false,
+  onSignIn: action("onSignIn"),
+  context: {
+    error: mockApiError({
+      message: "Enter a code from your authenticator app, or a recovery code.",
+      validations: [{ field: "mfa_code", detail: "A code is required." }],
+    }),
+    data: {
+      authMethods: MockAuthMethods,
+      hasFirstUser: false,
+    },
+  },
+}
+
+export const MFARecoveryCodes = Template.bind({})
+MFARecoveryCodes.args = {
+  isLoading: false,
+  onSignIn: action("onSignIn"),
+  onContinue: action("onContinue"),
+  mfaRecoveryCodes: ["6G3QKQ2RVY", "T9W4C7MXAJ", "E2PZ8HNU5B"],
+  context: {},
+}
@@ -4,14 +4,21 @@ import { FC } from "react"
 import { useLocation } from "react-router-dom"
 import { AuthContext, UnauthenticatedData } from "xServices/auth/authXService"
 import { SignInForm } from "components/SignInForm/SignInForm"
+import { SignInCredentials } from "components/SignInForm/SignInForm.types"
 import { retrieveRedirect } from "utils/redirect"
 import { CoderIcon } from "components/Icons/CoderIcon"
+import { MFARecoveryCodes } from "components/MultiFactorAuth/MultiFactorAuth"
+import Button from "@mui/material/Button"
 
 export interface LoginPageViewProps {
   context: AuthContext
   isLoading: boolean
   isSigningIn: boolean
-  onSignIn: (credentials: { email: string; password: string }) => void
+  onSignIn: (credentials: SignInCredentials) => void
+  // mfaRecoveryCodes are shown instead of the form after a sign in that
+  // enrolled an authenticator, until the user continues.
+  mfaRecoveryCodes?: string[]
+  onContinue?: () => void
 }
 
 export const LoginPageView: FC<LoginPageViewProps> = ({
@@ -19,6 +26,8 @@ export const LoginPageView: FC<LoginPageViewProps> = ({
   isLoading,
   isSigningIn,
   onSignIn,
+  mfaRecoveryCodes,
+  onContinue,
 }) => {
   const location = useLocation()
   const redirectTo = retrieveRedirect(location.search)
@@ -35,14 +44,23 @@ export const LoginPageView: FC<LoginPageViewProps> = ({
     <div className={styles.root}>
       <div className={styles.container}>
         <CoderIcon fill="white" opacity={1} className={styles.icon} />
-        <SignInForm
-          authMethods={data.authMethods}
-          redirectTo={redirectTo}
-          isSigningIn={isSigningIn}
-          error={error}
-          info={info}
-          onSubmit={onSignIn}
-        />
+        {mfaRecoveryCodes ? (
+          <>
+            <MFARecoveryCodes codes={mfaRecoveryCodes} />
+            <Button fullWidth size="large" onClick={onContinue}>
+              Continue
+            </Button>
+          </>
+        ) : (
+          <SignInForm
+            authMethods={data.authMethods}
+            redirectTo={redirectTo}
+            isSigningIn={isSigningIn}
+            error={error}
+            info={info}
+            onSubmit={onSignIn}
+          />
+        )}
         <footer className={styles.footer}>
           Copyright © {new Date().getFullYear()} Coder Technologies, Inc.
         </footer>
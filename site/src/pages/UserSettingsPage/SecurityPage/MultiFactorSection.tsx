@@ -0,0 +1,191 @@
+import { useState } from "react"
+import { Section } from "../../../components/SettingsLayout/Section"
+import TextField from "@mui/material/TextField"
+import Box from "@mui/material/Box"
+import Button from "@mui/material/Button"
+import Skeleton from "@mui/material/Skeleton"
+import CheckCircleOutlined from "@mui/icons-material/CheckCircleOutlined"
+import { useMutation, useQuery, useQueryClient } from "@tanstack/react-query"
+import {
+  enrollUserTOTP,
+  getUserMFA,
+  resetUserMFA,
+  verifyUserTOTP,
+} from "api/api"
+import { TOTPEnrollment, UserMFA } from "api/typesGenerated"
+import { ErrorAlert } from "components/Alert/ErrorAlert"
+import { LoadingButton } from "components/LoadingButton/LoadingButton"
+import {
+  Language as MFALanguage,
+  MFARecoveryCodes,
+  TOTPEnrollmentDetails,
+} from "components/MultiFactorAuth/MultiFactorAuth"
+import { Stack } from "components/Stack/Stack"
+
+const mfaKey = ["me", "mfa"]
+
+export const useMultiFactorSection = () => {
+  const queryClient = useQueryClient()
+  const { data: status } = useQuery({
+    queryKey: mfaKey,
+    queryFn: () => getUserMFA(),
+  })
+  // Recovery codes are only returned when the authenticator is enabled.
+  const [recoveryCodes, setRecoveryCodes] = useState<string[]>()
+
+  const enrollMutation = useMutation(() => enrollUserTOTP())
+  const verifyMutation = useMutation((code: string) => verifyUserTOTP(code), {
+    onSuccess: async (data) => {
+      setRecoveryCodes(data.recovery_codes)
+      enrollMutation.reset()
+      await queryClient.invalidateQueries(mfaKey)
+    },
+  })
+  const resetMutation = useMutation((code: string) => resetUserMFA({ code }), {
+    onSuccess: async () => {
+      setRecoveryCodes(undefined)
+      await queryClient.invalidateQueries(mfaKey)
+    },
+  })
+
+  return {
+    status,
+    enrollment: enrollMutation.data,
+    recoveryCodes,
+    enroll: () => enrollMutation.mutate(),
+    verify: (code: string) => verifyMutation.mutate(code),
+    reset: (code: string) => resetMutation.mutate(code),
+    isUpdating:
+      enrollMutation.isLoading ||
+      verifyMutation.isLoading ||
+      resetMutation.isLoading,
+    error: enrollMutation.error ?? verifyMutation.error ?? resetMutation.error,
+  }
+}
+
+type MultiFactorSectionProps = {
+  status?: UserMFA
+  enrollment?: TOTPEnrollment
+  recoveryCodes?: string[]
+  enroll: () => void
+  verify: (code: string) => void
+  reset: (code: string) => void
+  isUpdating: boolean
+  error: unknown
+}
+
+export const MultiFactorSection = ({
+  status,
+  enrollment,
+  recoveryCodes,
+  enroll,
+  verify,
+  reset,
+  isUpdating,
+  error,
+}: MultiFactorSectionProps) => {
+  const [code, setCode] = useState("")
+
+  const codeField = (
+    <TextField
+      value={code}
+      onChange={(event) => setCode(event.target.value.trim())}
+      autoComplete="one-time-code"
+      fullWidth
+      id="mfa_code"
+      label={MFALanguage.codeLabel}
+      helperText={MFALanguage.codeHelperText}
+    />
+  )
+
+  return (
+    <Section
+      id="mfa-section"
+      title="Multi-Factor Authentication"
+      description="Ask for a code from an authenticator app when you sign in with your password"
+    >
+      <Stack spacing={2}>
+        {Boolean(error) && <ErrorAlert error={error} />}
+        {recoveryCodes && <MFARecoveryCodes codes={recoveryCodes} />}
+        {!status ? (
+          <Skeleton
+            variant="rectangular"
+            sx={{ height: 40, borderRadius: 1 }}
+          />
+        ) : status.enabled ? (
+          <>
+            <Box
+              sx={{
+                background: (theme) => theme.palette.background.paper,
+                borderRadius: 1,
+                border: (theme) => `1px solid ${theme.palette.divider}`,
+                padding: 2,
+                display: "flex",
+                gap: 2,
+                alignItems: "center",
+                fontSize: 14,
+              }}
+            >
+              <CheckCircleOutlined
+                sx={{
+                  color: (theme) => theme.palette.success.light,
+                  fontSize: 16,
+                }}
+              />
+              <span>
+                Authenticator app enabled,{" "}
+                <strong>{status.recovery_codes_remaining}</strong> recovery
+                codes remaining
+              </span>
+            </Box>
+            {status.required ? (
+              <Box sx={{ fontSize: 14, color: "text.secondary" }}>
+                Your deployment requires multi-factor authentication. Ask an
+                administrator to reset it if you lose your authenticator.
+              </Box>
+            ) : (
+              <>
+                {codeField}
+                <div>
+                  <LoadingButton
+                    loading={isUpdating}
+                    disabled={code === ""}
+                    onClick={() => {
+                      reset(code)
+                      setCode("")
+                    }}
+                  >
+                    Remove authenticator
+                  </LoadingButton>
+                </div>
+              </>
+            )}
+          </>
+        ) : enrollment ? (
+          <>
+            <TOTPEnrollmentDetails enrollment={enrollment} />
+            {codeField}
+            <div>
+              <LoadingButton
+                loading={isUpdating}
+                disabled={code === ""}
+                onClick={() => {
+                  verify(code)
+                  setCode("")
+                }}
+              >
+                Enable authenticator
+              </LoadingButton>
+            </div>
+          </>
+        ) : (
+          <div>
+            <Button disabled={isUpdating} onClick={enroll}>
+              Set up authenticator app
+            </Button>
+          </div>
+        )}
+      </Stack>
+    </Section>
+  )
+}
@@ -158,3 +158,29 @@ test("change login type to OIDC", async () => {
     })
   })
 })
+
+test("enable multi-factor authentication", async () => {
+  const user = userEvent.setup()
+  jest.spyOn(API, "enrollUserTOTP").mockResolvedValue({
+    secret: "JBSWY3DPEHPK3PXP",
+    url: "otpauth://totp/Coder:admin?issuer=Coder&secret=JBSWY3DPEHPK3PXP",
+  })
+  const verifyUserTOTPSpy = jest
+    .spyOn(API, "verifyUserTOTP")
+    .mockResolvedValue({ recovery_codes: ["6G3QKQ2RVY", "T9W4C7MXAJ"] })
+  await renderPage()
+
+  const mfaSection = await screen.findByTestId("mfa-section")
+  await user.click(
+    await within(mfaSection).findByText("Set up authenticator app"),
+  )
+  await within(mfaSection).findByText("JBSWY3DPEHPK3PXP")
+  await user.type(
+    within(mfaSection).getByLabelText("Authenticator code"),
+    "123456",
+  )
+  await user.click(within(mfaSection).getByText("Enable authenticator"))
+
+  await within(mfaSection).findByText("6G3QKQ2RVY")
+  expect(verifyUserTOTPSpy).toHaveBeenCalledWith("123456")
+})
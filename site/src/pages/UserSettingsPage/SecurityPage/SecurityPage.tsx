@@ -10,6 +10,10 @@ import {
   SingleSignOnSection,
   useSingleSignOnSection,
 } from "./SingleSignOnSection"
+import {
+  MultiFactorSection,
+  useMultiFactorSection,
+} from "./MultiFactorSection"
 import { Loader } from "components/Loader/Loader"
 import { Stack } from "components/Stack/Stack"
 
@@ -33,6 +37,7 @@ export const SecurityPage: FC = () => {
     queryFn: getUserLoginType,
   })
   const singleSignOnSection = useSingleSignOnSection()
+  const multiFactorSection = useMultiFactorSection()
 
   if (!authMethods || !userLoginType) {
     return <Loader />
@@ -60,6 +65,11 @@ export const SecurityPage: FC = () => {
           ...singleSignOnSection,
         },
       }}
+      mfa={
+        userLoginType.login_type === "password"
+          ? { section: multiFactorSection }
+          : undefined
+      }
     />
   )
 }
@@ -67,6 +77,7 @@ export const SecurityPage: FC = () => {
 export const SecurityPageView = ({
   security,
   oidc,
+  mfa,
 }: {
   security: {
     form: ComponentProps<typeof SecurityForm>
@@ -74,12 +85,16 @@ export const SecurityPageView = ({
   oidc?: {
     section: ComponentProps<typeof SingleSignOnSection>
   }
+  mfa?: {
+    section: ComponentProps<typeof MultiFactorSection>
+  }
 }) => {
   return (
     <Stack spacing={6}>
       <Section title="Security" description="Update your account password">
         <SecurityForm {...security.form} />
       </Section>
+      {mfa && <MultiFactorSection {...mfa.section} />}
       {oidc && <SingleSignOnSection {...oidc.section} />}
     </Stack>
   )
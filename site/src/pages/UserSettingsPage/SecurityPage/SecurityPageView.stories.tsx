@@ -4,6 +4,7 @@ import { action } from "@storybook/addon-actions"
 import {
   MockAuthMethods,
   MockAuthMethodsWithPasswordType,
+  MockUserMFA,
 } from "testHelpers/entities"
 import { ComponentProps } from "react"
 import set from "lodash/fp/set"
@@ -31,6 +32,16 @@ const defaultArgs: ComponentProps<typeof SecurityPageView> = {
       openConfirmation: action("openConfirmation"),
     },
   },
+  mfa: {
+    section: {
+      status: MockUserMFA,
+      enroll: action("enroll"),
+      verify: action("verify"),
+      reset: action("reset"),
+      isUpdating: false,
+      error: undefined,
+    },
+  },
 }
 
 const meta: Meta<typeof SecurityPageView> = {
@@ -70,3 +81,30 @@ export const ConfirmingOIDCConversion: Story = {
     defaultArgs,
   ),
 }
+
+export const EnrollingMFA: Story = {
+  args: set(
+    "mfa.section.enrollment",
+    {
+      secret: "JBSWY3DPEHPK3PXP",
+      url: "otpauth://totp/Coder:admin?issuer=Coder&secret=JBSWY3DPEHPK3PXP",
+    },
+    defaultArgs,
+  ),
+}
+
+export const MFAEnabled: Story = {
+  args: set(
+    "mfa.section",
+    {
+      ...defaultArgs.mfa?.section,
+      status: {
+        ...MockUserMFA,
+        enabled: true,
+        enabled_at: "2023-09-01T00:00:00Z",
+      },
+      recoveryCodes: ["6G3QKQ2RVY", "T9W4C7MXAJ", "E2PZ8HNU5B"],
+    },
+    defaultArgs,
+  ),
+}
@@ -449,6 +449,7 @@ export const MockTemplate: TypesGen.Template = {
   time_til_dormant_autodelete_ms: 0,
   allow_user_autostart: false,
   allow_user_autostop: false,
+  egress_policy: {},
 }
 
 export const MockTemplateVersionFiles: TemplateVersionFiles = {
@@ -1125,6 +1126,8 @@ export const MockAuthMethods: TypesGen.AuthMethods = {
   password: { enabled: true },
   github: { enabled: false },
   oidc: { enabled: false, signInText: "", iconUrl: "" },
+  oidc_providers: [],
+  ldap: { enabled: false, signInText: "" },
 }
 
 export const MockAuthMethodsWithPasswordType: TypesGen.AuthMethods = {
@@ -1139,6 +1142,16 @@ export const MockGitSSHKey: TypesGen.GitSSHKey = {
   updated_at: "2022-05-16T15:29:10.302441433Z",
   public_key:
     "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIFJOQRIM7kE30rOzrfy+/+R+nQGCk7S9pioihy+2ARbq",
+  name: "default",
+  key_type: "ssh-ed25519",
+  imported: false,
+  hosts: [],
+}
+
+export const MockUserMFA: TypesGen.UserMFA = {
+  enabled: false,
+  required: false,
+  recovery_codes_remaining: 0,
 }
 
 export const MockWorkspaceBuildLogs: TypesGen.ProvisionerJobLog[] = [
@@ -2091,6 +2104,8 @@ export const MockWorkspaceBuildParameter5: TypesGen.WorkspaceBuildParameter = {
 export const MockTemplateVersionGitAuth: TypesGen.TemplateVersionGitAuth = {
   id: "github",
   type: "github",
+  display_name: "GitHub",
+  display_icon: "",
   authenticate_url: "https://example.com/gitauth/github",
   authenticated: false,
 }
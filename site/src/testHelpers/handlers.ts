@@ -195,6 +195,9 @@ export const handlers = [
   rest.get("/api/v2/users/:userId/gitsshkey", async (req, res, ctx) => {
     return res(ctx.status(200), ctx.json(M.MockGitSSHKey))
   }),
+  rest.get("/api/v2/users/:userId/mfa", async (req, res, ctx) => {
+    return res(ctx.status(200), ctx.json(M.MockUserMFA))
+  }),
   rest.get(
     "/api/v2/users/:userId/workspace/:workspaceName",
     async (req, res, ctx) => {
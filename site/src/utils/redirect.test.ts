@@ -1,4 +1,4 @@
-import { embedRedirect, retrieveRedirect } from "./redirect"
+import { embedRedirect, isServerRedirect, retrieveRedirect } from "./redirect"
 
 describe("redirect helper functions", () => {
   describe("embedRedirect", () => {
@@ -17,4 +17,12 @@ describe("redirect helper functions", () => {
       expect(result).toEqual("/workspaces")
     })
   })
+  describe("isServerRedirect", () => {
+    it("is true for pages served by coderd", () => {
+      expect(isServerRedirect("/oauth2/authorize?client_id=foo")).toBe(true)
+    })
+    it("is false for dashboard pages", () => {
+      expect(isServerRedirect("/workspaces")).toBe(false)
+    })
+  })
 })
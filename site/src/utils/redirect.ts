@@ -21,3 +21,13 @@ export const retrieveRedirect = (search: string): string => {
   const redirect = searchParams.get("redirect")
   return redirect ? redirect : defaultRedirect
 }
+
+/**
+ * Whether a redirect points at a page served by coderd rather than the
+ * dashboard, like the OAuth2 authorization page. These cannot be navigated to
+ * with the router and need a full page load.
+ * @param redirect the URL to redirect to
+ * @returns true if the redirect must be loaded from the server
+ */
+export const isServerRedirect = (redirect: string): boolean =>
+  redirect.startsWith("/oauth2/")
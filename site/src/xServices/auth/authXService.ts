@@ -2,6 +2,10 @@ import { assign, createMachine } from "xstate"
 import * as API from "../../api/api"
 import * as TypesGen from "../../api/typesGenerated"
 import { displaySuccess } from "../../components/GlobalSnackbar/utils"
+import {
+  isLDAPCredentials,
+  SignInCredentials,
+} from "../../components/SignInForm/SignInForm.types"
 
 export const Language = {
   successProfileUpdate: "Updated settings.",
@@ -102,6 +106,9 @@ export type Permissions = Record<keyof typeof permissionsToCheck, boolean>
 export type AuthenticatedData = {
   user: TypesGen.User
   permissions: Permissions
+  // mfaRecoveryCodes are only set by a sign in that enabled MFA, and are not
+  // shown again after it.
+  mfaRecoveryCodes?: string[]
 }
 export type UnauthenticatedData = {
   hasFirstUser: boolean
@@ -152,10 +159,15 @@ const loadInitialAuthData = async (): Promise<AuthData> => {
 }
 
 const signIn = async (
-  email: string,
-  password: string,
+  credentials: SignInCredentials,
 ): Promise<AuthenticatedData> => {
-  await API.login(email, password)
+  const response = isLDAPCredentials(credentials)
+    ? await API.loginWithLDAP(credentials.username, credentials.password)
+    : await API.login(
+        credentials.email,
+        credentials.password,
+        credentials.mfa_code,
+      )
   const [user, permissions] = await Promise.all([
     API.getAuthenticatedUser(),
     API.checkAuthorization({
@@ -166,6 +178,7 @@ const signIn = async (
   return {
     user: user as TypesGen.User,
     permissions: permissions as Permissions,
+    mfaRecoveryCodes: response.mfa_recovery_codes,
   }
 }
 
@@ -188,7 +201,7 @@ export interface AuthContext {
 
 export type AuthEvent =
   | { type: "SIGN_OUT" }
-  | { type: "SIGN_IN"; email: string; password: string }
+  | ({ type: "SIGN_IN" } & SignInCredentials)
   | { type: "UPDATE_PROFILE"; data: TypesGen.UpdateUserProfileRequest }
 
 export const authMachine =
@@ -355,7 +368,7 @@ export const authMachine =
     {
       services: {
         loadInitialAuthData,
-        signIn: (_, { email, password }) => signIn(email, password),
+        signIn: (_, credentials) => signIn(credentials),
         signOut,
         updateProfile: async ({ data }, event) => {
           if (!data) {
@@ -408,6 +408,7 @@ type Conn struct {
 	wireguardRouter  *router.Config
 	wireguardEngine  wgengine.Engine
 	listeners        map[listenKey]*listener
+	forwardTCPFilter func(port uint16) bool
 
 	lastMutex   sync.Mutex
 	nodeSending bool
@@ -481,6 +482,15 @@ func (c *Conn) SetBlockEndpoints(blockEndpoints bool) {
 	c.blockEndpoints = blockEndpoints
 }
 
+// SetForwardTCPFilter sets a filter for TCP connections to ports that aren't
+// listened on in the tailnet, which are otherwise forwarded to the same port
+// on localhost. Connections to ports that filter returns false for are closed.
+func (c *Conn) SetForwardTCPFilter(filter func(port uint16) bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.forwardTCPFilter = filter
+}
+
 // SetDERPRegionDialer updates the dialer to use for connecting to DERP regions.
 func (c *Conn) SetDERPRegionDialer(dialer func(ctx context.Context, region *tailcfg.DERPRegion) net.Conn) {
 	c.magicConn.SetDERPRegionDialer(dialer)
@@ -944,8 +954,14 @@ func (c *Conn) DialContextUDP(ctx context.Context, ipp netip.AddrPort) (*gonet.U
 func (c *Conn) forwardTCP(_, dst netip.AddrPort) (handler func(net.Conn), opts []tcpip.SettableSocketOption, intercept bool) {
 	c.mutex.Lock()
 	ln, ok := c.listeners[listenKey{"tcp", "", fmt.Sprint(dst.Port())}]
+	filter := c.forwardTCPFilter
 	c.mutex.Unlock()
 	if !ok {
+		if filter != nil && !filter(dst.Port()) {
+			return func(conn net.Conn) {
+				_ = conn.Close()
+			}, nil, true
+		}
 		return nil, nil, false
 	}
 	// See: https://github.com/tailscale/tailscale/blob/c7cea825aea39a00aca71ea02bab7266afc03e7c/wgengine/netstack/netstack.go#L888
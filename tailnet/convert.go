@@ -0,0 +1,103 @@
+package tailnet
+
+import (
+	"net/netip"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"tailscale.com/tailcfg"
+	"tailscale.com/types/key"
+
+	"github.com/coder/coder/v2/tailnet/proto"
+)
+
+// NodeToProto converts a node to its representation in the v2 coordinator
+// protocol.
+func NodeToProto(n *Node) (*proto.Node, error) {
+	k, err := n.Key.MarshalBinary()
+	if err != nil {
+		return nil, xerrors.Errorf("marshal key: %w", err)
+	}
+	disco, err := n.DiscoKey.MarshalText()
+	if err != nil {
+		return nil, xerrors.Errorf("marshal disco key: %w", err)
+	}
+	derpForcedWebsocket := make(map[int32]string, len(n.DERPForcedWebsocket))
+	for region, reason := range n.DERPForcedWebsocket {
+		derpForcedWebsocket[int32(region)] = reason
+	}
+	addresses := make([]string, len(n.Addresses))
+	for i, prefix := range n.Addresses {
+		addresses[i] = prefix.String()
+	}
+	allowedIPs := make([]string, len(n.AllowedIPs))
+	for i, prefix := range n.AllowedIPs {
+		allowedIPs[i] = prefix.String()
+	}
+	return &proto.Node{
+		Id:                  int64(n.ID),
+		AsOf:                timestamppb.New(n.AsOf),
+		Key:                 k,
+		Disco:               string(disco),
+		PreferredDerp:       int32(n.PreferredDERP),
+		DerpLatency:         n.DERPLatency,
+		DerpForcedWebsocket: derpForcedWebsocket,
+		Endpoints:           n.Endpoints,
+		Addresses:           addresses,
+		AllowedIps:          allowedIPs,
+	}, nil
+}
+
+// ProtoToNode converts a node from the v2 coordinator protocol.
+func ProtoToNode(p *proto.Node) (*Node, error) {
+	var k key.NodePublic
+	err := k.UnmarshalBinary(p.GetKey())
+	if err != nil {
+		return nil, xerrors.Errorf("unmarshal key: %w", err)
+	}
+	var disco key.DiscoPublic
+	err = disco.UnmarshalText([]byte(p.GetDisco()))
+	if err != nil {
+		return nil, xerrors.Errorf("unmarshal disco key: %w", err)
+	}
+	derpForcedWebsocket := make(map[int]string, len(p.GetDerpForcedWebsocket()))
+	for region, reason := range p.GetDerpForcedWebsocket() {
+		derpForcedWebsocket[int(region)] = reason
+	}
+	addresses := make([]netip.Prefix, len(p.GetAddresses()))
+	for i, address := range p.GetAddresses() {
+		addresses[i], err = netip.ParsePrefix(address)
+		if err != nil {
+			return nil, xerrors.Errorf("parse address %q: %w", address, err)
+		}
+	}
+	allowedIPs := make([]netip.Prefix, len(p.GetAllowedIps()))
+	for i, allowedIP := range p.GetAllowedIps() {
+		allowedIPs[i], err = netip.ParsePrefix(allowedIP)
+		if err != nil {
+			return nil, xerrors.Errorf("parse allowed ip %q: %w", allowedIP, err)
+		}
+	}
+	return &Node{
+		ID:                  tailcfg.NodeID(p.GetId()),
+		AsOf:                p.GetAsOf().AsTime(),
+		Key:                 k,
+		DiscoKey:            disco,
+		PreferredDERP:       int(p.GetPreferredDerp()),
+		DERPLatency:         p.GetDerpLatency(),
+		DERPForcedWebsocket: derpForcedWebsocket,
+		Endpoints:           p.GetEndpoints(),
+		Addresses:           addresses,
+		AllowedIPs:          allowedIPs,
+	}, nil
+}
+
+// ParseUUID parses a UUID sent as bytes in the v2 coordinator protocol.
+func ParseUUID(b []byte) (uuid.UUID, error) {
+	id, err := uuid.FromBytes(b)
+	if err != nil {
+		return uuid.Nil, xerrors.Errorf("parse uuid: %w", err)
+	}
+	return id, nil
+}
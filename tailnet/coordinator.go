@@ -22,6 +22,7 @@ import (
 
 	"cdr.dev/slog"
 	"github.com/coder/coder/v2/coderd/util/slice"
+	"github.com/coder/coder/v2/tailnet/proto"
 )
 
 // Coordinator exchanges nodes with agents to establish connections.
@@ -33,6 +34,10 @@ type Coordinator interface {
 	// ServeHTTPDebug serves a debug webpage that shows the internal state of
 	// the coordinator.
 	ServeHTTPDebug(w http.ResponseWriter, r *http.Request)
+	// Status returns the peers of the coordinator and the tunnels between
+	// them. Coordinators that are shared by replicas include the peers of
+	// every replica.
+	Status(ctx context.Context) (*CoordinatorStatus, error)
 	// Node returns an in-memory node by ID.
 	Node(id uuid.UUID) *Node
 	// ServeClient accepts a WebSocket connection that wants to connect to an agent
@@ -46,6 +51,13 @@ type Coordinator interface {
 	Close() error
 
 	ServeMultiAgent(id uuid.UUID) MultiAgentConn
+
+	// Coordinate serves a peer using the v2 protocol. The peer is an agent if
+	// a is AgentTunnelAuth, and a client otherwise; a client may only open
+	// tunnels that a authorizes. The caller sends requests on the returned
+	// request channel and closes it when the peer goes away. The response
+	// channel is closed by the coordinator when it's done with the peer.
+	Coordinate(ctx context.Context, id uuid.UUID, name string, a TunnelAuth) (chan<- *proto.CoordinateRequest, <-chan *proto.CoordinateResponse)
 }
 
 // Node represents a node in the network.
@@ -152,6 +164,96 @@ func (c *coordinator) ServeMultiAgent(id uuid.UUID) MultiAgentConn {
 	return m
 }
 
+func (c *coordinator) Coordinate(ctx context.Context, id uuid.UUID, name string, a TunnelAuth) (chan<- *proto.CoordinateRequest, <-chan *proto.CoordinateResponse) {
+	reqs := make(chan *proto.CoordinateRequest, RequestBufferSize)
+	resps := make(chan *proto.CoordinateResponse, ResponseBufferSize)
+	if _, ok := a.(AgentTunnelAuth); ok {
+		go c.core.coordinateAgent(ctx, id, name, reqs, resps)
+	} else {
+		go c.core.coordinateClient(ctx, id, name, a, reqs, resps)
+	}
+	return reqs, resps
+}
+
+func (c *core) coordinateAgent(pCtx context.Context, id uuid.UUID, name string, reqs <-chan *proto.CoordinateRequest, resps chan<- *proto.CoordinateResponse) {
+	ctx, cancel := context.WithCancel(pCtx)
+	logger := c.agentLogger(id)
+	logger.Debug(ctx, "coordinating v2 agent")
+	// This uniquely identifies a connection that belongs to this goroutine.
+	unique := uuid.New()
+	var pc *PeerConn
+	_, err := c.trackAgent(ctx, id, name, func(overwrites int64) Queue {
+		pc = NewPeerConn(ctx, cancel, unique, name, logger, resps, overwrites)
+		return pc
+	})
+	if err != nil {
+		cancel()
+		close(resps)
+		return
+	}
+	go pc.SendUpdates()
+
+	kind, reason := HandleCoordinateRequests(ctx, logger, reqs, pc, AgentTunnelAuth{}, agentHandler{core: c, id: id})
+	c.agentDisconnected(id, unique, kind, reason)
+	_ = pc.Close()
+}
+
+func (c *core) coordinateClient(pCtx context.Context, id uuid.UUID, name string, a TunnelAuth, reqs <-chan *proto.CoordinateRequest, resps chan<- *proto.CoordinateResponse) {
+	ctx, cancel := context.WithCancel(pCtx)
+	logger := c.clientLogger(id, uuid.Nil)
+	logger.Debug(ctx, "coordinating v2 client")
+	pc := NewPeerConn(ctx, cancel, id, name, logger, resps, 0)
+	c.addClient(id, pc)
+	go pc.SendUpdates()
+
+	kind, reason := HandleCoordinateRequests(ctx, logger, reqs, pc, a, clientHandler{core: c, pc: pc})
+	c.removeClient(id, kind, reason)
+	_ = pc.Close()
+}
+
+// agentHandler applies the requests of a v2 agent.
+type agentHandler struct {
+	core *core
+	id   uuid.UUID
+}
+
+func (h agentHandler) UpdateSelf(node *Node) error {
+	return h.core.agentNodeUpdate(h.id, node)
+}
+
+func (agentHandler) AddTunnel(uuid.UUID) error {
+	return xerrors.New("agents cannot open tunnels")
+}
+
+func (agentHandler) RemoveTunnel(uuid.UUID) error {
+	return xerrors.New("agents cannot open tunnels")
+}
+
+// clientHandler applies the requests of a v2 client.
+type clientHandler struct {
+	core *core
+	pc   *PeerConn
+}
+
+func (h clientHandler) UpdateSelf(node *Node) error {
+	return h.core.clientNodeUpdate(h.pc.UniqueID(), node)
+}
+
+func (h clientHandler) AddTunnel(dst uuid.UUID) error {
+	agentNode, err := h.core.clientSubscribeToAgent(h.pc, dst)
+	if err != nil {
+		return err
+	}
+	if agentNode != nil {
+		return h.pc.EnqueuePeer(dst, agentNode)
+	}
+	return nil
+}
+
+func (h clientHandler) RemoveTunnel(dst uuid.UUID) error {
+	return h.core.clientUnsubscribeFromAgent(h.pc, dst)
+}
+
 func (c *core) addClient(id uuid.UUID, ma Queue) {
 	c.mutex.Lock()
 	c.clients[id] = ma
@@ -315,6 +417,12 @@ func (c *core) initOrSetAgentConnectionSocketLocked(agentID uuid.UUID, enq Queue
 }
 
 func (c *core) clientDisconnected(id uuid.UUID) {
+	c.removeClient(id, proto.CoordinateResponse_PeerUpdate_DISCONNECTED, "client disconnected")
+}
+
+// removeClient removes the client from the coordinator, and tells the agents
+// it had tunnels to how it went away.
+func (c *core) removeClient(id uuid.UUID, kind proto.CoordinateResponse_PeerUpdate_Kind, reason string) {
 	logger := c.clientLogger(id, uuid.Nil)
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
@@ -322,7 +430,14 @@ func (c *core) clientDisconnected(id uuid.UUID) {
 	delete(c.nodes, id)
 	logger.Debug(context.Background(), "deleted client node")
 
-	for agentID := range c.clientsToAgents[id] {
+	for agentID, agentSocket := range c.clientsToAgents[id] {
+		if agentSocket != nil {
+			err := RemovePeer(agentSocket, id, kind, reason)
+			if err != nil {
+				logger.Debug(context.Background(), "unable to remove client from agent", slog.F("agent_id", agentID), slog.Error(err))
+			}
+		}
+
 		connectionSockets, ok := c.agentToConnectionSockets[agentID]
 		if !ok {
 			continue
@@ -376,7 +491,7 @@ func (c *core) clientNodeUpdateLocked(id uuid.UUID, node *Node) error {
 			continue
 		}
 
-		err := agentSocket.Enqueue([]*Node{node})
+		err := EnqueuePeer(agentSocket, id, node)
 		if err != nil {
 			logger.Debug(context.Background(), "unable to Enqueue node to agent", slog.Error(err), slog.F("agent_id", agentID))
 			continue
@@ -404,7 +519,7 @@ func (c *core) clientSubscribeToAgent(enq Queue, agentID uuid.UUID) (*Node, erro
 		if !ok {
 			logger.Debug(context.Background(), "subscribe to agent; socket is nil")
 		} else {
-			err := agentSocket.Enqueue([]*Node{node})
+			err := EnqueuePeer(agentSocket, enq.UniqueID(), node)
 			if err != nil {
 				return nil, xerrors.Errorf("enqueue client to agent: %w", err)
 			}
@@ -430,6 +545,12 @@ func (c *core) clientUnsubscribeFromAgent(enq Queue, agentID uuid.UUID) error {
 	delete(c.clientsToAgents[enq.UniqueID()], agentID)
 	delete(c.agentToConnectionSockets[agentID], enq.UniqueID())
 
+	if agentSocket, ok := c.agentSockets[agentID]; ok {
+		err := RemovePeer(agentSocket, enq.UniqueID(), proto.CoordinateResponse_PeerUpdate_DISCONNECTED, "tunnel removed")
+		if err != nil {
+			c.clientLogger(enq.UniqueID(), agentID).Debug(context.Background(), "unable to remove client from agent", slog.Error(err))
+		}
+	}
 	return nil
 }
 
@@ -446,16 +567,18 @@ func (c *coordinator) ServeAgent(conn net.Conn, id uuid.UUID, name string) error
 	logger.Debug(context.Background(), "coordinating agent")
 	// This uniquely identifies a connection that belongs to this goroutine.
 	unique := uuid.New()
-	tc, err := c.core.initAndTrackAgent(ctx, cancel, conn, id, unique, name)
+	tc, err := c.core.trackAgent(ctx, id, name, func(overwrites int64) Queue {
+		return NewTrackedConn(ctx, cancel, conn, unique, logger, name, overwrites)
+	})
 	if err != nil {
 		return err
 	}
 
 	// On this goroutine, we read updates from the agent and publish them.  We start a second goroutine
 	// to write updates back to the agent.
-	go tc.SendUpdates()
+	go tc.(*TrackedConn).SendUpdates()
 
-	defer c.core.agentDisconnected(id, unique)
+	defer c.core.agentDisconnected(id, unique, proto.CoordinateResponse_PeerUpdate_LOST, "agent disconnected")
 
 	decoder := json.NewDecoder(conn)
 	for {
@@ -470,7 +593,9 @@ func (c *coordinator) ServeAgent(conn net.Conn, id uuid.UUID, name string) error
 	}
 }
 
-func (c *core) agentDisconnected(id, unique uuid.UUID) {
+// agentDisconnected removes the agent connection with the given unique ID,
+// and tells the agent's clients how it went away.
+func (c *core) agentDisconnected(id, unique uuid.UUID, kind proto.CoordinateResponse_PeerUpdate_Kind, reason string) {
 	logger := c.agentLogger(id)
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
@@ -481,16 +606,22 @@ func (c *core) agentDisconnected(id, unique uuid.UUID) {
 		delete(c.agentSockets, id)
 		delete(c.nodes, id)
 		logger.Debug(context.Background(), "deleted agent socket and node")
+		for clientID, connectionSocket := range c.agentToConnectionSockets[id] {
+			err := RemovePeer(connectionSocket, id, kind, reason)
+			if err != nil {
+				logger.Debug(context.Background(), "unable to remove agent from client", slog.F("client_id", clientID), slog.Error(err))
+			}
+		}
 	}
 	for clientID := range c.agentToConnectionSockets[id] {
 		c.clientsToAgents[clientID][id] = nil
 	}
 }
 
-// initAndTrackAgent creates a TrackedConn for the agent, and sends any initial nodes updates if we have any.  It is
+// trackAgent creates a Queue for the agent with newQueue, and sends any initial nodes updates if we have any.  It is
 // one function that does two things because it is critical that we hold the mutex for both things, lest we miss some
 // updates.
-func (c *core) initAndTrackAgent(ctx context.Context, cancel func(), conn net.Conn, id, unique uuid.UUID, name string) (*TrackedConn, error) {
+func (c *core) trackAgent(ctx context.Context, id uuid.UUID, name string, newQueue func(overwrites int64) Queue) (Queue, error) {
 	logger := c.logger.With(slog.F("agent_id", id))
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
@@ -509,24 +640,24 @@ func (c *core) initAndTrackAgent(ctx context.Context, cancel func(), conn net.Co
 		overwrites = oldAgentSocket.Overwrites() + 1
 		_ = oldAgentSocket.Close()
 	}
-	tc := NewTrackedConn(ctx, cancel, conn, unique, logger, name, overwrites)
+	tc := newQueue(overwrites)
 	c.agentNameCache.Add(id, name)
 
 	sockets, ok := c.agentToConnectionSockets[id]
 	if ok {
 		// Publish all nodes that want to connect to the
 		// desired agent ID.
-		nodes := make([]*Node, 0, len(sockets))
+		nodes := make(map[uuid.UUID]*Node, len(sockets))
 		for targetID := range sockets {
 			node, ok := c.nodes[targetID]
 			if !ok {
 				continue
 			}
-			nodes = append(nodes, node)
+			nodes[targetID] = node
 		}
-		err := tc.Enqueue(nodes)
+		err := enqueueInitialNodes(tc, nodes)
 		// this should never error since we're still the only goroutine that
-		// knows about the TrackedConn.  If we hit an error something really
+		// knows about the Queue.  If we hit an error something really
 		// wrong is happening
 		if err != nil {
 			logger.Critical(ctx, "unable to queue initial nodes", slog.Error(err))
@@ -544,6 +675,25 @@ func (c *core) initAndTrackAgent(ctx context.Context, cancel func(), conn net.Co
 	return tc, nil
 }
 
+// enqueueInitialNodes sends the nodes of the peers in one update, or one per
+// peer if the queue needs to know which peer each node belongs to.
+func enqueueInitialNodes(q Queue, nodes map[uuid.UUID]*Node) error {
+	if pq, ok := q.(PeerQueue); ok {
+		for peerID, node := range nodes {
+			err := pq.EnqueuePeer(peerID, node)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	list := make([]*Node, 0, len(nodes))
+	for _, node := range nodes {
+		list = append(list, node)
+	}
+	return q.Enqueue(list)
+}
+
 func (c *coordinator) handleNextAgentMessage(id uuid.UUID, decoder *json.Decoder) error {
 	logger := c.core.agentLogger(id)
 	var node Node
@@ -588,7 +738,7 @@ func (c *core) agentNodeUpdate(id uuid.UUID, node *Node) error {
 
 	// Publish the new node to every listening socket.
 	for clientID, connectionSocket := range connectionSockets {
-		err := connectionSocket.Enqueue([]*Node{node})
+		err := EnqueuePeer(connectionSocket, id, node)
 		if err == nil {
 			logger.Debug(context.Background(), "enqueued agent node to client",
 				slog.F("client_id", clientID))
@@ -656,6 +806,69 @@ func (c *core) serveHTTPDebug(w http.ResponseWriter, r *http.Request) {
 	)(w, r)
 }
 
+func (c *coordinator) Status(context.Context) (*CoordinatorStatus, error) {
+	return c.core.status(), nil
+}
+
+func (c *core) status() *CoordinatorStatus {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return StatusFromLocal(false, c.agentSockets, c.agentToConnectionSockets, c.nodes, c.agentNameCache)
+}
+
+// StatusFromLocal builds the status of a coordinator from the peers that are
+// connected to it.
+func StatusFromLocal(
+	ha bool,
+	agentSocketsMap map[uuid.UUID]Queue,
+	agentToConnectionSocketsMap map[uuid.UUID]map[uuid.UUID]Queue,
+	nodesMap map[uuid.UUID]*Node,
+	agentNameCache *lru.Cache[uuid.UUID, string],
+) *CoordinatorStatus {
+	status := &CoordinatorStatus{HA: ha}
+	peer := func(id uuid.UUID, name string, agent, connected bool) PeerStatus {
+		p := PeerStatus{
+			ID:        id,
+			Name:      name,
+			Agent:     agent,
+			Connected: connected,
+			Node:      nodesMap[id],
+		}
+		if p.Node != nil {
+			p.LastUpdate = p.Node.AsOf
+		}
+		return p
+	}
+
+	for id, conn := range agentSocketsMap {
+		status.Peers = append(status.Peers, peer(id, conn.Name(), true, true))
+	}
+	seenClients := map[uuid.UUID]struct{}{}
+	for agentID, conns := range agentToConnectionSocketsMap {
+		if len(conns) == 0 {
+			continue
+		}
+		if _, ok := agentSocketsMap[agentID]; !ok {
+			agentName, ok := agentNameCache.Get(agentID)
+			if !ok {
+				agentName = "unknown"
+			}
+			status.Peers = append(status.Peers, peer(agentID, agentName, true, false))
+		}
+		for id, conn := range conns {
+			status.Tunnels = append(status.Tunnels, TunnelStatus{Src: id, Dst: agentID})
+			if _, ok := seenClients[id]; ok {
+				continue
+			}
+			seenClients[id] = struct{}{}
+			status.Peers = append(status.Peers, peer(id, conn.Name(), false, true))
+		}
+	}
+	status.Sort()
+	return status
+}
+
 func HTTPDebugFromLocal(
 	ha bool,
 	agentSocketsMap map[uuid.UUID]Queue,
@@ -804,6 +1017,56 @@ type HTMLNode struct {
 	Node any
 }
 
+// CoordinatorStatus is a snapshot of the peers of a coordinator and the
+// tunnels between them.
+type CoordinatorStatus struct {
+	// HA is true if the coordinator is shared by replicas.
+	HA      bool
+	Peers   []PeerStatus
+	Tunnels []TunnelStatus
+}
+
+// PeerStatus is what the coordinator knows about a peer.
+type PeerStatus struct {
+	ID   uuid.UUID
+	Name string
+	// Agent is true for agents and false for clients.
+	Agent bool
+	// CoordinatorID is the coordinator the peer is connected to. It's only
+	// set by coordinators that are shared by replicas.
+	CoordinatorID uuid.UUID
+	// Connected is false for agents that clients have tunnels to, but that
+	// aren't connected to a coordinator.
+	Connected bool
+	// Node is the latest node of the peer, if it has sent one.
+	Node *Node
+	// LastUpdate is the last time the node of the peer was updated.
+	LastUpdate time.Time
+}
+
+// TunnelStatus is a tunnel from a client to an agent.
+type TunnelStatus struct {
+	Src uuid.UUID
+	Dst uuid.UUID
+}
+
+// Sort orders agents before clients, and both by name and ID, so the status is
+// stable across calls.
+func (s *CoordinatorStatus) Sort() {
+	slices.SortFunc(s.Peers, func(a, b PeerStatus) int {
+		if a.Agent != b.Agent {
+			if a.Agent {
+				return -1
+			}
+			return 1
+		}
+		return slice.Ascending(a.Name+a.ID.String(), b.Name+b.ID.String())
+	})
+	slices.SortFunc(s.Tunnels, func(a, b TunnelStatus) int {
+		return slice.Ascending(a.Dst.String()+a.Src.String(), b.Dst.String()+b.Src.String())
+	})
+}
+
 var coordinatorDebugTmpl = `
 <!DOCTYPE html>
 <html>
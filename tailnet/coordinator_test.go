@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"nhooyr.io/websocket"
+	"tailscale.com/types/key"
 
 	"cdr.dev/slog"
 	"cdr.dev/slog/sloggers/slogtest"
@@ -19,6 +20,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/coder/coder/v2/tailnet"
+	"github.com/coder/coder/v2/tailnet/proto"
 	"github.com/coder/coder/v2/testutil"
 )
 
@@ -355,6 +357,288 @@ func TestCoordinator_AgentUpdateWhileClientConnects(t *testing.T) {
 	require.Equal(t, 1, cNodes[0].PreferredDERP)
 }
 
+func TestCoordinator_V2(t *testing.T) {
+	t.Parallel()
+	t.Run("AgentWithClient", func(t *testing.T) {
+		t.Parallel()
+		logger := slogtest.Make(t, nil).Leveled(slog.LevelDebug)
+		coordinator := tailnet.NewCoordinator(logger)
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitShort)
+		defer cancel()
+
+		agentID := uuid.New()
+		agentReqs, agentResps := coordinator.Coordinate(ctx, agentID, "agent", tailnet.AgentTunnelAuth{})
+		sendRequest(ctx, t, agentReqs, updateSelf(t, 1))
+		require.Eventually(t, func() bool {
+			return coordinator.Node(agentID) != nil
+		}, testutil.WaitShort, testutil.IntervalFast)
+
+		clientID := uuid.New()
+		clientReqs, clientResps := coordinator.Coordinate(ctx, clientID, "client", tailnet.ClientTunnelAuth{AgentID: agentID})
+		sendRequest(ctx, t, clientReqs, &proto.CoordinateRequest{
+			AddTunnel: &proto.CoordinateRequest_Tunnel{Uuid: agentID[:]},
+		})
+		update := recvPeerUpdate(ctx, t, clientResps)
+		require.Equal(t, agentID[:], update.Uuid)
+		require.Equal(t, proto.CoordinateResponse_PeerUpdate_NODE, update.Kind)
+		require.EqualValues(t, 1, update.Node.PreferredDerp)
+
+		sendRequest(ctx, t, clientReqs, updateSelf(t, 2))
+		update = recvPeerUpdate(ctx, t, agentResps)
+		require.Equal(t, clientID[:], update.Uuid)
+		require.Equal(t, proto.CoordinateResponse_PeerUpdate_NODE, update.Kind)
+		require.EqualValues(t, 2, update.Node.PreferredDerp)
+
+		// The agent is told that the client left on purpose.
+		sendRequest(ctx, t, clientReqs, &proto.CoordinateRequest{
+			Disconnect: &proto.CoordinateRequest_Disconnect{},
+		})
+		update = recvPeerUpdate(ctx, t, agentResps)
+		require.Equal(t, clientID[:], update.Uuid)
+		require.Equal(t, proto.CoordinateResponse_PeerUpdate_DISCONNECTED, update.Kind)
+		requireClosed(ctx, t, clientResps)
+
+		close(agentReqs)
+		requireClosed(ctx, t, agentResps)
+	})
+
+	t.Run("AgentLost", func(t *testing.T) {
+		t.Parallel()
+		logger := slogtest.Make(t, nil).Leveled(slog.LevelDebug)
+		coordinator := tailnet.NewCoordinator(logger)
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitShort)
+		defer cancel()
+
+		agentID := uuid.New()
+		agentReqs, agentResps := coordinator.Coordinate(ctx, agentID, "agent", tailnet.AgentTunnelAuth{})
+		sendRequest(ctx, t, agentReqs, updateSelf(t, 1))
+
+		clientID := uuid.New()
+		clientReqs, clientResps := coordinator.Coordinate(ctx, clientID, "client", tailnet.ClientTunnelAuth{AgentID: agentID})
+		sendRequest(ctx, t, clientReqs, &proto.CoordinateRequest{
+			AddTunnel: &proto.CoordinateRequest_Tunnel{Uuid: agentID[:]},
+		})
+		update := recvPeerUpdate(ctx, t, clientResps)
+		require.Equal(t, proto.CoordinateResponse_PeerUpdate_NODE, update.Kind)
+
+		// Closing the requests without a disconnect means the agent was lost.
+		close(agentReqs)
+		requireClosed(ctx, t, agentResps)
+		update = recvPeerUpdate(ctx, t, clientResps)
+		require.Equal(t, agentID[:], update.Uuid)
+		require.Equal(t, proto.CoordinateResponse_PeerUpdate_LOST, update.Kind)
+
+		close(clientReqs)
+		requireClosed(ctx, t, clientResps)
+	})
+
+	t.Run("UnauthorizedTunnel", func(t *testing.T) {
+		t.Parallel()
+		logger := slogtest.Make(t, nil).Leveled(slog.LevelDebug)
+		coordinator := tailnet.NewCoordinator(logger)
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitShort)
+		defer cancel()
+
+		otherAgentID := uuid.New()
+		reqs, resps := coordinator.Coordinate(ctx, uuid.New(), "client", tailnet.ClientTunnelAuth{AgentID: uuid.New()})
+		sendRequest(ctx, t, reqs, &proto.CoordinateRequest{
+			AddTunnel: &proto.CoordinateRequest_Tunnel{Uuid: otherAgentID[:]},
+		})
+		select {
+		case <-ctx.Done():
+			t.Fatal("timeout")
+		case resp := <-resps:
+			require.Contains(t, resp.Error, "unauthorized tunnel")
+		}
+		requireClosed(ctx, t, resps)
+		close(reqs)
+	})
+
+	t.Run("V1AgentWithV2Client", func(t *testing.T) {
+		t.Parallel()
+		logger := slogtest.Make(t, nil).Leveled(slog.LevelDebug)
+		coordinator := tailnet.NewCoordinator(logger)
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitShort)
+		defer cancel()
+
+		agentWS, agentServerWS := net.Pipe()
+		defer agentWS.Close()
+		agentNodeChan := make(chan []*tailnet.Node)
+		sendAgentNode, agentErrChan := tailnet.ServeCoordinator(agentWS, func(nodes []*tailnet.Node) error {
+			agentNodeChan <- nodes
+			return nil
+		})
+		agentID := uuid.New()
+		closeAgentChan := make(chan struct{})
+		go func() {
+			err := coordinator.ServeAgent(agentServerWS, agentID, "")
+			assert.NoError(t, err)
+			close(closeAgentChan)
+		}()
+		sendAgentNode(&tailnet.Node{PreferredDERP: 1})
+		require.Eventually(t, func() bool {
+			return coordinator.Node(agentID) != nil
+		}, testutil.WaitShort, testutil.IntervalFast)
+
+		clientID := uuid.New()
+		clientReqs, clientResps := coordinator.Coordinate(ctx, clientID, "client", tailnet.ClientTunnelAuth{AgentID: agentID})
+		sendRequest(ctx, t, clientReqs, &proto.CoordinateRequest{
+			AddTunnel: &proto.CoordinateRequest_Tunnel{Uuid: agentID[:]},
+		})
+		update := recvPeerUpdate(ctx, t, clientResps)
+		require.Equal(t, agentID[:], update.Uuid)
+		require.EqualValues(t, 1, update.Node.PreferredDerp)
+
+		// The v1 agent gets the node of the v2 client as usual.
+		sendRequest(ctx, t, clientReqs, updateSelf(t, 2))
+		select {
+		case <-ctx.Done():
+			t.Fatal("timeout")
+		case nodes := <-agentNodeChan:
+			require.Len(t, nodes, 1)
+			require.Equal(t, 2, nodes[0].PreferredDERP)
+		}
+
+		close(clientReqs)
+		requireClosed(ctx, t, clientResps)
+		require.NoError(t, agentWS.Close())
+		<-agentErrChan
+		<-closeAgentChan
+	})
+}
+
+func TestCoordinator_Status(t *testing.T) {
+	t.Parallel()
+	logger := slogtest.Make(t, nil).Leveled(slog.LevelDebug)
+	coordinator := tailnet.NewCoordinator(logger)
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitShort)
+	defer cancel()
+
+	agentID := uuid.New()
+	agentReqs, agentResps := coordinator.Coordinate(ctx, agentID, "agent", tailnet.AgentTunnelAuth{})
+	defer close(agentReqs)
+	sendRequest(ctx, t, agentReqs, updateSelf(t, 1))
+
+	// A tunnel to an agent that isn't connected.
+	missingID := uuid.New()
+	clientID := uuid.New()
+	clientReqs, clientResps := coordinator.Coordinate(ctx, clientID, "client", agentsTunnelAuth{agentID, missingID})
+	defer close(clientReqs)
+	sendRequest(ctx, t, clientReqs, updateSelf(t, 2))
+	sendRequest(ctx, t, clientReqs, &proto.CoordinateRequest{
+		AddTunnel: &proto.CoordinateRequest_Tunnel{Uuid: agentID[:]},
+	})
+	sendRequest(ctx, t, clientReqs, &proto.CoordinateRequest{
+		AddTunnel: &proto.CoordinateRequest_Tunnel{Uuid: missingID[:]},
+	})
+	update := recvPeerUpdate(ctx, t, clientResps)
+	require.Equal(t, agentID[:], update.Uuid)
+	update = recvPeerUpdate(ctx, t, agentResps)
+	require.Equal(t, clientID[:], update.Uuid)
+
+	var status *tailnet.CoordinatorStatus
+	require.Eventually(t, func() bool {
+		var err error
+		status, err = coordinator.Status(ctx)
+		require.NoError(t, err)
+		return len(status.Tunnels) == 2
+	}, testutil.WaitShort, testutil.IntervalFast)
+
+	require.False(t, status.HA)
+	require.Len(t, status.Peers, 3)
+	peers := map[uuid.UUID]tailnet.PeerStatus{}
+	for _, peer := range status.Peers {
+		peers[peer.ID] = peer
+	}
+	require.True(t, status.Peers[0].Agent)
+	require.True(t, status.Peers[1].Agent)
+	require.False(t, status.Peers[2].Agent)
+
+	agent := peers[agentID]
+	require.Equal(t, "agent", agent.Name)
+	require.True(t, agent.Connected)
+	require.NotNil(t, agent.Node)
+	require.Equal(t, 1, agent.Node.PreferredDERP)
+
+	missing := peers[missingID]
+	require.Equal(t, "unknown", missing.Name)
+	require.False(t, missing.Connected)
+	require.Nil(t, missing.Node)
+
+	client := peers[clientID]
+	require.Equal(t, "client", client.Name)
+	require.True(t, client.Connected)
+	require.NotNil(t, client.Node)
+	require.Equal(t, 2, client.Node.PreferredDERP)
+
+	require.ElementsMatch(t, []tailnet.TunnelStatus{
+		{Src: clientID, Dst: agentID},
+		{Src: clientID, Dst: missingID},
+	}, status.Tunnels)
+}
+
+// agentsTunnelAuth allows tunnels to any of the agents.
+type agentsTunnelAuth []uuid.UUID
+
+func (a agentsTunnelAuth) Authorize(dst uuid.UUID) bool {
+	for _, id := range a {
+		if id == dst {
+			return true
+		}
+	}
+	return false
+}
+
+func updateSelf(t *testing.T, preferredDERP int) *proto.CoordinateRequest {
+	t.Helper()
+	node, err := tailnet.NodeToProto(&tailnet.Node{
+		PreferredDERP: preferredDERP,
+		Key:           key.NewNode().Public(),
+		DiscoKey:      key.NewDisco().Public(),
+	})
+	require.NoError(t, err)
+	return &proto.CoordinateRequest{
+		UpdateSelf: &proto.CoordinateRequest_UpdateSelf{Node: node},
+	}
+}
+
+func sendRequest(ctx context.Context, t *testing.T, reqs chan<- *proto.CoordinateRequest, req *proto.CoordinateRequest) {
+	t.Helper()
+	select {
+	case <-ctx.Done():
+		t.Fatal("timeout sending request")
+	case reqs <- req:
+	}
+}
+
+func recvPeerUpdate(ctx context.Context, t *testing.T, resps <-chan *proto.CoordinateResponse) *proto.CoordinateResponse_PeerUpdate {
+	t.Helper()
+	select {
+	case <-ctx.Done():
+		t.Fatal("timeout receiving response")
+		return nil
+	case resp, ok := <-resps:
+		require.True(t, ok, "responses closed")
+		require.Empty(t, resp.Error)
+		require.Len(t, resp.PeerUpdates, 1)
+		return resp.PeerUpdates[0]
+	}
+}
+
+func requireClosed(ctx context.Context, t *testing.T, resps <-chan *proto.CoordinateResponse) {
+	t.Helper()
+	for {
+		select {
+		case <-ctx.Done():
+			t.Fatal("timeout waiting for responses to close")
+		case _, ok := <-resps:
+			if !ok {
+				return
+			}
+		}
+	}
+}
+
 func websocketConn(ctx context.Context, t *testing.T) (client net.Conn, server net.Conn) {
 	t.Helper()
 	sc := make(chan net.Conn, 1)
@@ -0,0 +1,300 @@
+package tailnet
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/tailnet/proto"
+)
+
+const (
+	// RequestBufferSize is the number of requests from a v2 peer that are
+	// buffered before the peer is blocked.
+	RequestBufferSize = 32
+	// ResponseBufferSize is the number of responses to a v2 peer that are
+	// buffered. Updates are batched, so it's rarely full.
+	ResponseBufferSize = 512
+)
+
+// PeerQueue is a Queue that is told which peer each node belongs to, as the v2
+// coordinator protocol requires. v1 connections only receive nodes.
+type PeerQueue interface {
+	Queue
+	EnqueuePeer(id uuid.UUID, node *Node) error
+	RemovePeer(id uuid.UUID, kind proto.CoordinateResponse_PeerUpdate_Kind, reason string) error
+}
+
+// EnqueuePeer enqueues the node of the peer with the given ID.
+func EnqueuePeer(q Queue, id uuid.UUID, node *Node) error {
+	if pq, ok := q.(PeerQueue); ok {
+		return pq.EnqueuePeer(id, node)
+	}
+	return q.Enqueue([]*Node{node})
+}
+
+// RemovePeer tells the queue that the peer with the given ID went away. v1
+// connections can't be told, so they keep the node until it's replaced.
+func RemovePeer(q Queue, id uuid.UUID, kind proto.CoordinateResponse_PeerUpdate_Kind, reason string) error {
+	if pq, ok := q.(PeerQueue); ok {
+		return pq.RemovePeer(id, kind, reason)
+	}
+	return nil
+}
+
+// PeerConn is the coordinator side of a v2 protocol connection. Peer updates
+// are coalesced by peer while a response is being sent, and sent together in
+// the next one.
+type PeerConn struct {
+	ctx    context.Context
+	cancel func()
+	id     uuid.UUID
+	name   string
+	logger slog.Logger
+	resps  chan<- *proto.CoordinateResponse
+	wake   chan struct{}
+
+	mu      sync.Mutex
+	pending map[uuid.UUID]*proto.CoordinateResponse_PeerUpdate
+	err     string
+	closed  bool
+
+	start      int64
+	lastWrite  int64
+	overwrites int64
+}
+
+func NewPeerConn(ctx context.Context, cancel func(), id uuid.UUID, name string, logger slog.Logger, resps chan<- *proto.CoordinateResponse, overwrites int64) *PeerConn {
+	now := time.Now().Unix()
+	return &PeerConn{
+		ctx:        ctx,
+		cancel:     cancel,
+		id:         id,
+		name:       name,
+		logger:     logger,
+		resps:      resps,
+		wake:       make(chan struct{}, 1),
+		pending:    map[uuid.UUID]*proto.CoordinateResponse_PeerUpdate{},
+		start:      now,
+		lastWrite:  now,
+		overwrites: overwrites,
+	}
+}
+
+// Enqueue is part of the Queue interface. The v2 protocol identifies peers by
+// ID, so coordinators must use EnqueuePeer instead.
+func (*PeerConn) Enqueue([]*Node) error {
+	return xerrors.New("nodes must be enqueued with the ID of their peer")
+}
+
+func (p *PeerConn) EnqueuePeer(id uuid.UUID, node *Node) error {
+	pn, err := NodeToProto(node)
+	if err != nil {
+		return xerrors.Errorf("convert node: %w", err)
+	}
+	p.update(id, &proto.CoordinateResponse_PeerUpdate{
+		Uuid: id[:],
+		Node: pn,
+		Kind: proto.CoordinateResponse_PeerUpdate_NODE,
+	})
+	return nil
+}
+
+func (p *PeerConn) RemovePeer(id uuid.UUID, kind proto.CoordinateResponse_PeerUpdate_Kind, reason string) error {
+	p.update(id, &proto.CoordinateResponse_PeerUpdate{
+		Uuid:   id[:],
+		Kind:   kind,
+		Reason: reason,
+	})
+	return nil
+}
+
+func (p *PeerConn) update(id uuid.UUID, update *proto.CoordinateResponse_PeerUpdate) {
+	atomic.StoreInt64(&p.lastWrite, time.Now().Unix())
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return
+	}
+	// Only the latest update for a peer matters.
+	p.pending[id] = update
+	p.notify()
+}
+
+func (p *PeerConn) notify() {
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+// CloseWithError sends the error to the peer and then closes the connection.
+func (p *PeerConn) CloseWithError(message string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return
+	}
+	p.closed = true
+	p.err = message
+	p.notify()
+}
+
+func (p *PeerConn) UniqueID() uuid.UUID {
+	return p.id
+}
+
+func (p *PeerConn) Name() string {
+	return p.name
+}
+
+func (p *PeerConn) Stats() (start, lastWrite int64) {
+	return p.start, atomic.LoadInt64(&p.lastWrite)
+}
+
+func (p *PeerConn) Overwrites() int64 {
+	return p.overwrites
+}
+
+func (p *PeerConn) CoordinatorClose() error {
+	return p.Close()
+}
+
+// Close stops sending updates. If an error is waiting to be sent, the
+// connection is closed once it has been.
+func (p *PeerConn) Close() error {
+	p.mu.Lock()
+	p.closed = true
+	sendingErr := p.err != ""
+	p.mu.Unlock()
+	if !sendingErr {
+		p.cancel()
+	}
+	return nil
+}
+
+// SendUpdates sends batched peer updates until the connection is closed, and
+// then closes the response channel.
+func (p *PeerConn) SendUpdates() {
+	defer close(p.resps)
+	defer p.cancel()
+	for {
+		select {
+		case <-p.ctx.Done():
+			p.logger.Debug(p.ctx, "done sending updates")
+			return
+		case <-p.wake:
+		}
+
+		p.mu.Lock()
+		pending := p.pending
+		p.pending = map[uuid.UUID]*proto.CoordinateResponse_PeerUpdate{}
+		errMessage := p.err
+		p.mu.Unlock()
+
+		resp := &proto.CoordinateResponse{
+			PeerUpdates: make([]*proto.CoordinateResponse_PeerUpdate, 0, len(pending)),
+			Error:       errMessage,
+		}
+		for _, update := range pending {
+			resp.PeerUpdates = append(resp.PeerUpdates, update)
+		}
+		sort.Slice(resp.PeerUpdates, func(i, j int) bool {
+			return bytes.Compare(resp.PeerUpdates[i].Uuid, resp.PeerUpdates[j].Uuid) < 0
+		})
+		if len(resp.PeerUpdates) == 0 && errMessage == "" {
+			continue
+		}
+
+		select {
+		case <-p.ctx.Done():
+			p.logger.Debug(p.ctx, "done sending updates")
+			return
+		case p.resps <- resp:
+		}
+		if errMessage != "" {
+			p.logger.Debug(p.ctx, "closed connection with error", slog.F("error", errMessage))
+			return
+		}
+	}
+}
+
+// CoordinateHandler applies the requests of a v2 peer to a coordinator.
+type CoordinateHandler interface {
+	UpdateSelf(node *Node) error
+	AddTunnel(dst uuid.UUID) error
+	RemoveTunnel(dst uuid.UUID) error
+}
+
+// HandleCoordinateRequests reads requests from a v2 peer until it disconnects,
+// and returns how it went away. Tunnels that aren't authorized close the
+// connection with an error.
+func HandleCoordinateRequests(
+	ctx context.Context, logger slog.Logger,
+	reqs <-chan *proto.CoordinateRequest, pc *PeerConn,
+	auth TunnelAuth, h CoordinateHandler,
+) (kind proto.CoordinateResponse_PeerUpdate_Kind, reason string) {
+	for {
+		var req *proto.CoordinateRequest
+		select {
+		case <-ctx.Done():
+			return proto.CoordinateResponse_PeerUpdate_LOST, "connection closed"
+		case r, ok := <-reqs:
+			if !ok {
+				return proto.CoordinateResponse_PeerUpdate_LOST, "requests closed"
+			}
+			req = r
+		}
+
+		if req.Disconnect != nil {
+			logger.Debug(ctx, "peer disconnected gracefully")
+			return proto.CoordinateResponse_PeerUpdate_DISCONNECTED, "graceful disconnect"
+		}
+		if upd := req.UpdateSelf; upd != nil {
+			node, err := ProtoToNode(upd.Node)
+			if err != nil {
+				pc.CloseWithError("invalid node: " + err.Error())
+				return proto.CoordinateResponse_PeerUpdate_LOST, "invalid node"
+			}
+			err = h.UpdateSelf(node)
+			if err != nil {
+				logger.Warn(ctx, "failed to update node", slog.Error(err))
+			}
+		}
+		if tun := req.AddTunnel; tun != nil {
+			dst, err := ParseUUID(tun.Uuid)
+			if err != nil {
+				pc.CloseWithError("invalid tunnel: " + err.Error())
+				return proto.CoordinateResponse_PeerUpdate_LOST, "invalid tunnel"
+			}
+			if !auth.Authorize(dst) {
+				logger.Warn(ctx, "unauthorized tunnel", slog.F("dst_id", dst))
+				pc.CloseWithError(fmt.Sprintf("unauthorized tunnel to %s", dst))
+				return proto.CoordinateResponse_PeerUpdate_LOST, "unauthorized tunnel"
+			}
+			err = h.AddTunnel(dst)
+			if err != nil {
+				logger.Warn(ctx, "failed to add tunnel", slog.F("dst_id", dst), slog.Error(err))
+			}
+		}
+		if tun := req.RemoveTunnel; tun != nil {
+			dst, err := ParseUUID(tun.Uuid)
+			if err != nil {
+				pc.CloseWithError("invalid tunnel: " + err.Error())
+				return proto.CoordinateResponse_PeerUpdate_LOST, "invalid tunnel"
+			}
+			err = h.RemoveTunnel(dst)
+			if err != nil {
+				logger.Warn(ctx, "failed to remove tunnel", slog.F("dst_id", dst), slog.Error(err))
+			}
+		}
+	}
+}
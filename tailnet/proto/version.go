@@ -0,0 +1,53 @@
+package proto
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// Version 1 of the coordinator protocol exchanges JSON encoded nodes over a
+// websocket. Version 2 is the protobuf protocol in tailnet.proto.
+const (
+	CurrentMajor = 2
+	CurrentMinor = 0
+)
+
+// CurrentVersion is the newest version of the coordinator protocol.
+var CurrentVersion = fmt.Sprintf("%d.%d", CurrentMajor, CurrentMinor)
+
+// ParseVersion parses a version of the form "major.minor".
+func ParseVersion(version string) (major, minor int, err error) {
+	parts := strings.Split(version, ".")
+	if len(parts) != 2 {
+		return 0, 0, xerrors.Errorf("invalid version %q: must be of the form major.minor", version)
+	}
+	major, err = strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, xerrors.Errorf("invalid major version %q: %w", parts[0], err)
+	}
+	minor, err = strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, xerrors.Errorf("invalid minor version %q: %w", parts[1], err)
+	}
+	return major, minor, nil
+}
+
+// ValidateVersion returns an error if a peer speaking the given version can't
+// be served. Every major version up to the current one is supported, and
+// minor versions are backwards compatible.
+func ValidateVersion(version string) error {
+	major, minor, err := ParseVersion(version)
+	if err != nil {
+		return err
+	}
+	if major < 1 || major > CurrentMajor {
+		return xerrors.Errorf("unsupported major version %d: supported major versions are 1 to %d", major, CurrentMajor)
+	}
+	if major == CurrentMajor && minor > CurrentMinor {
+		return xerrors.Errorf("unsupported version %s: the newest supported version is %s", version, CurrentVersion)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package proto_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/tailnet/proto"
+)
+
+func TestValidateVersion(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name    string
+		version string
+		err     string
+	}{
+		{name: "V1", version: "1.0"},
+		{name: "Current", version: proto.CurrentVersion},
+		{name: "OlderMinor", version: "1.5"},
+		{name: "NewerMinor", version: "2.1", err: "unsupported version"},
+		{name: "NewerMajor", version: "3.0", err: "unsupported major version"},
+		{name: "ZeroMajor", version: "0.9", err: "unsupported major version"},
+		{name: "Malformed", version: "2", err: "must be of the form major.minor"},
+		{name: "NotANumber", version: "two.0", err: "invalid major version"},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := proto.ValidateVersion(tc.version)
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
@@ -0,0 +1,219 @@
+package tailnet
+
+import (
+	"context"
+	"io"
+	"net"
+	"sync/atomic"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/yamux"
+	"golang.org/x/xerrors"
+	"storj.io/drpc/drpcmux"
+	"storj.io/drpc/drpcserver"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/codersdk/drpc"
+	"github.com/coder/coder/v2/tailnet/proto"
+)
+
+type streamIDContextKey struct{}
+
+// StreamID identifies the caller of the Coordinate RPC. It's stored on the
+// context, since the caller is authenticated at the HTTP layer before the
+// connection is handed to dRPC.
+type StreamID struct {
+	Name string
+	ID   uuid.UUID
+	Auth TunnelAuth
+}
+
+// WithStreamID returns a context that serves Coordinate RPCs as streamID.
+func WithStreamID(ctx context.Context, streamID StreamID) context.Context {
+	return context.WithValue(ctx, streamIDContextKey{}, streamID)
+}
+
+// ClientService serves coordinator connections of clients and agents. It
+// speaks the version of the protocol that the peer asks for: v1 peers exchange
+// JSON nodes with the coordinator directly, and v2 peers call the Coordinate
+// RPC over a multiplexed connection.
+type ClientService struct {
+	logger   slog.Logger
+	coordPtr *atomic.Pointer[Coordinator]
+	drpc     *drpcserver.Server
+}
+
+// NewClientService returns a ClientService that serves peers with the
+// coordinator stored in coordPtr, so it can be swapped at runtime.
+func NewClientService(logger slog.Logger, coordPtr *atomic.Pointer[Coordinator]) (*ClientService, error) {
+	s := &ClientService{logger: logger, coordPtr: coordPtr}
+	mux := drpcmux.New()
+	err := proto.DRPCRegisterTailnet(mux, &DRPCService{
+		CoordPtr: coordPtr,
+		Logger:   logger,
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("register DRPC service: %w", err)
+	}
+	s.drpc = drpcserver.NewWithOptions(mux, drpcserver.Options{
+		Log: func(err error) {
+			if xerrors.Is(err, io.EOF) {
+				return
+			}
+			logger.Debug(context.Background(), "drpc server error", slog.Error(err))
+		},
+	})
+	return s, nil
+}
+
+// ServeClient serves a client that may only open tunnels to agent.
+func (s *ClientService) ServeClient(ctx context.Context, version string, conn net.Conn, id uuid.UUID, agent uuid.UUID) error {
+	major, _, err := parseValidVersion(version)
+	if err != nil {
+		return err
+	}
+	if major == 1 {
+		coord := *(s.coordPtr.Load())
+		return coord.ServeClient(conn, id, agent)
+	}
+	return s.serve(ctx, conn, StreamID{
+		Name: "client",
+		ID:   id,
+		Auth: ClientTunnelAuth{AgentID: agent},
+	})
+}
+
+// ServeAgent serves an agent. Name is just used for debug information.
+func (s *ClientService) ServeAgent(ctx context.Context, version string, conn net.Conn, id uuid.UUID, name string) error {
+	major, _, err := parseValidVersion(version)
+	if err != nil {
+		return err
+	}
+	if major == 1 {
+		coord := *(s.coordPtr.Load())
+		return coord.ServeAgent(conn, id, name)
+	}
+	return s.serve(ctx, conn, StreamID{
+		Name: name,
+		ID:   id,
+		Auth: AgentTunnelAuth{},
+	})
+}
+
+func (s *ClientService) serve(ctx context.Context, conn net.Conn, streamID StreamID) error {
+	config := yamux.DefaultConfig()
+	config.LogOutput = io.Discard
+	session, err := yamux.Server(conn, config)
+	if err != nil {
+		return xerrors.Errorf("multiplex server: %w", err)
+	}
+	err = s.drpc.Serve(WithStreamID(ctx, streamID), session)
+	if err != nil && !xerrors.Is(err, io.EOF) {
+		return xerrors.Errorf("serve: %w", err)
+	}
+	return nil
+}
+
+func parseValidVersion(version string) (major, minor int, err error) {
+	if version == "" {
+		// Peers that predate versioning speak v1.
+		version = "1.0"
+	}
+	err = proto.ValidateVersion(version)
+	if err != nil {
+		return 0, 0, err
+	}
+	return proto.ParseVersion(version)
+}
+
+// DRPCService implements the v2 protocol as proto.DRPCTailnetServer. Callers
+// must be identified by WithStreamID.
+type DRPCService struct {
+	CoordPtr *atomic.Pointer[Coordinator]
+	Logger   slog.Logger
+}
+
+func (s *DRPCService) Coordinate(stream proto.DRPCTailnet_CoordinateStream) error {
+	ctx := stream.Context()
+	streamID, ok := ctx.Value(streamIDContextKey{}).(StreamID)
+	if !ok {
+		_ = stream.Close()
+		return xerrors.New("no stream ID")
+	}
+	logger := s.Logger.With(slog.F("peer_id", streamID.ID), slog.F("name", streamID.Name))
+	logger.Debug(ctx, "starting tailnet coordinate")
+	coord := *(s.CoordPtr.Load())
+	reqs, resps := coord.Coordinate(ctx, streamID.ID, streamID.Name, streamID.Auth)
+
+	go func() {
+		// Closing the requests tells the coordinator the peer is gone.
+		defer close(reqs)
+		for {
+			req, err := stream.Recv()
+			if err != nil {
+				logger.Debug(ctx, "error receiving requests from stream", slog.Error(err))
+				return
+			}
+			err = sendCtx(ctx, reqs, req)
+			if err != nil {
+				logger.Debug(ctx, "context done while sending coordinate request", slog.Error(err))
+				return
+			}
+		}
+	}()
+
+	defer func() {
+		err := stream.Close()
+		if err != nil {
+			logger.Debug(ctx, "error closing stream", slog.Error(err))
+		}
+	}()
+	for {
+		resp, err := recvCtx(ctx, resps)
+		if err != nil {
+			logger.Debug(ctx, "done receiving coordinate responses", slog.Error(err))
+			return nil
+		}
+		err = stream.Send(resp)
+		if err != nil {
+			logger.Debug(ctx, "error sending response to stream", slog.Error(err))
+			return nil
+		}
+	}
+}
+
+// NewDRPCClient returns a client of the v2 protocol over conn, which must be
+// served by ClientService with version 2.
+func NewDRPCClient(conn net.Conn) (proto.DRPCTailnetClient, error) {
+	config := yamux.DefaultConfig()
+	config.LogOutput = io.Discard
+	session, err := yamux.Client(conn, config)
+	if err != nil {
+		return nil, xerrors.Errorf("multiplex client: %w", err)
+	}
+	return proto.NewDRPCTailnetClient(drpc.MultiplexedConn(session)), nil
+}
+
+var errResponsesClosed = xerrors.New("responses closed")
+
+func sendCtx[A any](ctx context.Context, c chan<- A, a A) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case c <- a:
+		return nil
+	}
+}
+
+func recvCtx[A any](ctx context.Context, c <-chan A) (A, error) {
+	select {
+	case <-ctx.Done():
+		var a A
+		return a, ctx.Err()
+	case a, ok := <-c:
+		if !ok {
+			return a, errResponsesClosed
+		}
+		return a, nil
+	}
+}
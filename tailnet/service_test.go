@@ -0,0 +1,100 @@
+package tailnet_test
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/slogtest"
+
+	"github.com/coder/coder/v2/tailnet"
+	"github.com/coder/coder/v2/tailnet/proto"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestClientService_ServeClient_V2(t *testing.T) {
+	t.Parallel()
+	logger := slogtest.Make(t, nil).Leveled(slog.LevelDebug)
+	coordPtr := atomic.Pointer[tailnet.Coordinator]{}
+	coord := tailnet.NewCoordinator(logger)
+	coordPtr.Store(&coord)
+	uut, err := tailnet.NewClientService(logger, &coordPtr)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitShort)
+	defer cancel()
+
+	agentID := uuid.New()
+	agentReqs, agentResps := coord.Coordinate(ctx, agentID, "agent", tailnet.AgentTunnelAuth{})
+	sendRequest(ctx, t, agentReqs, updateSelf(t, 1))
+	defer close(agentReqs)
+
+	c, s := net.Pipe()
+	defer c.Close()
+	defer s.Close()
+	clientID := uuid.New()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- uut.ServeClient(ctx, "2.0", s, clientID, agentID)
+	}()
+
+	client, err := tailnet.NewDRPCClient(c)
+	require.NoError(t, err)
+	stream, err := client.Coordinate(ctx)
+	require.NoError(t, err)
+
+	err = stream.Send(&proto.CoordinateRequest{
+		AddTunnel: &proto.CoordinateRequest_Tunnel{Uuid: agentID[:]},
+	})
+	require.NoError(t, err)
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	require.Len(t, resp.PeerUpdates, 1)
+	require.Equal(t, agentID[:], resp.PeerUpdates[0].Uuid)
+	require.EqualValues(t, 1, resp.PeerUpdates[0].Node.PreferredDerp)
+
+	err = stream.Send(updateSelf(t, 2))
+	require.NoError(t, err)
+	update := recvPeerUpdate(ctx, t, agentResps)
+	require.Equal(t, clientID[:], update.Uuid)
+	require.EqualValues(t, 2, update.Node.PreferredDerp)
+
+	err = stream.Send(&proto.CoordinateRequest{
+		Disconnect: &proto.CoordinateRequest_Disconnect{},
+	})
+	require.NoError(t, err)
+	update = recvPeerUpdate(ctx, t, agentResps)
+	require.Equal(t, clientID[:], update.Uuid)
+	require.Equal(t, proto.CoordinateResponse_PeerUpdate_DISCONNECTED, update.Kind)
+
+	require.NoError(t, c.Close())
+	select {
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for ServeClient to return")
+	case err := <-errCh:
+		require.NoError(t, err)
+	}
+}
+
+func TestClientService_ServeClient_UnsupportedVersion(t *testing.T) {
+	t.Parallel()
+	logger := slogtest.Make(t, nil).Leveled(slog.LevelDebug)
+	coordPtr := atomic.Pointer[tailnet.Coordinator]{}
+	coord := tailnet.NewCoordinator(logger)
+	coordPtr.Store(&coord)
+	uut, err := tailnet.NewClientService(logger, &coordPtr)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitShort)
+	defer cancel()
+	c, s := net.Pipe()
+	defer c.Close()
+	defer s.Close()
+	err = uut.ServeClient(ctx, "3.0", s, uuid.New(), uuid.New())
+	require.ErrorContains(t, err, "unsupported major version")
+}
@@ -0,0 +1,27 @@
+package tailnet
+
+import "github.com/google/uuid"
+
+// TunnelAuth decides which peers a peer may open tunnels to. It is checked
+// for every tunnel requested over the v2 coordinator protocol.
+type TunnelAuth interface {
+	Authorize(dst uuid.UUID) bool
+}
+
+// ClientTunnelAuth allows a client to open a tunnel to the agent it was
+// authorized for when it connected.
+type ClientTunnelAuth struct {
+	AgentID uuid.UUID
+}
+
+func (a ClientTunnelAuth) Authorize(dst uuid.UUID) bool {
+	return dst == a.AgentID
+}
+
+// AgentTunnelAuth identifies the peer as an agent. Agents don't open tunnels;
+// clients open tunnels to them.
+type AgentTunnelAuth struct{}
+
+func (AgentTunnelAuth) Authorize(uuid.UUID) bool {
+	return false
+}
@@ -0,0 +1,92 @@
+package vpn
+
+import (
+	"net"
+	"net/netip"
+	"strings"
+
+	"golang.org/x/net/dns/dnsmessage"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+)
+
+// ServeDNS answers DNS queries for the hostnames of agents on pc until it's
+// closed. AAAA queries are answered with the tailnet IP of the agent, which
+// the proxy accepts in place of the hostname. Queries for names outside of
+// HostnameSuffix are refused, so the resolver is meant to be used for the
+// suffix only.
+func (d *Daemon) ServeDNS(pc net.PacketConn) error {
+	logger := d.logger.Named("dns")
+	resolve := func(name string) (netip.Addr, bool) {
+		agent, ok := d.Resolve(name)
+		return agent.IP, ok
+	}
+	buf := make([]byte, 512)
+	for {
+		n, addr, err := pc.ReadFrom(buf)
+		if err != nil {
+			if xerrors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return xerrors.Errorf("read: %w", err)
+		}
+		resp, err := dnsResponse(buf[:n], resolve)
+		if err != nil {
+			logger.Debug(d.ctx, "invalid dns query", slog.F("addr", addr.String()), slog.Error(err))
+			continue
+		}
+		_, err = pc.WriteTo(resp, addr)
+		if err != nil {
+			logger.Debug(d.ctx, "write dns response", slog.F("addr", addr.String()), slog.Error(err))
+		}
+	}
+}
+
+// dnsResponse returns the response to a DNS query. Only the first question is
+// answered.
+func dnsResponse(query []byte, resolve func(name string) (netip.Addr, bool)) ([]byte, error) {
+	var parser dnsmessage.Parser
+	header, err := parser.Start(query)
+	if err != nil {
+		return nil, xerrors.Errorf("parse header: %w", err)
+	}
+	question, err := parser.Question()
+	if err != nil {
+		return nil, xerrors.Errorf("parse question: %w", err)
+	}
+
+	resp := dnsmessage.Message{
+		Header: dnsmessage.Header{
+			ID:               header.ID,
+			Response:         true,
+			OpCode:           header.OpCode,
+			Authoritative:    true,
+			RecursionDesired: header.RecursionDesired,
+		},
+		Questions: []dnsmessage.Question{question},
+	}
+	name := strings.ToLower(strings.TrimSuffix(question.Name.String(), "."))
+	if !strings.HasSuffix(name, "."+HostnameSuffix) {
+		resp.Header.RCode = dnsmessage.RCodeRefused
+		return resp.Pack()
+	}
+	ip, ok := resolve(name)
+	if !ok {
+		resp.Header.RCode = dnsmessage.RCodeNameError
+		return resp.Pack()
+	}
+	// Agents only have IPv6 addresses, so other types get an empty answer.
+	if question.Type == dnsmessage.TypeAAAA && question.Class == dnsmessage.ClassINET {
+		resp.Answers = []dnsmessage.Resource{{
+			Header: dnsmessage.ResourceHeader{
+				Name:  question.Name,
+				Type:  dnsmessage.TypeAAAA,
+				Class: dnsmessage.ClassINET,
+				TTL:   60,
+			},
+			Body: &dnsmessage.AAAAResource{AAAA: ip.As16()},
+		}}
+	}
+	return resp.Pack()
+}
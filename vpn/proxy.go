@@ -0,0 +1,320 @@
+package vpn
+
+import (
+	"bufio"
+	"context"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/agent/agentssh"
+)
+
+const (
+	socks5Version                  = 0x05
+	socks5MethodNoAuth             = 0x00
+	socks5MethodPassword           = 0x02
+	socks5MethodNoAccept           = 0xff
+	socks5PasswordVersion          = 0x01
+	socks5PasswordSucceeded        = 0x00
+	socks5PasswordFailed           = 0x01
+	socks5CommandConnect           = 0x01
+	socks5AddressIPv4              = 0x01
+	socks5AddressDomain            = 0x03
+	socks5AddressIPv6              = 0x04
+	socks5ReplySucceeded           = 0x00
+	socks5ReplyHostUnreachable     = 0x04
+	socks5ReplyCommandNotSupported = 0x07
+	socks5ReplyAddressNotSupported = 0x08
+)
+
+// DialFunc dials a port of the host that a proxy client requested.
+type DialFunc func(ctx context.Context, host string, port uint16) (net.Conn, error)
+
+// ServeProxy serves SOCKS5 and HTTP CONNECT proxy connections to agents on l
+// until it's closed. Clients must authenticate with password, since anyone on
+// the machine can connect to the listener.
+func (d *Daemon) ServeProxy(l net.Listener, password string) error {
+	if password == "" {
+		return xerrors.New("password is required")
+	}
+	proxyURL := url.URL{
+		Scheme: "socks5h",
+		User:   url.UserPassword(ProxyUsername, password),
+		Host:   l.Addr().String(),
+	}
+	d.mu.Lock()
+	d.proxyURL = proxyURL.String()
+	d.mu.Unlock()
+	return serveProxy(d.ctx, d.logger.Named("proxy"), l, password, d.dialHost)
+}
+
+// ServeProxy serves SOCKS5 and HTTP CONNECT proxy connections on l until it's
+// closed, connecting clients to the hosts they request with dial. Which of the
+// protocols a client speaks is detected from the first byte it sends. Clients
+// don't authenticate.
+func ServeProxy(ctx context.Context, logger slog.Logger, l net.Listener, dial DialFunc) error {
+	return serveProxy(ctx, logger, l, "", dial)
+}
+
+// serveProxy is like ServeProxy, but if password is set, clients must
+// authenticate with it: SOCKS5 clients with the username/password method of
+// RFC 1929, and HTTP clients with basic Proxy-Authorization. The username is
+// ignored.
+func serveProxy(ctx context.Context, logger slog.Logger, l net.Listener, password string, dial DialFunc) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if xerrors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return xerrors.Errorf("accept: %w", err)
+		}
+		go func() {
+			err := handleProxyConn(ctx, conn, password, dial)
+			if err != nil {
+				logger.Debug(ctx, "proxy connection", slog.F("remote_addr", conn.RemoteAddr().String()), slog.Error(err))
+			}
+		}()
+	}
+}
+
+func handleProxyConn(ctx context.Context, conn net.Conn, password string, dial DialFunc) error {
+	br := bufio.NewReader(conn)
+	first, err := br.Peek(1)
+	if err != nil {
+		_ = conn.Close()
+		return xerrors.Errorf("read: %w", err)
+	}
+	client := &bufferedConn{Conn: conn, r: br}
+	if first[0] == socks5Version {
+		return handleSOCKS5(ctx, client, password, dial)
+	}
+	return handleHTTPConnect(ctx, client, password, dial)
+}
+
+// handleSOCKS5 serves a SOCKS5 CONNECT request, see RFC 1928. Clients
+// authenticate with the username/password method if password is set, and
+// without authentication otherwise.
+func handleSOCKS5(ctx context.Context, conn *bufferedConn, password string, dial DialFunc) error {
+	defer conn.Close()
+
+	var greeting [2]byte
+	_, err := io.ReadFull(conn, greeting[:])
+	if err != nil {
+		return xerrors.Errorf("read greeting: %w", err)
+	}
+	methods := make([]byte, greeting[1])
+	_, err = io.ReadFull(conn, methods)
+	if err != nil {
+		return xerrors.Errorf("read methods: %w", err)
+	}
+	required := byte(socks5MethodNoAuth)
+	if password != "" {
+		required = socks5MethodPassword
+	}
+	supported := false
+	for _, method := range methods {
+		if method == required {
+			supported = true
+		}
+	}
+	if !supported {
+		_, _ = conn.Write([]byte{socks5Version, socks5MethodNoAccept})
+		return xerrors.Errorf("client doesn't support authentication method %d", required)
+	}
+	_, err = conn.Write([]byte{socks5Version, required})
+	if err != nil {
+		return xerrors.Errorf("write method: %w", err)
+	}
+	if password != "" {
+		err = authenticateSOCKS5(conn, password)
+		if err != nil {
+			return err
+		}
+	}
+
+	var request [4]byte
+	_, err = io.ReadFull(conn, request[:])
+	if err != nil {
+		return xerrors.Errorf("read request: %w", err)
+	}
+	reply := func(code byte) error {
+		// The bound address isn't meaningful for tunneled connections.
+		_, err := conn.Write([]byte{socks5Version, code, 0, socks5AddressIPv4, 0, 0, 0, 0, 0, 0})
+		return err
+	}
+	if request[0] != socks5Version {
+		return xerrors.Errorf("unsupported version %d", request[0])
+	}
+	if request[1] != socks5CommandConnect {
+		_ = reply(socks5ReplyCommandNotSupported)
+		return xerrors.Errorf("unsupported command %d", request[1])
+	}
+
+	var host string
+	switch request[3] {
+	case socks5AddressIPv4, socks5AddressIPv6:
+		ip := make(net.IP, net.IPv4len)
+		if request[3] == socks5AddressIPv6 {
+			ip = make(net.IP, net.IPv6len)
+		}
+		_, err = io.ReadFull(conn, ip)
+		host = ip.String()
+	case socks5AddressDomain:
+		var length [1]byte
+		_, err = io.ReadFull(conn, length[:])
+		if err == nil {
+			domain := make([]byte, length[0])
+			_, err = io.ReadFull(conn, domain)
+			host = string(domain)
+		}
+	default:
+		_ = reply(socks5ReplyAddressNotSupported)
+		return xerrors.Errorf("unsupported address type %d", request[3])
+	}
+	if err != nil {
+		return xerrors.Errorf("read address: %w", err)
+	}
+	var port [2]byte
+	_, err = io.ReadFull(conn, port[:])
+	if err != nil {
+		return xerrors.Errorf("read port: %w", err)
+	}
+
+	remote, err := dial(ctx, host, binary.BigEndian.Uint16(port[:]))
+	if err != nil {
+		_ = reply(socks5ReplyHostUnreachable)
+		return xerrors.Errorf("dial %s: %w", host, err)
+	}
+	err = reply(socks5ReplySucceeded)
+	if err != nil {
+		_ = remote.Close()
+		return xerrors.Errorf("write reply: %w", err)
+	}
+	agentssh.Bicopy(ctx, conn, remote)
+	return nil
+}
+
+// authenticateSOCKS5 reads the username and password of a client, see RFC
+// 1929.
+func authenticateSOCKS5(conn *bufferedConn, password string) error {
+	var version [1]byte
+	_, err := io.ReadFull(conn, version[:])
+	if err != nil {
+		return xerrors.Errorf("read auth version: %w", err)
+	}
+	if version[0] != socks5PasswordVersion {
+		return xerrors.Errorf("unsupported auth version %d", version[0])
+	}
+	readField := func() ([]byte, error) {
+		var length [1]byte
+		_, err := io.ReadFull(conn, length[:])
+		if err != nil {
+			return nil, err
+		}
+		field := make([]byte, length[0])
+		_, err = io.ReadFull(conn, field)
+		return field, err
+	}
+	_, err = readField()
+	if err != nil {
+		return xerrors.Errorf("read username: %w", err)
+	}
+	got, err := readField()
+	if err != nil {
+		return xerrors.Errorf("read password: %w", err)
+	}
+	if subtle.ConstantTimeCompare(got, []byte(password)) != 1 {
+		_, _ = conn.Write([]byte{socks5PasswordVersion, socks5PasswordFailed})
+		return xerrors.New("invalid password")
+	}
+	_, err = conn.Write([]byte{socks5PasswordVersion, socks5PasswordSucceeded})
+	if err != nil {
+		return xerrors.Errorf("write auth status: %w", err)
+	}
+	return nil
+}
+
+// handleHTTPConnect serves an HTTP CONNECT request. Other methods aren't
+// supported, since every connection is tunneled.
+func handleHTTPConnect(ctx context.Context, conn *bufferedConn, password string, dial DialFunc) error {
+	defer conn.Close()
+
+	req, err := http.ReadRequest(conn.r)
+	if err != nil {
+		return xerrors.Errorf("read request: %w", err)
+	}
+	respond := func(status int) error {
+		_, err := fmt.Fprintf(conn, "HTTP/1.1 %d %s\r\n\r\n", status, http.StatusText(status))
+		return err
+	}
+	if req.Method != http.MethodConnect {
+		_ = respond(http.StatusMethodNotAllowed)
+		return xerrors.Errorf("unsupported method %q", req.Method)
+	}
+	if password != "" && !proxyAuthorized(req, password) {
+		_, _ = fmt.Fprintf(conn, "HTTP/1.1 %d %s\r\nProxy-Authenticate: Basic realm=\"coder\"\r\n\r\n",
+			http.StatusProxyAuthRequired, http.StatusText(http.StatusProxyAuthRequired))
+		return xerrors.New("invalid proxy authorization")
+	}
+	host, rawPort, err := net.SplitHostPort(req.Host)
+	if err != nil {
+		_ = respond(http.StatusBadRequest)
+		return xerrors.Errorf("split host port %q: %w", req.Host, err)
+	}
+	port, err := strconv.ParseUint(rawPort, 10, 16)
+	if err != nil {
+		_ = respond(http.StatusBadRequest)
+		return xerrors.Errorf("parse port %q: %w", rawPort, err)
+	}
+
+	remote, err := dial(ctx, host, uint16(port))
+	if err != nil {
+		_ = respond(http.StatusBadGateway)
+		return xerrors.Errorf("dial %s: %w", host, err)
+	}
+	err = respond(http.StatusOK)
+	if err != nil {
+		_ = remote.Close()
+		return xerrors.Errorf("write response: %w", err)
+	}
+	agentssh.Bicopy(ctx, conn, remote)
+	return nil
+}
+
+// proxyAuthorized returns true if the request has basic Proxy-Authorization
+// with the password.
+func proxyAuthorized(req *http.Request, password string) bool {
+	encoded, ok := strings.CutPrefix(req.Header.Get("Proxy-Authorization"), "Basic ")
+	if !ok {
+		return false
+	}
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return false
+	}
+	_, got, ok := strings.Cut(string(decoded), ":")
+	return ok && subtle.ConstantTimeCompare([]byte(got), []byte(password)) == 1
+}
+
+// bufferedConn reads through a buffered reader that may already hold data
+// that was read from the connection.
+type bufferedConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+func (c *bufferedConn) Read(p []byte) (int, error) {
+	return c.r.Read(p)
+}
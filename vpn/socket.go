@@ -0,0 +1,173 @@
+package vpn
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/agent/agentssh"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// ServeSocket serves the API that other CLI commands reuse the connections of
+// the daemon through on l until it's closed. The listener is expected to be a
+// unix socket that only the user can access, since requests aren't
+// authenticated.
+func (d *Daemon) ServeSocket(l net.Listener) error {
+	server := &http.Server{
+		Handler:           d.socketHandler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext: func(net.Listener) context.Context {
+			return d.ctx
+		},
+	}
+	go func() {
+		<-d.ctx.Done()
+		_ = server.Close()
+	}()
+	err := server.Serve(l)
+	if err != nil && !xerrors.Is(err, http.ErrServerClosed) && !xerrors.Is(err, net.ErrClosed) {
+		return xerrors.Errorf("serve: %w", err)
+	}
+	return nil
+}
+
+// socketHandler serves the status of the daemon on GET /status, and tunnels
+// CONNECT requests for <agent-id>:<port> to the agent.
+func (d *Daemon) socketHandler() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/status":
+			httpapi.Write(ctx, rw, http.StatusOK, d.Status())
+		case r.Method == http.MethodConnect:
+			rawAgentID, rawPort, err := net.SplitHostPort(r.Host)
+			if err != nil {
+				httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+					Message: "Expected <agent-id>:<port>.",
+					Detail:  err.Error(),
+				})
+				return
+			}
+			agentID, err := uuid.Parse(rawAgentID)
+			if err != nil {
+				httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+					Message: "Invalid agent ID.",
+					Detail:  err.Error(),
+				})
+				return
+			}
+			port, err := strconv.ParseUint(rawPort, 10, 16)
+			if err != nil {
+				httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+					Message: "Invalid port.",
+					Detail:  err.Error(),
+				})
+				return
+			}
+			remote, err := d.DialAgent(ctx, agentID, uint16(port))
+			if err != nil {
+				httpapi.Write(ctx, rw, http.StatusBadGateway, codersdk.Response{
+					Message: "Failed to dial agent.",
+					Detail:  err.Error(),
+				})
+				return
+			}
+			hijacker, ok := rw.(http.Hijacker)
+			if !ok {
+				_ = remote.Close()
+				httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+					Message: "Connection can't be hijacked.",
+				})
+				return
+			}
+			rw.WriteHeader(http.StatusOK)
+			conn, brw, err := hijacker.Hijack()
+			if err != nil {
+				_ = remote.Close()
+				d.logger.Debug(ctx, "hijack socket connection", slog.Error(err))
+				return
+			}
+			agentssh.Bicopy(d.ctx, &bufferedConn{Conn: conn, r: brw.Reader}, remote)
+		default:
+			httpapi.ResourceNotFound(rw)
+		}
+	})
+}
+
+// Client talks to a daemon over its unix socket.
+type Client struct {
+	SocketPath string
+}
+
+func (c *Client) dial(ctx context.Context) (net.Conn, error) {
+	var dialer net.Dialer
+	return dialer.DialContext(ctx, "unix", c.SocketPath)
+}
+
+// Status returns the status of the daemon. It fails if no daemon is listening
+// on the socket.
+func (c *Client) Status(ctx context.Context) (Status, error) {
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return c.dial(ctx)
+			},
+		},
+	}
+	defer client.CloseIdleConnections()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://coder-vpn/status", nil)
+	if err != nil {
+		return Status{}, err
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return Status{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return Status{}, codersdk.ReadBodyAsError(res)
+	}
+	var status Status
+	return status, json.NewDecoder(res.Body).Decode(&status)
+}
+
+// DialAgent dials a TCP port of an agent through the daemon.
+func (c *Client) DialAgent(ctx context.Context, agentID uuid.UUID, port uint16) (net.Conn, error) {
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	addr := net.JoinHostPort(agentID.String(), strconv.Itoa(int(port)))
+	_, err = fmt.Fprintf(conn, "CONNECT %s HTTP/1.1\r\nHost: %s\r\n\r\n", addr, addr)
+	if err != nil {
+		_ = conn.Close()
+		return nil, xerrors.Errorf("write request: %w", err)
+	}
+	br := bufio.NewReader(conn)
+	res, err := http.ReadResponse(br, &http.Request{Method: http.MethodConnect})
+	if err != nil {
+		_ = conn.Close()
+		return nil, xerrors.Errorf("read response: %w", err)
+	}
+	if res.StatusCode != http.StatusOK {
+		defer conn.Close()
+		return nil, codersdk.ReadBodyAsError(res)
+	}
+	_ = conn.SetDeadline(time.Time{})
+	return &bufferedConn{Conn: conn, r: br}, nil
+}
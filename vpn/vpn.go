@@ -0,0 +1,437 @@
+// Package vpn implements the daemon behind "coder vpn". It keeps a single
+// tailnet connection to every running agent of the user, and exposes them
+// through a SOCKS5 and HTTP CONNECT proxy, a DNS resolver and a unix socket
+// that other CLI commands reuse the connection over.
+package vpn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+	"nhooyr.io/websocket"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/tailnet"
+	"github.com/coder/retry"
+)
+
+// HostnameSuffix is the top-level domain of the hostnames of agents.
+const HostnameSuffix = "coder"
+
+// DefaultRefreshInterval is how often the daemon looks for agents that started
+// or stopped by default.
+const DefaultRefreshInterval = 15 * time.Second
+
+// Hostname returns the hostname of an agent, which is
+// <agent>.<workspace>.<owner>.coder.
+func Hostname(agentName, workspaceName, ownerName string) string {
+	return strings.ToLower(strings.Join([]string{agentName, workspaceName, ownerName, HostnameSuffix}, "."))
+}
+
+// Agent is a workspace agent that the daemon is connected to.
+type Agent struct {
+	ID            uuid.UUID  `json:"id" table:"-"`
+	Hostname      string     `json:"hostname" table:"hostname,default_sort"`
+	Name          string     `json:"name" table:"agent"`
+	WorkspaceName string     `json:"workspace_name" table:"workspace"`
+	OwnerName     string     `json:"owner_name" table:"owner"`
+	IP            netip.Addr `json:"ip" table:"ip"`
+}
+
+// ProxyUsername is the username in the URL of the proxy. Clients may
+// authenticate with any username.
+const ProxyUsername = "coder"
+
+// Status is the state of a daemon.
+type Status struct {
+	// URL is the deployment the daemon is connected to.
+	URL    string  `json:"url"`
+	Agents []Agent `json:"agents"`
+	// ProxyURL is the URL of the proxy including its credentials, which is
+	// fine since the status is only served on the socket of the user.
+	ProxyURL string `json:"proxy_url,omitempty"`
+}
+
+type Options struct {
+	Client *codersdk.Client
+	Logger slog.Logger
+	// BlockEndpoints forces connections through DERP.
+	BlockEndpoints bool
+	// RefreshInterval defaults to DefaultRefreshInterval.
+	RefreshInterval time.Duration
+	// AgentID restricts the daemon to a single agent if set.
+	AgentID uuid.UUID
+}
+
+// Daemon maintains a tailnet connection to all running agents of the user.
+// Agents are looked up periodically, and each of them is coordinated with
+// over its own coordinator connection.
+type Daemon struct {
+	opts   Options
+	logger slog.Logger
+	conn   *tailnet.Conn
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	closed chan struct{}
+
+	mu       sync.RWMutex
+	agents   map[uuid.UUID]*coordinatedAgent
+	proxyURL string
+
+	// activeMu guards the number of open connections to agents, and when
+	// the last one was closed.
+	activeMu    sync.Mutex
+	activeConns int
+	lastActive  time.Time
+}
+
+// coordinatedAgent is an agent whose node is exchanged with the daemon
+// through the coordinator.
+type coordinatedAgent struct {
+	Agent
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu       sync.Mutex
+	sendNode func(node *tailnet.Node)
+}
+
+func (a *coordinatedAgent) setSendNode(sendNode func(node *tailnet.Node)) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.sendNode = sendNode
+}
+
+func (a *coordinatedAgent) send(node *tailnet.Node) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.sendNode != nil {
+		a.sendNode(node)
+	}
+}
+
+// New connects to the tailnet of the deployment and starts coordinating with
+// the running agents of the user.
+func New(ctx context.Context, opts Options) (*Daemon, error) {
+	if opts.RefreshInterval == 0 {
+		opts.RefreshInterval = DefaultRefreshInterval
+	}
+
+	connInfo, err := opts.Client.WorkspaceAgentConnectionInfoGeneric(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("get connection info: %w", err)
+	}
+	var header http.Header
+	headerTransport, ok := opts.Client.HTTPClient.Transport.(interface {
+		Header() http.Header
+	})
+	if ok {
+		header = headerTransport.Header()
+	}
+	conn, err := tailnet.NewConn(&tailnet.Options{
+		Addresses:           []netip.Prefix{netip.PrefixFrom(tailnet.IP(), 128)},
+		DERPMap:             connInfo.DERPMap,
+		DERPHeader:          &header,
+		DERPForceWebSockets: connInfo.DERPForceWebSockets,
+		Logger:              opts.Logger.Named("tailnet"),
+		BlockEndpoints:      opts.Client.DisableDirectConnections || opts.BlockEndpoints || connInfo.DisableDirectConnections,
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("create tailnet: %w", err)
+	}
+
+	daemonCtx, cancel := context.WithCancel(context.Background())
+	d := &Daemon{
+		opts:   opts,
+		logger: opts.Logger,
+		conn:   conn,
+		ctx:    daemonCtx,
+		cancel: cancel,
+		closed: make(chan struct{}),
+		agents: map[uuid.UUID]*coordinatedAgent{},
+
+		lastActive: time.Now(),
+	}
+	// Our node is sent to every agent we coordinate with.
+	conn.SetNodeCallback(func(node *tailnet.Node) {
+		d.mu.RLock()
+		defer d.mu.RUnlock()
+		for _, agent := range d.agents {
+			agent.send(node)
+		}
+	})
+
+	err = d.refresh(ctx)
+	if err != nil {
+		close(d.closed)
+		_ = d.Close()
+		return nil, xerrors.Errorf("find agents: %w", err)
+	}
+	go d.refreshLoop()
+	return d, nil
+}
+
+func (d *Daemon) refreshLoop() {
+	defer close(d.closed)
+	ticker := time.NewTicker(d.opts.RefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-d.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		err := d.refresh(d.ctx)
+		if err != nil && !errors.Is(err, context.Canceled) {
+			d.logger.Warn(d.ctx, "find agents", slog.Error(err))
+		}
+	}
+}
+
+// refresh starts coordinating with agents that started and stops coordinating
+// with agents that stopped since the last refresh. The DERP map is updated as
+// well.
+func (d *Daemon) refresh(ctx context.Context) error {
+	connInfo, err := d.opts.Client.WorkspaceAgentConnectionInfoGeneric(ctx)
+	if err != nil {
+		return xerrors.Errorf("get connection info: %w", err)
+	}
+	if !tailnet.CompareDERPMaps(d.conn.DERPMap(), connInfo.DERPMap) {
+		d.conn.SetDERPMap(connInfo.DERPMap)
+	}
+
+	res, err := d.opts.Client.Workspaces(ctx, codersdk.WorkspaceFilter{
+		Owner: codersdk.Me,
+	})
+	if err != nil {
+		return xerrors.Errorf("get workspaces: %w", err)
+	}
+	running := map[uuid.UUID]Agent{}
+	for _, workspace := range res.Workspaces {
+		if workspace.LatestBuild.Status != codersdk.WorkspaceStatusRunning {
+			continue
+		}
+		for _, resource := range workspace.LatestBuild.Resources {
+			for _, agent := range resource.Agents {
+				if d.opts.AgentID != uuid.Nil && agent.ID != d.opts.AgentID {
+					continue
+				}
+				running[agent.ID] = Agent{
+					ID:            agent.ID,
+					Hostname:      Hostname(agent.Name, workspace.Name, workspace.OwnerName),
+					Name:          agent.Name,
+					WorkspaceName: workspace.Name,
+					OwnerName:     workspace.OwnerName,
+					IP:            tailnet.IPFromUUID(agent.ID),
+				}
+			}
+		}
+	}
+
+	d.mu.Lock()
+	var stopped []*coordinatedAgent
+	for id, agent := range d.agents {
+		if _, ok := running[id]; ok {
+			continue
+		}
+		d.logger.Debug(ctx, "agent stopped", slog.F("hostname", agent.Hostname))
+		agent.cancel()
+		stopped = append(stopped, agent)
+		delete(d.agents, id)
+	}
+	for id, agent := range running {
+		if _, ok := d.agents[id]; ok {
+			continue
+		}
+		d.logger.Debug(ctx, "agent started", slog.F("hostname", agent.Hostname))
+		agentCtx, cancel := context.WithCancel(d.ctx)
+		coordinated := &coordinatedAgent{
+			Agent:  agent,
+			cancel: cancel,
+			done:   make(chan struct{}),
+		}
+		d.agents[id] = coordinated
+		go d.coordinate(agentCtx, coordinated)
+	}
+	d.mu.Unlock()
+
+	// Stopped agents are waited for without holding the lock, so resolving
+	// and dialing other agents isn't blocked meanwhile.
+	for _, agent := range stopped {
+		<-agent.done
+		_, err := d.conn.RemovePeer(tailnet.PeerSelector{IP: netip.PrefixFrom(agent.IP, 128)})
+		if err != nil {
+			d.logger.Debug(ctx, "remove agent peer", slog.F("hostname", agent.Hostname), slog.Error(err))
+		}
+	}
+	return nil
+}
+
+// coordinate exchanges nodes with the agent through the coordinator of coderd
+// until ctx is done, reconnecting when the connection fails.
+func (d *Daemon) coordinate(ctx context.Context, agent *coordinatedAgent) {
+	defer close(agent.done)
+	logger := d.logger.With(slog.F("hostname", agent.Hostname))
+
+	client := d.opts.Client
+	coordinateURL, err := client.URL.Parse(fmt.Sprintf("/api/v2/workspaceagents/%s/coordinate", agent.ID))
+	if err != nil {
+		logger.Error(ctx, "parse coordinate url", slog.Error(err))
+		return
+	}
+	headers := make(http.Header)
+	tokenHeader := codersdk.SessionTokenHeader
+	if client.SessionTokenHeader != "" {
+		tokenHeader = client.SessionTokenHeader
+	}
+	headers.Set(tokenHeader, client.SessionToken())
+
+	for retrier := retry.New(50*time.Millisecond, 10*time.Second); retrier.Wait(ctx); {
+		// nolint:bodyclose
+		ws, _, err := websocket.Dial(ctx, coordinateURL.String(), &websocket.DialOptions{
+			HTTPClient: client.HTTPClient,
+			HTTPHeader: headers,
+			// Need to disable compression to avoid a data-race.
+			CompressionMode: websocket.CompressionDisabled,
+		})
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				return
+			}
+			logger.Debug(ctx, "failed to dial coordinator", slog.Error(err))
+			continue
+		}
+		sendNode, errChan := tailnet.ServeCoordinator(websocket.NetConn(ctx, ws, websocket.MessageBinary), func(nodes []*tailnet.Node) error {
+			return d.conn.UpdateNodes(nodes, false)
+		})
+		agent.setSendNode(sendNode)
+		sendNode(d.conn.Node())
+		logger.Debug(ctx, "serving coordinator")
+		err = <-errChan
+		agent.setSendNode(nil)
+		_ = ws.Close(websocket.StatusGoingAway, "")
+		if errors.Is(err, context.Canceled) {
+			return
+		}
+		if err != nil {
+			logger.Debug(ctx, "error serving coordinator", slog.Error(err))
+		}
+	}
+}
+
+// Status returns the agents the daemon is connected to, sorted by hostname.
+func (d *Daemon) Status() Status {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	agents := make([]Agent, 0, len(d.agents))
+	for _, agent := range d.agents {
+		agents = append(agents, agent.Agent)
+	}
+	sort.Slice(agents, func(i, j int) bool {
+		return agents[i].Hostname < agents[j].Hostname
+	})
+	return Status{
+		URL:      d.opts.Client.URL.String(),
+		Agents:   agents,
+		ProxyURL: d.proxyURL,
+	}
+}
+
+// Resolve returns the agent with the given hostname or tailnet IP.
+func (d *Daemon) Resolve(host string) (Agent, bool) {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	ip, err := netip.ParseAddr(host)
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	for _, agent := range d.agents {
+		if agent.Hostname == host || (err == nil && agent.IP == ip) {
+			return agent.Agent, true
+		}
+	}
+	return Agent{}, false
+}
+
+// DialAgent dials a TCP port of an agent the daemon is connected to.
+func (d *Daemon) DialAgent(ctx context.Context, agentID uuid.UUID, port uint16) (net.Conn, error) {
+	d.mu.RLock()
+	agent, ok := d.agents[agentID]
+	d.mu.RUnlock()
+	if !ok {
+		return nil, xerrors.Errorf("not connected to agent %s", agentID)
+	}
+	if !d.conn.AwaitReachable(ctx, agent.IP) {
+		return nil, xerrors.Errorf("agent %s not reachable in time: %w", agent.Hostname, ctx.Err())
+	}
+	conn, err := d.conn.DialContextTCP(ctx, netip.AddrPortFrom(agent.IP, port))
+	if err != nil {
+		return nil, err
+	}
+	d.activeMu.Lock()
+	d.activeConns++
+	d.activeMu.Unlock()
+	return &trackedConn{Conn: conn, daemon: d}, nil
+}
+
+// Idle returns how long ago the last connection to an agent was closed, or
+// zero if connections are open.
+func (d *Daemon) Idle() time.Duration {
+	d.activeMu.Lock()
+	defer d.activeMu.Unlock()
+	if d.activeConns > 0 {
+		return 0
+	}
+	return time.Since(d.lastActive)
+}
+
+// trackedConn is a connection to an agent that counts towards the open
+// connections of the daemon until it's closed.
+type trackedConn struct {
+	net.Conn
+	daemon *Daemon
+	once   sync.Once
+}
+
+func (c *trackedConn) Close() error {
+	c.once.Do(func() {
+		c.daemon.activeMu.Lock()
+		defer c.daemon.activeMu.Unlock()
+		c.daemon.activeConns--
+		c.daemon.lastActive = time.Now()
+	})
+	return c.Conn.Close()
+}
+
+// dialHost dials a port of the agent with the given hostname or tailnet IP.
+func (d *Daemon) dialHost(ctx context.Context, host string, port uint16) (net.Conn, error) {
+	agent, ok := d.Resolve(host)
+	if !ok {
+		return nil, xerrors.Errorf("unknown host %q", host)
+	}
+	return d.DialAgent(ctx, agent.ID, port)
+}
+
+// Close stops coordinating with all agents and closes the tailnet connection.
+func (d *Daemon) Close() error {
+	d.cancel()
+	<-d.closed
+	d.mu.Lock()
+	agents := d.agents
+	d.agents = map[uuid.UUID]*coordinatedAgent{}
+	d.mu.Unlock()
+	for _, agent := range agents {
+		<-agent.done
+	}
+	return d.conn.Close()
+}
@@ -0,0 +1,261 @@
+package vpn
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/dns/dnsmessage"
+	"golang.org/x/net/proxy"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestHostname(t *testing.T) {
+	t.Parallel()
+	require.Equal(t, "main.dev.alice.coder", Hostname("main", "Dev", "Alice"))
+}
+
+func TestProxy(t *testing.T) {
+	t.Parallel()
+
+	// echoDial connects to an echo server for the "agent.coder" host only, and
+	// sends every dialed address on the returned channel.
+	echoDial := func() (DialFunc, chan string) {
+		dialed := make(chan string, 1)
+		return func(ctx context.Context, host string, port uint16) (net.Conn, error) {
+			dialed <- fmt.Sprintf("%s:%d", host, port)
+			if host != "agent.coder" {
+				return nil, xerrors.New("unknown host")
+			}
+			client, server := net.Pipe()
+			go func() {
+				defer server.Close()
+				_, _ = io.Copy(server, server)
+			}()
+			return client, nil
+		}, dialed
+	}
+	startProxy := func(t *testing.T, password string, dial DialFunc) string {
+		ctx, cancel := context.WithCancel(context.Background())
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			cancel()
+			_ = l.Close()
+		})
+		go func() {
+			_ = serveProxy(ctx, slogtest.Make(t, nil), l, password, dial)
+		}()
+		return l.Addr().String()
+	}
+	assertEcho := func(t *testing.T, conn net.Conn) {
+		_, err := conn.Write([]byte("hello"))
+		require.NoError(t, err)
+		buf := make([]byte, 5)
+		_, err = io.ReadFull(conn, buf)
+		require.NoError(t, err)
+		require.Equal(t, "hello", string(buf))
+	}
+
+	t.Run("SOCKS5", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		dial, dialed := echoDial()
+		dialer, err := proxy.SOCKS5("tcp", startProxy(t, "", dial), nil, proxy.Direct)
+		require.NoError(t, err)
+
+		conn, err := dialer.(proxy.ContextDialer).DialContext(ctx, "tcp", "agent.coder:22")
+		require.NoError(t, err)
+		defer conn.Close()
+		assertEcho(t, conn)
+		require.Equal(t, "agent.coder:22", <-dialed)
+	})
+
+	t.Run("SOCKS5Unreachable", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		dial, _ := echoDial()
+		dialer, err := proxy.SOCKS5("tcp", startProxy(t, "", dial), nil, proxy.Direct)
+		require.NoError(t, err)
+
+		_, err = dialer.(proxy.ContextDialer).DialContext(ctx, "tcp", "unknown.coder:22")
+		require.Error(t, err)
+	})
+
+	t.Run("HTTPConnect", func(t *testing.T) {
+		t.Parallel()
+		dial, dialed := echoDial()
+		conn, err := net.Dial("tcp", startProxy(t, "", dial))
+		require.NoError(t, err)
+		defer conn.Close()
+
+		_, err = fmt.Fprint(conn, "CONNECT agent.coder:8080 HTTP/1.1\r\nHost: agent.coder:8080\r\n\r\n")
+		require.NoError(t, err)
+		br := bufio.NewReader(conn)
+		res, err := http.ReadResponse(br, &http.Request{Method: http.MethodConnect})
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		assertEcho(t, &bufferedConn{Conn: conn, r: br})
+		require.Equal(t, "agent.coder:8080", <-dialed)
+	})
+
+	t.Run("SOCKS5Password", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		dial, dialed := echoDial()
+		address := startProxy(t, "secret", dial)
+
+		for _, auth := range []*proxy.Auth{nil, {User: ProxyUsername, Password: "wrong"}} {
+			dialer, err := proxy.SOCKS5("tcp", address, auth, proxy.Direct)
+			require.NoError(t, err)
+			_, err = dialer.(proxy.ContextDialer).DialContext(ctx, "tcp", "agent.coder:22")
+			require.Error(t, err)
+		}
+		require.Empty(t, dialed)
+
+		dialer, err := proxy.SOCKS5("tcp", address, &proxy.Auth{User: ProxyUsername, Password: "secret"}, proxy.Direct)
+		require.NoError(t, err)
+		conn, err := dialer.(proxy.ContextDialer).DialContext(ctx, "tcp", "agent.coder:22")
+		require.NoError(t, err)
+		defer conn.Close()
+		assertEcho(t, conn)
+		require.Equal(t, "agent.coder:22", <-dialed)
+	})
+
+	t.Run("HTTPConnectPassword", func(t *testing.T) {
+		t.Parallel()
+		dial, dialed := echoDial()
+		address := startProxy(t, "secret", dial)
+
+		conn, err := net.Dial("tcp", address)
+		require.NoError(t, err)
+		defer conn.Close()
+		_, err = fmt.Fprint(conn, "CONNECT agent.coder:8080 HTTP/1.1\r\nHost: agent.coder:8080\r\n\r\n")
+		require.NoError(t, err)
+		res, err := http.ReadResponse(bufio.NewReader(conn), &http.Request{Method: http.MethodConnect})
+		require.NoError(t, err)
+		require.Equal(t, http.StatusProxyAuthRequired, res.StatusCode)
+		require.Empty(t, dialed)
+
+		conn, err = net.Dial("tcp", address)
+		require.NoError(t, err)
+		defer conn.Close()
+		authorization := base64.StdEncoding.EncodeToString([]byte(ProxyUsername + ":secret"))
+		_, err = fmt.Fprintf(conn, "CONNECT agent.coder:8080 HTTP/1.1\r\nHost: agent.coder:8080\r\nProxy-Authorization: Basic %s\r\n\r\n", authorization)
+		require.NoError(t, err)
+		br := bufio.NewReader(conn)
+		res, err = http.ReadResponse(br, &http.Request{Method: http.MethodConnect})
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		assertEcho(t, &bufferedConn{Conn: conn, r: br})
+		require.Equal(t, "agent.coder:8080", <-dialed)
+	})
+
+	t.Run("HTTPMethodNotAllowed", func(t *testing.T) {
+		t.Parallel()
+		dial, dialed := echoDial()
+		conn, err := net.Dial("tcp", startProxy(t, "", dial))
+		require.NoError(t, err)
+		defer conn.Close()
+
+		_, err = fmt.Fprint(conn, "GET http://agent.coder/ HTTP/1.1\r\nHost: agent.coder\r\n\r\n")
+		require.NoError(t, err)
+		res, err := http.ReadResponse(bufio.NewReader(conn), nil)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusMethodNotAllowed, res.StatusCode)
+		require.Empty(t, dialed)
+	})
+}
+
+func TestDNSResponse(t *testing.T) {
+	t.Parallel()
+
+	ip := netip.MustParseAddr("fd7a:115c:a1e0::1")
+	resolve := func(name string) (netip.Addr, bool) {
+		if name == "main.dev.alice.coder" {
+			return ip, true
+		}
+		return netip.Addr{}, false
+	}
+	query := func(t *testing.T, name string, typ dnsmessage.Type) dnsmessage.Message {
+		msg := dnsmessage.Message{
+			Header: dnsmessage.Header{ID: 1234, RecursionDesired: true},
+			Questions: []dnsmessage.Question{{
+				Name:  dnsmessage.MustNewName(name),
+				Type:  typ,
+				Class: dnsmessage.ClassINET,
+			}},
+		}
+		raw, err := msg.Pack()
+		require.NoError(t, err)
+		raw, err = dnsResponse(raw, resolve)
+		require.NoError(t, err)
+		var resp dnsmessage.Message
+		err = resp.Unpack(raw)
+		require.NoError(t, err)
+		assert.Equal(t, uint16(1234), resp.Header.ID)
+		assert.True(t, resp.Header.Response)
+		return resp
+	}
+
+	t.Run("AAAA", func(t *testing.T) {
+		t.Parallel()
+		resp := query(t, "Main.Dev.Alice.coder.", dnsmessage.TypeAAAA)
+		require.Equal(t, dnsmessage.RCodeSuccess, resp.Header.RCode)
+		require.Len(t, resp.Answers, 1)
+		body, ok := resp.Answers[0].Body.(*dnsmessage.AAAAResource)
+		require.True(t, ok)
+		require.Equal(t, ip.As16(), body.AAAA)
+	})
+
+	t.Run("A", func(t *testing.T) {
+		t.Parallel()
+		resp := query(t, "main.dev.alice.coder.", dnsmessage.TypeA)
+		require.Equal(t, dnsmessage.RCodeSuccess, resp.Header.RCode)
+		require.Empty(t, resp.Answers)
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		t.Parallel()
+		resp := query(t, "other.dev.alice.coder.", dnsmessage.TypeAAAA)
+		require.Equal(t, dnsmessage.RCodeNameError, resp.Header.RCode)
+	})
+
+	t.Run("OutsideSuffix", func(t *testing.T) {
+		t.Parallel()
+		resp := query(t, "example.com.", dnsmessage.TypeAAAA)
+		require.Equal(t, dnsmessage.RCodeRefused, resp.Header.RCode)
+	})
+}
+
+func TestIdle(t *testing.T) {
+	t.Parallel()
+
+	d := &Daemon{lastActive: time.Now().Add(-time.Hour)}
+	require.GreaterOrEqual(t, d.Idle(), time.Hour)
+
+	client, server := net.Pipe()
+	defer server.Close()
+	d.activeConns++
+	conn := &trackedConn{Conn: client, daemon: d}
+	require.Zero(t, d.Idle())
+
+	// Closing twice only counts once.
+	_ = conn.Close()
+	_ = conn.Close()
+	require.Less(t, d.Idle(), time.Hour)
+	require.Zero(t, d.activeConns)
+}